@@ -21,12 +21,40 @@ func TestOpenAPIContract_ParsesAndHasRequiredPaths(t *testing.T) {
 		"/api/openapi.yaml",
 		"/power/v1/transitions",
 		"/power/v1/transitions/{id}",
+		"/power/v1/transitions/{id}/approve",
+		"/power/v1/transitions/{id}/reject",
+		"/power/v1/transitions/{id}/retry",
+		"/power/v1/transitions/{id}/tasks/{nodeID}",
+		"/power/v1/templates",
+		"/power/v1/templates/{name}",
+		"/power/v1/templates/{name}/versions",
+		"/power/v1/templates/{name}/run",
 		"/power/v1/power-status",
 		"/power/v1/actions/on",
 		"/power/v1/actions/off",
 		"/power/v1/actions/reboot",
 		"/power/v1/actions/reset",
+		"/power/v1/actions/boot-override",
+		"/power/v1/powercap",
+		"/power/v1/telemetry",
+		"/power/v1/boot-override",
+		"/power/v1/virtual-media/insert",
+		"/power/v1/virtual-media/eject",
+		"/power/v1/admin/mappings/diagnostics",
 		"/power/v1/admin/mappings/sync",
+		"/power/v1/admin/system-paths",
+		"/power/v1/admin/system-paths/{nodeID}",
+		"/power/v1/admin/quotas",
+		"/power/v1/admin/webhooks",
+		"/power/v1/admin/webhooks/{id}",
+		"/power/v1/admin/webhooks/{id}/deliveries",
+		"/power/v1/admin/webhooks/{id}/deliveries/{deliveryID}/redeliver",
+		"/v1/transitions",
+		"/v1/transitions/{transitionID}",
+		"/v1/power-status",
+		"/v1/power-cap",
+		"/v1/power-cap/snapshot",
+		"/v1/power-cap/{taskID}",
 	} {
 		assert.Containsf(t, paths, path, "missing path %s", path)
 	}
@@ -44,7 +72,19 @@ func TestOpenAPIContract_OperationEnumsMatchADR(t *testing.T) {
 
 	assert.ElementsMatch(
 		t,
-		[]string{"pending", "running", "completed", "failed", "partial", "canceled", "planned"},
+		[]string{
+			"On", "ForceOff", "GracefulShutdown", "GracefulRestart", "ForceRestart", "Nmi", "PowerCap",
+			"SetBootOverride", "InsertMedia", "EjectMedia", "BootOverrideReset",
+		},
+		stringSliceAt(t, mapAt(t, schemas, "TransitionOperation"), "enum"),
+	)
+
+	assert.ElementsMatch(
+		t,
+		[]string{
+			"pending", "running", "completed", "failed", "partial", "canceled", "planned",
+			"pending-approval", "rejected", "expired",
+		},
 		stringSliceAt(t, mapAt(t, schemas, "TransitionState"), "enum"),
 	)
 
@@ -107,16 +147,50 @@ func TestOpenAPIContract_ScopeAnnotationsPresent(t *testing.T) {
 	}
 
 	expected := map[endpointMethod][]string{
-		{Path: "/power/v1/transitions", Method: "get"}:          {"read:power", "admin"},
-		{Path: "/power/v1/transitions", Method: "post"}:         {"write:power", "admin"},
-		{Path: "/power/v1/transitions/{id}", Method: "get"}:     {"read:power", "admin"},
-		{Path: "/power/v1/transitions/{id}", Method: "delete"}:  {"write:power", "admin"},
-		{Path: "/power/v1/power-status", Method: "get"}:         {"read:power", "admin"},
-		{Path: "/power/v1/actions/on", Method: "post"}:          {"write:power", "admin"},
-		{Path: "/power/v1/actions/off", Method: "post"}:         {"write:power", "admin"},
-		{Path: "/power/v1/actions/reboot", Method: "post"}:      {"write:power", "admin"},
-		{Path: "/power/v1/actions/reset", Method: "post"}:       {"write:power", "admin"},
-		{Path: "/power/v1/admin/mappings/sync", Method: "post"}: {"admin:power", "admin"},
+		{Path: "/power/v1/transitions", Method: "get"}:                                            {"read:power", "admin"},
+		{Path: "/power/v1/transitions", Method: "post"}:                                           {"write:power", "admin"},
+		{Path: "/power/v1/transitions/{id}", Method: "get"}:                                       {"read:power", "admin"},
+		{Path: "/power/v1/transitions/{id}", Method: "delete"}:                                    {"write:power", "admin"},
+		{Path: "/power/v1/transitions/{id}/approve", Method: "post"}:                              {"admin:power", "admin"},
+		{Path: "/power/v1/transitions/{id}/reject", Method: "post"}:                               {"admin:power", "admin"},
+		{Path: "/power/v1/transitions/{id}/retry", Method: "post"}:                                {"write:power", "admin"},
+		{Path: "/power/v1/transitions/{id}/tasks/{nodeID}", Method: "delete"}:                     {"write:power", "admin"},
+		{Path: "/power/v1/power-status", Method: "get"}:                                           {"read:power", "admin"},
+		{Path: "/power/v1/actions/on", Method: "post"}:                                            {"write:power", "admin"},
+		{Path: "/power/v1/actions/off", Method: "post"}:                                           {"write:power", "admin"},
+		{Path: "/power/v1/actions/reboot", Method: "post"}:                                        {"write:power", "admin"},
+		{Path: "/power/v1/actions/reset", Method: "post"}:                                         {"write:power", "admin"},
+		{Path: "/power/v1/powercap", Method: "get"}:                                               {"read:power", "admin"},
+		{Path: "/power/v1/powercap", Method: "post"}:                                              {"write:power", "admin"},
+		{Path: "/power/v1/telemetry", Method: "get"}:                                              {"read:power", "admin"},
+		{Path: "/power/v1/templates", Method: "get"}:                                              {"read:power", "admin"},
+		{Path: "/power/v1/templates", Method: "post"}:                                             {"write:power", "admin"},
+		{Path: "/power/v1/templates/{name}", Method: "get"}:                                       {"read:power", "admin"},
+		{Path: "/power/v1/templates/{name}", Method: "put"}:                                       {"write:power", "admin"},
+		{Path: "/power/v1/templates/{name}", Method: "delete"}:                                    {"write:power", "admin"},
+		{Path: "/power/v1/templates/{name}/versions", Method: "get"}:                              {"read:power", "admin"},
+		{Path: "/power/v1/templates/{name}/run", Method: "post"}:                                  {"write:power", "admin"},
+		{Path: "/power/v1/actions/boot-override", Method: "post"}:                                 {"write:power", "admin"},
+		{Path: "/power/v1/boot-override", Method: "post"}:                                         {"write:power", "admin"},
+		{Path: "/power/v1/virtual-media/insert", Method: "post"}:                                  {"write:power", "admin"},
+		{Path: "/power/v1/virtual-media/eject", Method: "post"}:                                   {"write:power", "admin"},
+		{Path: "/power/v1/admin/mappings/sync", Method: "post"}:                                   {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks", Method: "get"}:                                         {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks", Method: "post"}:                                        {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks/{id}", Method: "put"}:                                    {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks/{id}", Method: "delete"}:                                 {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks/{id}/deliveries", Method: "get"}:                         {"admin:power", "admin"},
+		{Path: "/power/v1/admin/webhooks/{id}/deliveries/{deliveryID}/redeliver", Method: "post"}: {"admin:power", "admin"},
+		{Path: "/v1/transitions", Method: "get"}:                                                  {"read:power", "admin"},
+		{Path: "/v1/transitions", Method: "post"}:                                                 {"write:power", "admin"},
+		{Path: "/v1/transitions/{transitionID}", Method: "get"}:                                   {"read:power", "admin"},
+		{Path: "/v1/transitions/{transitionID}", Method: "delete"}:                                {"write:power", "admin"},
+		{Path: "/v1/power-status", Method: "get"}:                                                 {"read:power", "admin"},
+		{Path: "/v1/power-status", Method: "post"}:                                                {"read:power", "admin"},
+		{Path: "/v1/power-cap", Method: "get"}:                                                    {"read:power", "admin"},
+		{Path: "/v1/power-cap", Method: "patch"}:                                                  {"write:power", "admin"},
+		{Path: "/v1/power-cap/snapshot", Method: "post"}:                                          {"read:power", "admin"},
+		{Path: "/v1/power-cap/{taskID}", Method: "get"}:                                           {"read:power", "admin"},
 	}
 
 	for key, scopes := range expected {
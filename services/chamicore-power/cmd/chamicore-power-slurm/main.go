@@ -0,0 +1,145 @@
+// Package main is the entry point for chamicore-power-slurm, the Slurm
+// SuspendProgram/ResumeProgram for chamicore-power.
+//
+// Usage:
+//
+//	chamicore-power-slurm suspend|resume <hostlist>
+//
+// slurmctld passes only the hostlist, so the mode may instead come from the
+// program name: install symlinks ending in "-suspend" and "-resume" and point
+// SuspendProgram and ResumeProgram at them.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/slurm"
+	powerclient "git.cscs.ch/openchami/chamicore-power/pkg/client"
+	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
+)
+
+// Exit codes reported to slurmctld, which logs any non-zero status.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+var (
+	version   = "dev"
+	commit    = "none"
+	buildDate = "unknown"
+)
+
+func main() {
+	os.Exit(run(os.Args))
+}
+
+func run(args []string) int {
+	mode, hostlistExpr, err := parseArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\nusage: %s suspend|resume <hostlist>\n", err, filepath.Base(args[0]))
+		return exitUsage
+	}
+
+	cfg := config.LoadSlurm()
+	logger, closeLog, err := newLogger(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open log file: %v\n", err)
+		return exitFailure
+	}
+	defer closeLog()
+
+	power, err := powerclient.New(powerclient.Config{BaseURL: cfg.PowerURL, Token: cfg.Token})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create power client")
+		return exitFailure
+	}
+	smd := smdclient.New(smdclient.Config{BaseURL: cfg.SMDURL, Token: cfg.Token})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	program := slurm.New(power, slurm.NewResolver(smd), slurm.Config{
+		Timeout:       cfg.Timeout,
+		PollInterval:  cfg.PollInterval,
+		EscalateAfter: cfg.EscalateAfter,
+	}, logger)
+	if err := program.Run(ctx, mode, hostlistExpr); err != nil {
+		logger.Error().Err(err).Str("mode", string(mode)).Str("hostlist", hostlistExpr).Msg("slurm power request failed")
+		return exitFailure
+	}
+	return exitOK
+}
+
+// parseArgs reads the mode from the program name or the first argument,
+// followed by the hostlist.
+func parseArgs(args []string) (slurm.Mode, string, error) {
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("missing arguments")
+	}
+
+	name := strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+	rest := args[1:]
+	var mode slurm.Mode
+	switch {
+	case strings.HasSuffix(name, "-"+string(slurm.ModeSuspend)):
+		mode = slurm.ModeSuspend
+	case strings.HasSuffix(name, "-"+string(slurm.ModeResume)):
+		mode = slurm.ModeResume
+	default:
+		if len(rest) == 0 {
+			return "", "", fmt.Errorf("missing mode")
+		}
+		parsed, err := slurm.ParseMode(rest[0])
+		if err != nil {
+			return "", "", err
+		}
+		mode = parsed
+		rest = rest[1:]
+	}
+
+	if len(rest) != 1 || strings.TrimSpace(rest[0]) == "" {
+		return "", "", fmt.Errorf("expected exactly one hostlist argument")
+	}
+	return mode, rest[0], nil
+}
+
+func newLogger(cfg config.SlurmConfig) (zerolog.Logger, func(), error) {
+	level, err := zerolog.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		level = zerolog.InfoLevel
+	}
+
+	var out io.Writer = os.Stderr
+	closeLog := func() {}
+	if cfg.LogFile != "" {
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+		if err != nil {
+			return zerolog.Logger{}, nil, err
+		}
+		out = zerolog.MultiLevelWriter(os.Stderr, file)
+		closeLog = func() { _ = file.Close() }
+	}
+
+	zerolog.TimeFieldFormat = time.RFC3339
+	logger := zerolog.New(out).Level(level).With().
+		Timestamp().
+		Str("service", "power-slurm").
+		Str("version", version).
+		Int("pid", os.Getpid()).
+		Logger()
+	logger.Debug().Str("commit", commit).Str("build_date", buildDate).Msg("starting chamicore-power-slurm")
+	return logger, closeLog, nil
+}
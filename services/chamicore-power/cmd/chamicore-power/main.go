@@ -24,10 +24,14 @@ import (
 	"git.cscs.ch/openchami/chamicore-power/api"
 	"git.cscs.ch/openchami/chamicore-power/internal/config"
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/quota"
 	"git.cscs.ch/openchami/chamicore-power/internal/server"
 	powersmd "git.cscs.ch/openchami/chamicore-power/internal/smd"
 	"git.cscs.ch/openchami/chamicore-power/internal/store"
 	powersync "git.cscs.ch/openchami/chamicore-power/internal/sync"
+	"git.cscs.ch/openchami/chamicore-power/internal/telemetry"
+	"git.cscs.ch/openchami/chamicore-power/internal/webhook"
 	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
 )
 
@@ -138,21 +142,70 @@ func main() {
 		}
 	}
 
+	var syncOpts []powersync.Option
+	if cfg.SyncLeaderElection {
+		syncOpts = append(syncOpts, powersync.WithLeaderCoordinator(st.NewMappingSyncCoordinator(cfg.ReplicaID)))
+	}
 	mappingSync := powersync.New(st, smd, powersync.Config{
 		Interval:            cfg.MappingSyncInterval,
+		LeaderPollInterval:  cfg.SyncLeaderPoll,
+		PageSize:            cfg.SyncPageSize,
 		SyncOnStartup:       cfg.MappingSyncOnStartup,
 		DefaultCredentialID: cfg.DefaultCredentialID,
-	}, logger.With().Str("component", "mapping-sync").Logger())
+	}, logger.With().Str("component", "mapping-sync").Str("replica_id", cfg.ReplicaID).Logger(), syncOpts...)
 	go mappingSync.Run(ctx)
 
-	stateUpdater := powersmd.NewUpdater(smd)
-	systemResolver := engine.NewSystemPathResolver()
+	if cfg.SyncEventsEnabled && strings.TrimSpace(cfg.NATSURL) != "" {
+		subscriber, subscriberErr := nats.NewSubscriber(nats.Config{
+			URL:  cfg.NATSURL,
+			Name: "chamicore-power-mapping-sync",
+			Stream: nats.StreamConfig{
+				Name:     cfg.SMDNATSStream,
+				Subjects: []string{"chamicore.smd.>"},
+			},
+		})
+		if subscriberErr != nil {
+			logger.Warn().Err(subscriberErr).Str("nats_url", cfg.NATSURL).Msg("event-driven mapping sync disabled: falling back to polling")
+		} else {
+			defer func() {
+				if closeErr := subscriber.Close(); closeErr != nil {
+					logger.Error().Err(closeErr).Msg("failed to close NATS subscriber")
+				}
+			}()
+			if subscribeErr := mappingSync.SubscribeEvents(ctx, subscriber); subscribeErr != nil {
+				logger.Warn().Err(subscribeErr).Msg("event-driven mapping sync disabled: falling back to polling")
+			} else {
+				logger.Info().Str("stream", cfg.SMDNATSStream).Msg("event-driven mapping sync started")
+			}
+		}
+	}
+
+	smdStateMap, err := powersmd.ParseStateMap(cfg.SMDStateMap)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid CHAMICORE_POWER_SMD_STATE_MAP")
+	}
+	stateUpdater := powersmd.NewUpdater(
+		smd,
+		powersmd.WithStateMap(smdStateMap),
+		powersmd.WithPatchBatching(cfg.SMDBatchSize, cfg.SMDBatchWindow, cfg.SMDUpdateConcurrency),
+	)
+	systemResolver := engine.NewSystemPathResolver(
+		engine.WithSystemPathStore(st),
+		engine.WithSystemPathCacheTTL(cfg.SystemPathCacheTTL),
+		engine.WithSystemPathLogger(logger.With().Str("component", "system-path").Logger()),
+	)
 	// Local Sushy/libvirt development uses unauthenticated Redfish.
 	// Auth-backed credential resolution is wired as a follow-up phase.
 	credResolver := engine.EmptyCredentialResolver{}
 	redfishConfig := sharedredfish.Config{MaxAttempts: 1}
 	actionExecutor := engine.NewRedfishExecutor(redfishConfig, credResolver, systemResolver)
 	powerStateReader := engine.NewRedfishStateReader(redfishConfig, credResolver, systemResolver)
+	powerCapper := engine.NewRedfishPowerCapper(redfishConfig, credResolver, systemResolver)
+	bootController := engine.NewRedfishBootController(redfishConfig, credResolver, systemResolver)
+	queuePriorities, err := engine.ParsePriorities(cfg.QueuePriorities)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid CHAMICORE_POWER_QUEUE_PRIORITIES")
+	}
 	runner := engine.New(st, actionExecutor, powerStateReader, engine.Config{
 		GlobalConcurrency:  cfg.GlobalConcurrency,
 		PerBMCConcurrency:  cfg.PerBMCConcurrency,
@@ -162,9 +215,34 @@ func main() {
 		TransitionDeadline: cfg.TransitionDeadline,
 		VerificationWindow: cfg.VerificationWindow,
 		VerificationPoll:   cfg.VerificationPoll,
-	}, engine.WithNodeStateUpdater(stateUpdater))
+		BootWaitTimeout:    cfg.BootWaitTimeout,
+		BootWaitPoll:       cfg.BootWaitPoll,
+		QueueSize:          cfg.QueueSize,
+		Priorities:         queuePriorities,
+	},
+		engine.WithNodeStateUpdater(stateUpdater),
+		engine.WithNodeReadiness(stateUpdater),
+		engine.WithPowerCapper(powerCapper),
+		engine.WithBootController(bootController),
+		engine.WithLogger(logger.With().Str("component", "engine").Logger()),
+	)
 	runner.Start(ctx)
 
+	if cfg.TelemetryEnabled {
+		samplerOpts := []telemetry.Option{telemetry.WithBMCLimiter(runner.AcquireBMC)}
+		if cfg.SyncLeaderElection {
+			samplerOpts = append(samplerOpts, telemetry.WithLeaderCheck(mappingSync.IsLeader))
+		}
+		sampler := telemetry.New(st, engine.NewRedfishTelemetryReader(redfishConfig, credResolver, systemResolver), telemetry.Config{
+			Interval:    cfg.TelemetryInterval,
+			Resolution:  cfg.TelemetryResolution,
+			Retention:   cfg.TelemetryRetention,
+			Concurrency: cfg.TelemetryConcurrency,
+		}, logger.With().Str("component", "telemetry").Logger(), samplerOpts...)
+		go sampler.Run(ctx)
+		logger.Info().Dur("interval", cfg.TelemetryInterval).Dur("resolution", cfg.TelemetryResolution).Msg("telemetry sampling started")
+	}
+
 	resolveGroupMembers := func(ctx context.Context, group string) ([]string, error) {
 		groupName := strings.TrimSpace(group)
 		if groupName == "" {
@@ -183,17 +261,57 @@ func main() {
 		return append([]string(nil), resource.Spec.Members...), nil
 	}
 
-	srv := server.New(
-		st,
-		cfg,
-		version,
-		commit,
-		buildDate,
+	if cfg.WebhooksEnabled {
+		dispatcher := webhook.New(st, webhook.Config{
+			PollInterval: cfg.WebhookPollInterval,
+			Timeout:      cfg.WebhookTimeout,
+			MaxAttempts:  cfg.WebhookMaxAttempts,
+			BackoffBase:  cfg.WebhookBackoffBase,
+			BackoffMax:   cfg.WebhookBackoffMax,
+			Retention:    cfg.WebhookRetention,
+		}, logger.With().Str("component", "webhook").Logger(),
+			// A deleted group no longer matches any event.
+			webhook.WithGroupMemberResolver(func(ctx context.Context, group string) ([]string, error) {
+				members, err := resolveGroupMembers(ctx, group)
+				if errors.Is(err, server.ErrGroupNotFound) {
+					return nil, nil
+				}
+				return members, err
+			}),
+		)
+		go dispatcher.Run(ctx)
+		logger.Info().Dur("poll_interval", cfg.WebhookPollInterval).Msg("webhook delivery started")
+	}
+
+	quotaPolicy, err := quota.ParsePolicy(quota.Limits{
+		RatePerMinute: cfg.RateLimitPerMinute,
+		Burst:         cfg.RateLimitBurst,
+		MaxActive:     cfg.MaxActiveTransitions,
+	}, cfg.QuotaOverrides, cfg.QuotaGroups)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid caller quota configuration")
+	}
+	serverOpts := []server.Option{
 		server.WithOpenAPISpec(api.OpenAPISpec),
 		server.WithMappingSyncer(mappingSync),
 		server.WithTransitionRunner(runner),
+		server.WithPowerCapReader(runner),
 		server.WithGroupMemberResolver(resolveGroupMembers),
-	)
+		server.WithNodeSelector(func(ctx context.Context, selector model.NodeSelector) ([]string, error) {
+			return powersmd.SelectNodes(ctx, smd, selector)
+		}),
+		server.WithSystemPathCache(systemResolver),
+	}
+	if !quotaPolicy.IsZero() {
+		serverOpts = append(serverOpts, server.WithQuotas(quota.NewLimiter(quotaPolicy)))
+		logger.Info().
+			Int("rate_per_minute", cfg.RateLimitPerMinute).
+			Int("max_active", cfg.MaxActiveTransitions).
+			Int("overrides", len(quotaPolicy.Overrides)).
+			Msg("caller quotas enabled")
+	}
+
+	srv := server.New(st, cfg, version, commit, buildDate, serverOpts...)
 
 	httpServer := &http.Server{
 		Addr:              cfg.ListenAddr,
@@ -228,5 +346,6 @@ func main() {
 	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
 		logger.Error().Err(shutdownErr).Msg("HTTP server shutdown error")
 	}
+	runner.Wait()
 	logger.Info().Msg("server stopped gracefully")
 }
@@ -15,9 +15,13 @@ const (
 	defaultSMDURL            = "http://localhost:27779"
 	defaultNATSURL           = "nats://localhost:4222"
 	defaultNATSStream        = "CHAMICORE_POWER"
+	defaultSMDNATSStream     = "CHAMICORE_SMD"
 	defaultPrometheusAddr    = ":9090"
 	defaultSyncInterval      = 5 * time.Minute
+	defaultSyncLeaderPoll    = 5 * time.Second
+	defaultSyncPageSize      = 1000
 	defaultBulkMaxNodes      = 20
+	defaultBulkHardMaxNodes  = 10000
 	defaultRetryAttempts     = 3
 	defaultRetryBackoffBase  = 250 * time.Millisecond
 	defaultRetryBackoffMax   = 5 * time.Second
@@ -26,6 +30,23 @@ const (
 	defaultVerifyPoll        = 2 * time.Second
 	defaultGlobalWorkers     = 20
 	defaultPerBMCWorkers     = 1
+	defaultSystemPathTTL     = 10 * time.Minute
+	defaultBootWaitTimeout   = 15 * time.Minute
+	defaultBootWaitPoll      = 10 * time.Second
+	defaultSMDBatchSize      = 50
+	defaultSMDBatchWindow    = 50 * time.Millisecond
+	defaultSMDConcurrency    = 8
+	defaultTelemetryInterval = 30 * time.Second
+	defaultTelemetryRes      = time.Minute
+	defaultTelemetryKeep     = 7 * 24 * time.Hour
+	defaultTelemetryWorkers  = 8
+	defaultApprovalTTL       = 4 * time.Hour
+	defaultWebhookPoll       = 2 * time.Second
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookAttempts   = 10
+	defaultWebhookBackoff    = 10 * time.Second
+	defaultWebhookBackoffMax = time.Hour
+	defaultWebhookKeep       = 7 * 24 * time.Hour
 )
 
 // Config holds service configuration values.
@@ -35,6 +56,7 @@ type Config struct {
 	SMDURL         string
 	NATSURL        string
 	NATSStream     string
+	SMDNATSStream  string
 	LogLevel       string
 	JWKSURL        string
 	InternalToken  string
@@ -47,8 +69,16 @@ type Config struct {
 	MappingSyncInterval  time.Duration
 	MappingSyncOnStartup bool
 	DefaultCredentialID  string
+	SyncLeaderElection   bool
+	SyncLeaderPoll       time.Duration
+	ReplicaID            string
+	SyncEventsEnabled    bool
+	SyncPageSize         int
 
+	// Requests for more than BulkMaxNodes nodes, up to BulkHardMaxNodes, run
+	// as one transition split into chunks of BulkMaxNodes tasks.
 	BulkMaxNodes       int
+	BulkHardMaxNodes   int
 	RetryAttempts      int
 	RetryBackoffBase   time.Duration
 	RetryBackoffMax    time.Duration
@@ -57,6 +87,67 @@ type Config struct {
 	VerificationPoll   time.Duration
 	GlobalConcurrency  int
 	PerBMCConcurrency  int
+	SystemPathCacheTTL time.Duration
+	BootWaitTimeout    time.Duration
+	BootWaitPoll       time.Duration
+
+	// New transitions are rejected with 503 once QueueSize tasks wait for a
+	// worker; zero uses four times GlobalConcurrency. QueuePriorities
+	// (operation=high|normal|low) decides which queued tasks run first and is
+	// parsed by the engine package.
+	QueueSize       int
+	QueuePriorities string
+
+	// SMDStateMap overrides the power-outcome to SMD state/flag mapping,
+	// e.g. "Failed=Standby:Alert,PoweringOn=-".
+	SMDStateMap          string
+	SMDBatchSize         int
+	SMDBatchWindow       time.Duration
+	SMDUpdateConcurrency int
+
+	// Telemetry sampling reads node power draw and inlet temperature from
+	// every mapped BMC and stores it downsampled to TelemetryResolution.
+	TelemetryEnabled     bool
+	TelemetryInterval    time.Duration
+	TelemetryResolution  time.Duration
+	TelemetryRetention   time.Duration
+	TelemetryConcurrency int
+
+	// Transitions targeting more than ApprovalMaxNodes nodes (zero disables
+	// the limit), or ForceOff transitions touching ApprovalProtectedGroups,
+	// wait for a second principal to approve them within ApprovalTTL.
+	ApprovalMaxNodes        int
+	ApprovalProtectedGroups []string
+	ApprovalTTL             time.Duration
+
+	// PCSCompatEnabled serves the CSM PCS API under /v1 for tooling written
+	// against PCS.
+	PCSCompatEnabled bool
+
+	// Webhook delivery fans out outbox events to the subscriptions managed
+	// under /power/v1/admin/webhooks. Failed deliveries are retried with
+	// exponential backoff and dead-lettered after WebhookMaxAttempts.
+	WebhooksEnabled     bool
+	WebhookPollInterval time.Duration
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookBackoffBase  time.Duration
+	WebhookBackoffMax   time.Duration
+	WebhookRetention    time.Duration
+
+	// Mutating requests are limited per caller to RateLimitPerMinute with
+	// bursts of RateLimitBurst, and each caller may have at most
+	// MaxActiveTransitions unfinished transitions; zero disables a
+	// limit. QuotaOverrides (kind:name=rate/burst/active) replace these per
+	// scope, group or subject, with groups listed in QuotaGroups
+	// (name=subject|subject). Both are parsed by the quota package.
+	// Rate limit buckets live in memory on each replica, so N replicas
+	// behind a load balancer allow up to N times the configured rate.
+	RateLimitPerMinute   int
+	RateLimitBurst       int
+	MaxActiveTransitions int
+	QuotaOverrides       string
+	QuotaGroups          string
 }
 
 // Load reads configuration from environment variables.
@@ -67,6 +158,7 @@ func Load() (Config, error) {
 		SMDURL:               envOrDefault("CHAMICORE_POWER_SMD_URL", defaultSMDURL),
 		NATSURL:              envOrDefault("CHAMICORE_NATS_URL", defaultNATSURL),
 		NATSStream:           strings.TrimSpace(envOrDefault("CHAMICORE_POWER_NATS_STREAM", defaultNATSStream)),
+		SMDNATSStream:        strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SMD_NATS_STREAM", defaultSMDNATSStream)),
 		LogLevel:             strings.ToLower(envOrDefault("CHAMICORE_POWER_LOG_LEVEL", "info")),
 		DevMode:              envBool("CHAMICORE_POWER_DEV_MODE", false),
 		JWKSURL:              envOrDefault("CHAMICORE_POWER_JWKS_URL", ""),
@@ -77,7 +169,13 @@ func Load() (Config, error) {
 		MappingSyncInterval:  envPositiveDuration("CHAMICORE_POWER_MAPPING_SYNC_INTERVAL", defaultSyncInterval),
 		MappingSyncOnStartup: envBool("CHAMICORE_POWER_MAPPING_SYNC_ON_STARTUP", true),
 		DefaultCredentialID:  strings.TrimSpace(envOrDefault("CHAMICORE_POWER_DEFAULT_CREDENTIAL_ID", "")),
+		SyncLeaderElection:   envBool("CHAMICORE_POWER_MAPPING_SYNC_LEADER_ELECTION", true),
+		SyncLeaderPoll:       envPositiveDuration("CHAMICORE_POWER_MAPPING_SYNC_LEADER_POLL_INTERVAL", defaultSyncLeaderPoll),
+		ReplicaID:            strings.TrimSpace(envOrDefault("CHAMICORE_POWER_REPLICA_ID", "")),
+		SyncEventsEnabled:    envBool("CHAMICORE_POWER_MAPPING_SYNC_EVENTS", true),
+		SyncPageSize:         envPositiveInt("CHAMICORE_POWER_MAPPING_SYNC_PAGE_SIZE", defaultSyncPageSize),
 		BulkMaxNodes:         envPositiveInt("CHAMICORE_POWER_BULK_MAX_NODES", defaultBulkMaxNodes),
+		BulkHardMaxNodes:     envPositiveInt("CHAMICORE_POWER_BULK_HARD_MAX_NODES", defaultBulkHardMaxNodes),
 		RetryAttempts:        envPositiveInt("CHAMICORE_POWER_RETRY_ATTEMPTS", defaultRetryAttempts),
 		RetryBackoffBase:     envPositiveDuration("CHAMICORE_POWER_RETRY_BACKOFF_BASE", defaultRetryBackoffBase),
 		RetryBackoffMax:      envPositiveDuration("CHAMICORE_POWER_RETRY_BACKOFF_MAX", defaultRetryBackoffMax),
@@ -86,6 +184,40 @@ func Load() (Config, error) {
 		VerificationPoll:     envPositiveDuration("CHAMICORE_POWER_VERIFICATION_POLL_INTERVAL", defaultVerifyPoll),
 		GlobalConcurrency:    envPositiveInt("CHAMICORE_POWER_GLOBAL_CONCURRENCY", defaultGlobalWorkers),
 		PerBMCConcurrency:    envPositiveInt("CHAMICORE_POWER_PER_BMC_CONCURRENCY", defaultPerBMCWorkers),
+		SystemPathCacheTTL:   envPositiveDuration("CHAMICORE_POWER_SYSTEM_PATH_CACHE_TTL", defaultSystemPathTTL),
+		BootWaitTimeout:      envPositiveDuration("CHAMICORE_POWER_BOOT_WAIT_TIMEOUT", defaultBootWaitTimeout),
+		BootWaitPoll:         envPositiveDuration("CHAMICORE_POWER_BOOT_WAIT_POLL_INTERVAL", defaultBootWaitPoll),
+		QueueSize:            envPositiveInt("CHAMICORE_POWER_QUEUE_SIZE", 0),
+		QueuePriorities:      strings.TrimSpace(envOrDefault("CHAMICORE_POWER_QUEUE_PRIORITIES", "")),
+		SMDStateMap:          strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SMD_STATE_MAP", "")),
+		SMDBatchSize:         envPositiveInt("CHAMICORE_POWER_SMD_BATCH_SIZE", defaultSMDBatchSize),
+		SMDBatchWindow:       envPositiveDuration("CHAMICORE_POWER_SMD_BATCH_WINDOW", defaultSMDBatchWindow),
+		SMDUpdateConcurrency: envPositiveInt("CHAMICORE_POWER_SMD_UPDATE_CONCURRENCY", defaultSMDConcurrency),
+		TelemetryEnabled:     envBool("CHAMICORE_POWER_TELEMETRY_ENABLED", false),
+		TelemetryInterval:    envPositiveDuration("CHAMICORE_POWER_TELEMETRY_INTERVAL", defaultTelemetryInterval),
+		TelemetryResolution:  envPositiveDuration("CHAMICORE_POWER_TELEMETRY_RESOLUTION", defaultTelemetryRes),
+		TelemetryRetention:   envPositiveDuration("CHAMICORE_POWER_TELEMETRY_RETENTION", defaultTelemetryKeep),
+		TelemetryConcurrency: envPositiveInt("CHAMICORE_POWER_TELEMETRY_CONCURRENCY", defaultTelemetryWorkers),
+
+		ApprovalMaxNodes:        envPositiveInt("CHAMICORE_POWER_APPROVAL_MAX_NODES", 0),
+		ApprovalProtectedGroups: envList("CHAMICORE_POWER_APPROVAL_PROTECTED_GROUPS"),
+		ApprovalTTL:             envPositiveDuration("CHAMICORE_POWER_APPROVAL_TTL", defaultApprovalTTL),
+
+		PCSCompatEnabled: envBool("CHAMICORE_POWER_PCS_COMPAT_ENABLED", true),
+
+		WebhooksEnabled:     envBool("CHAMICORE_POWER_WEBHOOKS_ENABLED", true),
+		WebhookPollInterval: envPositiveDuration("CHAMICORE_POWER_WEBHOOK_POLL_INTERVAL", defaultWebhookPoll),
+		WebhookTimeout:      envPositiveDuration("CHAMICORE_POWER_WEBHOOK_TIMEOUT", defaultWebhookTimeout),
+		WebhookMaxAttempts:  envPositiveInt("CHAMICORE_POWER_WEBHOOK_MAX_ATTEMPTS", defaultWebhookAttempts),
+		WebhookBackoffBase:  envPositiveDuration("CHAMICORE_POWER_WEBHOOK_BACKOFF_BASE", defaultWebhookBackoff),
+		WebhookBackoffMax:   envPositiveDuration("CHAMICORE_POWER_WEBHOOK_BACKOFF_MAX", defaultWebhookBackoffMax),
+		WebhookRetention:    envPositiveDuration("CHAMICORE_POWER_WEBHOOK_RETENTION", defaultWebhookKeep),
+
+		RateLimitPerMinute:   envPositiveInt("CHAMICORE_POWER_RATE_LIMIT_PER_MINUTE", 0),
+		RateLimitBurst:       envPositiveInt("CHAMICORE_POWER_RATE_LIMIT_BURST", 0),
+		MaxActiveTransitions: envPositiveInt("CHAMICORE_POWER_MAX_ACTIVE_TRANSITIONS", 0),
+		QuotaOverrides:       strings.TrimSpace(envOrDefault("CHAMICORE_POWER_QUOTA_OVERRIDES", "")),
+		QuotaGroups:          strings.TrimSpace(envOrDefault("CHAMICORE_POWER_QUOTA_GROUPS", "")),
 	}
 
 	if strings.TrimSpace(cfg.DBDSN) == "" {
@@ -94,16 +226,46 @@ func Load() (Config, error) {
 	if strings.TrimSpace(cfg.NATSStream) == "" {
 		cfg.NATSStream = defaultNATSStream
 	}
+	if cfg.SMDNATSStream == "" {
+		cfg.SMDNATSStream = defaultSMDNATSStream
+	}
+	if cfg.ReplicaID == "" {
+		cfg.ReplicaID = defaultReplicaID()
+	}
+	if cfg.BulkHardMaxNodes < cfg.BulkMaxNodes {
+		cfg.BulkHardMaxNodes = cfg.BulkMaxNodes
+	}
 	if cfg.RetryBackoffMax < cfg.RetryBackoffBase {
 		cfg.RetryBackoffMax = cfg.RetryBackoffBase
 	}
 	if cfg.VerificationPoll > cfg.VerificationWindow {
 		cfg.VerificationPoll = cfg.VerificationWindow
 	}
+	if cfg.BootWaitPoll > cfg.BootWaitTimeout {
+		cfg.BootWaitPoll = cfg.BootWaitTimeout
+	}
+	if cfg.TelemetryResolution < cfg.TelemetryInterval {
+		cfg.TelemetryResolution = cfg.TelemetryInterval
+	}
+	if cfg.TelemetryRetention < cfg.TelemetryResolution {
+		cfg.TelemetryRetention = cfg.TelemetryResolution
+	}
+	if cfg.WebhookBackoffMax < cfg.WebhookBackoffBase {
+		cfg.WebhookBackoffMax = cfg.WebhookBackoffBase
+	}
 
 	return cfg, nil
 }
 
+// defaultReplicaID identifies this process for mapping sync leader reporting.
+func defaultReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil || strings.TrimSpace(hostname) == "" {
+		hostname = "chamicore-power"
+	}
+	return fmt.Sprintf("%s-%d", strings.TrimSpace(hostname), os.Getpid())
+}
+
 func envOrDefault(key, defaultVal string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -130,6 +292,17 @@ func envBool(key string, defaultVal bool) bool {
 	return b
 }
 
+// envList splits a comma-separated variable, dropping empty entries.
+func envList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
 func envPositiveInt(key string, defaultVal int) int {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
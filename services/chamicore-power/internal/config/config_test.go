@@ -14,6 +14,7 @@ func TestLoad_Defaults(t *testing.T) {
 	t.Setenv("CHAMICORE_POWER_SMD_URL", "")
 	t.Setenv("CHAMICORE_NATS_URL", "")
 	t.Setenv("CHAMICORE_POWER_NATS_STREAM", "")
+	t.Setenv("CHAMICORE_POWER_SMD_NATS_STREAM", "")
 	t.Setenv("CHAMICORE_POWER_LOG_LEVEL", "")
 	t.Setenv("CHAMICORE_POWER_DEV_MODE", "")
 	t.Setenv("CHAMICORE_POWER_JWKS_URL", "")
@@ -24,7 +25,13 @@ func TestLoad_Defaults(t *testing.T) {
 	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_INTERVAL", "")
 	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_ON_STARTUP", "")
 	t.Setenv("CHAMICORE_POWER_DEFAULT_CREDENTIAL_ID", "")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_LEADER_ELECTION", "")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_LEADER_POLL_INTERVAL", "")
+	t.Setenv("CHAMICORE_POWER_REPLICA_ID", "")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_EVENTS", "")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_PAGE_SIZE", "")
 	t.Setenv("CHAMICORE_POWER_BULK_MAX_NODES", "")
+	t.Setenv("CHAMICORE_POWER_BULK_HARD_MAX_NODES", "")
 	t.Setenv("CHAMICORE_POWER_RETRY_ATTEMPTS", "")
 	t.Setenv("CHAMICORE_POWER_RETRY_BACKOFF_BASE", "")
 	t.Setenv("CHAMICORE_POWER_RETRY_BACKOFF_MAX", "")
@@ -33,6 +40,24 @@ func TestLoad_Defaults(t *testing.T) {
 	t.Setenv("CHAMICORE_POWER_VERIFICATION_POLL_INTERVAL", "")
 	t.Setenv("CHAMICORE_POWER_GLOBAL_CONCURRENCY", "")
 	t.Setenv("CHAMICORE_POWER_PER_BMC_CONCURRENCY", "")
+	t.Setenv("CHAMICORE_POWER_SYSTEM_PATH_CACHE_TTL", "")
+	t.Setenv("CHAMICORE_POWER_BOOT_WAIT_TIMEOUT", "")
+	t.Setenv("CHAMICORE_POWER_BOOT_WAIT_POLL_INTERVAL", "")
+	t.Setenv("CHAMICORE_POWER_QUEUE_SIZE", "")
+	t.Setenv("CHAMICORE_POWER_QUEUE_PRIORITIES", "")
+	t.Setenv("CHAMICORE_POWER_SMD_STATE_MAP", "")
+	t.Setenv("CHAMICORE_POWER_SMD_BATCH_SIZE", "")
+	t.Setenv("CHAMICORE_POWER_SMD_BATCH_WINDOW", "")
+	t.Setenv("CHAMICORE_POWER_SMD_UPDATE_CONCURRENCY", "")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_ENABLED", "")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_INTERVAL", "")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_RESOLUTION", "")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_RETENTION", "")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_CONCURRENCY", "")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_MAX_NODES", "")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_PROTECTED_GROUPS", "")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_TTL", "")
+	t.Setenv("CHAMICORE_POWER_PCS_COMPAT_ENABLED", "")
 
 	cfg, err := Load()
 	require.NoError(t, err)
@@ -42,12 +67,19 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, defaultSMDURL, cfg.SMDURL)
 	assert.Equal(t, defaultNATSURL, cfg.NATSURL)
 	assert.Equal(t, defaultNATSStream, cfg.NATSStream)
+	assert.Equal(t, defaultSMDNATSStream, cfg.SMDNATSStream)
 	assert.Equal(t, "info", cfg.LogLevel)
 	assert.Equal(t, defaultPrometheusAddr, cfg.PrometheusAddr)
 	assert.Equal(t, defaultSyncInterval, cfg.MappingSyncInterval)
 	assert.True(t, cfg.MappingSyncOnStartup)
 	assert.Empty(t, cfg.DefaultCredentialID)
+	assert.True(t, cfg.SyncLeaderElection)
+	assert.Equal(t, defaultSyncLeaderPoll, cfg.SyncLeaderPoll)
+	assert.NotEmpty(t, cfg.ReplicaID)
+	assert.True(t, cfg.SyncEventsEnabled)
+	assert.Equal(t, defaultSyncPageSize, cfg.SyncPageSize)
 	assert.Equal(t, defaultBulkMaxNodes, cfg.BulkMaxNodes)
+	assert.Equal(t, defaultBulkHardMaxNodes, cfg.BulkHardMaxNodes)
 	assert.Equal(t, defaultRetryAttempts, cfg.RetryAttempts)
 	assert.Equal(t, defaultRetryBackoffBase, cfg.RetryBackoffBase)
 	assert.Equal(t, defaultRetryBackoffMax, cfg.RetryBackoffMax)
@@ -56,6 +88,36 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, defaultVerifyPoll, cfg.VerificationPoll)
 	assert.Equal(t, defaultGlobalWorkers, cfg.GlobalConcurrency)
 	assert.Equal(t, defaultPerBMCWorkers, cfg.PerBMCConcurrency)
+	assert.Equal(t, defaultSystemPathTTL, cfg.SystemPathCacheTTL)
+	assert.Equal(t, defaultBootWaitTimeout, cfg.BootWaitTimeout)
+	assert.Equal(t, defaultBootWaitPoll, cfg.BootWaitPoll)
+	assert.Zero(t, cfg.QueueSize)
+	assert.Empty(t, cfg.QueuePriorities)
+	assert.Empty(t, cfg.SMDStateMap)
+	assert.Equal(t, defaultSMDBatchSize, cfg.SMDBatchSize)
+	assert.Equal(t, defaultSMDBatchWindow, cfg.SMDBatchWindow)
+	assert.Equal(t, defaultSMDConcurrency, cfg.SMDUpdateConcurrency)
+	assert.False(t, cfg.TelemetryEnabled)
+	assert.Equal(t, defaultTelemetryInterval, cfg.TelemetryInterval)
+	assert.Equal(t, defaultTelemetryRes, cfg.TelemetryResolution)
+	assert.Equal(t, defaultTelemetryKeep, cfg.TelemetryRetention)
+	assert.Equal(t, defaultTelemetryWorkers, cfg.TelemetryConcurrency)
+	assert.Zero(t, cfg.ApprovalMaxNodes)
+	assert.Empty(t, cfg.ApprovalProtectedGroups)
+	assert.Equal(t, defaultApprovalTTL, cfg.ApprovalTTL)
+	assert.True(t, cfg.PCSCompatEnabled)
+	assert.True(t, cfg.WebhooksEnabled)
+	assert.Equal(t, defaultWebhookPoll, cfg.WebhookPollInterval)
+	assert.Equal(t, defaultWebhookTimeout, cfg.WebhookTimeout)
+	assert.Equal(t, defaultWebhookAttempts, cfg.WebhookMaxAttempts)
+	assert.Equal(t, defaultWebhookBackoff, cfg.WebhookBackoffBase)
+	assert.Equal(t, defaultWebhookBackoffMax, cfg.WebhookBackoffMax)
+	assert.Equal(t, defaultWebhookKeep, cfg.WebhookRetention)
+	assert.Zero(t, cfg.RateLimitPerMinute)
+	assert.Zero(t, cfg.RateLimitBurst)
+	assert.Zero(t, cfg.MaxActiveTransitions)
+	assert.Empty(t, cfg.QuotaOverrides)
+	assert.Empty(t, cfg.QuotaGroups)
 }
 
 func TestLoad_Normalization(t *testing.T) {
@@ -66,10 +128,39 @@ func TestLoad_Normalization(t *testing.T) {
 	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_INTERVAL", "45s")
 	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_ON_STARTUP", "false")
 	t.Setenv("CHAMICORE_POWER_DEFAULT_CREDENTIAL_ID", " cred-default ")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_LEADER_ELECTION", "off")
+	t.Setenv("CHAMICORE_POWER_REPLICA_ID", " power-0 ")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_EVENTS", "false")
+	t.Setenv("CHAMICORE_POWER_SMD_NATS_STREAM", " SMD_STREAM ")
+	t.Setenv("CHAMICORE_POWER_MAPPING_SYNC_PAGE_SIZE", "250")
+	t.Setenv("CHAMICORE_POWER_BULK_MAX_NODES", "64")
+	t.Setenv("CHAMICORE_POWER_BULK_HARD_MAX_NODES", "32")
 	t.Setenv("CHAMICORE_POWER_RETRY_BACKOFF_BASE", "2s")
 	t.Setenv("CHAMICORE_POWER_RETRY_BACKOFF_MAX", "500ms")
 	t.Setenv("CHAMICORE_POWER_VERIFICATION_WINDOW", "20s")
 	t.Setenv("CHAMICORE_POWER_VERIFICATION_POLL_INTERVAL", "30s")
+	t.Setenv("CHAMICORE_POWER_BOOT_WAIT_TIMEOUT", "5m")
+	t.Setenv("CHAMICORE_POWER_BOOT_WAIT_POLL_INTERVAL", "10m")
+	t.Setenv("CHAMICORE_POWER_SMD_STATE_MAP", " Failed=Standby:Alert ")
+	t.Setenv("CHAMICORE_POWER_QUEUE_SIZE", "500")
+	t.Setenv("CHAMICORE_POWER_QUEUE_PRIORITIES", " ForceOff=high ")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_ENABLED", "true")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_INTERVAL", "2m")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_RESOLUTION", "1m")
+	t.Setenv("CHAMICORE_POWER_TELEMETRY_RETENTION", "24h")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_MAX_NODES", "500")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_PROTECTED_GROUPS", " storage, ,login ")
+	t.Setenv("CHAMICORE_POWER_APPROVAL_TTL", "30m")
+	t.Setenv("CHAMICORE_POWER_PCS_COMPAT_ENABLED", "false")
+	t.Setenv("CHAMICORE_POWER_WEBHOOKS_ENABLED", "false")
+	t.Setenv("CHAMICORE_POWER_WEBHOOK_MAX_ATTEMPTS", "3")
+	t.Setenv("CHAMICORE_POWER_WEBHOOK_BACKOFF_BASE", "1m")
+	t.Setenv("CHAMICORE_POWER_WEBHOOK_BACKOFF_MAX", "30s")
+	t.Setenv("CHAMICORE_POWER_RATE_LIMIT_PER_MINUTE", "30")
+	t.Setenv("CHAMICORE_POWER_RATE_LIMIT_BURST", "10")
+	t.Setenv("CHAMICORE_POWER_MAX_ACTIVE_TRANSITIONS", "4")
+	t.Setenv("CHAMICORE_POWER_QUOTA_OVERRIDES", " scope:admin=0/0/0 ")
+	t.Setenv("CHAMICORE_POWER_QUOTA_GROUPS", " ci=svc-jenkins ")
 
 	cfg, err := Load()
 	require.NoError(t, err)
@@ -80,10 +171,39 @@ func TestLoad_Normalization(t *testing.T) {
 	assert.Equal(t, 45*time.Second, cfg.MappingSyncInterval)
 	assert.False(t, cfg.MappingSyncOnStartup)
 	assert.Equal(t, "cred-default", cfg.DefaultCredentialID)
+	assert.False(t, cfg.SyncLeaderElection)
+	assert.Equal(t, "power-0", cfg.ReplicaID)
+	assert.False(t, cfg.SyncEventsEnabled)
+	assert.Equal(t, "SMD_STREAM", cfg.SMDNATSStream)
+	assert.Equal(t, 250, cfg.SyncPageSize)
+	assert.Equal(t, 64, cfg.BulkMaxNodes)
+	assert.Equal(t, 64, cfg.BulkHardMaxNodes)
 	assert.Equal(t, 2*time.Second, cfg.RetryBackoffBase)
 	assert.Equal(t, 2*time.Second, cfg.RetryBackoffMax)
 	assert.Equal(t, 20*time.Second, cfg.VerificationWindow)
 	assert.Equal(t, 20*time.Second, cfg.VerificationPoll)
+	assert.Equal(t, 5*time.Minute, cfg.BootWaitTimeout)
+	assert.Equal(t, 5*time.Minute, cfg.BootWaitPoll)
+	assert.Equal(t, "Failed=Standby:Alert", cfg.SMDStateMap)
+	assert.Equal(t, 500, cfg.QueueSize)
+	assert.Equal(t, "ForceOff=high", cfg.QueuePriorities)
+	assert.True(t, cfg.TelemetryEnabled)
+	assert.Equal(t, 2*time.Minute, cfg.TelemetryInterval)
+	assert.Equal(t, 2*time.Minute, cfg.TelemetryResolution)
+	assert.Equal(t, 24*time.Hour, cfg.TelemetryRetention)
+	assert.Equal(t, 500, cfg.ApprovalMaxNodes)
+	assert.Equal(t, []string{"storage", "login"}, cfg.ApprovalProtectedGroups)
+	assert.Equal(t, 30*time.Minute, cfg.ApprovalTTL)
+	assert.False(t, cfg.PCSCompatEnabled)
+	assert.False(t, cfg.WebhooksEnabled)
+	assert.Equal(t, 3, cfg.WebhookMaxAttempts)
+	assert.Equal(t, time.Minute, cfg.WebhookBackoffBase)
+	assert.Equal(t, time.Minute, cfg.WebhookBackoffMax)
+	assert.Equal(t, 30, cfg.RateLimitPerMinute)
+	assert.Equal(t, 10, cfg.RateLimitBurst)
+	assert.Equal(t, 4, cfg.MaxActiveTransitions)
+	assert.Equal(t, "scope:admin=0/0/0", cfg.QuotaOverrides)
+	assert.Equal(t, "ci=svc-jenkins", cfg.QuotaGroups)
 }
 
 func TestLoad_InvalidOrZeroUsesDefaults(t *testing.T) {
@@ -107,3 +227,35 @@ func TestLoad_InvalidOrZeroUsesDefaults(t *testing.T) {
 	assert.Equal(t, defaultGlobalWorkers, cfg.GlobalConcurrency)
 	assert.Equal(t, defaultPerBMCWorkers, cfg.PerBMCConcurrency)
 }
+
+func TestLoadSlurm(t *testing.T) {
+	t.Setenv("CHAMICORE_POWER_SLURM_POWER_URL", "")
+	t.Setenv("CHAMICORE_POWER_SLURM_SMD_URL", "")
+	t.Setenv("CHAMICORE_POWER_SLURM_TOKEN", "")
+	t.Setenv("CHAMICORE_TOKEN", "shared-token")
+	t.Setenv("CHAMICORE_POWER_SLURM_TIMEOUT", "")
+	t.Setenv("CHAMICORE_POWER_SLURM_POLL_INTERVAL", "-1s")
+	t.Setenv("CHAMICORE_POWER_SLURM_ESCALATE_AFTER", "")
+	t.Setenv("CHAMICORE_POWER_SLURM_LOG_LEVEL", "")
+	t.Setenv("CHAMICORE_POWER_SLURM_LOG_FILE", "")
+
+	cfg := LoadSlurm()
+	assert.Equal(t, defaultSlurmPowerURL, cfg.PowerURL)
+	assert.Equal(t, defaultSMDURL, cfg.SMDURL)
+	assert.Equal(t, "shared-token", cfg.Token)
+	assert.Equal(t, defaultSlurmTimeout, cfg.Timeout)
+	assert.Equal(t, defaultSlurmPoll, cfg.PollInterval)
+	assert.Equal(t, defaultSlurmEscalate, cfg.EscalateAfter)
+	assert.Equal(t, "info", cfg.LogLevel)
+
+	t.Setenv("CHAMICORE_POWER_SLURM_TOKEN", " slurm-token ")
+	t.Setenv("CHAMICORE_POWER_SLURM_TIMEOUT", "300s")
+	t.Setenv("CHAMICORE_POWER_SLURM_ESCALATE_AFTER", "off")
+	t.Setenv("CHAMICORE_POWER_SLURM_LOG_FILE", "/var/log/slurm/power.log")
+
+	cfg = LoadSlurm()
+	assert.Equal(t, "slurm-token", cfg.Token)
+	assert.Equal(t, 5*time.Minute, cfg.Timeout)
+	assert.Zero(t, cfg.EscalateAfter)
+	assert.Equal(t, "/var/log/slurm/power.log", cfg.LogFile)
+}
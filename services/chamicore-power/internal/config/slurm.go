@@ -0,0 +1,51 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	defaultSlurmPowerURL = "http://localhost:27775"
+	defaultSlurmTimeout  = 10 * time.Minute
+	defaultSlurmPoll     = 5 * time.Second
+	defaultSlurmEscalate = 2 * time.Minute
+	defaultSlurmLogLevel = "info"
+)
+
+// SlurmConfig holds configuration for the chamicore-power-slurm program run
+// by slurmctld as its SuspendProgram and ResumeProgram.
+type SlurmConfig struct {
+	PowerURL string
+	SMDURL   string
+	// Token authenticates against both power and SMD; it falls back to
+	// CHAMICORE_TOKEN.
+	Token        string
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// EscalateAfter forces suspended nodes off when their graceful shutdown
+	// has not finished; "0" or "off" disables escalation.
+	EscalateAfter time.Duration
+	LogLevel      string
+	// LogFile receives logs in addition to stderr, which slurmctld discards.
+	LogFile string
+}
+
+// LoadSlurm reads Slurm program configuration from environment variables.
+func LoadSlurm() SlurmConfig {
+	cfg := SlurmConfig{
+		PowerURL:      strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_POWER_URL", defaultSlurmPowerURL)),
+		SMDURL:        strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_SMD_URL", defaultSMDURL)),
+		Token:         strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_TOKEN", envOrDefault("CHAMICORE_TOKEN", ""))),
+		Timeout:       envPositiveDuration("CHAMICORE_POWER_SLURM_TIMEOUT", defaultSlurmTimeout),
+		PollInterval:  envPositiveDuration("CHAMICORE_POWER_SLURM_POLL_INTERVAL", defaultSlurmPoll),
+		EscalateAfter: envPositiveDuration("CHAMICORE_POWER_SLURM_ESCALATE_AFTER", defaultSlurmEscalate),
+		LogLevel:      strings.ToLower(strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_LOG_LEVEL", defaultSlurmLogLevel))),
+		LogFile:       strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_LOG_FILE", "")),
+	}
+	switch strings.ToLower(strings.TrimSpace(envOrDefault("CHAMICORE_POWER_SLURM_ESCALATE_AFTER", ""))) {
+	case "0", "off":
+		cfg.EscalateAfter = 0
+	}
+	return cfg
+}
@@ -0,0 +1,232 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+const defaultApprovalSweepInterval = 30 * time.Second
+
+// ApprovalAction values recorded in a transition approval trail.
+const (
+	ApprovalActionRequested = "requested"
+	ApprovalActionApproved  = "approved"
+	ApprovalActionRejected  = "rejected"
+	ApprovalActionExpired   = "expired"
+	ApprovalActionCanceled  = "canceled"
+)
+
+var (
+	// ErrApprovalUnsupported indicates the store cannot persist approval decisions.
+	ErrApprovalUnsupported = errors.New("transition approvals are not supported")
+	// ErrApprovalNotPending indicates the transition is not awaiting approval.
+	ErrApprovalNotPending = errors.New("transition is not pending approval")
+	// ErrApprovalExpired indicates the approval window of a transition elapsed.
+	ErrApprovalExpired = errors.New("transition approval expired")
+	// ErrSelfApproval indicates the requester tried to approve their own transition.
+	ErrSelfApproval = errors.New("transition must be approved by a different principal")
+	// ErrInvalidApprovalAction indicates an unknown approval decision.
+	ErrInvalidApprovalAction = errors.New("invalid approval action")
+)
+
+// ApprovalRequirement holds a transition in pending-approval until a second
+// principal approves it.
+type ApprovalRequirement struct {
+	// Reason explains which policy required the approval.
+	Reason string
+	// TTL is how long the approval may stay pending, measured on the runner
+	// clock that the expiry sweep uses.
+	TTL time.Duration
+}
+
+// ApprovalDecision is one entry of a transition approval trail.
+type ApprovalDecision struct {
+	TransitionID string
+	Action       string
+	Principal    string
+	Reason       string
+	DecidedAt    time.Time
+}
+
+// ApprovalStore is implemented by stores that persist approval decisions.
+type ApprovalStore interface {
+	GetTransition(ctx context.Context, id string) (Transition, error)
+	ListTransitionTasks(ctx context.Context, transitionID string) ([]Task, error)
+	// DecideTransitionApproval records a decision on a pending-approval
+	// transition. Approving moves it to pending; any other decision ends it
+	// and cancels its tasks. It returns ErrApprovalNotPending when the
+	// transition is no longer awaiting approval.
+	DecideTransitionApproval(ctx context.Context, decision ApprovalDecision) (Transition, error)
+	// ExpireTransitionApprovals records an expired decision on every
+	// pending-approval transition whose window ended before now.
+	ExpireTransitionApprovals(ctx context.Context, now time.Time) (int, error)
+}
+
+// DecideApproval approves, rejects or cancels a pending-approval transition.
+// Approved transitions are enqueued like a newly started one.
+func (r *Runner) DecideApproval(ctx context.Context, decision ApprovalDecision) (Transition, error) {
+	if !r.isRunning() {
+		return Transition{}, ErrRunnerNotStarted
+	}
+	approvals, ok := r.store.(ApprovalStore)
+	if !ok {
+		return Transition{}, ErrApprovalUnsupported
+	}
+
+	switch decision.Action {
+	case ApprovalActionApproved, ApprovalActionRejected, ApprovalActionCanceled:
+	default:
+		return Transition{}, fmt.Errorf("%w: %q", ErrInvalidApprovalAction, decision.Action)
+	}
+	decision.TransitionID = strings.TrimSpace(decision.TransitionID)
+	decision.Principal = strings.TrimSpace(decision.Principal)
+	decision.Reason = strings.TrimSpace(decision.Reason)
+
+	transition, err := approvals.GetTransition(ctx, decision.TransitionID)
+	if err != nil {
+		return Transition{}, err
+	}
+	if transition.State != TransitionStatePendingApproval {
+		return Transition{}, ErrApprovalNotPending
+	}
+	if decision.Action == ApprovalActionApproved && decision.Principal == strings.TrimSpace(transition.RequestedBy) {
+		return Transition{}, ErrSelfApproval
+	}
+
+	now := r.cfg.now().UTC()
+	decision.DecidedAt = now
+	if transition.ApprovalExpiresAt != nil && !now.Before(*transition.ApprovalExpiresAt) {
+		if _, expireErr := approvals.DecideTransitionApproval(ctx, ApprovalDecision{
+			TransitionID: transition.ID,
+			Action:       ApprovalActionExpired,
+			DecidedAt:    now,
+		}); expireErr != nil && !errors.Is(expireErr, ErrApprovalNotPending) {
+			return Transition{}, fmt.Errorf("expiring transition approval: %w", expireErr)
+		}
+		return Transition{}, ErrApprovalExpired
+	}
+
+	if decision.Action == ApprovalActionApproved {
+		// Approved transitions are admitted like new ones, before the
+		// approval is recorded, so a full queue leaves them pending.
+		release, admitErr := r.admit()
+		if admitErr != nil {
+			return Transition{}, admitErr
+		}
+		defer release()
+	}
+
+	decided, err := approvals.DecideTransitionApproval(ctx, decision)
+	if err != nil {
+		return Transition{}, err
+	}
+	if decision.Action != ApprovalActionApproved {
+		return decided, nil
+	}
+
+	tasks, err := approvals.ListTransitionTasks(ctx, decided.ID)
+	if err != nil {
+		return Transition{}, fmt.Errorf("loading approved transition tasks: %w", err)
+	}
+	return r.enqueueApproved(ctx, decided, tasks)
+}
+
+// enqueueApproved enqueues the pending tasks of an approved transition.
+// Credentials are not persisted, so node mappings are resolved again; nodes
+// that lost their mapping while awaiting approval fail.
+func (r *Runner) enqueueApproved(ctx context.Context, transition Transition, tasks []Task) (Transition, error) {
+	pendingTasks := make([]Task, 0, len(tasks))
+	nodeIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.State == TaskStatePending {
+			pendingTasks = append(pendingTasks, task)
+			nodeIDs = append(nodeIDs, task.NodeID)
+		}
+	}
+
+	mappings, _, err := r.store.ResolveNodeMappings(ctx, nodeIDs)
+	if err != nil {
+		return Transition{}, fmt.Errorf("resolving node mappings: %w", err)
+	}
+	mappingByNode := make(map[string]model.NodePowerMapping, len(mappings))
+	for _, mapping := range mappings {
+		mappingByNode[strings.TrimSpace(mapping.NodeID)] = mapping
+	}
+
+	now := r.cfg.now().UTC()
+	runnable := make([]Task, 0, len(pendingTasks))
+	for _, task := range pendingTasks {
+		mapping, ok := mappingByNode[strings.TrimSpace(task.NodeID)]
+		if !ok {
+			completedAt := now
+			task.State = TaskStateFailed
+			task.ErrorDetail = model.MissingNodeMappingError(task.NodeID).Detail
+			task.CompletedAt = &completedAt
+			task.UpdatedAt = now
+			if _, updateErr := r.store.UpdateTransitionTask(ctx, task); updateErr != nil {
+				return Transition{}, fmt.Errorf("failing unmapped task: %w", updateErr)
+			}
+			transition.FailureCount++
+			continue
+		}
+		task.CredentialID = strings.TrimSpace(mapping.CredentialID)
+		task.InsecureSkipVerify = mapping.InsecureSkipVerify
+		runnable = append(runnable, task)
+	}
+
+	if len(runnable) == 0 {
+		completedAt := now
+		transition.State = TransitionStateFailed
+		transition.CompletedAt = &completedAt
+		transition.UpdatedAt = now
+		updated, updateErr := r.store.UpdateTransition(ctx, transition)
+		if updateErr != nil {
+			return Transition{}, fmt.Errorf("updating terminal transition record: %w", updateErr)
+		}
+		return updated, nil
+	}
+
+	if err := r.enqueueTransition(transition, runnable, taskReset(transition.Operation, runnable[0])); err != nil {
+		return Transition{}, err
+	}
+	return transition, nil
+}
+
+// sweepApprovals expires pending approvals until ctx is done.
+func (r *Runner) sweepApprovals(ctx context.Context, approvals ApprovalStore) {
+	ticker := time.NewTicker(r.cfg.approvalSweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := approvals.ExpireTransitionApprovals(ctx, r.cfg.now().UTC()); err != nil {
+				r.log.Warn().Err(err).Msg("failed to expire pending approvals")
+			}
+		}
+	}
+}
+
+// taskReset returns the Redfish reset a persisted task issues; empty for
+// setting-only operations.
+func taskReset(operation string, task Task) redfish.ResetOperation {
+	if operation == OperationBootOverrideReset {
+		return redfish.ResetOperation(strings.TrimSpace(task.ResetOperation))
+	}
+	if isPowerCapOperation(operation) || isBootControlOperation(operation) {
+		return ""
+	}
+	parsed, err := redfish.ParseResetOperation(operation)
+	if err != nil {
+		return ""
+	}
+	return parsed
+}
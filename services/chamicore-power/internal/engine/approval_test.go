@@ -0,0 +1,262 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+// approvalMemoryStore adds approval persistence to memoryStore.
+type approvalMemoryStore struct {
+	*memoryStore
+	trail []ApprovalDecision
+}
+
+func (s *approvalMemoryStore) GetTransition(ctx context.Context, id string) (Transition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	transition, ok := s.transitions[id]
+	if !ok {
+		return Transition{}, errors.New("not found")
+	}
+	return transition, nil
+}
+
+func (s *approvalMemoryStore) ListTransitionTasks(ctx context.Context, transitionID string) ([]Task, error) {
+	return s.tasksForTransition(transitionID), nil
+}
+
+func (s *approvalMemoryStore) DecideTransitionApproval(ctx context.Context, decision ApprovalDecision) (Transition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transition := s.transitions[decision.TransitionID]
+	if transition.State != TransitionStatePendingApproval {
+		return Transition{}, ErrApprovalNotPending
+	}
+	s.trail = append(s.trail, decision)
+
+	switch decision.Action {
+	case ApprovalActionApproved:
+		transition.State = TransitionStatePending
+	case ApprovalActionRejected:
+		transition.State = TransitionStateRejected
+	case ApprovalActionExpired:
+		transition.State = TransitionStateExpired
+	default:
+		transition.State = TransitionStateCanceled
+	}
+	if transition.State != TransitionStatePending {
+		for _, taskID := range s.tasksByTransition[transition.ID] {
+			task := s.tasks[taskID]
+			if task.State == TaskStatePending {
+				task.State = TaskStateCanceled
+				s.tasks[taskID] = task
+				transition.FailureCount++
+			}
+		}
+		s.closeTerminalLocked(transition.ID)
+	}
+	s.transitions[transition.ID] = transition
+	return transition, nil
+}
+
+func (s *approvalMemoryStore) ExpireTransitionApprovals(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	ids := make([]string, 0)
+	for id, transition := range s.transitions {
+		if transition.State == TransitionStatePendingApproval && !now.Before(*transition.ApprovalExpiresAt) {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		if _, err := s.DecideTransitionApproval(ctx, ApprovalDecision{TransitionID: id, Action: ApprovalActionExpired}); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+func newApprovalTestStore() *approvalMemoryStore {
+	return &approvalMemoryStore{memoryStore: newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-a", Endpoint: "https://bmc-a", CredentialID: "cred-a"},
+		{NodeID: "node-2", BMCID: "bmc-b", Endpoint: "https://bmc-b", CredentialID: "cred-b"},
+	}, nil)}
+}
+
+// startApprovalTestRunner starts a runner that powers nodes off and counts
+// its reset calls.
+func startApprovalTestRunner(t *testing.T, store *approvalMemoryStore, sweep time.Duration) (*Runner, *atomic.Int32) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		calls.Add(1)
+		return nil
+	}}
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		return "Off", nil
+	}}
+	runner := startTestRunner(t, store, withExecutor(exec), withReader(reader), withConfig(func(cfg *Config) {
+		cfg.GlobalConcurrency = 2
+		cfg.ApprovalSweepInterval = sweep
+	}))
+	return runner, calls
+}
+
+func TestRunner_ApprovalHoldsTransitionUntilApproved(t *testing.T) {
+	store := newApprovalTestStore()
+	runner, calls := startApprovalTestRunner(t, store, time.Hour)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:   "ForceOff",
+		NodeIDs:     []string{"node-1", "node-2"},
+		RequestedBy: "alice",
+		Approval:    &ApprovalRequirement{Reason: "protected group", TTL: time.Hour},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransitionStatePendingApproval, transition.State)
+	assert.Equal(t, "protected group", transition.ApprovalReason)
+	require.NotNil(t, transition.ApprovalExpiresAt)
+	assert.Equal(t, transition.CreatedAt.Add(time.Hour), *transition.ApprovalExpiresAt)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, int(calls.Load()), "pending-approval transitions must not run")
+
+	_, err = runner.DecideApproval(context.Background(), ApprovalDecision{
+		TransitionID: transition.ID,
+		Action:       ApprovalActionApproved,
+		Principal:    "alice",
+	})
+	require.ErrorIs(t, err, ErrSelfApproval)
+
+	approved, err := runner.DecideApproval(context.Background(), ApprovalDecision{
+		TransitionID: transition.ID,
+		Action:       ApprovalActionApproved,
+		Principal:    "bob",
+		Reason:       "maintenance window",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransitionStatePending, approved.State)
+	require.Len(t, store.trail, 1)
+	assert.Equal(t, "bob", store.trail[0].Principal)
+
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+	assert.Equal(t, TransitionStateCompleted, store.transition(transition.ID).State)
+	assert.Equal(t, 2, int(calls.Load()))
+	for _, task := range store.tasksForTransition(transition.ID) {
+		assert.Equal(t, TaskStateSucceeded, task.State)
+	}
+
+	_, err = runner.DecideApproval(context.Background(), ApprovalDecision{
+		TransitionID: transition.ID,
+		Action:       ApprovalActionRejected,
+		Principal:    "carol",
+	})
+	require.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestRunner_ApprovalIsAdmittedAgainstQueue(t *testing.T) {
+	store := newApprovalTestStore()
+	runner, calls := startApprovalTestRunner(t, store, time.Hour)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:   "ForceOff",
+		NodeIDs:     []string{"node-1"},
+		RequestedBy: "alice",
+		Approval:    &ApprovalRequirement{Reason: "protected group", TTL: time.Hour},
+	})
+	require.NoError(t, err)
+
+	held := 0
+	for {
+		if _, ok := runner.queue.reserve(runner.cfg.queueSize); !ok {
+			break
+		}
+		held++
+	}
+	decision := ApprovalDecision{TransitionID: transition.ID, Action: ApprovalActionApproved, Principal: "bob"}
+	_, err = runner.DecideApproval(context.Background(), decision)
+	require.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, TransitionStatePendingApproval, store.transition(transition.ID).State, "a rejected admission leaves the approval pending")
+	assert.Empty(t, store.trail)
+
+	for range held {
+		runner.queue.unreserve()
+	}
+	_, err = runner.DecideApproval(context.Background(), decision)
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+	assert.Equal(t, 1, int(calls.Load()))
+}
+
+func TestRunner_RejectedApprovalCancelsTasks(t *testing.T) {
+	store := newApprovalTestStore()
+	runner, calls := startApprovalTestRunner(t, store, time.Hour)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:   "ForceOff",
+		NodeIDs:     []string{"node-1", "node-2"},
+		RequestedBy: "alice",
+		Approval:    &ApprovalRequirement{Reason: "large", TTL: time.Hour},
+	})
+	require.NoError(t, err)
+
+	rejected, err := runner.DecideApproval(context.Background(), ApprovalDecision{
+		TransitionID: transition.ID,
+		Action:       ApprovalActionRejected,
+		Principal:    "alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransitionStateRejected, rejected.State)
+	assert.Zero(t, int(calls.Load()))
+	for _, task := range store.tasksForTransition(transition.ID) {
+		assert.Equal(t, TaskStateCanceled, task.State)
+	}
+}
+
+func TestRunner_ApprovalExpires(t *testing.T) {
+	store := newApprovalTestStore()
+	runner, _ := startApprovalTestRunner(t, store, 10*time.Millisecond)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:   "ForceOff",
+		NodeIDs:     []string{"node-1"},
+		RequestedBy: "alice",
+		Approval:    &ApprovalRequirement{Reason: "large", TTL: -time.Second},
+	})
+	require.NoError(t, err)
+
+	require.True(t, store.waitForTerminal(transition.ID, time.Second))
+	assert.Equal(t, TransitionStateExpired, store.transition(transition.ID).State)
+
+	_, err = runner.DecideApproval(context.Background(), ApprovalDecision{
+		TransitionID: transition.ID,
+		Action:       ApprovalActionApproved,
+		Principal:    "bob",
+	})
+	require.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestRunner_DryRunIgnoresApproval(t *testing.T) {
+	runner, _ := startApprovalTestRunner(t, newApprovalTestStore(), time.Hour)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "ForceOff",
+		NodeIDs:   []string{"node-1"},
+		DryRun:    true,
+		Approval:  &ApprovalRequirement{Reason: "large", TTL: time.Hour},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransitionStatePlanned, transition.State)
+	assert.Empty(t, transition.ApprovalReason)
+}
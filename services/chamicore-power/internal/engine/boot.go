@@ -0,0 +1,292 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+const (
+	defaultBootWaitTimeout = 15 * time.Minute
+	defaultBootWaitPoll    = 10 * time.Second
+
+	// BootStageReady is recorded when SMD reports the node Ready.
+	BootStageReady = "Ready"
+)
+
+var (
+	// ErrInvalidBootWait indicates a boot-tracking request cannot be honored.
+	ErrInvalidBootWait = errors.New("invalid boot wait")
+	// ErrBootTimeout indicates a node did not reach its boot target in time.
+	ErrBootTimeout = errors.New("boot wait timed out")
+)
+
+// bootProgressOrder ranks Redfish BootProgress.LastState values a booting node
+// moves through. SetupEntered and OEM stages are recorded but never satisfy a target.
+var bootProgressOrder = []string{
+	"None",
+	"PrimaryProcessorInitializationStarted",
+	"BusInitializationStarted",
+	"MemoryInitializationStarted",
+	"SecondaryProcessorInitializationStarted",
+	"PCIResourceConfigStarted",
+	"SystemHardwareInitializationComplete",
+	"OSBootStarted",
+	"OSRunning",
+}
+
+// BootTargets lists the BootProgress stages a transition may wait for.
+var BootTargets = []string{
+	"SystemHardwareInitializationComplete",
+	"OSBootStarted",
+	"OSRunning",
+}
+
+// BootWait requests a post-power phase that waits for the node to boot.
+type BootWait struct {
+	// Target is the BootProgress stage to reach; empty waits only for SMD readiness.
+	Target string
+	// WaitForReady also waits for SMD to report the node Ready via heartbeat.
+	WaitForReady bool
+}
+
+// BootStage records when a task first observed one boot stage.
+type BootStage struct {
+	Stage      string
+	ObservedAt time.Time
+}
+
+// NodeReadinessTracker resets and reads SMD node state for boot tracking.
+type NodeReadinessTracker interface {
+	MarkNodeBooting(ctx context.Context, nodeID string) error
+	ReadNodeState(ctx context.Context, nodeID string) (string, error)
+}
+
+// WithNodeReadiness sets the SMD readiness tracker used by boot waits.
+func WithNodeReadiness(tracker NodeReadinessTracker) Option {
+	return func(r *Runner) {
+		r.readiness = tracker
+	}
+}
+
+func (r *Runner) validateBootWait(operation redfish.ResetOperation, wait *BootWait) (*BootWait, error) {
+	if wait == nil {
+		return nil, nil
+	}
+
+	target := strings.TrimSpace(wait.Target)
+	if target == "" && !wait.WaitForReady {
+		return nil, nil
+	}
+
+	switch operation {
+	case redfish.ResetOperationOn, redfish.ResetOperationGracefulRestart, redfish.ResetOperationForceRestart:
+	default:
+		return nil, fmt.Errorf("%w: operation %q does not boot the node", ErrInvalidBootWait, operation)
+	}
+
+	if target != "" {
+		canonical := ""
+		for _, candidate := range BootTargets {
+			if strings.EqualFold(candidate, target) {
+				canonical = candidate
+				break
+			}
+		}
+		if canonical == "" {
+			return nil, fmt.Errorf(
+				"%w: unknown boot target %q, expected one of %s",
+				ErrInvalidBootWait,
+				target,
+				strings.Join(BootTargets, ", "),
+			)
+		}
+		if r.systemReader == nil {
+			return nil, fmt.Errorf("%w: boot progress is not available from the power-state reader", ErrInvalidBootWait)
+		}
+		target = canonical
+	}
+	if wait.WaitForReady && r.readiness == nil {
+		return nil, fmt.Errorf("%w: SMD readiness tracking is not configured", ErrInvalidBootWait)
+	}
+
+	return &BootWait{Target: target, WaitForReady: wait.WaitForReady}, nil
+}
+
+// applyBootWait validates a boot wait against the plan's reset operation and
+// records it on the task template.
+func (r *Runner) applyBootWait(plan *operationPlan, wait *BootWait) error {
+	validated, err := r.validateBootWait(plan.reset, wait)
+	if err != nil {
+		return err
+	}
+	if validated != nil {
+		plan.template.BootTarget = validated.Target
+		plan.template.BootWaitReady = validated.WaitForReady
+	}
+	return nil
+}
+
+func hasBootWait(task Task) bool {
+	return task.BootTarget != "" || task.BootWaitReady
+}
+
+// trackBoot polls boot progress and SMD readiness after a verified power-on
+// until the task's boot target is reached, then completes the task. It runs
+// outside the worker so long boots do not block other nodes, but takes the
+// BMC limiter for every boot-progress read.
+func (r *Runner) trackBoot(
+	ctx context.Context,
+	execCtx context.Context,
+	transitionID string,
+	task Task,
+	attempts int,
+	req ExecutionRequest,
+	finalPowerState string,
+) {
+	bootCtx, cancel := context.WithTimeout(execCtx, r.cfg.bootWaitTimeout)
+	defer cancel()
+
+	if task.BootWaitReady {
+		if err := r.readiness.MarkNodeBooting(bootCtx, task.NodeID); err != nil {
+			r.completeTask(ctx, transitionID, task, attempts, finalPowerState, fmt.Errorf("marking SMD node booting: %w", err))
+			return
+		}
+	}
+
+	stageReached := task.BootTarget == ""
+	ready := !task.BootWaitReady
+	for {
+		now := r.cfg.now().UTC()
+		changed := false
+
+		if !stageReached {
+			// Read errors are tolerated; BMCs are often slow to answer during POST.
+			if state, err := r.readBootState(bootCtx, req); err == nil {
+				stage := bootStageOf(state)
+				if stage != "" && stage != task.BootStage {
+					task.BootStage = stage
+					task.BootStages = append(task.BootStages, BootStage{Stage: stage, ObservedAt: now})
+					changed = true
+				}
+				stageReached = bootStageReached(stage, task.BootTarget)
+			}
+		}
+		if !ready {
+			if state, err := r.readiness.ReadNodeState(bootCtx, task.NodeID); err == nil && strings.EqualFold(strings.TrimSpace(state), BootStageReady) {
+				ready = true
+				task.BootStages = append(task.BootStages, BootStage{Stage: BootStageReady, ObservedAt: now})
+				changed = true
+			}
+		}
+
+		if stageReached && ready {
+			r.completeTask(ctx, transitionID, task, attempts, finalPowerState, nil)
+			return
+		}
+		if changed {
+			task.UpdatedAt = now
+			updated, err := r.store.UpdateTransitionTask(ctx, task)
+			if err != nil {
+				r.log.Warn().
+					Err(err).
+					Str("transition_id", transitionID).
+					Str("node_id", task.NodeID).
+					Msg("failed to persist boot progress")
+			} else {
+				task = updated
+			}
+		}
+
+		if err := r.cfg.sleep(bootCtx, r.cfg.bootWaitPoll); err != nil {
+			if execErr := execCtx.Err(); execErr != nil {
+				r.completeTask(ctx, transitionID, task, attempts, finalPowerState, execErr)
+				return
+			}
+			r.completeTask(ctx, transitionID, task, attempts, finalPowerState, bootTimeoutError(task, ready, r.cfg.bootWaitTimeout))
+			return
+		}
+	}
+}
+
+// readBootState reads the system state while holding the BMC limiter, so boot
+// polling shares the per-BMC concurrency with resets and verification.
+func (r *Runner) readBootState(ctx context.Context, req ExecutionRequest) (SystemState, error) {
+	release, err := r.acquireBMCLimiter(ctx, req.BMCID)
+	if err != nil {
+		return SystemState{}, err
+	}
+	defer release()
+	return r.systemReader.ReadSystemState(ctx, req)
+}
+
+func bootTimeoutError(task Task, ready bool, timeout time.Duration) error {
+	waitingFor := make([]string, 0, 2)
+	if task.BootTarget != "" && !bootStageReached(task.BootStage, task.BootTarget) {
+		waitingFor = append(waitingFor, fmt.Sprintf("boot progress %q", task.BootTarget))
+	}
+	if task.BootWaitReady && !ready {
+		waitingFor = append(waitingFor, "SMD state Ready")
+	}
+	lastStage := task.BootStage
+	if lastStage == "" {
+		lastStage = "unknown"
+	}
+	return fmt.Errorf(
+		"%w: %s not reached within %s, last stage %s",
+		ErrBootTimeout,
+		strings.Join(waitingFor, " and "),
+		timeout,
+		lastStage,
+	)
+}
+
+// bootStageOf returns the boot stage of one observation, preferring the
+// standard BootProgress and falling back to the OEM PostState.
+func bootStageOf(state SystemState) string {
+	progress := strings.TrimSpace(state.BootProgress)
+	if progress != "" && progress != "None" {
+		return progress
+	}
+	if stage := postStateStage(state.PostState); stage != "" {
+		return stage
+	}
+	return progress
+}
+
+// postStateStage maps an OEM PostState onto the equivalent BootProgress stage.
+func postStateStage(postState string) string {
+	switch strings.TrimSpace(postState) {
+	case "PowerOff", "Reset", "Unknown":
+		return "None"
+	case "InPost":
+		return "PrimaryProcessorInitializationStarted"
+	case "InPostDiscoveryComplete":
+		return "SystemHardwareInitializationComplete"
+	case "FinishedPost":
+		return "OSBootStarted"
+	default:
+		return ""
+	}
+}
+
+func bootStageReached(stage, target string) bool {
+	if target == "" {
+		return true
+	}
+	observed := bootStageRank(stage)
+	return observed >= 0 && observed >= bootStageRank(target)
+}
+
+func bootStageRank(stage string) int {
+	for i, candidate := range bootProgressOrder {
+		if candidate == strings.TrimSpace(stage) {
+			return i
+		}
+	}
+	return -1
+}
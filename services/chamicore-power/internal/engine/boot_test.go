@@ -0,0 +1,232 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+type mockReadinessTracker struct {
+	mu      sync.Mutex
+	marked  []string
+	readyAt int
+	reads   int
+}
+
+func (m *mockReadinessTracker) MarkNodeBooting(ctx context.Context, nodeID string) error {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.marked = append(m.marked, nodeID)
+	return nil
+}
+
+// ReadNodeState reports On until readyAt reads have happened, then Ready.
+func (m *mockReadinessTracker) ReadNodeState(ctx context.Context, nodeID string) (string, error) {
+	_ = ctx
+	_ = nodeID
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	if m.reads >= m.readyAt {
+		return BootStageReady, nil
+	}
+	return "On", nil
+}
+
+func newBootTestStore() *memoryStore {
+	return newMemoryStore([]model.NodePowerMapping{{
+		NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1",
+	}}, nil)
+}
+
+func fastBootWait(cfg *Config) {
+	cfg.BootWaitTimeout = 200 * time.Millisecond
+	cfg.BootWaitPoll = time.Millisecond
+}
+
+func TestRunner_BootWaitRecordsStagesUntilTarget(t *testing.T) {
+	reader := &sequenceStateReader{states: []SystemState{
+		{PowerState: "On", BootProgress: "None"},
+		{PowerState: "On", BootProgress: "PrimaryProcessorInitializationStarted"},
+		{PowerState: "On", BootProgress: "PrimaryProcessorInitializationStarted"},
+		{PowerState: "On", BootProgress: "OSBootStarted"},
+		{PowerState: "On", BootProgress: "OSRunning"},
+	}}
+	store := newBootTestStore()
+	runner := startTestRunner(t, store, withReader(reader), withConfig(fastBootWait))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1"},
+		BootWait:  &BootWait{Target: "osrunning"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	task := tasks[0]
+	assert.Equal(t, TaskStateSucceeded, task.State)
+	assert.Equal(t, "On", task.FinalPowerState)
+	assert.Equal(t, "OSRunning", task.BootTarget)
+	assert.Equal(t, "OSRunning", task.BootStage)
+
+	stages := make([]string, 0, len(task.BootStages))
+	for _, stage := range task.BootStages {
+		stages = append(stages, stage.Stage)
+		assert.False(t, stage.ObservedAt.IsZero())
+	}
+	assert.Equal(t, []string{"PrimaryProcessorInitializationStarted", "OSBootStarted", "OSRunning"}, stages)
+}
+
+func TestRunner_BootWaitTimesOut(t *testing.T) {
+	reader := &sequenceStateReader{states: []SystemState{
+		{PowerState: "On", BootProgress: "None"},
+		{PowerState: "On", BootProgress: "MemoryInitializationStarted"},
+	}}
+	store := newBootTestStore()
+	runner := startTestRunner(t, store, withReader(reader), withConfig(fastBootWait))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1"},
+		BootWait:  &BootWait{Target: "OSRunning"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateFailed, tasks[0].State)
+	assert.Contains(t, tasks[0].ErrorDetail, ErrBootTimeout.Error())
+	assert.Contains(t, tasks[0].ErrorDetail, "MemoryInitializationStarted")
+	assert.Equal(t, "MemoryInitializationStarted", tasks[0].BootStage)
+}
+
+func TestRunner_BootWaitForSMDReady(t *testing.T) {
+	reader := &sequenceStateReader{states: []SystemState{{PowerState: "On", BootProgress: "OSRunning"}}}
+	tracker := &mockReadinessTracker{readyAt: 3}
+	store := newBootTestStore()
+	runner := startTestRunner(t, store, withReader(reader), withConfig(fastBootWait), withRunnerOptions(WithNodeReadiness(tracker)))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1"},
+		BootWait:  &BootWait{WaitForReady: true},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateSucceeded, tasks[0].State)
+	assert.True(t, tasks[0].BootWaitReady)
+	require.Len(t, tasks[0].BootStages, 1)
+	assert.Equal(t, BootStageReady, tasks[0].BootStages[0].Stage)
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	assert.Equal(t, []string{"node-1"}, tracker.marked)
+	assert.Equal(t, 3, tracker.reads)
+}
+
+func TestRunner_BootPollingWaitsForBMCLimiter(t *testing.T) {
+	reader := &sequenceStateReader{states: []SystemState{{PowerState: "On", BootProgress: "OSRunning"}}}
+	runner := startTestRunner(t, newBootTestStore(), withReader(reader), withConfig(fastBootWait))
+
+	release, err := runner.acquireBMCLimiter(context.Background(), "bmc-1")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = runner.readBootState(ctx, ExecutionRequest{BMCID: "bmc-1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	release()
+
+	state, err := runner.readBootState(context.Background(), ExecutionRequest{BMCID: "bmc-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "OSRunning", state.BootProgress)
+}
+
+func TestRunner_WaitStopsBootTracking(t *testing.T) {
+	store := newBootTestStore()
+	reader := &sequenceStateReader{states: []SystemState{{PowerState: "On", BootProgress: "None"}}}
+	runCtx, cancel := context.WithCancel(context.Background())
+	runner := startTestRunner(t, store, withRunContext(runCtx), withReader(reader), withConfig(func(cfg *Config) {
+		cfg.BootWaitTimeout = time.Hour
+		cfg.BootWaitPoll = time.Millisecond
+	}))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1"},
+		BootWait:  &BootWait{Target: "OSRunning"},
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		tasks := store.tasksForTransition(transition.ID)
+		return len(tasks) == 1 && tasks[0].BootStage == "None"
+	}, 2*time.Second, time.Millisecond, "boot tracking did not start")
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		runner.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not return after the runner context was canceled")
+	}
+}
+
+func TestRunner_BootWaitValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		wait      *BootWait
+		reader    PowerStateReader
+	}{
+		{name: "operation does not boot", operation: "ForceOff", wait: &BootWait{Target: "OSRunning"}},
+		{name: "unknown target", operation: "On", wait: &BootWait{Target: "Booted"}},
+		{name: "no boot progress reader", operation: "On", wait: &BootWait{Target: "OSRunning"}, reader: &mockReader{}},
+		{name: "no readiness tracker", operation: "ForceRestart", wait: &BootWait{WaitForReady: true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := tc.reader
+			if reader == nil {
+				reader = &sequenceStateReader{states: []SystemState{{PowerState: "On"}}}
+			}
+			runner := startTestRunner(t, newBootTestStore(), withReader(reader), withConfig(fastBootWait))
+
+			_, err := runner.StartTransition(context.Background(), StartRequest{
+				Operation: tc.operation,
+				NodeIDs:   []string{"node-1"},
+				BootWait:  tc.wait,
+			})
+			require.ErrorIs(t, err, ErrInvalidBootWait)
+		})
+	}
+}
+
+func TestBootStageOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "OSBootStarted", bootStageOf(SystemState{BootProgress: "OSBootStarted", PostState: "InPost"}))
+	assert.Equal(t, "SystemHardwareInitializationComplete", bootStageOf(SystemState{BootProgress: "None", PostState: "InPostDiscoveryComplete"}))
+	assert.Equal(t, "OSBootStarted", bootStageOf(SystemState{PostState: "FinishedPost"}))
+	assert.Equal(t, "", bootStageOf(SystemState{}))
+
+	assert.True(t, bootStageReached("OSRunning", "OSBootStarted"))
+	assert.False(t, bootStageReached("SetupEntered", "OSBootStarted"))
+	assert.False(t, bootStageReached("OSBootStarted", "OSRunning"))
+}
@@ -0,0 +1,329 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+// Boot-control transition operations.
+const (
+	// OperationSetBootOverride sets the Redfish boot source override.
+	OperationSetBootOverride = "SetBootOverride"
+	// OperationInsertMedia inserts a virtual-media image.
+	OperationInsertMedia = "InsertMedia"
+	// OperationEjectMedia ejects the inserted virtual-media image.
+	OperationEjectMedia = "EjectMedia"
+	// OperationBootOverrideReset optionally inserts media, sets the boot
+	// override, then resets the node, so a rescue boot is one transition.
+	OperationBootOverrideReset = "BootOverrideReset"
+)
+
+// Boot override modes map to Redfish Boot.BootSourceOverrideEnabled.
+const (
+	BootOverrideOnce       = "Once"
+	BootOverrideContinuous = "Continuous"
+)
+
+// BootOverrideTargets lists the supported Boot.BootSourceOverrideTarget values.
+var BootOverrideTargets = []string{"Pxe", "Hdd", "Cd", "BiosSetup"}
+
+// NonPowerOperations lists transition operations that leave node power state
+// unchanged, so they neither update SMD nor count toward power status.
+var NonPowerOperations = []string{
+	OperationPowerCap,
+	OperationSetBootOverride,
+	OperationInsertMedia,
+	OperationEjectMedia,
+}
+
+var (
+	// ErrInvalidBootOverride indicates a boot override request cannot be honored.
+	ErrInvalidBootOverride = errors.New("invalid boot override")
+	// ErrInvalidVirtualMedia indicates a virtual-media request cannot be honored.
+	ErrInvalidVirtualMedia = errors.New("invalid virtual media")
+	// ErrBootControlUnsupported indicates boot control is not configured or the
+	// BMC exposes no boot override or virtual media.
+	ErrBootControlUnsupported = errors.New("boot control is not supported")
+	// ErrBootOverrideNotApplied indicates the read-back override never matched the request.
+	ErrBootOverrideNotApplied = errors.New("boot override not applied")
+	// ErrMediaNotApplied indicates the read-back virtual media never matched the request.
+	ErrMediaNotApplied = errors.New("virtual media not applied")
+)
+
+// BootOverride is a Redfish boot source override.
+type BootOverride struct {
+	// Target is one of BootOverrideTargets.
+	Target string
+	// Mode is BootOverrideOnce or BootOverrideContinuous; empty means once.
+	Mode string
+}
+
+// VirtualMedia describes an image to insert into a node's virtual drive.
+type VirtualMedia struct {
+	// Image is the URL the BMC fetches the image from.
+	Image string
+}
+
+// MediaState is the observed state of a node's virtual drive.
+type MediaState struct {
+	Image    string
+	Inserted bool
+}
+
+// BootController sets boot overrides and manages virtual media.
+type BootController interface {
+	SetBootOverride(ctx context.Context, req ExecutionRequest, override BootOverride) error
+	ReadBootOverride(ctx context.Context, req ExecutionRequest) (BootOverride, error)
+	InsertMedia(ctx context.Context, req ExecutionRequest, media VirtualMedia) error
+	EjectMedia(ctx context.Context, req ExecutionRequest) error
+	ReadMedia(ctx context.Context, req ExecutionRequest) (MediaState, error)
+}
+
+// WithBootController enables boot-override and virtual-media transitions.
+func WithBootController(controller BootController) Option {
+	return func(r *Runner) {
+		r.booter = controller
+	}
+}
+
+func isBootControlOperation(operation string) bool {
+	_, ok := canonicalBootControlOperation(operation)
+	return ok
+}
+
+func canonicalBootControlOperation(operation string) (string, bool) {
+	operation = strings.TrimSpace(operation)
+	for _, known := range []string{
+		OperationSetBootOverride,
+		OperationInsertMedia,
+		OperationEjectMedia,
+		OperationBootOverrideReset,
+	} {
+		if strings.EqualFold(known, operation) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// changesPowerState reports whether a task operation acts on node power.
+func changesPowerState(operation string) bool {
+	for _, candidate := range NonPowerOperations {
+		if operation == candidate {
+			return false
+		}
+	}
+	return true
+}
+
+// planBootControl validates a boot-control request. Only the combined
+// BootOverrideReset operation issues a reset and may wait for the boot.
+func (r *Runner) planBootControl(req StartRequest) (operationPlan, error) {
+	name, _ := canonicalBootControlOperation(req.Operation)
+	if r.booter == nil {
+		return operationPlan{}, ErrBootControlUnsupported
+	}
+
+	plan := operationPlan{name: name}
+	switch name {
+	case OperationSetBootOverride, OperationBootOverrideReset:
+		override, err := normalizeBootOverride(req.BootOverride)
+		if err != nil {
+			return operationPlan{}, err
+		}
+		plan.template.BootOverrideTarget = override.Target
+		plan.template.BootOverrideMode = override.Mode
+	}
+	switch name {
+	case OperationInsertMedia, OperationBootOverrideReset:
+		image, err := normalizeMediaImage(req.VirtualMedia, name == OperationInsertMedia)
+		if err != nil {
+			return operationPlan{}, err
+		}
+		plan.template.MediaImage = image
+	}
+
+	if name != OperationBootOverrideReset {
+		if req.BootWait != nil && (strings.TrimSpace(req.BootWait.Target) != "" || req.BootWait.WaitForReady) {
+			return operationPlan{}, fmt.Errorf("%w: operation %q does not boot the node", ErrInvalidBootWait, name)
+		}
+		return plan, nil
+	}
+
+	reset := redfish.ResetOperationForceRestart
+	if raw := strings.TrimSpace(req.ResetOperation); raw != "" {
+		parsed, err := redfish.ParseResetOperation(raw)
+		if err != nil {
+			return operationPlan{}, fmt.Errorf("%w: invalid reset operation %q", ErrInvalidBootOverride, raw)
+		}
+		reset = parsed
+	}
+	switch reset {
+	case redfish.ResetOperationOn, redfish.ResetOperationGracefulRestart, redfish.ResetOperationForceRestart:
+	default:
+		return operationPlan{}, fmt.Errorf("%w: reset operation %q does not boot the node", ErrInvalidBootOverride, reset)
+	}
+	plan.reset = reset
+	plan.template.ResetOperation = string(reset)
+
+	if err := r.applyBootWait(&plan, req.BootWait); err != nil {
+		return operationPlan{}, err
+	}
+	return plan, nil
+}
+
+func normalizeBootOverride(override *BootOverride) (BootOverride, error) {
+	if override == nil || strings.TrimSpace(override.Target) == "" {
+		return BootOverride{}, fmt.Errorf("%w: bootOverride.target is required", ErrInvalidBootOverride)
+	}
+
+	target := ""
+	for _, candidate := range BootOverrideTargets {
+		if strings.EqualFold(candidate, strings.TrimSpace(override.Target)) {
+			target = candidate
+			break
+		}
+	}
+	if target == "" {
+		return BootOverride{}, fmt.Errorf(
+			"%w: unknown target %q, expected one of %s",
+			ErrInvalidBootOverride,
+			strings.TrimSpace(override.Target),
+			strings.Join(BootOverrideTargets, ", "),
+		)
+	}
+
+	mode := BootOverrideOnce
+	switch {
+	case strings.TrimSpace(override.Mode) == "", strings.EqualFold(override.Mode, BootOverrideOnce):
+	case strings.EqualFold(override.Mode, BootOverrideContinuous):
+		mode = BootOverrideContinuous
+	default:
+		return BootOverride{}, fmt.Errorf(
+			"%w: unknown mode %q, expected %s or %s",
+			ErrInvalidBootOverride,
+			strings.TrimSpace(override.Mode),
+			BootOverrideOnce,
+			BootOverrideContinuous,
+		)
+	}
+
+	return BootOverride{Target: target, Mode: mode}, nil
+}
+
+func normalizeMediaImage(media *VirtualMedia, required bool) (string, error) {
+	image := ""
+	if media != nil {
+		image = strings.TrimSpace(media.Image)
+	}
+	if image == "" {
+		if required {
+			return "", fmt.Errorf("%w: virtualMedia.image is required", ErrInvalidVirtualMedia)
+		}
+		return "", nil
+	}
+
+	parsed, err := url.Parse(image)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("%w: image %q must be an absolute URL", ErrInvalidVirtualMedia, image)
+	}
+	return image, nil
+}
+
+// executeBootControl runs a setting-only boot-control task.
+func (r *Runner) executeBootControl(ctx, execCtx context.Context, transitionID string, task Task, req ExecutionRequest) {
+	attempts, err := r.applyBootSettings(execCtx, task, req)
+	r.completeTask(ctx, transitionID, task, attempts, "", err)
+}
+
+// applyBootSettings ejects or inserts virtual media and sets the boot override
+// a task asks for, verifying each step by reading it back. It returns the
+// number of BMC attempts made across all steps.
+func (r *Runner) applyBootSettings(ctx context.Context, task Task, req ExecutionRequest) (int, error) {
+	total := 0
+
+	if task.Operation == OperationEjectMedia {
+		attempts, err := r.withRetry(ctx, func(ctx context.Context) error {
+			return r.booter.EjectMedia(ctx, req)
+		})
+		total += attempts
+		if err != nil {
+			return total, err
+		}
+		return total, r.verifier.VerifyMedia(ctx, r.booter, req, MediaState{})
+	}
+
+	if task.MediaImage != "" {
+		media := VirtualMedia{Image: task.MediaImage}
+		attempts, err := r.withRetry(ctx, func(ctx context.Context) error {
+			return r.booter.InsertMedia(ctx, req, media)
+		})
+		total += attempts
+		if err != nil {
+			return total, err
+		}
+		if err := r.verifier.VerifyMedia(ctx, r.booter, req, MediaState{Image: media.Image, Inserted: true}); err != nil {
+			return total, err
+		}
+	}
+
+	if task.BootOverrideTarget != "" {
+		override := BootOverride{Target: task.BootOverrideTarget, Mode: task.BootOverrideMode}
+		attempts, err := r.withRetry(ctx, func(ctx context.Context) error {
+			return r.booter.SetBootOverride(ctx, req, override)
+		})
+		total += attempts
+		if err != nil {
+			return total, err
+		}
+		if err := r.verifier.VerifyBootOverride(ctx, r.booter, req, override); err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// VerifyBootOverride polls the node boot override until it matches want.
+func (v *Verifier) VerifyBootOverride(ctx context.Context, booter BootController, req ExecutionRequest, want BootOverride) error {
+	last, err := v.pollReadBack(ctx, func(ctx context.Context) (bool, string, error) {
+		got, err := booter.ReadBootOverride(ctx, req)
+		if err != nil {
+			return false, "", fmt.Errorf("reading boot override: %w", err)
+		}
+		observed := fmt.Sprintf("%s (%s)", got.Target, got.Mode)
+		return strings.EqualFold(got.Target, want.Target) && strings.EqualFold(got.Mode, want.Mode), observed, nil
+	})
+	if errors.Is(err, errReadBackMismatch) {
+		return fmt.Errorf("%w: expected %s (%s), last %s", ErrBootOverrideNotApplied, want.Target, want.Mode, last)
+	}
+	return err
+}
+
+// VerifyMedia polls the node virtual drive until it holds want.Image, or until
+// nothing is inserted when want.Inserted is false.
+func (v *Verifier) VerifyMedia(ctx context.Context, booter BootController, req ExecutionRequest, want MediaState) error {
+	last, err := v.pollReadBack(ctx, func(ctx context.Context) (bool, string, error) {
+		got, err := booter.ReadMedia(ctx, req)
+		if err != nil {
+			return false, "", fmt.Errorf("reading virtual media: %w", err)
+		}
+		if !got.Inserted {
+			return !want.Inserted, "ejected", nil
+		}
+		return want.Inserted && strings.TrimSpace(got.Image) == want.Image, "inserted " + strings.TrimSpace(got.Image), nil
+	})
+	if errors.Is(err, errReadBackMismatch) {
+		expected := "ejected"
+		if want.Inserted {
+			expected = "inserted " + want.Image
+		}
+		return fmt.Errorf("%w: expected %s, last %s", ErrMediaNotApplied, expected, last)
+	}
+	return err
+}
@@ -0,0 +1,309 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBootController struct {
+	mu        sync.Mutex
+	overrides map[string]BootOverride
+	media     map[string]MediaState
+	calls     []string
+	// ignore drops applied settings, so read-back never matches.
+	ignore bool
+}
+
+func (m *mockBootController) record(call string) {
+	m.calls = append(m.calls, call)
+}
+
+func (m *mockBootController) SetBootOverride(ctx context.Context, req ExecutionRequest, override BootOverride) error {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(req.NodeID + ":override:" + override.Target + ":" + override.Mode)
+	if m.ignore {
+		return nil
+	}
+	if m.overrides == nil {
+		m.overrides = make(map[string]BootOverride)
+	}
+	m.overrides[req.NodeID] = override
+	return nil
+}
+
+func (m *mockBootController) ReadBootOverride(ctx context.Context, req ExecutionRequest) (BootOverride, error) {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if override, ok := m.overrides[req.NodeID]; ok {
+		return override, nil
+	}
+	return BootOverride{Target: "None", Mode: "Disabled"}, nil
+}
+
+func (m *mockBootController) InsertMedia(ctx context.Context, req ExecutionRequest, media VirtualMedia) error {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(req.NodeID + ":insert:" + media.Image)
+	if m.ignore {
+		return nil
+	}
+	if m.media == nil {
+		m.media = make(map[string]MediaState)
+	}
+	m.media[req.NodeID] = MediaState{Image: media.Image, Inserted: true}
+	return nil
+}
+
+func (m *mockBootController) EjectMedia(ctx context.Context, req ExecutionRequest) error {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(req.NodeID + ":eject")
+	if m.ignore {
+		return nil
+	}
+	delete(m.media, req.NodeID)
+	return nil
+}
+
+func (m *mockBootController) ReadMedia(ctx context.Context, req ExecutionRequest) (MediaState, error) {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.media[req.NodeID], nil
+}
+
+func (m *mockBootController) callLog() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.calls...)
+}
+
+func TestRunner_SetBootOverrideVerifiesReadBack(t *testing.T) {
+	booter := &mockBootController{}
+	updater := &recordingStateUpdater{}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithBootController(booter), WithNodeStateUpdater(updater)))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:    "setbootoverride",
+		NodeIDs:      []string{"node-1", "node-2"},
+		BootOverride: &BootOverride{Target: "pxe"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, OperationSetBootOverride, transition.Operation)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, TaskStateSucceeded, task.State)
+		assert.Equal(t, "Pxe", task.BootOverrideTarget)
+		assert.Equal(t, BootOverrideOnce, task.BootOverrideMode)
+		assert.Empty(t, task.ResetOperation)
+	}
+	assert.ElementsMatch(t, []string{"node-1:override:Pxe:Once", "node-2:override:Pxe:Once"}, booter.callLog())
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+	assert.Empty(t, updater.transitioning)
+}
+
+func TestRunner_InsertAndEjectMedia(t *testing.T) {
+	booter := &mockBootController{}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithBootController(booter)))
+
+	inserted, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:    OperationInsertMedia,
+		NodeIDs:      []string{"node-1"},
+		VirtualMedia: &VirtualMedia{Image: "http://images.example/rescue.iso"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(inserted.ID, 2*time.Second))
+	tasks := store.tasksForTransition(inserted.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateSucceeded, tasks[0].State)
+	assert.Equal(t, "http://images.example/rescue.iso", tasks[0].MediaImage)
+
+	ejected, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: OperationEjectMedia,
+		NodeIDs:   []string{"node-1"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(ejected.ID, 2*time.Second))
+	tasks = store.tasksForTransition(ejected.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateSucceeded, tasks[0].State)
+
+	assert.Equal(t, []string{"node-1:insert:http://images.example/rescue.iso", "node-1:eject"}, booter.callLog())
+}
+
+func TestRunner_BootOverrideResetAppliesSettingsThenResets(t *testing.T) {
+	booter := &mockBootController{}
+	updater := &recordingStateUpdater{}
+
+	var (
+		mu     sync.Mutex
+		resets []string
+	)
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		resets = append(resets, req.NodeID+":"+string(req.Operation))
+		return nil
+	}}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withExecutor(exec), withRunnerOptions(WithBootController(booter), WithNodeStateUpdater(updater)))
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:      OperationBootOverrideReset,
+		NodeIDs:        []string{"node-1"},
+		BootOverride:   &BootOverride{Target: "Cd", Mode: "continuous"},
+		VirtualMedia:   &VirtualMedia{Image: "http://images.example/rescue.iso"},
+		ResetOperation: "on",
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateSucceeded, tasks[0].State)
+	assert.Equal(t, "On", tasks[0].FinalPowerState)
+	assert.Equal(t, "On", tasks[0].ResetOperation)
+	assert.Equal(t, "Cd", tasks[0].BootOverrideTarget)
+	assert.Equal(t, BootOverrideContinuous, tasks[0].BootOverrideMode)
+	assert.Equal(t, 3, tasks[0].AttemptCount)
+
+	assert.Equal(t, []string{
+		"node-1:insert:http://images.example/rescue.iso",
+		"node-1:override:Cd:Continuous",
+	}, booter.callLog())
+
+	mu.Lock()
+	assert.Equal(t, []string{"node-1:On"}, resets)
+	mu.Unlock()
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+	assert.Equal(t, []string{"node-1:On"}, updater.transitioning)
+}
+
+func TestRunner_BootOverrideNotAppliedSkipsReset(t *testing.T) {
+	booter := &mockBootController{ignore: true}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithBootController(booter)))
+
+	var resets int
+	runner.executor = &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		resets++
+		return nil
+	}}
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:    OperationBootOverrideReset,
+		NodeIDs:      []string{"node-1"},
+		BootOverride: &BootOverride{Target: "Pxe"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateFailed, tasks[0].State)
+	assert.Contains(t, tasks[0].ErrorDetail, ErrBootOverrideNotApplied.Error())
+	assert.Contains(t, tasks[0].ErrorDetail, "expected Pxe (Once), last None (Disabled)")
+	assert.Zero(t, resets)
+}
+
+func TestRunner_BootControlValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		booter  BootController
+		req     StartRequest
+		wantErr error
+	}{
+		{
+			name:    "not configured",
+			req:     StartRequest{Operation: OperationSetBootOverride, NodeIDs: []string{"node-1"}, BootOverride: &BootOverride{Target: "Pxe"}},
+			wantErr: ErrBootControlUnsupported,
+		},
+		{
+			name:    "missing target",
+			booter:  &mockBootController{},
+			req:     StartRequest{Operation: OperationSetBootOverride, NodeIDs: []string{"node-1"}},
+			wantErr: ErrInvalidBootOverride,
+		},
+		{
+			name:    "unknown target",
+			booter:  &mockBootController{},
+			req:     StartRequest{Operation: OperationSetBootOverride, NodeIDs: []string{"node-1"}, BootOverride: &BootOverride{Target: "Floppy"}},
+			wantErr: ErrInvalidBootOverride,
+		},
+		{
+			name:   "unknown mode",
+			booter: &mockBootController{},
+			req: StartRequest{
+				Operation:    OperationSetBootOverride,
+				NodeIDs:      []string{"node-1"},
+				BootOverride: &BootOverride{Target: "Pxe", Mode: "Forever"},
+			},
+			wantErr: ErrInvalidBootOverride,
+		},
+		{
+			name:    "missing image",
+			booter:  &mockBootController{},
+			req:     StartRequest{Operation: OperationInsertMedia, NodeIDs: []string{"node-1"}},
+			wantErr: ErrInvalidVirtualMedia,
+		},
+		{
+			name:    "relative image",
+			booter:  &mockBootController{},
+			req:     StartRequest{Operation: OperationInsertMedia, NodeIDs: []string{"node-1"}, VirtualMedia: &VirtualMedia{Image: "rescue.iso"}},
+			wantErr: ErrInvalidVirtualMedia,
+		},
+		{
+			name:   "boot wait without reset",
+			booter: &mockBootController{},
+			req: StartRequest{
+				Operation:    OperationSetBootOverride,
+				NodeIDs:      []string{"node-1"},
+				BootOverride: &BootOverride{Target: "Pxe"},
+				BootWait:     &BootWait{Target: "OSRunning"},
+			},
+			wantErr: ErrInvalidBootWait,
+		},
+		{
+			name:   "reset that does not boot",
+			booter: &mockBootController{},
+			req: StartRequest{
+				Operation:      OperationBootOverrideReset,
+				NodeIDs:        []string{"node-1"},
+				BootOverride:   &BootOverride{Target: "Pxe"},
+				ResetOperation: "ForceOff",
+			},
+			wantErr: ErrInvalidBootOverride,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []Option
+			if tc.booter != nil {
+				opts = append(opts, WithBootController(tc.booter))
+			}
+			runner := startTestRunner(t, newSharedBMCTestStore(), withRunnerOptions(opts...))
+			_, err := runner.StartTransition(context.Background(), tc.req)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
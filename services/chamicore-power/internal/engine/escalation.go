@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+var (
+	// ErrInvalidEscalation indicates an escalation policy is malformed or set on
+	// an operation that cannot escalate.
+	ErrInvalidEscalation = errors.New("invalid escalation")
+	// ErrInvalidBatchSize indicates a negative transition batch size.
+	ErrInvalidBatchSize = errors.New("invalid batch size")
+	// ErrInvalidChunkSize indicates a negative transition chunk size.
+	ErrInvalidChunkSize = errors.New("invalid chunk size")
+)
+
+// applyPolicy validates the batching, chunking and escalation settings of a
+// request.
+func applyPolicy(plan *operationPlan, req StartRequest) error {
+	if req.BatchSize < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", ErrInvalidBatchSize, req.BatchSize)
+	}
+	plan.batchSize = req.BatchSize
+	if req.ChunkSize < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", ErrInvalidChunkSize, req.ChunkSize)
+	}
+	plan.chunkSize = req.ChunkSize
+
+	if req.Escalation == nil {
+		return nil
+	}
+	if req.Escalation.After <= 0 {
+		return fmt.Errorf("%w: after must be positive", ErrInvalidEscalation)
+	}
+	if _, ok := escalationOperation(plan.reset); !ok {
+		return fmt.Errorf("%w: only GracefulShutdown and GracefulRestart escalate, got %s", ErrInvalidEscalation, plan.name)
+	}
+	plan.escalateAfter = req.Escalation.After
+	return nil
+}
+
+// escalationOperation returns the forced counterpart of a graceful reset.
+func escalationOperation(operation redfish.ResetOperation) (redfish.ResetOperation, bool) {
+	switch operation {
+	case redfish.ResetOperationGracefulShutdown:
+		return redfish.ResetOperationForceOff, true
+	case redfish.ResetOperationGracefulRestart:
+		return redfish.ResetOperationForceRestart, true
+	default:
+		return "", false
+	}
+}
+
+// verifyWithEscalation verifies a graceful reset for the escalation window and,
+// when the node has not reached the expected state by then, issues the forced
+// reset and verifies that instead. It returns the attempts spent escalating.
+func (r *Runner) verifyWithEscalation(
+	ctx context.Context,
+	task *Task,
+	req ExecutionRequest,
+	baseline SystemState,
+	after time.Duration,
+) (int, string, error) {
+	finalPowerState, err := r.verifier.VerifyWithin(ctx, req, baseline, after)
+	if err == nil || ctx.Err() != nil || !escalates(err) {
+		return 0, finalPowerState, err
+	}
+
+	forced, _ := escalationOperation(req.Operation)
+	req.Operation = forced
+	task.Escalated = true
+	attempts, execErr := r.executeWithRetry(ctx, req)
+	if execErr != nil {
+		return attempts, finalPowerState, fmt.Errorf("escalating to %s: %w", forced, execErr)
+	}
+	finalPowerState, err = r.verifier.Verify(ctx, req, baseline)
+	if err != nil {
+		return attempts, finalPowerState, fmt.Errorf("escalated to %s: %w", forced, err)
+	}
+	return attempts, finalPowerState, nil
+}
+
+// escalates reports whether a verification failure means the node ignored the
+// graceful request, as opposed to the BMC being unreadable.
+func escalates(err error) bool {
+	return errors.Is(err, ErrVerificationTimeout) ||
+		errors.Is(err, ErrRestartNotObserved) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
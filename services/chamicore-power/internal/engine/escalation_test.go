@@ -0,0 +1,236 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+func TestRunner_BatchSizeLimitsInFlightTasks(t *testing.T) {
+	mappings := make([]model.NodePowerMapping, 0, 5)
+	nodeIDs := make([]string, 0, 5)
+	for _, suffix := range []string{"1", "2", "3", "4", "5"} {
+		mappings = append(mappings, model.NodePowerMapping{
+			NodeID:       "node-" + suffix,
+			BMCID:        "bmc-" + suffix,
+			Endpoint:     "https://bmc-" + suffix,
+			CredentialID: "cred-" + suffix,
+		})
+		nodeIDs = append(nodeIDs, "node-"+suffix)
+	}
+	store := newMemoryStore(mappings, nil)
+
+	exec := newConcurrencyExecutor(25 * time.Millisecond)
+	runner := New(store, exec, &mockReader{}, Config{
+		GlobalConcurrency:  5,
+		PerBMCConcurrency:  1,
+		RetryAttempts:      1,
+		VerificationWindow: 200 * time.Millisecond,
+		VerificationPoll:   5 * time.Millisecond,
+		TransitionDeadline: 500 * time.Millisecond,
+	})
+	runner.cfg.jitter = func(time.Duration) time.Duration { return 0 }
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   nodeIDs,
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, transition.BatchSize)
+
+	require.True(t, store.waitForTerminal(transition.ID, 3*time.Second))
+	finalTransition := store.transition(transition.ID)
+	assert.Equal(t, TransitionStateCompleted, finalTransition.State)
+	assert.Equal(t, 5, finalTransition.SuccessCount)
+	assert.LessOrEqual(t, exec.maxGlobal(), 2)
+}
+
+func TestRunner_EscalatesIgnoredGracefulShutdown(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-polite", BMCID: "bmc-a", Endpoint: "https://bmc-a", CredentialID: "cred-a"},
+		{NodeID: "node-stubborn", BMCID: "bmc-b", Endpoint: "https://bmc-b", CredentialID: "cred-b"},
+	}, nil)
+
+	var (
+		mu         sync.Mutex
+		issued     = map[string][]redfish.ResetOperation{}
+		poweredOff = map[string]bool{}
+	)
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		issued[req.NodeID] = append(issued[req.NodeID], req.Operation)
+		if req.NodeID == "node-polite" || req.Operation == redfish.ResetOperationForceOff {
+			poweredOff[req.NodeID] = true
+		}
+		return nil
+	}}
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if poweredOff[req.NodeID] {
+			return "Off", nil
+		}
+		return "On", nil
+	}}
+
+	runner := New(store, exec, reader, Config{
+		GlobalConcurrency:  2,
+		PerBMCConcurrency:  1,
+		RetryAttempts:      1,
+		VerificationWindow: 200 * time.Millisecond,
+		VerificationPoll:   5 * time.Millisecond,
+		TransitionDeadline: 500 * time.Millisecond,
+	})
+	runner.cfg.jitter = func(time.Duration) time.Duration { return 0 }
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:  "GracefulShutdown",
+		NodeIDs:    []string{"node-polite", "node-stubborn"},
+		Escalation: &Escalation{After: 40 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Millisecond, transition.EscalateAfter)
+
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+	assert.Equal(t, TransitionStateCompleted, store.transition(transition.ID).State)
+
+	byNode := make(map[string]Task)
+	for _, task := range store.tasksForTransition(transition.ID) {
+		byNode[task.NodeID] = task
+	}
+	assert.False(t, byNode["node-polite"].Escalated)
+	assert.True(t, byNode["node-stubborn"].Escalated)
+	assert.Equal(t, "Off", byNode["node-stubborn"].FinalPowerState)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []redfish.ResetOperation{redfish.ResetOperationGracefulShutdown}, issued["node-polite"])
+	assert.Equal(t, []redfish.ResetOperation{
+		redfish.ResetOperationGracefulShutdown,
+		redfish.ResetOperationForceOff,
+	}, issued["node-stubborn"])
+}
+
+func TestRunner_RejectsInvalidPolicy(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-a", Endpoint: "https://bmc-a", CredentialID: "cred-a"},
+	}, nil)
+	runner := New(store, &mockExecutor{}, &mockReader{}, Config{})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	_, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1"},
+		BatchSize: -1,
+	})
+	require.ErrorIs(t, err, ErrInvalidBatchSize)
+
+	_, err = runner.StartTransition(context.Background(), StartRequest{
+		Operation:  "On",
+		NodeIDs:    []string{"node-1"},
+		Escalation: &Escalation{After: time.Minute},
+	})
+	require.ErrorIs(t, err, ErrInvalidEscalation)
+
+	_, err = runner.StartTransition(context.Background(), StartRequest{
+		Operation:  "GracefulRestart",
+		NodeIDs:    []string{"node-1"},
+		Escalation: &Escalation{},
+	})
+	require.ErrorIs(t, err, ErrInvalidEscalation)
+}
+
+func TestRunner_ChunkWaitsForPreviousChunk(t *testing.T) {
+	mappings := make([]model.NodePowerMapping, 0, 5)
+	nodeIDs := make([]string, 0, 5)
+	for _, suffix := range []string{"1", "2", "3", "4", "5"} {
+		mappings = append(mappings, model.NodePowerMapping{
+			NodeID:       "node-" + suffix,
+			BMCID:        "bmc-" + suffix,
+			Endpoint:     "https://bmc-" + suffix,
+			CredentialID: "cred-" + suffix,
+		})
+		nodeIDs = append(nodeIDs, "node-"+suffix)
+	}
+	store := newMemoryStore(mappings, nil)
+
+	var (
+		mu       sync.Mutex
+		started  = map[string]time.Time{}
+		finished = map[string]time.Time{}
+	)
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		mu.Lock()
+		started[req.NodeID] = time.Now()
+		mu.Unlock()
+		if req.NodeID == "node-1" {
+			time.Sleep(80 * time.Millisecond)
+		}
+		mu.Lock()
+		finished[req.NodeID] = time.Now()
+		mu.Unlock()
+		return nil
+	}}
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		return "On", nil
+	}}
+
+	runner := New(store, exec, reader, Config{
+		GlobalConcurrency:  5,
+		RetryAttempts:      1,
+		VerificationWindow: 200 * time.Millisecond,
+		VerificationPoll:   5 * time.Millisecond,
+	})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   nodeIDs,
+		ChunkSize: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, transition.ChunkSize)
+
+	require.True(t, store.waitForTerminal(transition.ID, 3*time.Second))
+	finalTransition := store.transition(transition.ID)
+	assert.Equal(t, TransitionStateCompleted, finalTransition.State)
+	assert.Equal(t, 5, finalTransition.SuccessCount)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// node-3 and node-4 form the second chunk and wait for the slow node-1.
+	assert.True(t, started["node-3"].After(finished["node-1"]))
+	assert.True(t, started["node-4"].After(finished["node-1"]))
+	assert.True(t, started["node-5"].After(finished["node-3"]))
+	assert.True(t, started["node-5"].After(finished["node-4"]))
+
+	_, err = runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   nodeIDs,
+		ChunkSize: -1,
+	})
+	require.ErrorIs(t, err, ErrInvalidChunkSize)
+}
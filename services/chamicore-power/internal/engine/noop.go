@@ -1,6 +1,9 @@
 package engine
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // NoopExecutor is a placeholder action executor used until the concrete
 // Redfish/auth-backed execution path is wired.
@@ -22,3 +25,22 @@ func (ExpectedStateReader) ReadPowerState(ctx context.Context, req ExecutionRequ
 	_ = ctx
 	return expectedFinalPowerState(req.Operation)
 }
+
+// ReadSystemState returns the expected end-state and reports a fresh reset
+// time on every read, so restart operations verify as cycled. Nodes that end
+// up on report a running OS.
+func (ExpectedStateReader) ReadSystemState(ctx context.Context, req ExecutionRequest) (SystemState, error) {
+	_ = ctx
+	powerState, err := expectedFinalPowerState(req.Operation)
+	if err != nil {
+		return SystemState{}, err
+	}
+	state := SystemState{
+		PowerState:    powerState,
+		LastResetTime: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if powerState == "On" {
+		state.BootProgress = "OSRunning"
+	}
+	return state, nil
+}
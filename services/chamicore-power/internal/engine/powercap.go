@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OperationPowerCap is the transition operation that applies node power limits.
+const OperationPowerCap = "PowerCap"
+
+var (
+	// ErrInvalidPowerCap indicates a power-cap request cannot be honored.
+	ErrInvalidPowerCap = errors.New("invalid power cap")
+	// ErrPowerCapUnsupported indicates power capping is not configured or the BMC
+	// exposes neither Power nor EnvironmentMetrics limits.
+	ErrPowerCapUnsupported = errors.New("power capping is not supported")
+	// ErrPowerCapNotApplied indicates the read-back limit never matched the request.
+	ErrPowerCapNotApplied = errors.New("power cap not applied")
+)
+
+// PowerReading is one node's power limit and draw as reported by its BMC.
+type PowerReading struct {
+	// LimitWatts is the active power limit; nil when no limit is set.
+	LimitWatts *int
+	// ConsumedWatts is the current power draw when the BMC reports one.
+	ConsumedWatts *float64
+	// MinLimitWatts and MaxLimitWatts bound the limits the BMC accepts.
+	MinLimitWatts *int
+	MaxLimitWatts *int
+	// Source names the Redfish resource that was read.
+	Source string
+}
+
+// PowerCapper applies and reads node power limits.
+type PowerCapper interface {
+	// ApplyPowerCap sets a node's power limit; zero removes the limit.
+	ApplyPowerCap(ctx context.Context, req ExecutionRequest, limitWatts int) error
+	ReadPowerCap(ctx context.Context, req ExecutionRequest) (PowerReading, error)
+}
+
+// NodePowerReading is the power reading, or the reason there is none, for one node.
+type NodePowerReading struct {
+	NodeID      string
+	BMCID       string
+	Reading     PowerReading
+	ErrorDetail string
+}
+
+// WithPowerCapper enables PowerCap transitions and power readings.
+func WithPowerCapper(capper PowerCapper) Option {
+	return func(r *Runner) {
+		r.capper = capper
+	}
+}
+
+func isPowerCapOperation(operation string) bool {
+	return strings.EqualFold(strings.TrimSpace(operation), OperationPowerCap)
+}
+
+func (r *Runner) planPowerCap(req StartRequest) (operationPlan, error) {
+	if r.capper == nil {
+		return operationPlan{}, ErrPowerCapUnsupported
+	}
+	if req.PowerCapWatts == nil {
+		return operationPlan{}, fmt.Errorf("%w: limitWatts is required", ErrInvalidPowerCap)
+	}
+	if *req.PowerCapWatts < 0 {
+		return operationPlan{}, fmt.Errorf("%w: limitWatts must not be negative", ErrInvalidPowerCap)
+	}
+	if req.BootWait != nil {
+		return operationPlan{}, fmt.Errorf("%w: bootWait is not supported for power caps", ErrInvalidPowerCap)
+	}
+
+	limit := *req.PowerCapWatts
+	return operationPlan{name: OperationPowerCap, template: Task{PowerCapWatts: &limit}}, nil
+}
+
+// executePowerCap applies one node's power limit with the runner retry policy
+// and verifies it by reading the limit back.
+func (r *Runner) executePowerCap(ctx, execCtx context.Context, transitionID string, task Task, req ExecutionRequest) {
+	if task.PowerCapWatts == nil {
+		r.completeTask(ctx, transitionID, task, 0, "", fmt.Errorf("%w: task has no limit", ErrInvalidPowerCap))
+		return
+	}
+	limit := *task.PowerCapWatts
+
+	attempts, err := r.withRetry(execCtx, func(ctx context.Context) error {
+		return r.capper.ApplyPowerCap(ctx, req, limit)
+	})
+	if err != nil {
+		r.completeTask(ctx, transitionID, task, attempts, "", err)
+		return
+	}
+
+	r.completeTask(ctx, transitionID, task, attempts, "", r.verifier.VerifyPowerCap(execCtx, r.capper, req, limit))
+}
+
+// VerifyPowerCap polls the node power limit until it matches limitWatts, or
+// until no limit is reported when limitWatts is zero.
+func (v *Verifier) VerifyPowerCap(ctx context.Context, capper PowerCapper, req ExecutionRequest, limitWatts int) error {
+	last, err := v.pollReadBack(ctx, func(ctx context.Context) (bool, string, error) {
+		reading, err := capper.ReadPowerCap(ctx, req)
+		if err != nil {
+			return false, "", fmt.Errorf("reading power cap: %w", err)
+		}
+		if reading.LimitWatts == nil || *reading.LimitWatts == 0 {
+			// Some BMCs report a zero limit instead of null when uncapped.
+			return limitWatts == 0, "none", nil
+		}
+		return *reading.LimitWatts == limitWatts, fmt.Sprintf("%d W", *reading.LimitWatts), nil
+	})
+	if errors.Is(err, errReadBackMismatch) {
+		return fmt.Errorf("%w: expected %s, last %s", ErrPowerCapNotApplied, describePowerLimit(limitWatts), last)
+	}
+	return err
+}
+
+func describePowerLimit(limitWatts int) string {
+	if limitWatts == 0 {
+		return "no limit"
+	}
+	return fmt.Sprintf("%d W", limitWatts)
+}
+
+// ReadPowerCaps reads the current power limit and draw of each node. Reads
+// share the global and per-BMC concurrency limits with running transitions.
+func (r *Runner) ReadPowerCaps(ctx context.Context, nodeIDs []string) ([]NodePowerReading, error) {
+	if r.capper == nil {
+		return nil, ErrPowerCapUnsupported
+	}
+
+	nodes := normalizeNodeIDs(nodeIDs)
+	if len(nodes) == 0 {
+		return nil, ErrNoTargetNodes
+	}
+
+	mappings, missing, err := r.store.ResolveNodeMappings(ctx, nodes)
+	if err != nil {
+		return nil, fmt.Errorf("resolving node mappings: %w", err)
+	}
+
+	results := make([]NodePowerReading, 0, len(nodes))
+	for _, mappingErr := range missing {
+		results = append(results, NodePowerReading{
+			NodeID:      strings.TrimSpace(mappingErr.NodeID),
+			ErrorDetail: strings.TrimSpace(mappingErr.Detail),
+		})
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.cfg.globalConcurrency)
+	for _, mapping := range mappings {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := NodePowerReading{
+				NodeID: strings.TrimSpace(mapping.NodeID),
+				BMCID:  strings.TrimSpace(mapping.BMCID),
+			}
+			reading, readErr := r.readPowerCap(ctx, ExecutionRequest{
+				NodeID:             result.NodeID,
+				BMCID:              result.BMCID,
+				Endpoint:           strings.TrimSpace(mapping.Endpoint),
+				CredentialID:       strings.TrimSpace(mapping.CredentialID),
+				InsecureSkipVerify: mapping.InsecureSkipVerify,
+			})
+			if readErr != nil {
+				result.ErrorDetail = readErr.Error()
+			} else {
+				result.Reading = reading
+			}
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].NodeID < results[j].NodeID
+	})
+	return results, nil
+}
+
+func (r *Runner) readPowerCap(ctx context.Context, req ExecutionRequest) (PowerReading, error) {
+	release, err := r.acquireBMCLimiter(ctx, req.BMCID)
+	if err != nil {
+		return PowerReading{}, err
+	}
+	defer release()
+
+	return r.capper.ReadPowerCap(ctx, req)
+}
@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+type mockPowerCapper struct {
+	mu      sync.Mutex
+	limits  map[string]int
+	applied []string
+	// ignore drops applied limits, so read-back never matches.
+	ignore  bool
+	readErr error
+}
+
+func (m *mockPowerCapper) ApplyPowerCap(ctx context.Context, req ExecutionRequest, limitWatts int) error {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.applied = append(m.applied, req.NodeID)
+	if m.ignore {
+		return nil
+	}
+	if m.limits == nil {
+		m.limits = make(map[string]int)
+	}
+	m.limits[req.NodeID] = limitWatts
+	return nil
+}
+
+func (m *mockPowerCapper) ReadPowerCap(ctx context.Context, req ExecutionRequest) (PowerReading, error) {
+	_ = ctx
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.readErr != nil {
+		return PowerReading{}, m.readErr
+	}
+	consumed := 310.5
+	reading := PowerReading{ConsumedWatts: &consumed, Source: PowerSourceEnvironmentMetrics}
+	if limit, ok := m.limits[req.NodeID]; ok && limit > 0 {
+		reading.LimitWatts = &limit
+	}
+	return reading, nil
+}
+
+// newSharedBMCTestStore maps node-1 and node-2 to one BMC; node-3 is unmapped.
+func newSharedBMCTestStore() *memoryStore {
+	return newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1"},
+		{NodeID: "node-2", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1"},
+	}, []model.NodeMappingError{{NodeID: "node-3", Detail: "node not found in topology mapping"}})
+}
+
+func TestRunner_PowerCapAppliesAndVerifiesLimit(t *testing.T) {
+	capper := &mockPowerCapper{}
+	updater := &recordingStateUpdater{}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithPowerCapper(capper), WithNodeStateUpdater(updater)))
+
+	limit := 400
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:     "powercap",
+		NodeIDs:       []string{"node-1", "node-2"},
+		PowerCapWatts: &limit,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, OperationPowerCap, transition.Operation)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, OperationPowerCap, task.Operation)
+		assert.Equal(t, TaskStateSucceeded, task.State)
+		require.NotNil(t, task.PowerCapWatts)
+		assert.Equal(t, 400, *task.PowerCapWatts)
+		assert.Empty(t, task.FinalPowerState)
+	}
+
+	capper.mu.Lock()
+	assert.ElementsMatch(t, []string{"node-1", "node-2"}, capper.applied)
+	capper.mu.Unlock()
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+	assert.Empty(t, updater.transitioning)
+	assert.Empty(t, updater.failed)
+}
+
+func TestRunner_PowerCapFailsWhenReadBackDiffers(t *testing.T) {
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithPowerCapper(&mockPowerCapper{ignore: true})))
+
+	limit := 350
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:     OperationPowerCap,
+		NodeIDs:       []string{"node-1"},
+		PowerCapWatts: &limit,
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateFailed, tasks[0].State)
+	assert.Contains(t, tasks[0].ErrorDetail, ErrPowerCapNotApplied.Error())
+	assert.Contains(t, tasks[0].ErrorDetail, "expected 350 W, last none")
+}
+
+func TestRunner_PowerCapRemovalVerifiesNoLimit(t *testing.T) {
+	capper := &mockPowerCapper{limits: map[string]int{"node-1": 500}}
+	store := newSharedBMCTestStore()
+	runner := startTestRunner(t, store, withRunnerOptions(WithPowerCapper(capper)))
+
+	limit := 0
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation:     OperationPowerCap,
+		NodeIDs:       []string{"node-1"},
+		PowerCapWatts: &limit,
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	tasks := store.tasksForTransition(transition.ID)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, TaskStateSucceeded, tasks[0].State)
+}
+
+func TestRunner_PowerCapValidation(t *testing.T) {
+	negative := -1
+	limit := 300
+
+	tests := []struct {
+		name    string
+		capper  PowerCapper
+		req     StartRequest
+		wantErr error
+	}{
+		{
+			name:    "not configured",
+			req:     StartRequest{Operation: OperationPowerCap, NodeIDs: []string{"node-1"}, PowerCapWatts: &limit},
+			wantErr: ErrPowerCapUnsupported,
+		},
+		{
+			name:    "missing limit",
+			capper:  &mockPowerCapper{},
+			req:     StartRequest{Operation: OperationPowerCap, NodeIDs: []string{"node-1"}},
+			wantErr: ErrInvalidPowerCap,
+		},
+		{
+			name:    "negative limit",
+			capper:  &mockPowerCapper{},
+			req:     StartRequest{Operation: OperationPowerCap, NodeIDs: []string{"node-1"}, PowerCapWatts: &negative},
+			wantErr: ErrInvalidPowerCap,
+		},
+		{
+			name:   "boot wait",
+			capper: &mockPowerCapper{},
+			req: StartRequest{
+				Operation:     OperationPowerCap,
+				NodeIDs:       []string{"node-1"},
+				PowerCapWatts: &limit,
+				BootWait:      &BootWait{Target: "OSRunning"},
+			},
+			wantErr: ErrInvalidPowerCap,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := startTestRunner(t, newSharedBMCTestStore(), withRunnerOptions(WithPowerCapper(tc.capper)))
+			_, err := runner.StartTransition(context.Background(), tc.req)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRunner_ReadPowerCaps(t *testing.T) {
+	capper := &mockPowerCapper{limits: map[string]int{"node-2": 450}}
+	runner := startTestRunner(t, newSharedBMCTestStore(), withRunnerOptions(WithPowerCapper(capper)))
+
+	readings, err := runner.ReadPowerCaps(context.Background(), []string{"node-3", "node-2", "node-1"})
+	require.NoError(t, err)
+	require.Len(t, readings, 3)
+
+	assert.Equal(t, "node-1", readings[0].NodeID)
+	assert.Equal(t, "bmc-1", readings[0].BMCID)
+	assert.Nil(t, readings[0].Reading.LimitWatts)
+	require.NotNil(t, readings[0].Reading.ConsumedWatts)
+	assert.InDelta(t, 310.5, *readings[0].Reading.ConsumedWatts, 0.001)
+
+	require.NotNil(t, readings[1].Reading.LimitWatts)
+	assert.Equal(t, 450, *readings[1].Reading.LimitWatts)
+
+	assert.Equal(t, "node-3", readings[2].NodeID)
+	assert.Equal(t, "node not found in topology mapping", readings[2].ErrorDetail)
+}
+
+func TestRunner_ReadPowerCapsReportsReadErrors(t *testing.T) {
+	runner := startTestRunner(t, newSharedBMCTestStore(), withRunnerOptions(WithPowerCapper(&mockPowerCapper{readErr: errors.New("bmc unreachable")})))
+
+	readings, err := runner.ReadPowerCaps(context.Background(), []string{"node-1"})
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	assert.Equal(t, "bmc unreachable", readings[0].ErrorDetail)
+
+	unconfigured := startTestRunner(t, newSharedBMCTestStore())
+	_, err = unconfigured.ReadPowerCaps(context.Background(), []string{"node-1"})
+	require.ErrorIs(t, err, ErrPowerCapUnsupported)
+}
@@ -3,50 +3,242 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
 )
 
 var errQueueClosed = errors.New("engine queue closed")
 
-// Queue is a bounded in-memory queue for transition tasks.
+// Priority classes, served in this order except when a lower class is owed
+// its share; see Queue.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+var priorityClasses = []string{PriorityHigh, PriorityNormal, PriorityLow}
+
+// queueMaxSkips is how many tasks of higher classes may be dequeued past a
+// waiting task of a lower class before that class is served once.
+const queueMaxSkips = 8
+
+// queueOperations lists the operations a priority class can be assigned to.
+var queueOperations = []string{
+	string(redfish.ResetOperationOn),
+	string(redfish.ResetOperationForceOff),
+	string(redfish.ResetOperationGracefulShutdown),
+	string(redfish.ResetOperationGracefulRestart),
+	string(redfish.ResetOperationForceRestart),
+	string(redfish.ResetOperationNMI),
+	OperationPowerCap,
+	OperationSetBootOverride,
+	OperationInsertMedia,
+	OperationEjectMedia,
+	OperationBootOverrideReset,
+}
+
+// DefaultPriorities returns the default priority class of each operation:
+// powering nodes on goes first and reboots go last. Operations not listed
+// are normal.
+func DefaultPriorities() map[string]string {
+	return map[string]string{
+		string(redfish.ResetOperationOn):              PriorityHigh,
+		string(redfish.ResetOperationGracefulRestart): PriorityLow,
+		string(redfish.ResetOperationForceRestart):    PriorityLow,
+	}
+}
+
+// ParsePriorities applies comma-separated `operation=class` overrides, e.g.
+// `ForceOff=high,PowerCap=low`, to DefaultPriorities.
+func ParsePriorities(raw string) (map[string]string, error) {
+	priorities := DefaultPriorities()
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid queue priority %q: expected operation=class", entry)
+		}
+
+		operation := ""
+		for _, candidate := range queueOperations {
+			if strings.EqualFold(candidate, strings.TrimSpace(key)) {
+				operation = candidate
+				break
+			}
+		}
+		if operation == "" {
+			return nil, fmt.Errorf("invalid queue priority %q: unknown operation", entry)
+		}
+
+		class := strings.ToLower(strings.TrimSpace(value))
+		if priorityRank(class) < 0 {
+			return nil, fmt.Errorf("invalid queue priority %q: class must be high, normal or low", entry)
+		}
+		priorities[operation] = class
+	}
+	return priorities, nil
+}
+
+func priorityRank(class string) int {
+	for rank, candidate := range priorityClasses {
+		if candidate == class {
+			return rank
+		}
+	}
+	return -1
+}
+
+// Queue is an in-memory priority queue for transition tasks. Tasks of a
+// higher priority class are dequeued first, but a class that was passed over
+// queueMaxSkips times is served next, so lower classes are never starved.
+// Tasks of one class are dequeued in order. Enqueueing never blocks: the
+// runner bounds the queue by rejecting new transitions once it is full.
 type Queue struct {
-	mu     sync.RWMutex
-	closed bool
-	ch     chan queuedTask
+	mu         sync.Mutex
+	closed     bool
+	classes    [][]queuedTask
+	priorities map[string]int
+	// skipped counts, per class, the dequeues that passed over its waiting
+	// tasks.
+	skipped []int
+	// reserved counts slots held by admitted transitions that have not
+	// enqueued their tasks yet.
+	reserved int
+	// ready holds a wakeup for one waiting worker while tasks are queued.
+	ready chan struct{}
+	done  chan struct{}
 }
 
-func newQueue(capacity int) *Queue {
-	if capacity <= 0 {
-		capacity = 1
+func newQueue(priorities map[string]string) *Queue {
+	ranks := make(map[string]int, len(priorities))
+	for operation, class := range priorities {
+		if rank := priorityRank(class); rank >= 0 {
+			ranks[operation] = rank
+		}
+	}
+	return &Queue{
+		classes:    make([][]queuedTask, len(priorityClasses)),
+		priorities: ranks,
+		skipped:    make([]int, len(priorityClasses)),
+		ready:      make(chan struct{}, 1),
+		done:       make(chan struct{}),
 	}
-	return &Queue{ch: make(chan queuedTask, capacity)}
 }
 
-func (q *Queue) enqueue(ctx context.Context, item queuedTask) error {
-	ch, closed := q.channel()
-	if closed {
-		return errQueueClosed
+func (q *Queue) rank(item queuedTask) int {
+	if rank, ok := q.priorities[item.task.Operation]; ok {
+		return rank
 	}
+	return priorityRank(PriorityNormal)
+}
 
-	select {
-	case ch <- item:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
+func (q *Queue) enqueue(item queuedTask) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return errQueueClosed
 	}
+	rank := q.rank(item)
+	q.classes[rank] = append(q.classes[rank], item)
+	q.signal()
+	return nil
 }
 
 func (q *Queue) dequeue(ctx context.Context) (queuedTask, error) {
-	ch, _ := q.channel()
+	for {
+		q.mu.Lock()
+		item, ok := q.pop()
+		closed := q.closed
+		if ok && q.lenLocked() > 0 {
+			// Pass the wakeup on so another waiting worker takes the rest.
+			q.signal()
+		}
+		q.mu.Unlock()
 
-	select {
-	case item, ok := <-ch:
-		if !ok {
+		switch {
+		case ok:
+			return item, nil
+		case closed:
 			return queuedTask{}, errQueueClosed
 		}
-		return item, nil
-	case <-ctx.Done():
-		return queuedTask{}, ctx.Err()
+
+		select {
+		case <-q.ready:
+		case <-q.done:
+		case <-ctx.Done():
+			return queuedTask{}, ctx.Err()
+		}
+	}
+}
+
+// pop removes the first task of the highest non-empty class, or of a class
+// that has been passed over queueMaxSkips times. The caller must hold mu.
+func (q *Queue) pop() (queuedTask, bool) {
+	chosen := -1
+	for rank, items := range q.classes {
+		if len(items) == 0 {
+			continue
+		}
+		if chosen < 0 {
+			chosen = rank
+		}
+		if q.skipped[rank] >= queueMaxSkips {
+			chosen = rank
+			break
+		}
+	}
+	if chosen < 0 {
+		return queuedTask{}, false
+	}
+
+	for rank, items := range q.classes {
+		if rank > chosen && len(items) > 0 {
+			q.skipped[rank]++
+		}
+	}
+	q.skipped[chosen] = 0
+
+	items := q.classes[chosen]
+	item := items[0]
+	items[0] = queuedTask{}
+	q.classes[chosen] = items[1:]
+	return item, true
+}
+
+// reserve holds a slot for an admitted transition while fewer than limit
+// tasks are queued or reserved. It returns the occupied depth otherwise.
+func (q *Queue) reserve(limit int) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depth := q.lenLocked() + q.reserved
+	if depth >= limit {
+		return depth, false
+	}
+	q.reserved++
+	return depth, true
+}
+
+// unreserve releases a slot taken by reserve.
+func (q *Queue) unreserve() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved--
+}
+
+// signal wakes one waiting worker. The caller must hold mu.
+func (q *Queue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
 	}
 }
 
@@ -58,11 +250,32 @@ func (q *Queue) close() {
 		return
 	}
 	q.closed = true
-	close(q.ch)
+	close(q.done)
 }
 
-func (q *Queue) channel() (chan queuedTask, bool) {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
-	return q.ch, q.closed
+// depth returns the number of queued tasks.
+func (q *Queue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.lenLocked()
+}
+
+func (q *Queue) lenLocked() int {
+	total := 0
+	for _, items := range q.classes {
+		total += len(items)
+	}
+	return total
+}
+
+// depthByClass returns the number of queued tasks per priority class.
+func (q *Queue) depthByClass() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depths := make(map[string]int, len(priorityClasses))
+	for rank, class := range priorityClasses {
+		depths[class] = len(q.classes[rank])
+	}
+	return depths
 }
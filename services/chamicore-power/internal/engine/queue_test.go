@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriorities(t *testing.T) {
+	priorities, err := ParsePriorities(" forceoff=HIGH, On=normal ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"On":              PriorityNormal,
+		"ForceOff":        PriorityHigh,
+		"GracefulRestart": PriorityLow,
+		"ForceRestart":    PriorityLow,
+	}, priorities)
+
+	defaults, err := ParsePriorities("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPriorities(), defaults)
+
+	for _, raw := range []string{"On", "Off=high", "On=urgent"} {
+		_, err := ParsePriorities(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestQueue_DequeuesByPriority(t *testing.T) {
+	q := newQueue(DefaultPriorities())
+	for _, item := range []struct{ node, operation string }{
+		{"node-1", "ForceRestart"},
+		{"node-2", "ForceOff"},
+		{"node-3", "On"},
+		{"node-4", "ForceRestart"},
+		{"node-5", "On"},
+		{"node-6", OperationPowerCap},
+	} {
+		require.NoError(t, q.enqueue(queuedTask{task: Task{NodeID: item.node, Operation: item.operation}}))
+	}
+	assert.Equal(t, 6, q.depth())
+	assert.Equal(t, map[string]int{PriorityHigh: 2, PriorityNormal: 2, PriorityLow: 2}, q.depthByClass())
+
+	order := make([]string, 0, 6)
+	for range 6 {
+		item, err := q.dequeue(context.Background())
+		require.NoError(t, err)
+		order = append(order, item.task.NodeID)
+	}
+	assert.Equal(t, []string{"node-3", "node-5", "node-2", "node-6", "node-1", "node-4"}, order)
+	assert.Zero(t, q.depth())
+}
+
+func TestQueue_ServesLowerClassesAfterMaxSkips(t *testing.T) {
+	q := newQueue(DefaultPriorities())
+	require.NoError(t, q.enqueue(queuedTask{task: Task{NodeID: "reboot", Operation: "ForceRestart"}}))
+	for i := range 2 * queueMaxSkips {
+		require.NoError(t, q.enqueue(queuedTask{task: Task{NodeID: "on-" + strconvItoa(i), Operation: "On"}}))
+	}
+
+	order := make([]string, 0, 2*queueMaxSkips+1)
+	for range 2*queueMaxSkips + 1 {
+		item, err := q.dequeue(context.Background())
+		require.NoError(t, err)
+		order = append(order, item.task.NodeID)
+	}
+	assert.Equal(t, "reboot", order[queueMaxSkips], "a low-priority task waits for at most queueMaxSkips others")
+	assert.Equal(t, "on-0", order[0])
+}
+
+func TestQueue_ReserveHoldsSlots(t *testing.T) {
+	q := newQueue(nil)
+	require.NoError(t, q.enqueue(queuedTask{task: Task{NodeID: "node-1", Operation: "On"}}))
+
+	_, ok := q.reserve(2)
+	require.True(t, ok)
+	depth, ok := q.reserve(2)
+	assert.False(t, ok, "a reserved slot counts against the limit")
+	assert.Equal(t, 2, depth)
+
+	q.unreserve()
+	_, ok = q.reserve(2)
+	assert.True(t, ok)
+}
+
+func TestQueue_WakesEveryWaitingWorker(t *testing.T) {
+	q := newQueue(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	dequeued := make(chan string, 3)
+	for range 3 {
+		go func() {
+			item, err := q.dequeue(ctx)
+			if err == nil {
+				dequeued <- item.task.NodeID
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	for _, node := range []string{"node-1", "node-2", "node-3"} {
+		require.NoError(t, q.enqueue(queuedTask{task: Task{NodeID: node, Operation: "On"}}))
+	}
+
+	got := make([]string, 0, 3)
+	for range 3 {
+		select {
+		case node := <-dequeued:
+			got = append(got, node)
+		case <-ctx.Done():
+			t.Fatalf("only %d of 3 tasks dequeued", len(got))
+		}
+	}
+	assert.ElementsMatch(t, []string{"node-1", "node-2", "node-3"}, got)
+
+	q.close()
+	_, err := q.dequeue(ctx)
+	assert.ErrorIs(t, err, errQueueClosed)
+	assert.ErrorIs(t, q.enqueue(queuedTask{}), errQueueClosed)
+}
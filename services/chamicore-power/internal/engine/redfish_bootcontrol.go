@@ -0,0 +1,278 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+// RedfishBootController sets boot source overrides on the node's Redfish
+// system and manages the virtual CD/DVD drive exposed by the system or, on
+// older BMCs, by its managing BMC.
+type RedfishBootController struct {
+	baseConfig sharedredfish.Config
+	creds      CredentialResolver
+	systems    *SystemPathResolver
+	raw        redfishHTTP
+}
+
+// NewRedfishBootController creates a boot controller backed by Redfish.
+func NewRedfishBootController(cfg sharedredfish.Config, creds CredentialResolver, systems *SystemPathResolver) *RedfishBootController {
+	if creds == nil {
+		creds = EmptyCredentialResolver{}
+	}
+	if systems == nil {
+		systems = NewSystemPathResolver()
+	}
+
+	return &RedfishBootController{
+		baseConfig: cfg,
+		creds:      creds,
+		systems:    systems,
+		raw:        newRedfishHTTP(),
+	}
+}
+
+type bootSystemResource struct {
+	Boot struct {
+		BootSourceOverrideTarget  string   `json:"BootSourceOverrideTarget"`
+		BootSourceOverrideEnabled string   `json:"BootSourceOverrideEnabled"`
+		AllowableTargets          []string `json:"BootSourceOverrideTarget@Redfish.AllowableValues"`
+	} `json:"Boot"`
+	VirtualMedia *redfishLink `json:"VirtualMedia"`
+	Links        struct {
+		ManagedBy []redfishLink `json:"ManagedBy"`
+	} `json:"Links"`
+}
+
+type redfishActionTarget struct {
+	Target string `json:"target"`
+}
+
+type virtualMediaResource struct {
+	Image      string   `json:"Image"`
+	Inserted   bool     `json:"Inserted"`
+	MediaTypes []string `json:"MediaTypes"`
+	Actions    struct {
+		Insert *redfishActionTarget `json:"#VirtualMedia.InsertMedia"`
+		Eject  *redfishActionTarget `json:"#VirtualMedia.EjectMedia"`
+	} `json:"Actions"`
+}
+
+// SetBootOverride patches Boot.BootSourceOverrideTarget and
+// Boot.BootSourceOverrideEnabled on the node's system.
+func (c *RedfishBootController) SetBootOverride(ctx context.Context, req ExecutionRequest, override BootOverride) error {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	var system bootSystemResource
+	systemPath, err := c.raw.getSystem(ctx, c.systems, c.client(req.InsecureSkipVerify), req, cred, &system)
+	if err != nil {
+		return classifyExecutionError(err)
+	}
+
+	if allowed := system.Boot.AllowableTargets; len(allowed) > 0 && !containsFold(allowed, override.Target) {
+		return fmt.Errorf(
+			"%w: BMC does not support target %q, allowed: %s",
+			ErrInvalidBootOverride,
+			override.Target,
+			strings.Join(allowed, ", "),
+		)
+	}
+
+	body := map[string]any{
+		"Boot": map[string]any{
+			"BootSourceOverrideTarget":  override.Target,
+			"BootSourceOverrideEnabled": override.Mode,
+		},
+	}
+	if err := c.raw.patch(ctx, req.Endpoint, systemPath, cred, req.InsecureSkipVerify, body); err != nil {
+		return classifyExecutionError(fmt.Errorf("patching Redfish boot override: %w", err))
+	}
+	return nil
+}
+
+// ReadBootOverride returns the node's current boot source override.
+func (c *RedfishBootController) ReadBootOverride(ctx context.Context, req ExecutionRequest) (BootOverride, error) {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return BootOverride{}, fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	var system bootSystemResource
+	if _, err := c.raw.getSystem(ctx, c.systems, c.client(req.InsecureSkipVerify), req, cred, &system); err != nil {
+		return BootOverride{}, err
+	}
+	return BootOverride{
+		Target: strings.TrimSpace(system.Boot.BootSourceOverrideTarget),
+		Mode:   strings.TrimSpace(system.Boot.BootSourceOverrideEnabled),
+	}, nil
+}
+
+// InsertMedia inserts an image into the node's virtual drive, ejecting any
+// other image first. Inserting the image that is already present is a no-op.
+func (c *RedfishBootController) InsertMedia(ctx context.Context, req ExecutionRequest, media VirtualMedia) error {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	path, drive, err := c.resolveMedia(ctx, req, cred)
+	if err != nil {
+		return classifyExecutionError(err)
+	}
+	if drive.Inserted {
+		if strings.TrimSpace(drive.Image) == media.Image {
+			return nil
+		}
+		if err := c.eject(ctx, req, cred, path, drive); err != nil {
+			return err
+		}
+	}
+
+	body := map[string]any{"Image": media.Image, "Inserted": true, "WriteProtected": true}
+	if drive.Actions.Insert != nil && strings.TrimSpace(drive.Actions.Insert.Target) != "" {
+		err = c.raw.post(ctx, req.Endpoint, strings.TrimSpace(drive.Actions.Insert.Target), cred, req.InsecureSkipVerify, body)
+	} else {
+		err = c.raw.patch(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, body)
+	}
+	if err != nil {
+		return classifyExecutionError(fmt.Errorf("inserting Redfish virtual media: %w", err))
+	}
+	return nil
+}
+
+// EjectMedia ejects the image from the node's virtual drive, if any.
+func (c *RedfishBootController) EjectMedia(ctx context.Context, req ExecutionRequest) error {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	path, drive, err := c.resolveMedia(ctx, req, cred)
+	if err != nil {
+		return classifyExecutionError(err)
+	}
+	if !drive.Inserted {
+		return nil
+	}
+	return c.eject(ctx, req, cred, path, drive)
+}
+
+// ReadMedia returns the state of the node's virtual drive.
+func (c *RedfishBootController) ReadMedia(ctx context.Context, req ExecutionRequest) (MediaState, error) {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return MediaState{}, fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	_, drive, err := c.resolveMedia(ctx, req, cred)
+	if err != nil {
+		return MediaState{}, err
+	}
+	return MediaState{Image: strings.TrimSpace(drive.Image), Inserted: drive.Inserted}, nil
+}
+
+func (c *RedfishBootController) eject(
+	ctx context.Context,
+	req ExecutionRequest,
+	cred sharedredfish.Credential,
+	path string,
+	drive virtualMediaResource,
+) error {
+	var err error
+	if drive.Actions.Eject != nil && strings.TrimSpace(drive.Actions.Eject.Target) != "" {
+		err = c.raw.post(ctx, req.Endpoint, strings.TrimSpace(drive.Actions.Eject.Target), cred, req.InsecureSkipVerify, map[string]any{})
+	} else {
+		err = c.raw.patch(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, map[string]any{"Image": nil, "Inserted": false})
+	}
+	if err != nil {
+		return classifyExecutionError(fmt.Errorf("ejecting Redfish virtual media: %w", err))
+	}
+	return nil
+}
+
+// resolveMedia finds the node's virtual CD/DVD drive, looking at the system's
+// own VirtualMedia collection first and then at the managing BMC's.
+func (c *RedfishBootController) resolveMedia(
+	ctx context.Context,
+	req ExecutionRequest,
+	cred sharedredfish.Credential,
+) (string, virtualMediaResource, error) {
+	var system bootSystemResource
+	systemPath, err := c.raw.getSystem(ctx, c.systems, c.client(req.InsecureSkipVerify), req, cred, &system)
+	if err != nil {
+		return "", virtualMediaResource{}, err
+	}
+
+	collectionPath := ""
+	if system.VirtualMedia != nil {
+		collectionPath = strings.TrimSpace(system.VirtualMedia.ODataID)
+	}
+	if collectionPath == "" && len(system.Links.ManagedBy) > 0 && strings.TrimSpace(system.Links.ManagedBy[0].ODataID) != "" {
+		var manager struct {
+			VirtualMedia *redfishLink `json:"VirtualMedia"`
+		}
+		managerPath := strings.TrimSpace(system.Links.ManagedBy[0].ODataID)
+		if err := c.raw.get(ctx, req.Endpoint, managerPath, cred, req.InsecureSkipVerify, &manager); err != nil {
+			return "", virtualMediaResource{}, fmt.Errorf("reading Redfish manager: %w", err)
+		}
+		if manager.VirtualMedia != nil {
+			collectionPath = strings.TrimSpace(manager.VirtualMedia.ODataID)
+		}
+	}
+	if collectionPath == "" {
+		return "", virtualMediaResource{}, fmt.Errorf("%w: system %s exposes no virtual media", ErrBootControlUnsupported, systemPath)
+	}
+
+	var collection struct {
+		Members []redfishLink `json:"Members"`
+	}
+	if err := c.raw.get(ctx, req.Endpoint, collectionPath, cred, req.InsecureSkipVerify, &collection); err != nil {
+		return "", virtualMediaResource{}, fmt.Errorf("reading Redfish virtual media collection: %w", err)
+	}
+
+	var (
+		firstPath  string
+		firstDrive virtualMediaResource
+	)
+	for _, member := range collection.Members {
+		path := strings.TrimSpace(member.ODataID)
+		if path == "" {
+			continue
+		}
+		var drive virtualMediaResource
+		if err := c.raw.get(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, &drive); err != nil {
+			return "", virtualMediaResource{}, fmt.Errorf("reading Redfish virtual media: %w", err)
+		}
+		if containsFold(drive.MediaTypes, "CD") || containsFold(drive.MediaTypes, "DVD") {
+			return path, drive, nil
+		}
+		if firstPath == "" {
+			firstPath, firstDrive = path, drive
+		}
+	}
+	if firstPath == "" {
+		return "", virtualMediaResource{}, fmt.Errorf("%w: %s has no virtual media drives", ErrBootControlUnsupported, collectionPath)
+	}
+	return firstPath, firstDrive, nil
+}
+
+func (c *RedfishBootController) client(insecureSkipVerify bool) RedfishAPI {
+	cfg := c.baseConfig
+	cfg.InsecureSkipVerify = insecureSkipVerify
+	return sharedredfish.New(cfg)
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+type bootControlBMC struct {
+	mu        sync.Mutex
+	resources map[string]map[string]any
+	requests  []string
+	bodies    map[string]map[string]any
+}
+
+func newBootControlBMC(resources map[string]map[string]any) *bootControlBMC {
+	resources["/redfish/v1/Systems"] = map[string]any{
+		"Members": []map[string]string{{"@odata.id": "/redfish/v1/Systems/node-a"}},
+	}
+	return &bootControlBMC{resources: resources, bodies: make(map[string]map[string]any)}
+}
+
+func (b *bootControlBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+		b.bodies[r.Method+" "+r.URL.Path] = body
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	resource, ok := b.resources[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resource)
+}
+
+func (b *bootControlBMC) writes() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.requests...)
+}
+
+func (b *bootControlBMC) body(key string) map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bodies[key]
+}
+
+func TestRedfishBootController_BootOverride(t *testing.T) {
+	t.Parallel()
+
+	bmc := newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"Boot": map[string]any{
+				"BootSourceOverrideTarget":                         "Pxe",
+				"BootSourceOverrideEnabled":                        "Once",
+				"BootSourceOverrideTarget@Redfish.AllowableValues": []string{"None", "Pxe", "Hdd", "Cd"},
+			},
+		},
+	})
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	booter := NewRedfishBootController(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	req := ExecutionRequest{NodeID: "node-a", Endpoint: server.URL}
+
+	require.NoError(t, booter.SetBootOverride(context.Background(), req, BootOverride{Target: "Cd", Mode: "Once"}))
+	assert.Equal(t, map[string]any{
+		"Boot": map[string]any{"BootSourceOverrideTarget": "Cd", "BootSourceOverrideEnabled": "Once"},
+	}, bmc.body("PATCH /redfish/v1/Systems/node-a"))
+
+	got, err := booter.ReadBootOverride(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, BootOverride{Target: "Pxe", Mode: "Once"}, got)
+
+	err = booter.SetBootOverride(context.Background(), req, BootOverride{Target: "BiosSetup", Mode: "Once"})
+	require.ErrorIs(t, err, ErrInvalidBootOverride)
+	assert.Contains(t, err.Error(), `BMC does not support target "BiosSetup"`)
+	assert.Len(t, bmc.writes(), 1)
+}
+
+func TestRedfishBootController_VirtualMediaActions(t *testing.T) {
+	t.Parallel()
+
+	bmc := newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"Links": map[string]any{"ManagedBy": []map[string]string{{"@odata.id": "/redfish/v1/Managers/bmc"}}},
+		},
+		"/redfish/v1/Managers/bmc": {
+			"VirtualMedia": map[string]string{"@odata.id": "/redfish/v1/Managers/bmc/VirtualMedia"},
+		},
+		"/redfish/v1/Managers/bmc/VirtualMedia": {
+			"Members": []map[string]string{
+				{"@odata.id": "/redfish/v1/Managers/bmc/VirtualMedia/Floppy"},
+				{"@odata.id": "/redfish/v1/Managers/bmc/VirtualMedia/CD"},
+			},
+		},
+		"/redfish/v1/Managers/bmc/VirtualMedia/Floppy": {
+			"MediaTypes": []string{"Floppy", "USBStick"},
+		},
+		"/redfish/v1/Managers/bmc/VirtualMedia/CD": {
+			"MediaTypes": []string{"CD", "DVD"},
+			"Image":      "http://images.example/old.iso",
+			"Inserted":   true,
+			"Actions": map[string]any{
+				"#VirtualMedia.InsertMedia": map[string]string{"target": "/redfish/v1/Managers/bmc/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia"},
+				"#VirtualMedia.EjectMedia":  map[string]string{"target": "/redfish/v1/Managers/bmc/VirtualMedia/CD/Actions/VirtualMedia.EjectMedia"},
+			},
+		},
+	})
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	booter := NewRedfishBootController(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	req := ExecutionRequest{NodeID: "node-a", Endpoint: server.URL}
+
+	state, err := booter.ReadMedia(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, MediaState{Image: "http://images.example/old.iso", Inserted: true}, state)
+
+	require.NoError(t, booter.InsertMedia(context.Background(), req, VirtualMedia{Image: "http://images.example/rescue.iso"}))
+	assert.Equal(t, []string{
+		"POST /redfish/v1/Managers/bmc/VirtualMedia/CD/Actions/VirtualMedia.EjectMedia",
+		"POST /redfish/v1/Managers/bmc/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia",
+	}, bmc.writes())
+	assert.Equal(t, map[string]any{
+		"Image":          "http://images.example/rescue.iso",
+		"Inserted":       true,
+		"WriteProtected": true,
+	}, bmc.body("POST /redfish/v1/Managers/bmc/VirtualMedia/CD/Actions/VirtualMedia.InsertMedia"))
+
+	// The same image is already inserted, so nothing is written.
+	require.NoError(t, booter.InsertMedia(context.Background(), req, VirtualMedia{Image: "http://images.example/old.iso"}))
+	assert.Len(t, bmc.writes(), 2)
+}
+
+func TestRedfishBootController_VirtualMediaPatchFallback(t *testing.T) {
+	t.Parallel()
+
+	bmc := newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"VirtualMedia": map[string]string{"@odata.id": "/redfish/v1/Systems/node-a/VirtualMedia"},
+		},
+		"/redfish/v1/Systems/node-a/VirtualMedia": {
+			"Members": []map[string]string{{"@odata.id": "/redfish/v1/Systems/node-a/VirtualMedia/1"}},
+		},
+		"/redfish/v1/Systems/node-a/VirtualMedia/1": {
+			"MediaTypes": []string{"DVD"},
+			"Inserted":   false,
+		},
+	})
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	booter := NewRedfishBootController(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	req := ExecutionRequest{NodeID: "node-a", Endpoint: server.URL}
+
+	require.NoError(t, booter.EjectMedia(context.Background(), req))
+	assert.Empty(t, bmc.writes())
+
+	require.NoError(t, booter.InsertMedia(context.Background(), req, VirtualMedia{Image: "http://images.example/rescue.iso"}))
+	assert.Equal(t, []string{"PATCH /redfish/v1/Systems/node-a/VirtualMedia/1"}, bmc.writes())
+	assert.Equal(t, map[string]any{
+		"Image":          "http://images.example/rescue.iso",
+		"Inserted":       true,
+		"WriteProtected": true,
+	}, bmc.body("PATCH /redfish/v1/Systems/node-a/VirtualMedia/1"))
+}
+
+func TestRedfishBootController_NoVirtualMedia(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {"Id": "node-a"},
+	}))
+	defer server.Close()
+
+	booter := NewRedfishBootController(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	_, err := booter.ReadMedia(context.Background(), ExecutionRequest{NodeID: "node-a", Endpoint: server.URL})
+	require.ErrorIs(t, err, ErrBootControlUnsupported)
+}
@@ -7,8 +7,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
 
 	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
 )
 
 // CredentialResolver resolves Redfish credentials for one credential ID.
@@ -33,27 +37,98 @@ type RedfishAPI interface {
 	GetSystemPowerState(ctx context.Context, endpoint, systemPath string, cred sharedredfish.Credential) (string, error)
 }
 
-// SystemPathResolver caches endpoint/node -> system-path lookups.
+const defaultSystemPathCacheTTL = 10 * time.Minute
+
+// ErrAmbiguousSystemPath indicates a BMC exposes several Redfish systems and none matches the node.
+var ErrAmbiguousSystemPath = errors.New("ambiguous Redfish system path")
+
+// SystemPathStore persists explicit node -> Redfish system-path mappings.
+type SystemPathStore interface {
+	LookupNodeSystemPath(ctx context.Context, nodeID string) (string, bool, error)
+	SaveNodeSystemPath(ctx context.Context, item model.NodeSystemPath) (model.NodeSystemPath, error)
+	ForgetDiscoveredSystemPath(ctx context.Context, nodeID string) error
+}
+
+// SystemPathOption configures a SystemPathResolver.
+type SystemPathOption func(*SystemPathResolver)
+
+// WithSystemPathStore persists discovered paths and honors admin-set paths.
+func WithSystemPathStore(store SystemPathStore) SystemPathOption {
+	return func(r *SystemPathResolver) {
+		r.store = store
+	}
+}
+
+// WithSystemPathCacheTTL bounds how long a resolved path is reused without a lookup.
+func WithSystemPathCacheTTL(ttl time.Duration) SystemPathOption {
+	return func(r *SystemPathResolver) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithSystemPathLogger logs paths that could not be persisted.
+func WithSystemPathLogger(logger zerolog.Logger) SystemPathOption {
+	return func(r *SystemPathResolver) {
+		r.log = logger
+	}
+}
+
+type systemPathEntry struct {
+	path      string
+	expiresAt time.Time
+}
+
+// SystemPathResolver resolves and caches endpoint/node -> system-path lookups.
+// Persisted mappings take precedence over discovery; cached entries expire
+// after the TTL and are dropped when the BMC answers 404 for them.
 type SystemPathResolver struct {
 	cache sync.Map
+	store SystemPathStore
+	ttl   time.Duration
+	log   zerolog.Logger
+	now   func() time.Time
 }
 
 // NewSystemPathResolver creates a system-path resolver.
-func NewSystemPathResolver() *SystemPathResolver {
-	return &SystemPathResolver{}
+func NewSystemPathResolver(opts ...SystemPathOption) *SystemPathResolver {
+	r := &SystemPathResolver{
+		ttl: defaultSystemPathCacheTTL,
+		log: zerolog.Nop(),
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// Resolve discovers or returns cached Redfish system path for a node.
+// Resolve returns the Redfish system path for a node from cache, the persisted
+// mapping, or discovery, in that order.
 func (r *SystemPathResolver) Resolve(ctx context.Context, client RedfishAPI, endpoint, nodeID string, cred sharedredfish.Credential) (string, error) {
 	normalizedEndpoint, err := sharedredfish.NormalizeEndpoint(endpoint)
 	if err != nil {
 		return "", err
 	}
+	node := strings.TrimSpace(nodeID)
 
-	key := cacheKey(normalizedEndpoint, nodeID)
+	key := cacheKey(normalizedEndpoint, node)
 	if cached, ok := r.cache.Load(key); ok {
-		if path, ok := cached.(string); ok && strings.TrimSpace(path) != "" {
-			return path, nil
+		if entry, ok := cached.(systemPathEntry); ok && entry.path != "" && r.now().Before(entry.expiresAt) {
+			return entry.path, nil
+		}
+		r.cache.Delete(key)
+	}
+
+	if r.store != nil && node != "" {
+		path, found, lookupErr := r.store.LookupNodeSystemPath(ctx, node)
+		if lookupErr != nil {
+			return "", fmt.Errorf("loading system path for node %q: %w", node, lookupErr)
+		}
+		if found && strings.TrimSpace(path) != "" {
+			r.remember(key, strings.TrimSpace(path))
+			return strings.TrimSpace(path), nil
 		}
 	}
 
@@ -65,30 +140,74 @@ func (r *SystemPathResolver) Resolve(ctx context.Context, client RedfishAPI, end
 		return "", fmt.Errorf("endpoint %q has no Redfish systems", normalizedEndpoint)
 	}
 
-	chosen := selectSystemPath(systemPaths, nodeID)
-	r.cache.Store(key, chosen)
+	chosen, err := selectSystemPath(systemPaths, node)
+	if err != nil {
+		return "", fmt.Errorf("endpoint %q: %w", normalizedEndpoint, err)
+	}
+	r.remember(key, chosen)
+
+	if r.store != nil && node != "" {
+		// Persisting is best effort; the in-memory entry still serves this replica.
+		saved, saveErr := r.store.SaveNodeSystemPath(ctx, model.NodeSystemPath{
+			NodeID:     node,
+			SystemPath: chosen,
+			Source:     model.SystemPathSourceDiscovery,
+		})
+		switch {
+		case saveErr != nil:
+			r.log.Warn().Err(saveErr).Str("node_id", node).Str("system_path", chosen).Msg("failed to persist discovered system path")
+		case saved.Source != model.SystemPathSourceDiscovery || saved.SystemPath != chosen:
+			r.log.Debug().
+				Str("node_id", node).
+				Str("system_path", chosen).
+				Str("kept_path", saved.SystemPath).
+				Str("kept_source", saved.Source).
+				Msg("discovered system path not persisted over an existing mapping")
+		}
+	}
 	return chosen, nil
 }
 
-func cacheKey(endpoint, nodeID string) string {
-	return strings.TrimSpace(endpoint) + "|" + strings.TrimSpace(nodeID)
-}
+// Refresh drops a path the BMC no longer serves and resolves the node again.
+// Admin-set mappings are kept, so Refresh may return the same path.
+func (r *SystemPathResolver) Refresh(ctx context.Context, client RedfishAPI, endpoint, nodeID string, cred sharedredfish.Credential) (string, error) {
+	normalizedEndpoint, err := sharedredfish.NormalizeEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	node := strings.TrimSpace(nodeID)
 
-func selectSystemPath(paths []string, nodeID string) string {
-	normalizedNodeID := strings.TrimSpace(nodeID)
-	if normalizedNodeID != "" {
-		for _, path := range paths {
-			trimmedPath := strings.TrimSpace(path)
-			if trimmedPath == "" {
-				continue
-			}
-			id := systemIDFromPath(trimmedPath)
-			if strings.EqualFold(id, normalizedNodeID) {
-				return trimmedPath
-			}
+	r.cache.Delete(cacheKey(normalizedEndpoint, node))
+	if r.store != nil && node != "" {
+		if forgetErr := r.store.ForgetDiscoveredSystemPath(ctx, node); forgetErr != nil {
+			return "", fmt.Errorf("forgetting system path for node %q: %w", node, forgetErr)
 		}
 	}
+	return r.Resolve(ctx, client, normalizedEndpoint, node, cred)
+}
+
+// InvalidateNode drops cached paths for a node on every endpoint.
+func (r *SystemPathResolver) InvalidateNode(nodeID string) {
+	suffix := "|" + strings.TrimSpace(nodeID)
+	r.cache.Range(func(key, _ any) bool {
+		if k, ok := key.(string); ok && strings.HasSuffix(k, suffix) {
+			r.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+func (r *SystemPathResolver) remember(key, path string) {
+	r.cache.Store(key, systemPathEntry{path: path, expiresAt: r.now().Add(r.ttl)})
+}
+
+func cacheKey(endpoint, nodeID string) string {
+	return strings.TrimSpace(endpoint) + "|" + strings.TrimSpace(nodeID)
+}
 
+// selectSystemPath picks the system whose ID matches the node, or the only
+// system. It refuses to guess between several non-matching systems.
+func selectSystemPath(paths []string, nodeID string) (string, error) {
 	copied := make([]string, 0, len(paths))
 	for _, path := range paths {
 		trimmedPath := strings.TrimSpace(path)
@@ -96,14 +215,30 @@ func selectSystemPath(paths []string, nodeID string) string {
 			copied = append(copied, trimmedPath)
 		}
 	}
-	if len(copied) == 0 {
-		return ""
+
+	normalizedNodeID := strings.TrimSpace(nodeID)
+	if normalizedNodeID != "" {
+		for _, path := range copied {
+			if strings.EqualFold(systemIDFromPath(path), normalizedNodeID) {
+				return path, nil
+			}
+		}
 	}
-	if len(copied) == 1 {
-		return copied[0]
+
+	switch len(copied) {
+	case 0:
+		return "", fmt.Errorf("no usable Redfish system paths")
+	case 1:
+		return copied[0], nil
 	}
 	sort.Strings(copied)
-	return copied[0]
+	return "", fmt.Errorf(
+		"%w: node %q matches none of %d systems (%s); set an explicit system path via the admin API",
+		ErrAmbiguousSystemPath,
+		normalizedNodeID,
+		len(copied),
+		strings.Join(copied, ", "),
+	)
 }
 
 func systemIDFromPath(systemPath string) string {
@@ -153,7 +288,17 @@ func (e *RedfishExecutor) ExecutePowerAction(ctx context.Context, req ExecutionR
 		return classifyExecutionError(fmt.Errorf("resolving Redfish system path: %w", err))
 	}
 
-	if err := client.ResetSystem(ctx, req.Endpoint, systemPath, cred, req.Operation); err != nil {
+	err = sharedClientStatusError(client.ResetSystem(ctx, req.Endpoint, systemPath, cred, req.Operation))
+	if isRedfishNotFound(err) {
+		refreshed, refreshErr := e.systems.Refresh(ctx, client, req.Endpoint, req.NodeID, cred)
+		if refreshErr != nil {
+			return classifyExecutionError(fmt.Errorf("re-resolving Redfish system path: %w", refreshErr))
+		}
+		if refreshed != systemPath {
+			err = client.ResetSystem(ctx, req.Endpoint, refreshed, cred, req.Operation)
+		}
+	}
+	if err != nil {
 		return classifyExecutionError(fmt.Errorf("issuing Redfish reset action: %w", err))
 	}
 
@@ -171,6 +316,7 @@ type RedfishStateReader struct {
 	baseConfig sharedredfish.Config
 	creds      CredentialResolver
 	systems    *SystemPathResolver
+	raw        redfishHTTP
 }
 
 // NewRedfishStateReader creates a verification reader backed by Redfish.
@@ -186,6 +332,7 @@ func NewRedfishStateReader(cfg sharedredfish.Config, creds CredentialResolver, s
 		baseConfig: cfg,
 		creds:      creds,
 		systems:    systems,
+		raw:        newRedfishHTTP(),
 	}
 }
 
@@ -204,6 +351,16 @@ func (r *RedfishStateReader) ReadPowerState(ctx context.Context, req ExecutionRe
 	}
 
 	powerState, err := client.GetSystemPowerState(ctx, req.Endpoint, systemPath, cred)
+	err = sharedClientStatusError(err)
+	if isRedfishNotFound(err) {
+		refreshed, refreshErr := r.systems.Refresh(ctx, client, req.Endpoint, req.NodeID, cred)
+		if refreshErr != nil {
+			return "", fmt.Errorf("re-resolving Redfish system path: %w", refreshErr)
+		}
+		if refreshed != systemPath {
+			powerState, err = client.GetSystemPowerState(ctx, req.Endpoint, refreshed, cred)
+		}
+	}
 	if err != nil {
 		return "", fmt.Errorf("reading Redfish power state: %w", err)
 	}
@@ -211,6 +368,77 @@ func (r *RedfishStateReader) ReadPowerState(ctx context.Context, req ExecutionRe
 	return strings.TrimSpace(powerState), nil
 }
 
+// ReadSystemState returns one node's power state with its LastResetTime and
+// BootProgress, used to prove that a restart actually cycled the node.
+func (r *RedfishStateReader) ReadSystemState(ctx context.Context, req ExecutionRequest) (SystemState, error) {
+	client := r.client(req.InsecureSkipVerify)
+
+	cred, err := r.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return SystemState{}, fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	systemPath, err := r.systems.Resolve(ctx, client, req.Endpoint, req.NodeID, cred)
+	if err != nil {
+		return SystemState{}, fmt.Errorf("resolving Redfish system path: %w", err)
+	}
+
+	state, err := r.getSystem(ctx, req.Endpoint, systemPath, cred, req.InsecureSkipVerify)
+	if isRedfishNotFound(err) {
+		refreshed, refreshErr := r.systems.Refresh(ctx, client, req.Endpoint, req.NodeID, cred)
+		if refreshErr != nil {
+			return SystemState{}, fmt.Errorf("re-resolving Redfish system path: %w", refreshErr)
+		}
+		if refreshed != systemPath {
+			state, err = r.getSystem(ctx, req.Endpoint, refreshed, cred, req.InsecureSkipVerify)
+		}
+	}
+	if err != nil {
+		return SystemState{}, fmt.Errorf("reading Redfish system: %w", err)
+	}
+
+	return state, nil
+}
+
+// getSystem reads the ComputerSystem fields the shared client does not expose.
+func (r *RedfishStateReader) getSystem(
+	ctx context.Context,
+	endpoint, systemPath string,
+	cred sharedredfish.Credential,
+	insecureSkipVerify bool,
+) (SystemState, error) {
+	var system struct {
+		PowerState    string `json:"PowerState"`
+		LastResetTime string `json:"LastResetTime"`
+		BootProgress  struct {
+			LastState string `json:"LastState"`
+		} `json:"BootProgress"`
+		Oem struct {
+			Hpe struct {
+				PostState string `json:"PostState"`
+			} `json:"Hpe"`
+			Hp struct {
+				PostState string `json:"PostState"`
+			} `json:"Hp"`
+		} `json:"Oem"`
+	}
+	if err := r.raw.get(ctx, endpoint, systemPath, cred, insecureSkipVerify, &system); err != nil {
+		return SystemState{}, err
+	}
+
+	postState := strings.TrimSpace(system.Oem.Hpe.PostState)
+	if postState == "" {
+		postState = strings.TrimSpace(system.Oem.Hp.PostState)
+	}
+
+	return SystemState{
+		PowerState:    strings.TrimSpace(system.PowerState),
+		LastResetTime: strings.TrimSpace(system.LastResetTime),
+		BootProgress:  strings.TrimSpace(system.BootProgress.LastState),
+		PostState:     postState,
+	}, nil
+}
+
 func (r *RedfishStateReader) client(insecureSkipVerify bool) RedfishAPI {
 	cfg := r.baseConfig
 	cfg.InsecureSkipVerify = insecureSkipVerify
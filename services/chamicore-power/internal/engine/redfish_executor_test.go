@@ -1,20 +1,92 @@
 package engine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
 )
 
+type fakeSystemPathStore struct {
+	mu      sync.Mutex
+	items   map[string]model.NodeSystemPath
+	saveErr error
+}
+
+func newFakeSystemPathStore(items ...model.NodeSystemPath) *fakeSystemPathStore {
+	store := &fakeSystemPathStore{items: make(map[string]model.NodeSystemPath, len(items))}
+	for _, item := range items {
+		store.items[item.NodeID] = item
+	}
+	return store
+}
+
+func (f *fakeSystemPathStore) LookupNodeSystemPath(ctx context.Context, nodeID string) (string, bool, error) {
+	_ = ctx
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[nodeID]
+	return item.SystemPath, ok, nil
+}
+
+func (f *fakeSystemPathStore) SaveNodeSystemPath(ctx context.Context, item model.NodeSystemPath) (model.NodeSystemPath, error) {
+	_ = ctx
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.saveErr != nil {
+		return model.NodeSystemPath{}, f.saveErr
+	}
+	if existing, ok := f.items[item.NodeID]; ok && existing.Source == model.SystemPathSourceAdmin && item.Source != model.SystemPathSourceAdmin {
+		return existing, nil
+	}
+	f.items[item.NodeID] = item
+	return item, nil
+}
+
+func (f *fakeSystemPathStore) ForgetDiscoveredSystemPath(ctx context.Context, nodeID string) error {
+	_ = ctx
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if item, ok := f.items[nodeID]; ok && item.Source == model.SystemPathSourceDiscovery {
+		delete(f.items, nodeID)
+	}
+	return nil
+}
+
+func (f *fakeSystemPathStore) get(nodeID string) (model.NodeSystemPath, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[nodeID]
+	return item, ok
+}
+
+func systemsHandler(calls *int32, members ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if calls != nil {
+			atomic.AddInt32(calls, 1)
+		}
+		refs := make([]map[string]string, 0, len(members))
+		for _, member := range members {
+			refs = append(refs, map[string]string{"@odata.id": member})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"Members": refs})
+	}
+}
+
 func TestSystemPathResolver_ResolvesByNodeIDAndCaches(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +120,151 @@ func TestSystemPathResolver_ResolvesByNodeIDAndCaches(t *testing.T) {
 	assert.Equal(t, int32(1), atomic.LoadInt32(&systemsCalls))
 }
 
+func TestSystemPathResolver_FailsOnAmbiguousSystems(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(systemsHandler(nil, "/redfish/v1/Systems/1", "/redfish/v1/Systems/2"))
+	defer server.Close()
+
+	resolver := NewSystemPathResolver()
+	client := sharedredfish.New(sharedredfish.Config{MaxAttempts: 1})
+
+	_, err := resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrAmbiguousSystemPath)
+	assert.Contains(t, err.Error(), "/redfish/v1/Systems/1, /redfish/v1/Systems/2")
+}
+
+func TestSystemPathResolver_SingleSystemIsUsedAndPersisted(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(systemsHandler(nil, "/redfish/v1/Systems/Self"))
+	defer server.Close()
+
+	store := newFakeSystemPathStore()
+	resolver := NewSystemPathResolver(WithSystemPathStore(store))
+	client := sharedredfish.New(sharedredfish.Config{MaxAttempts: 1})
+
+	path, err := resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Systems/Self", path)
+
+	saved, ok := store.get("node-a")
+	require.True(t, ok)
+	assert.Equal(t, "/redfish/v1/Systems/Self", saved.SystemPath)
+	assert.Equal(t, model.SystemPathSourceDiscovery, saved.Source)
+}
+
+func TestSystemPathResolver_LogsPersistFailures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(systemsHandler(nil, "/redfish/v1/Systems/Self"))
+	defer server.Close()
+
+	store := newFakeSystemPathStore()
+	store.saveErr = errors.New("database unavailable")
+	var logs bytes.Buffer
+	resolver := NewSystemPathResolver(WithSystemPathStore(store), WithSystemPathLogger(zerolog.New(&logs)))
+	client := sharedredfish.New(sharedredfish.Config{MaxAttempts: 1})
+
+	path, err := resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err, "persisting is best effort")
+	assert.Equal(t, "/redfish/v1/Systems/Self", path)
+	assert.Contains(t, logs.String(), "failed to persist discovered system path")
+	assert.Contains(t, logs.String(), "database unavailable")
+}
+
+func TestSystemPathResolver_StoredPathTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	var systemsCalls int32
+	server := httptest.NewServer(systemsHandler(&systemsCalls, "/redfish/v1/Systems/1", "/redfish/v1/Systems/2"))
+	defer server.Close()
+
+	store := newFakeSystemPathStore(model.NodeSystemPath{
+		NodeID:     "node-a",
+		SystemPath: "/redfish/v1/Systems/2",
+		Source:     model.SystemPathSourceAdmin,
+	})
+	resolver := NewSystemPathResolver(WithSystemPathStore(store))
+	client := sharedredfish.New(sharedredfish.Config{MaxAttempts: 1})
+
+	path, err := resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Systems/2", path)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&systemsCalls))
+}
+
+func TestSystemPathResolver_CacheExpiresAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	var systemsCalls int32
+	server := httptest.NewServer(systemsHandler(&systemsCalls, "/redfish/v1/Systems/node-a"))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	resolver := NewSystemPathResolver(WithSystemPathCacheTTL(time.Minute))
+	resolver.now = func() time.Time { return now }
+	client := sharedredfish.New(sharedredfish.Config{MaxAttempts: 1})
+
+	_, err := resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	_, err = resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&systemsCalls))
+
+	now = now.Add(2 * time.Minute)
+	_, err = resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&systemsCalls))
+
+	resolver.InvalidateNode("node-a")
+	_, err = resolver.Resolve(context.Background(), client, server.URL, "node-a", sharedredfish.Credential{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&systemsCalls))
+}
+
+func TestRedfishExecutor_ExecutePowerAction_RefreshesStalePathOn404(t *testing.T) {
+	t.Parallel()
+
+	var resetCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/redfish/v1/Systems":
+			systemsHandler(nil, "/redfish/v1/Systems/node-a")(w, r)
+		case r.Method == http.MethodPost && r.URL.Path == "/redfish/v1/Systems/node-a/Actions/ComputerSystem.Reset":
+			atomic.AddInt32(&resetCalls, 1)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	store := newFakeSystemPathStore(model.NodeSystemPath{
+		NodeID:     "node-a",
+		SystemPath: "/redfish/v1/Systems/stale",
+		Source:     model.SystemPathSourceDiscovery,
+	})
+	executor := NewRedfishExecutor(
+		sharedredfish.Config{MaxAttempts: 1},
+		EmptyCredentialResolver{},
+		NewSystemPathResolver(WithSystemPathStore(store)),
+	)
+
+	err := executor.ExecutePowerAction(context.Background(), ExecutionRequest{
+		Endpoint:  server.URL,
+		NodeID:    "node-a",
+		Operation: sharedredfish.ResetOperationOn,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&resetCalls))
+
+	saved, ok := store.get("node-a")
+	require.True(t, ok)
+	assert.Equal(t, "/redfish/v1/Systems/node-a", saved.SystemPath)
+}
+
 func TestRedfishExecutor_ExecutePowerAction_Success(t *testing.T) {
 	t.Parallel()
 
@@ -118,6 +335,75 @@ func TestRedfishStateReader_ReadPowerState_Success(t *testing.T) {
 	assert.Equal(t, "On", powerState)
 }
 
+func TestRedfishStateReader_ReadSystemState_ReturnsRestartEvidence(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/Systems":
+			systemsHandler(nil, "/redfish/v1/Systems/node-a")(w, r)
+		case "/redfish/v1/Systems/node-a":
+			user, pass, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "root", user)
+			assert.Equal(t, "secret", pass)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"PowerState":    "On",
+				"LastResetTime": "2026-01-01T00:00:00Z",
+				"BootProgress":  map[string]string{"LastState": "OSRunning"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	reader := NewRedfishStateReader(
+		sharedredfish.Config{MaxAttempts: 1},
+		staticCredentialResolver{cred: sharedredfish.Credential{Username: "root", Password: "secret"}},
+		NewSystemPathResolver(),
+	)
+
+	state, err := reader.ReadSystemState(context.Background(), ExecutionRequest{
+		Endpoint: server.URL,
+		NodeID:   "node-a",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SystemState{
+		PowerState:    "On",
+		LastResetTime: "2026-01-01T00:00:00Z",
+		BootProgress:  "OSRunning",
+	}, state)
+}
+
+type staticCredentialResolver struct {
+	cred sharedredfish.Credential
+}
+
+func (s staticCredentialResolver) Resolve(ctx context.Context, credentialID string) (sharedredfish.Credential, error) {
+	_ = ctx
+	_ = credentialID
+	return s.cred, nil
+}
+
+func TestIsRedfishNotFound_ChecksTypedStatus(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isRedfishNotFound(fmt.Errorf("reading: %w", &redfishStatusError{StatusCode: http.StatusNotFound})))
+	assert.False(t, isRedfishNotFound(&redfishStatusError{StatusCode: http.StatusInternalServerError}))
+	assert.False(t, isRedfishNotFound(errors.New("unexpected status 404")))
+	assert.False(t, isRedfishNotFound(nil))
+
+	shared := fmt.Errorf("resetting system: %w", errors.New("unexpected status 404"))
+	converted := sharedClientStatusError(shared)
+	assert.True(t, isRedfishNotFound(converted))
+	assert.Equal(t, shared.Error(), converted.Error())
+	assert.ErrorIs(t, converted, errors.Unwrap(shared))
+
+	plain := errors.New("connection refused")
+	assert.Same(t, plain, sharedClientStatusError(plain))
+}
+
 func TestClassifyExecutionError_RetryableAndNonRetryable(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+const (
+	redfishRequestTimeout = 30 * time.Second
+	maxRedfishBodyBytes   = 1 << 20
+)
+
+// redfishStatusError reports a Redfish response with a non-2xx status.
+type redfishStatusError struct {
+	StatusCode int
+	// err is the original shared client error, when the status was
+	// recovered from one.
+	err error
+}
+
+func (e *redfishStatusError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("unexpected status %d", e.StatusCode)
+}
+
+func (e *redfishStatusError) Unwrap() error {
+	return e.err
+}
+
+// sharedClientStatusError types the status of a failed shared client call,
+// which reports it only in the error text as "unexpected status N".
+func sharedClientStatusError(err error) error {
+	var statusErr *redfishStatusError
+	if err == nil || errors.As(err, &statusErr) {
+		return err
+	}
+	const marker = "unexpected status "
+	msg := err.Error()
+	idx := strings.Index(msg, marker)
+	if idx < 0 {
+		return err
+	}
+	var code int
+	if _, scanErr := fmt.Sscanf(msg[idx+len(marker):], "%d", &code); scanErr != nil {
+		return err
+	}
+	return &redfishStatusError{StatusCode: code, err: err}
+}
+
+// isRedfishNotFound reports whether a Redfish call failed because the resource no longer exists.
+func isRedfishNotFound(err error) bool {
+	var statusErr *redfishStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// redfishHTTP issues the Redfish requests the shared client does not cover.
+type redfishHTTP struct {
+	secure   *http.Client
+	insecure *http.Client
+}
+
+func newRedfishHTTP() redfishHTTP {
+	return redfishHTTP{
+		secure: &http.Client{Timeout: redfishRequestTimeout},
+		insecure: &http.Client{
+			Timeout: redfishRequestTimeout,
+			Transport: &http.Transport{
+				//nolint:gosec // Opt-in per BMC mapping for self-signed BMC certificates.
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12},
+			},
+		},
+	}
+}
+
+// get decodes one Redfish resource into out.
+func (h redfishHTTP) get(
+	ctx context.Context,
+	endpoint, path string,
+	cred sharedredfish.Credential,
+	insecureSkipVerify bool,
+	out any,
+) error {
+	resp, err := h.do(ctx, http.MethodGet, endpoint, path, cred, insecureSkipVerify, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRedfishBodyBytes)).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// patch sends a JSON merge patch to one Redfish resource.
+func (h redfishHTTP) patch(
+	ctx context.Context,
+	endpoint, path string,
+	cred sharedredfish.Credential,
+	insecureSkipVerify bool,
+	body any,
+) error {
+	resp, err := h.do(ctx, http.MethodPatch, endpoint, path, cred, insecureSkipVerify, body)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRedfishBodyBytes))
+	return resp.Body.Close()
+}
+
+// post invokes one Redfish action.
+func (h redfishHTTP) post(
+	ctx context.Context,
+	endpoint, path string,
+	cred sharedredfish.Credential,
+	insecureSkipVerify bool,
+	body any,
+) error {
+	resp, err := h.do(ctx, http.MethodPost, endpoint, path, cred, insecureSkipVerify, body)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRedfishBodyBytes))
+	return resp.Body.Close()
+}
+
+// getSystem decodes a node's ComputerSystem into out, re-resolving the system
+// path once when the BMC no longer serves the cached one. It returns the path
+// that was read.
+func (h redfishHTTP) getSystem(
+	ctx context.Context,
+	systems *SystemPathResolver,
+	client RedfishAPI,
+	req ExecutionRequest,
+	cred sharedredfish.Credential,
+	out any,
+) (string, error) {
+	systemPath, err := systems.Resolve(ctx, client, req.Endpoint, req.NodeID, cred)
+	if err != nil {
+		return "", fmt.Errorf("resolving Redfish system path: %w", err)
+	}
+
+	err = h.get(ctx, req.Endpoint, systemPath, cred, req.InsecureSkipVerify, out)
+	if isRedfishNotFound(err) {
+		refreshed, refreshErr := systems.Refresh(ctx, client, req.Endpoint, req.NodeID, cred)
+		if refreshErr != nil {
+			return "", fmt.Errorf("re-resolving Redfish system path: %w", refreshErr)
+		}
+		if refreshed != systemPath {
+			systemPath = refreshed
+			err = h.get(ctx, req.Endpoint, systemPath, cred, req.InsecureSkipVerify, out)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading Redfish system: %w", err)
+	}
+	return systemPath, nil
+}
+
+func (h redfishHTTP) do(
+	ctx context.Context,
+	method, endpoint, path string,
+	cred sharedredfish.Credential,
+	insecureSkipVerify bool,
+	body any,
+) (*http.Response, error) {
+	normalizedEndpoint, err := sharedredfish.NormalizeEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		raw, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("encoding request body: %w", marshalErr)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, normalizedEndpoint+strings.TrimSpace(path), reader)
+	if err != nil {
+		return nil, fmt.Errorf("building %s request: %w", method, err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if cred.Username != "" || cred.Password != "" {
+		httpReq.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	client := h.secure
+	if insecureSkipVerify {
+		client = h.insecure
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		_ = resp.Body.Close()
+		return nil, &redfishStatusError{StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+// Power-reading sources.
+const (
+	PowerSourceEnvironmentMetrics = "EnvironmentMetrics"
+	PowerSourcePower              = "Power"
+)
+
+// RedfishPowerCapper applies and reads node power limits through the chassis
+// EnvironmentMetrics resource, falling back to the legacy Power resource on
+// BMCs that do not expose PowerLimitWatts.
+type RedfishPowerCapper struct {
+	baseConfig sharedredfish.Config
+	creds      CredentialResolver
+	systems    *SystemPathResolver
+	raw        redfishHTTP
+}
+
+// NewRedfishPowerCapper creates a power capper backed by Redfish.
+func NewRedfishPowerCapper(cfg sharedredfish.Config, creds CredentialResolver, systems *SystemPathResolver) *RedfishPowerCapper {
+	if creds == nil {
+		creds = EmptyCredentialResolver{}
+	}
+	if systems == nil {
+		systems = NewSystemPathResolver()
+	}
+
+	return &RedfishPowerCapper{
+		baseConfig: cfg,
+		creds:      creds,
+		systems:    systems,
+		raw:        newRedfishHTTP(),
+	}
+}
+
+type redfishLink struct {
+	ODataID string `json:"@odata.id"`
+}
+
+type environmentMetricsResource struct {
+	PowerWatts *struct {
+		Reading *float64 `json:"Reading"`
+	} `json:"PowerWatts"`
+	PowerLimitWatts *struct {
+		SetPoint     *float64 `json:"SetPoint"`
+		AllowableMin *float64 `json:"AllowableMin"`
+		AllowableMax *float64 `json:"AllowableMax"`
+		ControlMode  string   `json:"ControlMode"`
+	} `json:"PowerLimitWatts"`
+}
+
+type powerResource struct {
+	PowerControl []struct {
+		PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+		PowerCapacityWatts *float64 `json:"PowerCapacityWatts"`
+		PowerLimit         *struct {
+			LimitInWatts *float64 `json:"LimitInWatts"`
+		} `json:"PowerLimit"`
+	} `json:"PowerControl"`
+}
+
+// powerCapTarget is the resource that carries a node's power limit.
+type powerCapTarget struct {
+	path    string
+	source  string
+	reading PowerReading
+}
+
+// ReadPowerCap returns one node's power limit and current draw.
+func (c *RedfishPowerCapper) ReadPowerCap(ctx context.Context, req ExecutionRequest) (PowerReading, error) {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return PowerReading{}, fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	target, err := c.resolveTarget(ctx, req, cred)
+	if err != nil {
+		return PowerReading{}, err
+	}
+	return target.reading, nil
+}
+
+// ApplyPowerCap sets one node's power limit; zero removes the limit.
+func (c *RedfishPowerCapper) ApplyPowerCap(ctx context.Context, req ExecutionRequest, limitWatts int) error {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	target, err := c.resolveTarget(ctx, req, cred)
+	if err != nil {
+		return classifyExecutionError(err)
+	}
+
+	if limitWatts > 0 {
+		if minWatts := target.reading.MinLimitWatts; minWatts != nil && limitWatts < *minWatts {
+			return fmt.Errorf("%w: %d W is below the BMC minimum of %d W", ErrInvalidPowerCap, limitWatts, *minWatts)
+		}
+		if maxWatts := target.reading.MaxLimitWatts; maxWatts != nil && limitWatts > *maxWatts {
+			return fmt.Errorf("%w: %d W is above the BMC maximum of %d W", ErrInvalidPowerCap, limitWatts, *maxWatts)
+		}
+	}
+
+	var body any
+	switch target.source {
+	case PowerSourceEnvironmentMetrics:
+		limit := map[string]any{"ControlMode": "Disabled"}
+		if limitWatts > 0 {
+			limit = map[string]any{"SetPoint": limitWatts, "ControlMode": "Automatic"}
+		}
+		body = map[string]any{"PowerLimitWatts": limit}
+	default:
+		var limit *int
+		if limitWatts > 0 {
+			limit = &limitWatts
+		}
+		body = map[string]any{
+			"PowerControl": []map[string]any{{"PowerLimit": map[string]any{"LimitInWatts": limit}}},
+		}
+	}
+
+	if err := c.raw.patch(ctx, req.Endpoint, target.path, cred, req.InsecureSkipVerify, body); err != nil {
+		return classifyExecutionError(fmt.Errorf("patching Redfish %s: %w", target.source, err))
+	}
+	return nil
+}
+
+// resolveTarget follows system -> chassis -> EnvironmentMetrics or Power and
+// reads the current limit from whichever resource carries one.
+func (c *RedfishPowerCapper) resolveTarget(ctx context.Context, req ExecutionRequest, cred sharedredfish.Credential) (powerCapTarget, error) {
+	var system struct {
+		Links struct {
+			Chassis []redfishLink `json:"Chassis"`
+		} `json:"Links"`
+	}
+	systemPath, err := c.raw.getSystem(ctx, c.systems, c.client(req.InsecureSkipVerify), req, cred, &system)
+	if err != nil {
+		return powerCapTarget{}, err
+	}
+	if len(system.Links.Chassis) == 0 || strings.TrimSpace(system.Links.Chassis[0].ODataID) == "" {
+		return powerCapTarget{}, fmt.Errorf("%w: system %s has no chassis link", ErrPowerCapUnsupported, systemPath)
+	}
+	chassisPath := strings.TrimSpace(system.Links.Chassis[0].ODataID)
+
+	var chassis struct {
+		EnvironmentMetrics *redfishLink `json:"EnvironmentMetrics"`
+		Power              *redfishLink `json:"Power"`
+	}
+	if err := c.raw.get(ctx, req.Endpoint, chassisPath, cred, req.InsecureSkipVerify, &chassis); err != nil {
+		return powerCapTarget{}, fmt.Errorf("reading Redfish chassis: %w", err)
+	}
+
+	if chassis.EnvironmentMetrics != nil && strings.TrimSpace(chassis.EnvironmentMetrics.ODataID) != "" {
+		path := strings.TrimSpace(chassis.EnvironmentMetrics.ODataID)
+		var metrics environmentMetricsResource
+		if err := c.raw.get(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, &metrics); err != nil {
+			return powerCapTarget{}, fmt.Errorf("reading Redfish EnvironmentMetrics: %w", err)
+		}
+		if metrics.PowerLimitWatts != nil {
+			reading := PowerReading{
+				MinLimitWatts: roundWatts(metrics.PowerLimitWatts.AllowableMin),
+				MaxLimitWatts: roundWatts(metrics.PowerLimitWatts.AllowableMax),
+				Source:        PowerSourceEnvironmentMetrics,
+			}
+			if !strings.EqualFold(metrics.PowerLimitWatts.ControlMode, "Disabled") {
+				reading.LimitWatts = roundWatts(metrics.PowerLimitWatts.SetPoint)
+			}
+			if metrics.PowerWatts != nil {
+				reading.ConsumedWatts = metrics.PowerWatts.Reading
+			}
+			return powerCapTarget{path: path, source: PowerSourceEnvironmentMetrics, reading: reading}, nil
+		}
+	}
+
+	if chassis.Power != nil && strings.TrimSpace(chassis.Power.ODataID) != "" {
+		path := strings.TrimSpace(chassis.Power.ODataID)
+		var power powerResource
+		if err := c.raw.get(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, &power); err != nil {
+			return powerCapTarget{}, fmt.Errorf("reading Redfish Power: %w", err)
+		}
+		if len(power.PowerControl) > 0 {
+			control := power.PowerControl[0]
+			reading := PowerReading{
+				ConsumedWatts: control.PowerConsumedWatts,
+				MaxLimitWatts: roundWatts(control.PowerCapacityWatts),
+				Source:        PowerSourcePower,
+			}
+			if control.PowerLimit != nil {
+				reading.LimitWatts = roundWatts(control.PowerLimit.LimitInWatts)
+			}
+			return powerCapTarget{path: path, source: PowerSourcePower, reading: reading}, nil
+		}
+	}
+
+	return powerCapTarget{}, fmt.Errorf("%w: chassis %s exposes no power limit", ErrPowerCapUnsupported, chassisPath)
+}
+
+func (c *RedfishPowerCapper) client(insecureSkipVerify bool) RedfishAPI {
+	cfg := c.baseConfig
+	cfg.InsecureSkipVerify = insecureSkipVerify
+	return sharedredfish.New(cfg)
+}
+
+func roundWatts(v *float64) *int {
+	if v == nil {
+		return nil
+	}
+	rounded := int(math.Round(*v))
+	return &rounded
+}
@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+type powerCapBMC struct {
+	mu      sync.Mutex
+	chassis map[string]any
+	patches map[string]map[string]any
+	routes  map[string]any
+}
+
+func newPowerCapBMC(chassis map[string]any, routes map[string]any) *powerCapBMC {
+	return &powerCapBMC{chassis: chassis, routes: routes, patches: make(map[string]map[string]any)}
+}
+
+func (b *powerCapBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/redfish/v1/Systems":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Members": []map[string]string{{"@odata.id": "/redfish/v1/Systems/node-a"}},
+		})
+		return
+	case "/redfish/v1/Systems/node-a":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Links": map[string]any{"Chassis": []map[string]string{{"@odata.id": "/redfish/v1/Chassis/1"}}},
+		})
+		return
+	case "/redfish/v1/Chassis/1":
+		_ = json.NewEncoder(w).Encode(b.chassis)
+		return
+	}
+
+	body, ok := b.routes[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method == http.MethodPatch {
+		var patch map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&patch)
+		b.patches[r.URL.Path] = patch
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (b *powerCapBMC) patch(path string) map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.patches[path]
+}
+
+func TestRedfishPowerCapper_EnvironmentMetrics(t *testing.T) {
+	t.Parallel()
+
+	bmc := newPowerCapBMC(
+		map[string]any{
+			"EnvironmentMetrics": map[string]string{"@odata.id": "/redfish/v1/Chassis/1/EnvironmentMetrics"},
+			"Power":              map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Power"},
+		},
+		map[string]any{
+			"/redfish/v1/Chassis/1/EnvironmentMetrics": map[string]any{
+				"PowerWatts": map[string]any{"Reading": 287.4},
+				"PowerLimitWatts": map[string]any{
+					"SetPoint":     500.0,
+					"AllowableMin": 200.0,
+					"AllowableMax": 800.0,
+					"ControlMode":  "Automatic",
+				},
+			},
+		},
+	)
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	capper := NewRedfishPowerCapper(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	req := ExecutionRequest{NodeID: "node-a", Endpoint: server.URL}
+
+	reading, err := capper.ReadPowerCap(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, PowerSourceEnvironmentMetrics, reading.Source)
+	require.NotNil(t, reading.LimitWatts)
+	assert.Equal(t, 500, *reading.LimitWatts)
+	require.NotNil(t, reading.ConsumedWatts)
+	assert.InDelta(t, 287.4, *reading.ConsumedWatts, 0.001)
+	require.NotNil(t, reading.MinLimitWatts)
+	assert.Equal(t, 200, *reading.MinLimitWatts)
+	require.NotNil(t, reading.MaxLimitWatts)
+	assert.Equal(t, 800, *reading.MaxLimitWatts)
+
+	require.NoError(t, capper.ApplyPowerCap(context.Background(), req, 450))
+	assert.Equal(t, map[string]any{
+		"PowerLimitWatts": map[string]any{"SetPoint": 450.0, "ControlMode": "Automatic"},
+	}, bmc.patch("/redfish/v1/Chassis/1/EnvironmentMetrics"))
+
+	require.NoError(t, capper.ApplyPowerCap(context.Background(), req, 0))
+	assert.Equal(t, map[string]any{
+		"PowerLimitWatts": map[string]any{"ControlMode": "Disabled"},
+	}, bmc.patch("/redfish/v1/Chassis/1/EnvironmentMetrics"))
+
+	err = capper.ApplyPowerCap(context.Background(), req, 100)
+	require.ErrorIs(t, err, ErrInvalidPowerCap)
+	assert.Contains(t, err.Error(), "below the BMC minimum of 200 W")
+}
+
+func TestRedfishPowerCapper_FallsBackToPower(t *testing.T) {
+	t.Parallel()
+
+	bmc := newPowerCapBMC(
+		map[string]any{
+			"EnvironmentMetrics": map[string]string{"@odata.id": "/redfish/v1/Chassis/1/EnvironmentMetrics"},
+			"Power":              map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Power"},
+		},
+		map[string]any{
+			// EnvironmentMetrics without PowerLimitWatts cannot carry a cap.
+			"/redfish/v1/Chassis/1/EnvironmentMetrics": map[string]any{
+				"PowerWatts": map[string]any{"Reading": 150.0},
+			},
+			"/redfish/v1/Chassis/1/Power": map[string]any{
+				"PowerControl": []map[string]any{{
+					"PowerConsumedWatts": 180.0,
+					"PowerCapacityWatts": 900.0,
+					"PowerLimit":         map[string]any{"LimitInWatts": nil},
+				}},
+			},
+		},
+	)
+	server := httptest.NewServer(bmc)
+	defer server.Close()
+
+	capper := NewRedfishPowerCapper(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	req := ExecutionRequest{NodeID: "node-a", Endpoint: server.URL}
+
+	reading, err := capper.ReadPowerCap(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, PowerSourcePower, reading.Source)
+	assert.Nil(t, reading.LimitWatts)
+	require.NotNil(t, reading.ConsumedWatts)
+	assert.InDelta(t, 180.0, *reading.ConsumedWatts, 0.001)
+	require.NotNil(t, reading.MaxLimitWatts)
+	assert.Equal(t, 900, *reading.MaxLimitWatts)
+
+	require.NoError(t, capper.ApplyPowerCap(context.Background(), req, 600))
+	assert.Equal(t, map[string]any{
+		"PowerControl": []any{map[string]any{"PowerLimit": map[string]any{"LimitInWatts": 600.0}}},
+	}, bmc.patch("/redfish/v1/Chassis/1/Power"))
+
+	require.NoError(t, capper.ApplyPowerCap(context.Background(), req, 0))
+	assert.Equal(t, map[string]any{
+		"PowerControl": []any{map[string]any{"PowerLimit": map[string]any{"LimitInWatts": nil}}},
+	}, bmc.patch("/redfish/v1/Chassis/1/Power"))
+}
+
+func TestRedfishPowerCapper_UnsupportedChassis(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newPowerCapBMC(map[string]any{"Id": "1"}, nil))
+	defer server.Close()
+
+	capper := NewRedfishPowerCapper(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	_, err := capper.ReadPowerCap(context.Background(), ExecutionRequest{NodeID: "node-a", Endpoint: server.URL})
+	require.ErrorIs(t, err, ErrPowerCapUnsupported)
+}
@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+// ErrTelemetryUnsupported indicates a BMC exposes neither power draw nor
+// inlet temperature for a node.
+var ErrTelemetryUnsupported = errors.New("telemetry is not supported")
+
+// TelemetryReading is one node's instantaneous power draw and inlet
+// temperature. Either value is nil when the BMC does not report it.
+type TelemetryReading struct {
+	PowerWatts       *float64
+	InletTempCelsius *float64
+}
+
+// RedfishTelemetryReader reads node power draw and inlet temperature from the
+// chassis EnvironmentMetrics resource, falling back to the legacy Power and
+// Thermal resources for values EnvironmentMetrics does not carry.
+type RedfishTelemetryReader struct {
+	baseConfig sharedredfish.Config
+	creds      CredentialResolver
+	systems    *SystemPathResolver
+	raw        redfishHTTP
+}
+
+// NewRedfishTelemetryReader creates a telemetry reader backed by Redfish.
+func NewRedfishTelemetryReader(cfg sharedredfish.Config, creds CredentialResolver, systems *SystemPathResolver) *RedfishTelemetryReader {
+	if creds == nil {
+		creds = EmptyCredentialResolver{}
+	}
+	if systems == nil {
+		systems = NewSystemPathResolver()
+	}
+
+	return &RedfishTelemetryReader{
+		baseConfig: cfg,
+		creds:      creds,
+		systems:    systems,
+		raw:        newRedfishHTTP(),
+	}
+}
+
+type thermalResource struct {
+	Temperatures []struct {
+		Name            string   `json:"Name"`
+		PhysicalContext string   `json:"PhysicalContext"`
+		ReadingCelsius  *float64 `json:"ReadingCelsius"`
+	} `json:"Temperatures"`
+}
+
+// ReadTelemetry returns one node's current power draw and inlet temperature.
+func (c *RedfishTelemetryReader) ReadTelemetry(ctx context.Context, req ExecutionRequest) (TelemetryReading, error) {
+	cred, err := c.creds.Resolve(ctx, req.CredentialID)
+	if err != nil {
+		return TelemetryReading{}, fmt.Errorf("resolving credential %q: %w", req.CredentialID, err)
+	}
+
+	var system struct {
+		Links struct {
+			Chassis []redfishLink `json:"Chassis"`
+		} `json:"Links"`
+	}
+	systemPath, err := c.raw.getSystem(ctx, c.systems, c.client(req.InsecureSkipVerify), req, cred, &system)
+	if err != nil {
+		return TelemetryReading{}, err
+	}
+	if len(system.Links.Chassis) == 0 || strings.TrimSpace(system.Links.Chassis[0].ODataID) == "" {
+		return TelemetryReading{}, fmt.Errorf("%w: system %s has no chassis link", ErrTelemetryUnsupported, systemPath)
+	}
+	chassisPath := strings.TrimSpace(system.Links.Chassis[0].ODataID)
+
+	var chassis struct {
+		EnvironmentMetrics *redfishLink `json:"EnvironmentMetrics"`
+		Power              *redfishLink `json:"Power"`
+		Thermal            *redfishLink `json:"Thermal"`
+	}
+	if err := c.raw.get(ctx, req.Endpoint, chassisPath, cred, req.InsecureSkipVerify, &chassis); err != nil {
+		return TelemetryReading{}, fmt.Errorf("reading Redfish chassis: %w", err)
+	}
+
+	var reading TelemetryReading
+	if chassis.EnvironmentMetrics != nil && strings.TrimSpace(chassis.EnvironmentMetrics.ODataID) != "" {
+		var metrics struct {
+			PowerWatts *struct {
+				Reading *float64 `json:"Reading"`
+			} `json:"PowerWatts"`
+			TemperatureCelsius *struct {
+				Reading *float64 `json:"Reading"`
+			} `json:"TemperatureCelsius"`
+		}
+		path := strings.TrimSpace(chassis.EnvironmentMetrics.ODataID)
+		if err := c.raw.get(ctx, req.Endpoint, path, cred, req.InsecureSkipVerify, &metrics); err != nil {
+			return TelemetryReading{}, fmt.Errorf("reading Redfish EnvironmentMetrics: %w", err)
+		}
+		if metrics.PowerWatts != nil {
+			reading.PowerWatts = metrics.PowerWatts.Reading
+		}
+		if metrics.TemperatureCelsius != nil {
+			reading.InletTempCelsius = metrics.TemperatureCelsius.Reading
+		}
+	}
+
+	if reading.PowerWatts == nil && chassis.Power != nil && strings.TrimSpace(chassis.Power.ODataID) != "" {
+		var power powerResource
+		if err := c.raw.get(ctx, req.Endpoint, strings.TrimSpace(chassis.Power.ODataID), cred, req.InsecureSkipVerify, &power); err != nil {
+			return TelemetryReading{}, fmt.Errorf("reading Redfish Power: %w", err)
+		}
+		if len(power.PowerControl) > 0 {
+			reading.PowerWatts = power.PowerControl[0].PowerConsumedWatts
+		}
+	}
+
+	if reading.InletTempCelsius == nil && chassis.Thermal != nil && strings.TrimSpace(chassis.Thermal.ODataID) != "" {
+		var thermal thermalResource
+		if err := c.raw.get(ctx, req.Endpoint, strings.TrimSpace(chassis.Thermal.ODataID), cred, req.InsecureSkipVerify, &thermal); err != nil {
+			return TelemetryReading{}, fmt.Errorf("reading Redfish Thermal: %w", err)
+		}
+		reading.InletTempCelsius = inletTemperature(thermal)
+	}
+
+	if reading.PowerWatts == nil && reading.InletTempCelsius == nil {
+		return TelemetryReading{}, fmt.Errorf("%w: chassis %s reports no power or inlet temperature", ErrTelemetryUnsupported, chassisPath)
+	}
+	return reading, nil
+}
+
+// inletTemperature picks the intake sensor, identified by PhysicalContext or,
+// on BMCs that leave it unset, by an "Inlet" sensor name.
+func inletTemperature(thermal thermalResource) *float64 {
+	for _, sensor := range thermal.Temperatures {
+		if sensor.ReadingCelsius != nil && strings.EqualFold(strings.TrimSpace(sensor.PhysicalContext), "Intake") {
+			return sensor.ReadingCelsius
+		}
+	}
+	for _, sensor := range thermal.Temperatures {
+		if sensor.ReadingCelsius != nil && strings.Contains(strings.ToLower(sensor.Name), "inlet") {
+			return sensor.ReadingCelsius
+		}
+	}
+	return nil
+}
+
+func (c *RedfishTelemetryReader) client(insecureSkipVerify bool) RedfishAPI {
+	cfg := c.baseConfig
+	cfg.InsecureSkipVerify = insecureSkipVerify
+	return sharedredfish.New(cfg)
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedredfish "git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+func TestRedfishTelemetryReader_EnvironmentMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"Links": map[string]any{"Chassis": []map[string]string{{"@odata.id": "/redfish/v1/Chassis/1"}}},
+		},
+		"/redfish/v1/Chassis/1": {
+			"EnvironmentMetrics": map[string]string{"@odata.id": "/redfish/v1/Chassis/1/EnvironmentMetrics"},
+			"Power":              map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Power"},
+		},
+		"/redfish/v1/Chassis/1/EnvironmentMetrics": {
+			"PowerWatts":         map[string]any{"Reading": 412.5},
+			"TemperatureCelsius": map[string]any{"Reading": 23.0},
+		},
+	}))
+	defer server.Close()
+
+	reader := NewRedfishTelemetryReader(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	reading, err := reader.ReadTelemetry(context.Background(), ExecutionRequest{NodeID: "node-a", Endpoint: server.URL})
+	require.NoError(t, err)
+	require.NotNil(t, reading.PowerWatts)
+	assert.InDelta(t, 412.5, *reading.PowerWatts, 0.001)
+	require.NotNil(t, reading.InletTempCelsius)
+	assert.InDelta(t, 23.0, *reading.InletTempCelsius, 0.001)
+}
+
+func TestRedfishTelemetryReader_LegacyPowerAndThermal(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"Links": map[string]any{"Chassis": []map[string]string{{"@odata.id": "/redfish/v1/Chassis/1"}}},
+		},
+		"/redfish/v1/Chassis/1": {
+			"Power":   map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Power"},
+			"Thermal": map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Thermal"},
+		},
+		"/redfish/v1/Chassis/1/Power": {
+			"PowerControl": []map[string]any{{"PowerConsumedWatts": 298.0}},
+		},
+		"/redfish/v1/Chassis/1/Thermal": {
+			"Temperatures": []map[string]any{
+				{"Name": "CPU1 Temp", "PhysicalContext": "CPU", "ReadingCelsius": 61.0},
+				{"Name": "Inlet Temp", "ReadingCelsius": 21.5},
+			},
+		},
+	}))
+	defer server.Close()
+
+	reader := NewRedfishTelemetryReader(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	reading, err := reader.ReadTelemetry(context.Background(), ExecutionRequest{NodeID: "node-a", Endpoint: server.URL})
+	require.NoError(t, err)
+	require.NotNil(t, reading.PowerWatts)
+	assert.InDelta(t, 298.0, *reading.PowerWatts, 0.001)
+	require.NotNil(t, reading.InletTempCelsius)
+	assert.InDelta(t, 21.5, *reading.InletTempCelsius, 0.001)
+}
+
+func TestRedfishTelemetryReader_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newBootControlBMC(map[string]map[string]any{
+		"/redfish/v1/Systems/node-a": {
+			"Links": map[string]any{"Chassis": []map[string]string{{"@odata.id": "/redfish/v1/Chassis/1"}}},
+		},
+		"/redfish/v1/Chassis/1": {"Id": "1"},
+	}))
+	defer server.Close()
+
+	reader := NewRedfishTelemetryReader(sharedredfish.Config{MaxAttempts: 1}, nil, nil)
+	_, err := reader.ReadTelemetry(context.Background(), ExecutionRequest{NodeID: "node-a", Endpoint: server.URL})
+	require.ErrorIs(t, err, ErrTelemetryUnsupported)
+}
@@ -13,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rs/zerolog"
+
 	"git.cscs.ch/openchami/chamicore-lib/redfish"
 	"git.cscs.ch/openchami/chamicore-power/internal/model"
 )
@@ -24,6 +26,10 @@ const (
 	defaultRetryBackoffBase  = 250 * time.Millisecond
 	defaultRetryBackoffMax   = 5 * time.Second
 	defaultTransitionTimeout = 90 * time.Second
+	// defaultTaskDuration estimates how long a task takes before any task
+	// finished; maxQueueRetryAfter caps the Retry-After of ErrQueueFull.
+	defaultTaskDuration = 15 * time.Second
+	maxQueueRetryAfter  = 5 * time.Minute
 )
 
 var (
@@ -33,8 +39,40 @@ var (
 	ErrNoTargetNodes = errors.New("at least one node is required")
 	// ErrTransitionNotFound indicates no active transition exists with the provided ID.
 	ErrTransitionNotFound = errors.New("transition not found")
+	// ErrTaskNotActive indicates the task of a node is not pending or running.
+	ErrTaskNotActive = errors.New("task is not pending or running")
+	// ErrQueueFull indicates the task queue is full; see QueueFullError.
+	ErrQueueFull = errors.New("transition queue is full")
 )
 
+// QueueFullError rejects a transition while too many tasks are queued.
+type QueueFullError struct {
+	Depth int
+	Limit int
+	// RetryAfter estimates how long the workers need to drain the queue
+	// below Limit.
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("%s: %d tasks queued, limit %d", ErrQueueFull, e.Depth, e.Limit)
+}
+
+func (e *QueueFullError) Unwrap() error {
+	return ErrQueueFull
+}
+
+// QueueStatus reports the task queue of a runner.
+type QueueStatus struct {
+	Depth int
+	Limit int
+	// ByPriority counts queued tasks per priority class.
+	ByPriority map[string]int
+	// AvgTaskDuration is the moving average time workers spend on a task,
+	// or an estimate until a task finished.
+	AvgTaskDuration time.Duration
+}
+
 // RetryableError wraps an error that should be retried by policy.
 type RetryableError struct {
 	Err error
@@ -93,6 +131,11 @@ const (
 	TransitionStatePartial   = "partial"
 	TransitionStateCanceled  = "canceled"
 	TransitionStatePlanned   = "planned"
+	// TransitionStatePendingApproval holds a transition until a second
+	// principal approves it; rejected and expired end it without running.
+	TransitionStatePendingApproval = "pending-approval"
+	TransitionStateRejected        = "rejected"
+	TransitionStateExpired         = "expired"
 )
 
 // TaskState values.
@@ -116,6 +159,24 @@ type Transition struct {
 	TargetCount  int
 	SuccessCount int
 	FailureCount int
+	// BatchSize, ChunkSize and EscalateAfter record the execution policy of
+	// the transition.
+	BatchSize       int
+	ChunkSize       int
+	EscalateAfter   time.Duration
+	TemplateName    string
+	TemplateVersion int
+	// ApprovalReason and ApprovalExpiresAt are set on transitions that were
+	// held for approval.
+	ApprovalReason    string
+	ApprovalExpiresAt *time.Time
+	// ParentID links a retry to the transition whose failed tasks it re-runs.
+	ParentID string
+	// Exclusions records the targets the request excluded.
+	Exclusions *Exclusions
+	// PCSOperation is the operation name a PCS client requested; it is empty
+	// for transitions created through the native API.
+	PCSOperation string
 	QueuedAt     time.Time
 	StartedAt    *time.Time
 	CompletedAt  *time.Time
@@ -138,11 +199,22 @@ type Task struct {
 	AttemptCount       int
 	FinalPowerState    string
 	ErrorDetail        string
-	QueuedAt           time.Time
-	StartedAt          *time.Time
-	CompletedAt        *time.Time
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	BootTarget         string
+	BootWaitReady      bool
+	BootStage          string
+	BootStages         []BootStage
+	PowerCapWatts      *int
+	BootOverrideTarget string
+	BootOverrideMode   string
+	MediaImage         string
+	ResetOperation     string
+	// Escalated reports that the graceful reset timed out and was forced.
+	Escalated   bool
+	QueuedAt    time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // StartRequest describes one transition request.
@@ -152,6 +224,56 @@ type StartRequest struct {
 	Operation   string
 	NodeIDs     []string
 	DryRun      bool
+	BootWait    *BootWait
+	// PowerCapWatts is the limit for PowerCap transitions; zero removes it.
+	PowerCapWatts *int
+	// BootOverride is set by SetBootOverride and BootOverrideReset transitions.
+	BootOverride *BootOverride
+	// VirtualMedia is inserted by InsertMedia and, optionally, BootOverrideReset.
+	VirtualMedia *VirtualMedia
+	// ResetOperation is the reset issued by BootOverrideReset; defaults to ForceRestart.
+	ResetOperation string
+	// BatchSize caps how many tasks of the transition run at once; zero means
+	// no cap beyond the global and per-BMC limits.
+	BatchSize int
+	// ChunkSize splits a large transition into chunks of this many tasks; a
+	// chunk starts once every task of the previous one finished. Zero runs
+	// all tasks as one chunk.
+	ChunkSize int
+	// Escalation forces GracefulShutdown and GracefulRestart tasks that have
+	// not completed in time.
+	Escalation *Escalation
+	// TemplateName and TemplateVersion link a transition to the template it
+	// was instantiated from.
+	TemplateName    string
+	TemplateVersion int
+	// Approval, when set, creates the transition in pending-approval instead
+	// of enqueueing it. Dry runs ignore it.
+	Approval *ApprovalRequirement
+	// ParentID links a retry transition to the one it retries.
+	ParentID string
+	// Exclusions is recorded on the transition for audit; NodeIDs already
+	// has the excluded nodes removed.
+	Exclusions *Exclusions
+	// PCSOperation records the PCS operation name, which several native
+	// operations cannot be mapped back to unambiguously.
+	PCSOperation string
+}
+
+// Exclusions records what a request excluded from its targets. It is
+// persisted as JSON, so field tags are part of the storage format.
+type Exclusions struct {
+	Nodes  []string `json:"nodes,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Excluded lists the resolved targets that were dropped.
+	Excluded []string `json:"excluded,omitempty"`
+}
+
+// Escalation issues the forced counterpart of a graceful reset (ForceOff or
+// ForceRestart) when the node has not reached the expected state After the
+// graceful request.
+type Escalation struct {
+	After time.Duration
 }
 
 // ExecutionRequest is passed to executor/verification backends.
@@ -184,6 +306,18 @@ type NodeStateUpdater interface {
 	UpdateNodePowerState(ctx context.Context, nodeID, powerState string) error
 }
 
+// NodeFailureRecorder is implemented by state updaters that also mark nodes
+// whose power action failed, could not be verified, or was interrupted.
+type NodeFailureRecorder interface {
+	MarkNodePowerFailure(ctx context.Context, nodeID string) error
+}
+
+// NodeTransitionRecorder is implemented by state updaters that also record
+// the in-progress state of a node while its power action runs.
+type NodeTransitionRecorder interface {
+	MarkNodeTransitioning(ctx context.Context, nodeID, operation string) error
+}
+
 // Config controls execution policy.
 type Config struct {
 	GlobalConcurrency  int
@@ -194,7 +328,18 @@ type Config struct {
 	TransitionDeadline time.Duration
 	VerificationWindow time.Duration
 	VerificationPoll   time.Duration
-	QueueSize          int
+	BootWaitTimeout    time.Duration
+	BootWaitPoll       time.Duration
+	// QueueSize is the number of queued tasks at which new transitions are
+	// rejected with a QueueFullError. Tasks of admitted transitions are
+	// always queued, so the queue may briefly hold more. Defaults to four
+	// times GlobalConcurrency.
+	QueueSize int
+	// Priorities maps operations to priority classes; nil uses
+	// DefaultPriorities.
+	Priorities map[string]string
+	// ApprovalSweepInterval is how often expired approvals are closed.
+	ApprovalSweepInterval time.Duration
 }
 
 type runtimeConfig struct {
@@ -204,7 +349,11 @@ type runtimeConfig struct {
 	retryBackoffBase   time.Duration
 	retryBackoffMax    time.Duration
 	transitionDeadline time.Duration
+	bootWaitTimeout    time.Duration
+	bootWaitPoll       time.Duration
 	queueSize          int
+	priorities         map[string]string
+	approvalSweep      time.Duration
 	now                func() time.Time
 	sleep              func(context.Context, time.Duration) error
 	jitter             func(time.Duration) time.Duration
@@ -218,25 +367,53 @@ type transitionProgress struct {
 	started         bool
 	aborted         bool
 	cancel          context.CancelFunc
+	// taskCancels cancels the pending or running task of a node.
+	taskCancels map[string]context.CancelFunc
+	// backlog holds tasks held back by the batch or chunk size; see release.
+	backlog []queuedTask
+	// inFlight counts released tasks that have not finished; chunkLeft counts
+	// tasks of the current chunk still in the backlog.
+	inFlight  int
+	chunkLeft int
+}
+
+// operationPlan is the validated form of one StartRequest operation.
+type operationPlan struct {
+	name string
+	// reset is the Redfish reset the tasks issue; empty for setting-only operations.
+	reset redfish.ResetOperation
+	// template carries the operation fields copied onto every task.
+	template      Task
+	batchSize     int
+	chunkSize     int
+	escalateAfter time.Duration
 }
 
 type queuedTask struct {
-	operation    redfish.ResetOperation
-	transitionID string
-	executionCtx context.Context
-	task         Task
+	operation     redfish.ResetOperation
+	escalateAfter time.Duration
+	transitionID  string
+	executionCtx  context.Context
+	task          Task
 }
 
 // Runner executes transition tasks asynchronously with configured limits.
 type Runner struct {
-	store    Store
-	executor Executor
-	verifier *Verifier
-	updater  NodeStateUpdater
-	cfg      runtimeConfig
-	queue    *Queue
+	store        Store
+	executor     Executor
+	verifier     *Verifier
+	systemReader SystemStateReader
+	updater      NodeStateUpdater
+	readiness    NodeReadinessTracker
+	capper       PowerCapper
+	booter       BootController
+	cfg          runtimeConfig
+	queue        *Queue
+	log          zerolog.Logger
 
 	startOnce sync.Once
+	// bootTrackers counts boot-tracking goroutines still polling BMCs.
+	bootTrackers sync.WaitGroup
 
 	runMu      sync.RWMutex
 	runningCtx context.Context
@@ -246,11 +423,21 @@ type Runner struct {
 
 	bmcMu       sync.Mutex
 	bmcLimiters map[string]chan struct{}
+
+	statsMu         sync.Mutex
+	avgTaskDuration time.Duration
 }
 
 // Option customizes runner dependencies.
 type Option func(*Runner)
 
+// WithLogger sets the logger for failures the runner cannot report on a task.
+func WithLogger(logger zerolog.Logger) Option {
+	return func(r *Runner) {
+		r.log = logger
+	}
+}
+
 // WithNodeStateUpdater sets the SMD updater used on successful task outcomes.
 func WithNodeStateUpdater(updater NodeStateUpdater) Option {
 	return func(r *Runner) {
@@ -267,10 +454,14 @@ func New(st Store, executor Executor, reader PowerStateReader, cfg Config, opts
 		executor:    executor,
 		verifier:    NewVerifier(reader, VerifyConfig{Window: cfg.VerificationWindow, PollInterval: cfg.VerificationPoll}),
 		cfg:         normalized,
-		queue:       newQueue(normalized.queueSize),
+		queue:       newQueue(normalized.priorities),
+		log:         zerolog.Nop(),
 		progress:    make(map[string]*transitionProgress),
 		bmcLimiters: make(map[string]chan struct{}),
 	}
+	if systemReader, ok := reader.(SystemStateReader); ok {
+		runner.systemReader = systemReader
+	}
 	for _, opt := range opts {
 		opt(runner)
 	}
@@ -288,24 +479,43 @@ func (r *Runner) Start(ctx context.Context) {
 			<-ctx.Done()
 			r.queue.close()
 		}()
+		if approvals, ok := r.store.(ApprovalStore); ok {
+			go r.sweepApprovals(ctx, approvals)
+		}
 	})
 }
 
+// Wait blocks until background boot tracking has stopped. Call it after
+// canceling the context passed to Start.
+func (r *Runner) Wait() {
+	r.bootTrackers.Wait()
+}
+
 // StartTransition persists and enqueues a transition request.
 func (r *Runner) StartTransition(ctx context.Context, req StartRequest) (Transition, error) {
 	if !r.isRunning() {
 		return Transition{}, ErrRunnerNotStarted
 	}
 
-	operation, err := redfish.ParseResetOperation(req.Operation)
+	plan, err := r.planOperation(req)
 	if err != nil {
-		return Transition{}, fmt.Errorf("parsing operation: %w", err)
+		return Transition{}, err
+	}
+	if err := applyPolicy(&plan, req); err != nil {
+		return Transition{}, err
 	}
 
 	nodeIDs := normalizeNodeIDs(req.NodeIDs)
 	if len(nodeIDs) == 0 {
 		return Transition{}, ErrNoTargetNodes
 	}
+	if !req.DryRun && req.Approval == nil {
+		release, err := r.admit()
+		if err != nil {
+			return Transition{}, err
+		}
+		defer release()
+	}
 
 	mappings, missing, err := r.store.ResolveNodeMappings(ctx, nodeIDs)
 	if err != nil {
@@ -324,29 +534,35 @@ func (r *Runner) StartTransition(ctx context.Context, req StartRequest) (Transit
 
 	now := r.cfg.now().UTC()
 	transition := Transition{
-		RequestID:   strings.TrimSpace(req.RequestID),
-		Operation:   string(operation),
-		State:       TransitionStatePending,
-		RequestedBy: strings.TrimSpace(req.RequestedBy),
-		DryRun:      req.DryRun,
-		TargetCount: len(nodeIDs),
-		QueuedAt:    now,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		RequestID:       strings.TrimSpace(req.RequestID),
+		Operation:       plan.name,
+		State:           TransitionStatePending,
+		RequestedBy:     strings.TrimSpace(req.RequestedBy),
+		DryRun:          req.DryRun,
+		TargetCount:     len(nodeIDs),
+		BatchSize:       plan.batchSize,
+		ChunkSize:       plan.chunkSize,
+		EscalateAfter:   plan.escalateAfter,
+		TemplateName:    strings.TrimSpace(req.TemplateName),
+		TemplateVersion: req.TemplateVersion,
+		ParentID:        strings.TrimSpace(req.ParentID),
+		Exclusions:      req.Exclusions,
+		PCSOperation:    strings.TrimSpace(req.PCSOperation),
+		QueuedAt:        now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
 	tasks := make([]Task, 0, len(nodeIDs))
 	pendingCount := 0
 	for _, nodeID := range nodeIDs {
-		task := Task{
-			TransitionID: "",
-			NodeID:       nodeID,
-			Operation:    string(operation),
-			DryRun:       req.DryRun,
-			QueuedAt:     now,
-			CreatedAt:    now,
-			UpdatedAt:    now,
-		}
+		task := plan.template
+		task.NodeID = nodeID
+		task.Operation = plan.name
+		task.DryRun = req.DryRun
+		task.QueuedAt = now
+		task.CreatedAt = now
+		task.UpdatedAt = now
 
 		if missingErr, missing := missingByNode[nodeID]; missing {
 			completedAt := now
@@ -393,6 +609,11 @@ func (r *Runner) StartTransition(ctx context.Context, req StartRequest) (Transit
 		completedAt := now
 		transition.State = TransitionStateFailed
 		transition.CompletedAt = &completedAt
+	} else if req.Approval != nil {
+		expiresAt := now.Add(req.Approval.TTL)
+		transition.State = TransitionStatePendingApproval
+		transition.ApprovalReason = strings.TrimSpace(req.Approval.Reason)
+		transition.ApprovalExpiresAt = &expiresAt
 	}
 
 	createdTransition, createdTasks, err := r.store.CreateTransition(ctx, transition, tasks)
@@ -408,6 +629,9 @@ func (r *Runner) StartTransition(ctx context.Context, req StartRequest) (Transit
 		}
 		return updatedTransition, nil
 	}
+	if createdTransition.State == TransitionStatePendingApproval {
+		return createdTransition, nil
+	}
 
 	pendingTasks := make([]Task, 0, pendingCount)
 	for _, task := range createdTasks {
@@ -416,30 +640,75 @@ func (r *Runner) StartTransition(ctx context.Context, req StartRequest) (Transit
 		}
 	}
 
+	if err := r.enqueueTransition(createdTransition, pendingTasks, plan.reset); err != nil {
+		return Transition{}, err
+	}
+	return createdTransition, nil
+}
+
+// enqueueTransition tracks progress for a transition and enqueues its pending
+// tasks, holding back those beyond the transition batch and chunk size.
+func (r *Runner) enqueueTransition(
+	transition Transition,
+	pendingTasks []Task,
+	reset redfish.ResetOperation,
+) error {
 	transitionExecCtx, cancelTransition := context.WithCancel(r.runningContext())
 
-	r.progressMu.Lock()
-	r.progress[createdTransition.ID] = &transitionProgress{
-		transition:      createdTransition,
+	items := make([]queuedTask, 0, len(pendingTasks))
+	taskCancels := make(map[string]context.CancelFunc, len(pendingTasks))
+	for _, task := range pendingTasks {
+		taskExecCtx, cancelTask := context.WithCancel(transitionExecCtx)
+		taskCancels[task.NodeID] = cancelTask
+		items = append(items, queuedTask{
+			operation:     reset,
+			escalateAfter: transition.EscalateAfter,
+			transitionID:  transition.ID,
+			executionCtx:  taskExecCtx,
+			task:          task,
+		})
+	}
+	progress := &transitionProgress{
+		transition:      transition,
 		executableTotal: len(pendingTasks),
 		remaining:       len(pendingTasks),
 		cancel:          cancelTransition,
+		taskCancels:     taskCancels,
+		backlog:         items,
 	}
+
+	r.progressMu.Lock()
+	r.progress[transition.ID] = progress
+	released := progress.release()
 	r.progressMu.Unlock()
 
-	for _, task := range pendingTasks {
-		if enqueueErr := r.queue.enqueue(ctx, queuedTask{
-			operation:    operation,
-			transitionID: createdTransition.ID,
-			executionCtx: transitionExecCtx,
-			task:         task,
-		}); enqueueErr != nil {
+	for _, item := range released {
+		if enqueueErr := r.queue.enqueue(item); enqueueErr != nil {
 			cancelTransition()
-			return Transition{}, fmt.Errorf("enqueueing transition task: %w", enqueueErr)
+			return fmt.Errorf("enqueueing transition task: %w", enqueueErr)
 		}
 	}
+	return nil
+}
+
+// planOperation validates the operation of a request and the parameters it needs.
+func (r *Runner) planOperation(req StartRequest) (operationPlan, error) {
+	switch {
+	case isPowerCapOperation(req.Operation):
+		return r.planPowerCap(req)
+	case isBootControlOperation(req.Operation):
+		return r.planBootControl(req)
+	}
 
-	return createdTransition, nil
+	parsed, err := redfish.ParseResetOperation(req.Operation)
+	if err != nil {
+		return operationPlan{}, fmt.Errorf("parsing operation: %w", err)
+	}
+	plan := operationPlan{name: string(parsed), reset: parsed}
+	if err := r.applyBootWait(&plan, req.BootWait); err != nil {
+		return operationPlan{}, err
+	}
+	return plan, nil
 }
 
 // AbortTransition requests cancellation for an active transition.
@@ -485,6 +754,61 @@ func (r *Runner) AbortTransition(ctx context.Context, transitionID string) error
 	return nil
 }
 
+// AbortTask cancels the pending or running task of one node in an active
+// transition. Tasks still held back by the batch size are canceled right
+// away; queued and running ones end as canceled once their worker notices.
+func (r *Runner) AbortTask(ctx context.Context, transitionID, nodeID string) error {
+	id := strings.TrimSpace(transitionID)
+	node := strings.TrimSpace(nodeID)
+	if id == "" {
+		return ErrTransitionNotFound
+	}
+
+	r.progressMu.Lock()
+	progress, ok := r.progress[id]
+	if !ok {
+		r.progressMu.Unlock()
+		return ErrTransitionNotFound
+	}
+	cancelTask, ok := progress.taskCancels[node]
+	if !ok {
+		r.progressMu.Unlock()
+		return ErrTaskNotActive
+	}
+	delete(progress.taskCancels, node)
+	cancelTask()
+
+	var held *queuedTask
+	for i := range progress.backlog {
+		if progress.backlog[i].task.NodeID == node {
+			item := progress.backlog[i]
+			held = &item
+			progress.backlog = append(progress.backlog[:i], progress.backlog[i+1:]...)
+			if i < progress.chunkLeft {
+				progress.chunkLeft--
+			}
+			break
+		}
+	}
+	r.progressMu.Unlock()
+
+	if held == nil {
+		return nil
+	}
+
+	now := r.cfg.now().UTC()
+	task := held.task
+	task.State = TaskStateCanceled
+	task.ErrorDetail = context.Canceled.Error()
+	task.CompletedAt = &now
+	task.UpdatedAt = now
+	if _, err := r.store.UpdateTransitionTask(ctx, task); err != nil {
+		return fmt.Errorf("marking task canceled: %w", err)
+	}
+	r.recordTaskOutcome(ctx, id, node, TaskStateCanceled, false)
+	return nil
+}
+
 func (r *Runner) worker(ctx context.Context) {
 	for {
 		item, err := r.queue.dequeue(ctx)
@@ -494,7 +818,66 @@ func (r *Runner) worker(ctx context.Context) {
 			}
 			continue
 		}
+		startedAt := r.cfg.now()
 		r.executeTask(ctx, item)
+		r.observeTaskDuration(r.cfg.now().Sub(startedAt))
+	}
+}
+
+// admit rejects new transitions while the queue is full. An admitted
+// transition holds a queue slot until the returned release is called, which
+// it does once its tasks are enqueued, so concurrent admissions cannot all
+// pass the same check.
+func (r *Runner) admit() (func(), error) {
+	depth, ok := r.queue.reserve(r.cfg.queueSize)
+	if ok {
+		return r.queue.unreserve, nil
+	}
+	return nil, &QueueFullError{
+		Depth:      depth,
+		Limit:      r.cfg.queueSize,
+		RetryAfter: r.drainEstimate(depth - r.cfg.queueSize + 1),
+	}
+}
+
+// drainEstimate estimates how long the workers need to work off the given
+// number of queued tasks, at one task per worker per average task duration.
+func (r *Runner) drainEstimate(tasks int) time.Duration {
+	rounds := (tasks + r.cfg.globalConcurrency - 1) / r.cfg.globalConcurrency
+	estimate := time.Duration(rounds) * r.taskDuration()
+	return min(max(estimate, time.Second), maxQueueRetryAfter)
+}
+
+// observeTaskDuration folds the time a worker spent on one task into the
+// moving average.
+func (r *Runner) observeTaskDuration(elapsed time.Duration) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	if r.avgTaskDuration == 0 {
+		r.avgTaskDuration = elapsed
+		return
+	}
+	r.avgTaskDuration += (elapsed - r.avgTaskDuration) / 10
+}
+
+func (r *Runner) taskDuration() time.Duration {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	if r.avgTaskDuration <= 0 {
+		return defaultTaskDuration
+	}
+	return r.avgTaskDuration
+}
+
+// QueueStatus reports the depth of the task queue.
+func (r *Runner) QueueStatus() QueueStatus {
+	return QueueStatus{
+		Depth:           r.queue.depth(),
+		Limit:           r.cfg.queueSize,
+		ByPriority:      r.queue.depthByClass(),
+		AvgTaskDuration: r.taskDuration(),
 	}
 }
 
@@ -534,7 +917,9 @@ func (r *Runner) executeTask(ctx context.Context, item queuedTask) {
 	execCtx := baseExecCtx
 	cancelExec := func() {}
 	if r.cfg.transitionDeadline > 0 {
-		execCtx, cancelExec = context.WithTimeout(baseExecCtx, r.cfg.transitionDeadline)
+		// An escalating task may wait out its grace period before the
+		// forced reset gets the usual deadline.
+		execCtx, cancelExec = context.WithTimeout(baseExecCtx, r.cfg.transitionDeadline+item.escalateAfter)
 	}
 	defer cancelExec()
 
@@ -549,26 +934,77 @@ func (r *Runner) executeTask(ctx context.Context, item queuedTask) {
 		Operation:          item.operation,
 	}
 
+	switch task.Operation {
+	case OperationPowerCap:
+		r.executePowerCap(ctx, execCtx, item.transitionID, task, executionRequest)
+		return
+	case OperationSetBootOverride, OperationInsertMedia, OperationEjectMedia:
+		r.executeBootControl(ctx, execCtx, item.transitionID, task, executionRequest)
+		return
+	}
+
+	prepAttempts := 0
+	if task.Operation == OperationBootOverrideReset {
+		var prepErr error
+		prepAttempts, prepErr = r.applyBootSettings(execCtx, task, executionRequest)
+		if prepErr != nil {
+			r.completeTask(ctx, item.transitionID, task, prepAttempts, "", prepErr)
+			return
+		}
+	}
+
+	r.markNodeTransitioning(execCtx, task)
+
+	baseline := r.verifier.Baseline(execCtx, executionRequest)
+
 	attempts, execErr := r.executeWithRetry(execCtx, executionRequest)
+	attempts += prepAttempts
 	if execErr != nil {
 		r.completeTask(ctx, item.transitionID, task, attempts, "", execErr)
 		return
 	}
 
-	finalPowerState, verifyErr := r.verifier.Verify(execCtx, executionRequest)
+	var (
+		finalPowerState string
+		verifyErr       error
+	)
+	if item.escalateAfter > 0 {
+		var escalationAttempts int
+		escalationAttempts, finalPowerState, verifyErr = r.verifyWithEscalation(execCtx, &task, executionRequest, baseline, item.escalateAfter)
+		attempts += escalationAttempts
+	} else {
+		finalPowerState, verifyErr = r.verifier.Verify(execCtx, executionRequest, baseline)
+	}
 	if verifyErr != nil {
 		r.completeTask(ctx, item.transitionID, task, attempts, finalPowerState, verifyErr)
 		return
 	}
 
+	if hasBootWait(task) {
+		task.FinalPowerState = strings.TrimSpace(finalPowerState)
+		r.bootTrackers.Add(1)
+		go func() {
+			defer r.bootTrackers.Done()
+			r.trackBoot(ctx, baseExecCtx, item.transitionID, task, attempts, executionRequest, finalPowerState)
+		}()
+		return
+	}
+
 	r.completeTask(ctx, item.transitionID, task, attempts, finalPowerState, nil)
 }
 
 func (r *Runner) executeWithRetry(ctx context.Context, req ExecutionRequest) (int, error) {
+	return r.withRetry(ctx, func(ctx context.Context) error {
+		return r.executor.ExecutePowerAction(ctx, req)
+	})
+}
+
+// withRetry runs one BMC call under the configured retry and backoff policy.
+func (r *Runner) withRetry(ctx context.Context, call func(context.Context) error) (int, error) {
 	attempts := 0
 	for attempt := 1; attempt <= r.cfg.retryAttempts; attempt++ {
 		attempts = attempt
-		err := r.executor.ExecutePowerAction(ctx, req)
+		err := call(ctx)
 		if err == nil {
 			return attempts, nil
 		}
@@ -630,7 +1066,14 @@ func (r *Runner) completeTask(
 		default:
 			outcomeState = TaskStateFailed
 		}
-	} else if !task.DryRun && r.updater != nil {
+		// An aborted action says nothing about the node, so only failures
+		// are flagged in SMD.
+		if outcomeState == TaskStateFailed {
+			if err := r.markNodeFailed(ctx, task); err != nil {
+				task.ErrorDetail = strings.TrimSpace(fmt.Sprintf("%s; updating SMD state: %v", task.ErrorDetail, err))
+			}
+		}
+	} else if !task.DryRun && r.updater != nil && changesPowerState(task.Operation) {
 		if err := r.updater.UpdateNodePowerState(ctx, task.NodeID, task.FinalPowerState); err != nil {
 			task.ErrorDetail = strings.TrimSpace(fmt.Sprintf("updating SMD state: %v", err))
 			outcomeState = TaskStateFailed
@@ -643,7 +1086,37 @@ func (r *Runner) completeTask(
 		task = updatedTask
 	}
 
-	r.recordTaskOutcome(ctx, transitionID, task.State)
+	r.recordTaskOutcome(ctx, transitionID, task.NodeID, task.State, true)
+}
+
+// markNodeTransitioning records the in-progress SMD state of a node. It is
+// best effort: the final outcome update overwrites it either way.
+func (r *Runner) markNodeTransitioning(ctx context.Context, task Task) {
+	if task.DryRun {
+		return
+	}
+	recorder, ok := r.updater.(NodeTransitionRecorder)
+	if !ok {
+		return
+	}
+	operation := task.Operation
+	if task.ResetOperation != "" {
+		operation = task.ResetOperation
+	}
+	_ = recorder.MarkNodeTransitioning(ctx, task.NodeID, operation)
+}
+
+// markNodeFailed flags a node in SMD after its power action was attempted and
+// did not reach a verified state. Tasks that never started leave SMD untouched.
+func (r *Runner) markNodeFailed(ctx context.Context, task Task) error {
+	if task.DryRun || task.StartedAt == nil || !changesPowerState(task.Operation) {
+		return nil
+	}
+	recorder, ok := r.updater.(NodeFailureRecorder)
+	if !ok {
+		return nil
+	}
+	return recorder.MarkNodePowerFailure(ctx, task.NodeID)
 }
 
 func (r *Runner) markTransitionRunning(ctx context.Context, transitionID string) error {
@@ -682,7 +1155,10 @@ func (r *Runner) markTransitionRunning(ctx context.Context, transitionID string)
 	return nil
 }
 
-func (r *Runner) recordTaskOutcome(ctx context.Context, transitionID, taskState string) {
+// recordTaskOutcome counts a finished task, enqueues the held-back tasks it
+// makes room for, and persists the transition once all tasks are done.
+// released is false for tasks canceled while still in the backlog.
+func (r *Runner) recordTaskOutcome(ctx context.Context, transitionID, nodeID, taskState string, released bool) {
 	var transitionToPersist Transition
 	persist := false
 
@@ -703,7 +1179,16 @@ func (r *Runner) recordTaskOutcome(ctx context.Context, transitionID, taskState
 		progress.transition.FailureCount++
 	}
 
+	if cancelTask, ok := progress.taskCancels[nodeID]; ok {
+		cancelTask()
+		delete(progress.taskCancels, nodeID)
+	}
+
 	progress.remaining--
+	if released {
+		progress.inFlight--
+	}
+	next := progress.release()
 	if progress.remaining <= 0 {
 		completedAt := r.cfg.now().UTC()
 		progress.transition.CompletedAt = &completedAt
@@ -718,6 +1203,10 @@ func (r *Runner) recordTaskOutcome(ctx context.Context, transitionID, taskState
 	}
 	r.progressMu.Unlock()
 
+	for _, item := range next {
+		_ = r.queue.enqueue(item)
+	}
+
 	if !persist {
 		return
 	}
@@ -725,6 +1214,33 @@ func (r *Runner) recordTaskOutcome(ctx context.Context, transitionID, taskState
 	_, _ = r.store.UpdateTransition(ctx, transitionToPersist)
 }
 
+// release takes the backlog tasks that may start now. A new chunk opens once
+// every task of the previous one finished, and at most BatchSize tasks are in
+// flight within it. The caller must hold progressMu.
+func (p *transitionProgress) release() []queuedTask {
+	available := len(p.backlog)
+	if chunkSize := p.transition.ChunkSize; chunkSize > 0 {
+		if p.chunkLeft == 0 && p.inFlight == 0 {
+			p.chunkLeft = min(chunkSize, len(p.backlog))
+		}
+		available = p.chunkLeft
+	}
+	if batchSize := p.transition.BatchSize; batchSize > 0 {
+		available = min(available, batchSize-p.inFlight)
+	}
+	if available <= 0 {
+		return nil
+	}
+
+	released := p.backlog[:available:available]
+	p.backlog = p.backlog[available:]
+	p.inFlight += available
+	if p.transition.ChunkSize > 0 {
+		p.chunkLeft -= available
+	}
+	return released
+}
+
 func finalTransitionState(progress *transitionProgress) string {
 	if progress.executableTotal > 0 &&
 		progress.canceledCount == progress.executableTotal &&
@@ -743,6 +1259,13 @@ func finalTransitionState(progress *transitionProgress) string {
 	}
 }
 
+// AcquireBMC waits for a slot on the per-BMC limiter transition tasks use and
+// returns its release. Other BMC clients, such as the telemetry sampler, take
+// a slot so they do not add load to a BMC that tasks are already saturating.
+func (r *Runner) AcquireBMC(ctx context.Context, bmcID string) (func(), error) {
+	return r.acquireBMCLimiter(ctx, bmcID)
+}
+
 func (r *Runner) acquireBMCLimiter(ctx context.Context, bmcID string) (func(), error) {
 	bmc := strings.TrimSpace(bmcID)
 	if bmc == "" {
@@ -849,6 +1372,19 @@ func normalizeConfig(cfg Config) runtimeConfig {
 		transitionDeadline = defaultTransitionTimeout
 	}
 
+	bootWaitTimeout := cfg.BootWaitTimeout
+	if bootWaitTimeout <= 0 {
+		bootWaitTimeout = defaultBootWaitTimeout
+	}
+
+	bootWaitPoll := cfg.BootWaitPoll
+	if bootWaitPoll <= 0 {
+		bootWaitPoll = defaultBootWaitPoll
+	}
+	if bootWaitPoll > bootWaitTimeout {
+		bootWaitPoll = bootWaitTimeout
+	}
+
 	queueSize := cfg.QueueSize
 	if queueSize <= 0 {
 		queueSize = globalConcurrency * 4
@@ -857,6 +1393,16 @@ func normalizeConfig(cfg Config) runtimeConfig {
 		queueSize = 1
 	}
 
+	priorities := cfg.Priorities
+	if priorities == nil {
+		priorities = DefaultPriorities()
+	}
+
+	approvalSweep := cfg.ApprovalSweepInterval
+	if approvalSweep <= 0 {
+		approvalSweep = defaultApprovalSweepInterval
+	}
+
 	return runtimeConfig{
 		globalConcurrency:  globalConcurrency,
 		perBMCConcurrency:  perBMCConcurrency,
@@ -864,7 +1410,11 @@ func normalizeConfig(cfg Config) runtimeConfig {
 		retryBackoffBase:   retryBackoffBase,
 		retryBackoffMax:    retryBackoffMax,
 		transitionDeadline: transitionDeadline,
+		bootWaitTimeout:    bootWaitTimeout,
+		bootWaitPoll:       bootWaitPoll,
 		queueSize:          queueSize,
+		priorities:         priorities,
+		approvalSweep:      approvalSweep,
 		now:                time.Now,
 		sleep:              sleepWithContext,
 		jitter:             cryptoJitter,
@@ -50,6 +50,100 @@ func (m *mockStateUpdater) UpdateNodePowerState(ctx context.Context, nodeID, pow
 	return nil
 }
 
+type recordingStateUpdater struct {
+	mockStateUpdater
+
+	mu            sync.Mutex
+	transitioning []string
+	failed        []string
+}
+
+func (m *recordingStateUpdater) MarkNodeTransitioning(ctx context.Context, nodeID, operation string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transitioning = append(m.transitioning, nodeID+":"+operation)
+	return nil
+}
+
+func (m *recordingStateUpdater) MarkNodePowerFailure(ctx context.Context, nodeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed = append(m.failed, nodeID)
+	return nil
+}
+
+// testRunnerSetup holds what startTestRunner builds a runner from.
+type testRunnerSetup struct {
+	ctx      context.Context
+	executor Executor
+	reader   PowerStateReader
+	config   Config
+	options  []Option
+}
+
+type testRunnerOption func(*testRunnerSetup)
+
+// withRunContext runs the runner until ctx is canceled instead of until the
+// test ends.
+func withRunContext(ctx context.Context) testRunnerOption {
+	return func(s *testRunnerSetup) {
+		s.ctx = ctx
+	}
+}
+
+func withExecutor(executor Executor) testRunnerOption {
+	return func(s *testRunnerSetup) {
+		s.executor = executor
+	}
+}
+
+func withReader(reader PowerStateReader) testRunnerOption {
+	return func(s *testRunnerSetup) {
+		s.reader = reader
+	}
+}
+
+// withConfig adjusts the runner config after the test defaults are applied.
+func withConfig(configure func(*Config)) testRunnerOption {
+	return func(s *testRunnerSetup) {
+		configure(&s.config)
+	}
+}
+
+func withRunnerOptions(opts ...Option) testRunnerOption {
+	return func(s *testRunnerSetup) {
+		s.options = append(s.options, opts...)
+	}
+}
+
+// startTestRunner starts a runner over store with one attempt per call, fast
+// verification and no retry jitter, and stops it when the test ends.
+func startTestRunner(t *testing.T, store Store, opts ...testRunnerOption) *Runner {
+	t.Helper()
+
+	setup := testRunnerSetup{
+		ctx:      context.Background(),
+		executor: &mockExecutor{},
+		reader:   &mockReader{},
+		config: Config{
+			RetryAttempts:      1,
+			VerificationWindow: 100 * time.Millisecond,
+			VerificationPoll:   time.Millisecond,
+		},
+	}
+	for _, opt := range opts {
+		opt(&setup)
+	}
+
+	runner := New(store, setup.executor, setup.reader, setup.config, setup.options...)
+	runner.cfg.jitter = func(time.Duration) time.Duration { return 0 }
+
+	runCtx, cancel := context.WithCancel(setup.ctx)
+	t.Cleanup(cancel)
+	runner.Start(runCtx)
+	return runner
+}
+
 type memoryStore struct {
 	mu sync.Mutex
 
@@ -528,6 +622,54 @@ func TestRunner_VerificationMarksPerNodeSuccessAndFailure(t *testing.T) {
 	assert.Contains(t, byNode["node-bad"].ErrorDetail, ErrVerificationTimeout.Error())
 }
 
+func TestRunner_RestartRequiresObservedPowerCycle(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-cycled", BMCID: "bmc-a", Endpoint: "https://bmc-a", CredentialID: "cred-a"},
+		{NodeID: "node-stuck", BMCID: "bmc-b", Endpoint: "https://bmc-b", CredentialID: "cred-b"},
+	}, nil)
+
+	var cycledReads atomic.Int32
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		if req.NodeID == "node-cycled" && cycledReads.Add(1) == 2 {
+			return "Off", nil
+		}
+		return "On", nil
+	}}
+
+	runner := New(store, &mockExecutor{}, reader, Config{
+		GlobalConcurrency:  2,
+		PerBMCConcurrency:  1,
+		RetryAttempts:      1,
+		VerificationWindow: 60 * time.Millisecond,
+		VerificationPoll:   10 * time.Millisecond,
+		TransitionDeadline: 250 * time.Millisecond,
+	})
+	runner.cfg.jitter = func(time.Duration) time.Duration { return 0 }
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "ForceRestart",
+		NodeIDs:   []string{"node-cycled", "node-stuck"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	byNode := make(map[string]Task)
+	for _, task := range store.tasksForTransition(transition.ID) {
+		byNode[task.NodeID] = task
+	}
+
+	assert.Equal(t, TaskStateSucceeded, byNode["node-cycled"].State)
+	assert.Equal(t, "On", byNode["node-cycled"].FinalPowerState)
+
+	assert.Equal(t, TaskStateFailed, byNode["node-stuck"].State)
+	assert.Equal(t, "On", byNode["node-stuck"].FinalPowerState)
+	assert.Contains(t, byNode["node-stuck"].ErrorDetail, ErrRestartNotObserved.Error())
+}
+
 func TestRunner_DryRunCreatesPlannedRecords(t *testing.T) {
 	store := newMemoryStore([]model.NodePowerMapping{
 		{NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1"},
@@ -656,6 +798,112 @@ func TestRunner_SMDUpdateFailureMarksTaskFailed(t *testing.T) {
 	assert.Contains(t, tasks[0].ErrorDetail, "updating SMD state")
 }
 
+func TestRunner_RecordsTransitionalAndFailureStatesInSMD(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-ok", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1"},
+		{NodeID: "node-stuck", BMCID: "bmc-2", Endpoint: "https://bmc-2", CredentialID: "cred-2"},
+	}, nil)
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		if req.NodeID == "node-stuck" {
+			return "Off", nil
+		}
+		return "On", nil
+	}}
+
+	var updatedNodes []string
+	var updatedMu sync.Mutex
+	updater := &recordingStateUpdater{mockStateUpdater: mockStateUpdater{
+		updateNodePowerStateFn: func(ctx context.Context, nodeID, powerState string) error {
+			updatedMu.Lock()
+			defer updatedMu.Unlock()
+			updatedNodes = append(updatedNodes, nodeID)
+			return nil
+		},
+	}}
+
+	runner := New(store, &mockExecutor{}, reader, Config{
+		GlobalConcurrency:  2,
+		PerBMCConcurrency:  1,
+		RetryAttempts:      1,
+		VerificationWindow: 30 * time.Millisecond,
+		VerificationPoll:   5 * time.Millisecond,
+	}, WithNodeStateUpdater(updater))
+	runner.cfg.jitter = func(time.Duration) time.Duration { return 0 }
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-ok", "node-stuck"},
+	})
+	require.NoError(t, err)
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+	assert.ElementsMatch(t, []string{"node-ok:On", "node-stuck:On"}, updater.transitioning)
+	assert.Equal(t, []string{"node-stuck"}, updater.failed)
+
+	updatedMu.Lock()
+	defer updatedMu.Unlock()
+	assert.Equal(t, []string{"node-ok"}, updatedNodes)
+}
+
+func TestRunner_AbortedRunningTaskLeavesSMDFlagAlone(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{{
+		NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1",
+	}}, nil)
+
+	started := make(chan struct{}, 1)
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	updater := &recordingStateUpdater{}
+
+	runner := New(store, exec, &mockReader{}, Config{
+		RetryAttempts:      1,
+		TransitionDeadline: 2 * time.Second,
+	}, WithNodeStateUpdater(updater))
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "ForceOff",
+		NodeIDs:   []string{"node-1"},
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task execution did not start")
+	}
+
+	require.NoError(t, runner.AbortTransition(context.Background(), transition.ID))
+	require.Eventually(t, func() bool {
+		tasks := store.tasksForTransition(transition.ID)
+		return len(tasks) == 1 && tasks[0].CompletedAt != nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	tasks := store.tasksForTransition(transition.ID)
+	assert.Equal(t, TaskStateCanceled, tasks[0].State)
+	require.NotNil(t, tasks[0].StartedAt)
+
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+	assert.Equal(t, []string{"node-1:ForceOff"}, updater.transitioning)
+	assert.Empty(t, updater.failed)
+}
+
 func TestRunner_CancellationMarksTasksCanceled(t *testing.T) {
 	store := newMemoryStore([]model.NodePowerMapping{{
 		NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1",
@@ -811,3 +1059,129 @@ func TestRunner_AbortTransition_CancelsTasks(t *testing.T) {
 	assert.Equal(t, TaskStateCanceled, tasks[0].State)
 	assert.Equal(t, TaskStateCanceled, tasks[1].State)
 }
+
+func TestRunner_AbortTask_CancelsOneNode(t *testing.T) {
+	store := newMemoryStore([]model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1", CredentialID: "cred-1"},
+		{NodeID: "node-2", BMCID: "bmc-2", Endpoint: "https://bmc-2", CredentialID: "cred-2"},
+		{NodeID: "node-3", BMCID: "bmc-3", Endpoint: "https://bmc-3", CredentialID: "cred-3"},
+	}, nil)
+
+	started := make(chan struct{}, 1)
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		if req.NodeID != "node-1" {
+			return nil
+		}
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	reader := &mockReader{readFn: func(ctx context.Context, req ExecutionRequest) (string, error) {
+		return "On", nil
+	}}
+
+	runner := New(store, exec, reader, Config{
+		GlobalConcurrency:  2,
+		RetryAttempts:      1,
+		VerificationWindow: 200 * time.Millisecond,
+		VerificationPoll:   5 * time.Millisecond,
+		TransitionDeadline: 2 * time.Second,
+	})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	transition, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1", "node-2", "node-3"},
+		BatchSize: 1,
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("task execution did not start")
+	}
+
+	// node-2 is still held back by the batch size and is canceled at once.
+	require.NoError(t, runner.AbortTask(context.Background(), transition.ID, "node-2"))
+	byNode := make(map[string]Task)
+	for _, task := range store.tasksForTransition(transition.ID) {
+		byNode[task.NodeID] = task
+	}
+	assert.Equal(t, TaskStateCanceled, byNode["node-2"].State)
+
+	require.NoError(t, runner.AbortTask(context.Background(), transition.ID, "node-1"))
+	require.ErrorIs(t, runner.AbortTask(context.Background(), transition.ID, "node-1"), ErrTaskNotActive)
+	require.ErrorIs(t, runner.AbortTask(context.Background(), "missing", "node-1"), ErrTransitionNotFound)
+
+	require.True(t, store.waitForTerminal(transition.ID, 2*time.Second))
+	finalTransition := store.transition(transition.ID)
+	assert.Equal(t, TransitionStatePartial, finalTransition.State)
+	assert.Equal(t, 1, finalTransition.SuccessCount)
+	assert.Equal(t, 2, finalTransition.FailureCount)
+
+	for _, task := range store.tasksForTransition(transition.ID) {
+		byNode[task.NodeID] = task
+	}
+	assert.Equal(t, TaskStateCanceled, byNode["node-1"].State)
+	assert.Equal(t, TaskStateSucceeded, byNode["node-3"].State)
+}
+
+func TestRunner_RejectsTransitionsWhenQueueFull(t *testing.T) {
+	mappings := make([]model.NodePowerMapping, 0, 4)
+	for i := 1; i <= 4; i++ {
+		mappings = append(mappings, model.NodePowerMapping{
+			NodeID: "node-" + strconvItoa(i), BMCID: "bmc-" + strconvItoa(i), Endpoint: "https://bmc", CredentialID: "cred-1",
+		})
+	}
+	store := newMemoryStore(mappings, nil)
+
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	exec := &mockExecutor{executeFn: func(ctx context.Context, req ExecutionRequest) error {
+		started <- struct{}{}
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}}
+
+	runner := New(store, exec, &mockReader{}, Config{GlobalConcurrency: 1, QueueSize: 2, RetryAttempts: 1})
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.Start(runCtx)
+
+	first, err := runner.StartTransition(context.Background(), StartRequest{
+		Operation: "On",
+		NodeIDs:   []string{"node-1", "node-2", "node-3", "node-4"},
+	})
+	require.NoError(t, err)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("task execution did not start")
+	}
+	assert.Equal(t, 3, runner.QueueStatus().Depth)
+
+	_, err = runner.StartTransition(context.Background(), StartRequest{Operation: "ForceOff", NodeIDs: []string{"node-1"}})
+	require.ErrorIs(t, err, ErrQueueFull)
+	var queueFull *QueueFullError
+	require.ErrorAs(t, err, &queueFull)
+	assert.Equal(t, 3, queueFull.Depth)
+	assert.Equal(t, 2, queueFull.Limit)
+	assert.Equal(t, 2*defaultTaskDuration, queueFull.RetryAfter, "two tasks must finish on one worker")
+
+	_, err = runner.StartTransition(context.Background(), StartRequest{Operation: "ForceOff", NodeIDs: []string{"node-1"}, DryRun: true})
+	require.NoError(t, err, "dry runs queue no tasks")
+
+	close(release)
+	require.True(t, store.waitForTerminal(first.ID, 2*time.Second))
+	assert.Zero(t, runner.QueueStatus().Depth)
+	_, err = runner.StartTransition(context.Background(), StartRequest{Operation: "ForceOff", NodeIDs: []string{"node-1"}})
+	require.NoError(t, err)
+}
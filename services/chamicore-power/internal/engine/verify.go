@@ -20,6 +20,12 @@ var (
 	ErrUnsupportedOperation = errors.New("unsupported operation")
 	// ErrVerificationTimeout indicates verification did not reach expected final state in time.
 	ErrVerificationTimeout = errors.New("verification timed out")
+	// ErrRestartNotObserved indicates a restart operation showed no evidence of a power cycle.
+	ErrRestartNotObserved = errors.New("restart not observed")
+
+	// errReadBackMismatch is returned by pollReadBack when the window closes
+	// before the read-back value matched; callers wrap it with context.
+	errReadBackMismatch = errors.New("read-back did not match")
 )
 
 // PowerStateReader reads the current node power state.
@@ -27,6 +33,21 @@ type PowerStateReader interface {
 	ReadPowerState(ctx context.Context, req ExecutionRequest) (string, error)
 }
 
+// SystemState is one observation of a node's Redfish system.
+type SystemState struct {
+	PowerState    string
+	LastResetTime string
+	BootProgress  string
+	PostState     string
+}
+
+// SystemStateReader reads the power state together with the restart evidence
+// Redfish exposes. Readers that implement it let restart verification use
+// LastResetTime and BootProgress in addition to power-state dips.
+type SystemStateReader interface {
+	ReadSystemState(ctx context.Context, req ExecutionRequest) (SystemState, error)
+}
+
 // VerifyConfig controls final-state verification behavior.
 type VerifyConfig struct {
 	Window       time.Duration
@@ -62,19 +83,46 @@ func NewVerifier(reader PowerStateReader, cfg VerifyConfig) *Verifier {
 	}
 }
 
+// Baseline records restart evidence before a reset is issued. It returns a
+// zero state when the operation is not a restart or the read fails; restart
+// verification then relies on the evidence that needs no baseline.
+func (v *Verifier) Baseline(ctx context.Context, req ExecutionRequest) SystemState {
+	if !requiresPowerCycle(req.Operation) {
+		return SystemState{}
+	}
+	state, err := v.readState(ctx, req)
+	if err != nil {
+		return SystemState{}
+	}
+	return state
+}
+
 // Verify polls power state until it matches the expected final state or times out.
-func (v *Verifier) Verify(ctx context.Context, req ExecutionRequest) (string, error) {
+// Restart operations additionally require evidence that the node cycled
+// relative to baseline: an Off/PoweringOff dip, a changed LastResetTime, or
+// BootProgress moving back to an early stage.
+func (v *Verifier) Verify(ctx context.Context, req ExecutionRequest, baseline SystemState) (string, error) {
+	return v.VerifyWithin(ctx, req, baseline, v.window)
+}
+
+// VerifyWithin is Verify with an explicit verification window.
+func (v *Verifier) VerifyWithin(ctx context.Context, req ExecutionRequest, baseline SystemState, window time.Duration) (string, error) {
+	if window <= 0 {
+		window = v.window
+	}
 	expectedState, err := expectedFinalPowerState(req.Operation)
 	if err != nil {
 		return "", err
 	}
+	needCycle := requiresPowerCycle(req.Operation)
+	cycled := false
 
-	verifyCtx, cancel := context.WithTimeout(ctx, v.window)
+	verifyCtx, cancel := context.WithTimeout(ctx, window)
 	defer cancel()
 
 	lastState := ""
 	for {
-		state, readErr := v.reader.ReadPowerState(verifyCtx, req)
+		state, readErr := v.readState(verifyCtx, req)
 		if readErr != nil {
 			if verifyCtx.Err() != nil {
 				return strings.TrimSpace(lastState), verifyCtx.Err()
@@ -82,8 +130,11 @@ func (v *Verifier) Verify(ctx context.Context, req ExecutionRequest) (string, er
 			return strings.TrimSpace(lastState), fmt.Errorf("reading power state: %w", readErr)
 		}
 
-		lastState = strings.TrimSpace(state)
-		if strings.EqualFold(lastState, expectedState) {
+		lastState = strings.TrimSpace(state.PowerState)
+		if needCycle && !cycled {
+			cycled = observedPowerCycle(baseline, state)
+		}
+		if strings.EqualFold(lastState, expectedState) && (!needCycle || cycled) {
 			return lastState, nil
 		}
 
@@ -91,17 +142,119 @@ func (v *Verifier) Verify(ctx context.Context, req ExecutionRequest) (string, er
 		select {
 		case <-verifyCtx.Done():
 			timer.Stop()
-			return strings.TrimSpace(lastState), fmt.Errorf(
+			if needCycle && !cycled {
+				return lastState, fmt.Errorf(
+					"%w: no power-off, reset time change, or early boot progress within %s, last %q",
+					ErrRestartNotObserved,
+					window,
+					lastState,
+				)
+			}
+			return lastState, fmt.Errorf(
 				"%w: expected %q, last %q",
 				ErrVerificationTimeout,
 				expectedState,
-				strings.TrimSpace(lastState),
+				lastState,
 			)
 		case <-timer.C:
 		}
 	}
 }
 
+func (v *Verifier) readState(ctx context.Context, req ExecutionRequest) (SystemState, error) {
+	if reader, ok := v.reader.(SystemStateReader); ok {
+		return reader.ReadSystemState(ctx, req)
+	}
+	powerState, err := v.reader.ReadPowerState(ctx, req)
+	if err != nil {
+		return SystemState{}, err
+	}
+	return SystemState{PowerState: powerState}, nil
+}
+
+// pollReadBack polls read until it reports a match, for settings verified by
+// reading them back from the BMC. It returns the last observed value.
+func (v *Verifier) pollReadBack(
+	ctx context.Context,
+	read func(ctx context.Context) (matched bool, observed string, err error),
+) (string, error) {
+	verifyCtx, cancel := context.WithTimeout(ctx, v.window)
+	defer cancel()
+
+	last := "unknown"
+	for {
+		matched, observed, err := read(verifyCtx)
+		if err != nil {
+			if verifyCtx.Err() != nil {
+				return last, verifyCtx.Err()
+			}
+			return last, err
+		}
+		last = observed
+		if matched {
+			return last, nil
+		}
+
+		timer := time.NewTimer(v.pollInterval)
+		select {
+		case <-verifyCtx.Done():
+			timer.Stop()
+			return last, errReadBackMismatch
+		case <-timer.C:
+		}
+	}
+}
+
+func requiresPowerCycle(operation redfish.ResetOperation) bool {
+	switch operation {
+	case redfish.ResetOperationGracefulRestart,
+		redfish.ResetOperationForceRestart:
+		return true
+	default:
+		// An NMI interrupts the running OS without resetting the node, so
+		// it only has to leave the node On.
+		return false
+	}
+}
+
+// observedPowerCycle reports whether one observation proves the node went
+// through a reset since baseline was taken.
+func observedPowerCycle(baseline, observed SystemState) bool {
+	powerState := strings.TrimSpace(observed.PowerState)
+	if strings.EqualFold(powerState, "Off") || strings.EqualFold(powerState, "PoweringOff") {
+		return true
+	}
+
+	baselineReset := strings.TrimSpace(baseline.LastResetTime)
+	observedReset := strings.TrimSpace(observed.LastResetTime)
+	if baselineReset != "" && observedReset != "" && baselineReset != observedReset {
+		return true
+	}
+
+	baselineProgress := strings.TrimSpace(baseline.BootProgress)
+	return baselineProgress != "" &&
+		!isEarlyBootProgress(baselineProgress) &&
+		isEarlyBootProgress(observed.BootProgress)
+}
+
+// isEarlyBootProgress reports whether a Redfish BootProgress.LastState is a
+// pre-OS stage a node only passes through while starting up.
+func isEarlyBootProgress(state string) bool {
+	switch strings.TrimSpace(state) {
+	case "None",
+		"PrimaryProcessorInitializationStarted",
+		"BusInitializationStarted",
+		"MemoryInitializationStarted",
+		"SecondaryProcessorInitializationStarted",
+		"PCIResourceConfigStarted",
+		"SystemHardwareInitializationComplete",
+		"SetupEntered":
+		return true
+	default:
+		return false
+	}
+}
+
 func expectedFinalPowerState(operation redfish.ResetOperation) (string, error) {
 	switch operation {
 	case redfish.ResetOperationOn,
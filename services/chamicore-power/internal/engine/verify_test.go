@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+)
+
+type sequenceStateReader struct {
+	mu     sync.Mutex
+	states []SystemState
+}
+
+func (s *sequenceStateReader) ReadPowerState(ctx context.Context, req ExecutionRequest) (string, error) {
+	state, err := s.ReadSystemState(ctx, req)
+	return state.PowerState, err
+}
+
+// ReadSystemState returns the queued states in order and repeats the last one.
+func (s *sequenceStateReader) ReadSystemState(ctx context.Context, req ExecutionRequest) (SystemState, error) {
+	_ = ctx
+	_ = req
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[0]
+	if len(s.states) > 1 {
+		s.states = s.states[1:]
+	}
+	return state, nil
+}
+
+func TestVerifier_RestartEvidence(t *testing.T) {
+	t.Parallel()
+
+	running := SystemState{PowerState: "On", LastResetTime: "2026-01-01T00:00:00Z", BootProgress: "OSRunning"}
+
+	tests := []struct {
+		name    string
+		states  []SystemState
+		wantErr error
+	}{
+		{
+			name:   "last reset time changed",
+			states: []SystemState{running, {PowerState: "On", LastResetTime: "2026-01-01T00:05:00Z", BootProgress: "OSRunning"}},
+		},
+		{
+			name: "boot progress back to early stage",
+			states: []SystemState{
+				running,
+				{PowerState: "On", LastResetTime: running.LastResetTime, BootProgress: "MemoryInitializationStarted"},
+				{PowerState: "On", LastResetTime: running.LastResetTime, BootProgress: "OSRunning"},
+			},
+		},
+		{
+			name:   "powering off dip",
+			states: []SystemState{running, {PowerState: "PoweringOff"}, {PowerState: "On"}},
+		},
+		{
+			name:    "no evidence",
+			states:  []SystemState{running},
+			wantErr: ErrRestartNotObserved,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := &sequenceStateReader{states: tc.states}
+			verifier := NewVerifier(reader, VerifyConfig{Window: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+			req := ExecutionRequest{NodeID: "node-1", Operation: redfish.ResetOperationGracefulRestart}
+
+			baseline := verifier.Baseline(context.Background(), req)
+			assert.Equal(t, running, baseline)
+
+			finalState, err := verifier.Verify(context.Background(), req, baseline)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "On", finalState)
+		})
+	}
+}
+
+func TestVerifier_NonRestartSkipsBaselineAndCycle(t *testing.T) {
+	t.Parallel()
+
+	for _, operation := range []redfish.ResetOperation{redfish.ResetOperationOn, redfish.ResetOperationNMI} {
+		reader := &sequenceStateReader{states: []SystemState{{PowerState: "On", BootProgress: "OSRunning"}}}
+		verifier := NewVerifier(reader, VerifyConfig{Window: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+		req := ExecutionRequest{NodeID: "node-1", Operation: operation}
+
+		baseline := verifier.Baseline(context.Background(), req)
+		assert.Equal(t, SystemState{}, baseline, operation)
+
+		finalState, err := verifier.Verify(context.Background(), req, baseline)
+		require.NoError(t, err, operation)
+		assert.Equal(t, "On", finalState, operation)
+	}
+}
+
+func TestObservedPowerCycle_IgnoresEvidenceWithoutBaseline(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, observedPowerCycle(SystemState{}, SystemState{PowerState: "On", LastResetTime: "2026-01-01T00:00:00Z"}))
+	assert.False(t, observedPowerCycle(SystemState{}, SystemState{PowerState: "On", BootProgress: "None"}))
+	assert.False(t, observedPowerCycle(
+		SystemState{BootProgress: "None"},
+		SystemState{PowerState: "On", BootProgress: "None"},
+	))
+	assert.True(t, observedPowerCycle(SystemState{}, SystemState{PowerState: "Off"}))
+}
@@ -14,6 +14,8 @@ const (
 	MappingErrorCodeEndpointMissing = "endpoint_missing"
 	// MappingErrorCodeCredentialMissing indicates missing BMC credential reference.
 	MappingErrorCodeCredentialMissing = "credential_missing"
+	// MappingStatusResolved marks a node whose mapping resolves to a usable BMC endpoint and credential.
+	MappingStatusResolved = "resolved"
 )
 
 // BMCEndpoint stores per-BMC connectivity and credential reference.
@@ -46,6 +48,22 @@ type MappingApplyCounts struct {
 	LinksDeleted      int `json:"links_deleted"`
 }
 
+const (
+	// SystemPathSourceDiscovery marks a system path learned from the BMC's Redfish Systems collection.
+	SystemPathSourceDiscovery = "discovery"
+	// SystemPathSourceAdmin marks a system path set explicitly through the admin API.
+	SystemPathSourceAdmin = "admin"
+)
+
+// NodeSystemPath stores the Redfish ComputerSystem path that controls one node.
+type NodeSystemPath struct {
+	NodeID     string
+	SystemPath string
+	Source     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
 // NodePowerMapping is the resolved per-node power-control routing data.
 type NodePowerMapping struct {
 	NodeID             string `json:"node_id"`
@@ -62,6 +80,33 @@ type NodeMappingError struct {
 	Code   string `json:"code"`
 }
 
+// NodeMappingDiagnostic explains how a node's power mapping resolves, or why it does not.
+type NodeMappingDiagnostic struct {
+	NodeID       string         `json:"node_id"`
+	Status       string         `json:"status"`
+	Reason       string         `json:"reason"`
+	BMCID        string         `json:"bmc_id,omitempty"`
+	Endpoint     string         `json:"endpoint,omitempty"`
+	CredentialID string         `json:"credential_id,omitempty"`
+	SMD          NodeMappingSMD `json:"smd"`
+	Suggestions  []string       `json:"suggestions"`
+}
+
+// NodeMappingSMD is the SMD data a node's mapping is derived from.
+type NodeMappingSMD struct {
+	ParentID       string                     `json:"parent_id,omitempty"`
+	ParentType     string                     `json:"parent_type,omitempty"`
+	Interfaces     []InterfaceMappingEvidence `json:"interfaces"`
+	ChosenEndpoint string                     `json:"chosen_endpoint,omitempty"`
+}
+
+// InterfaceMappingEvidence is one SMD ethernet interface considered for a BMC endpoint.
+type InterfaceMappingEvidence struct {
+	ComponentID string   `json:"component_id"`
+	IPAddrs     []string `json:"ip_addrs"`
+	Endpoint    string   `json:"endpoint,omitempty"`
+}
+
 // MissingNodeMappingError returns an actionable error for absent node mapping.
 func MissingNodeMappingError(nodeID string) NodeMappingError {
 	node := strings.TrimSpace(nodeID)
@@ -104,3 +149,93 @@ func MissingCredentialError(nodeID, bmcID string) NodeMappingError {
 		),
 	}
 }
+
+// MappingSyncState is the mapping sync state shared between service replicas.
+type MappingSyncState struct {
+	LeaderID          string
+	LeaderHeartbeatAt *time.Time
+	RequestedSeq      int64
+	CompletedSeq      int64
+	LastAttemptAt     *time.Time
+	LastSyncAt        *time.Time
+	LastError         string
+	LastCounts        MappingApplyCounts
+	LastReport        *MappingSyncReport
+}
+
+// MappingSyncRun records the outcome of one leader-executed mapping sync.
+type MappingSyncRun struct {
+	LeaderID    string
+	HandledSeq  int64
+	AttemptedAt time.Time
+	SyncedAt    *time.Time
+	Error       string
+	Counts      MappingApplyCounts
+	Report      *MappingSyncReport
+}
+
+// MappingSyncReport lists the BMC and link rows a full sync added, changed, or removed.
+// Entry lists are capped; Summary always carries the full counts.
+type MappingSyncReport struct {
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Summary      MappingSyncSummary  `json:"summary"`
+	Truncated    bool                `json:"truncated"`
+	BMCsAdded    []BMCMappingChange  `json:"bmcs_added"`
+	BMCsChanged  []BMCMappingChange  `json:"bmcs_changed"`
+	BMCsRemoved  []BMCMappingChange  `json:"bmcs_removed"`
+	LinksAdded   []LinkMappingChange `json:"links_added"`
+	LinksChanged []LinkMappingChange `json:"links_changed"`
+	LinksRemoved []LinkMappingChange `json:"links_removed"`
+}
+
+// MappingSyncSummary counts the changes described by a MappingSyncReport.
+type MappingSyncSummary struct {
+	BMCsAdded    int `json:"bmcs_added"`
+	BMCsChanged  int `json:"bmcs_changed"`
+	BMCsRemoved  int `json:"bmcs_removed"`
+	LinksAdded   int `json:"links_added"`
+	LinksChanged int `json:"links_changed"`
+	LinksRemoved int `json:"links_removed"`
+}
+
+// BMCMappingChange describes one added, changed, or removed BMC endpoint row.
+type BMCMappingChange struct {
+	BMCID                string `json:"bmc_id"`
+	Endpoint             string `json:"endpoint,omitempty"`
+	PreviousEndpoint     string `json:"previous_endpoint,omitempty"`
+	CredentialID         string `json:"credential_id,omitempty"`
+	PreviousCredentialID string `json:"previous_credential_id,omitempty"`
+}
+
+// LinkMappingChange describes one added, changed, or removed node->BMC link.
+type LinkMappingChange struct {
+	NodeID        string `json:"node_id"`
+	BMCID         string `json:"bmc_id,omitempty"`
+	PreviousBMCID string `json:"previous_bmc_id,omitempty"`
+}
+
+// TopologyDelta is an incremental change to the cached node->BMC topology.
+type TopologyDelta struct {
+	// Endpoints are merged into existing rows: an empty endpoint never clears a
+	// known one and, unless Authoritative is set, an existing credential
+	// binding is kept.
+	Endpoints []BMCEndpoint
+	// Authoritative marks a delta computed by a full sync, whose endpoints
+	// carry the complete credential and TLS settings and overwrite the
+	// cached ones. Event-driven deltas leave it unset.
+	Authoritative bool
+	// EndpointRefreshes update the Redfish endpoint of already-tracked BMCs only.
+	EndpointRefreshes []BMCEndpoint
+	Links             []NodeBMCLink
+	DeletedBMCIDs     []string
+	DeletedNodeIDs    []string
+}
+
+// IsEmpty reports whether the delta carries no changes.
+func (d TopologyDelta) IsEmpty() bool {
+	return len(d.Endpoints) == 0 &&
+		len(d.EndpointRefreshes) == 0 &&
+		len(d.Links) == 0 &&
+		len(d.DeletedBMCIDs) == 0 &&
+		len(d.DeletedNodeIDs) == 0
+}
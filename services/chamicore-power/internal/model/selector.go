@@ -0,0 +1,17 @@
+package model
+
+// NodeSelector selects SMD nodes by component attributes. Set fields must all
+// match; Cabinet matches nodes whose xname lies in that cabinet.
+// Templates persist it as JSON, so field tags are part of the storage format.
+type NodeSelector struct {
+	Role    string `json:"role,omitempty"`
+	SubRole string `json:"subRole,omitempty"`
+	Arch    string `json:"arch,omitempty"`
+	State   string `json:"state,omitempty"`
+	Cabinet string `json:"cabinet,omitempty"`
+}
+
+// IsZero reports whether no attribute is set.
+func (s NodeSelector) IsZero() bool {
+	return s == NodeSelector{}
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// TelemetrySample is one instantaneous reading of a node's power draw and
+// inlet temperature. Either value may be missing when the BMC does not
+// report it.
+type TelemetrySample struct {
+	NodeID           string
+	SampledAt        time.Time
+	PowerWatts       *float64
+	InletTempCelsius *float64
+}
+
+// TelemetryPoint aggregates a node's samples over one query step.
+type TelemetryPoint struct {
+	NodeID    string
+	Timestamp time.Time
+	// Samples is the number of samples that reported power draw.
+	Samples          int
+	PowerWatts       *float64
+	MinPowerWatts    *float64
+	MaxPowerWatts    *float64
+	InletTempCelsius *float64
+	MaxInletTemp     *float64
+}
+
+// TelemetryQuery selects stored telemetry for a set of nodes.
+type TelemetryQuery struct {
+	NodeIDs []string
+	Since   time.Time
+	Until   time.Time
+	// Step is the width of each returned point; it is never finer than the
+	// stored resolution.
+	Step time.Duration
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// TransitionTemplate is one stored version of a named transition template.
+type TransitionTemplate struct {
+	Name        string
+	Version     int
+	Description string
+	Spec        TemplateSpec
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// TemplateSpec captures the settings a template run starts a transition with.
+// It is persisted as JSON, so field tags are part of the storage format.
+type TemplateSpec struct {
+	Operation      string              `json:"operation"`
+	Nodes          []string            `json:"nodes,omitempty"`
+	Groups         []string            `json:"groups,omitempty"`
+	Selector       NodeSelector        `json:"selector,omitzero"`
+	GroupOperation string              `json:"groupOperation,omitempty"`
+	ExcludeNodes   []string            `json:"excludeNodes,omitempty"`
+	ExcludeGroups  []string            `json:"excludeGroups,omitempty"`
+	BootWait       *TemplateBootWait   `json:"bootWait,omitempty"`
+	DryRun         bool                `json:"dryRun,omitempty"`
+	BatchSize      int                 `json:"batchSize,omitempty"`
+	Escalation     *TemplateEscalation `json:"escalation,omitempty"`
+	Guard          *TemplateGuard      `json:"guard,omitempty"`
+}
+
+// TemplateBootWait mirrors the bootWait settings of a transition request.
+type TemplateBootWait struct {
+	Target       string `json:"target,omitempty"`
+	WaitForReady bool   `json:"waitForReady,omitempty"`
+}
+
+// TemplateEscalation forces graceful resets that have not completed after
+// AfterSeconds.
+type TemplateEscalation struct {
+	AfterSeconds int `json:"afterSeconds"`
+}
+
+// TemplateGuard limits how a template may be run.
+type TemplateGuard struct {
+	// MaxNodes rejects runs that resolve to more nodes; zero means no limit.
+	MaxNodes int `json:"maxNodes,omitempty"`
+	// RequireConfirmation rejects runs that do not set confirm.
+	RequireConfirmation bool `json:"requireConfirmation,omitempty"`
+}
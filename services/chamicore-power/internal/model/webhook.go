@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// Webhook is an outbound subscription to transition events.
+type Webhook struct {
+	ID     string
+	Name   string
+	URL    string
+	Secret string
+	// EventTypes and Groups filter delivered events; empty matches all.
+	EventTypes []string
+	Groups     []string
+	Enabled    bool
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookDelivery is one event queued for, or delivered to, a webhook.
+type WebhookDelivery struct {
+	ID        string
+	WebhookID string
+	EventID   string
+	EventType string
+	Subject   string
+	// Payload is the exact request body, so retries resend the same bytes.
+	Payload        json.RawMessage
+	State          string
+	AttemptCount   int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// WebhookDeliveryQuery selects deliveries of one webhook for the delivery log.
+type WebhookDeliveryQuery struct {
+	WebhookID string
+	// State filters by delivery state; empty selects every state.
+	State  string
+	Limit  int
+	Offset int
+}
+
+// ClaimedWebhookDelivery is a due delivery together with its webhook.
+type ClaimedWebhookDelivery struct {
+	Delivery WebhookDelivery
+	Webhook  Webhook
+}
+
+// WebhookDeliveryResult records the outcome of one delivery attempt.
+type WebhookDeliveryResult struct {
+	DeliveryID string
+	State      string
+	StatusCode int
+	Error      string
+	// NextAttemptAt schedules the next attempt of a delivery left pending.
+	NextAttemptAt time.Time
+	AttemptedAt   time.Time
+}
+
+// OutboxEvent is one row of the event outbox not yet fanned out to webhooks.
+type OutboxEvent struct {
+	ID        string
+	EventType string
+	Subject   string
+	Data      json.RawMessage
+	CreatedAt time.Time
+}
@@ -0,0 +1,314 @@
+// Package quota limits how often and how much each caller may change power
+// state: a per-subject request rate and a cap on the subject's active
+// transitions, with overrides per scope, caller group or subject.
+package quota
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Override kinds, from lowest to highest precedence.
+const (
+	KindScope   = "scope"
+	KindGroup   = "group"
+	KindSubject = "subject"
+)
+
+// idleBucketTTL is how long a caller's bucket is kept after its last
+// request.
+const idleBucketTTL = time.Hour
+
+// Limits bounds one caller. Zero values disable the corresponding limit.
+type Limits struct {
+	// RatePerMinute is the sustained rate of mutating requests.
+	RatePerMinute int
+	// Burst is how many requests may be made at once; it defaults to
+	// RatePerMinute.
+	Burst int
+	// MaxActive caps the caller's pending, running and pending-approval
+	// transitions.
+	MaxActive int
+}
+
+func (l Limits) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.RatePerMinute
+}
+
+// Override replaces the default limits for callers matching Kind and Name.
+type Override struct {
+	Kind   string
+	Name   string
+	Limits Limits
+}
+
+// Source names the override that set a caller's limits, e.g.
+// "scope:admin", or "default".
+func (o Override) Source() string {
+	return o.Kind + ":" + o.Name
+}
+
+// Caller identifies who is making a request.
+type Caller struct {
+	Subject string
+	Scopes  []string
+}
+
+// Policy maps callers to limits.
+type Policy struct {
+	Default   Limits
+	Overrides []Override
+	// Groups maps caller group names to their member subjects.
+	Groups map[string][]string
+}
+
+// ParsePolicy builds a policy from default limits and two comma-separated
+// lists. Each override is `kind:name=rate/burst/active` with kind one of
+// scope, group or subject, e.g. `scope:admin=0/0/0,group:ci=30/10/2`; zero
+// disables a limit. Each group is `name=subject|subject`, e.g.
+// `ci=svc-jenkins|svc-gitlab`.
+func ParsePolicy(defaults Limits, overrides, groups string) (Policy, error) {
+	policy := Policy{Default: defaults, Groups: make(map[string][]string)}
+
+	for _, entry := range splitEntries(groups) {
+		name, members, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return Policy{}, fmt.Errorf("invalid quota group %q: expected name=subject|subject", entry)
+		}
+		for _, member := range strings.Split(members, "|") {
+			if member = strings.TrimSpace(member); member != "" {
+				policy.Groups[name] = append(policy.Groups[name], member)
+			}
+		}
+	}
+
+	for _, entry := range splitEntries(overrides) {
+		key, value, ok := strings.Cut(entry, "=")
+		kind, name, hasKind := strings.Cut(strings.TrimSpace(key), ":")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		name = strings.TrimSpace(name)
+		if !ok || !hasKind || name == "" {
+			return Policy{}, fmt.Errorf("invalid quota override %q: expected kind:name=rate/burst/active", entry)
+		}
+		switch kind {
+		case KindScope, KindSubject:
+		case KindGroup:
+			if _, known := policy.Groups[name]; !known {
+				return Policy{}, fmt.Errorf("invalid quota override %q: unknown group %q", entry, name)
+			}
+		default:
+			return Policy{}, fmt.Errorf("invalid quota override %q: kind must be scope, group or subject", entry)
+		}
+
+		limits, err := parseLimits(value)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid quota override %q: %w", entry, err)
+		}
+		policy.Overrides = append(policy.Overrides, Override{Kind: kind, Name: name, Limits: limits})
+	}
+	return policy, nil
+}
+
+func splitEntries(raw string) []string {
+	entries := make([]string, 0)
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func parseLimits(raw string) (Limits, error) {
+	parts := strings.Split(strings.TrimSpace(raw), "/")
+	if len(parts) != 3 {
+		return Limits{}, fmt.Errorf("expected rate/burst/active")
+	}
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 0 {
+			return Limits{}, fmt.Errorf("%q is not a non-negative integer", part)
+		}
+		values[i] = value
+	}
+	return Limits{RatePerMinute: values[0], Burst: values[1], MaxActive: values[2]}, nil
+}
+
+// IsZero reports whether the policy never limits anyone.
+func (p Policy) IsZero() bool {
+	return p.Default == (Limits{}) && len(p.Overrides) == 0
+}
+
+// LimitsFor returns the caller's limits and where they came from. A subject
+// override wins over a group override, which wins over a scope override;
+// among overrides of one kind the first listed wins.
+func (p Policy) LimitsFor(caller Caller) (Limits, string) {
+	for _, kind := range []string{KindSubject, KindGroup, KindScope} {
+		for _, override := range p.Overrides {
+			if override.Kind == kind && p.matches(override, caller) {
+				return override.Limits, override.Source()
+			}
+		}
+	}
+	return p.Default, "default"
+}
+
+func (p Policy) matches(override Override, caller Caller) bool {
+	switch override.Kind {
+	case KindSubject:
+		return override.Name == caller.Subject
+	case KindGroup:
+		for _, member := range p.Groups[override.Name] {
+			if member == caller.Subject {
+				return true
+			}
+		}
+	case KindScope:
+		for _, scope := range caller.Scopes {
+			if scope == override.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Decision is the outcome of Limiter.Allow.
+type Decision struct {
+	Allowed bool
+	// RetryAfter is how long until the next request would be allowed.
+	RetryAfter time.Duration
+	Limits     Limits
+	Source     string
+}
+
+// Usage reports the rate-limit state of one caller.
+type Usage struct {
+	Subject string
+	Scopes  []string
+	Limits  Limits
+	Source  string
+	// Tokens is how many requests the caller may make right now.
+	Tokens      float64
+	LastRequest time.Time
+}
+
+type bucket struct {
+	scopes   []string
+	tokens   float64
+	updated  time.Time
+	lastSeen time.Time
+}
+
+// Limiter enforces request rates per subject. Buckets live in memory, so
+// each replica limits the requests it serves.
+type Limiter struct {
+	policy Policy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+// NewLimiter creates a rate limiter for policy.
+func NewLimiter(policy Policy) *Limiter {
+	return &Limiter{
+		policy:  policy,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Policy returns the policy the limiter enforces.
+func (l *Limiter) Policy() Policy {
+	return l.policy
+}
+
+// Allow takes one request from the caller's bucket.
+func (l *Limiter) Allow(caller Caller) Decision {
+	limits, source := l.policy.LimitsFor(caller)
+	decision := Decision{Allowed: true, Limits: limits, Source: source}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+	b, ok := l.buckets[caller.Subject]
+	if !ok {
+		b = &bucket{tokens: float64(limits.burst()), updated: now}
+		l.buckets[caller.Subject] = b
+	}
+	b.scopes = append(b.scopes[:0], caller.Scopes...)
+	b.lastSeen = now
+	if limits.RatePerMinute <= 0 {
+		return decision
+	}
+
+	refill(b, limits, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return decision
+	}
+	perSecond := float64(limits.RatePerMinute) / 60
+	decision.Allowed = false
+	decision.RetryAfter = time.Duration(math.Ceil((1-b.tokens)/perSecond*1000)) * time.Millisecond
+	return decision
+}
+
+// Usage returns the state of every caller seen recently, sorted by subject.
+func (l *Limiter) Usage() []Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usage := make([]Usage, 0, len(l.buckets))
+	for subject, b := range l.buckets {
+		caller := Caller{Subject: subject, Scopes: b.scopes}
+		limits, source := l.policy.LimitsFor(caller)
+		refill(b, limits, now)
+		usage = append(usage, Usage{
+			Subject:     subject,
+			Scopes:      append([]string{}, b.scopes...),
+			Limits:      limits,
+			Source:      source,
+			Tokens:      b.tokens,
+			LastRequest: b.lastSeen,
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Subject < usage[j].Subject })
+	return usage
+}
+
+func refill(b *bucket, limits Limits, now time.Time) {
+	burst := float64(limits.burst())
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += elapsed.Minutes() * float64(limits.RatePerMinute)
+	}
+	b.tokens = min(b.tokens, burst)
+	b.updated = now
+}
+
+// prune drops buckets idle for longer than idleBucketTTL. Called with mu held.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for subject, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleBucketTTL {
+			delete(l.buckets, subject)
+		}
+	}
+}
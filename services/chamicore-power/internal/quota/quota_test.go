@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy(
+		Limits{RatePerMinute: 10, MaxActive: 2},
+		" scope:admin=0/0/0, group:ci=30/10/4 ,subject:alice=120/20/8",
+		"ci=svc-jenkins| svc-gitlab",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"ci": {"svc-jenkins", "svc-gitlab"}}, policy.Groups)
+	assert.Equal(t, []Override{
+		{Kind: KindScope, Name: "admin", Limits: Limits{}},
+		{Kind: KindGroup, Name: "ci", Limits: Limits{RatePerMinute: 30, Burst: 10, MaxActive: 4}},
+		{Kind: KindSubject, Name: "alice", Limits: Limits{RatePerMinute: 120, Burst: 20, MaxActive: 8}},
+	}, policy.Overrides)
+
+	for _, tc := range []struct{ overrides, groups string }{
+		{overrides: "admin=1/1/1"},
+		{overrides: "role:admin=1/1/1"},
+		{overrides: "scope:admin=1/1"},
+		{overrides: "scope:admin=1/-1/1"},
+		{overrides: "group:ops=1/1/1"},
+		{groups: "=alice"},
+	} {
+		_, err := ParsePolicy(Limits{}, tc.overrides, tc.groups)
+		assert.Error(t, err, "%+v", tc)
+	}
+}
+
+func TestPolicy_LimitsFor(t *testing.T) {
+	policy, err := ParsePolicy(
+		Limits{RatePerMinute: 10},
+		"scope:admin=0/0/0,group:ci=30/10/4,subject:svc-jenkins=60/5/1",
+		"ci=svc-jenkins|svc-gitlab",
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		caller Caller
+		source string
+		rate   int
+	}{
+		{caller: Caller{Subject: "bob", Scopes: []string{"write:power"}}, source: "default", rate: 10},
+		{caller: Caller{Subject: "bob", Scopes: []string{"write:power", "admin"}}, source: "scope:admin", rate: 0},
+		{caller: Caller{Subject: "svc-gitlab", Scopes: []string{"admin"}}, source: "group:ci", rate: 30},
+		{caller: Caller{Subject: "svc-jenkins"}, source: "subject:svc-jenkins", rate: 60},
+	}
+	for _, tc := range tests {
+		limits, source := policy.LimitsFor(tc.caller)
+		assert.Equal(t, tc.source, source, tc.caller.Subject)
+		assert.Equal(t, tc.rate, limits.RatePerMinute, tc.caller.Subject)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	policy, err := ParsePolicy(Limits{RatePerMinute: 6, Burst: 2}, "scope:admin=0/0/0", "")
+	require.NoError(t, err)
+	limiter := NewLimiter(policy)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	alice := Caller{Subject: "alice", Scopes: []string{"write:power"}}
+	assert.True(t, limiter.Allow(alice).Allowed)
+	assert.True(t, limiter.Allow(alice).Allowed)
+	denied := limiter.Allow(alice)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 10*time.Second, denied.RetryAfter, "six per minute refills one token every ten seconds")
+	assert.Equal(t, "default", denied.Source)
+
+	assert.True(t, limiter.Allow(Caller{Subject: "bob"}).Allowed, "buckets are per subject")
+	for range 10 {
+		assert.True(t, limiter.Allow(Caller{Subject: "root", Scopes: []string{"admin"}}).Allowed)
+	}
+
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, 5*time.Second, limiter.Allow(alice).RetryAfter)
+	now = now.Add(5 * time.Second)
+	assert.True(t, limiter.Allow(alice).Allowed)
+
+	usage := limiter.Usage()
+	require.Len(t, usage, 3)
+	assert.Equal(t, "alice", usage[0].Subject)
+	assert.InDelta(t, 0, usage[0].Tokens, 1e-9)
+	assert.Equal(t, now, usage[0].LastRequest)
+	assert.Equal(t, "scope:admin", usage[2].Source)
+
+	now = now.Add(2 * idleBucketTTL)
+	limiter.Allow(alice)
+	assert.Len(t, limiter.Usage(), 1, "idle buckets are pruned")
+}
@@ -8,18 +8,20 @@ import (
 )
 
 type actionRequest struct {
-	RequestID string   `json:"requestID,omitempty"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	RequestID string           `json:"requestID,omitempty"`
+	Nodes     []string         `json:"nodes,omitempty"`
+	Groups    []string         `json:"groups,omitempty"`
+	DryRun    bool             `json:"dryRun,omitempty"`
+	BootWait  *bootWaitRequest `json:"bootWait,omitempty"`
 }
 
 type resetActionRequest struct {
-	RequestID string   `json:"requestID,omitempty"`
-	Operation string   `json:"operation"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	RequestID string           `json:"requestID,omitempty"`
+	Operation string           `json:"operation"`
+	Nodes     []string         `json:"nodes,omitempty"`
+	Groups    []string         `json:"groups,omitempty"`
+	DryRun    bool             `json:"dryRun,omitempty"`
+	BootWait  *bootWaitRequest `json:"bootWait,omitempty"`
 }
 
 func (s *Server) handleActionOn(w http.ResponseWriter, r *http.Request) {
@@ -35,6 +37,7 @@ func (s *Server) handleActionOn(w http.ResponseWriter, r *http.Request) {
 		Nodes:     req.Nodes,
 		Groups:    req.Groups,
 		DryRun:    req.DryRun,
+		BootWait:  req.BootWait,
 	})
 }
 
@@ -51,6 +54,7 @@ func (s *Server) handleActionOff(w http.ResponseWriter, r *http.Request) {
 		Nodes:     req.Nodes,
 		Groups:    req.Groups,
 		DryRun:    req.DryRun,
+		BootWait:  req.BootWait,
 	})
 }
 
@@ -67,6 +71,7 @@ func (s *Server) handleActionReboot(w http.ResponseWriter, r *http.Request) {
 		Nodes:     req.Nodes,
 		Groups:    req.Groups,
 		DryRun:    req.DryRun,
+		BootWait:  req.BootWait,
 	})
 }
 
@@ -83,5 +88,6 @@ func (s *Server) handleActionReset(w http.ResponseWriter, r *http.Request) {
 		Nodes:     req.Nodes,
 		Groups:    req.Groups,
 		DryRun:    req.DryRun,
+		BootWait:  req.BootWait,
 	})
 }
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+type approvalStore interface {
+	ListTransitionApprovals(ctx context.Context, transitionID string) ([]engine.ApprovalDecision, error)
+}
+
+type approvalDecisionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type approvalSpec struct {
+	Action    string      `json:"action"`
+	Principal string      `json:"principal,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	DecidedAt timeRFC3339 `json:"decidedAt"`
+}
+
+func (s *Server) handleApproveTransition(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, engine.ApprovalActionApproved)
+}
+
+func (s *Server) handleRejectTransition(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, engine.ApprovalActionRejected)
+}
+
+func (s *Server) decideApproval(w http.ResponseWriter, r *http.Request, action string) {
+	if s.transitionRunner == nil || s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if id == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "transition id is required")
+		return
+	}
+
+	var req approvalDecisionRequest
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &req); err != nil {
+			httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+			return
+		}
+	}
+
+	_, err := s.transitionRunner.DecideApproval(r.Context(), engine.ApprovalDecision{
+		TransitionID: id,
+		Action:       action,
+		Principal:    requestedByFromContext(r),
+		Reason:       req.Reason,
+	})
+	if err != nil {
+		var queueFull *engine.QueueFullError
+		switch {
+		case errors.As(err, &queueFull):
+			respondRetryAfter(w, r, http.StatusServiceUnavailable, queueFull.RetryAfter, "%s", queueFull.Error())
+		case errors.Is(err, store.ErrNotFound):
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+		case errors.Is(err, engine.ErrSelfApproval):
+			httputil.RespondProblem(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, engine.ErrApprovalNotPending), errors.Is(err, engine.ErrApprovalExpired):
+			httputil.RespondProblem(w, r, http.StatusConflict, err.Error())
+		case errors.Is(err, engine.ErrRunnerNotStarted), errors.Is(err, engine.ErrApprovalUnsupported):
+			httputil.RespondProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		default:
+			httputil.RespondProblemf(w, r, http.StatusInternalServerError, "failed to record approval decision: %v", err)
+		}
+		return
+	}
+
+	s.respondTransition(w, r, http.StatusOK, id)
+}
+
+// respondTransition writes the current record of a transition with the task
+// page requested by taskLimit and taskOffset and its approval trail.
+func (s *Server) respondTransition(w http.ResponseWriter, r *http.Request, status int, id string) {
+	limit, offset, err := parseTaskPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transition, err := s.transitionStore.GetTransition(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return
+	}
+
+	resource, err := s.transitionResourcePage(r.Context(), transition, limit, offset)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return
+	}
+	if s.approvalStore != nil {
+		trail, trailErr := s.approvalStore.ListTransitionApprovals(r.Context(), id)
+		if trailErr != nil {
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition approvals")
+			return
+		}
+		resource.Spec.Approvals = toApprovalSpecs(trail)
+	}
+
+	httputil.RespondJSON(w, status, resource)
+}
+
+// approvalRequirement applies the approval policy to a resolved transition.
+// It returns nil when the transition may start right away.
+func (s *Server) approvalRequirement(
+	ctx context.Context,
+	operation string,
+	nodeIDs []string,
+	req transitionRequest,
+) (*engine.ApprovalRequirement, error) {
+	if req.DryRun {
+		return nil, nil
+	}
+
+	reasons := make([]string, 0, 2)
+	if s.cfg.ApprovalMaxNodes > 0 && len(nodeIDs) > s.cfg.ApprovalMaxNodes {
+		reasons = append(reasons, fmt.Sprintf("targets %d nodes, more than %d", len(nodeIDs), s.cfg.ApprovalMaxNodes))
+	}
+	if forcesOff(operation, req) {
+		groups, err := s.protectedGroupsTargeted(ctx, nodeIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(groups) > 0 {
+			reasons = append(reasons, fmt.Sprintf("forces off protected groups %s", strings.Join(groups, ", ")))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	return &engine.ApprovalRequirement{
+		Reason: strings.Join(reasons, "; "),
+		TTL:    s.cfg.ApprovalTTL,
+	}, nil
+}
+
+// forcesOff reports whether a transition may issue ForceOff, either directly
+// or by escalating a GracefulShutdown.
+func forcesOff(operation string, req transitionRequest) bool {
+	switch redfish.ResetOperation(operation) {
+	case redfish.ResetOperationForceOff:
+		return true
+	case redfish.ResetOperationGracefulShutdown:
+		return req.Escalation != nil
+	default:
+		return false
+	}
+}
+
+// protectedGroupsTargeted returns the protected groups with at least one
+// member among nodeIDs.
+func (s *Server) protectedGroupsTargeted(ctx context.Context, nodeIDs []string) ([]string, error) {
+	protected := parseTargetList(s.cfg.ApprovalProtectedGroups)
+	if len(protected) == 0 {
+		return nil, nil
+	}
+	if s.resolveGroupMembers == nil {
+		return nil, errGroupResolverUnavailable
+	}
+
+	targeted := make(map[string]struct{}, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		targeted[nodeID] = struct{}{}
+	}
+
+	groups := make([]string, 0)
+	for _, group := range protected {
+		members, err := s.resolveGroupMembers(ctx, group)
+		if err != nil {
+			if errors.Is(err, ErrGroupNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("resolving protected group %q: %w", group, err)
+		}
+		for _, member := range parseTargetList(members) {
+			if _, ok := targeted[member]; ok {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	return groups, nil
+}
+
+func toApprovalSpecs(trail []engine.ApprovalDecision) []approvalSpec {
+	if len(trail) == 0 {
+		return nil
+	}
+	specs := make([]approvalSpec, 0, len(trail))
+	for _, item := range trail {
+		specs = append(specs, approvalSpec{
+			Action:    strings.TrimSpace(item.Action),
+			Principal: strings.TrimSpace(item.Principal),
+			Reason:    strings.TrimSpace(item.Reason),
+			DecidedAt: newTimeRFC3339(item.DecidedAt),
+		})
+	}
+	return specs
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+)
+
+type bootOverrideRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	Target    string   `json:"target"`
+	Mode      string   `json:"mode,omitempty"`
+	DryRun    bool     `json:"dryRun,omitempty"`
+}
+
+type insertMediaRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	Image     string   `json:"image"`
+	DryRun    bool     `json:"dryRun,omitempty"`
+}
+
+type ejectMediaRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	DryRun    bool     `json:"dryRun,omitempty"`
+}
+
+type bootOverrideActionRequest struct {
+	RequestID      string           `json:"requestID,omitempty"`
+	Nodes          []string         `json:"nodes,omitempty"`
+	Groups         []string         `json:"groups,omitempty"`
+	Target         string           `json:"target"`
+	Mode           string           `json:"mode,omitempty"`
+	Image          string           `json:"image,omitempty"`
+	ResetOperation string           `json:"resetOperation,omitempty"`
+	DryRun         bool             `json:"dryRun,omitempty"`
+	BootWait       *bootWaitRequest `json:"bootWait,omitempty"`
+}
+
+func (s *Server) handleSetBootOverride(w http.ResponseWriter, r *http.Request) {
+	var req bootOverrideRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if strings.TrimSpace(req.Target) == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "target is required")
+		return
+	}
+
+	s.startTransition(w, r, transitionRequest{
+		RequestID:    strings.TrimSpace(req.RequestID),
+		Operation:    engine.OperationSetBootOverride,
+		Nodes:        req.Nodes,
+		Groups:       req.Groups,
+		DryRun:       req.DryRun,
+		BootControl:  true,
+		BootOverride: &engine.BootOverride{Target: req.Target, Mode: req.Mode},
+	})
+}
+
+func (s *Server) handleInsertMedia(w http.ResponseWriter, r *http.Request) {
+	var req insertMediaRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if strings.TrimSpace(req.Image) == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "image is required")
+		return
+	}
+
+	s.startTransition(w, r, transitionRequest{
+		RequestID:    strings.TrimSpace(req.RequestID),
+		Operation:    engine.OperationInsertMedia,
+		Nodes:        req.Nodes,
+		Groups:       req.Groups,
+		DryRun:       req.DryRun,
+		BootControl:  true,
+		VirtualMedia: &engine.VirtualMedia{Image: req.Image},
+	})
+}
+
+func (s *Server) handleEjectMedia(w http.ResponseWriter, r *http.Request) {
+	var req ejectMediaRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+
+	s.startTransition(w, r, transitionRequest{
+		RequestID:   strings.TrimSpace(req.RequestID),
+		Operation:   engine.OperationEjectMedia,
+		Nodes:       req.Nodes,
+		Groups:      req.Groups,
+		DryRun:      req.DryRun,
+		BootControl: true,
+	})
+}
+
+func (s *Server) handleActionBootOverride(w http.ResponseWriter, r *http.Request) {
+	var req bootOverrideActionRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if strings.TrimSpace(req.Target) == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "target is required")
+		return
+	}
+
+	transition := transitionRequest{
+		RequestID:      strings.TrimSpace(req.RequestID),
+		Operation:      engine.OperationBootOverrideReset,
+		Nodes:          req.Nodes,
+		Groups:         req.Groups,
+		DryRun:         req.DryRun,
+		BootWait:       req.BootWait,
+		BootControl:    true,
+		BootOverride:   &engine.BootOverride{Target: req.Target, Mode: req.Mode},
+		ResetOperation: strings.TrimSpace(req.ResetOperation),
+	}
+	if strings.TrimSpace(req.Image) != "" {
+		transition.VirtualMedia = &engine.VirtualMedia{Image: req.Image}
+	}
+	s.startTransition(w, r, transition)
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+var mappingDiagnosticStatuses = map[string]struct{}{
+	model.MappingStatusResolved:             {},
+	model.MappingErrorCodeNotFound:          {},
+	model.MappingErrorCodeEndpointMissing:   {},
+	model.MappingErrorCodeCredentialMissing: {},
+}
+
+type nodeMappingDiagnosticSpec struct {
+	NodeID       string             `json:"nodeID"`
+	Status       string             `json:"status"`
+	Reason       string             `json:"reason"`
+	BMCID        string             `json:"bmcID,omitempty"`
+	Endpoint     string             `json:"endpoint,omitempty"`
+	CredentialID string             `json:"credentialID,omitempty"`
+	SMD          nodeMappingSMDSpec `json:"smd"`
+	Suggestions  []string           `json:"suggestions"`
+}
+
+type nodeMappingSMDSpec struct {
+	ParentID       string                  `json:"parentID,omitempty"`
+	ParentType     string                  `json:"parentType,omitempty"`
+	Interfaces     []interfaceEvidenceSpec `json:"interfaces"`
+	ChosenEndpoint string                  `json:"chosenEndpoint,omitempty"`
+}
+
+type interfaceEvidenceSpec struct {
+	ComponentID string   `json:"componentID"`
+	IPAddrs     []string `json:"ipAddrs"`
+	Endpoint    string   `json:"endpoint,omitempty"`
+}
+
+func (s *Server) handleGetMappingDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if s.mappingSync == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, "mapping sync subsystem is not configured")
+		return
+	}
+
+	limit, offset, err := parseListPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes := parseQueryTargets(r, "code", "status")
+	for _, code := range codes {
+		if _, ok := mappingDiagnosticStatuses[code]; !ok {
+			httputil.RespondProblem(w, r, http.StatusBadRequest, fmt.Sprintf("invalid code value %q", code))
+			return
+		}
+	}
+
+	diagnostics, err := s.mappingSync.DiagnoseMappings(r.Context(), codes)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to diagnose node mappings")
+		httputil.RespondProblem(w, r, http.StatusBadGateway, "failed to diagnose node mappings")
+		return
+	}
+
+	total := len(diagnostics)
+	start := offset
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	resources := make([]httputil.Resource[nodeMappingDiagnosticSpec], 0, end-start)
+	for _, diagnostic := range diagnostics[start:end] {
+		resources = append(resources, httputil.Resource[nodeMappingDiagnosticSpec]{
+			Kind:       "NodeMappingDiagnostic",
+			APIVersion: "power/v1",
+			Metadata: httputil.Metadata{
+				ID: diagnostic.NodeID,
+			},
+			Spec: toNodeMappingDiagnosticSpec(diagnostic),
+		})
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[nodeMappingDiagnosticSpec]{
+		Kind:       "NodeMappingDiagnosticList",
+		APIVersion: "power/v1",
+		Metadata: httputil.ListMetadata{
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		},
+		Items: resources,
+	})
+}
+
+func toNodeMappingDiagnosticSpec(diagnostic model.NodeMappingDiagnostic) nodeMappingDiagnosticSpec {
+	interfaces := make([]interfaceEvidenceSpec, 0, len(diagnostic.SMD.Interfaces))
+	for _, iface := range diagnostic.SMD.Interfaces {
+		interfaces = append(interfaces, interfaceEvidenceSpec{
+			ComponentID: iface.ComponentID,
+			IPAddrs:     append([]string{}, iface.IPAddrs...),
+			Endpoint:    iface.Endpoint,
+		})
+	}
+
+	return nodeMappingDiagnosticSpec{
+		NodeID:       diagnostic.NodeID,
+		Status:       diagnostic.Status,
+		Reason:       diagnostic.Reason,
+		BMCID:        diagnostic.BMCID,
+		Endpoint:     diagnostic.Endpoint,
+		CredentialID: diagnostic.CredentialID,
+		SMD: nodeMappingSMDSpec{
+			ParentID:       diagnostic.SMD.ParentID,
+			ParentType:     diagnostic.SMD.ParentType,
+			Interfaces:     interfaces,
+			ChosenEndpoint: diagnostic.SMD.ChosenEndpoint,
+		},
+		Suggestions: append([]string{}, diagnostic.Suggestions...),
+	}
+}
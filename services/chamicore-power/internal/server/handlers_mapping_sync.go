@@ -2,18 +2,99 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	syncer "git.cscs.ch/openchami/chamicore-power/internal/sync"
 )
 
 type mappingSyncTriggerResponse struct {
 	Status string `json:"status"`
 }
 
+type mappingSyncStatusSpec struct {
+	Ready          bool                   `json:"ready"`
+	InProgress     bool                   `json:"inProgress"`
+	NotModified    bool                   `json:"notModified"`
+	LastAttemptAt  *timeRFC3339           `json:"lastAttemptAt,omitempty"`
+	LastSyncAt     *timeRFC3339           `json:"lastSyncAt,omitempty"`
+	LastError      string                 `json:"lastError,omitempty"`
+	LastCounts     mappingSyncCountsSpec  `json:"lastCounts"`
+	SuccessfulRuns int64                  `json:"successfulRuns"`
+	FailedRuns     int64                  `json:"failedRuns"`
+	LeaderElection bool                   `json:"leaderElection"`
+	Leader         bool                   `json:"leader"`
+	LeaderID       string                 `json:"leaderID,omitempty"`
+	ReplicaID      string                 `json:"replicaID,omitempty"`
+	EventsEnabled  bool                   `json:"eventsEnabled"`
+	EventsApplied  int64                  `json:"eventsApplied"`
+	LastEventAt    *timeRFC3339           `json:"lastEventAt,omitempty"`
+	LastReport     *mappingSyncReportSpec `json:"lastReport,omitempty"`
+}
+
+type mappingSyncCountsSpec struct {
+	EndpointsUpserted int `json:"endpointsUpserted"`
+	EndpointsDeleted  int `json:"endpointsDeleted"`
+	LinksUpserted     int `json:"linksUpserted"`
+	LinksDeleted      int `json:"linksDeleted"`
+}
+
+type mappingSyncReportSpec struct {
+	GeneratedAt  timeRFC3339             `json:"generatedAt"`
+	Summary      mappingSyncSummarySpec  `json:"summary"`
+	Truncated    bool                    `json:"truncated"`
+	BMCsAdded    []bmcMappingChangeSpec  `json:"bmcsAdded"`
+	BMCsChanged  []bmcMappingChangeSpec  `json:"bmcsChanged"`
+	BMCsRemoved  []bmcMappingChangeSpec  `json:"bmcsRemoved"`
+	LinksAdded   []linkMappingChangeSpec `json:"linksAdded"`
+	LinksChanged []linkMappingChangeSpec `json:"linksChanged"`
+	LinksRemoved []linkMappingChangeSpec `json:"linksRemoved"`
+}
+
+type mappingSyncSummarySpec struct {
+	BMCsAdded    int `json:"bmcsAdded"`
+	BMCsChanged  int `json:"bmcsChanged"`
+	BMCsRemoved  int `json:"bmcsRemoved"`
+	LinksAdded   int `json:"linksAdded"`
+	LinksChanged int `json:"linksChanged"`
+	LinksRemoved int `json:"linksRemoved"`
+}
+
+type bmcMappingChangeSpec struct {
+	BMCID                string `json:"bmcID"`
+	Endpoint             string `json:"endpoint,omitempty"`
+	PreviousEndpoint     string `json:"previousEndpoint,omitempty"`
+	CredentialID         string `json:"credentialID,omitempty"`
+	PreviousCredentialID string `json:"previousCredentialID,omitempty"`
+}
+
+type linkMappingChangeSpec struct {
+	NodeID        string `json:"nodeID"`
+	BMCID         string `json:"bmcID,omitempty"`
+	PreviousBMCID string `json:"previousBMCID,omitempty"`
+}
+
+func (s *Server) handleGetMappingSyncStatus(w http.ResponseWriter, r *http.Request) {
+	if s.mappingSync == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, "mapping sync subsystem is not configured")
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[mappingSyncStatusSpec]{
+		Kind:       "MappingSyncStatus",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: "power-mapping-sync",
+		},
+		Spec: toMappingSyncStatusSpec(s.mappingSync.Status()),
+	})
+}
+
 func (s *Server) handleAdminSyncMappings(w http.ResponseWriter, r *http.Request) {
 	if s.mappingSync == nil {
 		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, "mapping sync subsystem is not configured")
@@ -24,6 +105,10 @@ func (s *Server) handleAdminSyncMappings(w http.ResponseWriter, r *http.Request)
 	defer cancel()
 
 	if err := s.mappingSync.Trigger(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			httputil.RespondProblem(w, r, http.StatusGatewayTimeout, "timed out waiting for mapping sync leader to complete a run")
+			return
+		}
 		log.Ctx(r.Context()).Error().Err(err).Msg("failed to trigger mapping sync")
 		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to trigger mapping sync")
 		return
@@ -40,3 +125,81 @@ func (s *Server) handleAdminSyncMappings(w http.ResponseWriter, r *http.Request)
 		},
 	})
 }
+
+func toMappingSyncStatusSpec(status syncer.Status) mappingSyncStatusSpec {
+	spec := mappingSyncStatusSpec{
+		Ready:          status.Ready,
+		InProgress:     status.InProgress,
+		NotModified:    status.NotModified,
+		LastAttemptAt:  toTimeRFC3339Ptr(status.LastAttemptAt),
+		LastSyncAt:     toTimeRFC3339Ptr(status.LastSyncAt),
+		LastError:      status.LastError,
+		SuccessfulRuns: status.SuccessfulRuns,
+		FailedRuns:     status.FailedRuns,
+		LeaderElection: status.LeaderElection,
+		Leader:         status.Leader,
+		LeaderID:       status.LeaderID,
+		ReplicaID:      status.ReplicaID,
+		EventsEnabled:  status.EventsEnabled,
+		EventsApplied:  status.EventsApplied,
+		LastEventAt:    toTimeRFC3339Ptr(status.LastEventAt),
+		LastCounts: mappingSyncCountsSpec{
+			EndpointsUpserted: status.LastCounts.EndpointsUpserted,
+			EndpointsDeleted:  status.LastCounts.EndpointsDeleted,
+			LinksUpserted:     status.LastCounts.LinksUpserted,
+			LinksDeleted:      status.LastCounts.LinksDeleted,
+		},
+	}
+	if status.LastReport != nil {
+		report := toMappingSyncReportSpec(*status.LastReport)
+		spec.LastReport = &report
+	}
+	return spec
+}
+
+func toMappingSyncReportSpec(report model.MappingSyncReport) mappingSyncReportSpec {
+	return mappingSyncReportSpec{
+		GeneratedAt: newTimeRFC3339(report.GeneratedAt),
+		Summary: mappingSyncSummarySpec{
+			BMCsAdded:    report.Summary.BMCsAdded,
+			BMCsChanged:  report.Summary.BMCsChanged,
+			BMCsRemoved:  report.Summary.BMCsRemoved,
+			LinksAdded:   report.Summary.LinksAdded,
+			LinksChanged: report.Summary.LinksChanged,
+			LinksRemoved: report.Summary.LinksRemoved,
+		},
+		Truncated:    report.Truncated,
+		BMCsAdded:    toBMCMappingChangeSpecs(report.BMCsAdded),
+		BMCsChanged:  toBMCMappingChangeSpecs(report.BMCsChanged),
+		BMCsRemoved:  toBMCMappingChangeSpecs(report.BMCsRemoved),
+		LinksAdded:   toLinkMappingChangeSpecs(report.LinksAdded),
+		LinksChanged: toLinkMappingChangeSpecs(report.LinksChanged),
+		LinksRemoved: toLinkMappingChangeSpecs(report.LinksRemoved),
+	}
+}
+
+func toBMCMappingChangeSpecs(changes []model.BMCMappingChange) []bmcMappingChangeSpec {
+	specs := make([]bmcMappingChangeSpec, 0, len(changes))
+	for _, change := range changes {
+		specs = append(specs, bmcMappingChangeSpec{
+			BMCID:                change.BMCID,
+			Endpoint:             change.Endpoint,
+			PreviousEndpoint:     change.PreviousEndpoint,
+			CredentialID:         change.CredentialID,
+			PreviousCredentialID: change.PreviousCredentialID,
+		})
+	}
+	return specs
+}
+
+func toLinkMappingChangeSpecs(changes []model.LinkMappingChange) []linkMappingChangeSpec {
+	specs := make([]linkMappingChangeSpec, 0, len(changes))
+	for _, change := range changes {
+		specs = append(specs, linkMappingChangeSpec{
+			NodeID:        change.NodeID,
+			BMCID:         change.BMCID,
+			PreviousBMCID: change.PreviousBMCID,
+		})
+	}
+	return specs
+}
@@ -0,0 +1,470 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+// The /v1 routes serve the CSM Power Control Service (PCS) API on top of the
+// native runner and store, so tooling written against PCS keeps working.
+// PCS component reservations (deputy keys) are accepted and ignored.
+
+// PCS transition operations.
+const (
+	pcsOperationOn          = "On"
+	pcsOperationOff         = "Off"
+	pcsOperationSoftOff     = "Soft-Off"
+	pcsOperationSoftRestart = "Soft-Restart"
+	pcsOperationHardRestart = "Hard-Restart"
+	pcsOperationInit        = "Init"
+	pcsOperationForceOff    = "Force-Off"
+)
+
+// PCS transition and task states.
+const (
+	pcsStatusNew           = "new"
+	pcsStatusInProgress    = "in-progress"
+	pcsStatusCompleted     = "completed"
+	pcsStatusAborted       = "aborted"
+	pcsTaskStatusFailed    = "failed"
+	pcsTaskStatusSucceeded = "succeeded"
+)
+
+// pcsDefaultTaskDeadline is the PCS default for taskDeadlineMinutes.
+const pcsDefaultTaskDeadline = 5 * time.Minute
+
+// pcsPowerTransitions lists the PCS operations this service can translate.
+var pcsPowerTransitions = []string{
+	pcsOperationOn,
+	pcsOperationOff,
+	pcsOperationSoftOff,
+	pcsOperationSoftRestart,
+	pcsOperationHardRestart,
+	pcsOperationForceOff,
+}
+
+type pcsTransitionCreateRequest struct {
+	Operation string `json:"operation"`
+	// TaskDeadlineMinutes bounds how long Off waits for a graceful shutdown
+	// before forcing it: zero forces immediately, negative never forces.
+	TaskDeadlineMinutes *int          `json:"taskDeadlineMinutes,omitempty"`
+	Location            []pcsLocation `json:"location"`
+}
+
+type pcsLocation struct {
+	Xname     string `json:"xname"`
+	DeputyKey string `json:"deputyKey,omitempty"`
+}
+
+type pcsTransitionCreated struct {
+	TransitionID string `json:"transitionID"`
+	Operation    string `json:"operation"`
+}
+
+type pcsTransitionList struct {
+	Transitions []pcsTransition `json:"transitions"`
+}
+
+type pcsTransition struct {
+	TransitionID     string              `json:"transitionID"`
+	CreateTime       timeRFC3339         `json:"createTime"`
+	TransitionStatus string              `json:"transitionStatus"`
+	Operation        string              `json:"operation"`
+	TaskCounts       pcsTaskCounts       `json:"taskCounts"`
+	Tasks            []pcsTransitionTask `json:"tasks,omitempty"`
+}
+
+type pcsTransitionTask struct {
+	Xname                 string `json:"xname"`
+	TaskStatus            string `json:"taskStatus"`
+	TaskStatusDescription string `json:"taskStatusDescription,omitempty"`
+	Error                 string `json:"error,omitempty"`
+}
+
+type pcsTaskCounts struct {
+	Total       int `json:"total"`
+	New         int `json:"new"`
+	InProgress  int `json:"in-progress"`
+	Failed      int `json:"failed"`
+	Succeeded   int `json:"succeeded"`
+	Unsupported int `json:"un-supported"`
+}
+
+type pcsAbortResponse struct {
+	AbortStatus string `json:"abortStatus"`
+}
+
+type pcsPowerStatusRequest struct {
+	Xname                 []string `json:"xname"`
+	PowerStateFilter      string   `json:"powerStateFilter,omitempty"`
+	ManagementStateFilter string   `json:"managementStateFilter,omitempty"`
+}
+
+type pcsPowerStatusResponse struct {
+	Status []pcsPowerStatus `json:"status"`
+}
+
+type pcsPowerStatus struct {
+	Xname                     string       `json:"xname"`
+	PowerState                string       `json:"powerState"`
+	ManagementState           string       `json:"managementState"`
+	Error                     string       `json:"error,omitempty"`
+	SupportedPowerTransitions []string     `json:"supportedPowerTransitions,omitempty"`
+	LastUpdated               *timeRFC3339 `json:"lastUpdated,omitempty"`
+}
+
+func (s *Server) handlePCSCreateTransition(w http.ResponseWriter, r *http.Request) {
+	var req pcsTransitionCreateRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+
+	native, operation, err := pcsTransitionRequest(req)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transition, ok := s.launchTransition(w, r, native)
+	if !ok {
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, pcsTransitionCreated{
+		TransitionID: transition.ID,
+		Operation:    operation,
+	})
+}
+
+func (s *Server) handlePCSListTransitions(w http.ResponseWriter, r *http.Request) {
+	if s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	limit, offset, err := parseListPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, _, err := s.transitionStore.ListTransitions(r.Context(), limit, offset)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list transitions")
+		return
+	}
+
+	out := pcsTransitionList{Transitions: make([]pcsTransition, 0, len(items))}
+	for _, item := range items {
+		counts, err := s.transitionStore.CountTransitionTasksByState(r.Context(), item.ID)
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition tasks")
+			return
+		}
+		out.Transitions = append(out.Transitions, toPCSTransition(item, counts, nil))
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handlePCSGetTransition(w http.ResponseWriter, r *http.Request) {
+	if s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "transitionID"))
+	transition, tasks, err := s.loadTransition(r.Context(), id)
+	if err != nil {
+		s.respondPCSTransitionLoadError(w, r, id, err)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.State]++
+	}
+	httputil.RespondJSON(w, http.StatusOK, toPCSTransition(transition, counts, tasks))
+}
+
+func (s *Server) handlePCSDeleteTransition(w http.ResponseWriter, r *http.Request) {
+	if s.transitionRunner == nil || s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "transitionID"))
+	transition, err := s.transitionStore.GetTransition(r.Context(), id)
+	if err != nil {
+		s.respondPCSTransitionLoadError(w, r, id, err)
+		return
+	}
+	if isTerminalTransitionState(transition.State) {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "transition %q is already %s", id, transition.State)
+		return
+	}
+
+	if err := s.cancelTransition(r, id); err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to abort transition")
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusAccepted, pcsAbortResponse{AbortStatus: "Accepted"})
+}
+
+func (s *Server) handlePCSGetPowerStatus(w http.ResponseWriter, r *http.Request) {
+	s.respondPCSPowerStatus(w, r, pcsPowerStatusRequest{
+		Xname:                 parseQueryTargets(r, "xname"),
+		PowerStateFilter:      r.URL.Query().Get("powerStateFilter"),
+		ManagementStateFilter: r.URL.Query().Get("managementStateFilter"),
+	})
+}
+
+func (s *Server) handlePCSPostPowerStatus(w http.ResponseWriter, r *http.Request) {
+	var req pcsPowerStatusRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	s.respondPCSPowerStatus(w, r, req)
+}
+
+func (s *Server) respondPCSPowerStatus(w http.ResponseWriter, r *http.Request, req pcsPowerStatusRequest) {
+	if s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	powerFilter := strings.ToLower(strings.TrimSpace(req.PowerStateFilter))
+	switch powerFilter {
+	case "", "on", "off", "undefined":
+	default:
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid powerStateFilter %q", req.PowerStateFilter)
+		return
+	}
+	managementFilter := strings.ToLower(strings.TrimSpace(req.ManagementStateFilter))
+	switch managementFilter {
+	case "", "available", "unavailable":
+	default:
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid managementStateFilter %q", req.ManagementStateFilter)
+		return
+	}
+
+	targetNodes, err := s.resolveTargets(r.Context(), req.Xname, nil)
+	if err != nil {
+		s.respondTargetResolutionError(w, r, err)
+		return
+	}
+	if len(targetNodes) > s.cfg.BulkMaxNodes {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"too many target nodes: got %d, max %d",
+			len(targetNodes),
+			s.cfg.BulkMaxNodes,
+		)
+		return
+	}
+
+	statuses, err := s.nodePowerStatuses(r.Context(), targetNodes)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to resolve power status")
+		return
+	}
+
+	out := pcsPowerStatusResponse{Status: make([]pcsPowerStatus, 0, len(statuses))}
+	for _, status := range statuses {
+		item := toPCSPowerStatus(status)
+		if powerFilter != "" && item.PowerState != powerFilter {
+			continue
+		}
+		if managementFilter != "" && item.ManagementState != managementFilter {
+			continue
+		}
+		out.Status = append(out.Status, item)
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) respondPCSTransitionLoadError(w http.ResponseWriter, r *http.Request, id string, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+		return
+	}
+	httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+}
+
+// pcsTransitionRequest translates a PCS transition onto a native request and
+// returns the canonical PCS name of its operation.
+func pcsTransitionRequest(req pcsTransitionCreateRequest) (transitionRequest, string, error) {
+	nodes := make([]string, 0, len(req.Location))
+	for _, location := range req.Location {
+		nodes = append(nodes, location.Xname)
+	}
+
+	deadline := pcsDefaultTaskDeadline
+	if req.TaskDeadlineMinutes != nil {
+		deadline = time.Duration(*req.TaskDeadlineMinutes) * time.Minute
+	}
+
+	out := transitionRequest{Nodes: nodes}
+	var operation string
+	switch strings.ToLower(strings.TrimSpace(req.Operation)) {
+	case "on":
+		operation = pcsOperationOn
+		out.Operation = string(redfish.ResetOperationOn)
+	case "off":
+		// Off is a graceful shutdown forced once the task deadline passes.
+		operation = pcsOperationOff
+		switch {
+		case deadline == 0:
+			out.Operation = string(redfish.ResetOperationForceOff)
+		case deadline < 0:
+			out.Operation = string(redfish.ResetOperationGracefulShutdown)
+		default:
+			out.Operation = string(redfish.ResetOperationGracefulShutdown)
+			out.Escalation = &escalationRequest{AfterSeconds: int(deadline.Seconds())}
+		}
+	case "soft-off":
+		operation = pcsOperationSoftOff
+		out.Operation = string(redfish.ResetOperationGracefulShutdown)
+	case "soft-restart":
+		operation = pcsOperationSoftRestart
+		out.Operation = string(redfish.ResetOperationGracefulRestart)
+	case "hard-restart":
+		operation = pcsOperationHardRestart
+		out.Operation = string(redfish.ResetOperationForceRestart)
+	case "force-off":
+		operation = pcsOperationForceOff
+		out.Operation = string(redfish.ResetOperationForceOff)
+	case "init":
+		return transitionRequest{}, "", fmt.Errorf("operation %q is not supported", pcsOperationInit)
+	default:
+		return transitionRequest{}, "", fmt.Errorf("invalid operation %q", req.Operation)
+	}
+	out.PCSOperation = operation
+	return out, operation, nil
+}
+
+// pcsOperationName returns the PCS operation a transition was requested
+// with, mapping transitions created through the native API from their
+// native operation.
+func pcsOperationName(transition engine.Transition) string {
+	if transition.PCSOperation != "" {
+		return transition.PCSOperation
+	}
+	switch transition.Operation {
+	case string(redfish.ResetOperationOn):
+		return pcsOperationOn
+	case string(redfish.ResetOperationGracefulShutdown):
+		if transition.EscalateAfter > 0 {
+			return pcsOperationOff
+		}
+		return pcsOperationSoftOff
+	case string(redfish.ResetOperationGracefulRestart):
+		return pcsOperationSoftRestart
+	case string(redfish.ResetOperationForceRestart):
+		return pcsOperationHardRestart
+	case string(redfish.ResetOperationForceOff):
+		return pcsOperationForceOff
+	default:
+		return transition.Operation
+	}
+}
+
+func pcsTransitionStatus(state string) string {
+	switch state {
+	case engine.TransitionStatePending, engine.TransitionStatePendingApproval:
+		return pcsStatusNew
+	case engine.TransitionStateRunning:
+		return pcsStatusInProgress
+	case engine.TransitionStateCanceled, engine.TransitionStateRejected, engine.TransitionStateExpired:
+		return pcsStatusAborted
+	default:
+		return pcsStatusCompleted
+	}
+}
+
+func pcsTaskStatus(state string) string {
+	switch state {
+	case engine.TaskStatePending, engine.TaskStatePlanned:
+		return pcsStatusNew
+	case engine.TaskStateRunning:
+		return pcsStatusInProgress
+	case engine.TaskStateSucceeded:
+		return pcsTaskStatusSucceeded
+	default:
+		return pcsTaskStatusFailed
+	}
+}
+
+// toPCSTaskCounts folds native task counts into the PCS task states.
+func toPCSTaskCounts(counts map[string]int) pcsTaskCounts {
+	var out pcsTaskCounts
+	for state, count := range counts {
+		out.Total += count
+		switch pcsTaskStatus(state) {
+		case pcsStatusNew:
+			out.New += count
+		case pcsStatusInProgress:
+			out.InProgress += count
+		case pcsTaskStatusSucceeded:
+			out.Succeeded += count
+		default:
+			out.Failed += count
+		}
+	}
+	return out
+}
+
+func toPCSTransition(transition engine.Transition, counts map[string]int, tasks []engine.Task) pcsTransition {
+	out := pcsTransition{
+		TransitionID:     transition.ID,
+		CreateTime:       newTimeRFC3339(transition.CreatedAt),
+		TransitionStatus: pcsTransitionStatus(transition.State),
+		Operation:        pcsOperationName(transition),
+		TaskCounts:       toPCSTaskCounts(counts),
+	}
+	for _, task := range tasks {
+		out.Tasks = append(out.Tasks, pcsTransitionTask{
+			Xname:                 strings.TrimSpace(task.NodeID),
+			TaskStatus:            pcsTaskStatus(task.State),
+			TaskStatusDescription: task.State,
+			Error:                 strings.TrimSpace(task.ErrorDetail),
+		})
+	}
+	return out
+}
+
+func toPCSPowerStatus(status powerNodeStatus) pcsPowerStatus {
+	out := pcsPowerStatus{
+		Xname:           status.NodeID,
+		PowerState:      "undefined",
+		ManagementState: "available",
+		Error:           status.ErrorDetail,
+		LastUpdated:     status.LastUpdatedAt,
+	}
+	switch strings.ToLower(status.PowerState) {
+	case "on", "off":
+		out.PowerState = strings.ToLower(status.PowerState)
+	}
+	if status.State == "unresolved" {
+		out.ManagementState = "unavailable"
+		return out
+	}
+	out.SupportedPowerTransitions = pcsPowerTransitions
+	return out
+}
@@ -0,0 +1,383 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+const (
+	// pcsNodePowerLimit is the only PCS power-cap control this service sets.
+	pcsNodePowerLimit = "Node Power Limit"
+
+	pcsPowerCapTypeSnapshot = "snapshot"
+	pcsPowerCapTypePatch    = "patch"
+
+	// PCS snapshots are read synchronously and kept in memory, per replica,
+	// until clients fetch them.
+	pcsSnapshotTTL      = time.Hour
+	pcsSnapshotCapacity = 256
+)
+
+type pcsPowerCapSnapshotRequest struct {
+	Xnames []string `json:"xnames"`
+}
+
+type pcsPowerCapPatchRequest struct {
+	Components []pcsPowerCapComponentPatch `json:"components"`
+}
+
+type pcsPowerCapComponentPatch struct {
+	Xname    string                    `json:"xname"`
+	Controls []pcsPowerCapControlPatch `json:"controls"`
+}
+
+type pcsPowerCapControlPatch struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+type pcsPowerCapTaskID struct {
+	TaskID string `json:"taskID"`
+}
+
+type pcsPowerCapTaskList struct {
+	Tasks []pcsPowerCapTask `json:"tasks"`
+}
+
+type pcsPowerCapTask struct {
+	TaskID                  string                 `json:"taskID"`
+	Type                    string                 `json:"type"`
+	TaskCreateTime          timeRFC3339            `json:"taskCreateTime"`
+	AutomaticExpirationTime *timeRFC3339           `json:"automaticExpirationTime,omitempty"`
+	TaskStatus              string                 `json:"taskStatus"`
+	TaskCounts              pcsTaskCounts          `json:"taskCounts"`
+	Components              []pcsPowerCapComponent `json:"components,omitempty"`
+}
+
+type pcsPowerCapComponent struct {
+	Xname          string             `json:"xname"`
+	Error          string             `json:"error,omitempty"`
+	Limits         *pcsPowerCapLimits `json:"limits,omitempty"`
+	PowerCapLimits []pcsPowerCapLimit `json:"powerCapLimits,omitempty"`
+}
+
+type pcsPowerCapLimits struct {
+	HostLimitMax *int `json:"hostLimitMax,omitempty"`
+	HostLimitMin *int `json:"hostLimitMin,omitempty"`
+}
+
+type pcsPowerCapLimit struct {
+	Name         string `json:"name"`
+	CurrentValue *int   `json:"currentValue,omitempty"`
+	MaximumValue *int   `json:"maximumValue,omitempty"`
+	MinimumValue *int   `json:"minimumValue,omitempty"`
+}
+
+// pcsSnapshotCache holds completed PCS power-cap snapshots until they expire.
+type pcsSnapshotCache struct {
+	mu    sync.Mutex
+	items map[string]pcsSnapshotEntry
+}
+
+type pcsSnapshotEntry struct {
+	task      pcsPowerCapTask
+	expiresAt time.Time
+}
+
+func newPCSSnapshotCache() *pcsSnapshotCache {
+	return &pcsSnapshotCache{items: make(map[string]pcsSnapshotEntry)}
+}
+
+func (c *pcsSnapshotCache) put(task pcsPowerCapTask, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked(time.Now())
+	if len(c.items) >= pcsSnapshotCapacity {
+		oldestID := ""
+		var oldest time.Time
+		for id, entry := range c.items {
+			if oldestID == "" || entry.expiresAt.Before(oldest) {
+				oldestID, oldest = id, entry.expiresAt
+			}
+		}
+		delete(c.items, oldestID)
+	}
+	c.items[task.TaskID] = pcsSnapshotEntry{task: task, expiresAt: expiresAt}
+}
+
+func (c *pcsSnapshotCache) get(id string) (pcsPowerCapTask, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked(time.Now())
+	entry, ok := c.items[id]
+	return entry.task, ok
+}
+
+// list returns the cached snapshots, newest first.
+func (c *pcsSnapshotCache) list() []pcsPowerCapTask {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked(time.Now())
+	out := make([]pcsPowerCapTask, 0, len(c.items))
+	for _, entry := range c.items {
+		out = append(out, entry.task)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].TaskCreateTime > out[j].TaskCreateTime
+	})
+	return out
+}
+
+func (c *pcsSnapshotCache) pruneLocked(now time.Time) {
+	for id, entry := range c.items {
+		if !now.Before(entry.expiresAt) {
+			delete(c.items, id)
+		}
+	}
+}
+
+func (s *Server) handlePCSPowerCapSnapshot(w http.ResponseWriter, r *http.Request) {
+	if s.powerCapReader == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errPowerCapUnavailable.Error())
+		return
+	}
+
+	var req pcsPowerCapSnapshotRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+
+	targetNodes, err := s.resolveTargets(r.Context(), req.Xnames, nil)
+	if err != nil {
+		s.respondTargetResolutionError(w, r, err)
+		return
+	}
+	if len(targetNodes) > s.cfg.BulkMaxNodes {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"too many target nodes: got %d, max %d",
+			len(targetNodes),
+			s.cfg.BulkMaxNodes,
+		)
+		return
+	}
+
+	readings, err := s.powerCapReader.ReadPowerCaps(r.Context(), targetNodes)
+	if err != nil {
+		if errors.Is(err, engine.ErrPowerCapUnsupported) {
+			httputil.RespondProblem(w, r, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to read power caps")
+		return
+	}
+
+	id, err := newPCSSnapshotID()
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to create snapshot")
+		return
+	}
+	now := time.Now().UTC()
+	expiresAt := newTimeRFC3339(now.Add(pcsSnapshotTTL))
+	task := pcsPowerCapTask{
+		TaskID:                  id,
+		Type:                    pcsPowerCapTypeSnapshot,
+		TaskCreateTime:          newTimeRFC3339(now),
+		AutomaticExpirationTime: &expiresAt,
+		TaskStatus:              pcsStatusCompleted,
+		Components:              make([]pcsPowerCapComponent, 0, len(readings)),
+	}
+	for _, reading := range readings {
+		component := pcsPowerCapComponent{
+			Xname: strings.TrimSpace(reading.NodeID),
+			Error: strings.TrimSpace(reading.ErrorDetail),
+		}
+		task.TaskCounts.Total++
+		if component.Error != "" {
+			task.TaskCounts.Failed++
+		} else {
+			task.TaskCounts.Succeeded++
+			component.Limits = &pcsPowerCapLimits{
+				HostLimitMax: reading.Reading.MaxLimitWatts,
+				HostLimitMin: reading.Reading.MinLimitWatts,
+			}
+			component.PowerCapLimits = []pcsPowerCapLimit{{
+				Name:         pcsNodePowerLimit,
+				CurrentValue: reading.Reading.LimitWatts,
+				MaximumValue: reading.Reading.MaxLimitWatts,
+				MinimumValue: reading.Reading.MinLimitWatts,
+			}}
+		}
+		task.Components = append(task.Components, component)
+	}
+	s.pcsSnapshots.put(task, now.Add(pcsSnapshotTTL))
+
+	httputil.RespondJSON(w, http.StatusOK, pcsPowerCapTaskID{TaskID: id})
+}
+
+func (s *Server) handlePCSPatchPowerCap(w http.ResponseWriter, r *http.Request) {
+	var req pcsPowerCapPatchRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+
+	// Every component has to ask for the same node limit, which then runs as
+	// one native power-cap transition.
+	nodes := make([]string, 0, len(req.Components))
+	var limit *int
+	for _, component := range req.Components {
+		if len(component.Controls) == 0 {
+			httputil.RespondProblemf(w, r, http.StatusBadRequest, "component %q has no controls", component.Xname)
+			return
+		}
+		for _, control := range component.Controls {
+			if !strings.EqualFold(strings.TrimSpace(control.Name), pcsNodePowerLimit) {
+				httputil.RespondProblemf(
+					w,
+					r,
+					http.StatusBadRequest,
+					"unsupported control %q: only %q can be set",
+					control.Name,
+					pcsNodePowerLimit,
+				)
+				return
+			}
+			if limit != nil && *limit != control.Value {
+				httputil.RespondProblemf(w, r, http.StatusBadRequest, "all components must set the same %q value", pcsNodePowerLimit)
+				return
+			}
+			value := control.Value
+			limit = &value
+		}
+		nodes = append(nodes, component.Xname)
+	}
+	if limit == nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, engine.ErrNoTargetNodes.Error())
+		return
+	}
+	if *limit < 0 {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "%q must not be negative", pcsNodePowerLimit)
+		return
+	}
+
+	transition, ok := s.launchTransition(w, r, transitionRequest{
+		Operation:     engine.OperationPowerCap,
+		Nodes:         nodes,
+		PowerCapWatts: limit,
+	})
+	if !ok {
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, pcsPowerCapTaskID{TaskID: transition.ID})
+}
+
+func (s *Server) handlePCSListPowerCap(w http.ResponseWriter, r *http.Request) {
+	out := pcsPowerCapTaskList{Tasks: s.pcsSnapshots.list()}
+	for i := range out.Tasks {
+		out.Tasks[i].Components = nil
+	}
+
+	if s.transitionStore != nil {
+		items, _, err := s.transitionStore.ListTransitions(r.Context(), maxTransitionListLimit, 0)
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list transitions")
+			return
+		}
+		for _, item := range items {
+			if item.Operation != engine.OperationPowerCap {
+				continue
+			}
+			counts, err := s.transitionStore.CountTransitionTasksByState(r.Context(), item.ID)
+			if err != nil {
+				httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition tasks")
+				return
+			}
+			out.Tasks = append(out.Tasks, toPCSPowerCapPatchTask(item, counts, nil))
+		}
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handlePCSGetPowerCap(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "taskID"))
+	if task, ok := s.pcsSnapshots.get(id); ok {
+		httputil.RespondJSON(w, http.StatusOK, task)
+		return
+	}
+
+	if s.transitionStore == nil {
+		httputil.RespondProblemf(w, r, http.StatusNotFound, "power-cap task %q not found", id)
+		return
+	}
+	transition, tasks, err := s.loadTransition(r.Context(), id)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return
+	}
+	if err != nil || transition.Operation != engine.OperationPowerCap {
+		httputil.RespondProblemf(w, r, http.StatusNotFound, "power-cap task %q not found", id)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.State]++
+	}
+	httputil.RespondJSON(w, http.StatusOK, toPCSPowerCapPatchTask(transition, counts, tasks))
+}
+
+// toPCSPowerCapPatchTask presents a power-cap transition as a PCS patch task.
+func toPCSPowerCapPatchTask(transition engine.Transition, counts map[string]int, tasks []engine.Task) pcsPowerCapTask {
+	status := pcsTransitionStatus(transition.State)
+	if status == pcsStatusAborted {
+		status = pcsStatusCompleted
+	}
+
+	out := pcsPowerCapTask{
+		TaskID:         transition.ID,
+		Type:           pcsPowerCapTypePatch,
+		TaskCreateTime: newTimeRFC3339(transition.CreatedAt),
+		TaskStatus:     status,
+		TaskCounts:     toPCSTaskCounts(counts),
+	}
+	for _, task := range tasks {
+		out.Components = append(out.Components, pcsPowerCapComponent{
+			Xname: strings.TrimSpace(task.NodeID),
+			Error: strings.TrimSpace(task.ErrorDetail),
+			PowerCapLimits: []pcsPowerCapLimit{{
+				Name:         pcsNodePowerLimit,
+				CurrentValue: task.PowerCapWatts,
+			}},
+		})
+	}
+	return out
+}
+
+func newPCSSnapshotID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,493 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+func newPCSTestServer(t *testing.T, st *mockPowerStore, runner *mockTransitionRunner, opts ...Option) *Server {
+	t.Helper()
+
+	if runner != nil {
+		opts = append(opts, WithTransitionRunner(runner))
+	}
+	cfg := config.Config{DevMode: true, BulkMaxNodes: 20, BulkHardMaxNodes: 20, PCSCompatEnabled: true}
+	return New(st, cfg, "v1", "abc", "now", opts...)
+}
+
+func servePCS(t *testing.T, srv *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	return resp
+}
+
+func decodePCSObject(t *testing.T, resp *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+
+	var out map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func TestPCSCompat_Disabled(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, nil)
+
+	resp := servePCS(t, srv, http.MethodGet, "/v1/transitions", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestPCSCreateTransition_TranslatesOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		operation  string
+		escalation time.Duration
+		pcsName    string
+	}{
+		{
+			name:      "on",
+			body:      `{"operation":"on","location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation: "On",
+			pcsName:   "On",
+		},
+		{
+			name:       "off escalates at the task deadline",
+			body:       `{"operation":"Off","taskDeadlineMinutes":10,"location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation:  "GracefulShutdown",
+			escalation: 10 * time.Minute,
+			pcsName:    "Off",
+		},
+		{
+			name:       "off uses the default deadline",
+			body:       `{"operation":"off","location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation:  "GracefulShutdown",
+			escalation: pcsDefaultTaskDeadline,
+			pcsName:    "Off",
+		},
+		{
+			name:      "off without deadline forces",
+			body:      `{"operation":"off","taskDeadlineMinutes":0,"location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation: "ForceOff",
+			pcsName:   "Off",
+		},
+		{
+			name:      "off with negative deadline never forces",
+			body:      `{"operation":"off","taskDeadlineMinutes":-1,"location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation: "GracefulShutdown",
+			pcsName:   "Off",
+		},
+		{
+			name:      "soft-restart",
+			body:      `{"operation":"soft-restart","location":[{"xname":"x3000c0s19b1n0","deputyKey":"e2b5e3b6-4b6e-4f1c-8e5a-7d2c3c1a9f00"}]}`,
+			operation: "GracefulRestart",
+			pcsName:   "Soft-Restart",
+		},
+		{
+			name:      "hard-restart",
+			body:      `{"operation":"hard-restart","location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation: "ForceRestart",
+			pcsName:   "Hard-Restart",
+		},
+		{
+			name:      "force-off",
+			body:      `{"operation":"force-off","location":[{"xname":"x3000c0s19b1n0"}]}`,
+			operation: "ForceOff",
+			pcsName:   "Force-Off",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got engine.StartRequest
+			runner := &mockTransitionRunner{
+				startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+					got = req
+					return engine.Transition{ID: "8a7c1bbf-d6f0-4e3a-a1a0-1f4d5d0d9b2e", Operation: req.Operation}, nil
+				},
+			}
+			srv := newPCSTestServer(t, &mockPowerStore{}, runner)
+
+			resp := servePCS(t, srv, http.MethodPost, "/v1/transitions", tt.body)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+			assert.Equal(t, tt.operation, got.Operation)
+			assert.Equal(t, tt.pcsName, got.PCSOperation)
+			assert.Equal(t, []string{"x3000c0s19b1n0"}, got.NodeIDs)
+			if tt.escalation > 0 {
+				require.NotNil(t, got.Escalation)
+				assert.Equal(t, tt.escalation, got.Escalation.After)
+			} else {
+				assert.Nil(t, got.Escalation)
+			}
+
+			out := decodePCSObject(t, resp)
+			assert.Equal(t, map[string]any{
+				"transitionID": "8a7c1bbf-d6f0-4e3a-a1a0-1f4d5d0d9b2e",
+				"operation":    tt.pcsName,
+			}, out)
+		})
+	}
+}
+
+func TestPCSTransition_RoundTripsOffOperation(t *testing.T) {
+	for _, deadline := range []string{`"taskDeadlineMinutes":0,`, `"taskDeadlineMinutes":-1,`, ""} {
+		t.Run(deadline, func(t *testing.T) {
+			var stored engine.Transition
+			runner := &mockTransitionRunner{
+				startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+					stored = engine.Transition{
+						ID:           "tr-1",
+						Operation:    req.Operation,
+						State:        engine.TransitionStatePending,
+						PCSOperation: req.PCSOperation,
+					}
+					if req.Escalation != nil {
+						stored.EscalateAfter = req.Escalation.After
+					}
+					return stored, nil
+				},
+			}
+			st := &mockPowerStore{
+				getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+					return stored, nil
+				},
+			}
+			srv := newPCSTestServer(t, st, runner)
+
+			body := `{"operation":"Off",` + deadline + `"location":[{"xname":"x3000c0s19b1n0"}]}`
+			resp := servePCS(t, srv, http.MethodPost, "/v1/transitions", body)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			assert.Equal(t, "Off", decodePCSObject(t, resp)["operation"])
+
+			resp = servePCS(t, srv, http.MethodGet, "/v1/transitions/tr-1", "")
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			assert.Equal(t, "Off", decodePCSObject(t, resp)["operation"])
+		})
+	}
+}
+
+func TestPCSCreateTransition_RejectsInvalidRequests(t *testing.T) {
+	srv := newPCSTestServer(t, &mockPowerStore{}, &mockTransitionRunner{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "init", body: `{"operation":"init","location":[{"xname":"x3000c0s19b1n0"}]}`},
+		{name: "unknown operation", body: `{"operation":"reboot","location":[{"xname":"x3000c0s19b1n0"}]}`},
+		{name: "no location", body: `{"operation":"on","location":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := servePCS(t, srv, http.MethodPost, "/v1/transitions", tt.body)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestPCSGetTransition_ReturnsPCSShape(t *testing.T) {
+	created := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			return engine.Transition{
+				ID:            id,
+				Operation:     "GracefulShutdown",
+				State:         engine.TransitionStateRunning,
+				EscalateAfter: 5 * time.Minute,
+				CreatedAt:     created,
+			}, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{
+				{NodeID: "x3000c0s19b1n0", State: engine.TaskStateSucceeded},
+				{NodeID: "x3000c0s19b2n0", State: engine.TaskStateRunning},
+				{NodeID: "x3000c0s19b3n0", State: engine.TaskStateFailed, ErrorDetail: "BMC unreachable"},
+			}, nil
+		},
+	}
+	srv := newPCSTestServer(t, st, &mockTransitionRunner{})
+
+	resp := servePCS(t, srv, http.MethodGet, "/v1/transitions/tr-1", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	out := decodePCSObject(t, resp)
+	assert.Equal(t, "tr-1", out["transitionID"])
+	assert.Equal(t, "2026-03-04T12:00:00Z", out["createTime"])
+	assert.Equal(t, "in-progress", out["transitionStatus"])
+	assert.Equal(t, "Off", out["operation"])
+	assert.Equal(t, map[string]any{
+		"total":        float64(3),
+		"new":          float64(0),
+		"in-progress":  float64(1),
+		"failed":       float64(1),
+		"succeeded":    float64(1),
+		"un-supported": float64(0),
+	}, out["taskCounts"])
+
+	tasks, ok := out["tasks"].([]any)
+	require.True(t, ok)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, map[string]any{
+		"xname":                 "x3000c0s19b3n0",
+		"taskStatus":            "failed",
+		"taskStatusDescription": "failed",
+		"error":                 "BMC unreachable",
+	}, tasks[2])
+}
+
+func TestPCSListTransitions(t *testing.T) {
+	st := &mockPowerStore{
+		listTransitionsFn: func(ctx context.Context, limit, offset int) ([]engine.Transition, int, error) {
+			return []engine.Transition{
+				{ID: "tr-2", Operation: "On", State: engine.TransitionStateCompleted},
+				{ID: "tr-1", Operation: "ForceOff", State: engine.TransitionStateCanceled},
+			}, 2, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{{NodeID: "x3000c0s19b1n0", State: engine.TaskStateSucceeded}}, nil
+		},
+	}
+	srv := newPCSTestServer(t, st, &mockTransitionRunner{})
+
+	resp := servePCS(t, srv, http.MethodGet, "/v1/transitions", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var out pcsTransitionList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Len(t, out.Transitions, 2)
+	assert.Equal(t, "completed", out.Transitions[0].TransitionStatus)
+	assert.Equal(t, 1, out.Transitions[0].TaskCounts.Succeeded)
+	assert.Empty(t, out.Transitions[0].Tasks)
+	assert.Equal(t, "aborted", out.Transitions[1].TransitionStatus)
+	assert.Equal(t, "Force-Off", out.Transitions[1].Operation)
+}
+
+func TestPCSDeleteTransition(t *testing.T) {
+	aborted := ""
+	runner := &mockTransitionRunner{
+		abortTransitionFn: func(ctx context.Context, transitionID string) error {
+			aborted = transitionID
+			return nil
+		},
+	}
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			switch id {
+			case "running":
+				return engine.Transition{ID: id, State: engine.TransitionStateRunning}, nil
+			case "done":
+				return engine.Transition{ID: id, State: engine.TransitionStateCompleted}, nil
+			default:
+				return engine.Transition{}, store.ErrNotFound
+			}
+		},
+	}
+	srv := newPCSTestServer(t, st, runner)
+
+	resp := servePCS(t, srv, http.MethodDelete, "/v1/transitions/running", "")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, "running", aborted)
+	assert.Equal(t, map[string]any{"abortStatus": "Accepted"}, decodePCSObject(t, resp))
+
+	resp = servePCS(t, srv, http.MethodDelete, "/v1/transitions/done", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = servePCS(t, srv, http.MethodDelete, "/v1/transitions/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestPCSPowerStatus(t *testing.T) {
+	st := &mockPowerStore{
+		resolveNodeMappingsFn: func(ctx context.Context, nodeIDs []string) ([]model.NodePowerMapping, []model.NodeMappingError, error) {
+			return nil, []model.NodeMappingError{{NodeID: "x3000c0s19b3n0", Detail: "node mapping missing"}}, nil
+		},
+		listLatestTasksByNode: func(ctx context.Context, nodeIDs []string) ([]engine.Task, error) {
+			return []engine.Task{
+				{NodeID: "x3000c0s19b1n0", Operation: "On", State: engine.TaskStateSucceeded, FinalPowerState: "On", UpdatedAt: time.Now().UTC()},
+				{NodeID: "x3000c0s19b2n0", Operation: "ForceOff", State: engine.TaskStateSucceeded, UpdatedAt: time.Now().UTC()},
+			}, nil
+		},
+	}
+	srv := newPCSTestServer(t, st, &mockTransitionRunner{})
+
+	resp := servePCS(
+		t,
+		srv,
+		http.MethodGet,
+		"/v1/power-status?xname=x3000c0s19b1n0&xname=x3000c0s19b2n0&xname=x3000c0s19b3n0",
+		"",
+	)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var out pcsPowerStatusResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Len(t, out.Status, 3)
+	assert.Equal(t, "on", out.Status[0].PowerState)
+	assert.Equal(t, "available", out.Status[0].ManagementState)
+	assert.Contains(t, out.Status[0].SupportedPowerTransitions, "Soft-Restart")
+	assert.NotNil(t, out.Status[0].LastUpdated)
+	assert.Equal(t, "off", out.Status[1].PowerState)
+	assert.Equal(t, "undefined", out.Status[2].PowerState)
+	assert.Equal(t, "unavailable", out.Status[2].ManagementState)
+	assert.Equal(t, "node mapping missing", out.Status[2].Error)
+
+	resp = servePCS(
+		t,
+		srv,
+		http.MethodPost,
+		"/v1/power-status",
+		`{"xname":["x3000c0s19b1n0","x3000c0s19b2n0"],"powerStateFilter":"off","managementStateFilter":"available"}`,
+	)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	out = pcsPowerStatusResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Len(t, out.Status, 1)
+	assert.Equal(t, "x3000c0s19b2n0", out.Status[0].Xname)
+
+	resp = servePCS(t, srv, http.MethodGet, "/v1/power-status?xname=x3000c0s19b1n0&powerStateFilter=warm", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestPCSPowerCapSnapshot(t *testing.T) {
+	limit, minLimit, maxLimit := 500, 350, 900
+	reader := &mockPowerCapReader{
+		readPowerCapsFn: func(ctx context.Context, nodeIDs []string) ([]engine.NodePowerReading, error) {
+			assert.Equal(t, []string{"x3000c0s19b1n0", "x3000c0s19b2n0"}, nodeIDs)
+			return []engine.NodePowerReading{
+				{
+					NodeID: "x3000c0s19b1n0",
+					Reading: engine.PowerReading{
+						LimitWatts:    &limit,
+						MinLimitWatts: &minLimit,
+						MaxLimitWatts: &maxLimit,
+					},
+				},
+				{NodeID: "x3000c0s19b2n0", ErrorDetail: "power limit not supported"},
+			}, nil
+		},
+	}
+	srv := newPCSTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, WithPowerCapReader(reader))
+
+	resp := servePCS(t, srv, http.MethodPost, "/v1/power-cap/snapshot", `{"xnames":["x3000c0s19b1n0","x3000c0s19b2n0"]}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var created pcsPowerCapTaskID
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotEmpty(t, created.TaskID)
+
+	resp = servePCS(t, srv, http.MethodGet, "/v1/power-cap/"+created.TaskID, "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	out := decodePCSObject(t, resp)
+	assert.Equal(t, created.TaskID, out["taskID"])
+	assert.Equal(t, "snapshot", out["type"])
+	assert.Equal(t, "completed", out["taskStatus"])
+	assert.NotEmpty(t, out["taskCreateTime"])
+	assert.NotEmpty(t, out["automaticExpirationTime"])
+
+	components, ok := out["components"].([]any)
+	require.True(t, ok)
+	require.Len(t, components, 2)
+	assert.Equal(t, map[string]any{
+		"xname": "x3000c0s19b1n0",
+		"limits": map[string]any{
+			"hostLimitMax": float64(900),
+			"hostLimitMin": float64(350),
+		},
+		"powerCapLimits": []any{map[string]any{
+			"name":         "Node Power Limit",
+			"currentValue": float64(500),
+			"maximumValue": float64(900),
+			"minimumValue": float64(350),
+		}},
+	}, components[0])
+	assert.Equal(t, map[string]any{
+		"xname": "x3000c0s19b2n0",
+		"error": "power limit not supported",
+	}, components[1])
+
+	resp = servePCS(t, srv, http.MethodGet, "/v1/power-cap", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var list pcsPowerCapTaskList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, created.TaskID, list.Tasks[0].TaskID)
+	assert.Equal(t, 1, list.Tasks[0].TaskCounts.Failed)
+	assert.Empty(t, list.Tasks[0].Components)
+}
+
+func TestPCSPatchPowerCap(t *testing.T) {
+	var got engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			got = req
+			return engine.Transition{ID: "tr-cap", Operation: req.Operation}, nil
+		},
+	}
+	watts := 400
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			if id == "tr-on" {
+				return engine.Transition{ID: id, Operation: "On"}, nil
+			}
+			return engine.Transition{ID: id, Operation: engine.OperationPowerCap, State: engine.TransitionStateCompleted}, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{{NodeID: "x3000c0s19b1n0", State: engine.TaskStateSucceeded, PowerCapWatts: &watts}}, nil
+		},
+	}
+	srv := newPCSTestServer(t, st, runner)
+
+	resp := servePCS(t, srv, http.MethodPatch, "/v1/power-cap", `{
+		"components": [
+			{"xname": "x3000c0s19b1n0", "controls": [{"name": "Node Power Limit", "value": 400}]},
+			{"xname": "x3000c0s19b2n0", "controls": [{"name": "Node Power Limit", "value": 400}]}
+		]
+	}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, map[string]any{"taskID": "tr-cap"}, decodePCSObject(t, resp))
+	assert.Equal(t, engine.OperationPowerCap, got.Operation)
+	assert.Equal(t, []string{"x3000c0s19b1n0", "x3000c0s19b2n0"}, got.NodeIDs)
+	require.NotNil(t, got.PowerCapWatts)
+	assert.Equal(t, 400, *got.PowerCapWatts)
+
+	resp = servePCS(t, srv, http.MethodGet, "/v1/power-cap/tr-cap", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var task pcsPowerCapTask
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&task))
+	assert.Equal(t, "patch", task.Type)
+	assert.Equal(t, "completed", task.TaskStatus)
+	require.Len(t, task.Components, 1)
+	require.Len(t, task.Components[0].PowerCapLimits, 1)
+	assert.Equal(t, 400, *task.Components[0].PowerCapLimits[0].CurrentValue)
+
+	resp = servePCS(t, srv, http.MethodGet, "/v1/power-cap/tr-on", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	for name, body := range map[string]string{
+		"mixed values":    `{"components":[{"xname":"a","controls":[{"name":"Node Power Limit","value":400}]},{"xname":"b","controls":[{"name":"Node Power Limit","value":500}]}]}`,
+		"unknown control": `{"components":[{"xname":"a","controls":[{"name":"Accelerator0 Power Limit","value":300}]}]}`,
+		"no controls":     `{"components":[{"xname":"a","controls":[]}]}`,
+		"no components":   `{"components":[]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := servePCS(t, srv, http.MethodPatch, "/v1/power-cap", body)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
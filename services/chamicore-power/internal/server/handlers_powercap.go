@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+)
+
+var errPowerCapUnavailable = errors.New("power capping is not configured")
+
+type powerCapReader interface {
+	ReadPowerCaps(ctx context.Context, nodeIDs []string) ([]engine.NodePowerReading, error)
+}
+
+// WithPowerCapReader sets the backend used to read node power limits and draw.
+func WithPowerCapReader(reader powerCapReader) Option {
+	return func(s *Server) {
+		s.powerCapReader = reader
+	}
+}
+
+type powerCapRequest struct {
+	RequestID  string   `json:"requestID,omitempty"`
+	Nodes      []string `json:"nodes,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	LimitWatts *int     `json:"limitWatts"`
+	DryRun     bool     `json:"dryRun,omitempty"`
+}
+
+type powerCapResponse struct {
+	Nodes []powerCapNodeReading `json:"nodes"`
+	Total int                   `json:"total"`
+}
+
+type powerCapNodeReading struct {
+	NodeID        string   `json:"nodeID"`
+	BMCID         string   `json:"bmcID,omitempty"`
+	LimitWatts    *int     `json:"limitWatts,omitempty"`
+	ConsumedWatts *float64 `json:"consumedWatts,omitempty"`
+	MinLimitWatts *int     `json:"minLimitWatts,omitempty"`
+	MaxLimitWatts *int     `json:"maxLimitWatts,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	ErrorDetail   string   `json:"errorDetail,omitempty"`
+}
+
+func (s *Server) handleGetPowerCap(w http.ResponseWriter, r *http.Request) {
+	if s.powerCapReader == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errPowerCapUnavailable.Error())
+		return
+	}
+
+	nodes := parseQueryTargets(r, "nodes", "node")
+	groups := parseQueryTargets(r, "groups", "group")
+	targetNodes, err := s.resolveTargets(r.Context(), nodes, groups)
+	if err != nil {
+		s.respondTargetResolutionError(w, r, err)
+		return
+	}
+	if len(targetNodes) > s.cfg.BulkMaxNodes {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"too many target nodes: got %d, max %d",
+			len(targetNodes),
+			s.cfg.BulkMaxNodes,
+		)
+		return
+	}
+
+	readings, err := s.powerCapReader.ReadPowerCaps(r.Context(), targetNodes)
+	if err != nil {
+		if errors.Is(err, engine.ErrPowerCapUnsupported) {
+			httputil.RespondProblem(w, r, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to read power caps")
+		return
+	}
+
+	items := make([]powerCapNodeReading, 0, len(readings))
+	for _, reading := range readings {
+		items = append(items, powerCapNodeReading{
+			NodeID:        strings.TrimSpace(reading.NodeID),
+			BMCID:         strings.TrimSpace(reading.BMCID),
+			LimitWatts:    reading.Reading.LimitWatts,
+			ConsumedWatts: reading.Reading.ConsumedWatts,
+			MinLimitWatts: reading.Reading.MinLimitWatts,
+			MaxLimitWatts: reading.Reading.MaxLimitWatts,
+			Source:        strings.TrimSpace(reading.Reading.Source),
+			ErrorDetail:   strings.TrimSpace(reading.ErrorDetail),
+		})
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[powerCapResponse]{
+		Kind:       "PowerCap",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: "powercap",
+		},
+		Spec: powerCapResponse{
+			Nodes: items,
+			Total: len(items),
+		},
+	})
+}
+
+func (s *Server) handleApplyPowerCap(w http.ResponseWriter, r *http.Request) {
+	var req powerCapRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if req.LimitWatts == nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "limitWatts is required")
+		return
+	}
+	if *req.LimitWatts < 0 {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "limitWatts must not be negative")
+		return
+	}
+
+	s.startTransition(w, r, transitionRequest{
+		RequestID:     strings.TrimSpace(req.RequestID),
+		Operation:     engine.OperationPowerCap,
+		Nodes:         req.Nodes,
+		Groups:        req.Groups,
+		DryRun:        req.DryRun,
+		PowerCapWatts: req.LimitWatts,
+	})
+}
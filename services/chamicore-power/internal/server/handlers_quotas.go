@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"git.cscs.ch/openchami/chamicore-lib/auth"
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/quota"
+)
+
+// activeQuotaRetryAfter is the Retry-After hint sent to callers at their
+// active transition quota. Unlike the rate limit there is no exact time at
+// which a slot frees up.
+const activeQuotaRetryAfter = 30 * time.Second
+
+type quotaStore interface {
+	CountActiveTransitionsByRequester(ctx context.Context, requesters []string) (map[string]int, error)
+}
+
+type quotaLimitsSpec struct {
+	RatePerMinute int `json:"ratePerMinute"`
+	Burst         int `json:"burst"`
+	MaxActive     int `json:"maxActive"`
+}
+
+type quotaOverrideSpec struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	Limits quotaLimitsSpec `json:"limits"`
+}
+
+type quotaPolicySpec struct {
+	Default   quotaLimitsSpec     `json:"default"`
+	Overrides []quotaOverrideSpec `json:"overrides"`
+	Groups    map[string][]string `json:"groups"`
+}
+
+type callerQuotaSpec struct {
+	Subject string          `json:"subject"`
+	Scopes  []string        `json:"scopes"`
+	Source  string          `json:"source"`
+	Limits  quotaLimitsSpec `json:"limits"`
+	// RemainingRequests is unset when the caller has no rate limit.
+	RemainingRequests *int         `json:"remainingRequests,omitempty"`
+	ActiveTransitions int          `json:"activeTransitions"`
+	LastRequestAt     *timeRFC3339 `json:"lastRequestAt,omitempty"`
+}
+
+type quotaUsageSpec struct {
+	Enabled bool              `json:"enabled"`
+	Policy  quotaPolicySpec   `json:"policy"`
+	Callers []callerQuotaSpec `json:"callers"`
+}
+
+// callerFromContext identifies the caller for quota purposes.
+func callerFromContext(r *http.Request) quota.Caller {
+	caller := quota.Caller{Subject: requestedByFromContext(r)}
+	if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+		caller.Scopes = claims.Scopes
+	}
+	return caller
+}
+
+// limitCaller rejects requests over the caller's rate limit with 429.
+func (s *Server) limitCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.quotas == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		caller := callerFromContext(r)
+		decision := s.quotas.Allow(caller)
+		if !decision.Allowed {
+			respondRetryAfter(
+				w,
+				r,
+				http.StatusTooManyRequests,
+				decision.RetryAfter,
+				"rate limit exceeded for %q: %d requests per minute (%s)",
+				caller.Subject,
+				decision.Limits.RatePerMinute,
+				decision.Source,
+			)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkActiveQuota rejects the request with 429 when the caller already has
+// as many active transitions as their quota allows. Concurrent requests may
+// overshoot the quota by a few transitions; the rate limit bounds how many.
+// On failure it writes the problem response and returns false.
+func (s *Server) checkActiveQuota(w http.ResponseWriter, r *http.Request) bool {
+	if s.quotas == nil || s.quotaStore == nil {
+		return true
+	}
+
+	caller := callerFromContext(r)
+	limits, source := s.quotas.Policy().LimitsFor(caller)
+	if limits.MaxActive <= 0 {
+		return true
+	}
+
+	counts, err := s.quotaStore.CountActiveTransitionsByRequester(r.Context(), []string{caller.Subject})
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("failed to check active transition quota")
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to check active transition quota")
+		return false
+	}
+	if active := counts[caller.Subject]; active >= limits.MaxActive {
+		respondRetryAfter(
+			w,
+			r,
+			http.StatusTooManyRequests,
+			activeQuotaRetryAfter,
+			"too many active transitions for %q: %d of %d (%s)",
+			caller.Subject,
+			active,
+			limits.MaxActive,
+			source,
+		)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleGetQuotas(w http.ResponseWriter, r *http.Request) {
+	subject := strings.TrimSpace(r.URL.Query().Get("subject"))
+
+	var (
+		policy quota.Policy
+		usage  []quota.Usage
+	)
+	if s.quotas != nil {
+		policy = s.quotas.Policy()
+		usage = s.quotas.Usage()
+	}
+
+	active := map[string]int{}
+	if s.quotaStore != nil {
+		var requesters []string
+		if subject != "" {
+			requesters = []string{subject}
+		}
+		counts, err := s.quotaStore.CountActiveTransitionsByRequester(r.Context(), requesters)
+		if err != nil {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to count active transitions")
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to count active transitions")
+			return
+		}
+		active = counts
+	}
+
+	callers := make([]callerQuotaSpec, 0, len(usage)+len(active))
+	seen := make(map[string]struct{}, len(usage))
+	for _, item := range usage {
+		if subject != "" && item.Subject != subject {
+			continue
+		}
+		seen[item.Subject] = struct{}{}
+		spec := callerQuotaSpec{
+			Subject:           item.Subject,
+			Scopes:            item.Scopes,
+			Source:            item.Source,
+			Limits:            toQuotaLimitsSpec(item.Limits),
+			ActiveTransitions: active[item.Subject],
+			LastRequestAt:     toTimeRFC3339Ptr(&item.LastRequest),
+		}
+		if item.Limits.RatePerMinute > 0 {
+			remaining := int(math.Floor(item.Tokens))
+			spec.RemainingRequests = &remaining
+		}
+		callers = append(callers, spec)
+	}
+	// Callers with active transitions but no recent requests on this
+	// replica are reported without scopes, so scope overrides do not apply.
+	for requester, count := range active {
+		if _, ok := seen[requester]; ok {
+			continue
+		}
+		limits, source := policy.LimitsFor(quota.Caller{Subject: requester})
+		callers = append(callers, callerQuotaSpec{
+			Subject:           requester,
+			Scopes:            []string{},
+			Source:            source,
+			Limits:            toQuotaLimitsSpec(limits),
+			ActiveTransitions: count,
+		})
+	}
+	sort.Slice(callers, func(i, j int) bool { return callers[i].Subject < callers[j].Subject })
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[quotaUsageSpec]{
+		Kind:       "QuotaUsage",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: "power-quotas",
+		},
+		Spec: quotaUsageSpec{
+			Enabled: s.quotas != nil,
+			Policy:  toQuotaPolicySpec(policy),
+			Callers: callers,
+		},
+	})
+}
+
+func toQuotaLimitsSpec(limits quota.Limits) quotaLimitsSpec {
+	return quotaLimitsSpec{
+		RatePerMinute: limits.RatePerMinute,
+		Burst:         limits.Burst,
+		MaxActive:     limits.MaxActive,
+	}
+}
+
+func toQuotaPolicySpec(policy quota.Policy) quotaPolicySpec {
+	spec := quotaPolicySpec{
+		Default:   toQuotaLimitsSpec(policy.Default),
+		Overrides: make([]quotaOverrideSpec, 0, len(policy.Overrides)),
+		Groups:    map[string][]string{},
+	}
+	for _, override := range policy.Overrides {
+		spec.Overrides = append(spec.Overrides, quotaOverrideSpec{
+			Kind:   override.Kind,
+			Name:   override.Name,
+			Limits: toQuotaLimitsSpec(override.Limits),
+		})
+	}
+	for name, members := range policy.Groups {
+		spec.Groups[name] = members
+	}
+	return spec
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/auth"
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/quota"
+)
+
+type mockQuotaStore struct {
+	mockPowerStore
+	active   map[string]int
+	countErr error
+}
+
+func (m *mockQuotaStore) CountActiveTransitionsByRequester(ctx context.Context, requesters []string) (map[string]int, error) {
+	if m.countErr != nil {
+		return nil, m.countErr
+	}
+	counts := make(map[string]int)
+	for requester, count := range m.active {
+		if len(requesters) == 0 || requesters[0] == requester {
+			counts[requester] = count
+		}
+	}
+	return counts, nil
+}
+
+func newQuotaTestServer(t *testing.T, st *mockQuotaStore, policy quota.Policy) (*Server, *int) {
+	t.Helper()
+
+	started := 0
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			started++
+			return engine.Transition{ID: "tr-1", Operation: req.Operation, State: engine.TransitionStatePending}, nil
+		},
+	}
+	cfg := config.Config{DevMode: true, BulkMaxNodes: 20}
+	return New(st, cfg, "v1", "abc", "now", WithTransitionRunner(runner), WithQuotas(quota.NewLimiter(policy))), &started
+}
+
+func postTransition(srv *Server) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", strings.NewReader(`{"operation":"On","nodes":["node-1"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	return resp
+}
+
+func TestQuotas_RateLimit(t *testing.T) {
+	srv, started := newQuotaTestServer(t, &mockQuotaStore{}, quota.Policy{Default: quota.Limits{RatePerMinute: 2}})
+
+	require.Equal(t, http.StatusAccepted, postTransition(srv).Code)
+	require.Equal(t, http.StatusAccepted, postTransition(srv).Code)
+	resp := postTransition(srv)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	retryAfter := resp.Header().Get("Retry-After")
+	assert.Contains(t, []string{"29", "30"}, retryAfter, "two per minute refills one request every 30 seconds")
+	assert.Contains(t, resp.Body.String(), "rate limit exceeded")
+	assert.Equal(t, 2, *started)
+
+	del := httptest.NewRequest(http.MethodDelete, "/power/v1/transitions/tr-1", nil)
+	delResp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(delResp, del)
+	assert.Equal(t, http.StatusTooManyRequests, delResp.Code, "aborting transitions counts against the rate limit")
+}
+
+func TestQuotas_ActiveTransitions(t *testing.T) {
+	st := &mockQuotaStore{active: map[string]int{}}
+	srv, started := newQuotaTestServer(t, st, quota.Policy{Default: quota.Limits{MaxActive: 2}})
+
+	require.Equal(t, http.StatusAccepted, postTransition(srv).Code)
+	usage := srv.quotas.Usage()
+	require.Len(t, usage, 1)
+	st.active[usage[0].Subject] = 2
+
+	resp := postTransition(srv)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), "too many active transitions")
+	assert.Equal(t, 1, *started)
+}
+
+func TestQuotas_ActiveCountFailureHidesStoreError(t *testing.T) {
+	st := &mockQuotaStore{countErr: errors.New("pq: connection refused to 10.0.0.5")}
+	srv, started := newQuotaTestServer(t, st, quota.Policy{Default: quota.Limits{MaxActive: 2}})
+
+	resp := postTransition(srv)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "failed to check active transition quota")
+	assert.NotContains(t, resp.Body.String(), "10.0.0.5")
+	assert.Zero(t, *started)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/quotas", nil)
+	quotasResp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(quotasResp, req)
+	assert.Equal(t, http.StatusInternalServerError, quotasResp.Code)
+	assert.NotContains(t, quotasResp.Body.String(), "10.0.0.5")
+}
+
+func TestLimitCaller_UsesScopeOverrides(t *testing.T) {
+	policy, err := quota.ParsePolicy(quota.Limits{RatePerMinute: 1}, "scope:admin=0/0/0", "")
+	require.NoError(t, err)
+	srv := &Server{quotas: quota.NewLimiter(policy)}
+	handler := srv.limitCaller(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(claims *auth.Claims) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req = req.WithContext(auth.ContextWithClaims(req.Context(), claims))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	operator := &auth.Claims{Subject: "alice", Scopes: []string{"write:power"}}
+	assert.Equal(t, http.StatusNoContent, serve(operator).Code)
+	limited := serve(operator)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusNoContent, serve(&auth.Claims{Subject: "bob", Scopes: []string{"write:power"}}).Code)
+	for range 3 {
+		assert.Equal(t, http.StatusNoContent, serve(&auth.Claims{Subject: "root", Scopes: []string{"admin"}}).Code)
+	}
+}
+
+func TestGetQuotas(t *testing.T) {
+	policy, err := quota.ParsePolicy(
+		quota.Limits{RatePerMinute: 10, MaxActive: 4},
+		"group:ci=30/10/8",
+		"ci=svc-jenkins",
+	)
+	require.NoError(t, err)
+	st := &mockQuotaStore{active: map[string]int{"svc-jenkins": 3}}
+	srv, _ := newQuotaTestServer(t, st, policy)
+	require.Equal(t, http.StatusAccepted, postTransition(srv).Code)
+
+	get := func(query string) quotaUsageSpec {
+		req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/quotas"+query, nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var out httputil.Resource[quotaUsageSpec]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, "QuotaUsage", out.Kind)
+		return out.Spec
+	}
+
+	spec := get("")
+	assert.True(t, spec.Enabled)
+	assert.Equal(t, quotaLimitsSpec{RatePerMinute: 10, MaxActive: 4}, spec.Policy.Default)
+	assert.Equal(t, []quotaOverrideSpec{{Kind: "group", Name: "ci", Limits: quotaLimitsSpec{RatePerMinute: 30, Burst: 10, MaxActive: 8}}}, spec.Policy.Overrides)
+	assert.Equal(t, map[string][]string{"ci": {"svc-jenkins"}}, spec.Policy.Groups)
+	require.Len(t, spec.Callers, 2)
+
+	var requester, jenkins callerQuotaSpec
+	for _, caller := range spec.Callers {
+		if caller.Subject == "svc-jenkins" {
+			jenkins = caller
+		} else {
+			requester = caller
+		}
+	}
+	assert.Equal(t, "default", requester.Source)
+	require.NotNil(t, requester.RemainingRequests)
+	assert.Equal(t, 9, *requester.RemainingRequests)
+	assert.NotNil(t, requester.LastRequestAt)
+	assert.Equal(t, "group:ci", jenkins.Source)
+	assert.Equal(t, 3, jenkins.ActiveTransitions)
+	assert.Nil(t, jenkins.LastRequestAt)
+
+	filtered := get("?subject=svc-jenkins")
+	require.Len(t, filtered.Callers, 1)
+	assert.Equal(t, "svc-jenkins", filtered.Callers[0].Subject)
+}
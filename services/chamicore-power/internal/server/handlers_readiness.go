@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+)
+
+type queueStatusReporter interface {
+	QueueStatus() engine.QueueStatus
+}
+
+type readinessSpec struct {
+	Status string           `json:"status"`
+	Queue  *queueStatusSpec `json:"queue,omitempty"`
+}
+
+type queueStatusSpec struct {
+	Depth          int            `json:"depth"`
+	Limit          int            `json:"limit"`
+	Full           bool           `json:"full"`
+	ByPriority     map[string]int `json:"byPriority"`
+	AvgTaskSeconds float64        `json:"avgTaskSeconds"`
+}
+
+// handleReadiness reports whether the service can serve requests, with the
+// depth of the transition queue. A full queue does not make the service
+// unready: new transitions are rejected with 503 and Retry-After while reads
+// and aborts keep working.
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.Ping(r.Context()); err != nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if s.mappingSync != nil && !s.mappingSync.IsReady() {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errInitialMappingSyncPending.Error())
+		return
+	}
+
+	spec := readinessSpec{Status: "ready"}
+	if reporter, ok := s.transitionRunner.(queueStatusReporter); ok {
+		status := reporter.QueueStatus()
+		spec.Queue = &queueStatusSpec{
+			Depth:          status.Depth,
+			Limit:          status.Limit,
+			Full:           status.Depth >= status.Limit,
+			ByPriority:     status.ByPriority,
+			AvgTaskSeconds: status.AvgTaskDuration.Seconds(),
+		}
+	}
+	httputil.RespondJSON(w, http.StatusOK, spec)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"git.cscs.ch/openchami/chamicore-lib/httputil"
 	"git.cscs.ch/openchami/chamicore-lib/redfish"
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
 )
 
 type powerStatusResponse struct {
@@ -39,11 +41,16 @@ func (s *Server) handleGetPowerStatus(w http.ResponseWriter, r *http.Request) {
 
 	nodes := parseQueryTargets(r, "nodes", "node")
 	groups := parseQueryTargets(r, "groups", "group")
-	targetNodes, err := s.resolveTargets(r.Context(), nodes, groups)
+	targets, err := s.resolveTargetQuery(r.Context(), targetQuery{
+		Nodes:    nodes,
+		Groups:   groups,
+		Selector: parseQuerySelector(r),
+	})
 	if err != nil {
 		s.respondTargetResolutionError(w, r, err)
 		return
 	}
+	targetNodes := targets.Nodes
 	if len(targetNodes) > s.cfg.BulkMaxNodes {
 		httputil.RespondProblemf(
 			w,
@@ -56,16 +63,36 @@ func (s *Server) handleGetPowerStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	latestTasks, err := s.transitionStore.ListLatestTransitionTasksByNode(r.Context(), targetNodes)
+	statuses, err := s.nodePowerStatuses(r.Context(), targetNodes)
 	if err != nil {
 		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to resolve power status")
 		return
 	}
 
-	_, missingMappings, err := s.store.ResolveNodeMappings(r.Context(), targetNodes)
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[powerStatusResponse]{
+		Kind:       "PowerStatus",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: "power-status",
+		},
+		Spec: powerStatusResponse{
+			NodeStatuses: statuses,
+			Total:        len(statuses),
+		},
+	})
+}
+
+// nodePowerStatuses reports the last known power status of each node from
+// its latest transition task and its topology mapping.
+func (s *Server) nodePowerStatuses(ctx context.Context, targetNodes []string) ([]powerNodeStatus, error) {
+	latestTasks, err := s.transitionStore.ListLatestTransitionTasksByNode(ctx, targetNodes)
 	if err != nil {
-		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to resolve topology mapping")
-		return
+		return nil, fmt.Errorf("listing latest tasks: %w", err)
+	}
+
+	_, missingMappings, err := s.store.ResolveNodeMappings(ctx, targetNodes)
+	if err != nil {
+		return nil, fmt.Errorf("resolving topology mapping: %w", err)
 	}
 
 	missingByNode := make(map[string]string, len(missingMappings))
@@ -123,18 +150,7 @@ func (s *Server) handleGetPowerStatus(w http.ResponseWriter, r *http.Request) {
 
 		statuses = append(statuses, status)
 	}
-
-	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[powerStatusResponse]{
-		Kind:       "PowerStatus",
-		APIVersion: "power/v1",
-		Metadata: httputil.Metadata{
-			ID: "power-status",
-		},
-		Spec: powerStatusResponse{
-			NodeStatuses: statuses,
-			Total:        len(statuses),
-		},
-	})
+	return statuses, nil
 }
 
 func parseQueryTargets(r *http.Request, keys ...string) []string {
@@ -145,6 +161,18 @@ func parseQueryTargets(r *http.Request, keys ...string) []string {
 	return parseTargetList(values)
 }
 
+// parseQuerySelector reads SMD attribute selector query parameters.
+func parseQuerySelector(r *http.Request) model.NodeSelector {
+	query := r.URL.Query()
+	return model.NodeSelector{
+		Role:    strings.TrimSpace(query.Get("role")),
+		SubRole: strings.TrimSpace(query.Get("subRole")),
+		Arch:    strings.TrimSpace(query.Get("arch")),
+		State:   strings.TrimSpace(query.Get("state")),
+		Cabinet: strings.TrimSpace(query.Get("cabinet")),
+	}
+}
+
 func inferredPowerState(operation string) string {
 	parsed, err := redfish.ParseResetOperation(operation)
 	if err != nil {
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+const redfishSystemsPrefix = "/redfish/v1/Systems/"
+
+var errSystemPathSubsystemUnavailable = errors.New("system path mapping subsystem is not configured")
+
+type systemPathRequest struct {
+	SystemPath string `json:"systemPath"`
+}
+
+type systemPathSpec struct {
+	NodeID     string      `json:"nodeID"`
+	SystemPath string      `json:"systemPath"`
+	Source     string      `json:"source"`
+	CreatedAt  timeRFC3339 `json:"createdAt"`
+	UpdatedAt  timeRFC3339 `json:"updatedAt"`
+}
+
+func (s *Server) handleListSystemPaths(w http.ResponseWriter, r *http.Request) {
+	if s.systemPathStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errSystemPathSubsystemUnavailable.Error())
+		return
+	}
+
+	limit, offset, err := parseListPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, total, err := s.systemPathStore.ListNodeSystemPaths(r.Context(), limit, offset)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list system paths")
+		return
+	}
+
+	resources := make([]httputil.Resource[systemPathSpec], 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toSystemPathResource(item))
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[systemPathSpec]{
+		Kind:       "NodeSystemPathList",
+		APIVersion: "power/v1",
+		Metadata: httputil.ListMetadata{
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		},
+		Items: resources,
+	})
+}
+
+func (s *Server) handleGetSystemPath(w http.ResponseWriter, r *http.Request) {
+	if s.systemPathStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errSystemPathSubsystemUnavailable.Error())
+		return
+	}
+
+	nodeID := strings.TrimSpace(chi.URLParam(r, "nodeID"))
+	item, err := s.systemPathStore.GetNodeSystemPath(r.Context(), nodeID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "no system path mapping for node %q", nodeID)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load system path")
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, toSystemPathResource(item))
+}
+
+func (s *Server) handlePutSystemPath(w http.ResponseWriter, r *http.Request) {
+	if s.systemPathStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errSystemPathSubsystemUnavailable.Error())
+		return
+	}
+
+	nodeID := strings.TrimSpace(chi.URLParam(r, "nodeID"))
+	if nodeID == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "node id is required")
+		return
+	}
+
+	var req systemPathRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	systemPath := strings.TrimSuffix(strings.TrimSpace(req.SystemPath), "/")
+	if !strings.HasPrefix(systemPath, redfishSystemsPrefix) || len(systemPath) == len(redfishSystemsPrefix) {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "systemPath must be a Redfish system path under %s", redfishSystemsPrefix)
+		return
+	}
+
+	item, err := s.systemPathStore.SaveNodeSystemPath(r.Context(), model.NodeSystemPath{
+		NodeID:     nodeID,
+		SystemPath: systemPath,
+		Source:     model.SystemPathSourceAdmin,
+	})
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to save system path")
+		return
+	}
+	if s.systemPathCache != nil {
+		s.systemPathCache.InvalidateNode(nodeID)
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, toSystemPathResource(item))
+}
+
+func (s *Server) handleDeleteSystemPath(w http.ResponseWriter, r *http.Request) {
+	if s.systemPathStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errSystemPathSubsystemUnavailable.Error())
+		return
+	}
+
+	nodeID := strings.TrimSpace(chi.URLParam(r, "nodeID"))
+	if err := s.systemPathStore.DeleteNodeSystemPath(r.Context(), nodeID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "no system path mapping for node %q", nodeID)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to delete system path")
+		return
+	}
+	if s.systemPathCache != nil {
+		s.systemPathCache.InvalidateNode(nodeID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toSystemPathResource(item model.NodeSystemPath) httputil.Resource[systemPathSpec] {
+	return httputil.Resource[systemPathSpec]{
+		Kind:       "NodeSystemPath",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: item.NodeID,
+		},
+		Spec: systemPathSpec{
+			NodeID:     item.NodeID,
+			SystemPath: item.SystemPath,
+			Source:     item.Source,
+			CreatedAt:  newTimeRFC3339(item.CreatedAt),
+			UpdatedAt:  newTimeRFC3339(item.UpdatedAt),
+		},
+	}
+}
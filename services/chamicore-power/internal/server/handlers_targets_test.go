@@ -0,0 +1,231 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+func TestCreateTransition_ExpandsRangesAndSelectorInDryRun(t *testing.T) {
+	var started engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			started = req
+			return engine.Transition{ID: "transition-1", Operation: req.Operation, DryRun: req.DryRun, QueuedAt: time.Now().UTC()}, nil
+		},
+	}
+	st := &mockPowerStore{
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			tasks := make([]engine.Task, 0, len(started.NodeIDs))
+			for _, node := range started.NodeIDs {
+				tasks = append(tasks, engine.Task{NodeID: node, Operation: "On", State: engine.TaskStatePlanned, DryRun: true})
+			}
+			return tasks, nil
+		},
+	}
+	var gotSelector model.NodeSelector
+	srv := New(st, config.Config{DevMode: true, BulkMaxNodes: 20}, "v1", "abc", "now",
+		WithTransitionRunner(runner),
+		WithNodeSelector(func(ctx context.Context, selector model.NodeSelector) ([]string, error) {
+			gotSelector = selector
+			return []string{"x1001c0s0b0n0", "x1000c0s0b0n0"}, nil
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(`{
+		"operation": "On",
+		"nodes": ["x1000c[0-1]s0b0n[0-1]"],
+		"selector": {"role": "Compute", "arch": " X86 ", "cabinet": "x1001"},
+		"dryRun": true
+	}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	want := []string{"x1000c0s0b0n0", "x1000c0s0b0n1", "x1000c1s0b0n0", "x1000c1s0b0n1", "x1001c0s0b0n0"}
+	assert.Equal(t, want, started.NodeIDs)
+	assert.Equal(t, model.NodeSelector{Role: "Compute", Arch: "X86", Cabinet: "x1001"}, gotSelector)
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, want, out.Spec.ResolvedNodes)
+}
+
+func TestCreateTransition_AppliesGroupOperationAndExclusions(t *testing.T) {
+	groups := map[string][]string{
+		"rack1":   {"nid001", "nid002", "nid003", "nid004"},
+		"compute": {"nid002", "nid003", "nid004", "nid005"},
+		"drained": {"nid004"},
+	}
+	resolver := func(ctx context.Context, group string) ([]string, error) {
+		members, ok := groups[group]
+		if !ok {
+			return nil, ErrGroupNotFound
+		}
+		return members, nil
+	}
+
+	var started engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			started = req
+			return engine.Transition{
+				ID:         "transition-1",
+				Operation:  req.Operation,
+				Exclusions: req.Exclusions,
+				QueuedAt:   time.Now().UTC(),
+			}, nil
+		},
+	}
+	// The intersection has three nodes, above the limit of two; exclusions
+	// are applied before the limit is checked.
+	srv := New(&mockPowerStore{}, config.Config{DevMode: true, BulkMaxNodes: 2, BulkHardMaxNodes: 2}, "v1", "abc", "now",
+		WithTransitionRunner(runner),
+		WithGroupMemberResolver(resolver),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(`{
+		"operation": "ForceOff",
+		"groups": ["rack1", "compute"],
+		"groupOperation": "Intersection",
+		"excludeNodes": ["nid00[2,9]"],
+		"excludeGroups": ["drained"]
+	}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	assert.Equal(t, []string{"nid003"}, started.NodeIDs)
+	wantExclusions := &engine.Exclusions{
+		Nodes:    []string{"nid002", "nid009"},
+		Groups:   []string{"drained"},
+		Excluded: []string{"nid002", "nid004"},
+	}
+	assert.Equal(t, wantExclusions, started.Exclusions)
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.NotNil(t, out.Spec.Exclusions)
+	assert.Equal(t, wantExclusions.Excluded, out.Spec.Exclusions.Excluded)
+	assert.Equal(t, wantExclusions.Groups, out.Spec.Exclusions.Groups)
+}
+
+func TestCreateTransition_RejectsInvalidTargets(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, nil)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "bad range", body: `{"operation":"On","nodes":["nid[3-1]"]}`, status: http.StatusBadRequest},
+		{name: "unbalanced range", body: `{"operation":"On","nodes":["nid[1-3"]}`, status: http.StatusBadRequest},
+		{name: "selector unavailable", body: `{"operation":"On","selector":{"role":"Compute"}}`, status: http.StatusServiceUnavailable},
+		{name: "unknown group operation", body: `{"operation":"On","nodes":["nid001"],"groupOperation":"xor"}`, status: http.StatusBadRequest},
+		{name: "bad exclude range", body: `{"operation":"On","nodes":["nid001"],"excludeNodes":["nid[2-"]}`, status: http.StatusBadRequest},
+		{name: "everything excluded", body: `{"operation":"On","nodes":["nid00[1-2]"],"excludeNodes":["nid001","nid002"]}`, status: http.StatusBadRequest},
+		{name: "exclude groups unavailable", body: `{"operation":"On","nodes":["nid001"],"excludeGroups":["drained"]}`, status: http.StatusServiceUnavailable},
+		{name: "entries share the node limit", body: `{"operation":"On","nodes":["a[01-15]","b[01-15]"]}`, status: http.StatusBadRequest},
+		{name: "huge range", body: `{"operation":"On","nodes":["n[0-65534,0-65534]"]}`, status: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+			assert.Equal(t, tc.status, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+func TestRunTemplate_ResolvesSelectorAndExclusions(t *testing.T) {
+	st := &mockPowerStore{
+		getTemplateFn: func(ctx context.Context, name string, version int) (model.TransitionTemplate, error) {
+			return model.TransitionTemplate{
+				Name:    "reboot-workers",
+				Version: 1,
+				Spec: model.TemplateSpec{
+					Operation:     "GracefulRestart",
+					Selector:      model.NodeSelector{Role: "Compute", SubRole: "Worker"},
+					ExcludeGroups: []string{"drained"},
+				},
+			}, nil
+		},
+	}
+	var started engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			started = req
+			return engine.Transition{ID: "tr-1", Operation: req.Operation, State: engine.TransitionStatePending}, nil
+		},
+	}
+	var gotSelector model.NodeSelector
+	srv := New(st, config.Config{DevMode: true, BulkMaxNodes: 20}, "v1", "abc", "now",
+		WithTransitionRunner(runner),
+		WithGroupMemberResolver(func(ctx context.Context, group string) ([]string, error) {
+			return []string{"nid002"}, nil
+		}),
+		WithNodeSelector(func(ctx context.Context, selector model.NodeSelector) ([]string, error) {
+			gotSelector = selector
+			return []string{"nid001", "nid002", "nid003"}, nil
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/templates/reboot-workers/run", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, model.NodeSelector{Role: "Compute", SubRole: "Worker"}, gotSelector)
+	assert.Equal(t, []string{"nid001", "nid003"}, started.NodeIDs)
+	require.NotNil(t, started.Exclusions)
+	assert.Equal(t, []string{"nid002"}, started.Exclusions.Excluded)
+}
+
+func TestPowerStatus_AcceptsRangesAndSelector(t *testing.T) {
+	var queried []string
+	st := &mockPowerStore{
+		listLatestTasksByNode: func(ctx context.Context, nodeIDs []string) ([]engine.Task, error) {
+			queried = nodeIDs
+			return nil, nil
+		},
+	}
+	var gotSelector model.NodeSelector
+	srv := New(st, config.Config{DevMode: true, BulkMaxNodes: 20}, "v1", "abc", "now",
+		WithNodeSelector(func(ctx context.Context, selector model.NodeSelector) ([]string, error) {
+			gotSelector = selector
+			return []string{"nid005"}, nil
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/power-status?nodes=nid[001,003],nid004&subRole=Worker&state=Ready", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, []string{"nid001", "nid003", "nid004", "nid005"}, queried)
+	assert.Equal(t, model.NodeSelector{SubRole: "Worker", State: "Ready"}, gotSelector)
+}
+
+func TestParseTargetList_KeepsBracketCommas(t *testing.T) {
+	assert.Equal(t,
+		[]string{"nid[001,003-004]", "login01", "x1000c[0,2]s0b0n0"},
+		parseTargetList([]string{"nid[001,003-004], login01", "x1000c[0,2]s0b0n0,"}),
+	)
+}
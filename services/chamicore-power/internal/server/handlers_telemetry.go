@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+const (
+	defaultTelemetryWindow     = time.Hour
+	defaultTelemetryResolution = time.Minute
+	// maxTelemetryPoints bounds the number of points returned per series.
+	maxTelemetryPoints = 1440
+)
+
+var errTelemetryUnavailable = errors.New("telemetry sampling is not enabled")
+
+type telemetryStore interface {
+	ListTelemetry(ctx context.Context, query model.TelemetryQuery) ([]model.TelemetryPoint, error)
+}
+
+type telemetryResponse struct {
+	Since       timeRFC3339           `json:"since"`
+	Until       timeRFC3339           `json:"until"`
+	StepSeconds int64                 `json:"stepSeconds"`
+	Nodes       []telemetryNodeSeries `json:"nodes"`
+	Groups      []telemetryGroup      `json:"groups,omitempty"`
+}
+
+type telemetryNodeSeries struct {
+	NodeID string               `json:"nodeID"`
+	Points []telemetryNodePoint `json:"points"`
+}
+
+type telemetryNodePoint struct {
+	Timestamp           timeRFC3339 `json:"timestamp"`
+	Samples             int         `json:"samples"`
+	PowerWatts          *float64    `json:"powerWatts,omitempty"`
+	MinPowerWatts       *float64    `json:"minPowerWatts,omitempty"`
+	MaxPowerWatts       *float64    `json:"maxPowerWatts,omitempty"`
+	InletTempCelsius    *float64    `json:"inletTempCelsius,omitempty"`
+	MaxInletTempCelsius *float64    `json:"maxInletTempCelsius,omitempty"`
+}
+
+type telemetryGroup struct {
+	Group     string                `json:"group"`
+	NodeCount int                   `json:"nodeCount"`
+	Points    []telemetryGroupPoint `json:"points"`
+}
+
+// telemetryGroupPoint aggregates the members of a group that reported in a
+// step: power is the sum of member averages, temperature their mean.
+type telemetryGroupPoint struct {
+	Timestamp           timeRFC3339 `json:"timestamp"`
+	ReportingNodes      int         `json:"reportingNodes"`
+	PowerWatts          *float64    `json:"powerWatts,omitempty"`
+	InletTempCelsius    *float64    `json:"inletTempCelsius,omitempty"`
+	MaxInletTempCelsius *float64    `json:"maxInletTempCelsius,omitempty"`
+}
+
+func (s *Server) handleGetTelemetry(w http.ResponseWriter, r *http.Request) {
+	if s.telemetryStore == nil || !s.cfg.TelemetryEnabled {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTelemetryUnavailable.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	until := now
+	if raw := strings.TrimSpace(r.URL.Query().Get("until")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusBadRequest, "until must be an RFC3339 timestamp")
+			return
+		}
+		until = parsed.UTC()
+	}
+	since := until.Add(-defaultTelemetryWindow)
+	if raw := strings.TrimSpace(r.URL.Query().Get("since")); raw != "" {
+		parsed, err := parseTelemetrySince(raw, now)
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusBadRequest, "since must be an RFC3339 timestamp or a duration such as 2h")
+			return
+		}
+		since = parsed
+	}
+	if !since.Before(until) {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "since must be before until")
+		return
+	}
+
+	resolution := s.cfg.TelemetryResolution
+	if resolution <= 0 {
+		resolution = defaultTelemetryResolution
+	}
+	step := resolution
+	if raw := strings.TrimSpace(r.URL.Query().Get("step")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			httputil.RespondProblem(w, r, http.StatusBadRequest, "step must be a positive duration such as 5m")
+			return
+		}
+		if parsed < resolution {
+			httputil.RespondProblemf(w, r, http.StatusBadRequest, "step must be at least the sampling resolution %s", resolution)
+			return
+		}
+		// Steps are whole multiples of the resolution so stored buckets never
+		// straddle two points.
+		step = (parsed + resolution - 1) / resolution * resolution
+	}
+	if points := int64(until.Sub(since) / step); points > maxTelemetryPoints {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"too many points: got %d, max %d; widen step or narrow the time range",
+			points,
+			maxTelemetryPoints,
+		)
+		return
+	}
+
+	nodes := parseQueryTargets(r, "nodes", "node")
+	groups := parseQueryTargets(r, "groups", "group")
+	targets, err := s.resolveTargetQuery(r.Context(), targetQuery{Nodes: nodes, Groups: groups})
+	if err != nil {
+		s.respondTargetResolutionError(w, r, err)
+		return
+	}
+	targetNodes, groupMembers := targets.Nodes, targets.GroupMembers
+
+	points, err := s.telemetryStore.ListTelemetry(r.Context(), model.TelemetryQuery{
+		NodeIDs: targetNodes,
+		Since:   since,
+		Until:   until,
+		Step:    step,
+	})
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list telemetry")
+		return
+	}
+
+	byNode := make(map[string][]model.TelemetryPoint, len(targetNodes))
+	for _, point := range points {
+		byNode[point.NodeID] = append(byNode[point.NodeID], point)
+	}
+
+	series := make([]telemetryNodeSeries, 0, len(targetNodes))
+	for _, nodeID := range targetNodes {
+		nodePoints := byNode[nodeID]
+		item := telemetryNodeSeries{NodeID: nodeID, Points: make([]telemetryNodePoint, 0, len(nodePoints))}
+		for _, point := range nodePoints {
+			item.Points = append(item.Points, telemetryNodePoint{
+				Timestamp:           newTimeRFC3339(point.Timestamp),
+				Samples:             point.Samples,
+				PowerWatts:          point.PowerWatts,
+				MinPowerWatts:       point.MinPowerWatts,
+				MaxPowerWatts:       point.MaxPowerWatts,
+				InletTempCelsius:    point.InletTempCelsius,
+				MaxInletTempCelsius: point.MaxInletTemp,
+			})
+		}
+		series = append(series, item)
+	}
+
+	groupItems := make([]telemetryGroup, 0, len(groupMembers))
+	for _, group := range parseTargetList(groups) {
+		members := groupMembers[group]
+		groupItems = append(groupItems, telemetryGroup{
+			Group:     group,
+			NodeCount: len(members),
+			Points:    aggregateTelemetry(members, byNode),
+		})
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[telemetryResponse]{
+		Kind:       "Telemetry",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID: "telemetry",
+		},
+		Spec: telemetryResponse{
+			Since:       newTimeRFC3339(since),
+			Until:       newTimeRFC3339(until),
+			StepSeconds: int64(step / time.Second),
+			Nodes:       series,
+			Groups:      groupItems,
+		},
+	})
+}
+
+// parseTelemetrySince accepts an absolute RFC3339 timestamp or a duration
+// relative to now.
+func parseTelemetrySince(raw string, now time.Time) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed.UTC(), nil
+	}
+	ago, err := time.ParseDuration(raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ago <= 0 {
+		return time.Time{}, errors.New("duration must be positive")
+	}
+	return now.Add(-ago), nil
+}
+
+func aggregateTelemetry(members []string, byNode map[string][]model.TelemetryPoint) []telemetryGroupPoint {
+	type accumulator struct {
+		timestamp time.Time
+		reporting int
+		power     *float64
+		tempSum   float64
+		tempCount int
+		tempMax   *float64
+	}
+
+	byStep := make(map[time.Time]*accumulator)
+	steps := make([]time.Time, 0)
+	for _, member := range members {
+		for _, point := range byNode[member] {
+			acc, ok := byStep[point.Timestamp]
+			if !ok {
+				acc = &accumulator{timestamp: point.Timestamp}
+				byStep[point.Timestamp] = acc
+				steps = append(steps, point.Timestamp)
+			}
+			acc.reporting++
+			if point.PowerWatts != nil {
+				sum := *point.PowerWatts
+				if acc.power != nil {
+					sum += *acc.power
+				}
+				acc.power = &sum
+			}
+			if point.InletTempCelsius != nil {
+				acc.tempSum += *point.InletTempCelsius
+				acc.tempCount++
+			}
+			if point.MaxInletTemp != nil && (acc.tempMax == nil || *point.MaxInletTemp > *acc.tempMax) {
+				maxTemp := *point.MaxInletTemp
+				acc.tempMax = &maxTemp
+			}
+		}
+	}
+
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Before(steps[j]) })
+	out := make([]telemetryGroupPoint, 0, len(steps))
+	for _, step := range steps {
+		acc := byStep[step]
+		point := telemetryGroupPoint{
+			Timestamp:           newTimeRFC3339(acc.timestamp),
+			ReportingNodes:      acc.reporting,
+			PowerWatts:          acc.power,
+			MaxInletTempCelsius: acc.tempMax,
+		}
+		if acc.tempCount > 0 {
+			mean := acc.tempSum / float64(acc.tempCount)
+			point.InletTempCelsius = &mean
+		}
+		out = append(out, point)
+	}
+	return out
+}
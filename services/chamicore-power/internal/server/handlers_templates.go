@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-lib/redfish"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+var (
+	errTemplateStoreUnavailable = errors.New("template store is not configured")
+
+	templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,62}$`)
+)
+
+type templateStore interface {
+	ListTransitionTemplates(ctx context.Context) ([]model.TransitionTemplate, error)
+	ListTransitionTemplateVersions(ctx context.Context, name string) ([]model.TransitionTemplate, error)
+	GetTransitionTemplate(ctx context.Context, name string, version int) (model.TransitionTemplate, error)
+	CreateTransitionTemplate(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error)
+	UpdateTransitionTemplate(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error)
+	DeleteTransitionTemplate(ctx context.Context, name string) error
+}
+
+type templateRequest struct {
+	Name           string                `json:"name,omitempty"`
+	Description    string                `json:"description,omitempty"`
+	Operation      string                `json:"operation"`
+	Nodes          []string              `json:"nodes,omitempty"`
+	Groups         []string              `json:"groups,omitempty"`
+	Selector       *selectorRequest      `json:"selector,omitempty"`
+	GroupOperation string                `json:"groupOperation,omitempty"`
+	ExcludeNodes   []string              `json:"excludeNodes,omitempty"`
+	ExcludeGroups  []string              `json:"excludeGroups,omitempty"`
+	DryRun         bool                  `json:"dryRun,omitempty"`
+	BootWait       *bootWaitRequest      `json:"bootWait,omitempty"`
+	BatchSize      int                   `json:"batchSize,omitempty"`
+	Escalation     *escalationRequest    `json:"escalation,omitempty"`
+	Guard          *templateGuardRequest `json:"guard,omitempty"`
+}
+
+type templateGuardRequest struct {
+	MaxNodes            int  `json:"maxNodes,omitempty"`
+	RequireConfirmation bool `json:"requireConfirmation,omitempty"`
+}
+
+// templateRunRequest overrides template settings for one run. Nodes, groups
+// or a selector, when given, replace the template targets and group
+// operation; exclusions, when given, replace the template exclusions.
+type templateRunRequest struct {
+	RequestID      string             `json:"requestID,omitempty"`
+	Version        int                `json:"version,omitempty"`
+	Nodes          []string           `json:"nodes,omitempty"`
+	Groups         []string           `json:"groups,omitempty"`
+	Selector       *selectorRequest   `json:"selector,omitempty"`
+	GroupOperation string             `json:"groupOperation,omitempty"`
+	ExcludeNodes   []string           `json:"excludeNodes,omitempty"`
+	ExcludeGroups  []string           `json:"excludeGroups,omitempty"`
+	DryRun         *bool              `json:"dryRun,omitempty"`
+	BootWait       *bootWaitRequest   `json:"bootWait,omitempty"`
+	BatchSize      *int               `json:"batchSize,omitempty"`
+	Escalation     *escalationRequest `json:"escalation,omitempty"`
+	Confirm        bool               `json:"confirm,omitempty"`
+}
+
+type templateSpec struct {
+	Name           string                `json:"name"`
+	Version        int                   `json:"version"`
+	Description    string                `json:"description,omitempty"`
+	Operation      string                `json:"operation"`
+	Nodes          []string              `json:"nodes,omitempty"`
+	Groups         []string              `json:"groups,omitempty"`
+	Selector       *selectorRequest      `json:"selector,omitempty"`
+	GroupOperation string                `json:"groupOperation,omitempty"`
+	ExcludeNodes   []string              `json:"excludeNodes,omitempty"`
+	ExcludeGroups  []string              `json:"excludeGroups,omitempty"`
+	DryRun         bool                  `json:"dryRun,omitempty"`
+	BootWait       *bootWaitRequest      `json:"bootWait,omitempty"`
+	BatchSize      int                   `json:"batchSize,omitempty"`
+	Escalation     *escalationRequest    `json:"escalation,omitempty"`
+	Guard          *templateGuardRequest `json:"guard,omitempty"`
+	CreatedBy      string                `json:"createdBy,omitempty"`
+}
+
+func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	items, err := s.templateStore.ListTransitionTemplates(r.Context())
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list templates")
+		return
+	}
+	respondTemplateList(w, items)
+}
+
+func (s *Server) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	version, err := parseTemplateVersion(r.URL.Query().Get("version"))
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := s.templateStore.GetTransitionTemplate(r.Context(), name, version)
+	if err != nil {
+		respondTemplateLoadError(w, r, name, err)
+		return
+	}
+	httputil.RespondJSON(w, http.StatusOK, toTemplateResource(item))
+}
+
+func (s *Server) handleListTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	items, err := s.templateStore.ListTransitionTemplateVersions(r.Context(), name)
+	if err != nil {
+		respondTemplateLoadError(w, r, name, err)
+		return
+	}
+	respondTemplateList(w, items)
+}
+
+func (s *Server) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	var req templateRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	item, err := templateFromRequest(strings.TrimSpace(req.Name), req)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	item.CreatedBy = requestedByFromContext(r)
+
+	created, err := s.templateStore.CreateTransitionTemplate(r.Context(), item)
+	if err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			httputil.RespondProblemf(w, r, http.StatusConflict, "template %q already exists", item.Name)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to create template")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/power/v1/templates/%s", created.Name))
+	httputil.RespondJSON(w, http.StatusCreated, toTemplateResource(created))
+}
+
+func (s *Server) handlePutTemplate(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	var req templateRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if bodyName := strings.TrimSpace(req.Name); bodyName != "" && bodyName != name {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "template name %q does not match path %q", bodyName, name)
+		return
+	}
+	item, err := templateFromRequest(name, req)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	item.CreatedBy = requestedByFromContext(r)
+
+	updated, err := s.templateStore.UpdateTransitionTemplate(r.Context(), item)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "template %q not found", name)
+		case errors.Is(err, store.ErrConflict):
+			httputil.RespondProblemf(w, r, http.StatusConflict, "template %q was updated concurrently", name)
+		default:
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to update template")
+		}
+		return
+	}
+	httputil.RespondJSON(w, http.StatusOK, toTemplateResource(updated))
+}
+
+func (s *Server) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	if err := s.templateStore.DeleteTransitionTemplate(r.Context(), name); err != nil {
+		respondTemplateLoadError(w, r, name, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRunTemplate(w http.ResponseWriter, r *http.Request) {
+	if s.templateStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTemplateStoreUnavailable.Error())
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	var req templateRunRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	if req.Version < 0 {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	item, err := s.templateStore.GetTransitionTemplate(r.Context(), name, req.Version)
+	if err != nil {
+		respondTemplateLoadError(w, r, name, err)
+		return
+	}
+
+	spec := item.Spec
+	guard := model.TemplateGuard{}
+	if spec.Guard != nil {
+		guard = *spec.Guard
+	}
+	if guard.RequireConfirmation && !req.Confirm {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "template %q requires confirm: true", item.Name)
+		return
+	}
+
+	transitionReq := transitionRequest{
+		RequestID:       strings.TrimSpace(req.RequestID),
+		Operation:       spec.Operation,
+		Nodes:           spec.Nodes,
+		Groups:          spec.Groups,
+		Selector:        spec.Selector,
+		GroupOperation:  spec.GroupOperation,
+		ExcludeNodes:    spec.ExcludeNodes,
+		ExcludeGroups:   spec.ExcludeGroups,
+		DryRun:          spec.DryRun,
+		BatchSize:       spec.BatchSize,
+		MaxNodes:        guard.MaxNodes,
+		TemplateName:    item.Name,
+		TemplateVersion: item.Version,
+	}
+	if spec.BootWait != nil {
+		transitionReq.BootWait = &bootWaitRequest{
+			Target:       spec.BootWait.Target,
+			WaitForReady: spec.BootWait.WaitForReady,
+		}
+	}
+	if spec.Escalation != nil {
+		transitionReq.Escalation = &escalationRequest{AfterSeconds: spec.Escalation.AfterSeconds}
+	}
+
+	if selector := req.Selector.toModel(); len(req.Nodes) > 0 || len(req.Groups) > 0 || !selector.IsZero() {
+		transitionReq.Nodes = req.Nodes
+		transitionReq.Groups = req.Groups
+		transitionReq.Selector = selector
+		transitionReq.GroupOperation = req.GroupOperation
+	}
+	if len(req.ExcludeNodes) > 0 || len(req.ExcludeGroups) > 0 {
+		transitionReq.ExcludeNodes = req.ExcludeNodes
+		transitionReq.ExcludeGroups = req.ExcludeGroups
+	}
+	if req.DryRun != nil {
+		transitionReq.DryRun = *req.DryRun
+	}
+	if req.BootWait != nil {
+		transitionReq.BootWait = req.BootWait
+	}
+	if req.BatchSize != nil {
+		transitionReq.BatchSize = *req.BatchSize
+	}
+	if req.Escalation != nil {
+		transitionReq.Escalation = req.Escalation
+	}
+
+	s.startTransition(w, r, transitionReq)
+}
+
+// templateFromRequest validates a template body. Settings the engine checks
+// per transition (boot wait targets) are validated when the template runs.
+func templateFromRequest(name string, req templateRequest) (model.TransitionTemplate, error) {
+	if !templateNamePattern.MatchString(name) {
+		return model.TransitionTemplate{}, fmt.Errorf(
+			"invalid template name %q: use up to 63 letters, digits, '.', '_' or '-'",
+			name,
+		)
+	}
+	operation, err := redfish.ParseResetOperation(strings.TrimSpace(req.Operation))
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("invalid operation %q", req.Operation)
+	}
+	if req.BatchSize < 0 {
+		return model.TransitionTemplate{}, fmt.Errorf("batchSize must not be negative")
+	}
+
+	groupOperation := strings.ToLower(strings.TrimSpace(req.GroupOperation))
+	if groupOperation != "" && groupOperation != groupOperationUnion && groupOperation != groupOperationIntersection {
+		return model.TransitionTemplate{}, fmt.Errorf(
+			"groupOperation must be %q or %q",
+			groupOperationUnion,
+			groupOperationIntersection,
+		)
+	}
+
+	spec := model.TemplateSpec{
+		Operation:      string(operation),
+		Nodes:          parseTargetList(req.Nodes),
+		Groups:         parseTargetList(req.Groups),
+		Selector:       req.Selector.toModel(),
+		GroupOperation: groupOperation,
+		ExcludeNodes:   parseTargetList(req.ExcludeNodes),
+		ExcludeGroups:  parseTargetList(req.ExcludeGroups),
+		DryRun:         req.DryRun,
+		BatchSize:      req.BatchSize,
+	}
+	if req.BootWait != nil {
+		spec.BootWait = &model.TemplateBootWait{
+			Target:       strings.TrimSpace(req.BootWait.Target),
+			WaitForReady: req.BootWait.WaitForReady,
+		}
+	}
+	if req.Escalation != nil {
+		if req.Escalation.AfterSeconds <= 0 {
+			return model.TransitionTemplate{}, fmt.Errorf("escalation.afterSeconds must be positive")
+		}
+		if operation != redfish.ResetOperationGracefulShutdown && operation != redfish.ResetOperationGracefulRestart {
+			return model.TransitionTemplate{}, fmt.Errorf(
+				"escalation requires GracefulShutdown or GracefulRestart, got %s",
+				operation,
+			)
+		}
+		spec.Escalation = &model.TemplateEscalation{AfterSeconds: req.Escalation.AfterSeconds}
+	}
+	if req.Guard != nil {
+		if req.Guard.MaxNodes < 0 {
+			return model.TransitionTemplate{}, fmt.Errorf("guard.maxNodes must not be negative")
+		}
+		spec.Guard = &model.TemplateGuard{
+			MaxNodes:            req.Guard.MaxNodes,
+			RequireConfirmation: req.Guard.RequireConfirmation,
+		}
+	}
+
+	return model.TransitionTemplate{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Spec:        spec,
+	}, nil
+}
+
+func parseTemplateVersion(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("version must be a positive integer")
+	}
+	return version, nil
+}
+
+func respondTemplateLoadError(w http.ResponseWriter, r *http.Request, name string, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.RespondProblemf(w, r, http.StatusNotFound, "template %q not found", name)
+		return
+	}
+	httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load template")
+}
+
+func respondTemplateList(w http.ResponseWriter, items []model.TransitionTemplate) {
+	resources := make([]httputil.Resource[templateSpec], 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toTemplateResource(item))
+	}
+	httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[templateSpec]{
+		Kind:       "TransitionTemplateList",
+		APIVersion: "power/v1",
+		Metadata: httputil.ListMetadata{
+			Total:  len(resources),
+			Limit:  len(resources),
+			Offset: 0,
+		},
+		Items: resources,
+	})
+}
+
+func toTemplateResource(item model.TransitionTemplate) httputil.Resource[templateSpec] {
+	spec := templateSpec{
+		Name:           item.Name,
+		Version:        item.Version,
+		Description:    item.Description,
+		Operation:      item.Spec.Operation,
+		Nodes:          item.Spec.Nodes,
+		Groups:         item.Spec.Groups,
+		GroupOperation: item.Spec.GroupOperation,
+		ExcludeNodes:   item.Spec.ExcludeNodes,
+		ExcludeGroups:  item.Spec.ExcludeGroups,
+		DryRun:         item.Spec.DryRun,
+		BatchSize:      item.Spec.BatchSize,
+		CreatedBy:      item.CreatedBy,
+	}
+	if selector := item.Spec.Selector; !selector.IsZero() {
+		spec.Selector = &selectorRequest{
+			Role:    selector.Role,
+			SubRole: selector.SubRole,
+			Arch:    selector.Arch,
+			State:   selector.State,
+			Cabinet: selector.Cabinet,
+		}
+	}
+	if item.Spec.BootWait != nil {
+		spec.BootWait = &bootWaitRequest{
+			Target:       item.Spec.BootWait.Target,
+			WaitForReady: item.Spec.BootWait.WaitForReady,
+		}
+	}
+	if item.Spec.Escalation != nil {
+		spec.Escalation = &escalationRequest{AfterSeconds: item.Spec.Escalation.AfterSeconds}
+	}
+	if item.Spec.Guard != nil {
+		spec.Guard = &templateGuardRequest{
+			MaxNodes:            item.Spec.Guard.MaxNodes,
+			RequireConfirmation: item.Spec.Guard.RequireConfirmation,
+		}
+	}
+
+	return httputil.Resource[templateSpec]{
+		Kind:       "TransitionTemplate",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID:        fmt.Sprintf("%s@%d", item.Name, item.Version),
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.CreatedAt,
+		},
+		Spec: spec,
+	}
+}
@@ -24,6 +24,8 @@ import (
 type mockTransitionRunner struct {
 	startTransitionFn func(ctx context.Context, req engine.StartRequest) (engine.Transition, error)
 	abortTransitionFn func(ctx context.Context, transitionID string) error
+	decideApprovalFn  func(ctx context.Context, decision engine.ApprovalDecision) (engine.Transition, error)
+	abortTaskFn       func(ctx context.Context, transitionID, nodeID string) error
 }
 
 func (m *mockTransitionRunner) StartTransition(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
@@ -40,6 +42,20 @@ func (m *mockTransitionRunner) AbortTransition(ctx context.Context, transitionID
 	return nil
 }
 
+func (m *mockTransitionRunner) AbortTask(ctx context.Context, transitionID, nodeID string) error {
+	if m.abortTaskFn != nil {
+		return m.abortTaskFn(ctx, transitionID, nodeID)
+	}
+	return nil
+}
+
+func (m *mockTransitionRunner) DecideApproval(ctx context.Context, decision engine.ApprovalDecision) (engine.Transition, error) {
+	if m.decideApprovalFn != nil {
+		return m.decideApprovalFn(ctx, decision)
+	}
+	return engine.Transition{}, engine.ErrApprovalNotPending
+}
+
 type mockPowerStore struct {
 	pingFn                func(ctx context.Context) error
 	resolveNodeMappingsFn func(ctx context.Context, nodeIDs []string) ([]model.NodePowerMapping, []model.NodeMappingError, error)
@@ -47,9 +63,12 @@ type mockPowerStore struct {
 	getTransitionFn       func(ctx context.Context, id string) (engine.Transition, error)
 	listTransitionTasksFn func(ctx context.Context, transitionID string) ([]engine.Task, error)
 	listLatestTasksByNode func(ctx context.Context, nodeIDs []string) ([]engine.Task, error)
-	replaceMappingsFn     func(ctx context.Context, endpoints []model.BMCEndpoint, links []model.NodeBMCLink, syncedAt time.Time) (model.MappingApplyCounts, error)
 	listBMCEndpointsFn    func(ctx context.Context) ([]model.BMCEndpoint, error)
 	listNodeBMCLinksFn    func(ctx context.Context) ([]model.NodeBMCLink, error)
+	listTelemetryFn       func(ctx context.Context, query model.TelemetryQuery) ([]model.TelemetryPoint, error)
+	getTemplateFn         func(ctx context.Context, name string, version int) (model.TransitionTemplate, error)
+	createTemplateFn      func(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error)
+	updateTemplateFn      func(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error)
 }
 
 func (m *mockPowerStore) Ping(ctx context.Context) error {
@@ -59,15 +78,11 @@ func (m *mockPowerStore) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockPowerStore) ReplaceTopologyMappings(
+func (m *mockPowerStore) ApplyTopologyDelta(
 	ctx context.Context,
-	endpoints []model.BMCEndpoint,
-	links []model.NodeBMCLink,
+	delta model.TopologyDelta,
 	syncedAt time.Time,
 ) (model.MappingApplyCounts, error) {
-	if m.replaceMappingsFn != nil {
-		return m.replaceMappingsFn(ctx, endpoints, links, syncedAt)
-	}
 	return model.MappingApplyCounts{}, nil
 }
 
@@ -116,6 +131,29 @@ func (m *mockPowerStore) ListTransitionTasks(ctx context.Context, transitionID s
 	return []engine.Task{}, nil
 }
 
+func (m *mockPowerStore) ListTransitionTasksPage(ctx context.Context, transitionID string, limit, offset int) ([]engine.Task, error) {
+	tasks, err := m.ListTransitionTasks(ctx, transitionID)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= len(tasks) {
+		return []engine.Task{}, nil
+	}
+	return tasks[offset:min(offset+limit, len(tasks))], nil
+}
+
+func (m *mockPowerStore) CountTransitionTasksByState(ctx context.Context, transitionID string) (map[string]int, error) {
+	tasks, err := m.ListTransitionTasks(ctx, transitionID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.State]++
+	}
+	return counts, nil
+}
+
 func (m *mockPowerStore) ListLatestTransitionTasksByNode(ctx context.Context, nodeIDs []string) ([]engine.Task, error) {
 	if m.listLatestTasksByNode != nil {
 		return m.listLatestTasksByNode(ctx, nodeIDs)
@@ -123,6 +161,47 @@ func (m *mockPowerStore) ListLatestTransitionTasksByNode(ctx context.Context, no
 	return []engine.Task{}, nil
 }
 
+func (m *mockPowerStore) ListTelemetry(ctx context.Context, query model.TelemetryQuery) ([]model.TelemetryPoint, error) {
+	if m.listTelemetryFn != nil {
+		return m.listTelemetryFn(ctx, query)
+	}
+	return []model.TelemetryPoint{}, nil
+}
+
+func (m *mockPowerStore) ListTransitionTemplates(ctx context.Context) ([]model.TransitionTemplate, error) {
+	return []model.TransitionTemplate{}, nil
+}
+
+func (m *mockPowerStore) ListTransitionTemplateVersions(ctx context.Context, name string) ([]model.TransitionTemplate, error) {
+	return nil, store.ErrNotFound
+}
+
+func (m *mockPowerStore) GetTransitionTemplate(ctx context.Context, name string, version int) (model.TransitionTemplate, error) {
+	if m.getTemplateFn != nil {
+		return m.getTemplateFn(ctx, name, version)
+	}
+	return model.TransitionTemplate{}, store.ErrNotFound
+}
+
+func (m *mockPowerStore) CreateTransitionTemplate(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error) {
+	if m.createTemplateFn != nil {
+		return m.createTemplateFn(ctx, item)
+	}
+	item.Version = 1
+	return item, nil
+}
+
+func (m *mockPowerStore) UpdateTransitionTemplate(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error) {
+	if m.updateTemplateFn != nil {
+		return m.updateTemplateFn(ctx, item)
+	}
+	return model.TransitionTemplate{}, store.ErrNotFound
+}
+
+func (m *mockPowerStore) DeleteTransitionTemplate(ctx context.Context, name string) error {
+	return store.ErrNotFound
+}
+
 func newHandlerTestServer(
 	t *testing.T,
 	st *mockPowerStore,
@@ -381,6 +460,75 @@ func TestActionReset_RejectsInvalidOperation(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestActionOn_PassesBootWaitAndReturnsBootStages(t *testing.T) {
+	observedAt := time.Date(2026, 1, 1, 0, 2, 0, 0, time.UTC)
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			require.NotNil(t, req.BootWait)
+			assert.Equal(t, "OSRunning", req.BootWait.Target)
+			assert.True(t, req.BootWait.WaitForReady)
+			return engine.Transition{ID: "transition-1", Operation: req.Operation, QueuedAt: observedAt}, nil
+		},
+	}
+	st := &mockPowerStore{
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{{
+				NodeID:        "node-1",
+				Operation:     "On",
+				State:         engine.TaskStateRunning,
+				BootTarget:    "OSRunning",
+				BootWaitReady: true,
+				BootStage:     "OSBootStarted",
+				BootStages:    []engine.BootStage{{Stage: "OSBootStarted", ObservedAt: observedAt}},
+				QueuedAt:      observedAt,
+			}}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/power/v1/actions/on",
+		bytes.NewBufferString(`{"nodes":["node-1"],"bootWait":{"target":"OSRunning","waitForReady":true}}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Len(t, out.Spec.Tasks, 1)
+	task := out.Spec.Tasks[0]
+	assert.Equal(t, "OSRunning", task.BootTarget)
+	assert.True(t, task.BootWaitReady)
+	assert.Equal(t, "OSBootStarted", task.BootStage)
+	require.Len(t, task.BootStages, 1)
+	assert.Equal(t, "2026-01-01T00:02:00Z", string(task.BootStages[0].ObservedAt))
+}
+
+func TestCreateTransition_InvalidBootWait(t *testing.T) {
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			return engine.Transition{}, fmt.Errorf("%w: operation %q does not boot the node", engine.ErrInvalidBootWait, req.Operation)
+		},
+	}
+	srv := newHandlerTestServer(t, &mockPowerStore{}, runner, nil)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/power/v1/transitions",
+		bytes.NewBufferString(`{"operation":"ForceOff","nodes":["node-1"],"bootWait":{"target":"OSRunning"}}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "does not boot the node")
+}
+
 func TestRequireAnyScope(t *testing.T) {
 	hit := false
 	mw := requireAnyScope("read:power", "admin")
@@ -423,6 +571,963 @@ func TestCreateTransition_GroupNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestCreateTransition_QueueFull(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			return engine.Transition{}, &engine.QueueFullError{Depth: 90, Limit: 80, RetryAfter: 1500 * time.Millisecond}
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(`{"operation":"On","nodes":["node-1"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), "90 tasks queued, limit 80")
+}
+
+type mockPowerCapReader struct {
+	readPowerCapsFn func(ctx context.Context, nodeIDs []string) ([]engine.NodePowerReading, error)
+}
+
+func (m *mockPowerCapReader) ReadPowerCaps(ctx context.Context, nodeIDs []string) ([]engine.NodePowerReading, error) {
+	return m.readPowerCapsFn(ctx, nodeIDs)
+}
+
+func TestApplyPowerCap_StartsPowerCapTransition(t *testing.T) {
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			assert.Equal(t, engine.OperationPowerCap, req.Operation)
+			assert.Equal(t, []string{"node-1", "node-2"}, req.NodeIDs)
+			require.NotNil(t, req.PowerCapWatts)
+			assert.Equal(t, 450, *req.PowerCapWatts)
+			return engine.Transition{ID: "transition-1", Operation: req.Operation}, nil
+		},
+	}
+	st := &mockPowerStore{
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			limit := 450
+			return []engine.Task{{NodeID: "node-1", Operation: engine.OperationPowerCap, PowerCapWatts: &limit}}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/power/v1/powercap",
+		bytes.NewBufferString(`{"nodes":["node-2","node-1"],"limitWatts":450}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "/power/v1/transitions/transition-1", resp.Header().Get("Location"))
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, engine.OperationPowerCap, out.Spec.Operation)
+	require.Len(t, out.Spec.Tasks, 1)
+	require.NotNil(t, out.Spec.Tasks[0].PowerCapWatts)
+	assert.Equal(t, 450, *out.Spec.Tasks[0].PowerCapWatts)
+}
+
+func TestApplyPowerCap_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{name: "missing limit", body: `{"nodes":["node-1"]}`, status: http.StatusBadRequest},
+		{name: "negative limit", body: `{"nodes":["node-1"],"limitWatts":-5}`, status: http.StatusBadRequest},
+		{name: "out of range", body: `{"nodes":["node-1"],"limitWatts":5}`, err: engine.ErrInvalidPowerCap, status: http.StatusBadRequest},
+		{name: "unsupported", body: `{"nodes":["node-1"],"limitWatts":500}`, err: engine.ErrPowerCapUnsupported, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockTransitionRunner{
+				startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+					if tt.err == nil {
+						t.Fatal("transition must not start")
+					}
+					return engine.Transition{}, tt.err
+				},
+			}
+			srv := newHandlerTestServer(t, &mockPowerStore{}, runner, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/power/v1/powercap", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+		})
+	}
+}
+
+func TestCreateTransition_RejectsPowerCapOperation(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(`{"operation":"PowerCap","nodes":["node-1"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestGetPowerCap_ReturnsPerNodeReadings(t *testing.T) {
+	limit := 500
+	consumed := 287.5
+	reader := &mockPowerCapReader{
+		readPowerCapsFn: func(ctx context.Context, nodeIDs []string) ([]engine.NodePowerReading, error) {
+			assert.Equal(t, []string{"node-1", "node-2"}, nodeIDs)
+			return []engine.NodePowerReading{
+				{
+					NodeID: "node-1",
+					BMCID:  "bmc-1",
+					Reading: engine.PowerReading{
+						LimitWatts:    &limit,
+						ConsumedWatts: &consumed,
+						Source:        engine.PowerSourceEnvironmentMetrics,
+					},
+				},
+				{NodeID: "node-2", ErrorDetail: "node not found in topology mapping"},
+			}, nil
+		},
+	}
+	srv := New(&mockPowerStore{}, config.Config{DevMode: true, BulkMaxNodes: 20}, "v1", "abc", "now", WithPowerCapReader(reader))
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/powercap?nodes=node-2,node-1", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var out httputil.Resource[powerCapResponse]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, "PowerCap", out.Kind)
+	require.Equal(t, 2, out.Spec.Total)
+	require.NotNil(t, out.Spec.Nodes[0].LimitWatts)
+	assert.Equal(t, 500, *out.Spec.Nodes[0].LimitWatts)
+	require.NotNil(t, out.Spec.Nodes[0].ConsumedWatts)
+	assert.InDelta(t, 287.5, *out.Spec.Nodes[0].ConsumedWatts, 0.001)
+	assert.Equal(t, engine.PowerSourceEnvironmentMetrics, out.Spec.Nodes[0].Source)
+	assert.Equal(t, "node not found in topology mapping", out.Spec.Nodes[1].ErrorDetail)
+}
+
+func TestGetPowerCap_Unavailable(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/powercap?nodes=node-1", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
+
 func ptrTime(v time.Time) *time.Time {
 	return &v
 }
+
+func TestActionBootOverride_StartsBootOverrideResetTransition(t *testing.T) {
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			assert.Equal(t, engine.OperationBootOverrideReset, req.Operation)
+			assert.Equal(t, []string{"node-1"}, req.NodeIDs)
+			require.NotNil(t, req.BootOverride)
+			assert.Equal(t, engine.BootOverride{Target: "Cd", Mode: "Once"}, *req.BootOverride)
+			require.NotNil(t, req.VirtualMedia)
+			assert.Equal(t, "http://images.example/rescue.iso", req.VirtualMedia.Image)
+			assert.Equal(t, "GracefulRestart", req.ResetOperation)
+			require.NotNil(t, req.BootWait)
+			assert.Equal(t, "OSRunning", req.BootWait.Target)
+			return engine.Transition{ID: "transition-1", Operation: req.Operation}, nil
+		},
+	}
+	st := &mockPowerStore{
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{{
+				NodeID:             "node-1",
+				Operation:          engine.OperationBootOverrideReset,
+				BootOverrideTarget: "Cd",
+				BootOverrideMode:   "Once",
+				MediaImage:         "http://images.example/rescue.iso",
+				ResetOperation:     "GracefulRestart",
+			}}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/power/v1/actions/boot-override",
+		bytes.NewBufferString(`{
+			"nodes":["node-1"],
+			"target":"Cd",
+			"mode":"Once",
+			"image":"http://images.example/rescue.iso",
+			"resetOperation":"GracefulRestart",
+			"bootWait":{"target":"OSRunning"}
+		}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, engine.OperationBootOverrideReset, out.Spec.Operation)
+	require.Len(t, out.Spec.Tasks, 1)
+	assert.Equal(t, "Cd", out.Spec.Tasks[0].BootOverrideTarget)
+	assert.Equal(t, "Once", out.Spec.Tasks[0].BootOverrideMode)
+	assert.Equal(t, "http://images.example/rescue.iso", out.Spec.Tasks[0].MediaImage)
+	assert.Equal(t, "GracefulRestart", out.Spec.Tasks[0].ResetOperation)
+}
+
+func TestBootControlEndpoints_StartTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		operation string
+		check     func(t *testing.T, req engine.StartRequest)
+	}{
+		{
+			name:      "boot override",
+			path:      "/power/v1/boot-override",
+			body:      `{"nodes":["node-1"],"target":"Pxe","mode":"Continuous"}`,
+			operation: engine.OperationSetBootOverride,
+			check: func(t *testing.T, req engine.StartRequest) {
+				require.NotNil(t, req.BootOverride)
+				assert.Equal(t, engine.BootOverride{Target: "Pxe", Mode: "Continuous"}, *req.BootOverride)
+			},
+		},
+		{
+			name:      "insert media",
+			path:      "/power/v1/virtual-media/insert",
+			body:      `{"nodes":["node-1"],"image":"http://images.example/rescue.iso"}`,
+			operation: engine.OperationInsertMedia,
+			check: func(t *testing.T, req engine.StartRequest) {
+				require.NotNil(t, req.VirtualMedia)
+				assert.Equal(t, "http://images.example/rescue.iso", req.VirtualMedia.Image)
+			},
+		},
+		{
+			name:      "eject media",
+			path:      "/power/v1/virtual-media/eject",
+			body:      `{"nodes":["node-1"]}`,
+			operation: engine.OperationEjectMedia,
+			check: func(t *testing.T, req engine.StartRequest) {
+				assert.Nil(t, req.VirtualMedia)
+				assert.Nil(t, req.BootOverride)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockTransitionRunner{
+				startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+					assert.Equal(t, tt.operation, req.Operation)
+					tt.check(t, req)
+					return engine.Transition{ID: "transition-1", Operation: req.Operation}, nil
+				},
+			}
+			srv := newHandlerTestServer(t, &mockPowerStore{}, runner, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusAccepted, resp.Code)
+		})
+	}
+}
+
+func TestBootControlEndpoints_RejectInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		err    error
+		status int
+	}{
+		{name: "missing target", path: "/power/v1/boot-override", body: `{"nodes":["node-1"]}`, status: http.StatusBadRequest},
+		{name: "missing image", path: "/power/v1/virtual-media/insert", body: `{"nodes":["node-1"]}`, status: http.StatusBadRequest},
+		{name: "reset missing target", path: "/power/v1/actions/boot-override", body: `{"nodes":["node-1"]}`, status: http.StatusBadRequest},
+		{
+			name:   "invalid override",
+			path:   "/power/v1/boot-override",
+			body:   `{"nodes":["node-1"],"target":"Floppy"}`,
+			err:    engine.ErrInvalidBootOverride,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid media",
+			path:   "/power/v1/virtual-media/insert",
+			body:   `{"nodes":["node-1"],"image":"rescue.iso"}`,
+			err:    engine.ErrInvalidVirtualMedia,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unsupported",
+			path:   "/power/v1/virtual-media/eject",
+			body:   `{"nodes":["node-1"]}`,
+			err:    engine.ErrBootControlUnsupported,
+			status: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &mockTransitionRunner{
+				startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+					if tt.err == nil {
+						t.Fatal("transition must not start")
+					}
+					return engine.Transition{}, tt.err
+				},
+			}
+			srv := newHandlerTestServer(t, &mockPowerStore{}, runner, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.status, resp.Code)
+		})
+	}
+}
+
+func ptrFloat(v float64) *float64 {
+	return &v
+}
+
+func TestGetTelemetry_ReturnsNodeAndGroupSeries(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(5 * time.Minute)
+	st := &mockPowerStore{
+		listTelemetryFn: func(ctx context.Context, query model.TelemetryQuery) ([]model.TelemetryPoint, error) {
+			assert.Equal(t, []string{"node-1", "node-2", "node-3"}, query.NodeIDs)
+			assert.Equal(t, t0, query.Since)
+			assert.Equal(t, t0.Add(time.Hour), query.Until)
+			// 4m is rounded up to a multiple of the 2m resolution.
+			assert.Equal(t, 4*time.Minute, query.Step)
+			return []model.TelemetryPoint{
+				{NodeID: "node-1", Timestamp: t0, Samples: 4, PowerWatts: ptrFloat(400), InletTempCelsius: ptrFloat(20), MaxInletTemp: ptrFloat(21)},
+				{NodeID: "node-1", Timestamp: t1, Samples: 4, PowerWatts: ptrFloat(450), InletTempCelsius: ptrFloat(22), MaxInletTemp: ptrFloat(23)},
+				{NodeID: "node-2", Timestamp: t0, Samples: 4, PowerWatts: ptrFloat(300), InletTempCelsius: ptrFloat(24), MaxInletTemp: ptrFloat(26)},
+			}, nil
+		},
+	}
+	resolver := func(ctx context.Context, group string) ([]string, error) {
+		assert.Equal(t, "rack-1", group)
+		return []string{"node-1", "node-2"}, nil
+	}
+	srv := New(st, config.Config{DevMode: true, BulkMaxNodes: 20, TelemetryEnabled: true, TelemetryResolution: 2 * time.Minute},
+		"v1", "abc", "now", WithGroupMemberResolver(resolver))
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/power/v1/telemetry?groups=rack-1&nodes=node-3&since=2026-03-01T12:00:00Z&until=2026-03-01T13:00:00Z&step=3m",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var out httputil.Resource[telemetryResponse]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, "Telemetry", out.Kind)
+	assert.Equal(t, int64(240), out.Spec.StepSeconds)
+	require.Len(t, out.Spec.Nodes, 3)
+	assert.Len(t, out.Spec.Nodes[0].Points, 2)
+	assert.Empty(t, out.Spec.Nodes[2].Points)
+
+	require.Len(t, out.Spec.Groups, 1)
+	group := out.Spec.Groups[0]
+	assert.Equal(t, "rack-1", group.Group)
+	assert.Equal(t, 2, group.NodeCount)
+	require.Len(t, group.Points, 2)
+	assert.Equal(t, 2, group.Points[0].ReportingNodes)
+	assert.InDelta(t, 700, *group.Points[0].PowerWatts, 0.001)
+	assert.InDelta(t, 22, *group.Points[0].InletTempCelsius, 0.001)
+	assert.InDelta(t, 26, *group.Points[0].MaxInletTempCelsius, 0.001)
+	assert.Equal(t, 1, group.Points[1].ReportingNodes)
+	assert.InDelta(t, 450, *group.Points[1].PowerWatts, 0.001)
+}
+
+func TestGetTelemetry_RejectsInvalidQueries(t *testing.T) {
+	cfg := config.Config{DevMode: true, BulkMaxNodes: 20, TelemetryEnabled: true, TelemetryResolution: time.Minute}
+	srv := New(&mockPowerStore{}, cfg, "v1", "abc", "now")
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "no targets", query: ""},
+		{name: "bad since", query: "nodes=node-1&since=yesterday"},
+		{name: "bad until", query: "nodes=node-1&until=12:00"},
+		{name: "since after until", query: "nodes=node-1&since=2026-03-01T13:00:00Z&until=2026-03-01T12:00:00Z"},
+		{name: "step below resolution", query: "nodes=node-1&step=10s"},
+		{name: "too many points", query: "nodes=node-1&since=72h&step=1m"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/power/v1/telemetry?"+tc.query, nil)
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestGetTelemetry_Disabled(t *testing.T) {
+	srv := newHandlerTestServer(t, &mockPowerStore{}, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/telemetry?nodes=node-1", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
+
+func TestCreateTemplate(t *testing.T) {
+	var created model.TransitionTemplate
+	st := &mockPowerStore{
+		createTemplateFn: func(ctx context.Context, item model.TransitionTemplate) (model.TransitionTemplate, error) {
+			if item.Name == "exists" {
+				return model.TransitionTemplate{}, store.ErrConflict
+			}
+			created = item
+			item.Version = 1
+			return item, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, nil, nil)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{
+			name:   "valid",
+			body:   `{"name":"drain-rack","operation":"GracefulShutdown","groups":["rack-1"],"batchSize":32,"escalation":{"afterSeconds":300},"guard":{"maxNodes":64,"requireConfirmation":true}}`,
+			status: http.StatusCreated,
+		},
+		{name: "invalid name", body: `{"name":"bad name","operation":"On"}`, status: http.StatusBadRequest},
+		{name: "invalid operation", body: `{"name":"t","operation":"Explode"}`, status: http.StatusBadRequest},
+		{name: "escalation on forced operation", body: `{"name":"t","operation":"ForceOff","escalation":{"afterSeconds":60}}`, status: http.StatusBadRequest},
+		{name: "negative batch", body: `{"name":"t","operation":"On","batchSize":-1}`, status: http.StatusBadRequest},
+		{name: "invalid group operation", body: `{"name":"t","operation":"On","groups":["a"],"groupOperation":"xor"}`, status: http.StatusBadRequest},
+		{name: "duplicate", body: `{"name":"exists","operation":"On"}`, status: http.StatusConflict},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/power/v1/templates", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+			assert.Equal(t, tc.status, resp.Code, resp.Body.String())
+		})
+	}
+
+	assert.Equal(t, "drain-rack", created.Name)
+	assert.Equal(t, "GracefulShutdown", created.Spec.Operation)
+	assert.Equal(t, []string{"rack-1"}, created.Spec.Groups)
+	assert.Equal(t, 32, created.Spec.BatchSize)
+	require.NotNil(t, created.Spec.Escalation)
+	assert.Equal(t, 300, created.Spec.Escalation.AfterSeconds)
+	require.NotNil(t, created.Spec.Guard)
+	assert.True(t, created.Spec.Guard.RequireConfirmation)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/templates", bytes.NewBufferString(`{
+		"name": "reboot-gpu",
+		"operation": "GracefulRestart",
+		"groups": ["rack-1", "gpu"],
+		"groupOperation": "Intersection",
+		"selector": {"role": "Compute", "subRole": " Worker "},
+		"excludeNodes": ["nid001"],
+		"excludeGroups": ["drained"]
+	}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	assert.Equal(t, model.NodeSelector{Role: "Compute", SubRole: "Worker"}, created.Spec.Selector)
+	assert.Equal(t, "intersection", created.Spec.GroupOperation)
+	assert.Equal(t, []string{"nid001"}, created.Spec.ExcludeNodes)
+	assert.Equal(t, []string{"drained"}, created.Spec.ExcludeGroups)
+
+	var out httputil.Resource[templateSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.NotNil(t, out.Spec.Selector)
+	assert.Equal(t, "Compute", out.Spec.Selector.Role)
+	assert.Equal(t, []string{"drained"}, out.Spec.ExcludeGroups)
+}
+
+func TestRunTemplate_AppliesOverridesAndLinksTemplate(t *testing.T) {
+	st := &mockPowerStore{
+		getTemplateFn: func(ctx context.Context, name string, version int) (model.TransitionTemplate, error) {
+			assert.Equal(t, "drain-rack", name)
+			assert.Zero(t, version)
+			return model.TransitionTemplate{
+				Name:    "drain-rack",
+				Version: 3,
+				Spec: model.TemplateSpec{
+					Operation:  "GracefulShutdown",
+					Groups:     []string{"rack-1"},
+					BatchSize:  32,
+					Escalation: &model.TemplateEscalation{AfterSeconds: 300},
+					Guard:      &model.TemplateGuard{MaxNodes: 2, RequireConfirmation: true},
+				},
+			}, nil
+		},
+	}
+	var started engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			started = req
+			return engine.Transition{
+				ID:              "tr-1",
+				Operation:       req.Operation,
+				State:           engine.TransitionStatePending,
+				BatchSize:       req.BatchSize,
+				EscalateAfter:   req.Escalation.After,
+				TemplateName:    req.TemplateName,
+				TemplateVersion: req.TemplateVersion,
+			}, nil
+		},
+	}
+	resolver := func(ctx context.Context, group string) ([]string, error) {
+		return []string{"node-1", "node-2"}, nil
+	}
+	srv := newHandlerTestServer(t, st, runner, resolver)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/power/v1/templates/drain-rack/run",
+		bytes.NewBufferString(`{"batchSize":8,"confirm":true}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, "GracefulShutdown", started.Operation)
+	assert.Equal(t, []string{"node-1", "node-2"}, started.NodeIDs)
+	assert.Equal(t, 8, started.BatchSize)
+	require.NotNil(t, started.Escalation)
+	assert.Equal(t, 5*time.Minute, started.Escalation.After)
+	assert.Equal(t, "drain-rack", started.TemplateName)
+	assert.Equal(t, 3, started.TemplateVersion)
+
+	var out httputil.Resource[transitionSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, "drain-rack", out.Spec.TemplateName)
+	assert.Equal(t, 3, out.Spec.TemplateVersion)
+	assert.Equal(t, 300, out.Spec.EscalateAfterSeconds)
+}
+
+func TestRunTemplate_EnforcesGuard(t *testing.T) {
+	st := &mockPowerStore{
+		getTemplateFn: func(ctx context.Context, name string, version int) (model.TransitionTemplate, error) {
+			if name != "guarded" {
+				return model.TransitionTemplate{}, store.ErrNotFound
+			}
+			return model.TransitionTemplate{
+				Name:    "guarded",
+				Version: 1,
+				Spec: model.TemplateSpec{
+					Operation: "ForceOff",
+					Nodes:     []string{"node-1"},
+					Guard:     &model.TemplateGuard{MaxNodes: 2, RequireConfirmation: true},
+				},
+			}, nil
+		},
+	}
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			t.Errorf("guarded run must not start a transition")
+			return engine.Transition{}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		detail string
+	}{
+		{name: "missing confirmation", path: "guarded", body: `{}`, status: http.StatusBadRequest, detail: "requires confirm"},
+		{
+			name:   "too many nodes",
+			path:   "guarded",
+			body:   `{"nodes":["node-1","node-2","node-3"],"confirm":true}`,
+			status: http.StatusBadRequest,
+			detail: "max 2",
+		},
+		{name: "unknown template", path: "missing", body: `{}`, status: http.StatusNotFound, detail: "not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/power/v1/templates/"+tc.path+"/run",
+				bytes.NewBufferString(tc.body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+			assert.Equal(t, tc.status, resp.Code)
+			assert.Contains(t, resp.Body.String(), tc.detail)
+		})
+	}
+}
+
+func TestCreateTransition_ApprovalPolicy(t *testing.T) {
+	var got *engine.ApprovalRequirement
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			got = req.Approval
+			return engine.Transition{ID: "transition-1", Operation: req.Operation, State: engine.TransitionStatePending}, nil
+		},
+	}
+	srv := New(
+		&mockPowerStore{},
+		config.Config{
+			DevMode:                 true,
+			BulkMaxNodes:            20,
+			ApprovalMaxNodes:        2,
+			ApprovalProtectedGroups: []string{"storage", "retired"},
+			ApprovalTTL:             time.Hour,
+		},
+		"v1",
+		"abc",
+		"now",
+		WithTransitionRunner(runner),
+		WithGroupMemberResolver(func(ctx context.Context, group string) ([]string, error) {
+			if group == "storage" {
+				return []string{"node-9"}, nil
+			}
+			return nil, ErrGroupNotFound
+		}),
+	)
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "large", body: `{"operation":"On","nodes":["node-1","node-2","node-3"]}`, reason: "targets 3 nodes, more than 2"},
+		{name: "force off protected", body: `{"operation":"ForceOff","nodes":["node-9"]}`, reason: "forces off protected groups storage"},
+		{name: "escalating shutdown of protected", body: `{"operation":"GracefulShutdown","nodes":["node-9"],"escalation":{"afterSeconds":60}}`, reason: "forces off protected groups storage"},
+		{name: "force off unprotected", body: `{"operation":"ForceOff","nodes":["node-1"]}`},
+		{name: "graceful shutdown of protected", body: `{"operation":"GracefulShutdown","nodes":["node-9"]}`},
+		{name: "dry run", body: `{"operation":"ForceOff","nodes":["node-1","node-2","node-9"],"dryRun":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			srv.Router().ServeHTTP(resp, req)
+
+			require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+			if tt.reason == "" {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.reason, got.Reason)
+			assert.Equal(t, time.Hour, got.TTL)
+		})
+	}
+}
+
+func TestApproveTransition(t *testing.T) {
+	var decision engine.ApprovalDecision
+	decideErr := error(nil)
+	runner := &mockTransitionRunner{
+		decideApprovalFn: func(ctx context.Context, d engine.ApprovalDecision) (engine.Transition, error) {
+			decision = d
+			return engine.Transition{ID: d.TransitionID}, decideErr
+		},
+	}
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			return engine.Transition{ID: id, Operation: "ForceOff", State: engine.TransitionStatePending}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := post("/power/v1/transitions/t1/approve", `{"reason":"maintenance window"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "t1", decision.TransitionID)
+	assert.Equal(t, engine.ApprovalActionApproved, decision.Action)
+	assert.Equal(t, "maintenance window", decision.Reason)
+	assert.NotEmpty(t, decision.Principal)
+
+	resp = post("/power/v1/transitions/t1/reject", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, engine.ApprovalActionRejected, decision.Action)
+
+	for err, status := range map[error]int{
+		engine.ErrSelfApproval:       http.StatusForbidden,
+		engine.ErrApprovalNotPending: http.StatusConflict,
+		engine.ErrApprovalExpired:    http.StatusConflict,
+		store.ErrNotFound:            http.StatusNotFound,
+	} {
+		decideErr = err
+		resp = post("/power/v1/transitions/t1/approve", "")
+		assert.Equal(t, status, resp.Code, err.Error())
+	}
+}
+
+func TestDeleteTransition_CancelsPendingApproval(t *testing.T) {
+	var decision engine.ApprovalDecision
+	runner := &mockTransitionRunner{
+		abortTransitionFn: func(ctx context.Context, transitionID string) error {
+			return engine.ErrTransitionNotFound
+		},
+		decideApprovalFn: func(ctx context.Context, d engine.ApprovalDecision) (engine.Transition, error) {
+			decision = d
+			return engine.Transition{ID: d.TransitionID, State: engine.TransitionStateCanceled}, nil
+		},
+	}
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			return engine.Transition{ID: id, Operation: "ForceOff", State: engine.TransitionStateCanceled}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/power/v1/transitions/t1", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, "t1", decision.TransitionID)
+	assert.Equal(t, engine.ApprovalActionCanceled, decision.Action)
+}
+
+func TestDeleteTransitionTask(t *testing.T) {
+	abortErr := error(nil)
+	runner := &mockTransitionRunner{
+		abortTaskFn: func(ctx context.Context, transitionID, nodeID string) error {
+			return abortErr
+		},
+	}
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			if id != "t1" {
+				return engine.Transition{}, store.ErrNotFound
+			}
+			return engine.Transition{ID: id, Operation: "On", State: engine.TransitionStateRunning}, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{
+				{NodeID: "node-1", Operation: "On", State: engine.TaskStateCanceled},
+				{NodeID: "node-2", Operation: "On", State: engine.TaskStateSucceeded},
+			}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	deleteTask := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := deleteTask("/power/v1/transitions/t1/tasks/node-1")
+	assert.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	abortErr = engine.ErrTaskNotActive
+	resp = deleteTask("/power/v1/transitions/t1/tasks/node-2")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), "succeeded")
+
+	resp = deleteTask("/power/v1/transitions/t1/tasks/node-9")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	abortErr = engine.ErrTransitionNotFound
+	resp = deleteTask("/power/v1/transitions/t2/tasks/node-1")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestRetryTransition(t *testing.T) {
+	var got engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			got = req
+			return engine.Transition{ID: "t2", Operation: req.Operation, State: engine.TransitionStatePending, ParentID: req.ParentID}, nil
+		},
+	}
+	parents := map[string]engine.Transition{
+		"done":    {ID: "done", Operation: "GracefulShutdown", State: engine.TransitionStatePartial, BatchSize: 4, EscalateAfter: time.Minute},
+		"running": {ID: "running", Operation: "On", State: engine.TransitionStateRunning},
+		"capped":  {ID: "capped", Operation: engine.OperationPowerCap, State: engine.TransitionStateFailed},
+	}
+	watts := 350
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			parent, ok := parents[id]
+			if !ok {
+				return engine.Transition{}, store.ErrNotFound
+			}
+			return parent, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			if transitionID == "capped" {
+				return []engine.Task{{NodeID: "node-1", State: engine.TaskStateFailed, PowerCapWatts: &watts}}, nil
+			}
+			return []engine.Task{
+				{NodeID: "node-1", State: engine.TaskStateSucceeded},
+				{NodeID: "node-2", State: engine.TaskStateFailed},
+				{NodeID: "node-3", State: engine.TaskStateCanceled},
+			}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, runner, nil)
+
+	retry := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions/"+id+"/retry", bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := retry("done", "")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, "GracefulShutdown", got.Operation)
+	assert.Equal(t, []string{"node-2"}, got.NodeIDs)
+	assert.Equal(t, "done", got.ParentID)
+	assert.Equal(t, 4, got.BatchSize)
+	require.NotNil(t, got.Escalation)
+	assert.Equal(t, time.Minute, got.Escalation.After)
+
+	var body httputil.Resource[transitionSpec]
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "done", body.Spec.ParentID)
+
+	resp = retry("done", `{"includeCanceled":true}`)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, []string{"node-2", "node-3"}, got.NodeIDs)
+
+	resp = retry("capped", "")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, engine.OperationPowerCap, got.Operation)
+	require.NotNil(t, got.PowerCapWatts)
+	assert.Equal(t, 350, *got.PowerCapWatts)
+
+	assert.Equal(t, http.StatusConflict, retry("running", "").Code)
+	assert.Equal(t, http.StatusNotFound, retry("missing", "").Code)
+}
+
+func TestCreateTransition_ChunksBeyondBulkLimit(t *testing.T) {
+	var got engine.StartRequest
+	runner := &mockTransitionRunner{
+		startTransitionFn: func(ctx context.Context, req engine.StartRequest) (engine.Transition, error) {
+			got = req
+			return engine.Transition{ID: "transition-1", Operation: req.Operation, ChunkSize: req.ChunkSize}, nil
+		},
+	}
+	srv := New(
+		&mockPowerStore{},
+		config.Config{DevMode: true, BulkMaxNodes: 2, BulkHardMaxNodes: 4},
+		"v1",
+		"abc",
+		"now",
+		WithTransitionRunner(runner),
+	)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/power/v1/transitions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := create(`{"operation":"On","nodes":["node-1","node-2"]}`)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Zero(t, got.ChunkSize)
+
+	resp = create(`{"operation":"On","nodes":["node-1","node-2","node-3","node-4"]}`)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	assert.Equal(t, 2, got.ChunkSize)
+
+	var body httputil.Resource[transitionSpec]
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Spec.ChunkSize)
+
+	resp = create(`{"operation":"On","nodes":["node-1","node-2","node-3","node-4","node-5"]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "max 4")
+}
+
+func TestGetTransition_PaginatesTasks(t *testing.T) {
+	st := &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			return engine.Transition{ID: id, Operation: "On", State: engine.TransitionStateRunning, TargetCount: 3}, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{
+				{NodeID: "node-1", Operation: "On", State: engine.TaskStateSucceeded},
+				{NodeID: "node-2", Operation: "On", State: engine.TaskStateRunning},
+				{NodeID: "node-3", Operation: "On", State: engine.TaskStatePending},
+			}, nil
+		},
+	}
+	srv := newHandlerTestServer(t, st, &mockTransitionRunner{}, nil)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get("/power/v1/transitions/t1?taskLimit=1&taskOffset=1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var body httputil.Resource[transitionSpec]
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Spec.Tasks, 1)
+	assert.Equal(t, "node-2", body.Spec.Tasks[0].NodeID)
+	assert.Equal(t, map[string]int{"succeeded": 1, "running": 1, "pending": 1}, body.Spec.TaskCounts)
+	require.NotNil(t, body.Spec.TaskPage)
+	assert.Equal(t, httputil.ListMetadata{Total: 3, Limit: 1, Offset: 1}, *body.Spec.TaskPage)
+
+	resp = get("/power/v1/transitions/t1?taskLimit=zero")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
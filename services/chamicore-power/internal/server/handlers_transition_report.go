@@ -0,0 +1,372 @@
+package server
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+
+	// noErrorDetail groups failed tasks that recorded no error.
+	noErrorDetail = "(no error detail)"
+)
+
+// transitionTaskCSVHeader is the column order of CSV task exports.
+var transitionTaskCSVHeader = []string{
+	"nodeID",
+	"bmcID",
+	"endpoint",
+	"operation",
+	"state",
+	"dryRun",
+	"attemptCount",
+	"finalPowerState",
+	"escalated",
+	"queuedAt",
+	"startedAt",
+	"completedAt",
+	"durationSeconds",
+	"errorDetail",
+}
+
+type transitionReportSpec struct {
+	TransitionID string `json:"transitionID"`
+	Operation    string `json:"operation"`
+	State        string `json:"state"`
+	DryRun       bool   `json:"dryRun"`
+	TotalTasks   int    `json:"totalTasks"`
+	// SuccessRate is succeeded tasks over finished (succeeded, failed or
+	// canceled) tasks, between 0 and 1.
+	SuccessRate float64        `json:"successRate"`
+	TaskCounts  map[string]int `json:"taskCounts"`
+	// Latency covers tasks that have both started and completed.
+	Latency reportLatencySpec `json:"latency"`
+	// BMCFailures and FailuresByError group failed tasks; FailedNodes also
+	// lists canceled ones.
+	BMCFailures     []reportBMCFailureSpec `json:"bmcFailures"`
+	FailuresByError []reportErrorGroupSpec `json:"failuresByError"`
+	FailedNodes     []reportFailedNodeSpec `json:"failedNodes"`
+	QueuedAt        timeRFC3339            `json:"queuedAt"`
+	StartedAt       *timeRFC3339           `json:"startedAt,omitempty"`
+	CompletedAt     *timeRFC3339           `json:"completedAt,omitempty"`
+}
+
+type reportLatencySpec struct {
+	Count      int     `json:"count"`
+	MinSeconds float64 `json:"minSeconds"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+// reportBMCFailureSpec is one BMC with failed tasks; several failures behind
+// one BMC usually point at the BMC rather than the nodes.
+type reportBMCFailureSpec struct {
+	BMCID  string   `json:"bmcID"`
+	Failed int      `json:"failed"`
+	Total  int      `json:"total"`
+	Nodes  []string `json:"nodes"`
+}
+
+type reportErrorGroupSpec struct {
+	Error string   `json:"error"`
+	Count int      `json:"count"`
+	Nodes []string `json:"nodes"`
+}
+
+type reportFailedNodeSpec struct {
+	NodeID string `json:"nodeID"`
+	BMCID  string `json:"bmcID,omitempty"`
+	State  string `json:"state"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (s *Server) handleGetTransitionReport(w http.ResponseWriter, r *http.Request) {
+	transition, tasks, ok := s.loadTransitionForReport(w, r)
+	if !ok {
+		return
+	}
+
+	httputil.RespondJSON(w, http.StatusOK, httputil.Resource[transitionReportSpec]{
+		Kind:       "TransitionReport",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID:        strings.TrimSpace(transition.ID),
+			CreatedAt: transition.CreatedAt,
+			UpdatedAt: transition.UpdatedAt,
+		},
+		Spec: buildTransitionReport(transition, tasks),
+	})
+}
+
+func (s *Server) handleExportTransitionTasks(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatJSON {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "format must be %q or %q", exportFormatCSV, exportFormatJSON)
+		return
+	}
+
+	transition, tasks, ok := s.loadTransitionForReport(w, r)
+	if !ok {
+		return
+	}
+
+	if format == exportFormatJSON {
+		items := make([]httputil.Resource[transitionTaskSpec], 0, len(tasks))
+		for _, task := range tasks {
+			items = append(items, httputil.Resource[transitionTaskSpec]{
+				Kind:       "TransitionTask",
+				APIVersion: "power/v1",
+				Metadata:   httputil.Metadata{ID: strings.TrimSpace(task.ID)},
+				Spec:       toTransitionTaskSpec(task),
+			})
+		}
+		httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[transitionTaskSpec]{
+			Kind:       "TransitionTaskList",
+			APIVersion: "power/v1",
+			Metadata:   httputil.ListMetadata{Total: len(items), Limit: len(items)},
+			Items:      items,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", "transition-"+strings.TrimSpace(transition.ID)+"-tasks.csv"),
+	)
+	w.WriteHeader(http.StatusOK)
+	// The status is already sent, so a write error can only be dropped.
+	_ = writeTransitionTasksCSV(csv.NewWriter(w), tasks)
+}
+
+// loadTransitionForReport loads a transition and all of its tasks. On
+// failure it writes the problem response and returns false.
+func (s *Server) loadTransitionForReport(w http.ResponseWriter, r *http.Request) (engine.Transition, []engine.Task, bool) {
+	if s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return engine.Transition{}, nil, false
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if id == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "transition id is required")
+		return engine.Transition{}, nil, false
+	}
+
+	transition, tasks, err := s.loadTransition(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+			return engine.Transition{}, nil, false
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return engine.Transition{}, nil, false
+	}
+	return transition, tasks, true
+}
+
+// buildTransitionReport summarizes the tasks of a transition.
+func buildTransitionReport(transition engine.Transition, tasks []engine.Task) transitionReportSpec {
+	report := transitionReportSpec{
+		TransitionID:    strings.TrimSpace(transition.ID),
+		Operation:       strings.TrimSpace(transition.Operation),
+		State:           strings.TrimSpace(transition.State),
+		DryRun:          transition.DryRun,
+		TotalTasks:      len(tasks),
+		TaskCounts:      make(map[string]int),
+		BMCFailures:     []reportBMCFailureSpec{},
+		FailuresByError: []reportErrorGroupSpec{},
+		FailedNodes:     []reportFailedNodeSpec{},
+		QueuedAt:        newTimeRFC3339(transition.QueuedAt),
+		StartedAt:       toTimeRFC3339Ptr(transition.StartedAt),
+		CompletedAt:     toTimeRFC3339Ptr(transition.CompletedAt),
+	}
+
+	bmcTotals := make(map[string]int)
+	bmcFailures := make(map[string]*reportBMCFailureSpec)
+	errorGroups := make(map[string]*reportErrorGroupSpec)
+	durations := make([]time.Duration, 0, len(tasks))
+	for _, task := range tasks {
+		state := strings.TrimSpace(task.State)
+		nodeID := strings.TrimSpace(task.NodeID)
+		bmcID := strings.TrimSpace(task.BMCID)
+		report.TaskCounts[state]++
+		if bmcID != "" {
+			bmcTotals[bmcID]++
+		}
+		if duration, ok := taskDuration(task); ok {
+			durations = append(durations, duration)
+		}
+
+		if state != engine.TaskStateFailed && state != engine.TaskStateCanceled {
+			continue
+		}
+		detail := strings.TrimSpace(task.ErrorDetail)
+		report.FailedNodes = append(report.FailedNodes, reportFailedNodeSpec{
+			NodeID: nodeID,
+			BMCID:  bmcID,
+			State:  state,
+			Error:  detail,
+		})
+		// Canceled tasks were aborted rather than failed by the BMC, so
+		// they stay out of the failure groupings.
+		if state != engine.TaskStateFailed {
+			continue
+		}
+
+		if detail == "" {
+			detail = noErrorDetail
+		}
+		group, ok := errorGroups[detail]
+		if !ok {
+			group = &reportErrorGroupSpec{Error: detail}
+			errorGroups[detail] = group
+		}
+		group.Count++
+		group.Nodes = append(group.Nodes, nodeID)
+
+		if bmcID == "" {
+			continue
+		}
+		bmc, ok := bmcFailures[bmcID]
+		if !ok {
+			bmc = &reportBMCFailureSpec{BMCID: bmcID}
+			bmcFailures[bmcID] = bmc
+		}
+		bmc.Failed++
+		bmc.Nodes = append(bmc.Nodes, nodeID)
+	}
+
+	succeeded := report.TaskCounts[engine.TaskStateSucceeded]
+	finished := succeeded + report.TaskCounts[engine.TaskStateFailed] + report.TaskCounts[engine.TaskStateCanceled]
+	if finished > 0 {
+		report.SuccessRate = float64(succeeded) / float64(finished)
+	}
+	report.Latency = latencySummary(durations)
+
+	for bmcID, bmc := range bmcFailures {
+		bmc.Total = bmcTotals[bmcID]
+		sort.Strings(bmc.Nodes)
+		report.BMCFailures = append(report.BMCFailures, *bmc)
+	}
+	sort.Slice(report.BMCFailures, func(i, j int) bool {
+		if report.BMCFailures[i].Failed != report.BMCFailures[j].Failed {
+			return report.BMCFailures[i].Failed > report.BMCFailures[j].Failed
+		}
+		return report.BMCFailures[i].BMCID < report.BMCFailures[j].BMCID
+	})
+
+	for _, group := range errorGroups {
+		sort.Strings(group.Nodes)
+		report.FailuresByError = append(report.FailuresByError, *group)
+	}
+	sort.Slice(report.FailuresByError, func(i, j int) bool {
+		if report.FailuresByError[i].Count != report.FailuresByError[j].Count {
+			return report.FailuresByError[i].Count > report.FailuresByError[j].Count
+		}
+		return report.FailuresByError[i].Error < report.FailuresByError[j].Error
+	})
+
+	sort.Slice(report.FailedNodes, func(i, j int) bool {
+		return report.FailedNodes[i].NodeID < report.FailedNodes[j].NodeID
+	})
+	return report
+}
+
+func taskDuration(task engine.Task) (time.Duration, bool) {
+	if task.StartedAt == nil || task.CompletedAt == nil || task.CompletedAt.Before(*task.StartedAt) {
+		return 0, false
+	}
+	return task.CompletedAt.Sub(*task.StartedAt), true
+}
+
+// latencySummary reports nearest-rank percentiles of task durations.
+func latencySummary(durations []time.Duration) reportLatencySpec {
+	if len(durations) == 0 {
+		return reportLatencySpec{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(durations))))
+		return durations[max(rank, 1)-1].Seconds()
+	}
+	return reportLatencySpec{
+		Count:      len(durations),
+		MinSeconds: durations[0].Seconds(),
+		P50Seconds: percentile(50),
+		P90Seconds: percentile(90),
+		P99Seconds: percentile(99),
+		MaxSeconds: durations[len(durations)-1].Seconds(),
+	}
+}
+
+func writeTransitionTasksCSV(writer *csv.Writer, tasks []engine.Task) error {
+	if err := writer.Write(transitionTaskCSVHeader); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		duration := ""
+		if elapsed, ok := taskDuration(task); ok {
+			duration = strconv.FormatFloat(elapsed.Seconds(), 'f', 3, 64)
+		}
+		record := []string{
+			csvText(task.NodeID),
+			csvText(task.BMCID),
+			csvText(task.BMCEndpoint),
+			csvText(task.Operation),
+			csvText(task.State),
+			strconv.FormatBool(task.DryRun),
+			strconv.Itoa(task.AttemptCount),
+			csvText(task.FinalPowerState),
+			strconv.FormatBool(task.Escalated),
+			formatCSVTime(&task.QueuedAt),
+			formatCSVTime(task.StartedAt),
+			formatCSVTime(task.CompletedAt),
+			duration,
+			csvText(task.ErrorDetail),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText trims a free-text cell and prefixes it with a quote when it starts
+// with a character spreadsheets read as the start of a formula.
+func csvText(value string) string {
+	value = strings.TrimSpace(value)
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatCSVTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+)
+
+func reportTestStore() *mockPowerStore {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := func(node, bmc, state, detail string, seconds int) engine.Task {
+		task := engine.Task{NodeID: node, BMCID: bmc, Operation: "On", State: state, ErrorDetail: detail, QueuedAt: start}
+		if seconds > 0 {
+			startedAt := start
+			completedAt := start.Add(time.Duration(seconds) * time.Second)
+			task.StartedAt = &startedAt
+			task.CompletedAt = &completedAt
+		}
+		return task
+	}
+
+	return &mockPowerStore{
+		getTransitionFn: func(ctx context.Context, id string) (engine.Transition, error) {
+			return engine.Transition{ID: id, Operation: "On", State: engine.TransitionStatePartial, QueuedAt: start}, nil
+		},
+		listTransitionTasksFn: func(ctx context.Context, transitionID string) ([]engine.Task, error) {
+			return []engine.Task{
+				task("node-1", "bmc-a", engine.TaskStateSucceeded, "", 10),
+				task("node-2", "bmc-a", engine.TaskStateSucceeded, "", 20),
+				task("node-3", "bmc-b", engine.TaskStateFailed, "redfish: connection refused", 30),
+				task("node-4", "bmc-b", engine.TaskStateFailed, "redfish: connection refused", 40),
+				task("node-5", "bmc-c", engine.TaskStateFailed, "timed out, \"On\" not reached", 50),
+				task("node-6", "bmc-c", engine.TaskStateCanceled, "", 0),
+				task("node-7", "bmc-c", engine.TaskStatePending, "", 0),
+			}, nil
+		},
+	}
+}
+
+func TestGetTransitionReport(t *testing.T) {
+	srv := newHandlerTestServer(t, reportTestStore(), &mockTransitionRunner{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/transitions/tr-1/report", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var out httputil.Resource[transitionReportSpec]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	report := out.Spec
+	assert.Equal(t, "TransitionReport", out.Kind)
+	assert.Equal(t, 7, report.TotalTasks)
+	assert.InDelta(t, 2.0/6.0, report.SuccessRate, 1e-9)
+	assert.Equal(t, 3, report.TaskCounts[engine.TaskStateFailed])
+
+	assert.Equal(t, reportLatencySpec{
+		Count:      5,
+		MinSeconds: 10,
+		P50Seconds: 30,
+		P90Seconds: 50,
+		P99Seconds: 50,
+		MaxSeconds: 50,
+	}, report.Latency)
+
+	assert.Equal(t, []reportBMCFailureSpec{
+		{BMCID: "bmc-b", Failed: 2, Total: 2, Nodes: []string{"node-3", "node-4"}},
+		{BMCID: "bmc-c", Failed: 1, Total: 3, Nodes: []string{"node-5"}},
+	}, report.BMCFailures)
+	assert.Equal(t, []reportErrorGroupSpec{
+		{Error: "redfish: connection refused", Count: 2, Nodes: []string{"node-3", "node-4"}},
+		{Error: "timed out, \"On\" not reached", Count: 1, Nodes: []string{"node-5"}},
+	}, report.FailuresByError)
+	require.Len(t, report.FailedNodes, 4)
+	assert.Equal(t, reportFailedNodeSpec{NodeID: "node-6", BMCID: "bmc-c", State: engine.TaskStateCanceled}, report.FailedNodes[3])
+}
+
+func TestBuildTransitionReport_GroupsOnlyFailedTasks(t *testing.T) {
+	tasks := []engine.Task{
+		{NodeID: "node-1", BMCID: "bmc-a", State: engine.TaskStateFailed, ErrorDetail: "context canceled"},
+		{NodeID: "node-2", BMCID: "bmc-a", State: engine.TaskStateCanceled, ErrorDetail: "context canceled"},
+		{NodeID: "node-3", BMCID: "bmc-b", State: engine.TaskStateCanceled, ErrorDetail: "context canceled"},
+		{NodeID: "node-4", BMCID: "bmc-b", State: engine.TaskStateSucceeded},
+	}
+
+	report := buildTransitionReport(engine.Transition{ID: "tr-1", State: engine.TransitionStateCanceled}, tasks)
+
+	assert.Equal(t, []reportBMCFailureSpec{
+		{BMCID: "bmc-a", Failed: 1, Total: 2, Nodes: []string{"node-1"}},
+	}, report.BMCFailures)
+	assert.Equal(t, []reportErrorGroupSpec{
+		{Error: "context canceled", Count: 1, Nodes: []string{"node-1"}},
+	}, report.FailuresByError)
+	assert.Equal(t, []reportFailedNodeSpec{
+		{NodeID: "node-1", BMCID: "bmc-a", State: engine.TaskStateFailed, Error: "context canceled"},
+		{NodeID: "node-2", BMCID: "bmc-a", State: engine.TaskStateCanceled, Error: "context canceled"},
+		{NodeID: "node-3", BMCID: "bmc-b", State: engine.TaskStateCanceled, Error: "context canceled"},
+	}, report.FailedNodes)
+}
+
+func TestExportTransitionTasks(t *testing.T) {
+	srv := newHandlerTestServer(t, reportTestStore(), &mockTransitionRunner{}, nil)
+
+	t.Run("csv", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/power/v1/transitions/tr-1/export", nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), "transition-tr-1-tasks.csv")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 8)
+		assert.Equal(t, transitionTaskCSVHeader, records[0])
+		assert.Equal(t, []string{
+			"node-5", "bmc-c", "", "On", "failed", "false", "0", "", "false",
+			"2026-03-01T12:00:00Z", "2026-03-01T12:00:00Z", "2026-03-01T12:00:50Z", "50.000",
+			"timed out, \"On\" not reached",
+		}, records[5])
+		assert.Equal(t, "", records[7][12], "tasks that have not finished have no duration")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/power/v1/transitions/tr-1/export?format=JSON", nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var out httputil.ResourceList[transitionTaskSpec]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, 7, out.Metadata.Total)
+		require.Len(t, out.Items, 7)
+		assert.Equal(t, "node-3", out.Items[2].Spec.NodeID)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/power/v1/transitions/tr-1/export?format=xml", nil)
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		missing := newHandlerTestServer(t, &mockPowerStore{}, &mockTransitionRunner{}, nil)
+		req = httptest.NewRequest(http.MethodGet, "/power/v1/transitions/tr-404/report", nil)
+		resp = httptest.NewRecorder()
+		missing.Router().ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestWriteTransitionTasksCSV_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeTransitionTasksCSV(csv.NewWriter(&buf), []engine.Task{{
+		NodeID:      "=HYPERLINK(\"http://evil\")",
+		BMCID:       "@bmc",
+		Operation:   "On",
+		State:       engine.TaskStateFailed,
+		ErrorDetail: " -1+2",
+	}})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "'=HYPERLINK(\"http://evil\")", records[1][0])
+	assert.Equal(t, "'@bmc", records[1][1])
+	assert.Equal(t, "On", records[1][3])
+	assert.Equal(t, "'-1+2", records[1][13])
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+type transitionRetryRequest struct {
+	RequestID string `json:"requestID,omitempty"`
+	// IncludeCanceled also retries tasks that were canceled.
+	IncludeCanceled bool `json:"includeCanceled,omitempty"`
+	DryRun          bool `json:"dryRun,omitempty"`
+}
+
+func (s *Server) handleDeleteTransitionTask(w http.ResponseWriter, r *http.Request) {
+	if s.transitionRunner == nil || s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	nodeID := strings.TrimSpace(chi.URLParam(r, "nodeID"))
+	if id == "" || nodeID == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "transition id and node id are required")
+		return
+	}
+
+	abortErr := s.transitionRunner.AbortTask(r.Context(), id, nodeID)
+	switch {
+	case abortErr == nil:
+	case errors.Is(abortErr, engine.ErrTransitionNotFound), errors.Is(abortErr, engine.ErrTaskNotActive):
+		s.respondTaskNotActive(w, r, id, nodeID)
+		return
+	default:
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to abort task")
+		return
+	}
+
+	s.respondTransition(w, r, http.StatusAccepted, id)
+}
+
+// respondTaskNotActive explains why the runner had no active task to abort.
+func (s *Server) respondTaskNotActive(w http.ResponseWriter, r *http.Request, id, nodeID string) {
+	transition, tasks, err := s.loadTransition(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return
+	}
+
+	for _, task := range tasks {
+		if strings.TrimSpace(task.NodeID) != nodeID {
+			continue
+		}
+		if transition.State == engine.TransitionStatePendingApproval {
+			httputil.RespondProblemf(w, r, http.StatusConflict, "transition %q is pending approval; reject or cancel it instead", id)
+			return
+		}
+		httputil.RespondProblemf(w, r, http.StatusConflict, "task for node %q is %s", nodeID, task.State)
+		return
+	}
+	httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q has no task for node %q", id, nodeID)
+}
+
+func (s *Server) handleRetryTransition(w http.ResponseWriter, r *http.Request) {
+	if s.transitionRunner == nil || s.transitionStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if id == "" {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, "transition id is required")
+		return
+	}
+
+	var req transitionRetryRequest
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &req); err != nil {
+			httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+			return
+		}
+	}
+
+	parent, tasks, err := s.loadTransition(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
+		return
+	}
+	switch {
+	case parent.DryRun:
+		httputil.RespondProblemf(w, r, http.StatusConflict, "transition %q is a dry run", id)
+		return
+	case !isTerminalTransitionState(parent.State):
+		httputil.RespondProblemf(w, r, http.StatusConflict, "transition %q is still %s", id, parent.State)
+		return
+	}
+
+	retried := make([]engine.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.State == engine.TaskStateFailed || (req.IncludeCanceled && task.State == engine.TaskStateCanceled) {
+			retried = append(retried, task)
+		}
+	}
+	if len(retried) == 0 {
+		httputil.RespondProblemf(w, r, http.StatusConflict, "transition %q has no tasks to retry", id)
+		return
+	}
+
+	s.startTransition(w, r, retryTransitionRequest(parent, retried, req))
+}
+
+// retryTransitionRequest rebuilds the request of a parent transition for the
+// given subset of its tasks. Operation parameters are read back from the
+// task records, which carry them for every node.
+func retryTransitionRequest(parent engine.Transition, tasks []engine.Task, req transitionRetryRequest) transitionRequest {
+	nodes := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		nodes = append(nodes, task.NodeID)
+	}
+	first := tasks[0]
+
+	out := transitionRequest{
+		RequestID: strings.TrimSpace(req.RequestID),
+		Operation: parent.Operation,
+		Nodes:     nodes,
+		DryRun:    req.DryRun,
+		BatchSize: parent.BatchSize,
+		ParentID:  parent.ID,
+	}
+	if parent.EscalateAfter > 0 {
+		out.Escalation = &escalationRequest{AfterSeconds: int(parent.EscalateAfter.Seconds())}
+	}
+	if first.BootTarget != "" || first.BootWaitReady {
+		out.BootWait = &bootWaitRequest{Target: first.BootTarget, WaitForReady: first.BootWaitReady}
+	}
+
+	switch parent.Operation {
+	case engine.OperationPowerCap:
+		watts := 0
+		if first.PowerCapWatts != nil {
+			watts = *first.PowerCapWatts
+		}
+		out.PowerCapWatts = &watts
+	case engine.OperationSetBootOverride,
+		engine.OperationInsertMedia,
+		engine.OperationEjectMedia,
+		engine.OperationBootOverrideReset:
+		out.BootControl = true
+		out.ResetOperation = first.ResetOperation
+		if first.BootOverrideTarget != "" {
+			out.BootOverride = &engine.BootOverride{Target: first.BootOverrideTarget, Mode: first.BootOverrideMode}
+		}
+		if first.MediaImage != "" {
+			out.VirtualMedia = &engine.VirtualMedia{Image: first.MediaImage}
+		}
+	}
+	return out
+}
+
+func isTerminalTransitionState(state string) bool {
+	switch state {
+	case engine.TransitionStatePending, engine.TransitionStateRunning, engine.TransitionStatePendingApproval:
+		return false
+	default:
+		return true
+	}
+}
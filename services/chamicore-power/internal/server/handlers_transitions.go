@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -15,12 +17,18 @@ import (
 	"git.cscs.ch/openchami/chamicore-lib/httputil"
 	"git.cscs.ch/openchami/chamicore-lib/redfish"
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
 	"git.cscs.ch/openchami/chamicore-power/internal/store"
+	"git.cscs.ch/openchami/chamicore-power/pkg/hostlist"
 )
 
 const (
 	defaultTransitionListLimit = 100
 	maxTransitionListLimit     = 1000
+	defaultTransitionTaskLimit = 1000
+
+	groupOperationUnion        = "union"
+	groupOperationIntersection = "intersection"
 )
 
 var (
@@ -29,14 +37,76 @@ var (
 
 	errTransitionSubsystemUnavailable = errors.New("transition subsystem is not configured")
 	errGroupResolverUnavailable       = errors.New("group resolver is not configured")
+	errSelectorUnavailable            = errors.New("node selector is not configured")
+	errInvalidTargets                 = errors.New("invalid targets")
 )
 
 type transitionCreateRequest struct {
-	RequestID string   `json:"requestID,omitempty"`
-	Operation string   `json:"operation"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	RequestID string           `json:"requestID,omitempty"`
+	Operation string           `json:"operation"`
+	Nodes     []string         `json:"nodes,omitempty"`
+	Groups    []string         `json:"groups,omitempty"`
+	Selector  *selectorRequest `json:"selector,omitempty"`
+	// GroupOperation combines Groups as a "union" (default) or an
+	// "intersection" of their members.
+	GroupOperation string             `json:"groupOperation,omitempty"`
+	ExcludeNodes   []string           `json:"excludeNodes,omitempty"`
+	ExcludeGroups  []string           `json:"excludeGroups,omitempty"`
+	DryRun         bool               `json:"dryRun,omitempty"`
+	BootWait       *bootWaitRequest   `json:"bootWait,omitempty"`
+	BatchSize      int                `json:"batchSize,omitempty"`
+	Escalation     *escalationRequest `json:"escalation,omitempty"`
+}
+
+// targetQuery describes the nodes a request targets. Nodes, the selector
+// matches and the combined groups are unioned, then exclusions are removed.
+type targetQuery struct {
+	Nodes          []string
+	Groups         []string
+	GroupOperation string
+	Selector       model.NodeSelector
+	ExcludeNodes   []string
+	ExcludeGroups  []string
+}
+
+// targetResolution is the outcome of resolving a targetQuery.
+type targetResolution struct {
+	Nodes        []string
+	GroupMembers map[string][]string
+	// Exclusions is nil when the query excluded nothing.
+	Exclusions *engine.Exclusions
+}
+
+// selectorRequest selects SMD nodes by attribute in addition to the
+// explicit nodes and groups of a request.
+type selectorRequest struct {
+	Role    string `json:"role,omitempty"`
+	SubRole string `json:"subRole,omitempty"`
+	Arch    string `json:"arch,omitempty"`
+	State   string `json:"state,omitempty"`
+	Cabinet string `json:"cabinet,omitempty"`
+}
+
+func (r *selectorRequest) toModel() model.NodeSelector {
+	if r == nil {
+		return model.NodeSelector{}
+	}
+	return model.NodeSelector{
+		Role:    strings.TrimSpace(r.Role),
+		SubRole: strings.TrimSpace(r.SubRole),
+		Arch:    strings.TrimSpace(r.Arch),
+		State:   strings.TrimSpace(r.State),
+		Cabinet: strings.TrimSpace(r.Cabinet),
+	}
+}
+
+type escalationRequest struct {
+	AfterSeconds int `json:"afterSeconds"`
+}
+
+type bootWaitRequest struct {
+	Target       string `json:"target,omitempty"`
+	WaitForReady bool   `json:"waitForReady,omitempty"`
 }
 
 type transitionRequest struct {
@@ -44,37 +114,106 @@ type transitionRequest struct {
 	Operation string
 	Nodes     []string
 	Groups    []string
-	DryRun    bool
+	Selector  model.NodeSelector
+	// GroupOperation, ExcludeNodes and ExcludeGroups refine the targets;
+	// see targetQuery.
+	GroupOperation string
+	ExcludeNodes   []string
+	ExcludeGroups  []string
+	DryRun         bool
+	BootWait       *bootWaitRequest
+	// PowerCapWatts marks a power-cap transition and carries its limit.
+	PowerCapWatts *int
+	// BootControl marks a boot-override or virtual-media transition, whose
+	// Operation is an engine boot-control operation rather than a reset.
+	BootControl    bool
+	BootOverride   *engine.BootOverride
+	VirtualMedia   *engine.VirtualMedia
+	ResetOperation string
+	BatchSize      int
+	Escalation     *escalationRequest
+	// MaxNodes, when positive, rejects requests resolving to more nodes.
+	MaxNodes        int
+	TemplateName    string
+	TemplateVersion int
+	// ParentID is set when the request retries tasks of another transition.
+	ParentID string
+	// PCSOperation is the PCS operation name of requests made through the
+	// PCS compatibility API.
+	PCSOperation string
 }
 
 type transitionSpec struct {
-	RequestID    string               `json:"requestID,omitempty"`
-	Operation    string               `json:"operation"`
-	State        string               `json:"state"`
-	RequestedBy  string               `json:"requestedBy,omitempty"`
-	DryRun       bool                 `json:"dryRun"`
-	TargetCount  int                  `json:"targetCount"`
-	SuccessCount int                  `json:"successCount"`
-	FailureCount int                  `json:"failureCount"`
-	QueuedAt     timeRFC3339          `json:"queuedAt"`
-	StartedAt    *timeRFC3339         `json:"startedAt,omitempty"`
-	CompletedAt  *timeRFC3339         `json:"completedAt,omitempty"`
-	Tasks        []transitionTaskSpec `json:"tasks,omitempty"`
+	RequestID    string `json:"requestID,omitempty"`
+	Operation    string `json:"operation"`
+	State        string `json:"state"`
+	RequestedBy  string `json:"requestedBy,omitempty"`
+	DryRun       bool   `json:"dryRun"`
+	TargetCount  int    `json:"targetCount"`
+	SuccessCount int    `json:"successCount"`
+	FailureCount int    `json:"failureCount"`
+	BatchSize    int    `json:"batchSize,omitempty"`
+	// ChunkSize is set on transitions larger than the bulk limit, which run
+	// in chunks of that many tasks.
+	ChunkSize int `json:"chunkSize,omitempty"`
+	// EscalateAfterSeconds is set when graceful resets escalate to forced ones.
+	EscalateAfterSeconds int    `json:"escalateAfterSeconds,omitempty"`
+	TemplateName         string `json:"templateName,omitempty"`
+	TemplateVersion      int    `json:"templateVersion,omitempty"`
+	// ApprovalReason and ApprovalExpiresAt are set when the approval policy
+	// held the transition; Approvals is its decision trail.
+	ApprovalReason    string         `json:"approvalReason,omitempty"`
+	ApprovalExpiresAt *timeRFC3339   `json:"approvalExpiresAt,omitempty"`
+	Approvals         []approvalSpec `json:"approvals,omitempty"`
+	ParentID          string         `json:"parentID,omitempty"`
+	// ResolvedNodes lists every target node of a dry run.
+	ResolvedNodes []string `json:"resolvedNodes,omitempty"`
+	// Exclusions records the requested exclusions and the nodes they removed.
+	Exclusions *exclusionsSpec `json:"exclusions,omitempty"`
+	// TaskCounts aggregates all tasks by state; Tasks holds the page
+	// described by TaskPage.
+	TaskCounts  map[string]int         `json:"taskCounts,omitempty"`
+	TaskPage    *httputil.ListMetadata `json:"taskPage,omitempty"`
+	QueuedAt    timeRFC3339            `json:"queuedAt"`
+	StartedAt   *timeRFC3339           `json:"startedAt,omitempty"`
+	CompletedAt *timeRFC3339           `json:"completedAt,omitempty"`
+	Tasks       []transitionTaskSpec   `json:"tasks,omitempty"`
 }
 
 type transitionTaskSpec struct {
-	NodeID          string       `json:"nodeID"`
-	BMCID           string       `json:"bmcID,omitempty"`
-	Endpoint        string       `json:"endpoint,omitempty"`
-	Operation       string       `json:"operation"`
-	State           string       `json:"state"`
-	DryRun          bool         `json:"dryRun"`
-	AttemptCount    int          `json:"attemptCount"`
-	FinalPowerState string       `json:"finalPowerState,omitempty"`
-	ErrorDetail     string       `json:"errorDetail,omitempty"`
-	QueuedAt        timeRFC3339  `json:"queuedAt"`
-	StartedAt       *timeRFC3339 `json:"startedAt,omitempty"`
-	CompletedAt     *timeRFC3339 `json:"completedAt,omitempty"`
+	NodeID             string          `json:"nodeID"`
+	BMCID              string          `json:"bmcID,omitempty"`
+	Endpoint           string          `json:"endpoint,omitempty"`
+	Operation          string          `json:"operation"`
+	State              string          `json:"state"`
+	DryRun             bool            `json:"dryRun"`
+	AttemptCount       int             `json:"attemptCount"`
+	FinalPowerState    string          `json:"finalPowerState,omitempty"`
+	ErrorDetail        string          `json:"errorDetail,omitempty"`
+	BootTarget         string          `json:"bootTarget,omitempty"`
+	BootWaitReady      bool            `json:"bootWaitReady,omitempty"`
+	BootStage          string          `json:"bootStage,omitempty"`
+	BootStages         []bootStageSpec `json:"bootStages,omitempty"`
+	PowerCapWatts      *int            `json:"powerCapWatts,omitempty"`
+	BootOverrideTarget string          `json:"bootOverrideTarget,omitempty"`
+	BootOverrideMode   string          `json:"bootOverrideMode,omitempty"`
+	MediaImage         string          `json:"mediaImage,omitempty"`
+	ResetOperation     string          `json:"resetOperation,omitempty"`
+	Escalated          bool            `json:"escalated,omitempty"`
+	QueuedAt           timeRFC3339     `json:"queuedAt"`
+	StartedAt          *timeRFC3339    `json:"startedAt,omitempty"`
+	CompletedAt        *timeRFC3339    `json:"completedAt,omitempty"`
+}
+
+type exclusionsSpec struct {
+	Nodes    []string `json:"nodes,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Excluded []string `json:"excluded,omitempty"`
+}
+
+type bootStageSpec struct {
+	Stage      string      `json:"stage"`
+	ObservedAt timeRFC3339 `json:"observedAt"`
 }
 
 func (s *Server) handleListTransitions(w http.ResponseWriter, r *http.Request) {
@@ -120,11 +259,18 @@ func (s *Server) handleCreateTransition(w http.ResponseWriter, r *http.Request)
 	}
 
 	s.startTransition(w, r, transitionRequest{
-		RequestID: strings.TrimSpace(req.RequestID),
-		Operation: strings.TrimSpace(req.Operation),
-		Nodes:     req.Nodes,
-		Groups:    req.Groups,
-		DryRun:    req.DryRun,
+		RequestID:      strings.TrimSpace(req.RequestID),
+		Operation:      strings.TrimSpace(req.Operation),
+		Nodes:          req.Nodes,
+		Groups:         req.Groups,
+		Selector:       req.Selector.toModel(),
+		GroupOperation: req.GroupOperation,
+		ExcludeNodes:   req.ExcludeNodes,
+		ExcludeGroups:  req.ExcludeGroups,
+		DryRun:         req.DryRun,
+		BootWait:       req.BootWait,
+		BatchSize:      req.BatchSize,
+		Escalation:     req.Escalation,
 	})
 }
 
@@ -140,17 +286,7 @@ func (s *Server) handleGetTransition(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	transition, tasks, err := s.loadTransition(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, store.ErrNotFound) {
-			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
-			return
-		}
-		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
-		return
-	}
-
-	httputil.RespondJSON(w, http.StatusOK, toTransitionResource(transition, tasks))
+	s.respondTransition(w, r, http.StatusOK, id)
 }
 
 func (s *Server) handleDeleteTransition(w http.ResponseWriter, r *http.Request) {
@@ -165,79 +301,201 @@ func (s *Server) handleDeleteTransition(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	abortErr := s.transitionRunner.AbortTransition(r.Context(), id)
-	if abortErr != nil && !errors.Is(abortErr, engine.ErrTransitionNotFound) {
+	if err := s.cancelTransition(r, id); err != nil {
 		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to abort transition")
 		return
 	}
 
-	transition, tasks, err := s.loadTransition(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, store.ErrNotFound) {
-			httputil.RespondProblemf(w, r, http.StatusNotFound, "transition %q not found", id)
+	s.respondTransition(w, r, http.StatusAccepted, id)
+}
+
+// cancelTransition aborts a running transition or cancels one waiting for
+// approval. Transitions that are neither are left alone.
+func (s *Server) cancelTransition(r *http.Request, id string) error {
+	err := s.transitionRunner.AbortTransition(r.Context(), id)
+	if !errors.Is(err, engine.ErrTransitionNotFound) {
+		return err
+	}
+
+	// Not running; it may still be waiting for approval.
+	_, err = s.transitionRunner.DecideApproval(r.Context(), engine.ApprovalDecision{
+		TransitionID: id,
+		Action:       engine.ApprovalActionCanceled,
+		Principal:    requestedByFromContext(r),
+	})
+	if errors.Is(err, engine.ErrApprovalNotPending) ||
+		errors.Is(err, engine.ErrApprovalUnsupported) ||
+		errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) startTransition(w http.ResponseWriter, r *http.Request, req transitionRequest) {
+	transition, ok := s.launchTransition(w, r, req)
+	if !ok {
+		return
+	}
+
+	resource := toTransitionResource(transition, nil)
+	if s.transitionStore != nil {
+		var err error
+		resource, err = s.transitionResourcePage(r.Context(), transition, defaultTransitionTaskLimit, 0)
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition tasks")
 			return
 		}
-		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition")
-		return
+		if transition.DryRun {
+			resource.Spec.ResolvedNodes, err = s.transitionNodes(r.Context(), transition.ID)
+			if err != nil {
+				httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition tasks")
+				return
+			}
+		}
 	}
 
-	httputil.RespondJSON(w, http.StatusAccepted, toTransitionResource(transition, tasks))
+	w.Header().Set("Location", fmt.Sprintf("/power/v1/transitions/%s", transition.ID))
+	httputil.RespondJSON(w, http.StatusAccepted, resource)
 }
 
-func (s *Server) startTransition(w http.ResponseWriter, r *http.Request, req transitionRequest) {
+// launchTransition validates and starts a transition request. On failure it
+// writes the problem response and returns false.
+func (s *Server) launchTransition(w http.ResponseWriter, r *http.Request, req transitionRequest) (engine.Transition, bool) {
 	if s.transitionRunner == nil {
 		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errTransitionSubsystemUnavailable.Error())
-		return
+		return engine.Transition{}, false
 	}
 
-	operation, err := redfish.ParseResetOperation(req.Operation)
-	if err != nil {
-		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid operation %q", req.Operation)
-		return
+	operation := req.Operation
+	switch {
+	case req.PowerCapWatts != nil:
+		operation = engine.OperationPowerCap
+	case req.BootControl:
+	default:
+		parsed, err := redfish.ParseResetOperation(req.Operation)
+		if err != nil {
+			httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid operation %q", req.Operation)
+			return engine.Transition{}, false
+		}
+		operation = string(parsed)
+	}
+	if !s.checkActiveQuota(w, r) {
+		return engine.Transition{}, false
 	}
 
-	nodeIDs, err := s.resolveTargets(r.Context(), req.Nodes, req.Groups)
+	targets, err := s.resolveTargetQuery(r.Context(), targetQuery{
+		Nodes:          req.Nodes,
+		Groups:         req.Groups,
+		GroupOperation: req.GroupOperation,
+		Selector:       req.Selector,
+		ExcludeNodes:   req.ExcludeNodes,
+		ExcludeGroups:  req.ExcludeGroups,
+	})
 	if err != nil {
 		s.respondTargetResolutionError(w, r, err)
-		return
+		return engine.Transition{}, false
 	}
-	if len(nodeIDs) > s.cfg.BulkMaxNodes {
+	nodeIDs := targets.Nodes
+	hardMaxNodes := max(s.cfg.BulkHardMaxNodes, s.cfg.BulkMaxNodes)
+	if len(nodeIDs) > hardMaxNodes {
 		httputil.RespondProblemf(
 			w,
 			r,
 			http.StatusBadRequest,
 			"too many target nodes: got %d, max %d",
 			len(nodeIDs),
-			s.cfg.BulkMaxNodes,
+			hardMaxNodes,
 		)
-		return
+		return engine.Transition{}, false
+	}
+	chunkSize := 0
+	if len(nodeIDs) > s.cfg.BulkMaxNodes {
+		chunkSize = s.cfg.BulkMaxNodes
+	}
+	if req.MaxNodes > 0 && len(nodeIDs) > req.MaxNodes {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"too many target nodes for template %q: got %d, max %d",
+			req.TemplateName,
+			len(nodeIDs),
+			req.MaxNodes,
+		)
+		return engine.Transition{}, false
+	}
+
+	approval, err := s.approvalRequirement(r.Context(), operation, nodeIDs, req)
+	if err != nil {
+		s.respondTargetResolutionError(w, r, err)
+		return engine.Transition{}, false
 	}
 
 	startReq := engine.StartRequest{
-		RequestID:   resolvedRequestID(r, req.RequestID),
-		RequestedBy: requestedByFromContext(r),
-		Operation:   string(operation),
-		NodeIDs:     nodeIDs,
-		DryRun:      req.DryRun,
+		RequestID:       resolvedRequestID(r, req.RequestID),
+		RequestedBy:     requestedByFromContext(r),
+		Operation:       operation,
+		NodeIDs:         nodeIDs,
+		DryRun:          req.DryRun,
+		PowerCapWatts:   req.PowerCapWatts,
+		BootOverride:    req.BootOverride,
+		VirtualMedia:    req.VirtualMedia,
+		ResetOperation:  req.ResetOperation,
+		BatchSize:       req.BatchSize,
+		ChunkSize:       chunkSize,
+		TemplateName:    req.TemplateName,
+		TemplateVersion: req.TemplateVersion,
+		Approval:        approval,
+		ParentID:        req.ParentID,
+		Exclusions:      targets.Exclusions,
+		PCSOperation:    req.PCSOperation,
+	}
+	if req.Escalation != nil {
+		startReq.Escalation = &engine.Escalation{After: time.Duration(req.Escalation.AfterSeconds) * time.Second}
+	}
+	if req.BootWait != nil {
+		startReq.BootWait = &engine.BootWait{
+			Target:       strings.TrimSpace(req.BootWait.Target),
+			WaitForReady: req.BootWait.WaitForReady,
+		}
 	}
 
 	transition, err := s.transitionRunner.StartTransition(r.Context(), startReq)
 	if err != nil {
 		s.respondStartTransitionError(w, r, err)
-		return
+		return engine.Transition{}, false
 	}
+	return transition, true
+}
 
-	var tasks []engine.Task
-	if s.transitionStore != nil {
-		tasks, err = s.transitionStore.ListTransitionTasks(r.Context(), transition.ID)
-		if err != nil {
-			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load transition tasks")
-			return
-		}
+// transitionResourcePage builds the resource of a transition with one page
+// of its tasks and the task counts of the whole transition.
+func (s *Server) transitionResourcePage(
+	ctx context.Context,
+	transition engine.Transition,
+	limit int,
+	offset int,
+) (httputil.Resource[transitionSpec], error) {
+	counts, err := s.transitionStore.CountTransitionTasksByState(ctx, transition.ID)
+	if err != nil {
+		return httputil.Resource[transitionSpec]{}, err
+	}
+	tasks, err := s.transitionStore.ListTransitionTasksPage(ctx, transition.ID, limit, offset)
+	if err != nil {
+		return httputil.Resource[transitionSpec]{}, err
 	}
 
-	w.Header().Set("Location", fmt.Sprintf("/power/v1/transitions/%s", transition.ID))
-	httputil.RespondJSON(w, http.StatusAccepted, toTransitionResource(transition, tasks))
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	resource := toTransitionResource(transition, tasks)
+	if total > 0 {
+		resource.Spec.TaskCounts = counts
+	}
+	resource.Spec.TaskPage = &httputil.ListMetadata{Total: total, Limit: limit, Offset: offset}
+	return resource, nil
 }
 
 func (s *Server) loadTransition(ctx context.Context, id string) (engine.Transition, []engine.Task, error) {
@@ -254,51 +512,219 @@ func (s *Server) loadTransition(ctx context.Context, id string) (engine.Transiti
 	return transition, tasks, nil
 }
 
+// transitionNodes returns the sorted node IDs of all tasks of a transition.
+func (s *Server) transitionNodes(ctx context.Context, id string) ([]string, error) {
+	tasks, err := s.transitionStore.ListTransitionTasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		nodes = append(nodes, task.NodeID)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
 func (s *Server) resolveTargets(ctx context.Context, nodes, groups []string) ([]string, error) {
-	unique := make(map[string]struct{})
-	resolved := make([]string, 0, len(nodes))
+	resolution, err := s.resolveTargetQuery(ctx, targetQuery{Nodes: nodes, Groups: groups})
+	return resolution.Nodes, err
+}
 
-	for _, node := range parseTargetList(nodes) {
+// resolveTargetQuery expands a targetQuery into sorted node IDs. Node entries
+// may use hostlist range syntax such as x1000c[0-3]s0b0n[0-1]. Exclusions
+// are applied last, so they also drop nodes contributed by groups and the
+// selector.
+func (s *Server) resolveTargetQuery(ctx context.Context, query targetQuery) (targetResolution, error) {
+	unique := make(map[string]struct{})
+	resolved := make([]string, 0, len(query.Nodes))
+	add := func(node string) {
 		if _, exists := unique[node]; exists {
-			continue
+			return
 		}
 		unique[node] = struct{}{}
 		resolved = append(resolved, node)
 	}
 
-	groupNames := parseTargetList(groups)
+	nodes, err := s.expandTargetNodes(query.Nodes)
+	if err != nil {
+		return targetResolution{}, err
+	}
+	for _, node := range nodes {
+		add(node)
+	}
+
+	if !query.Selector.IsZero() {
+		if s.selectNodes == nil {
+			return targetResolution{}, errSelectorUnavailable
+		}
+		selected, selectErr := s.selectNodes(ctx, query.Selector)
+		if selectErr != nil {
+			return targetResolution{}, fmt.Errorf("resolving selector: %w", selectErr)
+		}
+		for _, node := range parseTargetList(selected) {
+			add(node)
+		}
+	}
+
+	groupNames := parseTargetList(query.Groups)
+	groupMembers, err := s.resolveGroups(ctx, groupNames)
+	if err != nil {
+		return targetResolution{}, err
+	}
+	switch strings.ToLower(strings.TrimSpace(query.GroupOperation)) {
+	case "", groupOperationUnion:
+		for _, group := range groupNames {
+			for _, member := range groupMembers[group] {
+				add(member)
+			}
+		}
+	case groupOperationIntersection:
+		for _, member := range intersectGroups(groupNames, groupMembers) {
+			add(member)
+		}
+	default:
+		return targetResolution{}, fmt.Errorf(
+			"%w: groupOperation must be %q or %q",
+			errInvalidTargets,
+			groupOperationUnion,
+			groupOperationIntersection,
+		)
+	}
+
+	resolved, exclusions, err := s.applyExclusions(ctx, resolved, query.ExcludeNodes, query.ExcludeGroups)
+	if err != nil {
+		return targetResolution{}, err
+	}
+
+	sort.Strings(resolved)
+	if len(resolved) == 0 {
+		return targetResolution{}, engine.ErrNoTargetNodes
+	}
+
+	return targetResolution{Nodes: resolved, GroupMembers: groupMembers, Exclusions: exclusions}, nil
+}
+
+// resolveGroups returns the members of each named SMD group.
+func (s *Server) resolveGroups(ctx context.Context, groupNames []string) (map[string][]string, error) {
 	if len(groupNames) > 0 && s.resolveGroupMembers == nil {
 		return nil, errGroupResolverUnavailable
 	}
 
+	groupMembers := make(map[string][]string, len(groupNames))
 	for _, group := range groupNames {
 		members, err := s.resolveGroupMembers(ctx, group)
 		if err != nil {
 			return nil, fmt.Errorf("resolving group %q: %w", group, err)
 		}
-		for _, member := range parseTargetList(members) {
-			if _, exists := unique[member]; exists {
+		groupMembers[group] = parseTargetList(members)
+	}
+	return groupMembers, nil
+}
+
+// applyExclusions drops excluded nodes and the members of excluded groups
+// from resolved. It returns nil exclusions when none were requested.
+func (s *Server) applyExclusions(
+	ctx context.Context,
+	resolved []string,
+	excludeNodes, excludeGroups []string,
+) ([]string, *engine.Exclusions, error) {
+	nodes, err := s.expandTargetNodes(excludeNodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	groupNames := parseTargetList(excludeGroups)
+	if len(nodes) == 0 && len(groupNames) == 0 {
+		return resolved, nil, nil
+	}
+
+	groupMembers, err := s.resolveGroups(ctx, groupNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	excluded := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		excluded[node] = struct{}{}
+	}
+	for _, members := range groupMembers {
+		for _, member := range members {
+			excluded[member] = struct{}{}
+		}
+	}
+
+	exclusions := &engine.Exclusions{Nodes: nodes, Groups: groupNames}
+	kept := resolved[:0]
+	for _, node := range resolved {
+		if _, drop := excluded[node]; drop {
+			exclusions.Excluded = append(exclusions.Excluded, node)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	sort.Strings(exclusions.Excluded)
+	return kept, exclusions, nil
+}
+
+// intersectGroups returns the nodes that are members of every group.
+func intersectGroups(groupNames []string, groupMembers map[string][]string) []string {
+	if len(groupNames) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, group := range groupNames {
+		seen := make(map[string]struct{}, len(groupMembers[group]))
+		for _, member := range groupMembers[group] {
+			if _, dup := seen[member]; dup {
 				continue
 			}
-			unique[member] = struct{}{}
-			resolved = append(resolved, member)
+			seen[member] = struct{}{}
+			counts[member]++
 		}
 	}
 
-	sort.Strings(resolved)
-	if len(resolved) == 0 {
-		return nil, engine.ErrNoTargetNodes
+	distinct := make(map[string]struct{}, len(groupNames))
+	for _, group := range groupNames {
+		distinct[group] = struct{}{}
 	}
+	members := make([]string, 0)
+	for member, count := range counts {
+		if count == len(distinct) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
 
-	return resolved, nil
+// expandTargetNodes splits node entries and expands their hostlist ranges.
+// All entries share one budget of the hard node limit, so a request cannot
+// make the server build more names than it would ever accept.
+func (s *Server) expandTargetNodes(entries []string) ([]string, error) {
+	limit := max(s.cfg.BulkHardMaxNodes, s.cfg.BulkMaxNodes)
+	nodes := make([]string, 0, len(entries))
+	for _, entry := range parseTargetList(entries) {
+		expanded, err := hostlist.ExpandLimit(entry, limit-len(nodes))
+		if errors.Is(err, hostlist.ErrTooManyHosts) {
+			return nil, fmt.Errorf("%w: too many target nodes, max %d", errInvalidTargets, limit)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidTargets, err)
+		}
+		nodes = append(nodes, expanded...)
+	}
+	return nodes, nil
 }
 
 func (s *Server) respondTargetResolutionError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case errors.Is(err, engine.ErrNoTargetNodes):
 		httputil.RespondProblem(w, r, http.StatusBadRequest, engine.ErrNoTargetNodes.Error())
+	case errors.Is(err, errInvalidTargets):
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
 	case errors.Is(err, errGroupResolverUnavailable):
 		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errGroupResolverUnavailable.Error())
+	case errors.Is(err, errSelectorUnavailable):
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errSelectorUnavailable.Error())
 	case errors.Is(err, ErrGroupNotFound):
 		httputil.RespondProblem(w, r, http.StatusNotFound, err.Error())
 	default:
@@ -307,11 +733,24 @@ func (s *Server) respondTargetResolutionError(w http.ResponseWriter, r *http.Req
 }
 
 func (s *Server) respondStartTransitionError(w http.ResponseWriter, r *http.Request, err error) {
+	var queueFull *engine.QueueFullError
 	switch {
+	case errors.As(err, &queueFull):
+		respondRetryAfter(w, r, http.StatusServiceUnavailable, queueFull.RetryAfter, "%s", queueFull.Error())
 	case errors.Is(err, engine.ErrRunnerNotStarted):
 		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, engine.ErrRunnerNotStarted.Error())
 	case errors.Is(err, engine.ErrNoTargetNodes):
 		httputil.RespondProblem(w, r, http.StatusBadRequest, engine.ErrNoTargetNodes.Error())
+	case errors.Is(err, engine.ErrInvalidBootWait),
+		errors.Is(err, engine.ErrInvalidPowerCap),
+		errors.Is(err, engine.ErrInvalidBootOverride),
+		errors.Is(err, engine.ErrInvalidVirtualMedia),
+		errors.Is(err, engine.ErrInvalidBatchSize),
+		errors.Is(err, engine.ErrInvalidChunkSize),
+		errors.Is(err, engine.ErrInvalidEscalation):
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, engine.ErrPowerCapUnsupported), errors.Is(err, engine.ErrBootControlUnsupported):
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, err.Error())
 	default:
 		httputil.RespondProblemf(w, r, http.StatusInternalServerError, "failed to create transition: %v", err)
 	}
@@ -320,20 +759,7 @@ func (s *Server) respondStartTransitionError(w http.ResponseWriter, r *http.Requ
 func toTransitionResource(transition engine.Transition, tasks []engine.Task) httputil.Resource[transitionSpec] {
 	taskSpecs := make([]transitionTaskSpec, 0, len(tasks))
 	for _, task := range tasks {
-		taskSpecs = append(taskSpecs, transitionTaskSpec{
-			NodeID:          strings.TrimSpace(task.NodeID),
-			BMCID:           strings.TrimSpace(task.BMCID),
-			Endpoint:        strings.TrimSpace(task.BMCEndpoint),
-			Operation:       strings.TrimSpace(task.Operation),
-			State:           strings.TrimSpace(task.State),
-			DryRun:          task.DryRun,
-			AttemptCount:    task.AttemptCount,
-			FinalPowerState: strings.TrimSpace(task.FinalPowerState),
-			ErrorDetail:     strings.TrimSpace(task.ErrorDetail),
-			QueuedAt:        newTimeRFC3339(task.QueuedAt),
-			StartedAt:       toTimeRFC3339Ptr(task.StartedAt),
-			CompletedAt:     toTimeRFC3339Ptr(task.CompletedAt),
-		})
+		taskSpecs = append(taskSpecs, toTransitionTaskSpec(task))
 	}
 
 	return httputil.Resource[transitionSpec]{
@@ -345,44 +771,130 @@ func toTransitionResource(transition engine.Transition, tasks []engine.Task) htt
 			UpdatedAt: transition.UpdatedAt,
 		},
 		Spec: transitionSpec{
-			RequestID:    strings.TrimSpace(transition.RequestID),
-			Operation:    strings.TrimSpace(transition.Operation),
-			State:        strings.TrimSpace(transition.State),
-			RequestedBy:  strings.TrimSpace(transition.RequestedBy),
-			DryRun:       transition.DryRun,
-			TargetCount:  transition.TargetCount,
-			SuccessCount: transition.SuccessCount,
-			FailureCount: transition.FailureCount,
-			QueuedAt:     newTimeRFC3339(transition.QueuedAt),
-			StartedAt:    toTimeRFC3339Ptr(transition.StartedAt),
-			CompletedAt:  toTimeRFC3339Ptr(transition.CompletedAt),
-			Tasks:        taskSpecs,
+			RequestID:            strings.TrimSpace(transition.RequestID),
+			Operation:            strings.TrimSpace(transition.Operation),
+			State:                strings.TrimSpace(transition.State),
+			RequestedBy:          strings.TrimSpace(transition.RequestedBy),
+			DryRun:               transition.DryRun,
+			TargetCount:          transition.TargetCount,
+			SuccessCount:         transition.SuccessCount,
+			FailureCount:         transition.FailureCount,
+			BatchSize:            transition.BatchSize,
+			ChunkSize:            transition.ChunkSize,
+			EscalateAfterSeconds: int(transition.EscalateAfter / time.Second),
+			TemplateName:         strings.TrimSpace(transition.TemplateName),
+			TemplateVersion:      transition.TemplateVersion,
+			ApprovalReason:       strings.TrimSpace(transition.ApprovalReason),
+			ApprovalExpiresAt:    toTimeRFC3339Ptr(transition.ApprovalExpiresAt),
+			ParentID:             strings.TrimSpace(transition.ParentID),
+			Exclusions:           toExclusionsSpec(transition.Exclusions),
+			QueuedAt:             newTimeRFC3339(transition.QueuedAt),
+			StartedAt:            toTimeRFC3339Ptr(transition.StartedAt),
+			CompletedAt:          toTimeRFC3339Ptr(transition.CompletedAt),
+			Tasks:                taskSpecs,
 		},
 	}
 }
 
+func toTransitionTaskSpec(task engine.Task) transitionTaskSpec {
+	return transitionTaskSpec{
+		NodeID:             strings.TrimSpace(task.NodeID),
+		BMCID:              strings.TrimSpace(task.BMCID),
+		Endpoint:           strings.TrimSpace(task.BMCEndpoint),
+		Operation:          strings.TrimSpace(task.Operation),
+		State:              strings.TrimSpace(task.State),
+		DryRun:             task.DryRun,
+		AttemptCount:       task.AttemptCount,
+		FinalPowerState:    strings.TrimSpace(task.FinalPowerState),
+		ErrorDetail:        strings.TrimSpace(task.ErrorDetail),
+		BootTarget:         strings.TrimSpace(task.BootTarget),
+		BootWaitReady:      task.BootWaitReady,
+		BootStage:          strings.TrimSpace(task.BootStage),
+		BootStages:         toBootStageSpecs(task.BootStages),
+		PowerCapWatts:      task.PowerCapWatts,
+		BootOverrideTarget: strings.TrimSpace(task.BootOverrideTarget),
+		BootOverrideMode:   strings.TrimSpace(task.BootOverrideMode),
+		MediaImage:         strings.TrimSpace(task.MediaImage),
+		ResetOperation:     strings.TrimSpace(task.ResetOperation),
+		Escalated:          task.Escalated,
+		QueuedAt:           newTimeRFC3339(task.QueuedAt),
+		StartedAt:          toTimeRFC3339Ptr(task.StartedAt),
+		CompletedAt:        toTimeRFC3339Ptr(task.CompletedAt),
+	}
+}
+
+func toExclusionsSpec(exclusions *engine.Exclusions) *exclusionsSpec {
+	if exclusions == nil {
+		return nil
+	}
+	return &exclusionsSpec{
+		Nodes:    exclusions.Nodes,
+		Groups:   exclusions.Groups,
+		Excluded: exclusions.Excluded,
+	}
+}
+
+func toBootStageSpecs(stages []engine.BootStage) []bootStageSpec {
+	if len(stages) == 0 {
+		return nil
+	}
+	specs := make([]bootStageSpec, 0, len(stages))
+	for _, stage := range stages {
+		specs = append(specs, bootStageSpec{
+			Stage:      strings.TrimSpace(stage.Stage),
+			ObservedAt: newTimeRFC3339(stage.ObservedAt),
+		})
+	}
+	return specs
+}
+
+// parseTargetList splits comma-separated items, keeping commas inside
+// hostlist brackets such as nid[001,003] together.
 func parseTargetList(items []string) []string {
 	result := make([]string, 0, len(items))
+	appendTarget := func(piece string) {
+		if normalized := strings.TrimSpace(piece); normalized != "" {
+			result = append(result, normalized)
+		}
+	}
 	for _, raw := range items {
-		for _, piece := range strings.Split(raw, ",") {
-			normalized := strings.TrimSpace(piece)
-			if normalized == "" {
-				continue
+		depth := 0
+		start := 0
+		for i := 0; i < len(raw); i++ {
+			switch raw[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			case ',':
+				if depth == 0 {
+					appendTarget(raw[start:i])
+					start = i + 1
+				}
 			}
-			result = append(result, normalized)
 		}
+		appendTarget(raw[start:])
 	}
 	return result
 }
 
 func parseListPagination(r *http.Request) (int, int, error) {
-	limit := defaultTransitionListLimit
+	return parsePagination(r, "limit", "offset", defaultTransitionListLimit)
+}
+
+// parseTaskPagination reads the task page of a single transition.
+func parseTaskPagination(r *http.Request) (int, int, error) {
+	return parsePagination(r, "taskLimit", "taskOffset", defaultTransitionTaskLimit)
+}
+
+func parsePagination(r *http.Request, limitParam, offsetParam string, defaultLimit int) (int, int, error) {
+	limit := defaultLimit
 	offset := 0
 
-	if value := strings.TrimSpace(r.URL.Query().Get("limit")); value != "" {
+	if value := strings.TrimSpace(r.URL.Query().Get(limitParam)); value != "" {
 		parsed, err := strconv.Atoi(value)
 		if err != nil || parsed <= 0 {
-			return 0, 0, fmt.Errorf("invalid limit value %q", value)
+			return 0, 0, fmt.Errorf("invalid %s value %q", limitParam, value)
 		}
 		if parsed > maxTransitionListLimit {
 			parsed = maxTransitionListLimit
@@ -390,10 +902,10 @@ func parseListPagination(r *http.Request) (int, int, error) {
 		limit = parsed
 	}
 
-	if value := strings.TrimSpace(r.URL.Query().Get("offset")); value != "" {
+	if value := strings.TrimSpace(r.URL.Query().Get(offsetParam)); value != "" {
 		parsed, err := strconv.Atoi(value)
 		if err != nil || parsed < 0 {
-			return 0, 0, fmt.Errorf("invalid offset value %q", value)
+			return 0, 0, fmt.Errorf("invalid %s value %q", offsetParam, value)
 		}
 		offset = parsed
 	}
@@ -423,6 +935,21 @@ func resolvedRequestID(r *http.Request, requested string) string {
 	return ""
 }
 
+// respondRetryAfter writes a problem response with a Retry-After header of
+// at least one second.
+func respondRetryAfter(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	retryAfter time.Duration,
+	format string,
+	args ...any,
+) {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	httputil.RespondProblemf(w, r, status, format, args...)
+}
+
 func requireAnyScope(scopes ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
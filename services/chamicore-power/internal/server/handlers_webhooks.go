@@ -0,0 +1,406 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+const minWebhookSecretLength = 16
+
+var (
+	errWebhookStoreUnavailable = errors.New("webhook store is not configured")
+
+	webhookEventTypes = []string{
+		types.EventTypeTransitionLifecycle,
+		types.EventTypeTransitionTaskResult,
+		types.EventTypeTransitionApproval,
+	}
+	webhookDeliveryStates = []string{
+		model.WebhookDeliveryPending,
+		model.WebhookDeliveryDelivered,
+		model.WebhookDeliveryDead,
+	}
+)
+
+type webhookStore interface {
+	ListWebhooks(ctx context.Context, limit, offset int) ([]model.Webhook, int, error)
+	GetWebhook(ctx context.Context, id string) (model.Webhook, error)
+	CreateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error)
+	UpdateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, int, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (model.WebhookDelivery, error)
+}
+
+// webhookRequest creates or replaces a webhook. An empty secret is generated
+// on create and left unchanged on update.
+type webhookRequest struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+}
+
+// webhookSpec never carries the secret except in the create response.
+type webhookSpec struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes"`
+	Groups     []string `json:"groups"`
+	Enabled    bool     `json:"enabled"`
+	CreatedBy  string   `json:"createdBy,omitempty"`
+}
+
+type webhookDeliverySpec struct {
+	WebhookID      string          `json:"webhookID"`
+	EventID        string          `json:"eventID"`
+	EventType      string          `json:"eventType"`
+	Subject        string          `json:"subject,omitempty"`
+	State          string          `json:"state"`
+	AttemptCount   int             `json:"attemptCount"`
+	NextAttemptAt  *timeRFC3339    `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	DeliveredAt    *timeRFC3339    `json:"deliveredAt,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	limit, offset, err := parseListPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, total, err := s.webhookStore.ListWebhooks(r.Context(), limit, offset)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to list webhooks")
+		return
+	}
+
+	resources := make([]httputil.Resource[webhookSpec], 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toWebhookResource(item, false))
+	}
+	httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[webhookSpec]{
+		Kind:       "WebhookList",
+		APIVersion: "power/v1",
+		Metadata: httputil.ListMetadata{
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		},
+		Items: resources,
+	})
+}
+
+func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	item, err := s.webhookStore.GetWebhook(r.Context(), id)
+	if err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+	httputil.RespondJSON(w, http.StatusOK, toWebhookResource(item, false))
+}
+
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	var req webhookRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+	item, err := webhookFromRequest(req, model.Webhook{Enabled: true})
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if item.Secret == "" {
+		item.Secret, err = newWebhookSecret()
+		if err != nil {
+			httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to generate webhook secret")
+			return
+		}
+	}
+	item.CreatedBy = requestedByFromContext(r)
+
+	created, err := s.webhookStore.CreateWebhook(r.Context(), item)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to create webhook")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/power/v1/admin/webhooks/%s", created.ID))
+	httputil.RespondJSON(w, http.StatusCreated, toWebhookResource(created, true))
+}
+
+func (s *Server) handlePutWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	var req webhookRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondProblemf(w, r, http.StatusBadRequest, "invalid request body: %v", err)
+		return
+	}
+
+	existing, err := s.webhookStore.GetWebhook(r.Context(), id)
+	if err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+	item, err := webhookFromRequest(req, existing)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := s.webhookStore.UpdateWebhook(r.Context(), item)
+	if err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+	httputil.RespondJSON(w, http.StatusOK, toWebhookResource(updated, false))
+}
+
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if err := s.webhookStore.DeleteWebhook(r.Context(), id); err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	limit, offset, err := parseListPagination(r)
+	if err != nil {
+		httputil.RespondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	state := strings.TrimSpace(r.URL.Query().Get("state"))
+	if state != "" && !slices.Contains(webhookDeliveryStates, state) {
+		httputil.RespondProblemf(
+			w,
+			r,
+			http.StatusBadRequest,
+			"invalid state %q: use one of %s",
+			state,
+			strings.Join(webhookDeliveryStates, ", "),
+		)
+		return
+	}
+
+	if _, err := s.webhookStore.GetWebhook(r.Context(), id); err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+	items, total, err := s.webhookStore.ListWebhookDeliveries(r.Context(), model.WebhookDeliveryQuery{
+		WebhookID: id,
+		State:     state,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		respondWebhookLoadError(w, r, id, err)
+		return
+	}
+
+	resources := make([]httputil.Resource[webhookDeliverySpec], 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toWebhookDeliveryResource(item))
+	}
+	httputil.RespondJSON(w, http.StatusOK, httputil.ResourceList[webhookDeliverySpec]{
+		Kind:       "WebhookDeliveryList",
+		APIVersion: "power/v1",
+		Metadata: httputil.ListMetadata{
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		},
+		Items: resources,
+	})
+}
+
+func (s *Server) handleRedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	if s.webhookStore == nil {
+		httputil.RespondProblem(w, r, http.StatusServiceUnavailable, errWebhookStoreUnavailable.Error())
+		return
+	}
+
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	deliveryID := strings.TrimSpace(chi.URLParam(r, "deliveryID"))
+	item, err := s.webhookStore.RedeliverWebhookDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			httputil.RespondProblemf(w, r, http.StatusNotFound, "delivery %q of webhook %q not found", deliveryID, id)
+			return
+		}
+		httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to requeue webhook delivery")
+		return
+	}
+	httputil.RespondJSON(w, http.StatusAccepted, toWebhookDeliveryResource(item))
+}
+
+// webhookFromRequest validates a webhook body and applies it over base.
+func webhookFromRequest(req webhookRequest, base model.Webhook) (model.Webhook, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.Webhook{}, fmt.Errorf("name is required")
+	}
+	target := strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return model.Webhook{}, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if req.Secret != "" && len(req.Secret) < minWebhookSecretLength {
+		return model.Webhook{}, fmt.Errorf("secret must be at least %d characters", minWebhookSecretLength)
+	}
+	eventTypes := parseTargetList(req.EventTypes)
+	for _, eventType := range eventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			return model.Webhook{}, fmt.Errorf(
+				"invalid event type %q: use one of %s",
+				eventType,
+				strings.Join(webhookEventTypes, ", "),
+			)
+		}
+	}
+
+	item := base
+	item.Name = name
+	item.URL = target
+	item.EventTypes = eventTypes
+	item.Groups = parseTargetList(req.Groups)
+	if req.Secret != "" {
+		item.Secret = req.Secret
+	}
+	if req.Enabled != nil {
+		item.Enabled = *req.Enabled
+	}
+	return item, nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func respondWebhookLoadError(w http.ResponseWriter, r *http.Request, id string, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		httputil.RespondProblemf(w, r, http.StatusNotFound, "webhook %q not found", id)
+		return
+	}
+	httputil.RespondProblem(w, r, http.StatusInternalServerError, "failed to load webhook")
+}
+
+func toWebhookResource(item model.Webhook, withSecret bool) httputil.Resource[webhookSpec] {
+	spec := webhookSpec{
+		Name:       item.Name,
+		URL:        item.URL,
+		EventTypes: item.EventTypes,
+		Groups:     item.Groups,
+		Enabled:    item.Enabled,
+		CreatedBy:  item.CreatedBy,
+	}
+	if spec.EventTypes == nil {
+		spec.EventTypes = []string{}
+	}
+	if spec.Groups == nil {
+		spec.Groups = []string{}
+	}
+	if withSecret {
+		spec.Secret = item.Secret
+	}
+
+	return httputil.Resource[webhookSpec]{
+		Kind:       "Webhook",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID:        item.ID,
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.UpdatedAt,
+		},
+		Spec: spec,
+	}
+}
+
+func toWebhookDeliveryResource(item model.WebhookDelivery) httputil.Resource[webhookDeliverySpec] {
+	spec := webhookDeliverySpec{
+		WebhookID:      item.WebhookID,
+		EventID:        item.EventID,
+		EventType:      item.EventType,
+		Subject:        item.Subject,
+		State:          item.State,
+		AttemptCount:   item.AttemptCount,
+		LastStatusCode: item.LastStatusCode,
+		LastError:      item.LastError,
+		DeliveredAt:    toTimeRFC3339Ptr(item.DeliveredAt),
+		Payload:        item.Payload,
+	}
+	if item.State == model.WebhookDeliveryPending {
+		next := newTimeRFC3339(item.NextAttemptAt)
+		spec.NextAttemptAt = &next
+	}
+
+	return httputil.Resource[webhookDeliverySpec]{
+		Kind:       "WebhookDelivery",
+		APIVersion: "power/v1",
+		Metadata: httputil.Metadata{
+			ID:        item.ID,
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.UpdatedAt,
+		},
+		Spec: spec,
+	}
+}
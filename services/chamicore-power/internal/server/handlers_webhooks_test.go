@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+type mockWebhookStore struct {
+	mockPowerStore
+	hooks         map[string]model.Webhook
+	deliveryQuery model.WebhookDeliveryQuery
+	deliveries    []model.WebhookDelivery
+}
+
+func (m *mockWebhookStore) ListWebhooks(ctx context.Context, limit, offset int) ([]model.Webhook, int, error) {
+	items := make([]model.Webhook, 0, len(m.hooks))
+	for _, item := range m.hooks {
+		items = append(items, item)
+	}
+	return items, len(items), nil
+}
+
+func (m *mockWebhookStore) GetWebhook(ctx context.Context, id string) (model.Webhook, error) {
+	item, ok := m.hooks[id]
+	if !ok {
+		return model.Webhook{}, store.ErrNotFound
+	}
+	return item, nil
+}
+
+func (m *mockWebhookStore) CreateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error) {
+	item.ID = "hook-1"
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+	m.hooks[item.ID] = item
+	return item, nil
+}
+
+func (m *mockWebhookStore) UpdateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error) {
+	if _, ok := m.hooks[item.ID]; !ok {
+		return model.Webhook{}, store.ErrNotFound
+	}
+	m.hooks[item.ID] = item
+	return item, nil
+}
+
+func (m *mockWebhookStore) DeleteWebhook(ctx context.Context, id string) error {
+	if _, ok := m.hooks[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(m.hooks, id)
+	return nil
+}
+
+func (m *mockWebhookStore) ListWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, int, error) {
+	m.deliveryQuery = query
+	return m.deliveries, len(m.deliveries), nil
+}
+
+func (m *mockWebhookStore) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (model.WebhookDelivery, error) {
+	for _, item := range m.deliveries {
+		if item.WebhookID == webhookID && item.ID == deliveryID {
+			item.State = model.WebhookDeliveryPending
+			item.AttemptCount = 0
+			return item, nil
+		}
+	}
+	return model.WebhookDelivery{}, store.ErrNotFound
+}
+
+func serveWebhookRequest(t *testing.T, srv *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhooks_CreateGeneratesSecretAndHidesItAfterwards(t *testing.T) {
+	st := &mockWebhookStore{hooks: map[string]model.Webhook{}}
+	srv := New(st, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	w := serveWebhookRequest(t, srv, http.MethodPost, "/power/v1/admin/webhooks", `{
+		"name": "ops",
+		"url": "https://hooks.example.com/power",
+		"eventTypes": ["chamicore.power.transitions.lifecycle"],
+		"groups": ["compute"]
+	}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/power/v1/admin/webhooks/hook-1", w.Header().Get("Location"))
+
+	var created struct {
+		Spec webhookSpec `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Len(t, created.Spec.Secret, 64)
+	assert.True(t, created.Spec.Enabled)
+	assert.Equal(t, []string{"compute"}, created.Spec.Groups)
+
+	w = serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks/hook-1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Spec.Secret)
+
+	// Updating without a secret keeps the generated one.
+	w = serveWebhookRequest(t, srv, http.MethodPut, "/power/v1/admin/webhooks/hook-1", `{
+		"name": "ops",
+		"url": "https://hooks.example.com/v2",
+		"enabled": false
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, created.Spec.Secret, st.hooks["hook-1"].Secret)
+	assert.False(t, st.hooks["hook-1"].Enabled)
+	assert.Empty(t, st.hooks["hook-1"].EventTypes)
+
+	w = serveWebhookRequest(t, srv, http.MethodDelete, "/power/v1/admin/webhooks/hook-1", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks/hook-1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhooks_RejectInvalidRequests(t *testing.T) {
+	st := &mockWebhookStore{hooks: map[string]model.Webhook{}}
+	srv := New(st, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing name", body: `{"url":"https://example.com"}`},
+		{name: "relative url", body: `{"name":"ops","url":"/hook"}`},
+		{name: "unsupported scheme", body: `{"name":"ops","url":"ftp://example.com"}`},
+		{name: "short secret", body: `{"name":"ops","url":"https://example.com","secret":"abc"}`},
+		{name: "unknown event type", body: `{"name":"ops","url":"https://example.com","eventTypes":["chamicore.smd.changed"]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serveWebhookRequest(t, srv, http.MethodPost, "/power/v1/admin/webhooks", tc.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+	assert.Empty(t, st.hooks)
+}
+
+func TestWebhooks_DeliveryLogAndRedeliver(t *testing.T) {
+	delivered := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	st := &mockWebhookStore{
+		hooks: map[string]model.Webhook{"hook-1": {ID: "hook-1", Name: "ops"}},
+		deliveries: []model.WebhookDelivery{
+			{ID: "del-1", WebhookID: "hook-1", EventID: "evt-1", State: model.WebhookDeliveryDead, AttemptCount: 10, LastStatusCode: 503, LastError: "unexpected status 503"},
+			{ID: "del-2", WebhookID: "hook-1", EventID: "evt-2", State: model.WebhookDeliveryDelivered, AttemptCount: 1, DeliveredAt: &delivered},
+		},
+	}
+	srv := New(st, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	w := serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks/hook-1/deliveries?state=dead&limit=5", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, model.WebhookDeliveryQuery{WebhookID: "hook-1", State: "dead", Limit: 5}, st.deliveryQuery)
+
+	var list struct {
+		Metadata struct {
+			Total int `json:"total"`
+		} `json:"metadata"`
+		Items []struct {
+			Spec webhookDeliverySpec `json:"spec"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "unexpected status 503", list.Items[0].Spec.LastError)
+	require.NotNil(t, list.Items[1].Spec.DeliveredAt)
+
+	w = serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks/hook-1/deliveries?state=lost", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks/missing/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveWebhookRequest(t, srv, http.MethodPost, "/power/v1/admin/webhooks/hook-1/deliveries/del-1/redeliver", "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"state":"pending"`)
+	w = serveWebhookRequest(t, srv, http.MethodPost, "/power/v1/admin/webhooks/hook-1/deliveries/del-9/redeliver", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhooks_Unavailable(t *testing.T) {
+	srv := New(&mockPowerStore{}, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	w := serveWebhookRequest(t, srv, http.MethodGet, "/power/v1/admin/webhooks", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
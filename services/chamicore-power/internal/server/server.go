@@ -14,7 +14,10 @@ import (
 	"git.cscs.ch/openchami/chamicore-lib/otel"
 	"git.cscs.ch/openchami/chamicore-power/internal/config"
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/quota"
 	"git.cscs.ch/openchami/chamicore-power/internal/store"
+	syncer "git.cscs.ch/openchami/chamicore-power/internal/sync"
 )
 
 var errInitialMappingSyncPending = errors.New("initial mapping sync has not completed")
@@ -22,27 +25,55 @@ var errInitialMappingSyncPending = errors.New("initial mapping sync has not comp
 type mappingSyncer interface {
 	Trigger(ctx context.Context) error
 	IsReady() bool
+	Status() syncer.Status
+	DiagnoseMappings(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error)
 }
 
 type transitionRunner interface {
 	StartTransition(ctx context.Context, req engine.StartRequest) (engine.Transition, error)
 	AbortTransition(ctx context.Context, transitionID string) error
+	AbortTask(ctx context.Context, transitionID, nodeID string) error
+	DecideApproval(ctx context.Context, decision engine.ApprovalDecision) (engine.Transition, error)
 }
 
 type transitionStore interface {
 	ListTransitions(ctx context.Context, limit, offset int) ([]engine.Transition, int, error)
 	GetTransition(ctx context.Context, id string) (engine.Transition, error)
 	ListTransitionTasks(ctx context.Context, transitionID string) ([]engine.Task, error)
+	ListTransitionTasksPage(ctx context.Context, transitionID string, limit, offset int) ([]engine.Task, error)
+	CountTransitionTasksByState(ctx context.Context, transitionID string) (map[string]int, error)
 	ListLatestTransitionTasksByNode(ctx context.Context, nodeIDs []string) ([]engine.Task, error)
 }
 
+type systemPathStore interface {
+	ListNodeSystemPaths(ctx context.Context, limit, offset int) ([]model.NodeSystemPath, int, error)
+	GetNodeSystemPath(ctx context.Context, nodeID string) (model.NodeSystemPath, error)
+	SaveNodeSystemPath(ctx context.Context, item model.NodeSystemPath) (model.NodeSystemPath, error)
+	DeleteNodeSystemPath(ctx context.Context, nodeID string) error
+}
+
+type systemPathCache interface {
+	InvalidateNode(nodeID string)
+}
+
 // Server wraps HTTP routes and dependencies.
 type Server struct {
 	store               store.Store
 	transitionStore     transitionStore
 	transitionRunner    transitionRunner
+	powerCapReader      powerCapReader
+	telemetryStore      telemetryStore
+	templateStore       templateStore
+	approvalStore       approvalStore
+	webhookStore        webhookStore
+	quotaStore          quotaStore
+	quotas              *quota.Limiter
 	resolveGroupMembers func(ctx context.Context, group string) ([]string, error)
+	selectNodes         func(ctx context.Context, selector model.NodeSelector) ([]string, error)
 	mappingSync         mappingSyncer
+	systemPathStore     systemPathStore
+	systemPathCache     systemPathCache
+	pcsSnapshots        *pcsSnapshotCache
 	cfg                 config.Config
 	version             string
 	commit              string
@@ -75,6 +106,13 @@ func WithTransitionRunner(runner transitionRunner) Option {
 	}
 }
 
+// WithSystemPathCache sets the Redfish system-path cache invalidated by admin changes.
+func WithSystemPathCache(cache systemPathCache) Option {
+	return func(s *Server) {
+		s.systemPathCache = cache
+	}
+}
+
 // WithGroupMemberResolver configures a resolver for SMD group expansion.
 func WithGroupMemberResolver(fn func(ctx context.Context, group string) ([]string, error)) Option {
 	return func(s *Server) {
@@ -82,18 +120,51 @@ func WithGroupMemberResolver(fn func(ctx context.Context, group string) ([]strin
 	}
 }
 
+// WithNodeSelector configures a resolver for SMD attribute selectors.
+func WithNodeSelector(fn func(ctx context.Context, selector model.NodeSelector) ([]string, error)) Option {
+	return func(s *Server) {
+		s.selectNodes = fn
+	}
+}
+
+// WithQuotas enables per-caller rate limits and active transition quotas.
+func WithQuotas(limiter *quota.Limiter) Option {
+	return func(s *Server) {
+		s.quotas = limiter
+	}
+}
+
 // New constructs a power API server.
 func New(st store.Store, cfg config.Config, version, commit, buildDate string, opts ...Option) *Server {
 	s := &Server{
-		store:     st,
-		cfg:       cfg,
-		version:   version,
-		commit:    commit,
-		buildDate: buildDate,
+		store:        st,
+		pcsSnapshots: newPCSSnapshotCache(),
+		cfg:          cfg,
+		version:      version,
+		commit:       commit,
+		buildDate:    buildDate,
 	}
 	if ts, ok := any(st).(transitionStore); ok {
 		s.transitionStore = ts
 	}
+	if ps, ok := any(st).(systemPathStore); ok {
+		s.systemPathStore = ps
+	}
+	if ts, ok := any(st).(telemetryStore); ok {
+		s.telemetryStore = ts
+	}
+	if ts, ok := any(st).(templateStore); ok {
+		s.templateStore = ts
+	}
+	if as, ok := any(st).(approvalStore); ok {
+		s.approvalStore = as
+	}
+	if ws, ok := any(st).(webhookStore); ok {
+		s.webhookStore = ws
+	}
+	if qs, ok := any(st).(quotaStore); ok {
+		s.quotaStore = qs
+	}
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -123,15 +194,7 @@ func (s *Server) buildRouter() chi.Router {
 
 	r.Group(func(r chi.Router) {
 		r.Method(http.MethodGet, "/health", httputil.HealthHandler())
-		r.Method(http.MethodGet, "/readiness", httputil.ReadinessHandler(func() error {
-			if err := s.store.Ping(context.Background()); err != nil {
-				return err
-			}
-			if s.mappingSync != nil && !s.mappingSync.IsReady() {
-				return errInitialMappingSyncPending
-			}
-			return nil
-		}))
+		r.Get("/readiness", s.handleReadiness)
 		r.Method(http.MethodGet, "/version", httputil.VersionHandler(s.version, s.commit, s.buildDate))
 		if s.cfg.MetricsEnabled {
 			r.Method(http.MethodGet, "/metrics", otel.PrometheusHandler())
@@ -149,19 +212,77 @@ func (s *Server) buildRouter() chi.Router {
 
 		r.Route("/power/v1", func(r chi.Router) {
 			r.With(requireAnyScope("read:power", "admin")).Get("/transitions", s.handleListTransitions)
-			r.With(requireAnyScope("write:power", "admin")).Post("/transitions", s.handleCreateTransition)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/transitions", s.handleCreateTransition)
 			r.With(requireAnyScope("read:power", "admin")).Get("/transitions/{id}", s.handleGetTransition)
-			r.With(requireAnyScope("write:power", "admin")).Delete("/transitions/{id}", s.handleDeleteTransition)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Delete("/transitions/{id}", s.handleDeleteTransition)
+			r.With(requireAnyScope("read:power", "admin")).Get("/transitions/{id}/report", s.handleGetTransitionReport)
+			r.With(requireAnyScope("read:power", "admin")).Get("/transitions/{id}/export", s.handleExportTransitionTasks)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/transitions/{id}/retry", s.handleRetryTransition)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Delete("/transitions/{id}/tasks/{nodeID}", s.handleDeleteTransitionTask)
+			r.With(requireAnyScope("admin:power", "admin")).Post("/transitions/{id}/approve", s.handleApproveTransition)
+			r.With(requireAnyScope("admin:power", "admin")).Post("/transitions/{id}/reject", s.handleRejectTransition)
+
+			r.With(requireAnyScope("read:power", "admin")).Get("/templates", s.handleListTemplates)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/templates", s.handleCreateTemplate)
+			r.With(requireAnyScope("read:power", "admin")).Get("/templates/{name}", s.handleGetTemplate)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Put("/templates/{name}", s.handlePutTemplate)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Delete("/templates/{name}", s.handleDeleteTemplate)
+			r.With(requireAnyScope("read:power", "admin")).Get("/templates/{name}/versions", s.handleListTemplateVersions)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/templates/{name}/run", s.handleRunTemplate)
 
 			r.With(requireAnyScope("read:power", "admin")).Get("/power-status", s.handleGetPowerStatus)
 
-			r.With(requireAnyScope("write:power", "admin")).Post("/actions/on", s.handleActionOn)
-			r.With(requireAnyScope("write:power", "admin")).Post("/actions/off", s.handleActionOff)
-			r.With(requireAnyScope("write:power", "admin")).Post("/actions/reboot", s.handleActionReboot)
-			r.With(requireAnyScope("write:power", "admin")).Post("/actions/reset", s.handleActionReset)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/actions/on", s.handleActionOn)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/actions/off", s.handleActionOff)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/actions/reboot", s.handleActionReboot)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/actions/reset", s.handleActionReset)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/actions/boot-override", s.handleActionBootOverride)
+
+			r.With(requireAnyScope("read:power", "admin")).Get("/powercap", s.handleGetPowerCap)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/powercap", s.handleApplyPowerCap)
+
+			r.With(requireAnyScope("read:power", "admin")).Get("/telemetry", s.handleGetTelemetry)
 
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/boot-override", s.handleSetBootOverride)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/virtual-media/insert", s.handleInsertMedia)
+			r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/virtual-media/eject", s.handleEjectMedia)
+
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/mappings/diagnostics", s.handleGetMappingDiagnostics)
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/mappings/sync", s.handleGetMappingSyncStatus)
 			r.With(requireAnyScope("admin:power", "admin")).Post("/admin/mappings/sync", s.handleAdminSyncMappings)
+
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/system-paths", s.handleListSystemPaths)
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/system-paths/{nodeID}", s.handleGetSystemPath)
+			r.With(requireAnyScope("admin:power", "admin")).Put("/admin/system-paths/{nodeID}", s.handlePutSystemPath)
+			r.With(requireAnyScope("admin:power", "admin")).Delete("/admin/system-paths/{nodeID}", s.handleDeleteSystemPath)
+
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/quotas", s.handleGetQuotas)
+
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/webhooks", s.handleListWebhooks)
+			r.With(requireAnyScope("admin:power", "admin")).Post("/admin/webhooks", s.handleCreateWebhook)
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/webhooks/{id}", s.handleGetWebhook)
+			r.With(requireAnyScope("admin:power", "admin")).Put("/admin/webhooks/{id}", s.handlePutWebhook)
+			r.With(requireAnyScope("admin:power", "admin")).Delete("/admin/webhooks/{id}", s.handleDeleteWebhook)
+			r.With(requireAnyScope("admin:power", "admin")).Get("/admin/webhooks/{id}/deliveries", s.handleListWebhookDeliveries)
+			r.With(requireAnyScope("admin:power", "admin")).Post("/admin/webhooks/{id}/deliveries/{deliveryID}/redeliver", s.handleRedeliverWebhookDelivery)
 		})
+
+		if s.cfg.PCSCompatEnabled {
+			r.Route("/v1", func(r chi.Router) {
+				r.With(requireAnyScope("read:power", "admin")).Get("/transitions", s.handlePCSListTransitions)
+				r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Post("/transitions", s.handlePCSCreateTransition)
+				r.With(requireAnyScope("read:power", "admin")).Get("/transitions/{transitionID}", s.handlePCSGetTransition)
+				r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Delete("/transitions/{transitionID}", s.handlePCSDeleteTransition)
+
+				r.With(requireAnyScope("read:power", "admin")).Get("/power-status", s.handlePCSGetPowerStatus)
+				r.With(requireAnyScope("read:power", "admin")).Post("/power-status", s.handlePCSPostPowerStatus)
+
+				r.With(requireAnyScope("read:power", "admin")).Get("/power-cap", s.handlePCSListPowerCap)
+				r.With(requireAnyScope("write:power", "admin"), s.limitCaller).Patch("/power-cap", s.handlePCSPatchPowerCap)
+				r.With(requireAnyScope("read:power", "admin")).Post("/power-cap/snapshot", s.handlePCSPowerCapSnapshot)
+				r.With(requireAnyScope("read:power", "admin")).Get("/power-cap/{taskID}", s.handlePCSGetPowerCap)
+			})
+		}
 	})
 
 	return r
@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -13,7 +14,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"git.cscs.ch/openchami/chamicore-power/internal/config"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
 	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+	syncer "git.cscs.ch/openchami/chamicore-power/internal/sync"
 )
 
 type mockStore struct {
@@ -27,10 +31,9 @@ func (m *mockStore) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockStore) ReplaceTopologyMappings(
+func (m *mockStore) ApplyTopologyDelta(
 	ctx context.Context,
-	endpoints []model.BMCEndpoint,
-	links []model.NodeBMCLink,
+	delta model.TopologyDelta,
 	syncedAt time.Time,
 ) (model.MappingApplyCounts, error) {
 	return model.MappingApplyCounts{}, nil
@@ -54,6 +57,8 @@ func (m *mockStore) ListNodeBMCLinks(ctx context.Context) ([]model.NodeBMCLink,
 type mockMappingSyncer struct {
 	triggerFn func(ctx context.Context) error
 	isReadyFn func() bool
+	statusFn  func() syncer.Status
+	diagnose  func(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error)
 }
 
 func (m *mockMappingSyncer) Trigger(ctx context.Context) error {
@@ -70,6 +75,20 @@ func (m *mockMappingSyncer) IsReady() bool {
 	return true
 }
 
+func (m *mockMappingSyncer) Status() syncer.Status {
+	if m.statusFn != nil {
+		return m.statusFn()
+	}
+	return syncer.Status{}
+}
+
+func (m *mockMappingSyncer) DiagnoseMappings(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error) {
+	if m.diagnose != nil {
+		return m.diagnose(ctx, statuses)
+	}
+	return []model.NodeMappingDiagnostic{}, nil
+}
+
 func TestServer_PublicEndpoints(t *testing.T) {
 	srv := New(&mockStore{}, config.Config{MetricsEnabled: true, DevMode: true}, "v1", "abc", "now")
 	router := srv.Router()
@@ -120,6 +139,42 @@ func TestServer_ReadinessRequiresMappingSync(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.Code)
 }
 
+type queueReportingRunner struct {
+	mockTransitionRunner
+	status engine.QueueStatus
+}
+
+func (m *queueReportingRunner) QueueStatus() engine.QueueStatus {
+	return m.status
+}
+
+func TestServer_ReadinessReportsQueueDepth(t *testing.T) {
+	runner := &queueReportingRunner{status: engine.QueueStatus{
+		Depth:           120,
+		Limit:           80,
+		ByPriority:      map[string]int{engine.PriorityHigh: 20, engine.PriorityNormal: 0, engine.PriorityLow: 100},
+		AvgTaskDuration: 4 * time.Second,
+	}}
+	srv := New(&mockStore{}, config.Config{DevMode: true}, "v1", "abc", "now", WithTransitionRunner(runner))
+
+	req := httptest.NewRequest(http.MethodGet, "/readiness", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, "a full queue does not make the service unready")
+
+	var out readinessSpec
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, "ready", out.Status)
+	require.NotNil(t, out.Queue)
+	assert.Equal(t, queueStatusSpec{
+		Depth:          120,
+		Limit:          80,
+		Full:           true,
+		ByPriority:     map[string]int{"high": 20, "normal": 0, "low": 100},
+		AvgTaskSeconds: 4,
+	}, *out.Queue)
+}
+
 func TestServer_AuthRequiredWhenNotDevMode(t *testing.T) {
 	srv := New(&mockStore{}, config.Config{DevMode: false}, "v1", "abc", "now")
 
@@ -188,3 +243,308 @@ func TestServer_AdminSyncMappings_Error(t *testing.T) {
 	srv.Router().ServeHTTP(resp, req)
 	require.Equal(t, http.StatusInternalServerError, resp.Code)
 }
+
+func TestServer_AdminSyncMappings_LeaderWaitTimeout(t *testing.T) {
+	srv := New(
+		&mockStore{},
+		config.Config{DevMode: true},
+		"v1",
+		"abc",
+		"now",
+		WithMappingSyncer(&mockMappingSyncer{
+			triggerFn: func(ctx context.Context) error {
+				return context.DeadlineExceeded
+			},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/power/v1/admin/mappings/sync", http.NoBody)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusGatewayTimeout, resp.Code)
+}
+
+func TestServer_GetMappingSyncStatus(t *testing.T) {
+	syncedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv := New(
+		&mockStore{},
+		config.Config{DevMode: true},
+		"v1",
+		"abc",
+		"now",
+		WithMappingSyncer(&mockMappingSyncer{
+			statusFn: func() syncer.Status {
+				return syncer.Status{
+					Ready:          true,
+					LastSyncAt:     &syncedAt,
+					SuccessfulRuns: 3,
+					LastCounts:     model.MappingApplyCounts{EndpointsUpserted: 1, LinksDeleted: 1},
+					LastReport: &model.MappingSyncReport{
+						GeneratedAt: syncedAt,
+						Summary:     model.MappingSyncSummary{BMCsChanged: 1, LinksRemoved: 1},
+						BMCsChanged: []model.BMCMappingChange{{
+							BMCID:            "bmc-1",
+							Endpoint:         "https://10.0.0.2",
+							PreviousEndpoint: "https://10.0.0.1",
+						}},
+						LinksRemoved: []model.LinkMappingChange{{NodeID: "node-9", PreviousBMCID: "bmc-9"}},
+					},
+				}
+			},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/mappings/sync", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			Ready          bool   `json:"ready"`
+			LastSyncAt     string `json:"lastSyncAt"`
+			SuccessfulRuns int64  `json:"successfulRuns"`
+			LastReport     struct {
+				Summary struct {
+					BMCsChanged  int `json:"bmcsChanged"`
+					LinksRemoved int `json:"linksRemoved"`
+				} `json:"summary"`
+				BMCsChanged []struct {
+					BMCID            string `json:"bmcID"`
+					PreviousEndpoint string `json:"previousEndpoint"`
+				} `json:"bmcsChanged"`
+				LinksRemoved []struct {
+					NodeID        string `json:"nodeID"`
+					PreviousBMCID string `json:"previousBMCID"`
+				} `json:"linksRemoved"`
+			} `json:"lastReport"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "MappingSyncStatus", body.Kind)
+	assert.True(t, body.Spec.Ready)
+	assert.Equal(t, "2026-03-01T12:00:00Z", body.Spec.LastSyncAt)
+	assert.Equal(t, int64(3), body.Spec.SuccessfulRuns)
+	assert.Equal(t, 1, body.Spec.LastReport.Summary.BMCsChanged)
+	assert.Equal(t, 1, body.Spec.LastReport.Summary.LinksRemoved)
+	require.Len(t, body.Spec.LastReport.BMCsChanged, 1)
+	assert.Equal(t, "https://10.0.0.1", body.Spec.LastReport.BMCsChanged[0].PreviousEndpoint)
+	require.Len(t, body.Spec.LastReport.LinksRemoved, 1)
+	assert.Equal(t, "bmc-9", body.Spec.LastReport.LinksRemoved[0].PreviousBMCID)
+}
+
+func TestServer_GetMappingDiagnostics(t *testing.T) {
+	var seenStatuses []string
+	srv := New(
+		&mockStore{},
+		config.Config{DevMode: true},
+		"v1",
+		"abc",
+		"now",
+		WithMappingSyncer(&mockMappingSyncer{
+			diagnose: func(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error) {
+				seenStatuses = statuses
+				return []model.NodeMappingDiagnostic{
+					{NodeID: "node-1", Status: model.MappingErrorCodeEndpointMissing, BMCID: "bmc-1"},
+					{
+						NodeID: "node-2",
+						Status: model.MappingErrorCodeEndpointMissing,
+						Reason: "SMD has no ethernet interfaces for BMC \"bmc-2\"",
+						BMCID:  "bmc-2",
+						SMD: model.NodeMappingSMD{
+							ParentID:   "bmc-2",
+							ParentType: "BMC",
+							Interfaces: []model.InterfaceMappingEvidence{},
+						},
+						Suggestions: []string{"add an ethernet interface"},
+					},
+				}, nil
+			},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/mappings/diagnostics?code=endpoint_missing&limit=1&offset=1", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []string{model.MappingErrorCodeEndpointMissing}, seenStatuses)
+
+	var body struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Total int `json:"total"`
+		} `json:"metadata"`
+		Items []struct {
+			Spec struct {
+				NodeID string `json:"nodeID"`
+				Status string `json:"status"`
+				SMD    struct {
+					ParentID string `json:"parentID"`
+				} `json:"smd"`
+				Suggestions []string `json:"suggestions"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "NodeMappingDiagnosticList", body.Kind)
+	assert.Equal(t, 2, body.Metadata.Total)
+	require.Len(t, body.Items, 1)
+	assert.Equal(t, "node-2", body.Items[0].Spec.NodeID)
+	assert.Equal(t, "endpoint_missing", body.Items[0].Spec.Status)
+	assert.Equal(t, "bmc-2", body.Items[0].Spec.SMD.ParentID)
+	assert.Equal(t, []string{"add an ethernet interface"}, body.Items[0].Spec.Suggestions)
+}
+
+func TestServer_GetMappingDiagnostics_Errors(t *testing.T) {
+	srv := New(
+		&mockStore{},
+		config.Config{DevMode: true},
+		"v1",
+		"abc",
+		"now",
+		WithMappingSyncer(&mockMappingSyncer{
+			diagnose: func(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error) {
+				return nil, errors.New("smd unavailable")
+			},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/mappings/diagnostics?code=bogus", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/power/v1/admin/mappings/diagnostics", nil)
+	resp = httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadGateway, resp.Code)
+}
+
+type mockSystemPathStore struct {
+	mockStore
+	items map[string]model.NodeSystemPath
+}
+
+func (m *mockSystemPathStore) ListNodeSystemPaths(ctx context.Context, limit, offset int) ([]model.NodeSystemPath, int, error) {
+	items := make([]model.NodeSystemPath, 0, len(m.items))
+	for _, item := range m.items {
+		items = append(items, item)
+	}
+	return items, len(items), nil
+}
+
+func (m *mockSystemPathStore) GetNodeSystemPath(ctx context.Context, nodeID string) (model.NodeSystemPath, error) {
+	item, ok := m.items[nodeID]
+	if !ok {
+		return model.NodeSystemPath{}, store.ErrNotFound
+	}
+	return item, nil
+}
+
+func (m *mockSystemPathStore) SaveNodeSystemPath(ctx context.Context, item model.NodeSystemPath) (model.NodeSystemPath, error) {
+	item.CreatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	item.UpdatedAt = item.CreatedAt
+	m.items[item.NodeID] = item
+	return item, nil
+}
+
+func (m *mockSystemPathStore) DeleteNodeSystemPath(ctx context.Context, nodeID string) error {
+	if _, ok := m.items[nodeID]; !ok {
+		return store.ErrNotFound
+	}
+	delete(m.items, nodeID)
+	return nil
+}
+
+type mockSystemPathCache struct {
+	invalidated []string
+}
+
+func (m *mockSystemPathCache) InvalidateNode(nodeID string) {
+	m.invalidated = append(m.invalidated, nodeID)
+}
+
+func TestServer_SystemPathsAdminLifecycle(t *testing.T) {
+	st := &mockSystemPathStore{items: map[string]model.NodeSystemPath{}}
+	cache := &mockSystemPathCache{}
+	srv := New(st, config.Config{DevMode: true}, "v1", "abc", "now", WithSystemPathCache(cache))
+
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/power/v1/admin/system-paths/node-1",
+		bytes.NewBufferString(`{"systemPath":"/redfish/v1/Systems/Node1"}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			NodeID     string `json:"nodeID"`
+			SystemPath string `json:"systemPath"`
+			Source     string `json:"source"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "NodeSystemPath", body.Kind)
+	assert.Equal(t, "node-1", body.Spec.NodeID)
+	assert.Equal(t, "/redfish/v1/Systems/Node1", body.Spec.SystemPath)
+	assert.Equal(t, model.SystemPathSourceAdmin, body.Spec.Source)
+	assert.Equal(t, []string{"node-1"}, cache.invalidated)
+
+	req = httptest.NewRequest(http.MethodGet, "/power/v1/admin/system-paths", nil)
+	resp = httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var list struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Total int `json:"total"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Equal(t, "NodeSystemPathList", list.Kind)
+	assert.Equal(t, 1, list.Metadata.Total)
+
+	req = httptest.NewRequest(http.MethodDelete, "/power/v1/admin/system-paths/node-1", nil)
+	resp = httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, []string{"node-1", "node-1"}, cache.invalidated)
+
+	req = httptest.NewRequest(http.MethodGet, "/power/v1/admin/system-paths/node-1", nil)
+	resp = httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestServer_PutSystemPath_RejectsNonSystemPath(t *testing.T) {
+	st := &mockSystemPathStore{items: map[string]model.NodeSystemPath{}}
+	srv := New(st, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	for _, payload := range []string{
+		`{"systemPath":"/redfish/v1/Managers/BMC"}`,
+		`{"systemPath":"/redfish/v1/Systems/"}`,
+		`{"systemPath":""}`,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/power/v1/admin/system-paths/node-1", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		srv.Router().ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, payload)
+	}
+	assert.Empty(t, st.items)
+}
+
+func TestServer_SystemPaths_UnavailableWithoutStore(t *testing.T) {
+	srv := New(&mockStore{}, config.Config{DevMode: true}, "v1", "abc", "now")
+
+	req := httptest.NewRequest(http.MethodGet, "/power/v1/admin/system-paths", nil)
+	resp := httptest.NewRecorder()
+	srv.Router().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
@@ -0,0 +1,197 @@
+package slurm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	powerclient "git.cscs.ch/openchami/chamicore-power/pkg/client"
+	"git.cscs.ch/openchami/chamicore-power/pkg/hostlist"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+// Mode selects what the program does with the nodes Slurm hands it.
+type Mode string
+
+const (
+	// ModeSuspend powers nodes off for Slurm's SuspendProgram.
+	ModeSuspend Mode = "suspend"
+	// ModeResume powers nodes on for Slurm's ResumeProgram.
+	ModeResume Mode = "resume"
+)
+
+const (
+	operationOn               = "On"
+	operationGracefulShutdown = "GracefulShutdown"
+)
+
+// ErrTimeout is returned when the transition does not finish in time.
+var ErrTimeout = errors.New("timed out waiting for transition")
+
+// ParseMode parses a suspend or resume mode name.
+func ParseMode(raw string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case ModeSuspend, ModeResume:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q (expected %q or %q)", raw, ModeSuspend, ModeResume)
+	}
+}
+
+// PowerClient is the subset of the power client used by the program.
+type PowerClient interface {
+	CreateTransition(ctx context.Context, req types.CreateTransitionRequest) (*httputil.Resource[types.Transition], error)
+	WaitTransition(
+		ctx context.Context,
+		id string,
+		opts powerclient.WaitTransitionOptions,
+	) (*httputil.Resource[types.Transition], error)
+	ExportTransitionTasks(ctx context.Context, id string) (*httputil.ResourceList[types.TransitionTask], error)
+}
+
+// HostResolver maps Slurm host names to node xnames.
+type HostResolver interface {
+	Resolve(ctx context.Context, hosts []string) (map[string]string, error)
+}
+
+// Config configures a Program.
+type Config struct {
+	// Timeout bounds the whole run, from SMD lookup to transition completion.
+	Timeout time.Duration
+	// PollInterval is how often the transition is polled.
+	PollInterval time.Duration
+	// EscalateAfter forces nodes off when a suspend's graceful shutdown has
+	// not completed in time. Zero disables escalation.
+	EscalateAfter time.Duration
+}
+
+// Program runs one suspend or resume request from Slurm.
+type Program struct {
+	power    PowerClient
+	resolver HostResolver
+	cfg      Config
+	logger   zerolog.Logger
+}
+
+// New creates a Slurm power program.
+func New(power PowerClient, resolver HostResolver, cfg Config, logger zerolog.Logger) *Program {
+	return &Program{
+		power:    power,
+		resolver: resolver,
+		cfg:      cfg,
+		logger:   logger.With().Str("component", "slurm").Logger(),
+	}
+}
+
+// Run expands the hostlist, powers its nodes and waits for the transition to
+// finish. It returns an error unless every node completed.
+func (p *Program) Run(ctx context.Context, mode Mode, expr string) error {
+	if p.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.Timeout)
+		defer cancel()
+	}
+
+	hosts, err := hostlist.Expand(expr)
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("hostlist %q is empty", expr)
+	}
+
+	xnames, err := p.resolver.Resolve(ctx, hosts)
+	if err != nil {
+		return err
+	}
+	hostByNode := make(map[string]string, len(xnames))
+	nodes := make([]string, 0, len(xnames))
+	for _, host := range hosts {
+		node := xnames[host]
+		if _, ok := hostByNode[node]; ok {
+			continue
+		}
+		hostByNode[node] = host
+		nodes = append(nodes, node)
+	}
+
+	req := types.CreateTransitionRequest{Operation: operationOn, Nodes: nodes}
+	if mode == ModeSuspend {
+		req.Operation = operationGracefulShutdown
+		if p.cfg.EscalateAfter > 0 {
+			req.Escalation = &types.Escalation{AfterSeconds: int(p.cfg.EscalateAfter / time.Second)}
+		}
+	}
+
+	created, err := p.power.CreateTransition(ctx, req)
+	if err != nil {
+		return err
+	}
+	logger := p.logger.With().
+		Str("mode", string(mode)).
+		Str("transition_id", created.Metadata.ID).
+		Str("operation", req.Operation).
+		Logger()
+	logger.Info().Int("nodes", len(nodes)).Str("hostlist", expr).Msg("transition started")
+
+	done, err := p.power.WaitTransition(ctx, created.Metadata.ID, powerclient.WaitTransitionOptions{
+		Interval: p.cfg.PollInterval,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w %s after %s", ErrTimeout, created.Metadata.ID, p.cfg.Timeout)
+		}
+		return err
+	}
+
+	transition := done.Spec
+	if transition.State == types.TransitionStateCompleted {
+		logger.Info().Int("succeeded", transition.SuccessCount).Msg("transition completed")
+		return nil
+	}
+
+	tasks := transition.Tasks
+	if page := transition.TaskPage; page != nil && page.Total > len(tasks) {
+		// Transitions larger than one task page need the full task list.
+		exported, exportErr := p.power.ExportTransitionTasks(ctx, created.Metadata.ID)
+		if exportErr != nil {
+			logger.Warn().Err(exportErr).Msg("listing all transition tasks failed, reporting the first page")
+		} else {
+			tasks = make([]types.TransitionTask, 0, len(exported.Items))
+			for _, item := range exported.Items {
+				tasks = append(tasks, item.Spec)
+			}
+		}
+	}
+	failed := failedHosts(tasks, hostByNode)
+	logger.Error().
+		Str("state", transition.State).
+		Int("succeeded", transition.SuccessCount).
+		Int("failed", transition.FailureCount).
+		Strs("failed_hosts", failed).
+		Msg("transition did not complete")
+	return fmt.Errorf("transition %s finished in state %s", created.Metadata.ID, transition.State)
+}
+
+// failedHosts returns the Slurm names of nodes whose task did not succeed.
+func failedHosts(tasks []types.TransitionTask, hostByNode map[string]string) []string {
+	hosts := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.State == types.TaskStateSucceeded {
+			continue
+		}
+		host := hostByNode[task.NodeID]
+		if host == "" {
+			host = task.NodeID
+		}
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
@@ -0,0 +1,216 @@
+package slurm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	powerclient "git.cscs.ch/openchami/chamicore-power/pkg/client"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+type mockSMD struct {
+	nodes      []string
+	interfaces []smdtypes.EthernetInterface
+	ifaceCalls int
+}
+
+func (m *mockSMD) ListComponents(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error) {
+	list := &httputil.ResourceList[smdtypes.Component]{Metadata: httputil.ListMetadata{Total: len(m.nodes)}}
+	for _, id := range m.nodes {
+		list.Items = append(list.Items, httputil.Resource[smdtypes.Component]{Spec: smdtypes.Component{ID: id, Type: opts.Type}})
+	}
+	return list, nil
+}
+
+func (m *mockSMD) ListEthernetInterfaces(ctx context.Context, opts smdclient.InterfaceListOptions) (*httputil.ResourceList[smdtypes.EthernetInterface], error) {
+	m.ifaceCalls++
+	list := &httputil.ResourceList[smdtypes.EthernetInterface]{Metadata: httputil.ListMetadata{Total: len(m.interfaces)}}
+	for _, item := range m.interfaces {
+		list.Items = append(list.Items, httputil.Resource[smdtypes.EthernetInterface]{Spec: item})
+	}
+	return list, nil
+}
+
+type mockPower struct {
+	req      types.CreateTransitionRequest
+	waitFn   func(ctx context.Context) (*httputil.Resource[types.Transition], error)
+	waitOpt  powerclient.WaitTransitionOptions
+	exported []types.TransitionTask
+}
+
+func (m *mockPower) CreateTransition(ctx context.Context, req types.CreateTransitionRequest) (*httputil.Resource[types.Transition], error) {
+	m.req = req
+	return &httputil.Resource[types.Transition]{Metadata: httputil.Metadata{ID: "tr-1"}}, nil
+}
+
+func (m *mockPower) WaitTransition(
+	ctx context.Context,
+	id string,
+	opts powerclient.WaitTransitionOptions,
+) (*httputil.Resource[types.Transition], error) {
+	m.waitOpt = opts
+	return m.waitFn(ctx)
+}
+
+func (m *mockPower) ExportTransitionTasks(ctx context.Context, id string) (*httputil.ResourceList[types.TransitionTask], error) {
+	list := &httputil.ResourceList[types.TransitionTask]{Metadata: httputil.ListMetadata{Total: len(m.exported)}}
+	for _, task := range m.exported {
+		list.Items = append(list.Items, httputil.Resource[types.TransitionTask]{Spec: task})
+	}
+	return list, nil
+}
+
+func finished(state string, tasks ...types.TransitionTask) func(ctx context.Context) (*httputil.Resource[types.Transition], error) {
+	return func(ctx context.Context) (*httputil.Resource[types.Transition], error) {
+		return &httputil.Resource[types.Transition]{Spec: types.Transition{State: state, Tasks: tasks}}, nil
+	}
+}
+
+func newTestSMD() *mockSMD {
+	return &mockSMD{
+		nodes: []string{"x1000c0s0b0n0", "x1000c0s1b0n0", "x1000c0s2b0n0"},
+		interfaces: []smdtypes.EthernetInterface{
+			{ComponentID: "x1000c0s0b0n0", IPAddrs: json.RawMessage(`["10.1.0.1"]`)},
+			{ComponentID: "x1000c0s1b0n0", IPAddrs: json.RawMessage(`["10.1.0.2"]`)},
+			{ComponentID: "x1000c0s0b0", IPAddrs: json.RawMessage(`["10.1.0.3"]`)},
+		},
+	}
+}
+
+func fakeDNS(records map[string][]string) ResolverOption {
+	return WithLookupHost(func(ctx context.Context, host string) ([]string, error) {
+		if addresses, ok := records[host]; ok {
+			return addresses, nil
+		}
+		return nil, errors.New("no such host")
+	})
+}
+
+func TestResolver_MapsHostnamesThroughSMDInterfaces(t *testing.T) {
+	smd := newTestSMD()
+	resolver := NewResolver(smd, fakeDNS(map[string][]string{
+		"nid001": {"fe80::1", "10.1.0.1"},
+		"nid002": {"10.1.0.2"},
+	}))
+
+	got, err := resolver.Resolve(context.Background(), []string{"nid001", "nid002", "x1000c0s2b0n0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"nid001":        "x1000c0s0b0n0",
+		"nid002":        "x1000c0s1b0n0",
+		"x1000c0s2b0n0": "x1000c0s2b0n0",
+	}, got)
+
+	got, err = NewResolver(smd).Resolve(context.Background(), []string{"x1000c0s0b0n0"})
+	require.NoError(t, err)
+	assert.Equal(t, "x1000c0s0b0n0", got["x1000c0s0b0n0"])
+	assert.Equal(t, 1, smd.ifaceCalls, "interfaces are only listed for names that are not xnames")
+}
+
+func TestResolver_FailsOnUnmappedHosts(t *testing.T) {
+	resolver := NewResolver(newTestSMD(), fakeDNS(map[string][]string{
+		"nid001": {"10.1.0.1"},
+		"bmc001": {"10.1.0.3"},
+	}))
+
+	_, err := resolver.Resolve(context.Background(), []string{"nid001", "nid404", "bmc001"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 host(s): bmc001,nid404")
+}
+
+func TestProgram_SuspendAndResume(t *testing.T) {
+	resolver := NewResolver(newTestSMD(), fakeDNS(map[string][]string{
+		"nid001": {"10.1.0.1"},
+		"nid002": {"10.1.0.2"},
+	}))
+
+	power := &mockPower{waitFn: finished(types.TransitionStateCompleted)}
+	program := New(power, resolver, Config{PollInterval: time.Second, EscalateAfter: 90 * time.Second}, zerolog.Nop())
+
+	require.NoError(t, program.Run(context.Background(), ModeSuspend, "nid[001-002]"))
+	assert.Equal(t, "GracefulShutdown", power.req.Operation)
+	assert.Equal(t, []string{"x1000c0s0b0n0", "x1000c0s1b0n0"}, power.req.Nodes)
+	require.NotNil(t, power.req.Escalation)
+	assert.Equal(t, 90, power.req.Escalation.AfterSeconds)
+	assert.Equal(t, time.Second, power.waitOpt.Interval)
+
+	require.NoError(t, program.Run(context.Background(), ModeResume, "nid001,x1000c0s0b0n0"))
+	assert.Equal(t, "On", power.req.Operation)
+	assert.Equal(t, []string{"x1000c0s0b0n0"}, power.req.Nodes)
+	assert.Nil(t, power.req.Escalation)
+}
+
+func TestProgram_FailsUnlessTransitionCompletes(t *testing.T) {
+	resolver := NewResolver(newTestSMD(), fakeDNS(map[string][]string{"nid001": {"10.1.0.1"}}))
+
+	power := &mockPower{waitFn: finished(types.TransitionStatePartial,
+		types.TransitionTask{NodeID: "x1000c0s0b0n0", State: types.TaskStateFailed},
+		types.TransitionTask{NodeID: "x1000c0s1b0n0", State: types.TaskStateSucceeded},
+	)}
+	err := New(power, resolver, Config{}, zerolog.Nop()).Run(context.Background(), ModeResume, "nid001,x1000c0s1b0n0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "partial")
+
+	assert.Equal(t, []string{"nid001"}, failedHosts([]types.TransitionTask{
+		{NodeID: "x1000c0s0b0n0", State: types.TaskStateFailed},
+		{NodeID: "x1000c0s1b0n0", State: types.TaskStateSucceeded},
+	}, map[string]string{"x1000c0s0b0n0": "nid001"}))
+}
+
+func TestProgram_ReportsFailedHostsBeyondFirstTaskPage(t *testing.T) {
+	resolver := NewResolver(newTestSMD(), fakeDNS(map[string][]string{"nid001": {"10.1.0.1"}}))
+	power := &mockPower{
+		waitFn: func(ctx context.Context) (*httputil.Resource[types.Transition], error) {
+			return &httputil.Resource[types.Transition]{Spec: types.Transition{
+				State:    types.TransitionStateFailed,
+				Tasks:    []types.TransitionTask{{NodeID: "x1000c0s1b0n0", State: types.TaskStateSucceeded}},
+				TaskPage: &types.TaskPage{Limit: 1, Total: 2},
+			}}, nil
+		},
+		exported: []types.TransitionTask{
+			{NodeID: "x1000c0s1b0n0", State: types.TaskStateSucceeded},
+			{NodeID: "x1000c0s0b0n0", State: types.TaskStateFailed},
+		},
+	}
+	var logs bytes.Buffer
+	err := New(power, resolver, Config{}, zerolog.New(&logs)).Run(context.Background(), ModeResume, "nid001,x1000c0s1b0n0")
+	require.Error(t, err)
+	assert.Contains(t, logs.String(), `"failed_hosts":["nid001"]`)
+}
+
+func TestProgram_Timeout(t *testing.T) {
+	power := &mockPower{waitFn: func(ctx context.Context) (*httputil.Resource[types.Transition], error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	program := New(power, NewResolver(newTestSMD()), Config{Timeout: 10 * time.Millisecond}, zerolog.Nop())
+
+	err := program.Run(context.Background(), ModeResume, "x1000c0s0b0n0")
+	require.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestProgram_RejectsBadInput(t *testing.T) {
+	power := &mockPower{}
+	program := New(power, NewResolver(newTestSMD()), Config{}, zerolog.Nop())
+
+	require.Error(t, program.Run(context.Background(), ModeResume, "nid[1-"))
+	require.Error(t, program.Run(context.Background(), ModeResume, ""))
+	assert.Empty(t, power.req.Operation, "no transition is created for invalid hostlists")
+
+	_, err := ParseMode("hibernate")
+	require.Error(t, err)
+	mode, err := ParseMode(" Resume ")
+	require.NoError(t, err)
+	assert.Equal(t, ModeResume, mode)
+}
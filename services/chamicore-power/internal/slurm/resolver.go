@@ -0,0 +1,176 @@
+// Package slurm implements the Slurm SuspendProgram/ResumeProgram mode of
+// chamicore-power: hostlist expressions are mapped to SMD node xnames and
+// powered through the transition API.
+package slurm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	powersmd "git.cscs.ch/openchami/chamicore-power/internal/smd"
+	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+const (
+	defaultPageSize = 1000
+	smdNodeType     = "Node"
+)
+
+// SMDClient is the subset of the SMD client used to map hostnames.
+type SMDClient interface {
+	ListComponents(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error)
+	ListEthernetInterfaces(ctx context.Context, opts smdclient.InterfaceListOptions) (*httputil.ResourceList[smdtypes.EthernetInterface], error)
+}
+
+// LookupHostFunc resolves a hostname to its IP addresses.
+type LookupHostFunc func(ctx context.Context, host string) ([]string, error)
+
+// Resolver maps Slurm node names to SMD node xnames.
+//
+// A name that is already an SMD node ID maps to itself. Any other name is
+// resolved through DNS and matched against the IP addresses SMD records for
+// node ethernet interfaces.
+type Resolver struct {
+	smd        SMDClient
+	lookupHost LookupHostFunc
+	pageSize   int
+}
+
+// ResolverOption configures a Resolver.
+type ResolverOption func(*Resolver)
+
+// WithLookupHost overrides DNS resolution of hostnames.
+func WithLookupHost(fn LookupHostFunc) ResolverOption {
+	return func(r *Resolver) {
+		if fn != nil {
+			r.lookupHost = fn
+		}
+	}
+}
+
+// NewResolver creates a hostname resolver backed by SMD.
+func NewResolver(smd SMDClient, opts ...ResolverOption) *Resolver {
+	r := &Resolver{
+		smd:        smd,
+		lookupHost: net.DefaultResolver.LookupHost,
+		pageSize:   defaultPageSize,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Resolve returns the xname of every host, keyed by host name. It fails if
+// any host cannot be mapped so that no partial set of nodes is powered.
+func (r *Resolver) Resolve(ctx context.Context, hosts []string) (map[string]string, error) {
+	nodes, err := r.listNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing SMD nodes: %w", err)
+	}
+
+	resolved := make(map[string]string, len(hosts))
+	var byIP map[string]string
+	var unresolved []string
+	for _, host := range hosts {
+		if _, ok := nodes[host]; ok {
+			resolved[host] = host
+			continue
+		}
+
+		if byIP == nil {
+			byIP, err = r.nodeAddresses(ctx, nodes)
+			if err != nil {
+				return nil, fmt.Errorf("listing SMD ethernet interfaces: %w", err)
+			}
+		}
+		if xname := r.lookupNode(ctx, host, byIP); xname != "" {
+			resolved[host] = xname
+			continue
+		}
+		unresolved = append(unresolved, host)
+	}
+
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return nil, fmt.Errorf("no SMD node found for %d host(s): %s", len(unresolved), strings.Join(unresolved, ","))
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) lookupNode(ctx context.Context, host string, byIP map[string]string) string {
+	addresses, err := r.lookupHost(ctx, host)
+	if err != nil {
+		return ""
+	}
+	for _, address := range addresses {
+		if xname, ok := byIP[address]; ok {
+			return xname
+		}
+	}
+	return ""
+}
+
+// listNodes returns the IDs of all SMD node components.
+func (r *Resolver) listNodes(ctx context.Context) (map[string]struct{}, error) {
+	nodes := make(map[string]struct{})
+	for offset := 0; ; {
+		page, err := r.smd.ListComponents(ctx, smdclient.ComponentListOptions{
+			Type:   smdNodeType,
+			Fields: "id",
+			Limit:  r.pageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("page at offset %d: %w", offset, err)
+		}
+		if page == nil {
+			break
+		}
+		for _, item := range page.Items {
+			nodes[item.Spec.ID] = struct{}{}
+		}
+
+		offset += len(page.Items)
+		if len(page.Items) < r.pageSize || offset >= page.Metadata.Total {
+			break
+		}
+	}
+	return nodes, nil
+}
+
+// nodeAddresses maps every IP address SMD records for a node to its xname.
+func (r *Resolver) nodeAddresses(ctx context.Context, nodes map[string]struct{}) (map[string]string, error) {
+	byIP := make(map[string]string)
+	for offset := 0; ; {
+		page, err := r.smd.ListEthernetInterfaces(ctx, smdclient.InterfaceListOptions{
+			Limit:  r.pageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("page at offset %d: %w", offset, err)
+		}
+		if page == nil {
+			break
+		}
+		for _, item := range page.Items {
+			if _, ok := nodes[item.Spec.ComponentID]; !ok {
+				continue
+			}
+			for _, address := range powersmd.ParseIPAddrs(item.Spec.IPAddrs) {
+				byIP[address] = item.Spec.ComponentID
+			}
+		}
+
+		offset += len(page.Items)
+		if len(page.Items) < r.pageSize || offset >= page.Metadata.Total {
+			break
+		}
+	}
+	return byIP, nil
+}
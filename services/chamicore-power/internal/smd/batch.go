@@ -0,0 +1,157 @@
+package smd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+const batchPatchTimeout = 30 * time.Second
+
+// patchBatcher collects component PATCHes issued close together and sends
+// them as one batch with bounded concurrency, so a large transition does not
+// fan out one SMD request per completing task. Patches for the same component
+// within a batch are merged, and batches are sent one at a time in the order
+// they were cut, so a later update never races an earlier one for the same
+// component.
+type patchBatcher struct {
+	client      ComponentClient
+	size        int
+	window      time.Duration
+	concurrency int
+
+	mu      sync.Mutex
+	pending map[string]*pendingPatch
+	order   []string
+	timer   *time.Timer
+	// batches are cut but not yet sent; sending is set while a sender
+	// goroutine drains them.
+	batches [][]*pendingPatch
+	sending bool
+}
+
+type pendingPatch struct {
+	id   string
+	req  types.PatchComponentRequest
+	done chan struct{}
+	err  error
+}
+
+func newPatchBatcher(client ComponentClient, size int, window time.Duration, concurrency int) *patchBatcher {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &patchBatcher{
+		client:      client,
+		size:        size,
+		window:      window,
+		concurrency: concurrency,
+		pending:     make(map[string]*pendingPatch),
+	}
+}
+
+// patch queues one component PATCH and waits until its batch is sent.
+func (b *patchBatcher) patch(ctx context.Context, id string, req types.PatchComponentRequest) error {
+	b.mu.Lock()
+	item, ok := b.pending[id]
+	if ok {
+		mergePatch(&item.req, req)
+	} else {
+		item = &pendingPatch{id: id, req: req, done: make(chan struct{})}
+		b.pending[id] = item
+		b.order = append(b.order, id)
+	}
+
+	if len(b.order) >= b.size {
+		b.cutLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flush)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-item.done:
+		return item.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *patchBatcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cutLocked()
+}
+
+// cutLocked moves the pending patches into a new batch at the end of the send
+// queue and starts the sender if it is idle. Called with mu held.
+func (b *patchBatcher) cutLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.order) == 0 {
+		return
+	}
+	ready := make([]*pendingPatch, 0, len(b.order))
+	for _, id := range b.order {
+		ready = append(ready, b.pending[id])
+	}
+	b.pending = make(map[string]*pendingPatch)
+	b.order = nil
+
+	b.batches = append(b.batches, ready)
+	if !b.sending {
+		b.sending = true
+		go b.drain()
+	}
+}
+
+// drain sends queued batches in order until the queue is empty.
+func (b *patchBatcher) drain() {
+	for {
+		b.mu.Lock()
+		if len(b.batches) == 0 {
+			b.sending = false
+			b.mu.Unlock()
+			return
+		}
+		batch := b.batches[0]
+		b.batches[0] = nil
+		b.batches = b.batches[1:]
+		b.mu.Unlock()
+
+		b.send(batch)
+	}
+}
+
+func (b *patchBatcher) send(batch []*pendingPatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), batchPatchTimeout)
+	defer cancel()
+
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+	for _, item := range batch {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *pendingPatch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, item.err = b.client.PatchComponent(ctx, item.id, item.req)
+			close(item.done)
+		}(item)
+	}
+	wg.Wait()
+}
+
+// mergePatch applies the set fields of next over into.
+func mergePatch(into *types.PatchComponentRequest, next types.PatchComponentRequest) {
+	if next.State != nil {
+		into.State = next.State
+	}
+	if next.Flag != nil {
+		into.Flag = next.Flag
+	}
+}
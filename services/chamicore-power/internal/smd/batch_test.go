@@ -0,0 +1,160 @@
+package smd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+func TestUpdater_BatchesPatchesWithBoundedConcurrency(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			calls.Add(1)
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				observed := maxInFlight.Load()
+				if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+	updater := NewUpdater(client, WithPatchBatching(10, time.Second, 2))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- updater.UpdateNodePowerState(context.Background(), fmt.Sprintf("node-%d", i), "On")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	// A full batch is sent without waiting for the one-second window.
+	assert.Equal(t, int32(10), calls.Load())
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestUpdater_BatchMergesPatchesForSameNode(t *testing.T) {
+	var mu sync.Mutex
+	var patches []smdtypes.PatchComponentRequest
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			mu.Lock()
+			defer mu.Unlock()
+			patches = append(patches, req)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+	updater := NewUpdater(client, WithPatchBatching(10, 20*time.Millisecond, 4))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, updater.MarkNodeTransitioning(context.Background(), "node-1", "On"))
+	}()
+	time.Sleep(2 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, updater.UpdateNodePowerState(context.Background(), "node-1", "On"))
+	}()
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, patches, 1)
+	require.NotNil(t, patches[0].State)
+	assert.Equal(t, "Ready", *patches[0].State)
+	require.NotNil(t, patches[0].Flag)
+	assert.Equal(t, "OK", *patches[0].Flag)
+}
+
+func TestUpdater_BatchReturnsPerNodeErrors(t *testing.T) {
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			if id == "node-bad" {
+				return nil, fmt.Errorf("smd unavailable")
+			}
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+	updater := NewUpdater(client, WithPatchBatching(2, time.Second, 2))
+
+	var wg sync.WaitGroup
+	var goodErr, badErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		goodErr = updater.UpdateNodePowerState(context.Background(), "node-good", "Off")
+	}()
+	go func() {
+		defer wg.Done()
+		badErr = updater.UpdateNodePowerState(context.Background(), "node-bad", "Off")
+	}()
+	wg.Wait()
+
+	require.NoError(t, goodErr)
+	require.Error(t, badErr)
+	assert.Contains(t, badErr.Error(), "smd unavailable")
+}
+
+func TestUpdater_BatchesAreSentInOrder(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var sent []string
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			if id == "node-1a" {
+				<-release
+			}
+			mu.Lock()
+			sent = append(sent, id)
+			mu.Unlock()
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+	updater := NewUpdater(client, WithPatchBatching(2, time.Second, 2))
+
+	var wg sync.WaitGroup
+	update := func(id string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, updater.UpdateNodePowerState(context.Background(), id, "On"))
+		}()
+		time.Sleep(2 * time.Millisecond)
+	}
+	// The first batch stalls on node-1a while two more batches are cut.
+	for _, id := range []string{"node-1a", "node-1b", "node-2a", "node-2b", "node-3a", "node-3b"} {
+		update(id)
+	}
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, sent, 6)
+	assert.ElementsMatch(t, []string{"node-1a", "node-1b"}, sent[:2])
+	assert.ElementsMatch(t, []string{"node-2a", "node-2b"}, sent[2:4])
+	assert.ElementsMatch(t, []string{"node-3a", "node-3b"}, sent[4:])
+}
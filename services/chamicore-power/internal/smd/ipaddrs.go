@@ -0,0 +1,34 @@
+package smd
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ParseIPAddrs returns the string entries of an SMD ethernet interface
+// ipAddrs list, trimmed. Non-string entries are skipped and an undecodable
+// list yields nil.
+func ParseIPAddrs(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var addresses []string
+	if err := json.Unmarshal(raw, &addresses); err != nil {
+		var generic []any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return nil
+		}
+		addresses = make([]string, 0, len(generic))
+		for _, value := range generic {
+			if address, ok := value.(string); ok {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	for i, address := range addresses {
+		addresses[i] = strings.TrimSpace(address)
+	}
+	return addresses
+}
@@ -0,0 +1,15 @@
+package smd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPAddrs(t *testing.T) {
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ParseIPAddrs(json.RawMessage(`[" 10.0.0.1", "10.0.0.2"]`)))
+	assert.Equal(t, []string{"10.0.0.1"}, ParseIPAddrs(json.RawMessage(`["10.0.0.1", {"IPAddress": "10.0.0.2"}, 3]`)))
+	assert.Nil(t, ParseIPAddrs(nil))
+	assert.Nil(t, ParseIPAddrs(json.RawMessage(`{"not": "a list"}`)))
+}
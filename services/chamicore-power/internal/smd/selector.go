@@ -0,0 +1,79 @@
+package smd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
+	"git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+const (
+	nodeComponentType = "Node"
+	selectorPageSize  = 1000
+)
+
+// ComponentLister lists SMD components.
+type ComponentLister interface {
+	ListComponents(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[types.Component], error)
+}
+
+// SelectNodes returns the IDs of SMD nodes matching selector. Role and state
+// are filtered by SMD; the remaining attributes are matched here.
+func SelectNodes(ctx context.Context, client ComponentLister, selector model.NodeSelector) ([]string, error) {
+	nodes := make([]string, 0)
+	for offset := 0; ; {
+		page, err := client.ListComponents(ctx, smdclient.ComponentListOptions{
+			Type:   nodeComponentType,
+			Role:   strings.TrimSpace(selector.Role),
+			State:  strings.TrimSpace(selector.State),
+			Limit:  selectorPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing SMD nodes at offset %d: %w", offset, err)
+		}
+		if page == nil {
+			break
+		}
+		for _, item := range page.Items {
+			if MatchesSelector(item.Spec, selector) {
+				nodes = append(nodes, item.Spec.ID)
+			}
+		}
+
+		offset += len(page.Items)
+		if len(page.Items) < selectorPageSize || offset >= page.Metadata.Total {
+			break
+		}
+	}
+	return nodes, nil
+}
+
+// MatchesSelector reports whether a component satisfies every set selector
+// attribute. Attribute values compare case-insensitively.
+func MatchesSelector(component types.Component, selector model.NodeSelector) bool {
+	if !strings.EqualFold(component.Type, nodeComponentType) {
+		return false
+	}
+	for _, check := range []struct{ want, got string }{
+		{selector.Role, component.Role},
+		{selector.SubRole, component.SubRole},
+		{selector.Arch, component.Arch},
+		{selector.State, component.State},
+	} {
+		want := strings.TrimSpace(check.want)
+		if want != "" && !strings.EqualFold(want, strings.TrimSpace(check.got)) {
+			return false
+		}
+	}
+	if cabinet := strings.ToLower(strings.TrimSpace(selector.Cabinet)); cabinet != "" {
+		if !strings.HasPrefix(strings.ToLower(component.ID), cabinet+"c") {
+			return false
+		}
+	}
+	return true
+}
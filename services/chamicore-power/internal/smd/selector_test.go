@@ -0,0 +1,52 @@
+package smd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+type mockComponentLister struct {
+	opts  smdclient.ComponentListOptions
+	items []smdtypes.Component
+}
+
+func (m *mockComponentLister) ListComponents(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error) {
+	m.opts = opts
+	list := &httputil.ResourceList[smdtypes.Component]{Metadata: httputil.ListMetadata{Total: len(m.items)}}
+	for _, item := range m.items {
+		list.Items = append(list.Items, httputil.Resource[smdtypes.Component]{Spec: item})
+	}
+	return list, nil
+}
+
+func TestSelectNodes(t *testing.T) {
+	lister := &mockComponentLister{items: []smdtypes.Component{
+		{ID: "x1000c0s0b0n0", Type: "Node", Role: "Compute", SubRole: "Worker", Arch: "X86", State: "Ready"},
+		{ID: "x1000c0s1b0n0", Type: "Node", Role: "Compute", Arch: "ARM", State: "Ready"},
+		{ID: "x10000c0s0b0n0", Type: "Node", Role: "Compute", Arch: "X86", State: "Ready"},
+		{ID: "x1001c0s0b0n0", Type: "Node", Role: "Compute", Arch: "X86", State: "Off"},
+		{ID: "x1000c0s0b0", Type: "NodeBMC", Arch: "X86"},
+	}}
+
+	nodes, err := SelectNodes(context.Background(), lister, model.NodeSelector{Role: "Compute", Arch: "x86", Cabinet: "x1000"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x1000c0s0b0n0"}, nodes)
+	assert.Equal(t, "Node", lister.opts.Type)
+	assert.Equal(t, "Compute", lister.opts.Role)
+
+	nodes, err = SelectNodes(context.Background(), lister, model.NodeSelector{SubRole: "worker"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x1000c0s0b0n0"}, nodes)
+
+	nodes, err = SelectNodes(context.Background(), lister, model.NodeSelector{State: "off"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x1001c0s0b0n0"}, nodes)
+}
@@ -0,0 +1,120 @@
+package smd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Power outcomes that map onto SMD component updates.
+const (
+	// OutcomeOn is a verified transition to powered on.
+	OutcomeOn = "On"
+	// OutcomeOff is a verified transition to powered off.
+	OutcomeOff = "Off"
+	// OutcomeFailed is a power action that failed or could not be verified.
+	OutcomeFailed = "Failed"
+	// OutcomePoweringOn is an On or restart action still in progress.
+	OutcomePoweringOn = "PoweringOn"
+	// OutcomePoweringOff is an Off or shutdown action still in progress.
+	OutcomePoweringOff = "PoweringOff"
+)
+
+var knownOutcomes = []string{OutcomeOn, OutcomeOff, OutcomeFailed, OutcomePoweringOn, OutcomePoweringOff}
+
+// ComponentUpdate is the SMD state and flag written for one outcome.
+// Empty fields leave the component value unchanged.
+type ComponentUpdate struct {
+	State string
+	Flag  string
+}
+
+// IsZero reports whether the update changes nothing.
+func (u ComponentUpdate) IsZero() bool {
+	return u.State == "" && u.Flag == ""
+}
+
+// StateMap maps power outcomes to SMD component updates. Outcomes without an
+// entry are not written to SMD.
+type StateMap map[string]ComponentUpdate
+
+// DefaultStateMap returns the built-in outcome mapping.
+func DefaultStateMap() StateMap {
+	return StateMap{
+		OutcomeOn:         {State: "Ready", Flag: "OK"},
+		OutcomeOff:        {State: "Off", Flag: "OK"},
+		OutcomeFailed:     {Flag: "Alert"},
+		OutcomePoweringOn: {State: "On"},
+	}
+}
+
+// ParseStateMap applies comma-separated overrides to the default mapping.
+// Each entry is `outcome=state[:flag]`; an empty state keeps the current
+// component state, and `outcome=` or `outcome=-` disables the outcome,
+// e.g. `Failed=Standby:Alert,PoweringOn=-`.
+func ParseStateMap(raw string) (StateMap, error) {
+	states := DefaultStateMap()
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid SMD state map entry %q: expected outcome=state[:flag]", entry)
+		}
+		outcome, known := canonicalOutcome(key)
+		if !known {
+			return nil, fmt.Errorf(
+				"invalid SMD state map entry %q: unknown outcome %q, expected one of %s",
+				entry,
+				strings.TrimSpace(key),
+				strings.Join(knownOutcomes, ", "),
+			)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" || value == "-" {
+			delete(states, outcome)
+			continue
+		}
+		state, flag, _ := strings.Cut(value, ":")
+		update := ComponentUpdate{State: strings.TrimSpace(state), Flag: strings.TrimSpace(flag)}
+		if update.IsZero() {
+			delete(states, outcome)
+			continue
+		}
+		states[outcome] = update
+	}
+	return states, nil
+}
+
+func (m StateMap) lookup(outcome string) (ComponentUpdate, bool) {
+	update, ok := m[outcome]
+	if !ok || update.IsZero() {
+		return ComponentUpdate{}, false
+	}
+	return update, true
+}
+
+func canonicalOutcome(outcome string) (string, bool) {
+	outcome = strings.TrimSpace(outcome)
+	for _, known := range knownOutcomes {
+		if strings.EqualFold(known, outcome) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// outcomeForOperation returns the in-progress outcome of a power operation.
+func outcomeForOperation(operation string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(operation)) {
+	case "on", "gracefulrestart", "forcerestart":
+		return OutcomePoweringOn, true
+	case "forceoff", "gracefulshutdown":
+		return OutcomePoweringOff, true
+	default:
+		return "", false
+	}
+}
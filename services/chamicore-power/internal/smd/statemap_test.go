@@ -0,0 +1,35 @@
+package smd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateMap_DefaultsWhenEmpty(t *testing.T) {
+	states, err := ParseStateMap("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStateMap(), states)
+}
+
+func TestParseStateMap_Overrides(t *testing.T) {
+	states, err := ParseStateMap(" failed=Standby:Alert, PoweringOn=-, poweringoff=:Warning ,Off=")
+	require.NoError(t, err)
+
+	assert.Equal(t, ComponentUpdate{State: "Standby", Flag: "Alert"}, states[OutcomeFailed])
+	assert.Equal(t, ComponentUpdate{Flag: "Warning"}, states[OutcomePoweringOff])
+	assert.Equal(t, ComponentUpdate{State: "Ready", Flag: "OK"}, states[OutcomeOn])
+	assert.NotContains(t, states, OutcomePoweringOn)
+	assert.NotContains(t, states, OutcomeOff)
+}
+
+func TestParseStateMap_RejectsInvalidEntries(t *testing.T) {
+	_, err := ParseStateMap("Ready")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected outcome=state[:flag]")
+
+	_, err = ParseStateMap("Halted=Off")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown outcome")
+}
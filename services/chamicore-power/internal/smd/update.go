@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"git.cscs.ch/openchami/chamicore-lib/httputil"
 	"git.cscs.ch/openchami/chamicore-smd/pkg/types"
@@ -18,22 +19,77 @@ var (
 
 // ComponentClient defines the SMD API calls needed for state updates.
 type ComponentClient interface {
+	GetComponent(ctx context.Context, id string) (*httputil.Resource[types.Component], error)
 	PatchComponent(ctx context.Context, id string, req types.PatchComponentRequest) (*httputil.Resource[types.Component], error)
 }
 
-// Updater patches node state in SMD after successful power verification.
+// Updater patches node state in SMD from power task outcomes.
 type Updater struct {
-	client ComponentClient
+	client  ComponentClient
+	states  StateMap
+	batcher *patchBatcher
+}
+
+// Option configures an Updater.
+type Option func(*Updater)
+
+// WithStateMap replaces the outcome-to-SMD mapping.
+func WithStateMap(states StateMap) Option {
+	return func(u *Updater) {
+		if states != nil {
+			u.states = states
+		}
+	}
+}
+
+// WithPatchBatching collects PATCHes issued within window into batches of up
+// to size components, sent with at most concurrency requests in flight.
+// A size below 2 disables batching.
+func WithPatchBatching(size int, window time.Duration, concurrency int) Option {
+	return func(u *Updater) {
+		if size < 2 || window <= 0 || u.client == nil {
+			u.batcher = nil
+			return
+		}
+		u.batcher = newPatchBatcher(u.client, size, window, concurrency)
+	}
 }
 
 // NewUpdater creates an SMD updater from an SMD client.
-func NewUpdater(client ComponentClient) *Updater {
-	return &Updater{client: client}
+func NewUpdater(client ComponentClient, opts ...Option) *Updater {
+	u := &Updater{client: client, states: DefaultStateMap()}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
-// UpdateNodePowerState maps a final Redfish state to an SMD component state and
-// patches the component in SMD.
+// UpdateNodePowerState maps a verified final Redfish state to an SMD component
+// update and patches the component in SMD.
 func (u *Updater) UpdateNodePowerState(ctx context.Context, nodeID, powerState string) error {
+	outcome, err := outcomeForPowerState(powerState)
+	if err != nil {
+		return err
+	}
+	return u.apply(ctx, nodeID, outcome)
+}
+
+// MarkNodePowerFailure flags a node whose power action failed or could not be
+// verified. SMD has no field for the reason; it stays on the task record.
+func (u *Updater) MarkNodePowerFailure(ctx context.Context, nodeID string) error {
+	return u.apply(ctx, nodeID, OutcomeFailed)
+}
+
+// MarkNodeTransitioning records that a power operation is in progress on a node.
+func (u *Updater) MarkNodeTransitioning(ctx context.Context, nodeID, operation string) error {
+	outcome, ok := outcomeForOperation(operation)
+	if !ok {
+		return nil
+	}
+	return u.apply(ctx, nodeID, outcome)
+}
+
+func (u *Updater) apply(ctx context.Context, nodeID, outcome string) error {
 	if u == nil || u.client == nil {
 		return fmt.Errorf("smd updater is not configured")
 	}
@@ -43,27 +99,81 @@ func (u *Updater) UpdateNodePowerState(ctx context.Context, nodeID, powerState s
 		return fmt.Errorf("node id is required")
 	}
 
-	targetState, err := componentStateForPowerState(powerState)
-	if err != nil {
-		return err
+	update, ok := u.states.lookup(outcome)
+	if !ok {
+		return nil
 	}
 
-	req := types.PatchComponentRequest{
-		State: &targetState,
+	req := types.PatchComponentRequest{}
+	if update.State != "" {
+		req.State = &update.State
 	}
-	if _, err := u.client.PatchComponent(ctx, componentID, req); err != nil {
-		return fmt.Errorf("patching SMD component %q state to %q: %w", componentID, targetState, err)
+	if update.Flag != "" {
+		req.Flag = &update.Flag
+	}
+	if err := u.patch(ctx, componentID, req); err != nil {
+		return fmt.Errorf("patching SMD component %q for %s outcome: %w", componentID, outcome, err)
 	}
 
 	return nil
 }
 
-func componentStateForPowerState(powerState string) (string, error) {
+func (u *Updater) patch(ctx context.Context, componentID string, req types.PatchComponentRequest) error {
+	if u.batcher != nil {
+		return u.batcher.patch(ctx, componentID, req)
+	}
+	_, err := u.client.PatchComponent(ctx, componentID, req)
+	return err
+}
+
+// MarkNodeBooting sets a powered node's SMD state to On so that a later Ready
+// can only come from the node's own heartbeat.
+func (u *Updater) MarkNodeBooting(ctx context.Context, nodeID string) error {
+	if u == nil || u.client == nil {
+		return fmt.Errorf("smd updater is not configured")
+	}
+
+	componentID := strings.TrimSpace(nodeID)
+	if componentID == "" {
+		return fmt.Errorf("node id is required")
+	}
+
+	state := "On"
+	if err := u.patch(ctx, componentID, types.PatchComponentRequest{State: &state}); err != nil {
+		return fmt.Errorf("patching SMD component %q state to %q: %w", componentID, state, err)
+	}
+
+	return nil
+}
+
+// ReadNodeState returns the node's current SMD component state.
+func (u *Updater) ReadNodeState(ctx context.Context, nodeID string) (string, error) {
+	if u == nil || u.client == nil {
+		return "", fmt.Errorf("smd updater is not configured")
+	}
+
+	componentID := strings.TrimSpace(nodeID)
+	if componentID == "" {
+		return "", fmt.Errorf("node id is required")
+	}
+
+	resource, err := u.client.GetComponent(ctx, componentID)
+	if err != nil {
+		return "", fmt.Errorf("reading SMD component %q: %w", componentID, err)
+	}
+	if resource == nil {
+		return "", fmt.Errorf("reading SMD component %q: empty response", componentID)
+	}
+
+	return strings.TrimSpace(resource.Spec.State), nil
+}
+
+func outcomeForPowerState(powerState string) (string, error) {
 	switch strings.ToLower(strings.TrimSpace(powerState)) {
 	case "on":
-		return "Ready", nil
+		return OutcomeOn, nil
 	case "off":
-		return "Off", nil
+		return OutcomeOff, nil
 	default:
 		return "", fmt.Errorf("%w: %q", ErrUnsupportedPowerState, strings.TrimSpace(powerState))
 	}
@@ -13,9 +13,14 @@ import (
 )
 
 type mockComponentClient struct {
+	getComponentFn   func(ctx context.Context, id string) (*httputil.Resource[smdtypes.Component], error)
 	patchComponentFn func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error)
 }
 
+func (m *mockComponentClient) GetComponent(ctx context.Context, id string) (*httputil.Resource[smdtypes.Component], error) {
+	return m.getComponentFn(ctx, id)
+}
+
 func (m *mockComponentClient) PatchComponent(
 	ctx context.Context,
 	id string,
@@ -85,3 +90,87 @@ func TestUpdater_UpdateNodePowerState_NoClient(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not configured")
 }
+
+func TestUpdater_MarkNodeBooting_PatchesOn(t *testing.T) {
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			require.Equal(t, "node-1", id)
+			require.NotNil(t, req.State)
+			assert.Equal(t, "On", *req.State)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+
+	updater := NewUpdater(client)
+	require.NoError(t, updater.MarkNodeBooting(context.Background(), " node-1 "))
+}
+
+func TestUpdater_ReadNodeState(t *testing.T) {
+	client := &mockComponentClient{
+		getComponentFn: func(ctx context.Context, id string) (*httputil.Resource[smdtypes.Component], error) {
+			require.Equal(t, "node-1", id)
+			return &httputil.Resource[smdtypes.Component]{Spec: smdtypes.Component{ID: id, State: "Ready"}}, nil
+		},
+	}
+
+	updater := NewUpdater(client)
+	state, err := updater.ReadNodeState(context.Background(), "node-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ready", state)
+}
+
+func TestUpdater_MarkNodePowerFailure_SetsFlag(t *testing.T) {
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			require.Equal(t, "node-1", id)
+			assert.Nil(t, req.State)
+			require.NotNil(t, req.Flag)
+			assert.Equal(t, "Alert", *req.Flag)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+
+	updater := NewUpdater(client)
+	require.NoError(t, updater.MarkNodePowerFailure(context.Background(), "node-1"))
+}
+
+func TestUpdater_UsesConfiguredStateMap(t *testing.T) {
+	var patched []smdtypes.PatchComponentRequest
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			patched = append(patched, req)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+	states, err := ParseStateMap("Failed=Standby:Alert,PoweringOn=-")
+	require.NoError(t, err)
+
+	updater := NewUpdater(client, WithStateMap(states))
+	require.NoError(t, updater.MarkNodeTransitioning(context.Background(), "node-1", "ForceRestart"))
+	require.NoError(t, updater.MarkNodePowerFailure(context.Background(), "node-1"))
+
+	require.Len(t, patched, 1)
+	require.NotNil(t, patched[0].State)
+	assert.Equal(t, "Standby", *patched[0].State)
+	require.NotNil(t, patched[0].Flag)
+	assert.Equal(t, "Alert", *patched[0].Flag)
+}
+
+func TestUpdater_MarkNodeTransitioning(t *testing.T) {
+	var states []string
+	client := &mockComponentClient{
+		patchComponentFn: func(ctx context.Context, id string, req smdtypes.PatchComponentRequest) (*httputil.Resource[smdtypes.Component], error) {
+			require.NotNil(t, req.State)
+			states = append(states, *req.State)
+			return &httputil.Resource[smdtypes.Component]{}, nil
+		},
+	}
+
+	updater := NewUpdater(client)
+	require.NoError(t, updater.MarkNodeTransitioning(context.Background(), "node-1", "GracefulRestart"))
+	// No default mapping for powering off, and NMI has no transitional state.
+	require.NoError(t, updater.MarkNodeTransitioning(context.Background(), "node-1", "ForceOff"))
+	require.NoError(t, updater.MarkNodeTransitioning(context.Background(), "node-1", "Nmi"))
+
+	assert.Equal(t, []string{"On"}, states)
+}
@@ -10,12 +10,14 @@ import (
 
 	"git.cscs.ch/openchami/chamicore-lib/events"
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
 )
 
 const (
-	transitionLifecycleEventType  = "chamicore.power.transitions.lifecycle"
-	transitionTaskResultEventType = "chamicore.power.transitions.task-result"
-	transitionEventSource         = "chamicore-power"
+	transitionLifecycleEventType  = types.EventTypeTransitionLifecycle
+	transitionTaskResultEventType = types.EventTypeTransitionTaskResult
+	transitionApprovalEventType   = types.EventTypeTransitionApproval
+	transitionEventSource         = types.EventSource
 )
 
 var (
@@ -23,57 +25,39 @@ var (
 	marshalTransitionEvent    = json.Marshal
 )
 
-type transitionLifecycleEventData struct {
-	TransitionID string                  `json:"transitionId"`
-	Snapshot     transitionEventSnapshot `json:"snapshot"`
-}
+func newTransitionLifecycleEvent(transition engine.Transition) (events.Event, error) {
+	transitionID := strings.TrimSpace(transition.ID)
+	if transitionID == "" {
+		return events.Event{}, fmt.Errorf("transition id is required")
+	}
 
-type transitionTaskResultEventData struct {
-	TransitionID string                      `json:"transitionId"`
-	NodeID       string                      `json:"nodeId"`
-	TaskID       string                      `json:"taskId"`
-	Snapshot     transitionTaskEventSnapshot `json:"snapshot"`
-}
+	eventID, err := newTransitionEventID()
+	if err != nil {
+		return events.Event{}, err
+	}
 
-type transitionEventSnapshot struct {
-	ID           string     `json:"id"`
-	RequestID    string     `json:"requestId,omitempty"`
-	Operation    string     `json:"operation"`
-	State        string     `json:"state"`
-	RequestedBy  string     `json:"requestedBy,omitempty"`
-	DryRun       bool       `json:"dryRun"`
-	TargetCount  int        `json:"targetCount"`
-	SuccessCount int        `json:"successCount"`
-	FailureCount int        `json:"failureCount"`
-	QueuedAt     time.Time  `json:"queuedAt"`
-	StartedAt    *time.Time `json:"startedAt,omitempty"`
-	CompletedAt  *time.Time `json:"completedAt,omitempty"`
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
-}
+	payload := types.TransitionLifecycleEvent{
+		SchemaVersion: types.TransitionEventSchemaVersion,
+		TransitionID:  transitionID,
+		Snapshot:      newTransitionEventSnapshot(transitionID, transition),
+	}
 
-type transitionTaskEventSnapshot struct {
-	ID                 string     `json:"id"`
-	TransitionID       string     `json:"transitionId"`
-	NodeID             string     `json:"nodeId"`
-	BMCID              string     `json:"bmcId,omitempty"`
-	BMCEndpoint        string     `json:"bmcEndpoint,omitempty"`
-	CredentialID       string     `json:"credentialId,omitempty"`
-	Operation          string     `json:"operation"`
-	State              string     `json:"state"`
-	DryRun             bool       `json:"dryRun"`
-	AttemptCount       int        `json:"attemptCount"`
-	FinalPowerState    string     `json:"finalPowerState,omitempty"`
-	ErrorDetail        string     `json:"errorDetail,omitempty"`
-	QueuedAt           time.Time  `json:"queuedAt"`
-	StartedAt          *time.Time `json:"startedAt,omitempty"`
-	CompletedAt        *time.Time `json:"completedAt,omitempty"`
-	CreatedAt          time.Time  `json:"createdAt"`
-	UpdatedAt          time.Time  `json:"updatedAt"`
-	InsecureSkipVerify bool       `json:"insecureSkipVerify,omitempty"`
+	data, err := marshalTransitionEvent(payload)
+	if err != nil {
+		return events.Event{}, fmt.Errorf("marshaling transition lifecycle payload: %w", err)
+	}
+
+	return events.Event{
+		ID:              eventID,
+		Source:          transitionEventSource,
+		Type:            transitionLifecycleEventType,
+		Subject:         transitionID,
+		DataContentType: events.JSONDataContentType,
+		Data:            data,
+	}, nil
 }
 
-func newTransitionLifecycleEvent(transition engine.Transition) (events.Event, error) {
+func newTransitionApprovalEvent(transition engine.Transition, decision engine.ApprovalDecision) (events.Event, error) {
 	transitionID := strings.TrimSpace(transition.ID)
 	if transitionID == "" {
 		return events.Event{}, fmt.Errorf("transition id is required")
@@ -84,41 +68,55 @@ func newTransitionLifecycleEvent(transition engine.Transition) (events.Event, er
 		return events.Event{}, err
 	}
 
-	payload := transitionLifecycleEventData{
-		TransitionID: transitionID,
-		Snapshot: transitionEventSnapshot{
-			ID:           transitionID,
-			RequestID:    strings.TrimSpace(transition.RequestID),
-			Operation:    strings.TrimSpace(transition.Operation),
-			State:        strings.TrimSpace(transition.State),
-			RequestedBy:  strings.TrimSpace(transition.RequestedBy),
-			DryRun:       transition.DryRun,
-			TargetCount:  transition.TargetCount,
-			SuccessCount: transition.SuccessCount,
-			FailureCount: transition.FailureCount,
-			QueuedAt:     transition.QueuedAt.UTC(),
-			StartedAt:    utcTimePtr(transition.StartedAt),
-			CompletedAt:  utcTimePtr(transition.CompletedAt),
-			CreatedAt:    transition.CreatedAt.UTC(),
-			UpdatedAt:    transition.UpdatedAt.UTC(),
-		},
+	payload := types.TransitionApprovalEvent{
+		SchemaVersion: types.TransitionEventSchemaVersion,
+		TransitionID:  transitionID,
+		Action:        strings.TrimSpace(decision.Action),
+		Principal:     strings.TrimSpace(decision.Principal),
+		Reason:        strings.TrimSpace(decision.Reason),
+		DecidedAt:     decision.DecidedAt.UTC(),
+		Snapshot:      newTransitionEventSnapshot(transitionID, transition),
 	}
 
 	data, err := marshalTransitionEvent(payload)
 	if err != nil {
-		return events.Event{}, fmt.Errorf("marshaling transition lifecycle payload: %w", err)
+		return events.Event{}, fmt.Errorf("marshaling transition approval payload: %w", err)
 	}
 
 	return events.Event{
 		ID:              eventID,
 		Source:          transitionEventSource,
-		Type:            transitionLifecycleEventType,
+		Type:            transitionApprovalEventType,
 		Subject:         transitionID,
 		DataContentType: events.JSONDataContentType,
 		Data:            data,
 	}, nil
 }
 
+func newTransitionEventSnapshot(transitionID string, transition engine.Transition) types.TransitionEventSnapshot {
+	return types.TransitionEventSnapshot{
+		ID:                transitionID,
+		RequestID:         strings.TrimSpace(transition.RequestID),
+		Operation:         strings.TrimSpace(transition.Operation),
+		State:             strings.TrimSpace(transition.State),
+		RequestedBy:       strings.TrimSpace(transition.RequestedBy),
+		DryRun:            transition.DryRun,
+		TargetCount:       transition.TargetCount,
+		SuccessCount:      transition.SuccessCount,
+		FailureCount:      transition.FailureCount,
+		TemplateName:      strings.TrimSpace(transition.TemplateName),
+		TemplateVersion:   transition.TemplateVersion,
+		ApprovalReason:    strings.TrimSpace(transition.ApprovalReason),
+		ApprovalExpiresAt: utcTimePtr(transition.ApprovalExpiresAt),
+		ParentID:          strings.TrimSpace(transition.ParentID),
+		QueuedAt:          transition.QueuedAt.UTC(),
+		StartedAt:         utcTimePtr(transition.StartedAt),
+		CompletedAt:       utcTimePtr(transition.CompletedAt),
+		CreatedAt:         transition.CreatedAt.UTC(),
+		UpdatedAt:         transition.UpdatedAt.UTC(),
+	}
+}
+
 func newTransitionTaskResultEvent(task engine.Task) (events.Event, error) {
 	taskID := strings.TrimSpace(task.ID)
 	if taskID == "" {
@@ -140,11 +138,12 @@ func newTransitionTaskResultEvent(task engine.Task) (events.Event, error) {
 		return events.Event{}, err
 	}
 
-	payload := transitionTaskResultEventData{
-		TransitionID: transitionID,
-		NodeID:       nodeID,
-		TaskID:       taskID,
-		Snapshot: transitionTaskEventSnapshot{
+	payload := types.TransitionTaskResultEvent{
+		SchemaVersion: types.TransitionEventSchemaVersion,
+		TransitionID:  transitionID,
+		NodeID:        nodeID,
+		TaskID:        taskID,
+		Snapshot: types.TransitionTaskEventSnapshot{
 			ID:                 taskID,
 			TransitionID:       transitionID,
 			NodeID:             nodeID,
@@ -163,6 +162,16 @@ func newTransitionTaskResultEvent(task engine.Task) (events.Event, error) {
 			CreatedAt:          task.CreatedAt.UTC(),
 			UpdatedAt:          task.UpdatedAt.UTC(),
 			InsecureSkipVerify: task.InsecureSkipVerify,
+			BootTarget:         strings.TrimSpace(task.BootTarget),
+			BootWaitReady:      task.BootWaitReady,
+			BootStage:          strings.TrimSpace(task.BootStage),
+			BootStages:         transitionTaskBootStages(task.BootStages),
+			PowerCapWatts:      task.PowerCapWatts,
+			BootOverrideTarget: strings.TrimSpace(task.BootOverrideTarget),
+			BootOverrideMode:   strings.TrimSpace(task.BootOverrideMode),
+			MediaImage:         strings.TrimSpace(task.MediaImage),
+			ResetOperation:     strings.TrimSpace(task.ResetOperation),
+			Escalated:          task.Escalated,
 		},
 	}
 
@@ -181,6 +190,17 @@ func newTransitionTaskResultEvent(task engine.Task) (events.Event, error) {
 	}, nil
 }
 
+func transitionTaskBootStages(stages []engine.BootStage) []types.BootStage {
+	if len(stages) == 0 {
+		return nil
+	}
+	out := make([]types.BootStage, 0, len(stages))
+	for _, stage := range stages {
+		out = append(out, types.BootStage{Stage: stage.Stage, ObservedAt: stage.ObservedAt.UTC()})
+	}
+	return out
+}
+
 func newTransitionEventID() (string, error) {
 	var id [16]byte
 	if _, err := readTransitionEventRandom(id[:]); err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
 )
 
 func TestNewTransitionLifecycleEvent(t *testing.T) {
@@ -39,14 +40,53 @@ func TestNewTransitionLifecycleEvent(t *testing.T) {
 	assert.Equal(t, transitionEventSource, event.Source)
 	assert.Equal(t, "transition-1", event.Subject)
 
-	var payload transitionLifecycleEventData
+	var payload types.TransitionLifecycleEvent
 	require.NoError(t, json.Unmarshal(event.Data, &payload))
+	assert.Equal(t, types.TransitionEventSchemaVersion, payload.SchemaVersion)
 	assert.Equal(t, "transition-1", payload.TransitionID)
 	assert.Equal(t, engine.TransitionStateCompleted, payload.Snapshot.State)
 	assert.Equal(t, "On", payload.Snapshot.Operation)
 	assert.Equal(t, 2, payload.Snapshot.SuccessCount)
 }
 
+func TestNewTransitionApprovalEvent(t *testing.T) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(time.Hour)
+
+	event, err := newTransitionApprovalEvent(engine.Transition{
+		ID:                "transition-1",
+		Operation:         "ForceOff",
+		State:             engine.TransitionStatePending,
+		RequestedBy:       "alice",
+		TargetCount:       3,
+		ApprovalReason:    "forces off protected groups storage",
+		ApprovalExpiresAt: &expiresAt,
+		QueuedAt:          now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, engine.ApprovalDecision{
+		Action:    engine.ApprovalActionApproved,
+		Principal: " bob ",
+		Reason:    "maintenance window",
+		DecidedAt: now,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, transitionApprovalEventType, event.Type)
+	assert.Equal(t, "transition-1", event.Subject)
+
+	var payload types.TransitionApprovalEvent
+	require.NoError(t, json.Unmarshal(event.Data, &payload))
+	assert.Equal(t, engine.ApprovalActionApproved, payload.Action)
+	assert.Equal(t, "bob", payload.Principal)
+	assert.Equal(t, "maintenance window", payload.Reason)
+	assert.Equal(t, "forces off protected groups storage", payload.Snapshot.ApprovalReason)
+	require.NotNil(t, payload.Snapshot.ApprovalExpiresAt)
+
+	_, err = newTransitionApprovalEvent(engine.Transition{}, engine.ApprovalDecision{})
+	require.Error(t, err)
+}
+
 func TestNewTransitionTaskResultEvent(t *testing.T) {
 	now := time.Now().UTC()
 	startedAt := now.Add(time.Second)
@@ -77,7 +117,7 @@ func TestNewTransitionTaskResultEvent(t *testing.T) {
 	assert.Equal(t, "node-1", event.Subject)
 	assert.Equal(t, transitionEventSource, event.Source)
 
-	var payload transitionTaskResultEventData
+	var payload types.TransitionTaskResultEvent
 	require.NoError(t, json.Unmarshal(event.Data, &payload))
 	assert.Equal(t, "transition-1", payload.TransitionID)
 	assert.Equal(t, "node-1", payload.NodeID)
@@ -0,0 +1,306 @@
+// Package store provides transition approval persistence for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-lib/events/outbox"
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+)
+
+// DecideTransitionApproval records an approval decision on a pending-approval
+// transition. Approving moves the transition to pending so the runner can
+// enqueue it; rejecting, canceling or expiring ends it and cancels its
+// pending tasks. It returns engine.ErrApprovalNotPending when the transition
+// was already decided.
+func (s *PostgresStore) DecideTransitionApproval(
+	ctx context.Context,
+	decision engine.ApprovalDecision,
+) (engine.Transition, error) {
+	id := strings.TrimSpace(decision.TransitionID)
+	if id == "" {
+		return engine.Transition{}, ErrNotFound
+	}
+	decision.TransitionID = id
+
+	var nextState string
+	switch decision.Action {
+	case engine.ApprovalActionApproved:
+		nextState = engine.TransitionStatePending
+	case engine.ApprovalActionRejected:
+		nextState = engine.TransitionStateRejected
+	case engine.ApprovalActionExpired:
+		nextState = engine.TransitionStateExpired
+	case engine.ApprovalActionCanceled:
+		nextState = engine.TransitionStateCanceled
+	default:
+		return engine.Transition{}, fmt.Errorf("%w: %q", engine.ErrInvalidApprovalAction, decision.Action)
+	}
+	if decision.DecidedAt.IsZero() {
+		decision.DecidedAt = time.Now()
+	}
+	decidedAt := decision.DecidedAt.UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return engine.Transition{}, fmt.Errorf("starting transition approval transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if searchPathErr := setLocalPowerSearchPath(ctx, tx); searchPathErr != nil {
+		return engine.Transition{}, searchPathErr
+	}
+
+	sqlStr, args, err := s.sb.
+		Select("state").
+		From("power.transitions").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return engine.Transition{}, fmt.Errorf("building transition lock query: %w", err)
+	}
+	var state string
+	if scanErr := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&state); scanErr != nil {
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			return engine.Transition{}, ErrNotFound
+		}
+		return engine.Transition{}, fmt.Errorf("locking transition %q: %w", id, scanErr)
+	}
+	if state != engine.TransitionStatePendingApproval {
+		return engine.Transition{}, engine.ErrApprovalNotPending
+	}
+
+	canceledTasks := []engine.Task{}
+	if nextState != engine.TransitionStatePending {
+		canceledTasks, err = s.cancelPendingTasksTx(ctx, tx, id, decision.Action, decidedAt)
+		if err != nil {
+			return engine.Transition{}, err
+		}
+	}
+
+	update := s.sb.
+		Update("power.transitions").
+		Set("state", nextState).
+		Set("updated_at", decidedAt).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + strings.Join(transitionColumns, ", "))
+	if nextState != engine.TransitionStatePending {
+		update = update.
+			Set("failure_count", sq.Expr("failure_count + ?", len(canceledTasks))).
+			Set("completed_at", decidedAt)
+	}
+	sqlStr, args, err = update.ToSql()
+	if err != nil {
+		return engine.Transition{}, fmt.Errorf("building transition approval update query: %w", err)
+	}
+	decided, err := scanTransition(tx.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		return engine.Transition{}, fmt.Errorf("updating transition %q approval state: %w", id, err)
+	}
+
+	if err := s.insertTransitionApprovalTx(ctx, tx, decided, decision); err != nil {
+		return engine.Transition{}, err
+	}
+	lifecycleEvent, err := newTransitionLifecycleEvent(decided)
+	if err != nil {
+		return engine.Transition{}, fmt.Errorf("building transition lifecycle event: %w", err)
+	}
+	if err := outbox.WriteContext(ctx, tx, lifecycleEvent); err != nil {
+		return engine.Transition{}, fmt.Errorf("writing transition lifecycle outbox event: %w", err)
+	}
+	for _, task := range canceledTasks {
+		taskEvent, taskEventErr := newTransitionTaskResultEvent(task)
+		if taskEventErr != nil {
+			return engine.Transition{}, fmt.Errorf("building transition task event for node %q: %w", task.NodeID, taskEventErr)
+		}
+		if writeErr := outbox.WriteContext(ctx, tx, taskEvent); writeErr != nil {
+			return engine.Transition{}, fmt.Errorf("writing transition task outbox event for node %q: %w", task.NodeID, writeErr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return engine.Transition{}, fmt.Errorf("committing transition approval transaction: %w", err)
+	}
+	return decided, nil
+}
+
+// ExpireTransitionApprovals records an expired decision on every
+// pending-approval transition whose approval window ended before now. It
+// returns how many transitions expired.
+func (s *PostgresStore) ExpireTransitionApprovals(ctx context.Context, now time.Time) (int, error) {
+	sqlStr, args, err := s.sb.
+		Select("id").
+		From("power.transitions").
+		Where(sq.Eq{"state": engine.TransitionStatePendingApproval}).
+		Where(sq.LtOrEq{"approval_expires_at": now.UTC()}).
+		OrderBy("approval_expires_at ASC").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building expired approval query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, fmt.Errorf("listing expired approvals: %w", err)
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if scanErr := rows.Scan(&id); scanErr != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning expired approval row: %w", scanErr)
+		}
+		ids = append(ids, id)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return 0, fmt.Errorf("iterating expired approval rows: %w", rowsErr)
+	}
+
+	expired := 0
+	for _, id := range ids {
+		_, decideErr := s.DecideTransitionApproval(ctx, engine.ApprovalDecision{
+			TransitionID: id,
+			Action:       engine.ApprovalActionExpired,
+			Reason:       "approval window elapsed",
+			DecidedAt:    now,
+		})
+		if decideErr != nil {
+			if errors.Is(decideErr, engine.ErrApprovalNotPending) || errors.Is(decideErr, ErrNotFound) {
+				// Decided or deleted concurrently.
+				continue
+			}
+			return expired, decideErr
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// ListTransitionApprovals returns the approval trail of a transition, oldest first.
+func (s *PostgresStore) ListTransitionApprovals(ctx context.Context, transitionID string) ([]engine.ApprovalDecision, error) {
+	transitionID = strings.TrimSpace(transitionID)
+	if transitionID == "" {
+		return []engine.ApprovalDecision{}, nil
+	}
+
+	sqlStr, args, err := s.sb.
+		Select("transition_id", "action", "principal", "reason", "created_at").
+		From("power.transition_approvals").
+		Where(sq.Eq{"transition_id": transitionID}).
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building transition approval list query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing transition approvals: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]engine.ApprovalDecision, 0)
+	for rows.Next() {
+		var item engine.ApprovalDecision
+		if scanErr := rows.Scan(
+			&item.TransitionID,
+			&item.Action,
+			&item.Principal,
+			&item.Reason,
+			&item.DecidedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scanning transition approval row: %w", scanErr)
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating transition approval rows: %w", rowsErr)
+	}
+	return items, nil
+}
+
+// insertTransitionApprovalTx appends one decision to the approval trail and
+// writes its outbox event.
+func (s *PostgresStore) insertTransitionApprovalTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	transition engine.Transition,
+	decision engine.ApprovalDecision,
+) error {
+	decision.Principal = strings.TrimSpace(decision.Principal)
+	decision.Reason = strings.TrimSpace(decision.Reason)
+
+	sqlStr, args, err := s.sb.
+		Insert("power.transition_approvals").
+		Columns("transition_id", "action", "principal", "reason", "created_at").
+		Values(transition.ID, decision.Action, decision.Principal, decision.Reason, decision.DecidedAt.UTC()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building transition approval insert query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("inserting transition approval: %w", err)
+	}
+
+	event, err := newTransitionApprovalEvent(transition, decision)
+	if err != nil {
+		return fmt.Errorf("building transition approval event: %w", err)
+	}
+	if err := outbox.WriteContext(ctx, tx, event); err != nil {
+		return fmt.Errorf("writing transition approval outbox event: %w", err)
+	}
+	return nil
+}
+
+// cancelPendingTasksTx cancels the pending tasks of a transition that will
+// not run.
+func (s *PostgresStore) cancelPendingTasksTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	transitionID string,
+	action string,
+	decidedAt time.Time,
+) ([]engine.Task, error) {
+	sqlStr, args, err := s.sb.
+		Update("power.transition_tasks").
+		Set("state", engine.TaskStateCanceled).
+		Set("error_detail", "transition approval "+action).
+		Set("completed_at", decidedAt).
+		Set("updated_at", decidedAt).
+		Where(sq.Eq{"transition_id": transitionID, "state": engine.TaskStatePending}).
+		Suffix("RETURNING " + strings.Join(transitionTaskColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building transition task cancel query: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("canceling transition tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]engine.Task, 0)
+	for rows.Next() {
+		task, scanErr := scanTransitionTask(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		tasks = append(tasks, task)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating canceled transition task rows: %w", rowsErr)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,124 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+func createPendingApproval(
+	t *testing.T,
+	st *store.PostgresStore,
+	expiresAt time.Time,
+) engine.Transition {
+	t.Helper()
+
+	now := time.Now().UTC()
+	transition, _, err := st.CreateTransition(context.Background(), engine.Transition{
+		Operation:         "ForceOff",
+		State:             engine.TransitionStatePendingApproval,
+		RequestedBy:       "alice",
+		TargetCount:       2,
+		ApprovalReason:    "targets 2 nodes, more than 1",
+		ApprovalExpiresAt: &expiresAt,
+		QueuedAt:          now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, []engine.Task{
+		{NodeID: "node-1", BMCID: "bmc-1", Operation: "ForceOff", State: engine.TaskStatePending, QueuedAt: now, CreatedAt: now, UpdatedAt: now},
+		{NodeID: "node-2", BMCID: "bmc-2", Operation: "ForceOff", State: engine.TaskStatePending, QueuedAt: now, CreatedAt: now, UpdatedAt: now},
+	})
+	require.NoError(t, err)
+	require.Equal(t, engine.TransitionStatePendingApproval, transition.State)
+	return transition
+}
+
+func TestPostgresStore_TransitionApprovals(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	approved := createPendingApproval(t, st, time.Now().UTC().Add(time.Hour))
+	assert.Equal(t, "targets 2 nodes, more than 1", approved.ApprovalReason)
+	require.NotNil(t, approved.ApprovalExpiresAt)
+
+	decided, err := st.DecideTransitionApproval(ctx, engine.ApprovalDecision{
+		TransitionID: approved.ID,
+		Action:       engine.ApprovalActionApproved,
+		Principal:    "bob",
+		Reason:       "maintenance window",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, engine.TransitionStatePending, decided.State)
+	assert.Nil(t, decided.CompletedAt)
+
+	_, err = st.DecideTransitionApproval(ctx, engine.ApprovalDecision{
+		TransitionID: approved.ID,
+		Action:       engine.ApprovalActionRejected,
+		Principal:    "carol",
+	})
+	require.ErrorIs(t, err, engine.ErrApprovalNotPending)
+
+	trail, err := st.ListTransitionApprovals(ctx, approved.ID)
+	require.NoError(t, err)
+	require.Len(t, trail, 2)
+	assert.Equal(t, engine.ApprovalActionRequested, trail[0].Action)
+	assert.Equal(t, "alice", trail[0].Principal)
+	assert.Equal(t, engine.ApprovalActionApproved, trail[1].Action)
+	assert.Equal(t, "bob", trail[1].Principal)
+
+	rejected := createPendingApproval(t, st, time.Now().UTC().Add(time.Hour))
+	decided, err = st.DecideTransitionApproval(ctx, engine.ApprovalDecision{
+		TransitionID: rejected.ID,
+		Action:       engine.ApprovalActionRejected,
+		Principal:    "bob",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, engine.TransitionStateRejected, decided.State)
+	assert.Equal(t, 2, decided.FailureCount)
+	require.NotNil(t, decided.CompletedAt)
+
+	tasks, err := st.ListTransitionTasks(ctx, rejected.ID)
+	require.NoError(t, err)
+	for _, task := range tasks {
+		assert.Equal(t, engine.TaskStateCanceled, task.State)
+	}
+
+	_, err = st.DecideTransitionApproval(ctx, engine.ApprovalDecision{
+		TransitionID: "missing",
+		Action:       engine.ApprovalActionApproved,
+	})
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestPostgresStore_ExpireTransitionApprovals(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	stale := createPendingApproval(t, st, now.Add(-time.Minute))
+	fresh := createPendingApproval(t, st, now.Add(time.Hour))
+
+	expired, err := st.ExpireTransitionApprovals(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	staleAfter, err := st.GetTransition(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, engine.TransitionStateExpired, staleAfter.State)
+
+	freshAfter, err := st.GetTransition(ctx, fresh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, engine.TransitionStatePendingApproval, freshAfter.State)
+
+	expired, err = st.ExpireTransitionApprovals(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+}
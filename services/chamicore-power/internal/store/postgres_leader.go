@@ -0,0 +1,241 @@
+// Package store provides mapping sync leader election for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	stdsync "sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+// mappingSyncLockKey is the session advisory lock key guarding mapping sync leadership.
+const mappingSyncLockKey int64 = 0x706f7765720001
+
+// MappingSyncCoordinator elects a single mapping-sync leader across replicas
+// using a session-scoped PostgreSQL advisory lock, and exposes the shared
+// sync state followers use to report status and wait on leader runs.
+type MappingSyncCoordinator struct {
+	db        *sql.DB
+	sb        sq.StatementBuilderType
+	replicaID string
+
+	mu   stdsync.Mutex
+	conn *sql.Conn
+}
+
+// NewMappingSyncCoordinator returns a leader coordinator bound to this store's database.
+func (s *PostgresStore) NewMappingSyncCoordinator(replicaID string) *MappingSyncCoordinator {
+	return &MappingSyncCoordinator{
+		db:        s.db,
+		sb:        s.sb,
+		replicaID: strings.TrimSpace(replicaID),
+	}
+}
+
+// ReplicaID returns the identity this replica advertises while leading.
+func (c *MappingSyncCoordinator) ReplicaID() string {
+	return c.replicaID
+}
+
+// TryAcquireLeadership attempts to take (or confirms it still holds) the leader lock.
+// The lock lives on a dedicated connection, so a dropped session releases it for other replicas.
+func (c *MappingSyncCoordinator) TryAcquireLeadership(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		if err := c.conn.PingContext(ctx); err != nil {
+			_ = c.conn.Close()
+			c.conn = nil
+		} else {
+			return true, c.heartbeat(ctx)
+		}
+	}
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquiring leader election connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", mappingSyncLockKey).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("trying mapping sync advisory lock: %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	c.conn = conn
+	return true, c.heartbeat(ctx)
+}
+
+// ReleaseLeadership gives up the leader lock if held.
+func (c *MappingSyncCoordinator) ReleaseLeadership(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	defer func() {
+		_ = c.conn.Close()
+		c.conn = nil
+	}()
+
+	if _, err := c.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", mappingSyncLockKey); err != nil {
+		return fmt.Errorf("releasing mapping sync advisory lock: %w", err)
+	}
+	return nil
+}
+
+// RequestSync records a request for the leader to run a sync and returns its sequence number.
+func (c *MappingSyncCoordinator) RequestSync(ctx context.Context) (int64, error) {
+	query := c.sb.
+		Update("power.mapping_sync_state").
+		Set("requested_seq", sq.Expr("requested_seq + 1")).
+		Set("requested_at", sq.Expr("NOW()")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": 1}).
+		Suffix("RETURNING requested_seq")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building sync request query: %w", err)
+	}
+
+	var seq int64
+	if err := c.db.QueryRowContext(ctx, sqlStr, args...).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("recording sync request: %w", err)
+	}
+	return seq, nil
+}
+
+// LoadSyncState returns the shared mapping sync state.
+func (c *MappingSyncCoordinator) LoadSyncState(ctx context.Context) (model.MappingSyncState, error) {
+	query := c.sb.
+		Select(
+			"leader_id",
+			"leader_heartbeat_at",
+			"requested_seq",
+			"completed_seq",
+			"last_attempt_at",
+			"last_sync_at",
+			"last_error",
+			"last_counts",
+			"last_report",
+		).
+		From("power.mapping_sync_state").
+		Where(sq.Eq{"id": 1})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return model.MappingSyncState{}, fmt.Errorf("building sync state query: %w", err)
+	}
+
+	var (
+		state         model.MappingSyncState
+		heartbeatAt   sql.NullTime
+		lastAttemptAt sql.NullTime
+		lastSyncAt    sql.NullTime
+		countsRaw     []byte
+		reportRaw     []byte
+	)
+	if err := c.db.QueryRowContext(ctx, sqlStr, args...).Scan(
+		&state.LeaderID,
+		&heartbeatAt,
+		&state.RequestedSeq,
+		&state.CompletedSeq,
+		&lastAttemptAt,
+		&lastSyncAt,
+		&state.LastError,
+		&countsRaw,
+		&reportRaw,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.MappingSyncState{}, ErrNotFound
+		}
+		return model.MappingSyncState{}, fmt.Errorf("loading sync state: %w", err)
+	}
+
+	state.LeaderHeartbeatAt = nullTimePtr(heartbeatAt)
+	state.LastAttemptAt = nullTimePtr(lastAttemptAt)
+	state.LastSyncAt = nullTimePtr(lastSyncAt)
+	if len(countsRaw) > 0 {
+		if err := json.Unmarshal(countsRaw, &state.LastCounts); err != nil {
+			return model.MappingSyncState{}, fmt.Errorf("decoding sync counts: %w", err)
+		}
+	}
+	if len(reportRaw) > 0 {
+		var report model.MappingSyncReport
+		if err := json.Unmarshal(reportRaw, &report); err != nil {
+			return model.MappingSyncState{}, fmt.Errorf("decoding sync report: %w", err)
+		}
+		state.LastReport = &report
+	}
+	return state, nil
+}
+
+// RecordSyncRun stores the outcome of a leader sync run and marks requests up to HandledSeq complete.
+func (c *MappingSyncCoordinator) RecordSyncRun(ctx context.Context, run model.MappingSyncRun) error {
+	counts, err := json.Marshal(run.Counts)
+	if err != nil {
+		return fmt.Errorf("encoding sync counts: %w", err)
+	}
+
+	query := c.sb.
+		Update("power.mapping_sync_state").
+		Set("leader_id", strings.TrimSpace(run.LeaderID)).
+		Set("leader_heartbeat_at", sq.Expr("NOW()")).
+		Set("completed_seq", sq.Expr("GREATEST(completed_seq, ?)", run.HandledSeq)).
+		Set("last_attempt_at", run.AttemptedAt.UTC()).
+		Set("last_error", run.Error).
+		Set("last_counts", counts).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": 1})
+	if run.SyncedAt != nil {
+		query = query.Set("last_sync_at", run.SyncedAt.UTC())
+	}
+	if run.Report != nil {
+		report, err := json.Marshal(run.Report)
+		if err != nil {
+			return fmt.Errorf("encoding sync report: %w", err)
+		}
+		query = query.Set("last_report", report)
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("building sync run update query: %w", err)
+	}
+	if _, err := c.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("recording sync run: %w", err)
+	}
+	return nil
+}
+
+func (c *MappingSyncCoordinator) heartbeat(ctx context.Context) error {
+	query := c.sb.
+		Update("power.mapping_sync_state").
+		Set("leader_id", c.replicaID).
+		Set("leader_heartbeat_at", time.Now().UTC()).
+		Where(sq.Eq{"id": 1})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("building leader heartbeat query: %w", err)
+	}
+	if _, err := c.conn.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("recording leader heartbeat: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+func TestMappingSyncCoordinator_SingleLeaderAndFailover(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	first := st.NewMappingSyncCoordinator("power-0")
+	second := st.NewMappingSyncCoordinator("power-1")
+
+	leader, err := first.TryAcquireLeadership(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	leader, err = first.TryAcquireLeadership(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader, "leader must keep leadership on renewal")
+
+	leader, err = second.TryAcquireLeadership(ctx)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	state, err := second.LoadSyncState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "power-0", state.LeaderID)
+	require.NotNil(t, state.LeaderHeartbeatAt)
+
+	require.NoError(t, first.ReleaseLeadership(ctx))
+
+	leader, err = second.TryAcquireLeadership(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+	require.NoError(t, second.ReleaseLeadership(ctx))
+}
+
+func TestMappingSyncCoordinator_RequestAndRecordRun(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	coordinator := st.NewMappingSyncCoordinator("power-0")
+
+	first, err := coordinator.RequestSync(ctx)
+	require.NoError(t, err)
+	second, err := coordinator.RequestSync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first+1, second)
+
+	syncedAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, coordinator.RecordSyncRun(ctx, model.MappingSyncRun{
+		LeaderID:    "power-0",
+		HandledSeq:  second,
+		AttemptedAt: syncedAt.Add(-time.Second),
+		SyncedAt:    &syncedAt,
+		Counts:      model.MappingApplyCounts{EndpointsUpserted: 2, LinksUpserted: 3},
+		Report: &model.MappingSyncReport{
+			GeneratedAt: syncedAt,
+			Summary:     model.MappingSyncSummary{BMCsAdded: 1},
+			BMCsAdded:   []model.BMCMappingChange{{BMCID: "bmc-1", Endpoint: "https://10.0.0.1"}},
+		},
+	}))
+
+	state, err := coordinator.LoadSyncState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, state.RequestedSeq)
+	assert.Equal(t, second, state.CompletedSeq)
+	assert.Empty(t, state.LastError)
+	require.NotNil(t, state.LastSyncAt)
+	assert.True(t, syncedAt.Equal(*state.LastSyncAt))
+	assert.Equal(t, 3, state.LastCounts.LinksUpserted)
+	require.NotNil(t, state.LastReport)
+	assert.Equal(t, 1, state.LastReport.Summary.BMCsAdded)
+	require.Len(t, state.LastReport.BMCsAdded, 1)
+	assert.Equal(t, "bmc-1", state.LastReport.BMCsAdded[0].BMCID)
+
+	require.NoError(t, coordinator.RecordSyncRun(ctx, model.MappingSyncRun{
+		LeaderID:    "power-0",
+		HandledSeq:  first,
+		AttemptedAt: time.Now().UTC(),
+		Error:       "listing components from SMD: boom",
+	}))
+
+	state, err = coordinator.LoadSyncState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, state.CompletedSeq, "completed sequence must not move backwards")
+	assert.Equal(t, "listing components from SMD: boom", state.LastError)
+	require.NotNil(t, state.LastSyncAt)
+	assert.True(t, syncedAt.Equal(*state.LastSyncAt), "failed runs keep the last successful sync time")
+	require.NotNil(t, state.LastReport, "failed runs keep the last sync report")
+}
@@ -14,19 +14,31 @@ import (
 	"git.cscs.ch/openchami/chamicore-power/internal/model"
 )
 
-// ReplaceTopologyMappings reconciles cached mapping rows against the provided desired topology.
-func (s *PostgresStore) ReplaceTopologyMappings(
+// ApplyTopologyDelta applies an incremental mapping change in one transaction.
+func (s *PostgresStore) ApplyTopologyDelta(
 	ctx context.Context,
-	endpoints []model.BMCEndpoint,
-	links []model.NodeBMCLink,
+	delta model.TopologyDelta,
 	syncedAt time.Time,
 ) (model.MappingApplyCounts, error) {
-	cleanEndpoints := normalizeEndpoints(endpoints)
-	cleanLinks := normalizeLinks(links)
+	endpoints := normalizeEndpoints(delta.Endpoints)
+	refreshes := normalizeEndpoints(delta.EndpointRefreshes)
+	links := normalizeLinks(delta.Links)
+	_, deletedNodeIDs := normalizeNodeIDs(delta.DeletedNodeIDs)
+	_, deletedBMCIDs := normalizeNodeIDs(delta.DeletedBMCIDs)
+
+	// Sparse event deltas only fill in a missing credential binding; full
+	// syncs overwrite the credential and TLS settings.
+	endpointSettings := `
+  credential_id = COALESCE(NULLIF(bmc_endpoints.credential_id, ''), EXCLUDED.credential_id),`
+	if delta.Authoritative {
+		endpointSettings = `
+  credential_id = EXCLUDED.credential_id,
+  insecure_skip_verify = EXCLUDED.insecure_skip_verify,`
+	}
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return model.MappingApplyCounts{}, fmt.Errorf("starting mapping transaction: %w", err)
+		return model.MappingApplyCounts{}, fmt.Errorf("starting mapping delta transaction: %w", err)
 	}
 	defer func() {
 		_ = tx.Rollback()
@@ -34,7 +46,18 @@ func (s *PostgresStore) ReplaceTopologyMappings(
 
 	counts := model.MappingApplyCounts{}
 
-	for _, endpoint := range cleanEndpoints {
+	if len(deletedNodeIDs) > 0 {
+		deleted, deleteErr := s.execCount(ctx, tx,
+			s.sb.Delete("power.node_bmc_links").Where(sq.Eq{"node_id": deletedNodeIDs}),
+			"node links",
+		)
+		if deleteErr != nil {
+			return model.MappingApplyCounts{}, deleteErr
+		}
+		counts.LinksDeleted += deleted
+	}
+
+	for _, endpoint := range endpoints {
 		query := s.sb.
 			Insert("power.bmc_endpoints").
 			Columns(
@@ -59,24 +82,37 @@ func (s *PostgresStore) ReplaceTopologyMappings(
 			).
 			Suffix(`
 ON CONFLICT (bmc_id) DO UPDATE SET
-  endpoint = EXCLUDED.endpoint,
-  credential_id = EXCLUDED.credential_id,
-  insecure_skip_verify = EXCLUDED.insecure_skip_verify,
+  endpoint = COALESCE(NULLIF(EXCLUDED.endpoint, ''), bmc_endpoints.endpoint),` + endpointSettings + `
   source = EXCLUDED.source,
   last_synced_at = EXCLUDED.last_synced_at,
   updated_at = EXCLUDED.updated_at`)
 
-		sqlStr, args, sqlErr := query.ToSql()
-		if sqlErr != nil {
-			return model.MappingApplyCounts{}, fmt.Errorf("building endpoint upsert query: %w", sqlErr)
+		upserted, upsertErr := s.execCount(ctx, tx, query, fmt.Sprintf("endpoint %q", endpoint.BMCID))
+		if upsertErr != nil {
+			return model.MappingApplyCounts{}, upsertErr
 		}
-		if _, execErr := tx.ExecContext(ctx, sqlStr, args...); execErr != nil {
-			return model.MappingApplyCounts{}, fmt.Errorf("upserting endpoint %q: %w", endpoint.BMCID, execErr)
+		counts.EndpointsUpserted += upserted
+	}
+
+	for _, refresh := range refreshes {
+		if refresh.Endpoint == "" {
+			continue
 		}
-		counts.EndpointsUpserted++
+		query := s.sb.
+			Update("power.bmc_endpoints").
+			Set("endpoint", refresh.Endpoint).
+			Set("last_synced_at", syncedAt).
+			Set("updated_at", syncedAt).
+			Where(sq.Eq{"bmc_id": refresh.BMCID})
+
+		updated, updateErr := s.execCount(ctx, tx, query, fmt.Sprintf("endpoint refresh %q", refresh.BMCID))
+		if updateErr != nil {
+			return model.MappingApplyCounts{}, updateErr
+		}
+		counts.EndpointsUpserted += updated
 	}
 
-	for _, link := range cleanLinks {
+	for _, link := range links {
 		query := s.sb.
 			Insert("power.node_bmc_links").
 			Columns(
@@ -102,30 +138,31 @@ ON CONFLICT (node_id) DO UPDATE SET
   last_synced_at = EXCLUDED.last_synced_at,
   updated_at = EXCLUDED.updated_at`)
 
-		sqlStr, args, sqlErr := query.ToSql()
-		if sqlErr != nil {
-			return model.MappingApplyCounts{}, fmt.Errorf("building link upsert query: %w", sqlErr)
+		upserted, upsertErr := s.execCount(ctx, tx, query, fmt.Sprintf("link for node %q", link.NodeID))
+		if upsertErr != nil {
+			return model.MappingApplyCounts{}, upsertErr
 		}
-		if _, execErr := tx.ExecContext(ctx, sqlStr, args...); execErr != nil {
-			return model.MappingApplyCounts{}, fmt.Errorf("upserting link for node %q: %w", link.NodeID, execErr)
-		}
-		counts.LinksUpserted++
+		counts.LinksUpserted += upserted
 	}
 
-	deletedLinks, err := s.deleteStaleLinks(ctx, tx, cleanLinks)
-	if err != nil {
-		return model.MappingApplyCounts{}, err
-	}
-	counts.LinksDeleted = deletedLinks
+	if len(deletedBMCIDs) > 0 {
+		linkQuery := s.sb.Delete("power.node_bmc_links").Where(sq.Eq{"bmc_id": deletedBMCIDs})
+		deletedLinks, deleteErr := s.execCount(ctx, tx, linkQuery, "links of deleted BMCs")
+		if deleteErr != nil {
+			return model.MappingApplyCounts{}, deleteErr
+		}
+		counts.LinksDeleted += deletedLinks
 
-	deletedEndpoints, err := s.deleteStaleEndpoints(ctx, tx, cleanEndpoints)
-	if err != nil {
-		return model.MappingApplyCounts{}, err
+		endpointQuery := s.sb.Delete("power.bmc_endpoints").Where(sq.Eq{"bmc_id": deletedBMCIDs})
+		deletedEndpoints, deleteErr := s.execCount(ctx, tx, endpointQuery, "BMC endpoints")
+		if deleteErr != nil {
+			return model.MappingApplyCounts{}, deleteErr
+		}
+		counts.EndpointsDeleted += deletedEndpoints
 	}
-	counts.EndpointsDeleted = deletedEndpoints
 
 	if commitErr := tx.Commit(); commitErr != nil {
-		return model.MappingApplyCounts{}, fmt.Errorf("committing mapping transaction: %w", commitErr)
+		return model.MappingApplyCounts{}, fmt.Errorf("committing mapping delta transaction: %w", commitErr)
 	}
 
 	return counts, nil
@@ -302,54 +339,16 @@ func (s *PostgresStore) ListNodeBMCLinks(ctx context.Context) ([]model.NodeBMCLi
 	return items, nil
 }
 
-func (s *PostgresStore) deleteStaleLinks(
-	ctx context.Context,
-	tx *sql.Tx,
-	desiredLinks []model.NodeBMCLink,
-) (int, error) {
-	query := s.sb.Delete("power.node_bmc_links")
-	if len(desiredLinks) > 0 {
-		nodeIDs := make([]string, 0, len(desiredLinks))
-		for _, link := range desiredLinks {
-			nodeIDs = append(nodeIDs, link.NodeID)
-		}
-		query = query.Where(sq.NotEq{"node_id": nodeIDs})
-	}
-
-	sqlStr, args, err := query.ToSql()
-	if err != nil {
-		return 0, fmt.Errorf("building stale-link delete query: %w", err)
-	}
-	res, err := tx.ExecContext(ctx, sqlStr, args...)
-	if err != nil {
-		return 0, fmt.Errorf("deleting stale node links: %w", err)
-	}
-	return rowsAffectedAsInt(res, "stale node links")
-}
-
-func (s *PostgresStore) deleteStaleEndpoints(
-	ctx context.Context,
-	tx *sql.Tx,
-	desiredEndpoints []model.BMCEndpoint,
-) (int, error) {
-	query := s.sb.Delete("power.bmc_endpoints")
-	if len(desiredEndpoints) > 0 {
-		bmcIDs := make([]string, 0, len(desiredEndpoints))
-		for _, endpoint := range desiredEndpoints {
-			bmcIDs = append(bmcIDs, endpoint.BMCID)
-		}
-		query = query.Where(sq.NotEq{"bmc_id": bmcIDs})
-	}
-
+func (s *PostgresStore) execCount(ctx context.Context, tx *sql.Tx, query sq.Sqlizer, label string) (int, error) {
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
-		return 0, fmt.Errorf("building stale-endpoint delete query: %w", err)
+		return 0, fmt.Errorf("building %s query: %w", label, err)
 	}
 	res, err := tx.ExecContext(ctx, sqlStr, args...)
 	if err != nil {
-		return 0, fmt.Errorf("deleting stale BMC endpoints: %w", err)
+		return 0, fmt.Errorf("applying %s: %w", label, err)
 	}
-	return rowsAffectedAsInt(res, "stale BMC endpoints")
+	return rowsAffectedAsInt(res, label)
 }
 
 func rowsAffectedAsInt(res sql.Result, label string) (int, error) {
@@ -21,26 +21,36 @@ func newTestStore(t *testing.T) *store.PostgresStore {
 	return store.NewPostgresStore(db)
 }
 
-func TestPostgresStore_ReplaceTopologyMappings_CreateUpdateDelete(t *testing.T) {
+func TestPostgresStore_ApplyTopologyDelta_AuthoritativeCreateUpdateDelete(t *testing.T) {
 	st := newTestStore(t)
 	ctx := context.Background()
 	now := time.Now().UTC()
 
-	_, err := st.ReplaceTopologyMappings(ctx, []model.BMCEndpoint{
-		{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
-		{BMCID: "bmc-old", Endpoint: "https://10.0.0.99", CredentialID: "cred-old", Source: "smd"},
-	}, []model.NodeBMCLink{
-		{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
-		{NodeID: "node-old", BMCID: "bmc-old", Source: "smd"},
+	_, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
+			{BMCID: "bmc-old", Endpoint: "https://10.0.0.99", CredentialID: "cred-old", Source: "smd"},
+		},
+		Links: []model.NodeBMCLink{
+			{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
+			{NodeID: "node-old", BMCID: "bmc-old", Source: "smd"},
+		},
 	}, now)
 	require.NoError(t, err)
 
-	counts, err := st.ReplaceTopologyMappings(ctx, []model.BMCEndpoint{
-		{BMCID: "bmc-1", Endpoint: "https://10.0.0.10", CredentialID: "cred-1-new", Source: "smd"},
-		{BMCID: "bmc-2", Endpoint: "https://10.0.0.2", CredentialID: "cred-2", Source: "smd"},
-	}, []model.NodeBMCLink{
-		{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
-		{NodeID: "node-2", BMCID: "bmc-2", Source: "smd"},
+	counts, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.10", CredentialID: "cred-1-new", Source: "smd"},
+			{BMCID: "bmc-2", Endpoint: "https://10.0.0.2", CredentialID: "cred-2", Source: "smd"},
+		},
+		Links: []model.NodeBMCLink{
+			{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
+			{NodeID: "node-2", BMCID: "bmc-2", Source: "smd"},
+		},
+		DeletedNodeIDs: []string{"node-old"},
+		DeletedBMCIDs:  []string{"bmc-old"},
 	}, now.Add(time.Second))
 	require.NoError(t, err)
 	assert.Equal(t, 2, counts.EndpointsUpserted)
@@ -65,19 +75,25 @@ func TestPostgresStore_ReplaceTopologyMappings_CreateUpdateDelete(t *testing.T)
 	assert.Equal(t, "bmc-2", links[1].BMCID)
 }
 
-func TestPostgresStore_ReplaceTopologyMappings_EmptyClearsAll(t *testing.T) {
+func TestPostgresStore_ApplyTopologyDelta_DeletesAll(t *testing.T) {
 	st := newTestStore(t)
 	ctx := context.Background()
 	now := time.Now().UTC()
 
-	_, err := st.ReplaceTopologyMappings(ctx, []model.BMCEndpoint{
-		{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
-	}, []model.NodeBMCLink{
-		{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
+	_, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
+		},
+		Links: []model.NodeBMCLink{{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"}},
 	}, now)
 	require.NoError(t, err)
 
-	counts, err := st.ReplaceTopologyMappings(ctx, nil, nil, now.Add(time.Second))
+	counts, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative:  true,
+		DeletedNodeIDs: []string{"node-1"},
+		DeletedBMCIDs:  []string{"bmc-1"},
+	}, now.Add(time.Second))
 	require.NoError(t, err)
 	assert.Equal(t, 0, counts.EndpointsUpserted)
 	assert.Equal(t, 0, counts.LinksUpserted)
@@ -97,14 +113,18 @@ func TestPostgresStore_ResolveNodeMappings_MissingEdgeCases(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now().UTC()
 
-	_, err := st.ReplaceTopologyMappings(ctx, []model.BMCEndpoint{
-		{BMCID: "bmc-ok", Endpoint: "https://10.0.0.1", CredentialID: "cred-ok", Source: "smd"},
-		{BMCID: "bmc-no-endpoint", Endpoint: "", CredentialID: "cred-no-endpoint", Source: "smd"},
-		{BMCID: "bmc-no-cred", Endpoint: "https://10.0.0.3", CredentialID: "", Source: "smd"},
-	}, []model.NodeBMCLink{
-		{NodeID: "node-ok", BMCID: "bmc-ok", Source: "smd"},
-		{NodeID: "node-no-endpoint", BMCID: "bmc-no-endpoint", Source: "smd"},
-		{NodeID: "node-no-cred", BMCID: "bmc-no-cred", Source: "smd"},
+	_, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-ok", Endpoint: "https://10.0.0.1", CredentialID: "cred-ok", Source: "smd"},
+			{BMCID: "bmc-no-endpoint", Endpoint: "", CredentialID: "cred-no-endpoint", Source: "smd"},
+			{BMCID: "bmc-no-cred", Endpoint: "https://10.0.0.3", CredentialID: "", Source: "smd"},
+		},
+		Links: []model.NodeBMCLink{
+			{NodeID: "node-ok", BMCID: "bmc-ok", Source: "smd"},
+			{NodeID: "node-no-endpoint", BMCID: "bmc-no-endpoint", Source: "smd"},
+			{NodeID: "node-no-cred", BMCID: "bmc-no-cred", Source: "smd"},
+		},
 	}, now)
 	require.NoError(t, err)
 
@@ -131,3 +151,94 @@ func TestPostgresStore_ResolveNodeMappings_MissingEdgeCases(t *testing.T) {
 	assert.Equal(t, model.MappingErrorCodeNotFound, missing[2].Code)
 	assert.Contains(t, missing[2].Detail, "discovery is not auto-triggered")
 }
+
+func TestPostgresStore_ApplyTopologyDelta(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	_, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
+			{BMCID: "bmc-2", Endpoint: "https://10.0.0.2", CredentialID: "cred-2", Source: "smd"},
+		},
+		Links: []model.NodeBMCLink{
+			{NodeID: "node-1", BMCID: "bmc-1", Source: "smd"},
+			{NodeID: "node-2", BMCID: "bmc-2", Source: "smd"},
+		},
+	}, now)
+	require.NoError(t, err)
+
+	counts, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", CredentialID: "cred-default", Source: "smd"},
+			{BMCID: "bmc-3", CredentialID: "cred-default", Source: "smd"},
+		},
+		EndpointRefreshes: []model.BMCEndpoint{
+			{BMCID: "bmc-3", Endpoint: "https://10.0.0.3"},
+			{BMCID: "node-9", Endpoint: "https://10.0.9.9"},
+		},
+		Links:          []model.NodeBMCLink{{NodeID: "node-3", BMCID: "bmc-3", Source: "smd"}},
+		DeletedNodeIDs: []string{"node-1"},
+		DeletedBMCIDs:  []string{"bmc-2"},
+	}, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 3, counts.EndpointsUpserted)
+	assert.Equal(t, 1, counts.LinksUpserted)
+	assert.Equal(t, 1, counts.EndpointsDeleted)
+	assert.Equal(t, 2, counts.LinksDeleted)
+
+	endpoints, err := st.ListBMCEndpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "bmc-1", endpoints[0].BMCID)
+	assert.Equal(t, "https://10.0.0.1", endpoints[0].Endpoint, "empty endpoint must not clear a known one")
+	assert.Equal(t, "cred-1", endpoints[0].CredentialID, "existing credential binding must be kept")
+	assert.Equal(t, "bmc-3", endpoints[1].BMCID)
+	assert.Equal(t, "https://10.0.0.3", endpoints[1].Endpoint)
+	assert.Equal(t, "cred-default", endpoints[1].CredentialID)
+
+	links, err := st.ListNodeBMCLinks(ctx)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "node-3", links[0].NodeID)
+}
+
+func TestPostgresStore_ApplyTopologyDelta_AuthoritativeOverwritesSettings(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	_, err := st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"},
+		},
+	}, now)
+	require.NoError(t, err)
+
+	_, err = st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", CredentialID: "cred-2", InsecureSkipVerify: true, Source: "smd"},
+		},
+	}, now.Add(time.Second))
+	require.NoError(t, err)
+	endpoints, err := st.ListBMCEndpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "cred-1", endpoints[0].CredentialID, "event deltas keep the credential binding")
+	assert.False(t, endpoints[0].InsecureSkipVerify)
+
+	_, err = st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-2", InsecureSkipVerify: true, Source: "smd"},
+		},
+	}, now.Add(2*time.Second))
+	require.NoError(t, err)
+	endpoints, err = st.ListBMCEndpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "cred-2", endpoints[0].CredentialID)
+	assert.True(t, endpoints[0].InsecureSkipVerify)
+}
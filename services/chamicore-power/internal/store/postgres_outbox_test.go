@@ -26,6 +26,7 @@ import (
 	"git.cscs.ch/openchami/chamicore-power/internal/engine"
 	"git.cscs.ch/openchami/chamicore-power/internal/model"
 	powersmd "git.cscs.ch/openchami/chamicore-power/internal/smd"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
 	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
 	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
 )
@@ -42,18 +43,22 @@ FROM power.outbox
 `)
 	require.NoError(t, err)
 
-	_, err = st.ReplaceTopologyMappings(ctx, []model.BMCEndpoint{
-		{
-			BMCID:        "bmc-1",
-			Endpoint:     "https://bmc-1",
-			CredentialID: "cred-1",
-			Source:       "smd",
+	_, err = st.ApplyTopologyDelta(ctx, model.TopologyDelta{
+		Authoritative: true,
+		Endpoints: []model.BMCEndpoint{
+			{
+				BMCID:        "bmc-1",
+				Endpoint:     "https://bmc-1",
+				CredentialID: "cred-1",
+				Source:       "smd",
+			},
 		},
-	}, []model.NodeBMCLink{
-		{
-			NodeID: "node-1",
-			BMCID:  "bmc-1",
-			Source: "smd",
+		Links: []model.NodeBMCLink{
+			{
+				NodeID: "node-1",
+				BMCID:  "bmc-1",
+				Source: "smd",
+			},
 		},
 	}, time.Now().UTC())
 	require.NoError(t, err)
@@ -190,7 +195,7 @@ FROM power.outbox
 		if event.Type != transitionLifecycleEventType || event.Subject != transition.ID {
 			return false
 		}
-		var payload transitionLifecycleEventData
+		var payload types.TransitionLifecycleEvent
 		if err := json.Unmarshal(event.Data, &payload); err != nil {
 			return false
 		}
@@ -203,7 +208,7 @@ FROM power.outbox
 		if event.Type != transitionTaskResultEventType || event.Subject != "node-1" {
 			return false
 		}
-		var payload transitionTaskResultEventData
+		var payload types.TransitionTaskResultEvent
 		if err := json.Unmarshal(event.Data, &payload); err != nil {
 			return false
 		}
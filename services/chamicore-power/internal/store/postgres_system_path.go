@@ -0,0 +1,196 @@
+// Package store provides node Redfish system-path persistence for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+// LookupNodeSystemPath returns the persisted Redfish system path for one node.
+func (s *PostgresStore) LookupNodeSystemPath(ctx context.Context, nodeID string) (string, bool, error) {
+	item, err := s.GetNodeSystemPath(ctx, nodeID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return item.SystemPath, true, nil
+}
+
+// GetNodeSystemPath returns one persisted node system-path mapping.
+func (s *PostgresStore) GetNodeSystemPath(ctx context.Context, nodeID string) (model.NodeSystemPath, error) {
+	query := s.sb.
+		Select("node_id", "system_path", "source", "created_at", "updated_at").
+		From("power.node_system_paths").
+		Where(sq.Eq{"node_id": strings.TrimSpace(nodeID)})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return model.NodeSystemPath{}, fmt.Errorf("building system path query: %w", err)
+	}
+
+	var item model.NodeSystemPath
+	if err := s.db.QueryRowContext(ctx, sqlStr, args...).Scan(
+		&item.NodeID,
+		&item.SystemPath,
+		&item.Source,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.NodeSystemPath{}, ErrNotFound
+		}
+		return model.NodeSystemPath{}, fmt.Errorf("loading system path for node %q: %w", nodeID, err)
+	}
+	return item, nil
+}
+
+// ListNodeSystemPaths returns persisted node system-path mappings ordered by node ID.
+func (s *PostgresStore) ListNodeSystemPaths(ctx context.Context, limit, offset int) ([]model.NodeSystemPath, int, error) {
+	limit = normalizeTransitionPageLimit(limit)
+	if offset < 0 {
+		offset = 0
+	}
+
+	countSQL, countArgs, err := s.sb.Select("COUNT(*)").From("power.node_system_paths").ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building system path count query: %w", err)
+	}
+	var total int
+	if scanErr := s.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); scanErr != nil {
+		return nil, 0, fmt.Errorf("counting system paths: %w", scanErr)
+	}
+
+	query := s.sb.
+		Select("node_id", "system_path", "source", "created_at", "updated_at").
+		From("power.node_system_paths").
+		OrderBy("node_id").
+		Limit(safeUint64(limit)).
+		Offset(safeUint64(offset))
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building system path list query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing system paths: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.NodeSystemPath, 0, limit)
+	for rows.Next() {
+		var item model.NodeSystemPath
+		if scanErr := rows.Scan(
+			&item.NodeID,
+			&item.SystemPath,
+			&item.Source,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); scanErr != nil {
+			return nil, 0, fmt.Errorf("scanning system path row: %w", scanErr)
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, 0, fmt.Errorf("iterating system path rows: %w", rowsErr)
+	}
+
+	return items, total, nil
+}
+
+// SaveNodeSystemPath upserts one node system-path mapping. Discovered paths
+// never replace a path set through the admin API.
+func (s *PostgresStore) SaveNodeSystemPath(ctx context.Context, item model.NodeSystemPath) (model.NodeSystemPath, error) {
+	nodeID := strings.TrimSpace(item.NodeID)
+	systemPath := strings.TrimSpace(item.SystemPath)
+	if nodeID == "" || systemPath == "" {
+		return model.NodeSystemPath{}, fmt.Errorf("node ID and system path are required")
+	}
+	source := strings.TrimSpace(item.Source)
+	if source == "" {
+		source = model.SystemPathSourceDiscovery
+	}
+
+	now := time.Now().UTC()
+	query := s.sb.
+		Insert("power.node_system_paths").
+		Columns("node_id", "system_path", "source", "created_at", "updated_at").
+		Values(nodeID, systemPath, source, now, now).
+		Suffix(`
+ON CONFLICT (node_id) DO UPDATE SET
+  system_path = EXCLUDED.system_path,
+  source = EXCLUDED.source,
+  updated_at = EXCLUDED.updated_at
+WHERE node_system_paths.source <> 'admin' OR EXCLUDED.source = 'admin'
+RETURNING node_id, system_path, source, created_at, updated_at`)
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return model.NodeSystemPath{}, fmt.Errorf("building system path upsert query: %w", err)
+	}
+
+	var saved model.NodeSystemPath
+	if err := s.db.QueryRowContext(ctx, sqlStr, args...).Scan(
+		&saved.NodeID,
+		&saved.SystemPath,
+		&saved.Source,
+		&saved.CreatedAt,
+		&saved.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// An admin-set path was kept.
+			return s.GetNodeSystemPath(ctx, nodeID)
+		}
+		return model.NodeSystemPath{}, fmt.Errorf("saving system path for node %q: %w", nodeID, err)
+	}
+	return saved, nil
+}
+
+// DeleteNodeSystemPath removes one node system-path mapping regardless of source.
+func (s *PostgresStore) DeleteNodeSystemPath(ctx context.Context, nodeID string) error {
+	deleted, err := s.deleteNodeSystemPaths(ctx, sq.Eq{"node_id": strings.TrimSpace(nodeID)})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ForgetDiscoveredSystemPath removes a discovered mapping so the next lookup rediscovers it.
+// Admin-set mappings are kept.
+func (s *PostgresStore) ForgetDiscoveredSystemPath(ctx context.Context, nodeID string) error {
+	_, err := s.deleteNodeSystemPaths(ctx, sq.Eq{
+		"node_id": strings.TrimSpace(nodeID),
+		"source":  model.SystemPathSourceDiscovery,
+	})
+	return err
+}
+
+func (s *PostgresStore) deleteNodeSystemPaths(ctx context.Context, where sq.Eq) (int, error) {
+	sqlStr, args, err := s.sb.Delete("power.node_system_paths").Where(where).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building system path delete query: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, fmt.Errorf("deleting system path: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("reading deleted system path count: %w", err)
+	}
+	return int(affected), nil
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+func TestNodeSystemPaths_AdminPathsWinOverDiscovery(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	saved, err := st.SaveNodeSystemPath(ctx, model.NodeSystemPath{
+		NodeID:     "node-1",
+		SystemPath: "/redfish/v1/Systems/1",
+		Source:     model.SystemPathSourceDiscovery,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.SystemPathSourceDiscovery, saved.Source)
+
+	saved, err = st.SaveNodeSystemPath(ctx, model.NodeSystemPath{
+		NodeID:     "node-1",
+		SystemPath: "/redfish/v1/Systems/2",
+		Source:     model.SystemPathSourceAdmin,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Systems/2", saved.SystemPath)
+	assert.Equal(t, model.SystemPathSourceAdmin, saved.Source)
+
+	saved, err = st.SaveNodeSystemPath(ctx, model.NodeSystemPath{
+		NodeID:     "node-1",
+		SystemPath: "/redfish/v1/Systems/3",
+		Source:     model.SystemPathSourceDiscovery,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/redfish/v1/Systems/2", saved.SystemPath, "discovery must not replace an admin path")
+
+	require.NoError(t, st.ForgetDiscoveredSystemPath(ctx, "node-1"))
+	path, found, err := st.LookupNodeSystemPath(ctx, "node-1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "/redfish/v1/Systems/2", path)
+
+	items, total, err := st.ListNodeSystemPaths(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+
+	require.NoError(t, st.DeleteNodeSystemPath(ctx, "node-1"))
+	assert.ErrorIs(t, st.DeleteNodeSystemPath(ctx, "node-1"), store.ErrNotFound)
+}
+
+func TestNodeSystemPaths_ForgetRemovesDiscoveredPath(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := st.SaveNodeSystemPath(ctx, model.NodeSystemPath{
+		NodeID:     "node-2",
+		SystemPath: "/redfish/v1/Systems/Self",
+		Source:     model.SystemPathSourceDiscovery,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, st.ForgetDiscoveredSystemPath(ctx, "node-2"))
+	_, found, err := st.LookupNodeSystemPath(ctx, "node-2")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
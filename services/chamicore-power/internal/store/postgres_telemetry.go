@@ -0,0 +1,287 @@
+// Package store provides node telemetry persistence for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+// telemetryInsertBatch bounds rows per upsert statement to stay well below
+// the PostgreSQL bind-parameter limit.
+const telemetryInsertBatch = 1000
+
+// telemetryBucket accumulates the samples of one node in one bucket.
+type telemetryBucket struct {
+	nodeID       string
+	bucketStart  time.Time
+	powerSamples int
+	powerSum     float64
+	powerMin     *float64
+	powerMax     *float64
+	tempSamples  int
+	tempSum      float64
+	tempMax      *float64
+}
+
+// RecordTelemetrySamples folds samples into resolution-wide buckets, merging
+// them with any samples already stored for the same node and bucket.
+func (s *PostgresStore) RecordTelemetrySamples(
+	ctx context.Context,
+	samples []model.TelemetrySample,
+	resolution time.Duration,
+) error {
+	if resolution <= 0 {
+		return fmt.Errorf("telemetry resolution must be positive")
+	}
+	buckets := bucketTelemetrySamples(samples, resolution)
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting telemetry transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for start := 0; start < len(buckets); start += telemetryInsertBatch {
+		end := start + telemetryInsertBatch
+		if end > len(buckets) {
+			end = len(buckets)
+		}
+
+		query := s.sb.
+			Insert("power.node_telemetry").
+			Columns(
+				"node_id",
+				"bucket_start",
+				"power_samples",
+				"power_sum_watts",
+				"power_min_watts",
+				"power_max_watts",
+				"temp_samples",
+				"inlet_temp_sum",
+				"inlet_temp_max",
+			)
+		for _, bucket := range buckets[start:end] {
+			query = query.Values(
+				bucket.nodeID,
+				bucket.bucketStart,
+				bucket.powerSamples,
+				bucket.powerSum,
+				optionalFloatValue(bucket.powerMin),
+				optionalFloatValue(bucket.powerMax),
+				bucket.tempSamples,
+				bucket.tempSum,
+				optionalFloatValue(bucket.tempMax),
+			)
+		}
+		query = query.Suffix(`
+ON CONFLICT (node_id, bucket_start) DO UPDATE SET
+  power_samples = node_telemetry.power_samples + EXCLUDED.power_samples,
+  power_sum_watts = node_telemetry.power_sum_watts + EXCLUDED.power_sum_watts,
+  power_min_watts = LEAST(node_telemetry.power_min_watts, EXCLUDED.power_min_watts),
+  power_max_watts = GREATEST(node_telemetry.power_max_watts, EXCLUDED.power_max_watts),
+  temp_samples = node_telemetry.temp_samples + EXCLUDED.temp_samples,
+  inlet_temp_sum = node_telemetry.inlet_temp_sum + EXCLUDED.inlet_temp_sum,
+  inlet_temp_max = GREATEST(node_telemetry.inlet_temp_max, EXCLUDED.inlet_temp_max)`)
+
+		sqlStr, args, sqlErr := query.ToSql()
+		if sqlErr != nil {
+			return fmt.Errorf("building telemetry upsert query: %w", sqlErr)
+		}
+		if _, execErr := tx.ExecContext(ctx, sqlStr, args...); execErr != nil {
+			return fmt.Errorf("upserting telemetry samples: %w", execErr)
+		}
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fmt.Errorf("committing telemetry transaction: %w", commitErr)
+	}
+	return nil
+}
+
+// ListTelemetry returns per-node telemetry points between query.Since
+// (inclusive) and query.Until (exclusive), merged into query.Step-wide points
+// ordered by node ID and time.
+func (s *PostgresStore) ListTelemetry(ctx context.Context, query model.TelemetryQuery) ([]model.TelemetryPoint, error) {
+	nodeIDs := make([]string, 0, len(query.NodeIDs))
+	for _, nodeID := range query.NodeIDs {
+		if trimmed := strings.TrimSpace(nodeID); trimmed != "" {
+			nodeIDs = append(nodeIDs, trimmed)
+		}
+	}
+	if len(nodeIDs) == 0 {
+		return []model.TelemetryPoint{}, nil
+	}
+	if query.Step <= 0 {
+		return nil, fmt.Errorf("telemetry step must be positive")
+	}
+	stepSeconds := query.Step.Seconds()
+
+	builder := s.sb.
+		Select("node_id").
+		Column(sq.Expr(
+			"to_timestamp(floor(extract(epoch FROM bucket_start)::double precision / ?::double precision) * ?::double precision) AS step_start",
+			stepSeconds,
+			stepSeconds,
+		)).
+		Column("SUM(power_samples)").
+		Column("SUM(power_sum_watts) / NULLIF(SUM(power_samples), 0)").
+		Column("MIN(power_min_watts)").
+		Column("MAX(power_max_watts)").
+		Column("SUM(inlet_temp_sum) / NULLIF(SUM(temp_samples), 0)").
+		Column("MAX(inlet_temp_max)").
+		From("power.node_telemetry").
+		Where(sq.Eq{"node_id": nodeIDs}).
+		Where(sq.GtOrEq{"bucket_start": query.Since.UTC()}).
+		GroupBy("node_id", "step_start").
+		OrderBy("node_id", "step_start")
+	if !query.Until.IsZero() {
+		builder = builder.Where(sq.Lt{"bucket_start": query.Until.UTC()})
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building telemetry query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing telemetry: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]model.TelemetryPoint, 0)
+	for rows.Next() {
+		var (
+			point    model.TelemetryPoint
+			power    sql.NullFloat64
+			powerMin sql.NullFloat64
+			powerMax sql.NullFloat64
+			temp     sql.NullFloat64
+			tempMax  sql.NullFloat64
+		)
+		if scanErr := rows.Scan(
+			&point.NodeID,
+			&point.Timestamp,
+			&point.Samples,
+			&power,
+			&powerMin,
+			&powerMax,
+			&temp,
+			&tempMax,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scanning telemetry row: %w", scanErr)
+		}
+		point.Timestamp = point.Timestamp.UTC()
+		point.PowerWatts = nullFloatPtr(power)
+		point.MinPowerWatts = nullFloatPtr(powerMin)
+		point.MaxPowerWatts = nullFloatPtr(powerMax)
+		point.InletTempCelsius = nullFloatPtr(temp)
+		point.MaxInletTemp = nullFloatPtr(tempMax)
+		points = append(points, point)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating telemetry rows: %w", rowsErr)
+	}
+
+	return points, nil
+}
+
+// PruneTelemetry deletes telemetry buckets that start before cutoff and
+// returns the number of rows removed.
+func (s *PostgresStore) PruneTelemetry(ctx context.Context, cutoff time.Time) (int64, error) {
+	sqlStr, args, err := s.sb.
+		Delete("power.node_telemetry").
+		Where(sq.Lt{"bucket_start": cutoff.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building telemetry prune query: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, fmt.Errorf("pruning telemetry: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("counting pruned telemetry rows: %w", err)
+	}
+	return deleted, nil
+}
+
+func bucketTelemetrySamples(samples []model.TelemetrySample, resolution time.Duration) []telemetryBucket {
+	byKey := make(map[string]*telemetryBucket)
+	for _, sample := range samples {
+		nodeID := strings.TrimSpace(sample.NodeID)
+		if nodeID == "" || (sample.PowerWatts == nil && sample.InletTempCelsius == nil) {
+			continue
+		}
+		bucketStart := sample.SampledAt.UTC().Truncate(resolution)
+		key := nodeID + "|" + bucketStart.Format(time.RFC3339Nano)
+
+		bucket, ok := byKey[key]
+		if !ok {
+			bucket = &telemetryBucket{nodeID: nodeID, bucketStart: bucketStart}
+			byKey[key] = bucket
+		}
+		if power := sample.PowerWatts; power != nil {
+			bucket.powerSamples++
+			bucket.powerSum += *power
+			if bucket.powerMin == nil || *power < *bucket.powerMin {
+				bucket.powerMin = floatPtr(*power)
+			}
+			if bucket.powerMax == nil || *power > *bucket.powerMax {
+				bucket.powerMax = floatPtr(*power)
+			}
+		}
+		if temp := sample.InletTempCelsius; temp != nil {
+			bucket.tempSamples++
+			bucket.tempSum += *temp
+			if bucket.tempMax == nil || *temp > *bucket.tempMax {
+				bucket.tempMax = floatPtr(*temp)
+			}
+		}
+	}
+
+	buckets := make([]telemetryBucket, 0, len(byKey))
+	for _, bucket := range byKey {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].nodeID != buckets[j].nodeID {
+			return buckets[i].nodeID < buckets[j].nodeID
+		}
+		return buckets[i].bucketStart.Before(buckets[j].bucketStart)
+	})
+	return buckets
+}
+
+func optionalFloatValue(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullFloatPtr(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return floatPtr(v.Float64)
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
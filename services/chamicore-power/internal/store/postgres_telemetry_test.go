@@ -0,0 +1,87 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+func telemetryValue(v float64) *float64 {
+	return &v
+}
+
+func TestPostgresStore_Telemetry(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, st.RecordTelemetrySamples(ctx, []model.TelemetrySample{
+		{NodeID: "node-1", SampledAt: base.Add(10 * time.Second), PowerWatts: telemetryValue(400), InletTempCelsius: telemetryValue(20)},
+		{NodeID: "node-1", SampledAt: base.Add(40 * time.Second), PowerWatts: telemetryValue(500)},
+		{NodeID: "node-1", SampledAt: base.Add(70 * time.Second), PowerWatts: telemetryValue(300), InletTempCelsius: telemetryValue(22)},
+		{NodeID: "node-2", SampledAt: base.Add(10 * time.Second), InletTempCelsius: telemetryValue(25)},
+		{NodeID: "node-3", SampledAt: base.Add(10 * time.Second)},
+	}, time.Minute))
+
+	// A second batch for an existing bucket is merged, not overwritten.
+	require.NoError(t, st.RecordTelemetrySamples(ctx, []model.TelemetrySample{
+		{NodeID: "node-1", SampledAt: base.Add(50 * time.Second), PowerWatts: telemetryValue(600)},
+	}, time.Minute))
+
+	points, err := st.ListTelemetry(ctx, model.TelemetryQuery{
+		NodeIDs: []string{"node-1", "node-2", "node-3"},
+		Since:   base,
+		Until:   base.Add(time.Hour),
+		Step:    time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+
+	assert.Equal(t, "node-1", points[0].NodeID)
+	assert.Equal(t, base, points[0].Timestamp)
+	assert.Equal(t, 3, points[0].Samples)
+	assert.InDelta(t, 500, *points[0].PowerWatts, 0.001)
+	assert.InDelta(t, 400, *points[0].MinPowerWatts, 0.001)
+	assert.InDelta(t, 600, *points[0].MaxPowerWatts, 0.001)
+	assert.InDelta(t, 20, *points[0].InletTempCelsius, 0.001)
+
+	assert.Equal(t, base.Add(time.Minute), points[1].Timestamp)
+	assert.InDelta(t, 300, *points[1].PowerWatts, 0.001)
+
+	assert.Equal(t, "node-2", points[2].NodeID)
+	assert.Zero(t, points[2].Samples)
+	assert.Nil(t, points[2].PowerWatts)
+	assert.InDelta(t, 25, *points[2].MaxInletTemp, 0.001)
+
+	wide, err := st.ListTelemetry(ctx, model.TelemetryQuery{
+		NodeIDs: []string{"node-1"},
+		Since:   base,
+		Step:    5 * time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, wide, 1)
+	assert.Equal(t, 4, wide[0].Samples)
+	assert.InDelta(t, 450, *wide[0].PowerWatts, 0.001)
+	assert.InDelta(t, 21, *wide[0].InletTempCelsius, 0.001)
+	assert.InDelta(t, 22, *wide[0].MaxInletTemp, 0.001)
+
+	deleted, err := st.PruneTelemetry(ctx, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	remaining, err := st.ListTelemetry(ctx, model.TelemetryQuery{
+		NodeIDs: []string{"node-1", "node-2"},
+		Since:   base,
+		Step:    time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, base.Add(time.Minute), remaining[0].Timestamp)
+}
@@ -0,0 +1,263 @@
+// Package store provides transition template persistence for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+var transitionTemplateColumns = []string{
+	"name",
+	"version",
+	"description",
+	"spec",
+	"created_by",
+	"created_at",
+}
+
+// ListTransitionTemplates returns the latest version of every template ordered by name.
+func (s *PostgresStore) ListTransitionTemplates(ctx context.Context) ([]model.TransitionTemplate, error) {
+	query := s.sb.
+		Select(transitionTemplateColumns...).
+		Options("DISTINCT ON (name)").
+		From("power.transition_templates").
+		OrderBy("name", "version DESC")
+	return s.queryTransitionTemplates(ctx, query)
+}
+
+// ListTransitionTemplateVersions returns every stored version of one template, newest first.
+func (s *PostgresStore) ListTransitionTemplateVersions(ctx context.Context, name string) ([]model.TransitionTemplate, error) {
+	query := s.sb.
+		Select(transitionTemplateColumns...).
+		From("power.transition_templates").
+		Where(sq.Eq{"name": strings.TrimSpace(name)}).
+		OrderBy("version DESC")
+	items, err := s.queryTransitionTemplates(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrNotFound
+	}
+	return items, nil
+}
+
+// GetTransitionTemplate returns one template version; version zero selects the latest.
+func (s *PostgresStore) GetTransitionTemplate(ctx context.Context, name string, version int) (model.TransitionTemplate, error) {
+	query := s.sb.
+		Select(transitionTemplateColumns...).
+		From("power.transition_templates").
+		Where(sq.Eq{"name": strings.TrimSpace(name)}).
+		OrderBy("version DESC").
+		Limit(1)
+	if version > 0 {
+		query = query.Where(sq.Eq{"version": version})
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("building template query: %w", err)
+	}
+
+	item, err := scanTransitionTemplate(s.db.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TransitionTemplate{}, ErrNotFound
+		}
+		return model.TransitionTemplate{}, fmt.Errorf("loading template %q: %w", name, err)
+	}
+	return item, nil
+}
+
+// CreateTransitionTemplate stores version 1 of a new template. It returns
+// ErrConflict when a template with the same name exists.
+func (s *PostgresStore) CreateTransitionTemplate(
+	ctx context.Context,
+	item model.TransitionTemplate,
+) (model.TransitionTemplate, error) {
+	item.Version = 1
+	created, err := s.insertTransitionTemplate(ctx, s.db, item)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TransitionTemplate{}, ErrConflict
+		}
+		return model.TransitionTemplate{}, err
+	}
+	return created, nil
+}
+
+// UpdateTransitionTemplate stores a template as the next version of an
+// existing template. Earlier versions are kept.
+func (s *PostgresStore) UpdateTransitionTemplate(
+	ctx context.Context,
+	item model.TransitionTemplate,
+) (model.TransitionTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("starting template update transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	sqlStr, args, err := s.sb.
+		Select("COALESCE(MAX(version), 0)").
+		From("power.transition_templates").
+		Where(sq.Eq{"name": strings.TrimSpace(item.Name)}).
+		ToSql()
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("building template version query: %w", err)
+	}
+	var latest int
+	if scanErr := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&latest); scanErr != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("loading latest version of template %q: %w", item.Name, scanErr)
+	}
+	if latest == 0 {
+		return model.TransitionTemplate{}, ErrNotFound
+	}
+
+	item.Version = latest + 1
+	updated, err := s.insertTransitionTemplate(ctx, tx, item)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// A concurrent update took this version.
+			return model.TransitionTemplate{}, ErrConflict
+		}
+		return model.TransitionTemplate{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("committing template update: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteTransitionTemplate removes every version of a template. Transitions
+// started from it keep their template reference.
+func (s *PostgresStore) DeleteTransitionTemplate(ctx context.Context, name string) error {
+	sqlStr, args, err := s.sb.
+		Delete("power.transition_templates").
+		Where(sq.Eq{"name": strings.TrimSpace(name)}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building template delete query: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("deleting template %q: %w", name, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading deleted template count: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// insertTransitionTemplate inserts one template version and returns
+// sql.ErrNoRows when that version already exists.
+func (s *PostgresStore) insertTransitionTemplate(
+	ctx context.Context,
+	runner interface {
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	},
+	item model.TransitionTemplate,
+) (model.TransitionTemplate, error) {
+	name := strings.TrimSpace(item.Name)
+	if name == "" {
+		return model.TransitionTemplate{}, fmt.Errorf("template name is required")
+	}
+	spec, err := json.Marshal(item.Spec)
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("marshaling template spec: %w", err)
+	}
+
+	sqlStr, args, err := s.sb.
+		Insert("power.transition_templates").
+		Columns(transitionTemplateColumns...).
+		Values(
+			name,
+			item.Version,
+			strings.TrimSpace(item.Description),
+			spec,
+			strings.TrimSpace(item.CreatedBy),
+			time.Now().UTC(),
+		).
+		Suffix(`
+ON CONFLICT (name, version) DO NOTHING
+RETURNING ` + strings.Join(transitionTemplateColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("building template insert query: %w", err)
+	}
+
+	created, err := scanTransitionTemplate(runner.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TransitionTemplate{}, err
+		}
+		return model.TransitionTemplate{}, fmt.Errorf("inserting template %q: %w", name, err)
+	}
+	return created, nil
+}
+
+func (s *PostgresStore) queryTransitionTemplates(
+	ctx context.Context,
+	query sq.SelectBuilder,
+) ([]model.TransitionTemplate, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building template list query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing templates: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.TransitionTemplate, 0)
+	for rows.Next() {
+		item, scanErr := scanTransitionTemplate(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scanning template row: %w", scanErr)
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating template rows: %w", rowsErr)
+	}
+	return items, nil
+}
+
+func scanTransitionTemplate(scanner interface {
+	Scan(dest ...any) error
+}) (model.TransitionTemplate, error) {
+	var out model.TransitionTemplate
+	var specRaw []byte
+
+	if err := scanner.Scan(
+		&out.Name,
+		&out.Version,
+		&out.Description,
+		&specRaw,
+		&out.CreatedBy,
+		&out.CreatedAt,
+	); err != nil {
+		return model.TransitionTemplate{}, err
+	}
+	if err := json.Unmarshal(specRaw, &out.Spec); err != nil {
+		return model.TransitionTemplate{}, fmt.Errorf("decoding template spec: %w", err)
+	}
+	return out, nil
+}
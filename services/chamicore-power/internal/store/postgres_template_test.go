@@ -0,0 +1,92 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+func TestPostgresStore_TransitionTemplates(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	created, err := st.CreateTransitionTemplate(ctx, model.TransitionTemplate{
+		Name:        "drain-rack",
+		Description: "Drain rack 1",
+		CreatedBy:   "ops",
+		Spec: model.TemplateSpec{
+			Operation:  "GracefulShutdown",
+			Groups:     []string{"rack-1"},
+			BatchSize:  32,
+			Escalation: &model.TemplateEscalation{AfterSeconds: 300},
+			Guard:      &model.TemplateGuard{MaxNodes: 64, RequireConfirmation: true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Version)
+	assert.Equal(t, "ops", created.CreatedBy)
+
+	_, err = st.CreateTransitionTemplate(ctx, model.TransitionTemplate{
+		Name: "drain-rack",
+		Spec: model.TemplateSpec{Operation: "On"},
+	})
+	require.ErrorIs(t, err, store.ErrConflict)
+
+	updated, err := st.UpdateTransitionTemplate(ctx, model.TransitionTemplate{
+		Name: "drain-rack",
+		Spec: model.TemplateSpec{Operation: "GracefulShutdown", Groups: []string{"rack-1"}, BatchSize: 16},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+
+	_, err = st.UpdateTransitionTemplate(ctx, model.TransitionTemplate{
+		Name: "missing",
+		Spec: model.TemplateSpec{Operation: "On"},
+	})
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	_, err = st.CreateTransitionTemplate(ctx, model.TransitionTemplate{
+		Name: "all-on",
+		Spec: model.TemplateSpec{Operation: "On", Groups: []string{"compute"}},
+	})
+	require.NoError(t, err)
+
+	latest, err := st.GetTransitionTemplate(ctx, "drain-rack", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+	assert.Equal(t, 16, latest.Spec.BatchSize)
+
+	first, err := st.GetTransitionTemplate(ctx, "drain-rack", 1)
+	require.NoError(t, err)
+	require.NotNil(t, first.Spec.Escalation)
+	assert.Equal(t, 300, first.Spec.Escalation.AfterSeconds)
+	require.NotNil(t, first.Spec.Guard)
+	assert.True(t, first.Spec.Guard.RequireConfirmation)
+
+	_, err = st.GetTransitionTemplate(ctx, "drain-rack", 9)
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	items, err := st.ListTransitionTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "all-on", items[0].Name)
+	assert.Equal(t, "drain-rack", items[1].Name)
+	assert.Equal(t, 2, items[1].Version)
+
+	versions, err := st.ListTransitionTemplateVersions(ctx, "drain-rack")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+
+	require.NoError(t, st.DeleteTransitionTemplate(ctx, "drain-rack"))
+	require.ErrorIs(t, st.DeleteTransitionTemplate(ctx, "drain-rack"), store.ErrNotFound)
+	_, err = st.ListTransitionTemplateVersions(ctx, "drain-rack")
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
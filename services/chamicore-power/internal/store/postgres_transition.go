@@ -4,6 +4,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -20,6 +21,63 @@ const (
 	maxTransitionPageLimit     = 1000
 )
 
+var (
+	transitionColumns = []string{
+		"id",
+		"request_id",
+		"operation",
+		"state",
+		"requested_by",
+		"dry_run",
+		"target_count",
+		"batch_size",
+		"chunk_size",
+		"escalate_after_seconds",
+		"template_name",
+		"template_version",
+		"approval_reason",
+		"approval_expires_at",
+		"parent_id",
+		"exclusions",
+		"pcs_operation",
+		"success_count",
+		"failure_count",
+		"queued_at",
+		"started_at",
+		"completed_at",
+		"created_at",
+		"updated_at",
+	}
+	transitionTaskColumns = []string{
+		"id",
+		"transition_id",
+		"node_id",
+		"bmc_id",
+		"bmc_endpoint",
+		"operation",
+		"state",
+		"dry_run",
+		"attempt_count",
+		"final_power_state",
+		"error_detail",
+		"boot_target",
+		"boot_wait_ready",
+		"boot_stage",
+		"boot_stages",
+		"power_cap_watts",
+		"boot_override_target",
+		"boot_override_mode",
+		"media_image",
+		"reset_operation",
+		"escalated",
+		"queued_at",
+		"started_at",
+		"completed_at",
+		"created_at",
+		"updated_at",
+	}
+)
+
 // CreateTransition persists a transition row and all per-node task rows.
 func (s *PostgresStore) CreateTransition(
 	ctx context.Context,
@@ -59,6 +117,17 @@ func (s *PostgresStore) CreateTransition(
 	if err := outbox.WriteContext(ctx, tx, lifecycleEvent); err != nil {
 		return engine.Transition{}, nil, fmt.Errorf("writing transition lifecycle outbox event: %w", err)
 	}
+	if createdTransition.State == engine.TransitionStatePendingApproval {
+		if err := s.insertTransitionApprovalTx(ctx, tx, createdTransition, engine.ApprovalDecision{
+			TransitionID: createdTransition.ID,
+			Action:       engine.ApprovalActionRequested,
+			Principal:    createdTransition.RequestedBy,
+			Reason:       createdTransition.ApprovalReason,
+			DecidedAt:    createdTransition.CreatedAt,
+		}); err != nil {
+			return engine.Transition{}, nil, err
+		}
+	}
 
 	for _, task := range createdTasks {
 		if !isTerminalTaskState(task.State) {
@@ -180,9 +249,19 @@ func (s *PostgresStore) UpdateTransitionTask(ctx context.Context, task engine.Ta
 	task.State = strings.TrimSpace(task.State)
 	task.FinalPowerState = strings.TrimSpace(task.FinalPowerState)
 	task.ErrorDetail = strings.TrimSpace(task.ErrorDetail)
+	task.BootTarget = strings.TrimSpace(task.BootTarget)
+	task.BootStage = strings.TrimSpace(task.BootStage)
+	task.BootOverrideTarget = strings.TrimSpace(task.BootOverrideTarget)
+	task.BootOverrideMode = strings.TrimSpace(task.BootOverrideMode)
+	task.MediaImage = strings.TrimSpace(task.MediaImage)
+	task.ResetOperation = strings.TrimSpace(task.ResetOperation)
 	if task.UpdatedAt.IsZero() {
 		task.UpdatedAt = time.Now().UTC()
 	}
+	bootStages, err := marshalBootStages(task.BootStages)
+	if err != nil {
+		return engine.Task{}, err
+	}
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -207,6 +286,16 @@ func (s *PostgresStore) UpdateTransitionTask(ctx context.Context, task engine.Ta
 		Set("attempt_count", task.AttemptCount).
 		Set("final_power_state", task.FinalPowerState).
 		Set("error_detail", task.ErrorDetail).
+		Set("boot_target", task.BootTarget).
+		Set("boot_wait_ready", task.BootWaitReady).
+		Set("boot_stage", task.BootStage).
+		Set("boot_stages", bootStages).
+		Set("power_cap_watts", optionalIntValue(task.PowerCapWatts)).
+		Set("boot_override_target", task.BootOverrideTarget).
+		Set("boot_override_mode", task.BootOverrideMode).
+		Set("media_image", task.MediaImage).
+		Set("reset_operation", task.ResetOperation).
+		Set("escalated", task.Escalated).
 		Set("queued_at", task.QueuedAt.UTC()).
 		Set("started_at", optionalTimeValue(task.StartedAt)).
 		Set("completed_at", optionalTimeValue(task.CompletedAt)).
@@ -267,22 +356,7 @@ func (s *PostgresStore) ListTransitions(ctx context.Context, limit, offset int)
 	}
 
 	query := s.sb.
-		Select(
-			"id",
-			"request_id",
-			"operation",
-			"state",
-			"requested_by",
-			"dry_run",
-			"target_count",
-			"success_count",
-			"failure_count",
-			"queued_at",
-			"started_at",
-			"completed_at",
-			"created_at",
-			"updated_at",
-		).
+		Select(transitionColumns...).
 		From("power.transitions").
 		OrderBy("queued_at DESC", "id DESC").
 		Limit(safeUint64(limit)).
@@ -322,22 +396,7 @@ func (s *PostgresStore) GetTransition(ctx context.Context, id string) (engine.Tr
 	}
 
 	query := s.sb.
-		Select(
-			"id",
-			"request_id",
-			"operation",
-			"state",
-			"requested_by",
-			"dry_run",
-			"target_count",
-			"success_count",
-			"failure_count",
-			"queued_at",
-			"started_at",
-			"completed_at",
-			"created_at",
-			"updated_at",
-		).
+		Select(transitionColumns...).
 		From("power.transitions").
 		Where(sq.Eq{"id": id})
 
@@ -366,24 +425,7 @@ func (s *PostgresStore) ListTransitionTasks(ctx context.Context, transitionID st
 	}
 
 	query := s.sb.
-		Select(
-			"id",
-			"transition_id",
-			"node_id",
-			"bmc_id",
-			"bmc_endpoint",
-			"operation",
-			"state",
-			"dry_run",
-			"attempt_count",
-			"final_power_state",
-			"error_detail",
-			"queued_at",
-			"started_at",
-			"completed_at",
-			"created_at",
-			"updated_at",
-		).
+		Select(transitionTaskColumns...).
 		From("power.transition_tasks").
 		Where(sq.Eq{"transition_id": transitionID}).
 		OrderBy("node_id ASC")
@@ -414,7 +456,151 @@ func (s *PostgresStore) ListTransitionTasks(ctx context.Context, transitionID st
 	return tasks, nil
 }
 
+// ListTransitionTasksPage returns one page of the tasks of a transition,
+// ordered by node ID like ListTransitionTasks.
+func (s *PostgresStore) ListTransitionTasksPage(
+	ctx context.Context,
+	transitionID string,
+	limit int,
+	offset int,
+) ([]engine.Task, error) {
+	transitionID = strings.TrimSpace(transitionID)
+	if transitionID == "" {
+		return []engine.Task{}, nil
+	}
+	limit = normalizeTransitionPageLimit(limit)
+	if offset < 0 {
+		offset = 0
+	}
+
+	sqlStr, args, err := s.sb.
+		Select(transitionTaskColumns...).
+		From("power.transition_tasks").
+		Where(sq.Eq{"transition_id": transitionID}).
+		OrderBy("node_id ASC").
+		Limit(safeUint64(limit)).
+		Offset(safeUint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building transition task page query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing transition task page: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]engine.Task, 0, limit)
+	for rows.Next() {
+		task, scanErr := scanTransitionTask(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		tasks = append(tasks, task)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating transition task page rows: %w", rowsErr)
+	}
+	return tasks, nil
+}
+
+// CountTransitionTasksByState returns how many tasks of a transition are in
+// each task state.
+func (s *PostgresStore) CountTransitionTasksByState(ctx context.Context, transitionID string) (map[string]int, error) {
+	counts := make(map[string]int)
+	transitionID = strings.TrimSpace(transitionID)
+	if transitionID == "" {
+		return counts, nil
+	}
+
+	sqlStr, args, err := s.sb.
+		Select("state", "COUNT(*)").
+		From("power.transition_tasks").
+		Where(sq.Eq{"transition_id": transitionID}).
+		GroupBy("state").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building transition task count query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("counting transition tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			state string
+			count int
+		)
+		if scanErr := rows.Scan(&state, &count); scanErr != nil {
+			return nil, fmt.Errorf("scanning transition task count row: %w", scanErr)
+		}
+		counts[state] = count
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating transition task count rows: %w", rowsErr)
+	}
+	return counts, nil
+}
+
+// activeTransitionStates are the transition states that count against a
+// caller's active transition quota.
+var activeTransitionStates = []string{
+	engine.TransitionStatePending,
+	engine.TransitionStateRunning,
+	engine.TransitionStatePendingApproval,
+}
+
+// CountActiveTransitionsByRequester returns how many pending, running and
+// pending-approval transitions each requester has. An empty requesters list
+// counts every requester.
+func (s *PostgresStore) CountActiveTransitionsByRequester(
+	ctx context.Context,
+	requesters []string,
+) (map[string]int, error) {
+	query := s.sb.
+		Select("requested_by", "COUNT(*)").
+		From("power.transitions").
+		Where(sq.Eq{"state": activeTransitionStates}).
+		GroupBy("requested_by")
+	if len(requesters) > 0 {
+		query = query.Where(sq.Eq{"requested_by": requesters})
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building active transition count query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("counting active transitions: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			requester string
+			count     int
+		)
+		if scanErr := rows.Scan(&requester, &count); scanErr != nil {
+			return nil, fmt.Errorf("scanning active transition count row: %w", scanErr)
+		}
+		counts[requester] = count
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating active transition count rows: %w", rowsErr)
+	}
+	return counts, nil
+}
+
 // ListLatestTransitionTasksByNode returns latest task row per node for requested node IDs.
+// Power-cap, boot-override and virtual-media tasks are skipped because they do
+// not change node power state.
 func (s *PostgresStore) ListLatestTransitionTasksByNode(ctx context.Context, nodeIDs []string) ([]engine.Task, error) {
 	_, queryNodeIDs := normalizeNodeIDs(nodeIDs)
 	if len(queryNodeIDs) == 0 {
@@ -434,6 +620,16 @@ func (s *PostgresStore) ListLatestTransitionTasksByNode(ctx context.Context, nod
 			"attempt_count",
 			"final_power_state",
 			"error_detail",
+			"boot_target",
+			"boot_wait_ready",
+			"boot_stage",
+			"boot_stages",
+			"power_cap_watts",
+			"boot_override_target",
+			"boot_override_mode",
+			"media_image",
+			"reset_operation",
+			"escalated",
 			"queued_at",
 			"started_at",
 			"completed_at",
@@ -442,6 +638,7 @@ func (s *PostgresStore) ListLatestTransitionTasksByNode(ctx context.Context, nod
 		).
 		From("power.transition_tasks").
 		Where(sq.Eq{"node_id": queryNodeIDs}).
+		Where(sq.NotEq{"operation": engine.NonPowerOperations}).
 		OrderBy("node_id ASC", "updated_at DESC", "created_at DESC")
 
 	sqlStr, args, err := query.ToSql()
@@ -490,6 +687,10 @@ func (s *PostgresStore) insertTransitionTx(
 	if transition.UpdatedAt.IsZero() {
 		transition.UpdatedAt = transition.CreatedAt
 	}
+	exclusions, err := marshalExclusions(transition.Exclusions)
+	if err != nil {
+		return engine.Transition{}, err
+	}
 
 	query := s.sb.Insert("power.transitions")
 	if transition.ID != "" {
@@ -502,6 +703,16 @@ func (s *PostgresStore) insertTransitionTx(
 				"requested_by",
 				"dry_run",
 				"target_count",
+				"batch_size",
+				"chunk_size",
+				"escalate_after_seconds",
+				"template_name",
+				"template_version",
+				"approval_reason",
+				"approval_expires_at",
+				"parent_id",
+				"exclusions",
+				"pcs_operation",
 				"success_count",
 				"failure_count",
 				"queued_at",
@@ -518,6 +729,16 @@ func (s *PostgresStore) insertTransitionTx(
 				transition.RequestedBy,
 				transition.DryRun,
 				transition.TargetCount,
+				transition.BatchSize,
+				transition.ChunkSize,
+				int(transition.EscalateAfter/time.Second),
+				strings.TrimSpace(transition.TemplateName),
+				transition.TemplateVersion,
+				strings.TrimSpace(transition.ApprovalReason),
+				optionalTimeValue(transition.ApprovalExpiresAt),
+				optionalStringValue(transition.ParentID),
+				exclusions,
+				strings.TrimSpace(transition.PCSOperation),
 				transition.SuccessCount,
 				transition.FailureCount,
 				transition.QueuedAt.UTC(),
@@ -535,6 +756,16 @@ func (s *PostgresStore) insertTransitionTx(
 				"requested_by",
 				"dry_run",
 				"target_count",
+				"batch_size",
+				"chunk_size",
+				"escalate_after_seconds",
+				"template_name",
+				"template_version",
+				"approval_reason",
+				"approval_expires_at",
+				"parent_id",
+				"exclusions",
+				"pcs_operation",
 				"success_count",
 				"failure_count",
 				"queued_at",
@@ -550,6 +781,16 @@ func (s *PostgresStore) insertTransitionTx(
 				transition.RequestedBy,
 				transition.DryRun,
 				transition.TargetCount,
+				transition.BatchSize,
+				transition.ChunkSize,
+				int(transition.EscalateAfter/time.Second),
+				strings.TrimSpace(transition.TemplateName),
+				transition.TemplateVersion,
+				strings.TrimSpace(transition.ApprovalReason),
+				optionalTimeValue(transition.ApprovalExpiresAt),
+				optionalStringValue(transition.ParentID),
+				exclusions,
+				strings.TrimSpace(transition.PCSOperation),
 				transition.SuccessCount,
 				transition.FailureCount,
 				transition.QueuedAt.UTC(),
@@ -568,6 +809,16 @@ RETURNING id,
           requested_by,
           dry_run,
           target_count,
+          batch_size,
+          chunk_size,
+          escalate_after_seconds,
+          template_name,
+          template_version,
+          approval_reason,
+          approval_expires_at,
+          parent_id,
+          exclusions,
+          pcs_operation,
           success_count,
           failure_count,
           queued_at,
@@ -604,9 +855,19 @@ func (s *PostgresStore) insertTransitionTaskTx(
 	task.State = strings.TrimSpace(task.State)
 	task.FinalPowerState = strings.TrimSpace(task.FinalPowerState)
 	task.ErrorDetail = strings.TrimSpace(task.ErrorDetail)
+	task.BootTarget = strings.TrimSpace(task.BootTarget)
+	task.BootStage = strings.TrimSpace(task.BootStage)
+	task.BootOverrideTarget = strings.TrimSpace(task.BootOverrideTarget)
+	task.BootOverrideMode = strings.TrimSpace(task.BootOverrideMode)
+	task.MediaImage = strings.TrimSpace(task.MediaImage)
+	task.ResetOperation = strings.TrimSpace(task.ResetOperation)
 	if task.QueuedAt.IsZero() {
 		task.QueuedAt = now
 	}
+	bootStages, err := marshalBootStages(task.BootStages)
+	if err != nil {
+		return engine.Task{}, err
+	}
 	if task.CreatedAt.IsZero() {
 		task.CreatedAt = now
 	}
@@ -629,6 +890,16 @@ func (s *PostgresStore) insertTransitionTaskTx(
 				"attempt_count",
 				"final_power_state",
 				"error_detail",
+				"boot_target",
+				"boot_wait_ready",
+				"boot_stage",
+				"boot_stages",
+				"power_cap_watts",
+				"boot_override_target",
+				"boot_override_mode",
+				"media_image",
+				"reset_operation",
+				"escalated",
 				"queued_at",
 				"started_at",
 				"completed_at",
@@ -647,6 +918,16 @@ func (s *PostgresStore) insertTransitionTaskTx(
 				task.AttemptCount,
 				task.FinalPowerState,
 				task.ErrorDetail,
+				task.BootTarget,
+				task.BootWaitReady,
+				task.BootStage,
+				bootStages,
+				optionalIntValue(task.PowerCapWatts),
+				task.BootOverrideTarget,
+				task.BootOverrideMode,
+				task.MediaImage,
+				task.ResetOperation,
+				task.Escalated,
 				task.QueuedAt.UTC(),
 				optionalTimeValue(task.StartedAt),
 				optionalTimeValue(task.CompletedAt),
@@ -666,6 +947,16 @@ func (s *PostgresStore) insertTransitionTaskTx(
 				"attempt_count",
 				"final_power_state",
 				"error_detail",
+				"boot_target",
+				"boot_wait_ready",
+				"boot_stage",
+				"boot_stages",
+				"power_cap_watts",
+				"boot_override_target",
+				"boot_override_mode",
+				"media_image",
+				"reset_operation",
+				"escalated",
 				"queued_at",
 				"started_at",
 				"completed_at",
@@ -683,6 +974,16 @@ func (s *PostgresStore) insertTransitionTaskTx(
 				task.AttemptCount,
 				task.FinalPowerState,
 				task.ErrorDetail,
+				task.BootTarget,
+				task.BootWaitReady,
+				task.BootStage,
+				bootStages,
+				optionalIntValue(task.PowerCapWatts),
+				task.BootOverrideTarget,
+				task.BootOverrideMode,
+				task.MediaImage,
+				task.ResetOperation,
+				task.Escalated,
 				task.QueuedAt.UTC(),
 				optionalTimeValue(task.StartedAt),
 				optionalTimeValue(task.CompletedAt),
@@ -703,6 +1004,16 @@ RETURNING id,
           attempt_count,
           final_power_state,
           error_detail,
+          boot_target,
+          boot_wait_ready,
+          boot_stage,
+          boot_stages,
+          power_cap_watts,
+          boot_override_target,
+          boot_override_mode,
+          media_image,
+          reset_operation,
+          escalated,
           queued_at,
           started_at,
           completed_at,
@@ -734,6 +1045,10 @@ func scanTransition(scanner interface {
 	var out engine.Transition
 	var startedAt sql.NullTime
 	var completedAt sql.NullTime
+	var approvalExpiresAt sql.NullTime
+	var parentID sql.NullString
+	var exclusionsRaw []byte
+	var escalateAfterSeconds int
 
 	err := scanner.Scan(
 		&out.ID,
@@ -743,6 +1058,16 @@ func scanTransition(scanner interface {
 		&out.RequestedBy,
 		&out.DryRun,
 		&out.TargetCount,
+		&out.BatchSize,
+		&out.ChunkSize,
+		&escalateAfterSeconds,
+		&out.TemplateName,
+		&out.TemplateVersion,
+		&out.ApprovalReason,
+		&approvalExpiresAt,
+		&parentID,
+		&exclusionsRaw,
+		&out.PCSOperation,
 		&out.SuccessCount,
 		&out.FailureCount,
 		&out.QueuedAt,
@@ -755,8 +1080,15 @@ func scanTransition(scanner interface {
 		return engine.Transition{}, err
 	}
 
+	out.EscalateAfter = time.Duration(escalateAfterSeconds) * time.Second
+	out.ApprovalExpiresAt = nullTimePtr(approvalExpiresAt)
+	out.ParentID = parentID.String
 	out.StartedAt = nullTimePtr(startedAt)
 	out.CompletedAt = nullTimePtr(completedAt)
+	out.Exclusions, err = unmarshalExclusions(exclusionsRaw)
+	if err != nil {
+		return engine.Transition{}, err
+	}
 	return out, nil
 }
 
@@ -766,6 +1098,8 @@ func scanTransitionTask(scanner interface {
 	var out engine.Task
 	var startedAt sql.NullTime
 	var completedAt sql.NullTime
+	var bootStagesRaw []byte
+	var powerCapWatts sql.NullInt64
 
 	err := scanner.Scan(
 		&out.ID,
@@ -779,6 +1113,16 @@ func scanTransitionTask(scanner interface {
 		&out.AttemptCount,
 		&out.FinalPowerState,
 		&out.ErrorDetail,
+		&out.BootTarget,
+		&out.BootWaitReady,
+		&out.BootStage,
+		&bootStagesRaw,
+		&powerCapWatts,
+		&out.BootOverrideTarget,
+		&out.BootOverrideMode,
+		&out.MediaImage,
+		&out.ResetOperation,
+		&out.Escalated,
 		&out.QueuedAt,
 		&startedAt,
 		&completedAt,
@@ -791,9 +1135,77 @@ func scanTransitionTask(scanner interface {
 
 	out.StartedAt = nullTimePtr(startedAt)
 	out.CompletedAt = nullTimePtr(completedAt)
+	out.PowerCapWatts = nullIntPtr(powerCapWatts)
+	out.BootStages, err = unmarshalBootStages(bootStagesRaw)
+	if err != nil {
+		return engine.Task{}, err
+	}
 	return out, nil
 }
 
+// bootStageRecord is the JSONB shape of one recorded boot stage.
+type bootStageRecord struct {
+	Stage      string    `json:"stage"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+func marshalBootStages(stages []engine.BootStage) ([]byte, error) {
+	records := make([]bootStageRecord, 0, len(stages))
+	for _, stage := range stages {
+		records = append(records, bootStageRecord{
+			Stage:      strings.TrimSpace(stage.Stage),
+			ObservedAt: stage.ObservedAt.UTC(),
+		})
+	}
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("encoding boot stages: %w", err)
+	}
+	return raw, nil
+}
+
+func unmarshalBootStages(raw []byte) ([]engine.BootStage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var records []bootStageRecord
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("decoding boot stages: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	stages := make([]engine.BootStage, 0, len(records))
+	for _, record := range records {
+		stages = append(stages, engine.BootStage{Stage: record.Stage, ObservedAt: record.ObservedAt.UTC()})
+	}
+	return stages, nil
+}
+
+// marshalExclusions encodes transition exclusions, storing NULL when the
+// request excluded nothing.
+func marshalExclusions(exclusions *engine.Exclusions) (any, error) {
+	if exclusions == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(exclusions)
+	if err != nil {
+		return nil, fmt.Errorf("encoding transition exclusions: %w", err)
+	}
+	return raw, nil
+}
+
+func unmarshalExclusions(raw []byte) (*engine.Exclusions, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var exclusions engine.Exclusions
+	if err := json.Unmarshal(raw, &exclusions); err != nil {
+		return nil, fmt.Errorf("decoding transition exclusions: %w", err)
+	}
+	return &exclusions, nil
+}
+
 func optionalTimeValue(v *time.Time) any {
 	if v == nil {
 		return nil
@@ -809,6 +1221,28 @@ func nullTimePtr(v sql.NullTime) *time.Time {
 	return &t
 }
 
+func optionalStringValue(v string) any {
+	if v = strings.TrimSpace(v); v == "" {
+		return nil
+	}
+	return v
+}
+
+func optionalIntValue(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
 func normalizeTransitionPageLimit(limit int) int {
 	switch {
 	case limit <= 0:
@@ -4,6 +4,7 @@ package store_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -160,3 +161,417 @@ func TestPostgresStore_ListLatestTransitionTasksByNode(t *testing.T) {
 func ptrTime(v time.Time) *time.Time {
 	return &v
 }
+
+func TestPostgresStore_TransitionTaskBootStages(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	_, tasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   "On",
+		State:       engine.TransitionStatePending,
+		TargetCount: 1,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, []engine.Task{{
+		NodeID:        "node-1",
+		BMCID:         "bmc-1",
+		Operation:     "On",
+		State:         engine.TaskStatePending,
+		BootTarget:    "OSRunning",
+		BootWaitReady: true,
+		QueuedAt:      now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "OSRunning", tasks[0].BootTarget)
+	assert.True(t, tasks[0].BootWaitReady)
+	assert.Empty(t, tasks[0].BootStages)
+
+	task := tasks[0]
+	task.BootStage = "OSBootStarted"
+	task.BootStages = []engine.BootStage{
+		{Stage: "PrimaryProcessorInitializationStarted", ObservedAt: now.Add(time.Second)},
+		{Stage: "OSBootStarted", ObservedAt: now.Add(2 * time.Second)},
+	}
+	task.UpdatedAt = now.Add(2 * time.Second)
+
+	_, err = st.UpdateTransitionTask(ctx, task)
+	require.NoError(t, err)
+
+	listed, err := st.ListTransitionTasks(ctx, task.TransitionID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "OSBootStarted", listed[0].BootStage)
+	require.Len(t, listed[0].BootStages, 2)
+	assert.Equal(t, "PrimaryProcessorInitializationStarted", listed[0].BootStages[0].Stage)
+	assert.True(t, listed[0].BootStages[1].ObservedAt.Equal(now.Add(2*time.Second)))
+}
+
+func TestPostgresStore_PowerCapTasks(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	_, onTasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   "On",
+		State:       engine.TransitionStateCompleted,
+		TargetCount: 1,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, []engine.Task{{
+		NodeID:    "node-1",
+		BMCID:     "bmc-1",
+		Operation: "On",
+		State:     engine.TaskStateSucceeded,
+		QueuedAt:  now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}})
+	require.NoError(t, err)
+	require.Len(t, onTasks, 1)
+	assert.Nil(t, onTasks[0].PowerCapWatts)
+
+	limit := 450
+	later := now.Add(time.Minute)
+	_, capTasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   engine.OperationPowerCap,
+		State:       engine.TransitionStateCompleted,
+		TargetCount: 1,
+		QueuedAt:    later,
+		CreatedAt:   later,
+		UpdatedAt:   later,
+	}, []engine.Task{{
+		NodeID:        "node-1",
+		BMCID:         "bmc-1",
+		Operation:     engine.OperationPowerCap,
+		State:         engine.TaskStateSucceeded,
+		PowerCapWatts: &limit,
+		QueuedAt:      later,
+		CreatedAt:     later,
+		UpdatedAt:     later,
+	}})
+	require.NoError(t, err)
+	require.Len(t, capTasks, 1)
+	require.NotNil(t, capTasks[0].PowerCapWatts)
+	assert.Equal(t, 450, *capTasks[0].PowerCapWatts)
+
+	listed, err := st.ListTransitionTasks(ctx, capTasks[0].TransitionID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.NotNil(t, listed[0].PowerCapWatts)
+	assert.Equal(t, 450, *listed[0].PowerCapWatts)
+
+	// Power status reflects the latest power operation, not the later cap.
+	latest, err := st.ListLatestTransitionTasksByNode(ctx, []string{"node-1"})
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, "On", latest[0].Operation)
+}
+
+func TestPostgresStore_BootControlTasks(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	_, resetTasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   engine.OperationBootOverrideReset,
+		State:       engine.TransitionStateCompleted,
+		TargetCount: 1,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, []engine.Task{{
+		NodeID:             "node-1",
+		BMCID:              "bmc-1",
+		Operation:          engine.OperationBootOverrideReset,
+		State:              engine.TaskStateRunning,
+		BootOverrideTarget: "Cd",
+		BootOverrideMode:   engine.BootOverrideOnce,
+		MediaImage:         "http://images.example/rescue.iso",
+		ResetOperation:     "ForceRestart",
+		QueuedAt:           now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}})
+	require.NoError(t, err)
+	require.Len(t, resetTasks, 1)
+	assert.Equal(t, "Cd", resetTasks[0].BootOverrideTarget)
+	assert.Equal(t, engine.BootOverrideOnce, resetTasks[0].BootOverrideMode)
+	assert.Equal(t, "http://images.example/rescue.iso", resetTasks[0].MediaImage)
+	assert.Equal(t, "ForceRestart", resetTasks[0].ResetOperation)
+
+	task := resetTasks[0]
+	task.State = engine.TaskStateSucceeded
+	task.FinalPowerState = "On"
+	updated, err := st.UpdateTransitionTask(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, "Cd", updated.BootOverrideTarget)
+	assert.Equal(t, "ForceRestart", updated.ResetOperation)
+
+	later := now.Add(time.Minute)
+	_, ejectTasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   engine.OperationEjectMedia,
+		State:       engine.TransitionStateCompleted,
+		TargetCount: 1,
+		QueuedAt:    later,
+		CreatedAt:   later,
+		UpdatedAt:   later,
+	}, []engine.Task{{
+		NodeID:    "node-1",
+		BMCID:     "bmc-1",
+		Operation: engine.OperationEjectMedia,
+		State:     engine.TaskStateSucceeded,
+		QueuedAt:  later,
+		CreatedAt: later,
+		UpdatedAt: later,
+	}})
+	require.NoError(t, err)
+	require.Len(t, ejectTasks, 1)
+	assert.Empty(t, ejectTasks[0].MediaImage)
+
+	// The combined reset changes power; ejecting media afterwards does not.
+	latest, err := st.ListLatestTransitionTasksByNode(ctx, []string{"node-1"})
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, engine.OperationBootOverrideReset, latest[0].Operation)
+}
+
+func TestPostgresStore_TransitionPolicyAndTemplateLink(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	created, tasks, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:       "GracefulShutdown",
+		State:           engine.TransitionStatePending,
+		TargetCount:     1,
+		BatchSize:       32,
+		EscalateAfter:   5 * time.Minute,
+		TemplateName:    "drain-rack",
+		TemplateVersion: 2,
+		QueuedAt:        now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, []engine.Task{{
+		NodeID:    "node-1",
+		BMCID:     "bmc-1",
+		Operation: "GracefulShutdown",
+		State:     engine.TaskStatePending,
+		QueuedAt:  now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.False(t, tasks[0].Escalated)
+
+	loaded, err := st.GetTransition(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 32, loaded.BatchSize)
+	assert.Equal(t, 5*time.Minute, loaded.EscalateAfter)
+	assert.Equal(t, "drain-rack", loaded.TemplateName)
+	assert.Equal(t, 2, loaded.TemplateVersion)
+
+	task := tasks[0]
+	task.State = engine.TaskStateSucceeded
+	task.Escalated = true
+	_, err = st.UpdateTransitionTask(ctx, task)
+	require.NoError(t, err)
+
+	listed, err := st.ListTransitionTasks(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.True(t, listed[0].Escalated)
+}
+
+func TestPostgresStore_TransitionParentLink(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	parent, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   "On",
+		State:       engine.TransitionStateFailed,
+		TargetCount: 1,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, parent.ParentID)
+
+	child, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   "On",
+		State:       engine.TransitionStatePending,
+		TargetCount: 1,
+		ParentID:    parent.ID,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, parent.ID, child.ParentID)
+
+	loaded, err := st.GetTransition(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, parent.ID, loaded.ParentID)
+
+	_, _, err = st.CreateTransition(ctx, engine.Transition{
+		Operation: "On",
+		State:     engine.TransitionStatePending,
+		ParentID:  "missing",
+	}, nil)
+	require.Error(t, err)
+}
+
+func TestPostgresStore_TransitionExclusions(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	plain, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation: "On",
+		State:     engine.TransitionStatePending,
+		QueuedAt:  now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, plain.Exclusions)
+
+	exclusions := &engine.Exclusions{
+		Nodes:    []string{"node-2"},
+		Groups:   []string{"repair"},
+		Excluded: []string{"node-2", "node-3"},
+	}
+	created, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:  "ForceRestart",
+		State:      engine.TransitionStatePending,
+		Exclusions: exclusions,
+		QueuedAt:   now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, exclusions, created.Exclusions)
+
+	loaded, err := st.GetTransition(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, exclusions, loaded.Exclusions)
+}
+
+func TestPostgresStore_TransitionPCSOperation(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	created, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:    "ForceOff",
+		State:        engine.TransitionStatePending,
+		PCSOperation: "Off",
+		QueuedAt:     now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Off", created.PCSOperation)
+
+	loaded, err := st.GetTransition(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Off", loaded.PCSOperation)
+}
+
+func TestPostgresStore_TransitionTaskPaging(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	tasks := make([]engine.Task, 0, 5)
+	for i := 1; i <= 5; i++ {
+		state := engine.TaskStatePending
+		if i <= 2 {
+			state = engine.TaskStateSucceeded
+		}
+		tasks = append(tasks, engine.Task{
+			NodeID:    fmt.Sprintf("node-%d", i),
+			BMCID:     fmt.Sprintf("bmc-%d", i),
+			Operation: "On",
+			State:     state,
+			QueuedAt:  now,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	created, _, err := st.CreateTransition(ctx, engine.Transition{
+		Operation:   "On",
+		State:       engine.TransitionStateRunning,
+		TargetCount: len(tasks),
+		ChunkSize:   2,
+		QueuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, tasks)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.ChunkSize)
+
+	loaded, err := st.GetTransition(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.ChunkSize)
+
+	page, err := st.ListTransitionTasksPage(ctx, created.ID, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "node-3", page[0].NodeID)
+	assert.Equal(t, "node-4", page[1].NodeID)
+
+	last, err := st.ListTransitionTasksPage(ctx, created.ID, 2, 4)
+	require.NoError(t, err)
+	require.Len(t, last, 1)
+	assert.Equal(t, "node-5", last[0].NodeID)
+
+	counts, err := st.CountTransitionTasksByState(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		engine.TaskStateSucceeded: 2,
+		engine.TaskStatePending:   3,
+	}, counts)
+}
+
+func TestPostgresStore_CountActiveTransitionsByRequester(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, tc := range []struct{ requester, state string }{
+		{"alice", engine.TransitionStatePending},
+		{"alice", engine.TransitionStateRunning},
+		{"alice", engine.TransitionStatePendingApproval},
+		{"alice", engine.TransitionStateCompleted},
+		{"bob", engine.TransitionStateRunning},
+		{"carol", engine.TransitionStateFailed},
+	} {
+		_, _, err := st.CreateTransition(ctx, engine.Transition{
+			Operation:   "On",
+			State:       tc.state,
+			RequestedBy: tc.requester,
+			QueuedAt:    now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	counts, err := st.CountActiveTransitionsByRequester(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"alice": 3, "bob": 1}, counts)
+
+	counts, err = st.CountActiveTransitionsByRequester(ctx, []string{"bob", "carol"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"bob": 1}, counts)
+}
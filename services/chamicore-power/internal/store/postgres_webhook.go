@@ -0,0 +1,662 @@
+// Package store provides webhook subscription and delivery persistence for the power service.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+var (
+	webhookColumns = []string{
+		"id",
+		"name",
+		"url",
+		"secret",
+		"event_types",
+		"groups",
+		"enabled",
+		"created_by",
+		"created_at",
+		"updated_at",
+	}
+	webhookDeliveryColumns = []string{
+		"id",
+		"webhook_id",
+		"event_id",
+		"event_type",
+		"subject",
+		"payload",
+		"state",
+		"attempt_count",
+		"next_attempt_at",
+		"last_status_code",
+		"last_error",
+		"delivered_at",
+		"created_at",
+		"updated_at",
+	}
+
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// ListWebhooks returns webhook subscriptions ordered by creation time.
+func (s *PostgresStore) ListWebhooks(ctx context.Context, limit, offset int) ([]model.Webhook, int, error) {
+	limit = normalizeTransitionPageLimit(limit)
+	if offset < 0 {
+		offset = 0
+	}
+
+	countSQL, countArgs, err := s.sb.Select("COUNT(*)").From("power.webhooks").ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building webhook count query: %w", err)
+	}
+	var total int
+	if scanErr := s.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); scanErr != nil {
+		return nil, 0, fmt.Errorf("counting webhooks: %w", scanErr)
+	}
+
+	items, err := s.queryWebhooks(ctx, s.sb.
+		Select(webhookColumns...).
+		From("power.webhooks").
+		OrderBy("created_at", "id").
+		Limit(safeUint64(limit)).
+		Offset(safeUint64(offset)))
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListEnabledWebhooks returns every enabled webhook subscription.
+func (s *PostgresStore) ListEnabledWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	return s.queryWebhooks(ctx, s.sb.
+		Select(webhookColumns...).
+		From("power.webhooks").
+		Where(sq.Eq{"enabled": true}).
+		OrderBy("created_at", "id"))
+}
+
+// GetWebhook returns one webhook subscription.
+func (s *PostgresStore) GetWebhook(ctx context.Context, id string) (model.Webhook, error) {
+	id = strings.TrimSpace(id)
+	if !uuidPattern.MatchString(id) {
+		return model.Webhook{}, ErrNotFound
+	}
+
+	sqlStr, args, err := s.sb.
+		Select(webhookColumns...).
+		From("power.webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("building webhook query: %w", err)
+	}
+	item, err := scanWebhook(s.db.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("loading webhook %q: %w", id, err)
+	}
+	return item, nil
+}
+
+// CreateWebhook stores a new webhook subscription with a generated ID.
+func (s *PostgresStore) CreateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error) {
+	eventTypes, groups, err := marshalWebhookFilters(item)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	now := time.Now().UTC()
+
+	sqlStr, args, err := s.sb.
+		Insert("power.webhooks").
+		Columns("name", "url", "secret", "event_types", "groups", "enabled", "created_by", "created_at", "updated_at").
+		Values(
+			strings.TrimSpace(item.Name),
+			strings.TrimSpace(item.URL),
+			item.Secret,
+			eventTypes,
+			groups,
+			item.Enabled,
+			strings.TrimSpace(item.CreatedBy),
+			now,
+			now,
+		).
+		Suffix("RETURNING " + strings.Join(webhookColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("building webhook insert query: %w", err)
+	}
+	created, err := scanWebhook(s.db.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("inserting webhook %q: %w", item.Name, err)
+	}
+	return created, nil
+}
+
+// UpdateWebhook replaces the settings of an existing webhook subscription.
+func (s *PostgresStore) UpdateWebhook(ctx context.Context, item model.Webhook) (model.Webhook, error) {
+	id := strings.TrimSpace(item.ID)
+	if !uuidPattern.MatchString(id) {
+		return model.Webhook{}, ErrNotFound
+	}
+	eventTypes, groups, err := marshalWebhookFilters(item)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	sqlStr, args, err := s.sb.
+		Update("power.webhooks").
+		Set("name", strings.TrimSpace(item.Name)).
+		Set("url", strings.TrimSpace(item.URL)).
+		Set("secret", item.Secret).
+		Set("event_types", eventTypes).
+		Set("groups", groups).
+		Set("enabled", item.Enabled).
+		Set("updated_at", time.Now().UTC()).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + strings.Join(webhookColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("building webhook update query: %w", err)
+	}
+	updated, err := scanWebhook(s.db.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("updating webhook %q: %w", id, err)
+	}
+	return updated, nil
+}
+
+// DeleteWebhook removes a webhook subscription and its delivery log.
+func (s *PostgresStore) DeleteWebhook(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if !uuidPattern.MatchString(id) {
+		return ErrNotFound
+	}
+
+	sqlStr, args, err := s.sb.
+		Delete("power.webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building webhook delete query: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("deleting webhook %q: %w", id, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading deleted webhook count: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the delivery log of one webhook, newest first.
+func (s *PostgresStore) ListWebhookDeliveries(
+	ctx context.Context,
+	query model.WebhookDeliveryQuery,
+) ([]model.WebhookDelivery, int, error) {
+	webhookID := strings.TrimSpace(query.WebhookID)
+	if !uuidPattern.MatchString(webhookID) {
+		return nil, 0, ErrNotFound
+	}
+	limit := normalizeTransitionPageLimit(query.Limit)
+	offset := max(query.Offset, 0)
+
+	where := sq.And{sq.Eq{"webhook_id": webhookID}}
+	if state := strings.TrimSpace(query.State); state != "" {
+		where = append(where, sq.Eq{"state": state})
+	}
+
+	countSQL, countArgs, err := s.sb.Select("COUNT(*)").From("power.webhook_deliveries").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building webhook delivery count query: %w", err)
+	}
+	var total int
+	if scanErr := s.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); scanErr != nil {
+		return nil, 0, fmt.Errorf("counting webhook deliveries: %w", scanErr)
+	}
+
+	sqlStr, args, err := s.sb.
+		Select(webhookDeliveryColumns...).
+		From("power.webhook_deliveries").
+		Where(where).
+		OrderBy("created_at DESC", "id").
+		Limit(safeUint64(limit)).
+		Offset(safeUint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building webhook delivery list query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.WebhookDelivery, 0)
+	for rows.Next() {
+		item, scanErr := scanWebhookDelivery(rows)
+		if scanErr != nil {
+			return nil, 0, fmt.Errorf("scanning webhook delivery row: %w", scanErr)
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, 0, fmt.Errorf("iterating webhook delivery rows: %w", rowsErr)
+	}
+	return items, total, nil
+}
+
+// RedeliverWebhookDelivery requeues a delivery for an immediate attempt with
+// a fresh attempt budget, typically to replay a dead-lettered event.
+func (s *PostgresStore) RedeliverWebhookDelivery(
+	ctx context.Context,
+	webhookID, deliveryID string,
+) (model.WebhookDelivery, error) {
+	webhookID = strings.TrimSpace(webhookID)
+	deliveryID = strings.TrimSpace(deliveryID)
+	if !uuidPattern.MatchString(webhookID) || !uuidPattern.MatchString(deliveryID) {
+		return model.WebhookDelivery{}, ErrNotFound
+	}
+	now := time.Now().UTC()
+
+	sqlStr, args, err := s.sb.
+		Update("power.webhook_deliveries").
+		Set("state", model.WebhookDeliveryPending).
+		Set("attempt_count", 0).
+		Set("next_attempt_at", now).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": deliveryID, "webhook_id": webhookID}).
+		Suffix("RETURNING " + strings.Join(webhookDeliveryColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("building webhook redelivery query: %w", err)
+	}
+	item, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, sqlStr, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, ErrNotFound
+		}
+		return model.WebhookDelivery{}, fmt.Errorf("requeueing webhook delivery %q: %w", deliveryID, err)
+	}
+	return item, nil
+}
+
+// ListUndispatchedOutboxEvents returns the oldest outbox events not yet
+// fanned out to webhooks. Relaying to NATS does not affect this.
+func (s *PostgresStore) ListUndispatchedOutboxEvents(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	sqlStr, args, err := s.sb.
+		Select("id::text", "event_type", "subject", "data", "created_at").
+		From("power.outbox").
+		Where(sq.Eq{"webhook_dispatched_at": nil}).
+		OrderBy("created_at", "id").
+		Limit(safeUint64(max(limit, 1))).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building outbox webhook query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing undispatched outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.OutboxEvent, 0)
+	for rows.Next() {
+		var item model.OutboxEvent
+		var data []byte
+		if scanErr := rows.Scan(&item.ID, &item.EventType, &item.Subject, &data, &item.CreatedAt); scanErr != nil {
+			return nil, fmt.Errorf("scanning outbox row: %w", scanErr)
+		}
+		item.Data = data
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating outbox rows: %w", rowsErr)
+	}
+	return items, nil
+}
+
+// RecordWebhookDispatch queues deliveries and marks their outbox events as
+// fanned out in one transaction. Deliveries already queued for the same
+// webhook and event are skipped, so replicas racing on one event queue it once.
+func (s *PostgresStore) RecordWebhookDispatch(
+	ctx context.Context,
+	outboxIDs []string,
+	deliveries []model.WebhookDelivery,
+) error {
+	if len(outboxIDs) == 0 && len(deliveries) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting webhook dispatch transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, delivery := range deliveries {
+		sqlStr, args, buildErr := s.sb.
+			Insert("power.webhook_deliveries").
+			Columns("webhook_id", "event_id", "event_type", "subject", "payload", "state", "next_attempt_at", "created_at", "updated_at").
+			Values(
+				delivery.WebhookID,
+				delivery.EventID,
+				delivery.EventType,
+				delivery.Subject,
+				[]byte(delivery.Payload),
+				model.WebhookDeliveryPending,
+				now,
+				now,
+				now,
+			).
+			Suffix("ON CONFLICT (webhook_id, event_id) DO NOTHING").
+			ToSql()
+		if buildErr != nil {
+			return fmt.Errorf("building webhook delivery insert query: %w", buildErr)
+		}
+		if _, execErr := tx.ExecContext(ctx, sqlStr, args...); execErr != nil {
+			return fmt.Errorf("queueing event %q for webhook %q: %w", delivery.EventID, delivery.WebhookID, execErr)
+		}
+	}
+
+	if len(outboxIDs) > 0 {
+		sqlStr, args, buildErr := s.sb.
+			Update("power.outbox").
+			Set("webhook_dispatched_at", now).
+			Where(sq.Eq{"id": outboxIDs}).
+			ToSql()
+		if buildErr != nil {
+			return fmt.Errorf("building outbox dispatch update query: %w", buildErr)
+		}
+		if _, execErr := tx.ExecContext(ctx, sqlStr, args...); execErr != nil {
+			return fmt.Errorf("marking outbox events dispatched: %w", execErr)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing webhook dispatch: %w", err)
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries leases up to limit due deliveries of enabled
+// webhooks and counts the attempt. A claimed delivery is not due again until
+// lease has passed, so a replica that dies mid-attempt only delays it.
+func (s *PostgresStore) ClaimWebhookDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]model.ClaimedWebhookDelivery, error) {
+	now := time.Now().UTC()
+	returning := make([]string, 0, len(webhookDeliveryColumns)+len(webhookColumns))
+	for _, column := range webhookDeliveryColumns {
+		returning = append(returning, "d."+column)
+	}
+	for _, column := range webhookColumns {
+		returning = append(returning, "w."+column)
+	}
+
+	query := `
+UPDATE power.webhook_deliveries d
+SET attempt_count = d.attempt_count + 1,
+    next_attempt_at = $1,
+    updated_at = $2
+FROM power.webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (
+    SELECT due.id
+    FROM power.webhook_deliveries due
+    JOIN power.webhooks hook ON hook.id = due.webhook_id
+    WHERE due.state = $3
+      AND due.next_attempt_at <= $2
+      AND hook.enabled
+    ORDER BY due.next_attempt_at
+    LIMIT $4
+    FOR UPDATE OF due SKIP LOCKED
+  )
+RETURNING ` + strings.Join(returning, ", ")
+
+	rows, err := s.db.QueryContext(ctx, query, now.Add(lease), now, model.WebhookDeliveryPending, max(limit, 1))
+	if err != nil {
+		return nil, fmt.Errorf("claiming webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.ClaimedWebhookDelivery, 0)
+	for rows.Next() {
+		var (
+			item                     model.ClaimedWebhookDelivery
+			payload                  []byte
+			deliveredAt              sql.NullTime
+			eventTypesRaw, groupsRaw []byte
+		)
+		if scanErr := rows.Scan(
+			&item.Delivery.ID,
+			&item.Delivery.WebhookID,
+			&item.Delivery.EventID,
+			&item.Delivery.EventType,
+			&item.Delivery.Subject,
+			&payload,
+			&item.Delivery.State,
+			&item.Delivery.AttemptCount,
+			&item.Delivery.NextAttemptAt,
+			&item.Delivery.LastStatusCode,
+			&item.Delivery.LastError,
+			&deliveredAt,
+			&item.Delivery.CreatedAt,
+			&item.Delivery.UpdatedAt,
+			&item.Webhook.ID,
+			&item.Webhook.Name,
+			&item.Webhook.URL,
+			&item.Webhook.Secret,
+			&eventTypesRaw,
+			&groupsRaw,
+			&item.Webhook.Enabled,
+			&item.Webhook.CreatedBy,
+			&item.Webhook.CreatedAt,
+			&item.Webhook.UpdatedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("scanning claimed webhook delivery: %w", scanErr)
+		}
+		item.Delivery.Payload = payload
+		if deliveredAt.Valid {
+			item.Delivery.DeliveredAt = &deliveredAt.Time
+		}
+		if decodeErr := unmarshalWebhookFilters(&item.Webhook, eventTypesRaw, groupsRaw); decodeErr != nil {
+			return nil, decodeErr
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating claimed webhook deliveries: %w", rowsErr)
+	}
+	return items, nil
+}
+
+// CompleteWebhookDelivery records the outcome of one delivery attempt.
+func (s *PostgresStore) CompleteWebhookDelivery(ctx context.Context, result model.WebhookDeliveryResult) error {
+	attemptedAt := result.AttemptedAt.UTC()
+	if result.AttemptedAt.IsZero() {
+		attemptedAt = time.Now().UTC()
+	}
+
+	update := s.sb.
+		Update("power.webhook_deliveries").
+		Set("state", result.State).
+		Set("last_status_code", result.StatusCode).
+		Set("last_error", result.Error).
+		Set("updated_at", attemptedAt).
+		Where(sq.Eq{"id": strings.TrimSpace(result.DeliveryID)})
+	switch result.State {
+	case model.WebhookDeliveryDelivered:
+		update = update.Set("delivered_at", attemptedAt)
+	case model.WebhookDeliveryPending:
+		update = update.Set("next_attempt_at", result.NextAttemptAt.UTC())
+	}
+
+	sqlStr, args, err := update.ToSql()
+	if err != nil {
+		return fmt.Errorf("building webhook delivery update query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("recording webhook delivery %q: %w", result.DeliveryID, err)
+	}
+	return nil
+}
+
+// PruneWebhookDeliveries deletes delivered and dead-lettered deliveries last
+// updated before cutoff and returns how many were removed.
+func (s *PostgresStore) PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	sqlStr, args, err := s.sb.
+		Delete("power.webhook_deliveries").
+		Where(sq.Eq{"state": []string{model.WebhookDeliveryDelivered, model.WebhookDeliveryDead}}).
+		Where(sq.Lt{"updated_at": cutoff.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building webhook delivery prune query: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, fmt.Errorf("pruning webhook deliveries: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("reading pruned webhook delivery count: %w", err)
+	}
+	return deleted, nil
+}
+
+func (s *PostgresStore) queryWebhooks(ctx context.Context, query sq.SelectBuilder) ([]model.Webhook, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building webhook list query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.Webhook, 0)
+	for rows.Next() {
+		item, scanErr := scanWebhook(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scanning webhook row: %w", scanErr)
+		}
+		items = append(items, item)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterating webhook rows: %w", rowsErr)
+	}
+	return items, nil
+}
+
+func marshalWebhookFilters(item model.Webhook) ([]byte, []byte, error) {
+	eventTypes, err := json.Marshal(nonNilStrings(item.EventTypes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling webhook event types: %w", err)
+	}
+	groups, err := json.Marshal(nonNilStrings(item.Groups))
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling webhook groups: %w", err)
+	}
+	return eventTypes, groups, nil
+}
+
+func unmarshalWebhookFilters(item *model.Webhook, eventTypesRaw, groupsRaw []byte) error {
+	if err := json.Unmarshal(eventTypesRaw, &item.EventTypes); err != nil {
+		return fmt.Errorf("decoding webhook event types: %w", err)
+	}
+	if err := json.Unmarshal(groupsRaw, &item.Groups); err != nil {
+		return fmt.Errorf("decoding webhook groups: %w", err)
+	}
+	return nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func scanWebhook(scanner interface {
+	Scan(dest ...any) error
+}) (model.Webhook, error) {
+	var out model.Webhook
+	var eventTypesRaw, groupsRaw []byte
+
+	if err := scanner.Scan(
+		&out.ID,
+		&out.Name,
+		&out.URL,
+		&out.Secret,
+		&eventTypesRaw,
+		&groupsRaw,
+		&out.Enabled,
+		&out.CreatedBy,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		return model.Webhook{}, err
+	}
+	if err := unmarshalWebhookFilters(&out, eventTypesRaw, groupsRaw); err != nil {
+		return model.Webhook{}, err
+	}
+	return out, nil
+}
+
+func scanWebhookDelivery(scanner interface {
+	Scan(dest ...any) error
+}) (model.WebhookDelivery, error) {
+	var out model.WebhookDelivery
+	var payload []byte
+	var deliveredAt sql.NullTime
+
+	if err := scanner.Scan(
+		&out.ID,
+		&out.WebhookID,
+		&out.EventID,
+		&out.EventType,
+		&out.Subject,
+		&payload,
+		&out.State,
+		&out.AttemptCount,
+		&out.NextAttemptAt,
+		&out.LastStatusCode,
+		&out.LastError,
+		&deliveredAt,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	out.Payload = payload
+	if deliveredAt.Valid {
+		out.DeliveredAt = &deliveredAt.Time
+	}
+	return out, nil
+}
@@ -0,0 +1,141 @@
+//go:build integration
+
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/testutil"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/internal/store"
+)
+
+func TestPostgresStore_Webhooks(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+
+	created, err := st.CreateWebhook(ctx, model.Webhook{
+		Name:       "ops",
+		URL:        "https://hooks.example.com/power",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{"chamicore.power.transitions.lifecycle"},
+		Enabled:    true,
+		CreatedBy:  "admin",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Empty(t, created.Groups)
+
+	created.Groups = []string{"compute"}
+	created.Enabled = false
+	updated, err := st.UpdateWebhook(ctx, created)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compute"}, updated.Groups)
+	assert.False(t, updated.Enabled)
+
+	enabled, err := st.ListEnabledWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, enabled)
+
+	items, total, err := st.ListWebhooks(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+	assert.Equal(t, "0123456789abcdef", items[0].Secret)
+
+	_, err = st.GetWebhook(ctx, "not-a-uuid")
+	require.ErrorIs(t, err, store.ErrNotFound)
+	_, err = st.GetWebhook(ctx, "00000000-0000-0000-0000-000000000000")
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	require.NoError(t, st.DeleteWebhook(ctx, created.ID))
+	require.ErrorIs(t, st.DeleteWebhook(ctx, created.ID), store.ErrNotFound)
+}
+
+func TestPostgresStore_WebhookDispatchAndDelivery(t *testing.T) {
+	db := testutil.NewTestPostgres(t, "../../migrations/postgres")
+	st := store.NewPostgresStore(db)
+	ctx := context.Background()
+
+	hook, err := st.CreateWebhook(ctx, model.Webhook{
+		Name:    "ops",
+		URL:     "https://hooks.example.com/power",
+		Secret:  "0123456789abcdef",
+		Enabled: true,
+	})
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+INSERT INTO power.outbox (event_type, subject, data)
+VALUES ('chamicore.power.transitions.lifecycle', 'tr-1', '{"id":"evt-1","type":"chamicore.power.transitions.lifecycle","data":{}}')
+`)
+	require.NoError(t, err)
+
+	events, err := st.ListUndispatchedOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	delivery := model.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   "evt-1",
+		EventType: events[0].EventType,
+		Subject:   events[0].Subject,
+		Payload:   json.RawMessage(`{"id":"evt-1"}`),
+	}
+	require.NoError(t, st.RecordWebhookDispatch(ctx, []string{events[0].ID}, []model.WebhookDelivery{delivery}))
+	// A second replica dispatching the same event queues nothing new.
+	require.NoError(t, st.RecordWebhookDispatch(ctx, []string{events[0].ID}, []model.WebhookDelivery{delivery}))
+
+	events, err = st.ListUndispatchedOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	claimed, err := st.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, 1, claimed[0].Delivery.AttemptCount)
+	assert.Equal(t, hook.URL, claimed[0].Webhook.URL)
+	assert.JSONEq(t, `{"id":"evt-1"}`, string(claimed[0].Delivery.Payload))
+
+	// The lease hides the delivery from other claimants.
+	again, err := st.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.NoError(t, st.CompleteWebhookDelivery(ctx, model.WebhookDeliveryResult{
+		DeliveryID: claimed[0].Delivery.ID,
+		State:      model.WebhookDeliveryDead,
+		StatusCode: 503,
+		Error:      "unexpected status 503",
+	}))
+
+	log, total, err := st.ListWebhookDeliveries(ctx, model.WebhookDeliveryQuery{WebhookID: hook.ID, State: model.WebhookDeliveryDead})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, log, 1)
+	assert.Equal(t, 503, log[0].LastStatusCode)
+
+	requeued, err := st.RedeliverWebhookDelivery(ctx, hook.ID, log[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryPending, requeued.State)
+	assert.Zero(t, requeued.AttemptCount)
+
+	claimed, err = st.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NoError(t, st.CompleteWebhookDelivery(ctx, model.WebhookDeliveryResult{
+		DeliveryID: claimed[0].Delivery.ID,
+		State:      model.WebhookDeliveryDelivered,
+		StatusCode: 204,
+	}))
+
+	pruned, err := st.PruneWebhookDeliveries(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+}
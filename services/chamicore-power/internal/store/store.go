@@ -12,14 +12,16 @@ import (
 var (
 	// ErrNotFound indicates the requested resource does not exist.
 	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates the resource already exists.
+	ErrConflict = errors.New("conflict")
 )
 
 // Store defines persistence methods needed by P8.4 service scaffold.
 type Store interface {
 	// Ping checks DB connectivity for readiness probes.
 	Ping(ctx context.Context) error
-	// ReplaceTopologyMappings reconciles local mapping cache to desired SMD-derived state.
-	ReplaceTopologyMappings(ctx context.Context, endpoints []model.BMCEndpoint, links []model.NodeBMCLink, syncedAt time.Time) (model.MappingApplyCounts, error)
+	// ApplyTopologyDelta applies an incremental mapping change without touching unrelated rows.
+	ApplyTopologyDelta(ctx context.Context, delta model.TopologyDelta, syncedAt time.Time) (model.MappingApplyCounts, error)
 	// ResolveNodeMappings resolves per-node BMC/credential routing info and reports per-node actionable missing errors.
 	ResolveNodeMappings(ctx context.Context, nodeIDs []string) ([]model.NodePowerMapping, []model.NodeMappingError, error)
 	// ListBMCEndpoints returns all cached BMC endpoint rows.
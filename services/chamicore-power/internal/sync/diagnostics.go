@@ -0,0 +1,193 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	powersmd "git.cscs.ch/openchami/chamicore-power/internal/smd"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+// DiagnoseMappings explains the power mapping of every node SMD knows about.
+// A non-empty statuses list keeps only nodes whose status matches one of them.
+func (s *Syncer) DiagnoseMappings(ctx context.Context, statuses []string) ([]model.NodeMappingDiagnostic, error) {
+	components, _, _, err := s.listComponents(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing components from SMD: %w", err)
+	}
+	interfaces, err := s.listInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing ethernet interfaces from SMD: %w", err)
+	}
+
+	typeByID := make(map[string]string, len(components.Items))
+	nodes := make(map[string]string)
+	for _, item := range components.Items {
+		componentID := strings.TrimSpace(item.Spec.ID)
+		if componentID == "" {
+			continue
+		}
+		typeByID[componentID] = strings.TrimSpace(item.Spec.Type)
+		if !isNodeType(item.Spec.Type) {
+			continue
+		}
+		parentID := ""
+		if item.Spec.ParentID != nil {
+			parentID = strings.TrimSpace(*item.Spec.ParentID)
+		}
+		nodes[componentID] = parentID
+	}
+
+	interfacesByComponent := make(map[string][]smdtypes.EthernetInterface)
+	for _, item := range interfaces.Items {
+		componentID := strings.TrimSpace(item.Spec.ComponentID)
+		if componentID == "" {
+			continue
+		}
+		interfacesByComponent[componentID] = append(interfacesByComponent[componentID], item.Spec)
+	}
+
+	nodeIDs := make([]string, 0, len(nodes))
+	for nodeID := range nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	resolved, missing, err := s.store.ResolveNodeMappings(ctx, nodeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("resolving node mappings: %w", err)
+	}
+	links, err := s.store.ListNodeBMCLinks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing node BMC links: %w", err)
+	}
+	endpoints, err := s.store.ListBMCEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing BMC endpoints: %w", err)
+	}
+
+	statusByNode := make(map[string]string, len(nodeIDs))
+	for _, mapping := range resolved {
+		statusByNode[mapping.NodeID] = model.MappingStatusResolved
+	}
+	for _, mappingErr := range missing {
+		statusByNode[mappingErr.NodeID] = mappingErr.Code
+	}
+	linkByNode := make(map[string]model.NodeBMCLink, len(links))
+	for _, link := range links {
+		linkByNode[strings.TrimSpace(link.NodeID)] = link
+	}
+	endpointByBMC := make(map[string]model.BMCEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpointByBMC[strings.TrimSpace(endpoint.BMCID)] = endpoint
+	}
+
+	wanted := make(map[string]struct{}, len(statuses))
+	for _, status := range statuses {
+		if trimmed := strings.TrimSpace(status); trimmed != "" {
+			wanted[trimmed] = struct{}{}
+		}
+	}
+
+	diagnostics := make([]model.NodeMappingDiagnostic, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		status := statusByNode[nodeID]
+		if status == "" {
+			status = model.MappingErrorCodeNotFound
+		}
+		if len(wanted) > 0 {
+			if _, ok := wanted[status]; !ok {
+				continue
+			}
+		}
+
+		parentID := nodes[nodeID]
+		diagnostic := model.NodeMappingDiagnostic{
+			NodeID: nodeID,
+			Status: status,
+			SMD: model.NodeMappingSMD{
+				ParentID:   parentID,
+				ParentType: typeByID[parentID],
+				Interfaces: []model.InterfaceMappingEvidence{},
+			},
+			Suggestions: []string{},
+		}
+		if parentID != "" {
+			for _, iface := range interfacesByComponent[parentID] {
+				endpoint := endpointFromIPAddrs(iface.IPAddrs)
+				addrs := powersmd.ParseIPAddrs(iface.IPAddrs)
+				if addrs == nil {
+					addrs = []string{}
+				}
+				diagnostic.SMD.Interfaces = append(diagnostic.SMD.Interfaces, model.InterfaceMappingEvidence{
+					ComponentID: parentID,
+					IPAddrs:     addrs,
+					Endpoint:    endpoint,
+				})
+				if diagnostic.SMD.ChosenEndpoint == "" {
+					diagnostic.SMD.ChosenEndpoint = endpoint
+				}
+			}
+		}
+
+		if link, ok := linkByNode[nodeID]; ok {
+			diagnostic.BMCID = strings.TrimSpace(link.BMCID)
+			cached := endpointByBMC[diagnostic.BMCID]
+			diagnostic.Endpoint = strings.TrimSpace(cached.Endpoint)
+			diagnostic.CredentialID = strings.TrimSpace(cached.CredentialID)
+		}
+
+		s.explainMapping(&diagnostic)
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics, nil
+}
+
+// explainMapping fills the reason and suggested fixes for one node diagnostic.
+func (s *Syncer) explainMapping(d *model.NodeMappingDiagnostic) {
+	const runSync = "run POST /power/v1/admin/mappings/sync to refresh the local mapping cache"
+
+	parentID := d.SMD.ParentID
+	switch d.Status {
+	case model.MappingStatusResolved:
+		d.Reason = fmt.Sprintf("node resolves to BMC %q at %s", d.BMCID, d.Endpoint)
+	case model.MappingErrorCodeNotFound:
+		if parentID == "" {
+			d.Reason = "SMD component has no parentId, so no BMC link can be derived"
+			d.Suggestions = append(d.Suggestions, fmt.Sprintf("set parentId on SMD component %q to the ID of its BMC", d.NodeID))
+			return
+		}
+		d.Reason = fmt.Sprintf("SMD reports parent BMC %q but the local cache has no link for this node", parentID)
+		d.Suggestions = append(d.Suggestions, runSync)
+	case model.MappingErrorCodeEndpointMissing:
+		switch {
+		case len(d.SMD.Interfaces) == 0:
+			d.Reason = fmt.Sprintf("SMD has no ethernet interfaces for BMC %q", d.BMCID)
+			d.Suggestions = append(d.Suggestions, fmt.Sprintf("add an ethernet interface carrying the Redfish IP address of BMC %q in SMD", d.BMCID))
+		case d.SMD.ChosenEndpoint == "":
+			d.Reason = fmt.Sprintf("none of the %d SMD ethernet interfaces of BMC %q has a usable IP address", len(d.SMD.Interfaces), d.BMCID)
+			d.Suggestions = append(d.Suggestions, fmt.Sprintf("set ipAddrs on an ethernet interface of BMC %q in SMD", d.BMCID))
+		default:
+			d.Reason = fmt.Sprintf("SMD provides %s for BMC %q but the local cache has no endpoint", d.SMD.ChosenEndpoint, d.BMCID)
+			d.Suggestions = append(d.Suggestions, runSync)
+		}
+	case model.MappingErrorCodeCredentialMissing:
+		d.Reason = fmt.Sprintf("BMC %q has no credential binding", d.BMCID)
+		d.Suggestions = append(d.Suggestions, fmt.Sprintf("configure a credential for BMC %q", d.BMCID))
+		if s.defaultCredentialID == "" {
+			d.Suggestions = append(d.Suggestions, "set CHAMICORE_POWER_DEFAULT_CREDENTIAL_ID to bind BMCs without a credential to a default one")
+		} else {
+			d.Suggestions = append(d.Suggestions, runSync+" and apply the default credential")
+		}
+	}
+
+	if parentID != "" && d.BMCID != "" && parentID != d.BMCID {
+		d.Suggestions = append(d.Suggestions, fmt.Sprintf("cached BMC %q differs from SMD parent %q; %s", d.BMCID, parentID, runSync))
+	} else if d.SMD.ChosenEndpoint != "" && d.Endpoint != "" && d.SMD.ChosenEndpoint != d.Endpoint {
+		d.Suggestions = append(d.Suggestions, fmt.Sprintf("cached endpoint %s differs from SMD endpoint %s; %s", d.Endpoint, d.SMD.ChosenEndpoint, runSync))
+	}
+}
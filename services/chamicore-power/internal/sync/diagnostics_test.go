@@ -0,0 +1,86 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+func TestDiagnoseMappings_ExplainsEachStatus(t *testing.T) {
+	st := newMemoryStore()
+	st.endpoints["bmc-ok"] = model.BMCEndpoint{BMCID: "bmc-ok", Endpoint: "https://10.0.0.1", CredentialID: "cred-1"}
+	st.endpoints["bmc-noip"] = model.BMCEndpoint{BMCID: "bmc-noip", CredentialID: "cred-1"}
+	st.endpoints["bmc-nocred"] = model.BMCEndpoint{BMCID: "bmc-nocred", Endpoint: "https://10.0.0.3"}
+	st.links["node-ok"] = model.NodeBMCLink{NodeID: "node-ok", BMCID: "bmc-ok"}
+	st.links["node-noip"] = model.NodeBMCLink{NodeID: "node-noip", BMCID: "bmc-noip"}
+	st.links["node-nocred"] = model.NodeBMCLink{NodeID: "node-nocred", BMCID: "bmc-nocred"}
+
+	parent := func(id string) *string { return &id }
+	smd := &pagedSMDClient{
+		components: []smdtypes.Component{
+			{ID: "bmc-ok", Type: "BMC"},
+			{ID: "bmc-noip", Type: "BMC"},
+			{ID: "bmc-nocred", Type: "BMC"},
+			{ID: "node-ok", Type: "Node", ParentID: parent("bmc-ok")},
+			{ID: "node-noip", Type: "Node", ParentID: parent("bmc-noip")},
+			{ID: "node-nocred", Type: "Node", ParentID: parent("bmc-nocred")},
+			{ID: "node-orphan", Type: "Node"},
+			{ID: "node-unsynced", Type: "Node", ParentID: parent("bmc-ok")},
+		},
+		interfaces: []smdtypes.EthernetInterface{
+			{ComponentID: "bmc-ok", IPAddrs: json.RawMessage(`["10.0.0.1"]`)},
+			{ComponentID: "bmc-noip", IPAddrs: json.RawMessage(`[]`)},
+			{ComponentID: "bmc-nocred", IPAddrs: json.RawMessage(`["10.0.0.3"]`)},
+		},
+	}
+
+	s := New(st, smd.client(), Config{Interval: time.Second}, zerolog.Nop())
+	diagnostics, err := s.DiagnoseMappings(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 5)
+
+	byNode := make(map[string]model.NodeMappingDiagnostic, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		byNode[diagnostic.NodeID] = diagnostic
+	}
+
+	ok := byNode["node-ok"]
+	assert.Equal(t, model.MappingStatusResolved, ok.Status)
+	assert.Equal(t, "https://10.0.0.1", ok.SMD.ChosenEndpoint)
+	assert.Empty(t, ok.Suggestions)
+
+	noIP := byNode["node-noip"]
+	assert.Equal(t, model.MappingErrorCodeEndpointMissing, noIP.Status)
+	assert.Contains(t, noIP.Reason, "usable IP address")
+	require.Len(t, noIP.SMD.Interfaces, 1)
+	assert.Empty(t, noIP.SMD.Interfaces[0].IPAddrs)
+	assert.NotEmpty(t, noIP.Suggestions)
+
+	noCred := byNode["node-nocred"]
+	assert.Equal(t, model.MappingErrorCodeCredentialMissing, noCred.Status)
+	assert.Contains(t, noCred.Suggestions[1], "CHAMICORE_POWER_DEFAULT_CREDENTIAL_ID")
+
+	orphan := byNode["node-orphan"]
+	assert.Equal(t, model.MappingErrorCodeNotFound, orphan.Status)
+	assert.Contains(t, orphan.Reason, "no parentId")
+
+	unsynced := byNode["node-unsynced"]
+	assert.Equal(t, model.MappingErrorCodeNotFound, unsynced.Status)
+	assert.Equal(t, "bmc-ok", unsynced.SMD.ParentID)
+	assert.Equal(t, "BMC", unsynced.SMD.ParentType)
+	assert.Contains(t, unsynced.Suggestions[0], "/power/v1/admin/mappings/sync")
+
+	filtered, err := s.DiagnoseMappings(context.Background(), []string{model.MappingErrorCodeNotFound})
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "node-orphan", filtered[0].NodeID)
+	assert.Equal(t, "node-unsynced", filtered[1].NodeID)
+}
@@ -0,0 +1,163 @@
+package syncer
+
+import (
+	"sort"
+	"strings"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+// maxReportEntries caps each change list in a sync report; the summary keeps full counts.
+const maxReportEntries = 1000
+
+// diffTopology compares desired SMD-derived mappings with the cached rows and
+// returns a delta that touches only added, changed, or removed rows, plus a
+// report describing those changes.
+func diffTopology(
+	desiredEndpoints []model.BMCEndpoint,
+	desiredLinks []model.NodeBMCLink,
+	existingEndpoints []model.BMCEndpoint,
+	existingLinks []model.NodeBMCLink,
+) (model.TopologyDelta, model.MappingSyncReport) {
+	delta := model.TopologyDelta{Authoritative: true}
+	report := model.MappingSyncReport{
+		BMCsAdded:    []model.BMCMappingChange{},
+		BMCsChanged:  []model.BMCMappingChange{},
+		BMCsRemoved:  []model.BMCMappingChange{},
+		LinksAdded:   []model.LinkMappingChange{},
+		LinksChanged: []model.LinkMappingChange{},
+		LinksRemoved: []model.LinkMappingChange{},
+	}
+
+	existingByBMC := make(map[string]model.BMCEndpoint, len(existingEndpoints))
+	for _, endpoint := range existingEndpoints {
+		existingByBMC[strings.TrimSpace(endpoint.BMCID)] = endpoint
+	}
+	desiredBMCs := make(map[string]struct{}, len(desiredEndpoints))
+
+	for _, desired := range desiredEndpoints {
+		bmcID := strings.TrimSpace(desired.BMCID)
+		desiredBMCs[bmcID] = struct{}{}
+
+		existing, found := existingByBMC[bmcID]
+		switch {
+		case !found:
+			delta.Endpoints = append(delta.Endpoints, desired)
+			report.Summary.BMCsAdded++
+			report.BMCsAdded = appendBMCChange(&report, report.BMCsAdded, model.BMCMappingChange{
+				BMCID:        bmcID,
+				Endpoint:     desired.Endpoint,
+				CredentialID: desired.CredentialID,
+			})
+		case endpointChanged(existing, desired):
+			delta.Endpoints = append(delta.Endpoints, desired)
+			report.Summary.BMCsChanged++
+			report.BMCsChanged = appendBMCChange(&report, report.BMCsChanged, model.BMCMappingChange{
+				BMCID:                bmcID,
+				Endpoint:             desired.Endpoint,
+				PreviousEndpoint:     strings.TrimSpace(existing.Endpoint),
+				CredentialID:         desired.CredentialID,
+				PreviousCredentialID: strings.TrimSpace(existing.CredentialID),
+			})
+		}
+	}
+
+	removedBMCIDs := make([]string, 0)
+	for bmcID := range existingByBMC {
+		if _, wanted := desiredBMCs[bmcID]; !wanted {
+			removedBMCIDs = append(removedBMCIDs, bmcID)
+		}
+	}
+	sort.Strings(removedBMCIDs)
+	for _, bmcID := range removedBMCIDs {
+		existing := existingByBMC[bmcID]
+		delta.DeletedBMCIDs = append(delta.DeletedBMCIDs, bmcID)
+		report.Summary.BMCsRemoved++
+		report.BMCsRemoved = appendBMCChange(&report, report.BMCsRemoved, model.BMCMappingChange{
+			BMCID:                bmcID,
+			PreviousEndpoint:     strings.TrimSpace(existing.Endpoint),
+			PreviousCredentialID: strings.TrimSpace(existing.CredentialID),
+		})
+	}
+
+	existingByNode := make(map[string]model.NodeBMCLink, len(existingLinks))
+	for _, link := range existingLinks {
+		existingByNode[strings.TrimSpace(link.NodeID)] = link
+	}
+	desiredNodes := make(map[string]struct{}, len(desiredLinks))
+
+	for _, desired := range desiredLinks {
+		nodeID := strings.TrimSpace(desired.NodeID)
+		desiredNodes[nodeID] = struct{}{}
+
+		existing, found := existingByNode[nodeID]
+		switch {
+		case !found:
+			delta.Links = append(delta.Links, desired)
+			report.Summary.LinksAdded++
+			report.LinksAdded = appendLinkChange(&report, report.LinksAdded, model.LinkMappingChange{
+				NodeID: nodeID,
+				BMCID:  desired.BMCID,
+			})
+		case strings.TrimSpace(existing.BMCID) != strings.TrimSpace(desired.BMCID):
+			delta.Links = append(delta.Links, desired)
+			report.Summary.LinksChanged++
+			report.LinksChanged = appendLinkChange(&report, report.LinksChanged, model.LinkMappingChange{
+				NodeID:        nodeID,
+				BMCID:         desired.BMCID,
+				PreviousBMCID: strings.TrimSpace(existing.BMCID),
+			})
+		}
+	}
+
+	removedNodeIDs := make([]string, 0)
+	for nodeID := range existingByNode {
+		if _, wanted := desiredNodes[nodeID]; !wanted {
+			removedNodeIDs = append(removedNodeIDs, nodeID)
+		}
+	}
+	sort.Strings(removedNodeIDs)
+	for _, nodeID := range removedNodeIDs {
+		delta.DeletedNodeIDs = append(delta.DeletedNodeIDs, nodeID)
+		report.Summary.LinksRemoved++
+		report.LinksRemoved = appendLinkChange(&report, report.LinksRemoved, model.LinkMappingChange{
+			NodeID:        nodeID,
+			PreviousBMCID: strings.TrimSpace(existingByNode[nodeID].BMCID),
+		})
+	}
+
+	return delta, report
+}
+
+// endpointChanged reports whether applying desired would alter the cached row.
+// Desired endpoints already fall back to cached endpoint and credential values.
+func endpointChanged(existing, desired model.BMCEndpoint) bool {
+	return strings.TrimSpace(existing.Endpoint) != strings.TrimSpace(desired.Endpoint) ||
+		strings.TrimSpace(existing.CredentialID) != strings.TrimSpace(desired.CredentialID) ||
+		existing.InsecureSkipVerify != desired.InsecureSkipVerify ||
+		strings.TrimSpace(existing.Source) != strings.TrimSpace(desired.Source)
+}
+
+func appendBMCChange(
+	report *model.MappingSyncReport,
+	changes []model.BMCMappingChange,
+	change model.BMCMappingChange,
+) []model.BMCMappingChange {
+	if len(changes) >= maxReportEntries {
+		report.Truncated = true
+		return changes
+	}
+	return append(changes, change)
+}
+
+func appendLinkChange(
+	report *model.MappingSyncReport,
+	changes []model.LinkMappingChange,
+	change model.LinkMappingChange,
+) []model.LinkMappingChange {
+	if len(changes) >= maxReportEntries {
+		report.Truncated = true
+		return changes
+	}
+	return append(changes, change)
+}
@@ -0,0 +1,320 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/events"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
+)
+
+// SMDEventsSubject captures every SMD change event; component and interface
+// events are filtered by type so both singular and plural resource names match.
+const SMDEventsSubject = "chamicore.smd.>"
+
+var (
+	componentEventPrefixes = []string{"chamicore.smd.components.", "chamicore.smd.component."}
+	interfaceEventPrefixes = []string{"chamicore.smd.interfaces.", "chamicore.smd.interface."}
+)
+
+const eventActionDeleted = "deleted"
+
+// Fields whose change can move a mapping. Events that report changedFields
+// without any of these, such as the State and Flag updates power itself
+// PATCHes into SMD, are ignored.
+var (
+	componentTopologyFields = []string{"id", "type", "parentId"}
+	interfaceTopologyFields = []string{"componentId", "ipAddrs"}
+)
+
+// EventSubscriber is the subset of the shared events subscriber used for SMD change events.
+type EventSubscriber interface {
+	Subscribe(ctx context.Context, subject string, handler func(events.Event) error) error
+}
+
+// smdEventData accepts both ADR-015 envelopes ({componentId, snapshot}) and bare snapshots.
+type smdEventData struct {
+	ID            string          `json:"id"`
+	ComponentID   string          `json:"componentId"`
+	Type          string          `json:"type"`
+	ParentID      *string         `json:"parentId"`
+	IPAddrs       json.RawMessage `json:"ipAddrs"`
+	ChangedFields []string        `json:"changedFields"`
+	Snapshot      json.RawMessage `json:"snapshot"`
+}
+
+// SubscribeEvents registers the syncer for SMD component/interface change events.
+// The ETag poll loop keeps running regardless, so a failed subscription only
+// loses latency, not correctness.
+func (s *Syncer) SubscribeEvents(ctx context.Context, sub EventSubscriber) error {
+	if sub == nil {
+		return fmt.Errorf("event subscriber is nil")
+	}
+	if err := sub.Subscribe(ctx, SMDEventsSubject, func(evt events.Event) error {
+		return s.HandleEvent(ctx, evt)
+	}); err != nil {
+		return fmt.Errorf("subscribing to %s: %w", SMDEventsSubject, err)
+	}
+
+	s.updateStatus(func(st *Status) {
+		st.EventsEnabled = true
+	})
+	return nil
+}
+
+// HandleEvent applies one SMD change event as an incremental mapping update.
+// Events that do not carry enough data to apply safely schedule a full sync instead.
+// Only the leader handles events. Every replica subscribes, so the leader sees
+// each event itself, and its poll loop covers any it misses; followers ignore
+// them rather than asking the leader for a full sync per event.
+func (s *Syncer) HandleEvent(ctx context.Context, evt events.Event) error {
+	if !s.IsReady() {
+		// The initial full sync will pick the change up.
+		return nil
+	}
+
+	eventType := strings.TrimSpace(evt.Type)
+	isComponentEvent := hasAnyPrefix(eventType, componentEventPrefixes)
+	if !isComponentEvent && !hasAnyPrefix(eventType, interfaceEventPrefixes) {
+		return nil
+	}
+	if !s.IsLeader() {
+		return nil
+	}
+
+	var (
+		delta model.TopologyDelta
+		ok    bool
+		err   error
+	)
+	if isComponentEvent {
+		delta, ok, err = s.componentEventDelta(evt)
+	} else {
+		delta, ok, err = s.interfaceEventDelta(evt)
+	}
+	if err != nil {
+		s.log.Warn().Err(err).Str("event_type", eventType).Str("event_id", evt.ID).Msg("scheduling full mapping sync for undecodable SMD event")
+		s.RequestResync(ctx)
+		return nil
+	}
+	if !ok {
+		s.RequestResync(ctx)
+		return nil
+	}
+	if delta.IsEmpty() {
+		return nil
+	}
+
+	counts, err := s.store.ApplyTopologyDelta(ctx, delta, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("applying mapping update for event %s: %w", evt.ID, err)
+	}
+
+	appliedAt := time.Now().UTC()
+	s.updateStatus(func(st *Status) {
+		st.EventsApplied++
+		st.LastEventAt = &appliedAt
+	})
+	s.log.Debug().
+		Str("event_type", eventType).
+		Str("subject", evt.Subject).
+		Int("endpoints_upserted", counts.EndpointsUpserted).
+		Int("links_upserted", counts.LinksUpserted).
+		Int("endpoints_deleted", counts.EndpointsDeleted).
+		Int("links_deleted", counts.LinksDeleted).
+		Msg("applied event-driven mapping update")
+	return nil
+}
+
+// RequestResync schedules a full sync without waiting for it. Followers hand
+// the request to the leader.
+func (s *Syncer) RequestResync(ctx context.Context) {
+	if s.coordinator != nil && !s.IsLeader() {
+		if _, err := s.coordinator.RequestSync(ctx); err != nil {
+			s.log.Warn().Err(err).Msg("failed to request leader mapping sync")
+		}
+		return
+	}
+
+	select {
+	case s.resyncCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Syncer) componentEventDelta(evt events.Event) (model.TopologyDelta, bool, error) {
+	data, err := decodeSMDEventData(evt.Data)
+	if err != nil {
+		return model.TopologyDelta{}, false, err
+	}
+
+	var component smdtypes.Component
+	if len(data.Snapshot) > 0 {
+		if err := json.Unmarshal(data.Snapshot, &component); err != nil {
+			return model.TopologyDelta{}, false, fmt.Errorf("decoding component snapshot: %w", err)
+		}
+	} else {
+		component = smdtypes.Component{ID: data.ID, Type: data.Type, ParentID: data.ParentID}
+	}
+
+	deleted := eventAction(evt.Type) == eventActionDeleted
+	if !deleted && len(data.ChangedFields) > 0 && !containsAnyFold(data.ChangedFields, componentTopologyFields) {
+		return model.TopologyDelta{}, true, nil
+	}
+
+	componentID := firstNonEmpty(component.ID, data.ComponentID, data.ID)
+	if componentID == "" {
+		return model.TopologyDelta{}, false, nil
+	}
+
+	if deleted {
+		return model.TopologyDelta{
+			DeletedNodeIDs: []string{componentID},
+			DeletedBMCIDs:  []string{componentID},
+		}, true, nil
+	}
+
+	componentType := strings.TrimSpace(component.Type)
+	if componentType == "" {
+		return model.TopologyDelta{}, false, nil
+	}
+
+	switch {
+	case isBMCType(componentType):
+		return model.TopologyDelta{
+			Endpoints: []model.BMCEndpoint{s.eventEndpoint(componentID)},
+		}, true, nil
+	case isNodeType(componentType):
+		parentID := ""
+		if component.ParentID != nil {
+			parentID = strings.TrimSpace(*component.ParentID)
+		}
+		if parentID == "" {
+			// Snapshots may omit parentId; only a reported parent change warrants a full sync.
+			if containsFold(data.ChangedFields, "parentId") {
+				return model.TopologyDelta{}, false, nil
+			}
+			return model.TopologyDelta{}, true, nil
+		}
+		return model.TopologyDelta{
+			Endpoints: []model.BMCEndpoint{s.eventEndpoint(parentID)},
+			Links: []model.NodeBMCLink{{
+				NodeID: componentID,
+				BMCID:  parentID,
+				Source: "smd",
+			}},
+		}, true, nil
+	default:
+		return model.TopologyDelta{}, true, nil
+	}
+}
+
+func (s *Syncer) interfaceEventDelta(evt events.Event) (model.TopologyDelta, bool, error) {
+	if eventAction(evt.Type) == eventActionDeleted {
+		// Another interface may now supply the BMC endpoint; only a full sync knows.
+		return model.TopologyDelta{}, false, nil
+	}
+
+	data, err := decodeSMDEventData(evt.Data)
+	if err != nil {
+		return model.TopologyDelta{}, false, err
+	}
+	if len(data.ChangedFields) > 0 && !containsAnyFold(data.ChangedFields, interfaceTopologyFields) {
+		return model.TopologyDelta{}, true, nil
+	}
+
+	var iface smdtypes.EthernetInterface
+	if len(data.Snapshot) > 0 {
+		if err := json.Unmarshal(data.Snapshot, &iface); err != nil {
+			return model.TopologyDelta{}, false, fmt.Errorf("decoding interface snapshot: %w", err)
+		}
+	} else {
+		iface = smdtypes.EthernetInterface{ComponentID: data.ComponentID, IPAddrs: data.IPAddrs}
+	}
+
+	componentID := strings.TrimSpace(firstNonEmpty(iface.ComponentID, data.ComponentID))
+	if componentID == "" || len(iface.IPAddrs) == 0 {
+		return model.TopologyDelta{}, false, nil
+	}
+
+	endpoint := endpointFromIPAddrs(iface.IPAddrs)
+	if endpoint == "" {
+		return model.TopologyDelta{}, true, nil
+	}
+
+	return model.TopologyDelta{
+		EndpointRefreshes: []model.BMCEndpoint{{
+			BMCID:    componentID,
+			Endpoint: endpoint,
+			Source:   "smd",
+		}},
+	}, true, nil
+}
+
+func (s *Syncer) eventEndpoint(bmcID string) model.BMCEndpoint {
+	return model.BMCEndpoint{
+		BMCID:        bmcID,
+		CredentialID: s.defaultCredentialID,
+		Source:       "smd",
+	}
+}
+
+func decodeSMDEventData(raw json.RawMessage) (smdEventData, error) {
+	var data smdEventData
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return smdEventData{}, fmt.Errorf("decoding SMD event data: %w", err)
+	}
+	return data, nil
+}
+
+func eventAction(eventType string) string {
+	trimmed := strings.TrimSpace(eventType)
+	idx := strings.LastIndex(trimmed, ".")
+	if idx < 0 {
+		return ""
+	}
+	return trimmed[idx+1:]
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), want) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyFold(values, wants []string) bool {
+	for _, want := range wants {
+		if containsFold(values, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
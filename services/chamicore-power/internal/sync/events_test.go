@@ -0,0 +1,213 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-lib/events"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+type fakeEventSubscriber struct {
+	subjects []string
+	handler  func(events.Event) error
+	err      error
+}
+
+func (f *fakeEventSubscriber) Subscribe(ctx context.Context, subject string, handler func(events.Event) error) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.subjects = append(f.subjects, subject)
+	f.handler = handler
+	return nil
+}
+
+func newReadyEventSyncer(t *testing.T, st *memoryStore) *Syncer {
+	t.Helper()
+	s := New(st, emptySMDClient(), Config{DefaultCredentialID: "cred-default"}, zerolog.Nop())
+	require.NoError(t, s.SyncOnce(context.Background()))
+	require.True(t, s.IsReady())
+	return s
+}
+
+func smdEvent(t *testing.T, eventType, subject string, data any) events.Event {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	return events.Event{
+		ID:              "evt-1",
+		Source:          "chamicore-smd",
+		Type:            eventType,
+		Subject:         subject,
+		DataContentType: events.JSONDataContentType,
+		Data:            raw,
+	}
+}
+
+func TestHandleEvent_NodeCreatedAddsLinkAndParentEndpoint(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+
+	err := s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.created", "node-1", map[string]any{
+		"componentId": "node-1",
+		"snapshot":    map[string]any{"id": "node-1", "type": "Node", "parentId": "bmc-1"},
+	}))
+	require.NoError(t, err)
+
+	require.Contains(t, st.links, "node-1")
+	assert.Equal(t, "bmc-1", st.links["node-1"].BMCID)
+	require.Contains(t, st.endpoints, "bmc-1")
+	assert.Equal(t, "cred-default", st.endpoints["bmc-1"].CredentialID)
+
+	status := s.Status()
+	assert.Equal(t, int64(1), status.EventsApplied)
+	assert.NotNil(t, status.LastEventAt)
+}
+
+func TestHandleEvent_InterfaceUpdateRefreshesTrackedBMCOnly(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+	st.endpoints["bmc-1"] = model.BMCEndpoint{BMCID: "bmc-1", CredentialID: "cred-1", Source: "smd"}
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.interface.updated", "if-1", map[string]any{
+		"snapshot": map[string]any{"componentId": "bmc-1", "ipAddrs": []string{"10.0.0.7"}},
+	})))
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.interface.created", "if-2", map[string]any{
+		"snapshot": map[string]any{"componentId": "node-7", "ipAddrs": []string{"10.0.1.7"}},
+	})))
+
+	assert.Equal(t, "https://10.0.0.7", st.endpoints["bmc-1"].Endpoint)
+	assert.Equal(t, "cred-1", st.endpoints["bmc-1"].CredentialID)
+	assert.NotContains(t, st.endpoints, "node-7")
+}
+
+func TestHandleEvent_ComponentDeletedRemovesMappings(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+	st.endpoints["bmc-1"] = model.BMCEndpoint{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1"}
+	st.links["node-1"] = model.NodeBMCLink{NodeID: "node-1", BMCID: "bmc-1"}
+	st.links["node-2"] = model.NodeBMCLink{NodeID: "node-2", BMCID: "bmc-1"}
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.deleted", "node-1", map[string]any{
+		"componentId": "node-1",
+	})))
+	assert.NotContains(t, st.links, "node-1")
+	assert.Contains(t, st.links, "node-2")
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.deleted", "bmc-1", map[string]any{
+		"componentId": "bmc-1",
+	})))
+	assert.Empty(t, st.links)
+	assert.Empty(t, st.endpoints)
+}
+
+func TestHandleEvent_DeleteWithoutComponentIDSchedulesFullSync(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+	st.links["node-1"] = model.NodeBMCLink{NodeID: "node-1", BMCID: "bmc-1"}
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.deleted", "node-1", nil)))
+	assert.Contains(t, st.links, "node-1", "the event subject is not a component ID")
+	assert.Len(t, s.resyncCh, 1)
+}
+
+func TestHandleEvent_FollowerIgnoresEvents(t *testing.T) {
+	st := newMemoryStore()
+	coordinator := &fakeLeaderCoordinator{}
+	s := New(st, emptySMDClient(), Config{}, zerolog.Nop(), WithLeaderCoordinator(coordinator))
+	s.ready.Store(true)
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.updated", "node-1", map[string]any{
+		"changedFields": []string{"state", "flag"},
+		"snapshot":      map[string]any{"id": "node-1", "type": "Node", "parentId": "bmc-1", "state": "Ready"},
+	})))
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.created", "node-2", map[string]any{
+		"snapshot": map[string]any{"id": "node-2", "type": "Node", "parentId": "bmc-1"},
+	})))
+
+	assert.Empty(t, st.links, "followers never write mappings")
+	coordinator.mu.Lock()
+	defer coordinator.mu.Unlock()
+	assert.Zero(t, coordinator.state.RequestedSeq, "followers leave events to the leader")
+}
+
+func TestHandleEvent_IgnoresStateOnlyChanges(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.updated", "node-1", map[string]any{
+		"changedFields": []string{"State", "Flag"},
+		"snapshot":      map[string]any{"id": "node-1", "type": "Node", "parentId": "bmc-1"},
+	})))
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.interfaces.updated", "bmc-1", map[string]any{
+		"changedFields": []string{"description"},
+		"componentId":   "bmc-1",
+	})))
+
+	assert.Empty(t, st.links)
+	assert.Empty(t, st.endpoints)
+	assert.Empty(t, s.resyncCh)
+	assert.Zero(t, s.Status().EventsApplied)
+}
+
+func TestHandleEvent_InsufficientDataSchedulesFullSync(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+	st.links["node-1"] = model.NodeBMCLink{NodeID: "node-1", BMCID: "bmc-1"}
+
+	// A state-only update without parentId must not drop the link.
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.updated", "node-1", map[string]any{
+		"componentId":   "node-1",
+		"changedFields": []string{"state"},
+		"snapshot":      map[string]any{"id": "node-1", "type": "Node", "state": "Ready"},
+	})))
+	assert.Contains(t, st.links, "node-1")
+	assert.Empty(t, s.resyncCh)
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.updated", "node-1", map[string]any{
+		"componentId":   "node-1",
+		"changedFields": []string{"parentId"},
+		"snapshot":      map[string]any{"id": "node-1", "type": "Node"},
+	})))
+	assert.Len(t, s.resyncCh, 1)
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.interfaces.deleted", "if-1", nil)))
+	assert.Len(t, s.resyncCh, 1, "resync requests coalesce")
+}
+
+func TestHandleEvent_IgnoredBeforeInitialSync(t *testing.T) {
+	st := newMemoryStore()
+	s := New(st, emptySMDClient(), Config{}, zerolog.Nop())
+
+	require.NoError(t, s.HandleEvent(context.Background(), smdEvent(t, "chamicore.smd.components.created", "node-1", map[string]any{
+		"snapshot": map[string]any{"id": "node-1", "type": "Node", "parentId": "bmc-1"},
+	})))
+	assert.Empty(t, st.links)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	st := newMemoryStore()
+	s := newReadyEventSyncer(t, st)
+	sub := &fakeEventSubscriber{}
+
+	require.NoError(t, s.SubscribeEvents(context.Background(), sub))
+	assert.Equal(t, []string{SMDEventsSubject}, sub.subjects)
+	assert.True(t, s.Status().EventsEnabled)
+
+	require.NoError(t, sub.handler(smdEvent(t, "chamicore.smd.components.created", "bmc-2", map[string]any{
+		"snapshot": map[string]any{"id": "bmc-2", "type": "BMC"},
+	})))
+	assert.Contains(t, st.endpoints, "bmc-2")
+
+	failing := New(newMemoryStore(), emptySMDClient(), Config{}, zerolog.Nop())
+	err := failing.SubscribeEvents(context.Background(), &fakeEventSubscriber{err: errors.New("nats down")})
+	require.Error(t, err)
+	assert.False(t, failing.Status().EventsEnabled)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"sort"
@@ -17,6 +18,7 @@ import (
 
 	"git.cscs.ch/openchami/chamicore-lib/httputil"
 	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	powersmd "git.cscs.ch/openchami/chamicore-power/internal/smd"
 	"git.cscs.ch/openchami/chamicore-power/internal/store"
 	smdclient "git.cscs.ch/openchami/chamicore-smd/pkg/client"
 	smdtypes "git.cscs.ch/openchami/chamicore-smd/pkg/types"
@@ -25,23 +27,53 @@ import (
 const (
 	defaultSyncInterval         = 5 * time.Minute
 	defaultStartupRetryInterval = 1 * time.Second
+	defaultLeaderPollInterval   = 5 * time.Second
+	followerWaitPollInterval    = 500 * time.Millisecond
+	leaderReleaseTimeout        = 5 * time.Second
+	defaultSyncPageSize         = 1000
 	maxSyncPageSize             = 10000
 )
 
+// ErrLeaderSyncFailed indicates the leader replica's sync run failed while a follower waited on it.
+var ErrLeaderSyncFailed = errors.New("leader mapping sync failed")
+
 // SMDClient describes the SMD calls used by the topology sync loop.
 type SMDClient interface {
 	ListComponents(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error)
 	ListEthernetInterfaces(ctx context.Context, opts smdclient.InterfaceListOptions) (*httputil.ResourceList[smdtypes.EthernetInterface], error)
 }
 
+// LeaderCoordinator arbitrates which replica performs mapping sync and shares
+// the leader's run state with followers.
+type LeaderCoordinator interface {
+	ReplicaID() string
+	TryAcquireLeadership(ctx context.Context) (bool, error)
+	ReleaseLeadership(ctx context.Context) error
+	RequestSync(ctx context.Context) (int64, error)
+	LoadSyncState(ctx context.Context) (model.MappingSyncState, error)
+	RecordSyncRun(ctx context.Context, run model.MappingSyncRun) error
+}
+
 // Config contains sync-loop settings.
 type Config struct {
 	Interval             time.Duration
 	StartupRetryInterval time.Duration
+	LeaderPollInterval   time.Duration
+	PageSize             int
 	SyncOnStartup        bool
 	DefaultCredentialID  string
 }
 
+// Option configures optional syncer behavior.
+type Option func(*Syncer)
+
+// WithLeaderCoordinator enables leader election so only one replica runs SyncOnce at a time.
+func WithLeaderCoordinator(coordinator LeaderCoordinator) Option {
+	return func(s *Syncer) {
+		s.coordinator = coordinator
+	}
+}
+
 // Status captures current and last-run mapping sync state.
 type Status struct {
 	Ready             bool                     `json:"ready"`
@@ -53,8 +85,22 @@ type Status struct {
 	LastError         string                   `json:"last_error,omitempty"`
 	NotModified       bool                     `json:"not_modified"`
 	LastCounts        model.MappingApplyCounts `json:"last_counts"`
+	LastReport        *model.MappingSyncReport `json:"last_report,omitempty"`
 	SuccessfulRuns    int64                    `json:"successful_runs"`
 	FailedRuns        int64                    `json:"failed_runs"`
+	LeaderElection    bool                     `json:"leader_election"`
+	Leader            bool                     `json:"leader"`
+	LeaderID          string                   `json:"leader_id,omitempty"`
+	ReplicaID         string                   `json:"replica_id,omitempty"`
+	EventsEnabled     bool                     `json:"events_enabled"`
+	EventsApplied     int64                    `json:"events_applied"`
+	LastEventAt       *time.Time               `json:"last_event_at,omitempty"`
+}
+
+// componentPage is the validator and item count of one SMD component page.
+type componentPage struct {
+	etag  string
+	count int
 }
 
 // Syncer runs periodic and on-demand reconciliation from SMD.
@@ -67,7 +113,11 @@ type Syncer struct {
 	startupRetry        time.Duration
 	syncOnStartup       bool
 	defaultCredentialID string
+	leaderPoll          time.Duration
+	pageSize            int
 	forceSyncCh         chan chan error
+	resyncCh            chan struct{}
+	coordinator         LeaderCoordinator
 
 	runMu   stdsync.Mutex
 	stateMu stdsync.RWMutex
@@ -75,12 +125,15 @@ type Syncer struct {
 
 	lastComponentETag string
 	lastInterfaceETag string
+	// componentPages caches per-page validators from the last applied sync; guarded by runMu.
+	componentPages []componentPage
 
-	ready atomic.Bool
+	ready  atomic.Bool
+	leader atomic.Bool
 }
 
 // New creates a new mapping syncer.
-func New(st store.Store, smd SMDClient, cfg Config, logger zerolog.Logger) *Syncer {
+func New(st store.Store, smd SMDClient, cfg Config, logger zerolog.Logger, opts ...Option) *Syncer {
 	interval := cfg.Interval
 	if interval <= 0 {
 		interval = defaultSyncInterval
@@ -89,8 +142,19 @@ func New(st store.Store, smd SMDClient, cfg Config, logger zerolog.Logger) *Sync
 	if startupRetry <= 0 {
 		startupRetry = defaultStartupRetryInterval
 	}
+	leaderPoll := cfg.LeaderPollInterval
+	if leaderPoll <= 0 {
+		leaderPoll = defaultLeaderPollInterval
+	}
+	pageSize := cfg.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultSyncPageSize
+	}
+	if pageSize > maxSyncPageSize {
+		pageSize = maxSyncPageSize
+	}
 
-	return &Syncer{
+	s := &Syncer{
 		store:               st,
 		smd:                 smd,
 		log:                 logger,
@@ -98,12 +162,28 @@ func New(st store.Store, smd SMDClient, cfg Config, logger zerolog.Logger) *Sync
 		startupRetry:        startupRetry,
 		syncOnStartup:       cfg.SyncOnStartup,
 		defaultCredentialID: strings.TrimSpace(cfg.DefaultCredentialID),
+		leaderPoll:          leaderPoll,
+		pageSize:            pageSize,
 		forceSyncCh:         make(chan chan error),
+		resyncCh:            make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.coordinator != nil {
+		s.status.LeaderElection = true
+		s.status.ReplicaID = s.coordinator.ReplicaID()
+	}
+	return s
 }
 
 // Run starts the sync loop and blocks until ctx is canceled.
 func (s *Syncer) Run(ctx context.Context) {
+	if s.coordinator != nil {
+		s.runElected(ctx)
+		return
+	}
+
 	if s.syncOnStartup {
 		if err := s.SyncOnce(ctx); err != nil {
 			s.log.Error().Err(err).Msg("initial mapping sync failed")
@@ -130,12 +210,25 @@ func (s *Syncer) Run(ctx context.Context) {
 		case resultCh := <-s.forceSyncCh:
 			resultCh <- s.SyncOnce(ctx)
 			tickerInterval = s.adjustTickerInterval(ticker, tickerInterval)
+		case <-s.resyncCh:
+			if err := s.SyncOnce(ctx); err != nil {
+				s.log.Error().Err(err).Msg("event-requested mapping sync failed")
+			}
+			tickerInterval = s.adjustTickerInterval(ticker, tickerInterval)
 		}
 	}
 }
 
 // Trigger requests an immediate sync and waits for the result.
+// With leader election enabled, followers ask the leader to sync and wait for its run.
 func (s *Syncer) Trigger(ctx context.Context) error {
+	if s.coordinator != nil {
+		if s.IsLeader() {
+			return s.leaderSync(ctx)
+		}
+		return s.waitForLeaderSync(ctx)
+	}
+
 	resultCh := make(chan error, 1)
 
 	select {
@@ -157,6 +250,15 @@ func (s *Syncer) IsReady() bool {
 	return s.ready.Load()
 }
 
+// IsLeader reports whether this replica currently holds mapping sync leadership.
+// Without leader election every replica acts as its own leader.
+func (s *Syncer) IsLeader() bool {
+	if s.coordinator == nil {
+		return true
+	}
+	return s.leader.Load()
+}
+
 // Status returns the latest sync status snapshot.
 func (s *Syncer) Status() Status {
 	s.stateMu.RLock()
@@ -164,8 +266,10 @@ func (s *Syncer) Status() Status {
 
 	statusCopy := s.status
 	statusCopy.Ready = s.ready.Load()
+	statusCopy.Leader = s.IsLeader()
 	statusCopy.LastAttemptAt = cloneTimePtr(s.status.LastAttemptAt)
 	statusCopy.LastSyncAt = cloneTimePtr(s.status.LastSyncAt)
+	statusCopy.LastEventAt = cloneTimePtr(s.status.LastEventAt)
 	return statusCopy
 }
 
@@ -185,21 +289,13 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 		st.InProgress = false
 	})
 
-	components, err := s.smd.ListComponents(ctx, smdclient.ComponentListOptions{
-		Fields:      "id,type,parentId",
-		Limit:       maxSyncPageSize,
-		Offset:      0,
-		IfNoneMatch: s.getLastComponentETag(),
-	})
+	components, pages, unchanged, err := s.listComponents(ctx, s.componentPages)
 	if err != nil {
 		s.markFailure(err)
 		return fmt.Errorf("listing components from SMD: %w", err)
 	}
 
-	interfaces, err := s.smd.ListEthernetInterfaces(ctx, smdclient.InterfaceListOptions{
-		Limit:  maxSyncPageSize,
-		Offset: 0,
-	})
+	interfaces, err := s.listInterfaces(ctx)
 	if err != nil {
 		s.markFailure(err)
 		return fmt.Errorf("listing ethernet interfaces from SMD: %w", err)
@@ -211,7 +307,7 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 		return fmt.Errorf("computing interface etag: %w", err)
 	}
 
-	if components == nil && interfaceETag == s.getLastInterfaceETag() {
+	if unchanged && interfaceETag == s.getLastInterfaceETag() {
 		completedAt := time.Now().UTC()
 		s.updateStatus(func(st *Status) {
 			st.LastSyncAt = &completedAt
@@ -223,22 +319,15 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 	}
 
 	if components == nil {
-		components, err = s.smd.ListComponents(ctx, smdclient.ComponentListOptions{
-			Fields: "id,type,parentId",
-			Limit:  maxSyncPageSize,
-			Offset: 0,
-		})
+		// Some pages were served from cache; the diff needs every item.
+		components, pages, _, err = s.listComponents(ctx, nil)
 		if err != nil {
 			s.markFailure(err)
 			return fmt.Errorf("refreshing components from SMD: %w", err)
 		}
-		if components == nil {
-			s.markFailure(fmt.Errorf("component refresh unexpectedly returned nil"))
-			return fmt.Errorf("refreshing components from SMD: empty response")
-		}
 	}
 
-	componentETag, err := computeResourceListETag(components)
+	componentETag, err := combinePageETags(pages)
 	if err != nil {
 		s.markFailure(err)
 		return fmt.Errorf("computing component etag: %w", err)
@@ -249,6 +338,11 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 		s.markFailure(err)
 		return fmt.Errorf("listing existing BMC endpoint mappings: %w", err)
 	}
+	existingLinks, err := s.store.ListNodeBMCLinks(ctx)
+	if err != nil {
+		s.markFailure(err)
+		return fmt.Errorf("listing existing node BMC links: %w", err)
+	}
 
 	desiredEndpoints, desiredLinks := buildDesiredMappings(
 		components,
@@ -256,14 +350,20 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 		existingEndpoints,
 		s.defaultCredentialID,
 	)
+	delta, report := diffTopology(desiredEndpoints, desiredLinks, existingEndpoints, existingLinks)
 
 	syncedAt := time.Now().UTC()
-	counts, err := s.store.ReplaceTopologyMappings(ctx, desiredEndpoints, desiredLinks, syncedAt)
-	if err != nil {
-		s.markFailure(err)
-		return fmt.Errorf("reconciling topology mappings: %w", err)
+	counts := model.MappingApplyCounts{}
+	if !delta.IsEmpty() {
+		counts, err = s.store.ApplyTopologyDelta(ctx, delta, syncedAt)
+		if err != nil {
+			s.markFailure(err)
+			return fmt.Errorf("reconciling topology mappings: %w", err)
+		}
 	}
+	report.GeneratedAt = syncedAt
 
+	s.componentPages = pages
 	s.setLastComponentETag(componentETag)
 	s.setLastInterfaceETag(interfaceETag)
 	s.ready.Store(true)
@@ -274,6 +374,7 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 		st.LastComponentETag = componentETag
 		st.LastInterfaceETag = interfaceETag
 		st.LastCounts = counts
+		st.LastReport = &report
 		st.NotModified = false
 		st.SuccessfulRuns++
 		st.LastError = ""
@@ -282,6 +383,270 @@ func (s *Syncer) SyncOnce(ctx context.Context) error {
 	return nil
 }
 
+// listComponents pages through SMD components. Pages with a cached validator are
+// requested conditionally; if any page is answered 304 the returned list is nil
+// and unchanged reports whether every page matched the cache.
+func (s *Syncer) listComponents(
+	ctx context.Context,
+	cached []componentPage,
+) (*httputil.ResourceList[smdtypes.Component], []componentPage, bool, error) {
+	combined := &httputil.ResourceList[smdtypes.Component]{}
+	pages := make([]componentPage, 0, len(cached))
+	cacheHits := 0
+	total := 0
+
+	for offset := 0; ; {
+		index := len(pages)
+		ifNoneMatch := ""
+		if index < len(cached) {
+			ifNoneMatch = cached[index].etag
+		}
+
+		page, err := s.smd.ListComponents(ctx, smdclient.ComponentListOptions{
+			Fields:      "id,type,parentId",
+			Limit:       s.pageSize,
+			Offset:      offset,
+			IfNoneMatch: ifNoneMatch,
+		})
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("page at offset %d: %w", offset, err)
+		}
+
+		var count int
+		if page == nil {
+			if ifNoneMatch == "" {
+				return nil, nil, false, fmt.Errorf("page at offset %d: empty response", offset)
+			}
+			cacheHits++
+			count = cached[index].count
+			pages = append(pages, cached[index])
+		} else {
+			etag, etagErr := computeResourceListETag(page)
+			if etagErr != nil {
+				return nil, nil, false, fmt.Errorf("page at offset %d: %w", offset, etagErr)
+			}
+			count = len(page.Items)
+			pages = append(pages, componentPage{etag: etag, count: count})
+			combined.Kind = page.Kind
+			combined.APIVersion = page.APIVersion
+			combined.Items = append(combined.Items, page.Items...)
+			total = page.Metadata.Total
+		}
+
+		offset += count
+		if !s.hasMorePages(count, offset, total) {
+			break
+		}
+	}
+
+	if cacheHits > 0 {
+		return nil, pages, cacheHits == len(pages) && len(pages) == len(cached), nil
+	}
+	combined.Metadata = httputil.ListMetadata{Total: len(combined.Items), Limit: s.pageSize}
+	return combined, pages, false, nil
+}
+
+// listInterfaces pages through all SMD ethernet interfaces.
+func (s *Syncer) listInterfaces(ctx context.Context) (*httputil.ResourceList[smdtypes.EthernetInterface], error) {
+	combined := &httputil.ResourceList[smdtypes.EthernetInterface]{}
+
+	for offset := 0; ; {
+		page, err := s.smd.ListEthernetInterfaces(ctx, smdclient.InterfaceListOptions{
+			Limit:  s.pageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("page at offset %d: %w", offset, err)
+		}
+		if page == nil {
+			break
+		}
+
+		combined.Kind = page.Kind
+		combined.APIVersion = page.APIVersion
+		combined.Items = append(combined.Items, page.Items...)
+
+		offset += len(page.Items)
+		if !s.hasMorePages(len(page.Items), offset, page.Metadata.Total) {
+			break
+		}
+	}
+
+	combined.Metadata = httputil.ListMetadata{Total: len(combined.Items), Limit: s.pageSize}
+	return combined, nil
+}
+
+// hasMorePages reports whether another page may follow. A short page ends the
+// listing; a full page ends it only when the server-reported total is reached.
+func (s *Syncer) hasMorePages(count, offset, total int) bool {
+	if count < s.pageSize {
+		return false
+	}
+	return total <= 0 || offset < total
+}
+
+func (s *Syncer) runElected(ctx context.Context) {
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), leaderReleaseTimeout)
+		defer cancel()
+		if err := s.coordinator.ReleaseLeadership(releaseCtx); err != nil {
+			s.log.Warn().Err(err).Msg("failed to release mapping sync leadership")
+		}
+		s.setLeader(false)
+	}()
+
+	s.checkLeadership(ctx)
+
+	leaderTicker := time.NewTicker(s.leaderPoll)
+	defer leaderTicker.Stop()
+	syncTicker := time.NewTicker(s.interval)
+	defer syncTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-leaderTicker.C:
+			s.checkLeadership(ctx)
+		case <-syncTicker.C:
+			if !s.IsLeader() {
+				continue
+			}
+			if err := s.leaderSync(ctx); err != nil {
+				s.log.Error().Err(err).Msg("periodic mapping sync failed")
+			}
+		case <-s.resyncCh:
+			if !s.IsLeader() {
+				continue
+			}
+			if err := s.leaderSync(ctx); err != nil {
+				s.log.Error().Err(err).Msg("event-requested mapping sync failed")
+			}
+		}
+	}
+}
+
+// checkLeadership renews or acquires leadership. Leaders service pending sync
+// requests and retry until ready; followers mirror the leader's shared state.
+func (s *Syncer) checkLeadership(ctx context.Context) {
+	isLeader, err := s.coordinator.TryAcquireLeadership(ctx)
+	if err != nil {
+		s.log.Warn().Err(err).Msg("mapping sync leader election failed")
+	}
+	wasLeader := s.setLeader(isLeader)
+	if isLeader != wasLeader {
+		s.log.Info().Bool("leader", isLeader).Str("replica_id", s.coordinator.ReplicaID()).Msg("mapping sync leadership changed")
+	}
+
+	state, err := s.coordinator.LoadSyncState(ctx)
+	if err != nil {
+		s.log.Warn().Err(err).Msg("failed to load shared mapping sync state")
+		return
+	}
+
+	if !isLeader {
+		s.applySharedState(state)
+		return
+	}
+
+	pending := state.RequestedSeq > state.CompletedSeq
+	firstRun := !wasLeader && s.syncOnStartup
+	if pending || firstRun || !s.IsReady() {
+		if err := s.leaderSync(ctx); err != nil {
+			s.log.Error().Err(err).Msg("leader mapping sync failed")
+		}
+	}
+}
+
+// leaderSync runs SyncOnce and publishes the outcome to followers.
+func (s *Syncer) leaderSync(ctx context.Context) error {
+	var handledSeq int64
+	if state, err := s.coordinator.LoadSyncState(ctx); err != nil {
+		s.log.Warn().Err(err).Msg("failed to load shared mapping sync state")
+	} else {
+		handledSeq = state.RequestedSeq
+	}
+
+	attemptedAt := time.Now().UTC()
+	syncErr := s.SyncOnce(ctx)
+
+	status := s.Status()
+	run := model.MappingSyncRun{
+		LeaderID:    s.coordinator.ReplicaID(),
+		HandledSeq:  handledSeq,
+		AttemptedAt: attemptedAt,
+		Counts:      status.LastCounts,
+	}
+	if syncErr != nil {
+		run.Error = syncErr.Error()
+	} else {
+		run.SyncedAt = status.LastSyncAt
+		run.Report = status.LastReport
+	}
+	if err := s.coordinator.RecordSyncRun(ctx, run); err != nil {
+		s.log.Warn().Err(err).Msg("failed to record mapping sync run")
+	}
+
+	s.updateStatus(func(st *Status) {
+		st.LeaderID = s.coordinator.ReplicaID()
+	})
+	return syncErr
+}
+
+// waitForLeaderSync records a sync request and blocks until the leader completes a run covering it.
+func (s *Syncer) waitForLeaderSync(ctx context.Context) error {
+	seq, err := s.coordinator.RequestSync(ctx)
+	if err != nil {
+		return fmt.Errorf("requesting leader mapping sync: %w", err)
+	}
+
+	ticker := time.NewTicker(followerWaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		state, err := s.coordinator.LoadSyncState(ctx)
+		if err != nil {
+			s.log.Warn().Err(err).Msg("failed to load shared mapping sync state")
+			continue
+		}
+		if state.CompletedSeq < seq {
+			continue
+		}
+
+		s.applySharedState(state)
+		if state.LastError != "" {
+			return fmt.Errorf("%w: %s", ErrLeaderSyncFailed, state.LastError)
+		}
+		return nil
+	}
+}
+
+func (s *Syncer) applySharedState(state model.MappingSyncState) {
+	if state.LastSyncAt != nil {
+		s.ready.Store(true)
+	}
+	s.updateStatus(func(st *Status) {
+		st.LeaderID = state.LeaderID
+		st.LastAttemptAt = cloneTimePtr(state.LastAttemptAt)
+		st.LastSyncAt = cloneTimePtr(state.LastSyncAt)
+		st.LastError = state.LastError
+		st.LastCounts = state.LastCounts
+		if state.LastReport != nil {
+			st.LastReport = state.LastReport
+		}
+	})
+}
+
+func (s *Syncer) setLeader(isLeader bool) bool {
+	return s.leader.Swap(isLeader)
+}
+
 func buildDesiredMappings(
 	components *httputil.ResourceList[smdtypes.Component],
 	interfaces *httputil.ResourceList[smdtypes.EthernetInterface],
@@ -383,7 +748,7 @@ func buildDesiredMappings(
 }
 
 func endpointFromIPAddrs(ipAddrs json.RawMessage) string {
-	addresses := parseIPAddrs(ipAddrs)
+	addresses := powersmd.ParseIPAddrs(ipAddrs)
 	for _, address := range addresses {
 		if endpoint := normalizeEndpoint(address); endpoint != "" {
 			return endpoint
@@ -392,32 +757,6 @@ func endpointFromIPAddrs(ipAddrs json.RawMessage) string {
 	return ""
 }
 
-func parseIPAddrs(raw json.RawMessage) []string {
-	if len(raw) == 0 {
-		return nil
-	}
-
-	addresses := make([]string, 0, 4)
-	if err := json.Unmarshal(raw, &addresses); err == nil {
-		return addresses
-	}
-
-	var generic []any
-	if err := json.Unmarshal(raw, &generic); err != nil {
-		return nil
-	}
-
-	addresses = make([]string, 0, len(generic))
-	for _, value := range generic {
-		asString, ok := value.(string)
-		if !ok {
-			continue
-		}
-		addresses = append(addresses, asString)
-	}
-	return addresses
-}
-
 func normalizeEndpoint(raw string) string {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -464,6 +803,23 @@ func computeResourceListETag[T any](list *httputil.ResourceList[T]) (string, err
 	return fmt.Sprintf(`W/"%x"`, hash[:8]), nil
 }
 
+// combinePageETags derives one component validator from the per-page validators.
+func combinePageETags(pages []componentPage) (string, error) {
+	if len(pages) == 1 {
+		return pages[0].etag, nil
+	}
+	etags := make([]string, 0, len(pages))
+	for _, page := range pages {
+		etags = append(etags, page.etag)
+	}
+	encoded, err := json.Marshal(etags)
+	if err != nil {
+		return "", fmt.Errorf("marshaling page etags: %w", err)
+	}
+	hash := sha256.Sum256(encoded)
+	return fmt.Sprintf(`W/"%x"`, hash[:8]), nil
+}
+
 func cloneTimePtr(v *time.Time) *time.Time {
 	if v == nil {
 		return nil
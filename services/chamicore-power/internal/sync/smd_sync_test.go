@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sort"
 	"sync"
 	"testing"
@@ -50,46 +51,66 @@ func (m *memoryStore) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (m *memoryStore) ReplaceTopologyMappings(
+func (m *memoryStore) ApplyTopologyDelta(
 	ctx context.Context,
-	endpoints []model.BMCEndpoint,
-	links []model.NodeBMCLink,
+	delta model.TopologyDelta,
 	syncedAt time.Time,
 ) (model.MappingApplyCounts, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	newEndpoints := make(map[string]model.BMCEndpoint, len(endpoints))
-	for _, endpoint := range endpoints {
-		copyEndpoint := endpoint
-		copyEndpoint.LastSyncedAt = syncedAt
-		newEndpoints[endpoint.BMCID] = copyEndpoint
+	var counts model.MappingApplyCounts
+	for _, nodeID := range delta.DeletedNodeIDs {
+		if _, ok := m.links[nodeID]; ok {
+			delete(m.links, nodeID)
+			counts.LinksDeleted++
+		}
 	}
-
-	newLinks := make(map[string]model.NodeBMCLink, len(links))
-	for _, link := range links {
+	for _, endpoint := range delta.Endpoints {
+		merged := endpoint
+		if existing, ok := m.endpoints[endpoint.BMCID]; ok {
+			if merged.Endpoint == "" {
+				merged.Endpoint = existing.Endpoint
+			}
+			if !delta.Authoritative {
+				if existing.CredentialID != "" {
+					merged.CredentialID = existing.CredentialID
+				}
+				merged.InsecureSkipVerify = existing.InsecureSkipVerify
+			}
+		}
+		merged.LastSyncedAt = syncedAt
+		m.endpoints[endpoint.BMCID] = merged
+		counts.EndpointsUpserted++
+	}
+	for _, refresh := range delta.EndpointRefreshes {
+		existing, ok := m.endpoints[refresh.BMCID]
+		if !ok || refresh.Endpoint == "" {
+			continue
+		}
+		existing.Endpoint = refresh.Endpoint
+		existing.LastSyncedAt = syncedAt
+		m.endpoints[refresh.BMCID] = existing
+		counts.EndpointsUpserted++
+	}
+	for _, link := range delta.Links {
 		copyLink := link
 		copyLink.LastSyncedAt = syncedAt
-		newLinks[link.NodeID] = copyLink
+		m.links[link.NodeID] = copyLink
+		counts.LinksUpserted++
 	}
-
-	var counts model.MappingApplyCounts
-	counts.EndpointsUpserted = len(newEndpoints)
-	counts.LinksUpserted = len(newLinks)
-
-	for bmcID := range m.endpoints {
-		if _, ok := newEndpoints[bmcID]; !ok {
-			counts.EndpointsDeleted++
+	for _, bmcID := range delta.DeletedBMCIDs {
+		for nodeID, link := range m.links {
+			if link.BMCID == bmcID {
+				delete(m.links, nodeID)
+				counts.LinksDeleted++
+			}
 		}
-	}
-	for nodeID := range m.links {
-		if _, ok := newLinks[nodeID]; !ok {
-			counts.LinksDeleted++
+		if _, ok := m.endpoints[bmcID]; ok {
+			delete(m.endpoints, bmcID)
+			counts.EndpointsDeleted++
 		}
 	}
-
-	m.endpoints = newEndpoints
-	m.links = newLinks
 	return counts, nil
 }
 
@@ -386,6 +407,197 @@ func TestSyncOnce_ComponentFetchError(t *testing.T) {
 	assert.NotEmpty(t, status.LastError)
 }
 
+// pagedSMDClient serves components and interfaces in Limit/Offset pages.
+type pagedSMDClient struct {
+	components     []smdtypes.Component
+	interfaces     []smdtypes.EthernetInterface
+	componentCalls []smdclient.ComponentListOptions
+}
+
+func (p *pagedSMDClient) client() *mockSMDClient {
+	return &mockSMDClient{
+		listComponentsFn: func(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error) {
+			p.componentCalls = append(p.componentCalls, opts)
+			list := &httputil.ResourceList[smdtypes.Component]{
+				Kind:       "ComponentList",
+				APIVersion: "hsm/v2",
+				Metadata:   httputil.ListMetadata{Total: len(p.components), Limit: opts.Limit, Offset: opts.Offset},
+			}
+			for _, item := range pageOf(p.components, opts.Offset, opts.Limit) {
+				list.Items = append(list.Items, httputil.Resource[smdtypes.Component]{Spec: item})
+			}
+			if opts.IfNoneMatch != "" {
+				if etag, err := computeResourceListETag(list); err == nil && etag == opts.IfNoneMatch {
+					return nil, nil
+				}
+			}
+			return list, nil
+		},
+		listEthernetInterfacesFn: func(ctx context.Context, opts smdclient.InterfaceListOptions) (*httputil.ResourceList[smdtypes.EthernetInterface], error) {
+			list := &httputil.ResourceList[smdtypes.EthernetInterface]{
+				Kind:       "EthernetInterfaceList",
+				APIVersion: "hsm/v2",
+				Metadata:   httputil.ListMetadata{Total: len(p.interfaces), Limit: opts.Limit, Offset: opts.Offset},
+			}
+			for _, item := range pageOf(p.interfaces, opts.Offset, opts.Limit) {
+				list.Items = append(list.Items, httputil.Resource[smdtypes.EthernetInterface]{Spec: item})
+			}
+			return list, nil
+		},
+	}
+}
+
+func pageOf[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+func pagedInventory(nodes int) ([]smdtypes.Component, []smdtypes.EthernetInterface) {
+	components := make([]smdtypes.Component, 0, nodes*2)
+	interfaces := make([]smdtypes.EthernetInterface, 0, nodes)
+	for i := 0; i < nodes; i++ {
+		bmcID := fmt.Sprintf("bmc-%02d", i)
+		parent := bmcID
+		components = append(components,
+			smdtypes.Component{ID: bmcID, Type: "BMC"},
+			smdtypes.Component{ID: fmt.Sprintf("node-%02d", i), Type: "Node", ParentID: &parent},
+		)
+		interfaces = append(interfaces, smdtypes.EthernetInterface{
+			ComponentID: bmcID,
+			IPAddrs:     json.RawMessage(fmt.Sprintf(`["10.0.0.%d"]`, i+1)),
+		})
+	}
+	return components, interfaces
+}
+
+func TestSyncOnce_PagesThroughInventory(t *testing.T) {
+	st := newMemoryStore()
+	components, interfaces := pagedInventory(7)
+	smd := &pagedSMDClient{components: components, interfaces: interfaces}
+
+	s := New(st, smd.client(), Config{Interval: time.Second, PageSize: 4}, zerolog.Nop())
+	require.NoError(t, s.SyncOnce(context.Background()))
+
+	endpoints, err := st.ListBMCEndpoints(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 7)
+	links, err := st.ListNodeBMCLinks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, links, 7)
+	assert.Equal(t, "https://10.0.0.7", endpoints[6].Endpoint)
+
+	require.Len(t, smd.componentCalls, 4)
+	for i, call := range smd.componentCalls {
+		assert.Equal(t, 4, call.Limit)
+		assert.Equal(t, i*4, call.Offset)
+	}
+
+	status := s.Status()
+	require.NotNil(t, status.LastReport)
+	assert.Equal(t, 7, status.LastReport.Summary.BMCsAdded)
+	assert.Equal(t, 7, status.LastReport.Summary.LinksAdded)
+
+	smd.componentCalls = nil
+	require.NoError(t, s.SyncOnce(context.Background()))
+	assert.True(t, s.Status().NotModified)
+	require.Len(t, smd.componentCalls, 4)
+	for _, call := range smd.componentCalls {
+		assert.NotEmpty(t, call.IfNoneMatch)
+	}
+}
+
+func TestSyncOnce_AppliesOnlyChangedRowsAndReports(t *testing.T) {
+	st := newMemoryStore()
+	components, interfaces := pagedInventory(6)
+	smd := &pagedSMDClient{components: components, interfaces: interfaces}
+
+	s := New(st, smd.client(), Config{Interval: time.Second, PageSize: 5}, zerolog.Nop())
+	require.NoError(t, s.SyncOnce(context.Background()))
+
+	// Move node-01 to bmc-02, drop bmc-05/node-05, and renumber bmc-00.
+	moved := "bmc-02"
+	smd.components[3].ParentID = &moved
+	smd.components = smd.components[:10]
+	smd.interfaces[0].IPAddrs = json.RawMessage(`["10.9.9.9"]`)
+	smd.interfaces = smd.interfaces[:5]
+
+	require.NoError(t, s.SyncOnce(context.Background()))
+
+	status := s.Status()
+	assert.False(t, status.NotModified)
+	assert.Equal(t, model.MappingApplyCounts{
+		EndpointsUpserted: 1,
+		EndpointsDeleted:  1,
+		LinksUpserted:     1,
+		LinksDeleted:      1,
+	}, status.LastCounts)
+
+	report := status.LastReport
+	require.NotNil(t, report)
+	assert.False(t, report.Truncated)
+	assert.Equal(t, model.MappingSyncSummary{
+		BMCsChanged:  1,
+		BMCsRemoved:  1,
+		LinksChanged: 1,
+		LinksRemoved: 1,
+	}, report.Summary)
+	assert.Empty(t, report.BMCsAdded)
+	assert.Empty(t, report.LinksAdded)
+	assert.Equal(t, []model.BMCMappingChange{{
+		BMCID:            "bmc-00",
+		Endpoint:         "https://10.9.9.9",
+		PreviousEndpoint: "https://10.0.0.1",
+	}}, report.BMCsChanged)
+	assert.Equal(t, []model.BMCMappingChange{{
+		BMCID:            "bmc-05",
+		PreviousEndpoint: "https://10.0.0.6",
+	}}, report.BMCsRemoved)
+	assert.Equal(t, []model.LinkMappingChange{{
+		NodeID:        "node-01",
+		BMCID:         "bmc-02",
+		PreviousBMCID: "bmc-01",
+	}}, report.LinksChanged)
+	assert.Equal(t, []model.LinkMappingChange{{
+		NodeID:        "node-05",
+		PreviousBMCID: "bmc-05",
+	}}, report.LinksRemoved)
+
+	links, err := st.ListNodeBMCLinks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, links, 5)
+	assert.Equal(t, "node-01", links[1].NodeID)
+	assert.Equal(t, "bmc-02", links[1].BMCID)
+}
+
+func TestDiffTopology_TruncatesReportLists(t *testing.T) {
+	desired := make([]model.BMCEndpoint, 0, maxReportEntries+5)
+	for i := 0; i < maxReportEntries+5; i++ {
+		desired = append(desired, model.BMCEndpoint{BMCID: fmt.Sprintf("bmc-%05d", i), Source: "smd"})
+	}
+
+	delta, report := diffTopology(desired, nil, nil, nil)
+	assert.Len(t, delta.Endpoints, maxReportEntries+5)
+	assert.Equal(t, maxReportEntries+5, report.Summary.BMCsAdded)
+	assert.Len(t, report.BMCsAdded, maxReportEntries)
+	assert.True(t, report.Truncated)
+}
+
+func TestDiffTopology_ReportsTLSChangeAndOverwritesSettings(t *testing.T) {
+	existing := []model.BMCEndpoint{{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", Source: "smd"}}
+	desired := []model.BMCEndpoint{{BMCID: "bmc-1", Endpoint: "https://10.0.0.1", CredentialID: "cred-1", InsecureSkipVerify: true, Source: "smd"}}
+
+	delta, report := diffTopology(desired, nil, existing, nil)
+	assert.True(t, delta.Authoritative)
+	assert.Equal(t, desired, delta.Endpoints)
+	assert.Equal(t, 1, report.Summary.BMCsChanged)
+}
+
 func TestTrigger_ContextTimeoutWhenLoopNotRunning(t *testing.T) {
 	s := New(newMemoryStore(), &mockSMDClient{}, Config{}, zerolog.Nop())
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
@@ -543,3 +755,167 @@ func TestBuildDesiredMappings_PreservesExistingCredentialAndNormalizesEndpoint(t
 }
 
 var _ store.Store = (*memoryStore)(nil)
+
+type fakeLeaderCoordinator struct {
+	mu        sync.Mutex
+	leader    bool
+	state     model.MappingSyncState
+	runs      []model.MappingSyncRun
+	released  bool
+	onRequest func(seq int64, state *model.MappingSyncState)
+}
+
+func (f *fakeLeaderCoordinator) ReplicaID() string {
+	return "power-0"
+}
+
+func (f *fakeLeaderCoordinator) TryAcquireLeadership(ctx context.Context) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.leader, nil
+}
+
+func (f *fakeLeaderCoordinator) ReleaseLeadership(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = true
+	return nil
+}
+
+func (f *fakeLeaderCoordinator) RequestSync(ctx context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state.RequestedSeq++
+	if f.onRequest != nil {
+		f.onRequest(f.state.RequestedSeq, &f.state)
+	}
+	return f.state.RequestedSeq, nil
+}
+
+func (f *fakeLeaderCoordinator) LoadSyncState(ctx context.Context) (model.MappingSyncState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state, nil
+}
+
+func (f *fakeLeaderCoordinator) RecordSyncRun(ctx context.Context, run model.MappingSyncRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs = append(f.runs, run)
+	if run.HandledSeq > f.state.CompletedSeq {
+		f.state.CompletedSeq = run.HandledSeq
+	}
+	f.state.LeaderID = run.LeaderID
+	f.state.LastError = run.Error
+	if run.SyncedAt != nil {
+		f.state.LastSyncAt = run.SyncedAt
+	}
+	return nil
+}
+
+func (f *fakeLeaderCoordinator) recordedRuns() []model.MappingSyncRun {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]model.MappingSyncRun(nil), f.runs...)
+}
+
+func emptySMDClient() *mockSMDClient {
+	return &mockSMDClient{
+		listComponentsFn: func(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error) {
+			return &httputil.ResourceList[smdtypes.Component]{Kind: "ComponentList", APIVersion: "hsm/v2"}, nil
+		},
+		listEthernetInterfacesFn: func(ctx context.Context, opts smdclient.InterfaceListOptions) (*httputil.ResourceList[smdtypes.EthernetInterface], error) {
+			return &httputil.ResourceList[smdtypes.EthernetInterface]{Kind: "EthernetInterfaceList", APIVersion: "hsm/v2"}, nil
+		},
+	}
+}
+
+func TestRun_LeaderSyncsAndRecordsRun(t *testing.T) {
+	coordinator := &fakeLeaderCoordinator{leader: true}
+	coordinator.state.RequestedSeq = 3
+	s := New(newMemoryStore(), emptySMDClient(), Config{
+		Interval:           time.Hour,
+		LeaderPollInterval: time.Hour,
+	}, zerolog.Nop(), WithLeaderCoordinator(coordinator))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !s.IsReady() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.True(t, s.IsReady())
+
+	status := s.Status()
+	assert.True(t, status.LeaderElection)
+	assert.True(t, status.Leader)
+	assert.Equal(t, "power-0", status.ReplicaID)
+	assert.Equal(t, "power-0", status.LeaderID)
+
+	runs := coordinator.recordedRuns()
+	require.Len(t, runs, 1)
+	assert.Equal(t, int64(3), runs[0].HandledSeq)
+	assert.Empty(t, runs[0].Error)
+	assert.NotNil(t, runs[0].SyncedAt)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sync loop did not stop after cancellation")
+	}
+	assert.True(t, coordinator.released)
+	assert.False(t, s.IsLeader())
+}
+
+func TestTrigger_FollowerWaitsForLeaderRun(t *testing.T) {
+	listCalls := 0
+	smd := emptySMDClient()
+	smd.listComponentsFn = func(ctx context.Context, opts smdclient.ComponentListOptions) (*httputil.ResourceList[smdtypes.Component], error) {
+		listCalls++
+		return nil, errors.New("followers must not call SMD")
+	}
+
+	syncedAt := time.Now().UTC()
+	coordinator := &fakeLeaderCoordinator{
+		onRequest: func(seq int64, state *model.MappingSyncState) {
+			state.CompletedSeq = seq
+			state.LeaderID = "power-1"
+			state.LastSyncAt = &syncedAt
+		},
+	}
+	s := New(newMemoryStore(), smd, Config{}, zerolog.Nop(), WithLeaderCoordinator(coordinator))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, s.Trigger(ctx))
+
+	assert.Zero(t, listCalls)
+	assert.True(t, s.IsReady())
+	status := s.Status()
+	assert.False(t, status.Leader)
+	assert.Equal(t, "power-1", status.LeaderID)
+}
+
+func TestTrigger_FollowerReportsLeaderFailure(t *testing.T) {
+	coordinator := &fakeLeaderCoordinator{
+		onRequest: func(seq int64, state *model.MappingSyncState) {
+			state.CompletedSeq = seq
+			state.LeaderID = "power-1"
+			state.LastError = "listing components from SMD: boom"
+		},
+	}
+	s := New(newMemoryStore(), emptySMDClient(), Config{}, zerolog.Nop(), WithLeaderCoordinator(coordinator))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := s.Trigger(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrLeaderSyncFailed)
+	assert.False(t, s.IsReady())
+}
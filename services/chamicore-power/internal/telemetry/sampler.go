@@ -0,0 +1,262 @@
+// Package telemetry samples node power draw and inlet temperature from BMCs.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+const (
+	defaultInterval    = 30 * time.Second
+	defaultResolution  = time.Minute
+	defaultRetention   = 7 * 24 * time.Hour
+	defaultConcurrency = 8
+	pruneInterval      = time.Hour
+)
+
+// Reader reads one node's current telemetry from its BMC.
+type Reader interface {
+	ReadTelemetry(ctx context.Context, req engine.ExecutionRequest) (engine.TelemetryReading, error)
+}
+
+// Store defines the persistence methods used by the sampler.
+type Store interface {
+	ListNodeBMCLinks(ctx context.Context) ([]model.NodeBMCLink, error)
+	ResolveNodeMappings(ctx context.Context, nodeIDs []string) ([]model.NodePowerMapping, []model.NodeMappingError, error)
+	RecordTelemetrySamples(ctx context.Context, samples []model.TelemetrySample, resolution time.Duration) error
+	PruneTelemetry(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Config contains sampler settings.
+type Config struct {
+	Interval    time.Duration
+	Resolution  time.Duration
+	Retention   time.Duration
+	Concurrency int
+}
+
+// Option configures optional sampler behavior.
+type Option func(*Sampler)
+
+// WithLeaderCheck restricts sampling to the replica for which isLeader
+// reports true, so replicas do not sample the same BMCs twice.
+func WithLeaderCheck(isLeader func() bool) Option {
+	return func(s *Sampler) {
+		s.isLeader = isLeader
+	}
+}
+
+// WithBMCLimiter makes every read take a slot from acquire first, sharing the
+// per-BMC limit with transition tasks. acquire returns the slot's release.
+func WithBMCLimiter(acquire func(ctx context.Context, bmcID string) (func(), error)) Option {
+	return func(s *Sampler) {
+		s.acquireBMC = acquire
+	}
+}
+
+// Sampler periodically reads telemetry for every mapped node and stores it
+// downsampled to the configured resolution.
+type Sampler struct {
+	store  Store
+	reader Reader
+	log    zerolog.Logger
+
+	interval    time.Duration
+	resolution  time.Duration
+	retention   time.Duration
+	concurrency int
+	isLeader    func() bool
+	acquireBMC  func(ctx context.Context, bmcID string) (func(), error)
+	now         func() time.Time
+}
+
+// New creates a telemetry sampler.
+func New(st Store, reader Reader, cfg Config, logger zerolog.Logger, opts ...Option) *Sampler {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	resolution := cfg.Resolution
+	if resolution <= 0 {
+		resolution = defaultResolution
+	}
+	retention := cfg.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	s := &Sampler{
+		store:       st,
+		reader:      reader,
+		log:         logger,
+		interval:    interval,
+		resolution:  resolution,
+		retention:   retention,
+		concurrency: concurrency,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Resolution returns the bucket width samples are stored at.
+func (s *Sampler) Resolution() time.Duration {
+	return s.resolution
+}
+
+// Run samples on every interval and prunes expired telemetry until ctx is
+// canceled.
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.isLeader != nil && !s.isLeader() {
+			continue
+		}
+
+		if _, err := s.SampleOnce(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error().Err(err).Msg("telemetry sampling failed")
+		}
+
+		if now := s.now(); now.Sub(lastPrune) >= pruneInterval {
+			deleted, err := s.store.PruneTelemetry(ctx, now.Add(-s.retention))
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.Error().Err(err).Msg("pruning telemetry failed")
+				}
+				continue
+			}
+			lastPrune = now
+			if deleted > 0 {
+				s.log.Debug().Int64("deleted", deleted).Msg("pruned expired telemetry")
+			}
+		}
+	}
+}
+
+// SampleOnce reads telemetry from every mapped node and returns the number of
+// samples stored. Nodes behind the same BMC are read one at a time; distinct
+// BMCs are read concurrently up to the configured concurrency. With a BMC
+// limiter, each read also waits for a slot on its BMC.
+func (s *Sampler) SampleOnce(ctx context.Context) (int, error) {
+	links, err := s.store.ListNodeBMCLinks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing node links: %w", err)
+	}
+	if len(links) == 0 {
+		return 0, nil
+	}
+
+	nodeIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		nodeIDs = append(nodeIDs, link.NodeID)
+	}
+	mappings, _, err := s.store.ResolveNodeMappings(ctx, nodeIDs)
+	if err != nil {
+		return 0, fmt.Errorf("resolving node mappings: %w", err)
+	}
+
+	byBMC := make(map[string][]model.NodePowerMapping)
+	for _, mapping := range mappings {
+		byBMC[mapping.BMCID] = append(byBMC[mapping.BMCID], mapping)
+	}
+	bmcIDs := make([]string, 0, len(byBMC))
+	for bmcID := range byBMC {
+		bmcIDs = append(bmcIDs, bmcID)
+	}
+	sort.Strings(bmcIDs)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		samples = make([]model.TelemetrySample, 0, len(mappings))
+		sem     = make(chan struct{}, s.concurrency)
+	)
+	for _, bmcID := range bmcIDs {
+		nodes := byBMC[bmcID]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return 0, ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, mapping := range nodes {
+				sample, ok := s.sampleNode(ctx, mapping)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				samples = append(samples, sample)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := s.store.RecordTelemetrySamples(ctx, samples, s.resolution); err != nil {
+		return 0, fmt.Errorf("recording telemetry samples: %w", err)
+	}
+	return len(samples), nil
+}
+
+func (s *Sampler) sampleNode(ctx context.Context, mapping model.NodePowerMapping) (model.TelemetrySample, bool) {
+	if ctx.Err() != nil {
+		return model.TelemetrySample{}, false
+	}
+	readCtx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	if s.acquireBMC != nil {
+		release, err := s.acquireBMC(readCtx, mapping.BMCID)
+		if err != nil {
+			s.log.Debug().Err(err).Str("node_id", mapping.NodeID).Str("bmc_id", mapping.BMCID).Msg("no BMC slot for telemetry read")
+			return model.TelemetrySample{}, false
+		}
+		defer release()
+	}
+
+	reading, err := s.reader.ReadTelemetry(readCtx, engine.ExecutionRequest{
+		NodeID:             mapping.NodeID,
+		BMCID:              mapping.BMCID,
+		Endpoint:           mapping.Endpoint,
+		CredentialID:       mapping.CredentialID,
+		InsecureSkipVerify: mapping.InsecureSkipVerify,
+	})
+	if err != nil {
+		s.log.Debug().Err(err).Str("node_id", mapping.NodeID).Str("bmc_id", mapping.BMCID).Msg("reading node telemetry failed")
+		return model.TelemetrySample{}, false
+	}
+
+	return model.TelemetrySample{
+		NodeID:           mapping.NodeID,
+		SampledAt:        s.now().UTC(),
+		PowerWatts:       reading.PowerWatts,
+		InletTempCelsius: reading.InletTempCelsius,
+	}, true
+}
@@ -0,0 +1,214 @@
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+)
+
+type fakeStore struct {
+	mu         sync.Mutex
+	mappings   []model.NodePowerMapping
+	samples    []model.TelemetrySample
+	resolution time.Duration
+	pruned     []time.Time
+}
+
+func (f *fakeStore) ListNodeBMCLinks(ctx context.Context) ([]model.NodeBMCLink, error) {
+	_ = ctx
+	links := make([]model.NodeBMCLink, 0, len(f.mappings))
+	for _, mapping := range f.mappings {
+		links = append(links, model.NodeBMCLink{NodeID: mapping.NodeID, BMCID: mapping.BMCID})
+	}
+	return links, nil
+}
+
+func (f *fakeStore) ResolveNodeMappings(ctx context.Context, nodeIDs []string) ([]model.NodePowerMapping, []model.NodeMappingError, error) {
+	_ = ctx
+	_ = nodeIDs
+	return f.mappings, nil, nil
+}
+
+func (f *fakeStore) RecordTelemetrySamples(ctx context.Context, samples []model.TelemetrySample, resolution time.Duration) error {
+	_ = ctx
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples = append(f.samples, samples...)
+	f.resolution = resolution
+	return nil
+}
+
+func (f *fakeStore) PruneTelemetry(ctx context.Context, cutoff time.Time) (int64, error) {
+	_ = ctx
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pruned = append(f.pruned, cutoff)
+	return 0, nil
+}
+
+type fakeReader struct {
+	mu       sync.Mutex
+	readings map[string]engine.TelemetryReading
+	active   map[string]int
+	overlap  bool
+}
+
+func (f *fakeReader) ReadTelemetry(ctx context.Context, req engine.ExecutionRequest) (engine.TelemetryReading, error) {
+	_ = ctx
+	f.mu.Lock()
+	f.active[req.BMCID]++
+	if f.active[req.BMCID] > 1 {
+		f.overlap = true
+	}
+	f.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active[req.BMCID]--
+	reading, ok := f.readings[req.NodeID]
+	if !ok {
+		return engine.TelemetryReading{}, engine.ErrTelemetryUnsupported
+	}
+	return reading, nil
+}
+
+func watts(v float64) *float64 {
+	return &v
+}
+
+func TestSampler_SampleOnce(t *testing.T) {
+	st := &fakeStore{mappings: []model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1"},
+		{NodeID: "node-2", BMCID: "bmc-1", Endpoint: "https://bmc-1"},
+		{NodeID: "node-3", BMCID: "bmc-2", Endpoint: "https://bmc-2"},
+	}}
+	reader := &fakeReader{
+		active: make(map[string]int),
+		readings: map[string]engine.TelemetryReading{
+			"node-1": {PowerWatts: watts(410), InletTempCelsius: watts(22.5)},
+			"node-2": {PowerWatts: watts(390)},
+		},
+	}
+	sampler := New(st, reader, Config{Resolution: 5 * time.Minute}, zerolog.Nop())
+	sampledAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sampler.now = func() time.Time { return sampledAt }
+
+	stored, err := sampler.SampleOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+	assert.False(t, reader.overlap, "nodes behind one BMC must be read sequentially")
+
+	sort.Slice(st.samples, func(i, j int) bool { return st.samples[i].NodeID < st.samples[j].NodeID })
+	assert.Equal(t, []model.TelemetrySample{
+		{NodeID: "node-1", SampledAt: sampledAt, PowerWatts: watts(410), InletTempCelsius: watts(22.5)},
+		{NodeID: "node-2", SampledAt: sampledAt, PowerWatts: watts(390)},
+	}, st.samples)
+	assert.Equal(t, 5*time.Minute, st.resolution)
+}
+
+func TestSampler_SharesBMCLimiter(t *testing.T) {
+	st := &fakeStore{mappings: []model.NodePowerMapping{
+		{NodeID: "node-1", BMCID: "bmc-1", Endpoint: "https://bmc-1"},
+		{NodeID: "node-2", BMCID: "bmc-2", Endpoint: "https://bmc-2"},
+	}}
+	reader := &fakeReader{
+		active: make(map[string]int),
+		readings: map[string]engine.TelemetryReading{
+			"node-1": {PowerWatts: watts(410)},
+			"node-2": {PowerWatts: watts(390)},
+		},
+	}
+	var mu sync.Mutex
+	var acquired, released []string
+	acquire := func(ctx context.Context, bmcID string) (func(), error) {
+		if bmcID == "bmc-2" {
+			// A busy BMC: the read gives up once its deadline passes.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		acquired = append(acquired, bmcID)
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			released = append(released, bmcID)
+		}, nil
+	}
+	sampler := New(st, reader, Config{Interval: 20 * time.Millisecond}, zerolog.Nop(), WithBMCLimiter(acquire))
+
+	stored, err := sampler.SampleOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	require.Len(t, st.samples, 1)
+	assert.Equal(t, "node-1", st.samples[0].NodeID)
+	assert.Equal(t, []string{"bmc-1"}, acquired)
+	assert.Equal(t, []string{"bmc-1"}, released)
+}
+
+func TestSampler_RunSkipsFollowersAndPrunes(t *testing.T) {
+	st := &fakeStore{mappings: []model.NodePowerMapping{{NodeID: "node-1", BMCID: "bmc-1"}}}
+	reader := &fakeReader{
+		active:   make(map[string]int),
+		readings: map[string]engine.TelemetryReading{"node-1": {PowerWatts: watts(100)}},
+	}
+
+	var (
+		mu     sync.Mutex
+		leader bool
+	)
+	sampler := New(st, reader, Config{Interval: 5 * time.Millisecond, Retention: time.Hour}, zerolog.Nop(),
+		WithLeaderCheck(func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return leader
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sampler.Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	st.mu.Lock()
+	assert.Empty(t, st.samples)
+	st.mu.Unlock()
+
+	mu.Lock()
+	leader = true
+	mu.Unlock()
+	require.Eventually(t, func() bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		return len(st.samples) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	// Pruning runs at most once per hour.
+	require.Len(t, st.pruned, 1)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), st.pruned[0], time.Second)
+}
+
+func TestSampler_NoMappings(t *testing.T) {
+	reader := &fakeReader{active: make(map[string]int)}
+	stored, err := New(&fakeStore{}, reader, Config{}, zerolog.Nop()).SampleOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, stored)
+}
@@ -0,0 +1,606 @@
+// Package webhook delivers transition events from the outbox to subscribed
+// HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+// Headers set on every delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret, prefixed with "sha256=".
+const (
+	HeaderEventID    = "X-Chamicore-Event-Id"
+	HeaderEventType  = "X-Chamicore-Event-Type"
+	HeaderDeliveryID = "X-Chamicore-Delivery-Id"
+	HeaderTimestamp  = "X-Chamicore-Timestamp"
+	HeaderSignature  = "X-Chamicore-Signature"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 100
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 10
+	defaultBackoffBase  = 10 * time.Second
+	defaultBackoffMax   = time.Hour
+	defaultRetention    = 7 * 24 * time.Hour
+	defaultConcurrency  = 8
+	pruneInterval       = time.Hour
+	maxErrorBodyBytes   = 512
+)
+
+// Store defines the persistence methods used by the dispatcher.
+type Store interface {
+	ListEnabledWebhooks(ctx context.Context) ([]model.Webhook, error)
+	ListUndispatchedOutboxEvents(ctx context.Context, limit int) ([]model.OutboxEvent, error)
+	RecordWebhookDispatch(ctx context.Context, outboxIDs []string, deliveries []model.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.ClaimedWebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, result model.WebhookDeliveryResult) error
+	PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int64, error)
+	ListTransitionTasks(ctx context.Context, transitionID string) ([]engine.Task, error)
+}
+
+// Config contains dispatcher settings.
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Retention    time.Duration
+	Concurrency  int
+}
+
+// Option configures optional dispatcher behavior.
+type Option func(*Dispatcher)
+
+// WithGroupMemberResolver resolves the groups webhooks filter on. Without a
+// resolver, webhooks that filter on groups receive no events.
+func WithGroupMemberResolver(fn func(ctx context.Context, group string) ([]string, error)) Option {
+	return func(d *Dispatcher) {
+		d.resolveGroupMembers = fn
+	}
+}
+
+// WithHTTPClient replaces the client deliveries are sent with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// Dispatcher fans new outbox events out to matching webhooks and delivers
+// them, retrying failures with exponential backoff until they are
+// dead-lettered. Replicas may run dispatchers concurrently: fan-out is
+// idempotent per webhook and event, and deliveries are leased.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	log    zerolog.Logger
+
+	pollInterval        time.Duration
+	batchSize           int
+	timeout             time.Duration
+	maxAttempts         int
+	backoffBase         time.Duration
+	backoffMax          time.Duration
+	retention           time.Duration
+	concurrency         int
+	resolveGroupMembers func(ctx context.Context, group string) ([]string, error)
+	now                 func() time.Time
+}
+
+// New creates a webhook dispatcher.
+func New(st Store, cfg Config, logger zerolog.Logger, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:        st,
+		log:          logger,
+		pollInterval: positiveDuration(cfg.PollInterval, defaultPollInterval),
+		batchSize:    positiveInt(cfg.BatchSize, defaultBatchSize),
+		timeout:      positiveDuration(cfg.Timeout, defaultTimeout),
+		maxAttempts:  positiveInt(cfg.MaxAttempts, defaultMaxAttempts),
+		backoffBase:  positiveDuration(cfg.BackoffBase, defaultBackoffBase),
+		backoffMax:   positiveDuration(cfg.BackoffMax, defaultBackoffMax),
+		retention:    positiveDuration(cfg.Retention, defaultRetention),
+		concurrency:  positiveInt(cfg.Concurrency, defaultConcurrency),
+		now:          time.Now,
+	}
+	if d.backoffMax < d.backoffBase {
+		d.backoffMax = d.backoffBase
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.client == nil {
+		d.client = &http.Client{
+			// A redirect is reported as a failed delivery instead of being
+			// followed, since following would turn the POST into a GET.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return d
+}
+
+// Run dispatches and delivers events on every poll interval and prunes the
+// delivery log until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := d.DispatchOnce(ctx); err != nil && ctx.Err() == nil {
+			d.log.Error().Err(err).Msg("webhook dispatch failed")
+		}
+		if _, err := d.DeliverOnce(ctx); err != nil && ctx.Err() == nil {
+			d.log.Error().Err(err).Msg("webhook delivery failed")
+		}
+
+		if now := d.now(); now.Sub(lastPrune) >= pruneInterval {
+			deleted, err := d.store.PruneWebhookDeliveries(ctx, now.Add(-d.retention))
+			if err != nil {
+				if ctx.Err() == nil {
+					d.log.Error().Err(err).Msg("pruning webhook deliveries failed")
+				}
+				continue
+			}
+			lastPrune = now
+			if deleted > 0 {
+				d.log.Debug().Int64("deleted", deleted).Msg("pruned webhook deliveries")
+			}
+		}
+	}
+}
+
+// DispatchOnce queues deliveries for the next batch of outbox events and
+// returns how many deliveries were queued. Events are marked dispatched even
+// when no webhook matches them. An event whose match against some webhook
+// cannot be decided yet, because a group did not resolve, still queues its
+// other deliveries but stays undispatched; the next pass retries it, and the
+// deliveries already queued are skipped as duplicates.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	events, err := d.store.ListUndispatchedOutboxEvents(ctx, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing outbox events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	hooks, err := d.store.ListEnabledWebhooks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing webhooks: %w", err)
+	}
+
+	router := &eventRouter{
+		dispatcher:       d,
+		groupMembers:     make(map[string]map[string]struct{}),
+		groupErrs:        make(map[string]error),
+		transitionNodes:  make(map[string][]string),
+		resolvedNodesFor: make(map[string][]string),
+	}
+	outboxIDs := make([]string, 0, len(events))
+	deliveries := make([]model.WebhookDelivery, 0)
+	for _, event := range events {
+		if len(hooks) == 0 {
+			outboxIDs = append(outboxIDs, event.ID)
+			continue
+		}
+
+		envelope, err := newEnvelope(event)
+		if err == nil {
+			envelope.Data, err = redactEventData(envelope.Type, envelope.Data)
+		}
+		if err != nil {
+			d.log.Warn().Err(err).Str("outbox_id", event.ID).Msg("skipping undecodable outbox event")
+			outboxIDs = append(outboxIDs, event.ID)
+			continue
+		}
+		payload, err := json.Marshal(envelope)
+		if err != nil {
+			return 0, fmt.Errorf("marshaling webhook payload for event %q: %w", envelope.ID, err)
+		}
+		unresolved := false
+		for _, hook := range hooks {
+			matched, matchErr := router.matches(ctx, hook, envelope)
+			if matchErr != nil {
+				d.log.Warn().Err(matchErr).
+					Str("webhook_id", hook.ID).
+					Str("event_id", envelope.ID).
+					Msg("deferring webhook event until its filter resolves")
+				unresolved = true
+				continue
+			}
+			if !matched {
+				continue
+			}
+			deliveries = append(deliveries, model.WebhookDelivery{
+				WebhookID: hook.ID,
+				EventID:   envelope.ID,
+				EventType: envelope.Type,
+				Subject:   envelope.Subject,
+				Payload:   payload,
+			})
+		}
+		if !unresolved {
+			outboxIDs = append(outboxIDs, event.ID)
+		}
+	}
+
+	if err := d.store.RecordWebhookDispatch(ctx, outboxIDs, deliveries); err != nil {
+		return 0, fmt.Errorf("recording webhook dispatch: %w", err)
+	}
+	return len(deliveries), nil
+}
+
+// DeliverOnce attempts the next batch of due deliveries and returns how many
+// succeeded.
+func (d *Dispatcher) DeliverOnce(ctx context.Context) (int, error) {
+	// The lease outlasts one attempt so a slow endpoint is not retried by
+	// another replica while its request is still in flight.
+	claimed, err := d.store.ClaimWebhookDeliveries(ctx, d.batchSize, 2*d.timeout)
+	if err != nil {
+		return 0, fmt.Errorf("claiming webhook deliveries: %w", err)
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		delivered int
+		sem       = make(chan struct{}, d.concurrency)
+	)
+	for _, item := range claimed {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return delivered, ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := d.attempt(ctx, item)
+			if err := d.store.CompleteWebhookDelivery(ctx, result); err != nil {
+				d.log.Error().Err(err).Str("delivery_id", item.Delivery.ID).Msg("recording webhook delivery failed")
+				return
+			}
+			switch result.State {
+			case model.WebhookDeliveryDelivered:
+				mu.Lock()
+				delivered++
+				mu.Unlock()
+			case model.WebhookDeliveryDead:
+				d.log.Warn().
+					Str("webhook_id", item.Webhook.ID).
+					Str("delivery_id", item.Delivery.ID).
+					Int("attempts", item.Delivery.AttemptCount).
+					Str("error", result.Error).
+					Msg("webhook delivery dead-lettered")
+			}
+		}()
+	}
+	wg.Wait()
+	return delivered, nil
+}
+
+// attempt sends one delivery and decides its next state. The claim already
+// counted this attempt.
+func (d *Dispatcher) attempt(ctx context.Context, item model.ClaimedWebhookDelivery) model.WebhookDeliveryResult {
+	now := d.now()
+	result := model.WebhookDeliveryResult{
+		DeliveryID:  item.Delivery.ID,
+		AttemptedAt: now,
+	}
+
+	statusCode, err := d.send(ctx, item, now)
+	result.StatusCode = statusCode
+	if err == nil {
+		result.State = model.WebhookDeliveryDelivered
+		return result
+	}
+
+	result.Error = err.Error()
+	if item.Delivery.AttemptCount >= d.maxAttempts {
+		result.State = model.WebhookDeliveryDead
+		return result
+	}
+	result.State = model.WebhookDeliveryPending
+	result.NextAttemptAt = now.Add(d.backoff(item.Delivery.AttemptCount))
+	return result
+}
+
+func (d *Dispatcher) send(ctx context.Context, item model.ClaimedWebhookDelivery, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	body := []byte(item.Delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, item.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("building request: %w", err)
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "chamicore-power-webhook")
+	req.Header.Set(HeaderEventID, item.Delivery.EventID)
+	req.Header.Set(HeaderEventType, item.Delivery.EventType)
+	req.Header.Set(HeaderDeliveryID, item.Delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(item.Webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if msg := strings.TrimSpace(string(snippet)); msg != "" {
+			return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, msg)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after the given failed attempt: base doubled per
+// earlier attempt, capped at the configured maximum.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := d.backoffBase
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= d.backoffMax || wait <= 0 {
+			return d.backoffMax
+		}
+	}
+	return min(wait, d.backoffMax)
+}
+
+// Sign returns the signature header value for a delivery body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// envelope is the CloudEvents-style body POSTed to webhooks.
+type envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// newEnvelope rebuilds the published event from an outbox row. Rows hold the
+// serialized event; rows that hold only the payload are wrapped using the
+// row's own columns.
+func newEnvelope(event model.OutboxEvent) (envelope, error) {
+	var out envelope
+	if err := json.Unmarshal(event.Data, &out); err != nil {
+		return envelope{}, fmt.Errorf("decoding outbox event: %w", err)
+	}
+	if strings.TrimSpace(out.Type) == "" || len(out.Data) == 0 {
+		out = envelope{Data: event.Data}
+	}
+
+	out.SpecVersion = "1.0"
+	if strings.TrimSpace(out.ID) == "" {
+		out.ID = event.ID
+	}
+	if strings.TrimSpace(out.Source) == "" {
+		out.Source = types.EventSource
+	}
+	if strings.TrimSpace(out.Type) == "" {
+		out.Type = event.EventType
+	}
+	if strings.TrimSpace(out.Subject) == "" {
+		out.Subject = event.Subject
+	}
+	if out.Time.IsZero() {
+		out.Time = event.CreatedAt.UTC()
+	}
+	if strings.TrimSpace(out.DataContentType) == "" {
+		out.DataContentType = "application/json"
+	}
+	return out, nil
+}
+
+// internalTaskFields are task snapshot fields that describe how the service
+// reaches a BMC. They stay in the outbox for internal consumers but are not
+// sent to webhooks.
+var internalTaskFields = []string{"bmcEndpoint", "credentialId", "insecureSkipVerify"}
+
+// redactEventData removes internalTaskFields from the snapshot of a task
+// result event. Other event types are returned unchanged.
+func redactEventData(eventType string, data json.RawMessage) (json.RawMessage, error) {
+	if eventType != types.EventTypeTransitionTaskResult {
+		return data, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decoding task result event: %w", err)
+	}
+	raw, ok := fields["snapshot"]
+	if !ok {
+		return data, nil
+	}
+	var snapshot map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, fmt.Errorf("decoding task result snapshot: %w", err)
+	}
+	for _, field := range internalTaskFields {
+		delete(snapshot, field)
+	}
+	redacted, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("encoding task result snapshot: %w", err)
+	}
+	fields["snapshot"] = redacted
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encoding task result event: %w", err)
+	}
+	return out, nil
+}
+
+// eventRouter matches events against webhook filters, caching group members
+// and transition targets for one dispatch pass.
+type eventRouter struct {
+	dispatcher       *Dispatcher
+	groupMembers     map[string]map[string]struct{}
+	groupErrs        map[string]error
+	transitionNodes  map[string][]string
+	resolvedNodesFor map[string][]string
+}
+
+func (r *eventRouter) matches(ctx context.Context, hook model.Webhook, event envelope) (bool, error) {
+	if len(hook.EventTypes) > 0 && !slices.Contains(hook.EventTypes, event.Type) {
+		return false, nil
+	}
+	if len(hook.Groups) == 0 {
+		return true, nil
+	}
+
+	nodes, err := r.eventNodes(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range hook.Groups {
+		members, err := r.members(ctx, group)
+		if err != nil {
+			return false, err
+		}
+		for _, node := range nodes {
+			if _, ok := members[node]; ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// eventNodes returns the nodes an event concerns: the task node for task
+// results, and every target of the transition otherwise.
+func (r *eventRouter) eventNodes(ctx context.Context, event envelope) ([]string, error) {
+	if nodes, ok := r.resolvedNodesFor[event.ID]; ok {
+		return nodes, nil
+	}
+
+	var nodes []string
+	switch event.Type {
+	case types.EventTypeTransitionTaskResult:
+		var data types.TransitionTaskResultEvent
+		if err := json.Unmarshal(event.Data, &data); err == nil && data.NodeID != "" {
+			nodes = []string{data.NodeID}
+		} else if event.Subject != "" {
+			nodes = []string{event.Subject}
+		}
+	case types.EventTypeTransitionLifecycle, types.EventTypeTransitionApproval:
+		var data struct {
+			TransitionID string `json:"transitionId"`
+		}
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.TransitionID == "" {
+			data.TransitionID = event.Subject
+		}
+		resolved, err := r.transitionTargets(ctx, data.TransitionID)
+		if err != nil {
+			return nil, err
+		}
+		nodes = resolved
+	}
+	r.resolvedNodesFor[event.ID] = nodes
+	return nodes, nil
+}
+
+func (r *eventRouter) transitionTargets(ctx context.Context, transitionID string) ([]string, error) {
+	transitionID = strings.TrimSpace(transitionID)
+	if transitionID == "" {
+		return nil, nil
+	}
+	if nodes, ok := r.transitionNodes[transitionID]; ok {
+		return nodes, nil
+	}
+	tasks, err := r.dispatcher.store.ListTransitionTasks(ctx, transitionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks of transition %q: %w", transitionID, err)
+	}
+	nodes := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		nodes = append(nodes, task.NodeID)
+	}
+	r.transitionNodes[transitionID] = nodes
+	return nodes, nil
+}
+
+func (r *eventRouter) members(ctx context.Context, group string) (map[string]struct{}, error) {
+	if members, ok := r.groupMembers[group]; ok {
+		return members, nil
+	}
+	if err, ok := r.groupErrs[group]; ok {
+		return nil, err
+	}
+	members := make(map[string]struct{})
+	if r.dispatcher.resolveGroupMembers != nil {
+		nodeIDs, err := r.dispatcher.resolveGroupMembers(ctx, group)
+		if err != nil {
+			// A failed group is not retried within the pass.
+			err = fmt.Errorf("resolving group %q: %w", group, err)
+			r.groupErrs[group] = err
+			return nil, err
+		}
+		for _, nodeID := range nodeIDs {
+			members[nodeID] = struct{}{}
+		}
+	}
+	r.groupMembers[group] = members
+	return members, nil
+}
+
+func positiveDuration(value, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func positiveInt(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
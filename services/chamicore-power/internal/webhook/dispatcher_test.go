@@ -0,0 +1,299 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.cscs.ch/openchami/chamicore-power/internal/engine"
+	"git.cscs.ch/openchami/chamicore-power/internal/model"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+type fakeStore struct {
+	mu         sync.Mutex
+	hooks      []model.Webhook
+	events     []model.OutboxEvent
+	tasks      map[string][]engine.Task
+	dispatched []string
+	deliveries []model.WebhookDelivery
+	claimed    []model.ClaimedWebhookDelivery
+	results    []model.WebhookDeliveryResult
+}
+
+func (f *fakeStore) ListEnabledWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	return f.hooks, nil
+}
+
+func (f *fakeStore) ListUndispatchedOutboxEvents(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	return f.events, nil
+}
+
+func (f *fakeStore) RecordWebhookDispatch(ctx context.Context, outboxIDs []string, deliveries []model.WebhookDelivery) error {
+	f.dispatched = append(f.dispatched, outboxIDs...)
+	f.deliveries = append(f.deliveries, deliveries...)
+	return nil
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.ClaimedWebhookDelivery, error) {
+	return f.claimed, nil
+}
+
+func (f *fakeStore) CompleteWebhookDelivery(ctx context.Context, result model.WebhookDeliveryResult) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = append(f.results, result)
+	return nil
+}
+
+func (f *fakeStore) PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeStore) ListTransitionTasks(ctx context.Context, transitionID string) ([]engine.Task, error) {
+	return f.tasks[transitionID], nil
+}
+
+func outboxEvent(t *testing.T, id, eventType, subject string, data any) model.OutboxEvent {
+	t.Helper()
+
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	raw, err := json.Marshal(map[string]any{
+		"id":      "evt-" + id,
+		"source":  types.EventSource,
+		"type":    eventType,
+		"subject": subject,
+		"time":    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"data":    json.RawMessage(payload),
+	})
+	require.NoError(t, err)
+	return model.OutboxEvent{ID: id, EventType: eventType, Subject: subject, Data: raw}
+}
+
+func TestDispatchOnce_FiltersByEventTypeAndGroup(t *testing.T) {
+	st := &fakeStore{
+		hooks: []model.Webhook{
+			{ID: "all"},
+			{ID: "lifecycle", EventTypes: []string{types.EventTypeTransitionLifecycle}},
+			{ID: "compute", Groups: []string{"compute"}},
+		},
+		events: []model.OutboxEvent{
+			outboxEvent(t, "1", types.EventTypeTransitionLifecycle, "tr-1", types.TransitionLifecycleEvent{TransitionID: "tr-1"}),
+			outboxEvent(t, "2", types.EventTypeTransitionTaskResult, "node-2", types.TransitionTaskResultEvent{TransitionID: "tr-1", NodeID: "node-2"}),
+			outboxEvent(t, "3", types.EventTypeTransitionTaskResult, "node-1", types.TransitionTaskResultEvent{TransitionID: "tr-1", NodeID: "node-1"}),
+		},
+		tasks: map[string][]engine.Task{
+			"tr-1": {{NodeID: "node-1"}, {NodeID: "node-2"}},
+		},
+	}
+	resolved := 0
+	d := New(st, Config{}, zerolog.Nop(), WithGroupMemberResolver(func(ctx context.Context, group string) ([]string, error) {
+		resolved++
+		return []string{"node-1"}, nil
+	}))
+
+	queued, err := d.DispatchOnce(context.Background())
+	require.NoError(t, err)
+
+	got := map[string][]string{}
+	for _, delivery := range st.deliveries {
+		got[delivery.WebhookID] = append(got[delivery.WebhookID], delivery.EventID)
+	}
+	assert.Equal(t, 6, queued)
+	assert.Equal(t, []string{"evt-1", "evt-2", "evt-3"}, got["all"])
+	assert.Equal(t, []string{"evt-1"}, got["lifecycle"])
+	assert.Equal(t, []string{"evt-1", "evt-3"}, got["compute"], "lifecycle events match on any transition target")
+	assert.Equal(t, []string{"1", "2", "3"}, st.dispatched)
+	assert.Equal(t, 1, resolved, "group members are resolved once per pass")
+
+	var body envelope
+	require.NoError(t, json.Unmarshal(st.deliveries[0].Payload, &body))
+	assert.Equal(t, "1.0", body.SpecVersion)
+	assert.Equal(t, "evt-1", body.ID)
+	assert.Equal(t, "tr-1", body.Subject)
+	var data types.TransitionLifecycleEvent
+	require.NoError(t, json.Unmarshal(body.Data, &data))
+	assert.Equal(t, "tr-1", data.TransitionID)
+}
+
+func TestDispatchOnce_GroupResolutionErrorDefersOnlyUnresolvedEvents(t *testing.T) {
+	st := &fakeStore{
+		hooks: []model.Webhook{
+			{ID: "all"},
+			{ID: "compute", Groups: []string{"compute"}},
+			{ID: "gpu", Groups: []string{"gpu"}},
+		},
+		events: []model.OutboxEvent{
+			outboxEvent(t, "1", types.EventTypeTransitionTaskResult, "node-1", types.TransitionTaskResultEvent{NodeID: "node-1"}),
+			outboxEvent(t, "2", types.EventTypeTransitionTaskResult, "node-2", types.TransitionTaskResultEvent{NodeID: "node-2"}),
+		},
+	}
+	calls := map[string]int{}
+	d := New(st, Config{}, zerolog.Nop(), WithGroupMemberResolver(func(ctx context.Context, group string) ([]string, error) {
+		calls[group]++
+		if group == "gpu" {
+			return nil, errors.New("smd unavailable")
+		}
+		return []string{"node-1"}, nil
+	}))
+
+	queued, err := d.DispatchOnce(context.Background())
+	require.NoError(t, err)
+
+	got := map[string][]string{}
+	for _, delivery := range st.deliveries {
+		got[delivery.WebhookID] = append(got[delivery.WebhookID], delivery.EventID)
+	}
+	assert.Equal(t, 3, queued)
+	assert.Equal(t, []string{"evt-1", "evt-2"}, got["all"])
+	assert.Equal(t, []string{"evt-1"}, got["compute"])
+	assert.Empty(t, got["gpu"])
+	assert.Empty(t, st.dispatched, "events with an unresolved webhook filter are retried")
+	assert.Equal(t, 1, calls["gpu"], "a failed group is not retried within the pass")
+}
+
+func TestDispatchOnce_StripsBMCAccessFromTaskSnapshots(t *testing.T) {
+	st := &fakeStore{
+		hooks: []model.Webhook{{ID: "all"}},
+		events: []model.OutboxEvent{outboxEvent(t, "1", types.EventTypeTransitionTaskResult, "node-1", types.TransitionTaskResultEvent{
+			TransitionID: "tr-1",
+			NodeID:       "node-1",
+			Snapshot: types.TransitionTaskEventSnapshot{
+				ID:                 "task-1",
+				NodeID:             "node-1",
+				BMCID:              "bmc-1",
+				BMCEndpoint:        "https://10.0.0.1",
+				CredentialID:       "cred-1",
+				InsecureSkipVerify: true,
+			},
+		})},
+	}
+	d := New(st, Config{}, zerolog.Nop())
+
+	_, err := d.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, st.deliveries, 1)
+
+	var body struct {
+		Data struct {
+			NodeID   string         `json:"nodeId"`
+			Snapshot map[string]any `json:"snapshot"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(st.deliveries[0].Payload, &body))
+	assert.Equal(t, "node-1", body.Data.NodeID)
+	assert.Equal(t, "bmc-1", body.Data.Snapshot["bmcId"])
+	assert.NotContains(t, body.Data.Snapshot, "bmcEndpoint")
+	assert.NotContains(t, body.Data.Snapshot, "credentialId")
+	assert.NotContains(t, body.Data.Snapshot, "insecureSkipVerify")
+}
+
+func TestDispatchOnce_WrapsPayloadOnlyRows(t *testing.T) {
+	st := &fakeStore{
+		hooks: []model.Webhook{{ID: "all"}},
+		events: []model.OutboxEvent{{
+			ID:        "row-1",
+			EventType: types.EventTypeTransitionApproval,
+			Subject:   "tr-1",
+			Data:      json.RawMessage(`{"transitionId":"tr-1","action":"approved"}`),
+		}},
+	}
+	d := New(st, Config{}, zerolog.Nop())
+
+	_, err := d.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, st.deliveries, 1)
+
+	var body envelope
+	require.NoError(t, json.Unmarshal(st.deliveries[0].Payload, &body))
+	assert.Equal(t, "row-1", body.ID)
+	assert.Equal(t, types.EventTypeTransitionApproval, body.Type)
+	assert.Equal(t, types.EventSource, body.Source)
+	assert.JSONEq(t, `{"transitionId":"tr-1","action":"approved"}`, string(body.Data))
+}
+
+func TestDeliverOnce_SignsAndRecordsOutcome(t *testing.T) {
+	var gotHeaders http.Header
+	var gotBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	payload := json.RawMessage(`{"id":"evt-1"}`)
+	st := &fakeStore{claimed: []model.ClaimedWebhookDelivery{{
+		Delivery: model.WebhookDelivery{ID: "del-1", EventID: "evt-1", EventType: types.EventTypeTransitionLifecycle, Payload: payload, AttemptCount: 1},
+		Webhook:  model.Webhook{ID: "hook-1", URL: receiver.URL, Secret: "0123456789abcdef"},
+	}}}
+	d := New(st, Config{}, zerolog.Nop())
+
+	delivered, err := d.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	require.Len(t, st.results, 1)
+	assert.Equal(t, model.WebhookDeliveryDelivered, st.results[0].State)
+	assert.Equal(t, http.StatusNoContent, st.results[0].StatusCode)
+
+	assert.JSONEq(t, string(payload), string(gotBody))
+	assert.Equal(t, "evt-1", gotHeaders.Get(HeaderEventID))
+	assert.Equal(t, "del-1", gotHeaders.Get(HeaderDeliveryID))
+	timestamp, err := strconv.ParseInt(gotHeaders.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign("0123456789abcdef", timestamp, gotBody), gotHeaders.Get(HeaderSignature))
+}
+
+func TestDeliverOnce_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	claim := func(attempt int) model.ClaimedWebhookDelivery {
+		return model.ClaimedWebhookDelivery{
+			Delivery: model.WebhookDelivery{ID: "del-" + strconv.Itoa(attempt), AttemptCount: attempt, Payload: json.RawMessage(`{}`)},
+			Webhook:  model.Webhook{URL: receiver.URL, Secret: "0123456789abcdef"},
+		}
+	}
+	st := &fakeStore{claimed: []model.ClaimedWebhookDelivery{claim(2)}}
+	d := New(st, Config{MaxAttempts: 3, BackoffBase: time.Second, BackoffMax: time.Minute}, zerolog.Nop())
+	d.now = func() time.Time { return now }
+
+	_, err := d.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, st.results, 1)
+	assert.Equal(t, model.WebhookDeliveryPending, st.results[0].State)
+	assert.Equal(t, http.StatusServiceUnavailable, st.results[0].StatusCode)
+	assert.Equal(t, "unexpected status 503: busy", st.results[0].Error)
+	assert.Equal(t, now.Add(2*time.Second), st.results[0].NextAttemptAt)
+
+	st.claimed = []model.ClaimedWebhookDelivery{claim(3)}
+	_, err = d.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, st.results, 2)
+	assert.Equal(t, model.WebhookDeliveryDead, st.results[1].State)
+}
+
+func TestBackoff_DoublesUpToMax(t *testing.T) {
+	d := New(&fakeStore{}, Config{BackoffBase: 10 * time.Second, BackoffMax: time.Minute}, zerolog.Nop())
+
+	assert.Equal(t, 10*time.Second, d.backoff(1))
+	assert.Equal(t, 20*time.Second, d.backoff(2))
+	assert.Equal(t, 40*time.Second, d.backoff(3))
+	assert.Equal(t, time.Minute, d.backoff(4))
+	assert.Equal(t, time.Minute, d.backoff(100))
+}
@@ -0,0 +1,3 @@
+SET search_path TO power;
+
+DROP TABLE IF EXISTS power.mapping_sync_state;
@@ -0,0 +1,19 @@
+SET search_path TO power;
+
+CREATE TABLE IF NOT EXISTS power.mapping_sync_state (
+    id                  SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    leader_id           TEXT NOT NULL DEFAULT '',
+    leader_heartbeat_at TIMESTAMPTZ,
+    requested_seq       BIGINT NOT NULL DEFAULT 0,
+    requested_at        TIMESTAMPTZ,
+    completed_seq       BIGINT NOT NULL DEFAULT 0,
+    last_attempt_at     TIMESTAMPTZ,
+    last_sync_at        TIMESTAMPTZ,
+    last_error          TEXT NOT NULL DEFAULT '',
+    last_counts         JSONB NOT NULL DEFAULT '{}'::jsonb,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO power.mapping_sync_state (id)
+VALUES (1)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,4 @@
+SET search_path TO power;
+
+ALTER TABLE power.mapping_sync_state
+    ADD COLUMN IF NOT EXISTS last_report JSONB;
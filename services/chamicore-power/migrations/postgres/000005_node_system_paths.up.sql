@@ -0,0 +1,9 @@
+SET search_path TO power;
+
+CREATE TABLE IF NOT EXISTS power.node_system_paths (
+    node_id     TEXT PRIMARY KEY,
+    system_path TEXT NOT NULL,
+    source      TEXT NOT NULL DEFAULT 'discovery',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
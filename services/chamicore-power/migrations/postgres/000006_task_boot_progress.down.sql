@@ -0,0 +1,7 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    DROP COLUMN IF EXISTS boot_stages,
+    DROP COLUMN IF EXISTS boot_stage,
+    DROP COLUMN IF EXISTS boot_wait_ready,
+    DROP COLUMN IF EXISTS boot_target;
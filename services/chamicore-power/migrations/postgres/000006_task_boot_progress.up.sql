@@ -0,0 +1,7 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    ADD COLUMN IF NOT EXISTS boot_target TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS boot_wait_ready BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS boot_stage TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS boot_stages JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,4 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    ADD COLUMN IF NOT EXISTS power_cap_watts INTEGER;
@@ -0,0 +1,7 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    DROP COLUMN IF EXISTS reset_operation,
+    DROP COLUMN IF EXISTS media_image,
+    DROP COLUMN IF EXISTS boot_override_mode,
+    DROP COLUMN IF EXISTS boot_override_target;
@@ -0,0 +1,7 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    ADD COLUMN IF NOT EXISTS boot_override_target TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS boot_override_mode TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS media_image TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS reset_operation TEXT NOT NULL DEFAULT '';
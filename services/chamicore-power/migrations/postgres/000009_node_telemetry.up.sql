@@ -0,0 +1,16 @@
+SET search_path TO power;
+
+CREATE TABLE IF NOT EXISTS power.node_telemetry (
+    node_id         TEXT NOT NULL,
+    bucket_start    TIMESTAMPTZ NOT NULL,
+    power_samples   INTEGER NOT NULL DEFAULT 0,
+    power_sum_watts DOUBLE PRECISION NOT NULL DEFAULT 0,
+    power_min_watts DOUBLE PRECISION,
+    power_max_watts DOUBLE PRECISION,
+    temp_samples    INTEGER NOT NULL DEFAULT 0,
+    inlet_temp_sum  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    inlet_temp_max  DOUBLE PRECISION,
+    PRIMARY KEY (node_id, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_telemetry_bucket_start ON power.node_telemetry (bucket_start);
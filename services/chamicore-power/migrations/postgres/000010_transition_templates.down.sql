@@ -0,0 +1,14 @@
+SET search_path TO power;
+
+ALTER TABLE power.transition_tasks
+    DROP COLUMN IF EXISTS escalated;
+
+DROP INDEX IF EXISTS power.idx_transitions_template_name;
+
+ALTER TABLE power.transitions
+    DROP COLUMN IF EXISTS template_version,
+    DROP COLUMN IF EXISTS template_name,
+    DROP COLUMN IF EXISTS escalate_after_seconds,
+    DROP COLUMN IF EXISTS batch_size;
+
+DROP TABLE IF EXISTS power.transition_templates;
@@ -0,0 +1,22 @@
+SET search_path TO power;
+
+CREATE TABLE IF NOT EXISTS power.transition_templates (
+    name        TEXT NOT NULL,
+    version     INTEGER NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    spec        JSONB NOT NULL,
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, version)
+);
+
+ALTER TABLE power.transitions
+    ADD COLUMN IF NOT EXISTS batch_size INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS escalate_after_seconds INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS template_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS template_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transitions_template_name ON power.transitions (template_name) WHERE template_name <> '';
+
+ALTER TABLE power.transition_tasks
+    ADD COLUMN IF NOT EXISTS escalated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+SET search_path TO power;
+
+DROP TABLE IF EXISTS power.transition_approvals;
+
+DROP INDEX IF EXISTS power.idx_transitions_pending_approval;
+
+ALTER TABLE power.transitions
+    DROP COLUMN IF EXISTS approval_expires_at,
+    DROP COLUMN IF EXISTS approval_reason;
@@ -0,0 +1,18 @@
+SET search_path TO power;
+
+ALTER TABLE power.transitions
+    ADD COLUMN IF NOT EXISTS approval_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS approval_expires_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transitions_pending_approval ON power.transitions (approval_expires_at) WHERE state = 'pending-approval';
+
+CREATE TABLE IF NOT EXISTS power.transition_approvals (
+    id            BIGSERIAL PRIMARY KEY,
+    transition_id TEXT NOT NULL REFERENCES power.transitions(id) ON DELETE CASCADE,
+    action        TEXT NOT NULL,
+    principal     TEXT NOT NULL DEFAULT '',
+    reason        TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transition_approvals_transition_id ON power.transition_approvals (transition_id, id);
@@ -0,0 +1,6 @@
+SET search_path TO power;
+
+DROP INDEX IF EXISTS power.idx_transitions_parent_id;
+
+ALTER TABLE power.transitions
+    DROP COLUMN IF EXISTS parent_id;
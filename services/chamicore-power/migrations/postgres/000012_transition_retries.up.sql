@@ -0,0 +1,6 @@
+SET search_path TO power;
+
+ALTER TABLE power.transitions
+    ADD COLUMN IF NOT EXISTS parent_id TEXT NULL REFERENCES power.transitions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transitions_parent_id ON power.transitions (parent_id) WHERE parent_id IS NOT NULL;
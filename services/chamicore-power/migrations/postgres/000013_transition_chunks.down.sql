@@ -0,0 +1,6 @@
+SET search_path TO power;
+
+DROP INDEX IF EXISTS power.idx_transition_tasks_transition_node;
+
+ALTER TABLE power.transitions
+    DROP COLUMN IF EXISTS chunk_size;
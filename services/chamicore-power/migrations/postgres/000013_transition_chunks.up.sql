@@ -0,0 +1,6 @@
+SET search_path TO power;
+
+ALTER TABLE power.transitions
+    ADD COLUMN IF NOT EXISTS chunk_size INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transition_tasks_transition_node ON power.transition_tasks (transition_id, node_id);
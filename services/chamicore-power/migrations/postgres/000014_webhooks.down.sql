@@ -0,0 +1,9 @@
+SET search_path TO power;
+
+DROP INDEX IF EXISTS power.idx_outbox_webhook_undispatched;
+
+ALTER TABLE power.outbox
+    DROP COLUMN IF EXISTS webhook_dispatched_at;
+
+DROP TABLE IF EXISTS power.webhook_deliveries;
+DROP TABLE IF EXISTS power.webhooks;
@@ -0,0 +1,45 @@
+SET search_path TO power;
+
+CREATE TABLE IF NOT EXISTS power.webhooks (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    groups      JSONB NOT NULL DEFAULT '[]',
+    enabled     BOOLEAN NOT NULL DEFAULT true,
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS power.webhook_deliveries (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id       UUID NOT NULL REFERENCES power.webhooks(id) ON DELETE CASCADE,
+    event_id         TEXT NOT NULL,
+    event_type       TEXT NOT NULL,
+    subject          TEXT NOT NULL DEFAULT '',
+    payload          JSONB NOT NULL,
+    state            TEXT NOT NULL DEFAULT 'pending',
+    attempt_count    INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    delivered_at     TIMESTAMPTZ NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON power.webhook_deliveries (next_attempt_at) WHERE state = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON power.webhook_deliveries (webhook_id, created_at DESC);
+
+-- Events written before webhooks existed are not fanned out.
+ALTER TABLE power.outbox
+    ADD COLUMN IF NOT EXISTS webhook_dispatched_at TIMESTAMPTZ NULL;
+
+UPDATE power.outbox SET webhook_dispatched_at = created_at WHERE webhook_dispatched_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_webhook_undispatched
+    ON power.outbox (created_at)
+    WHERE webhook_dispatched_at IS NULL;
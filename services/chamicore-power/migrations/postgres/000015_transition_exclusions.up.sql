@@ -0,0 +1,4 @@
+SET search_path TO power;
+
+ALTER TABLE power.transitions
+    ADD COLUMN IF NOT EXISTS exclusions JSONB NULL;
@@ -0,0 +1,4 @@
+SET search_path TO power;
+
+ALTER TABLE power.transitions
+    DROP COLUMN IF EXISTS pcs_operation;
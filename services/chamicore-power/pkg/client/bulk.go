@@ -0,0 +1,368 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"git.cscs.ch/openchami/chamicore-lib/httputil"
+	"git.cscs.ch/openchami/chamicore-power/pkg/hostlist"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBaseDelay   = time.Second
+	defaultRetryMaxDelay    = time.Minute
+	// defaultBulkChunkSize matches the server's default bulk limit.
+	defaultBulkChunkSize   = 20
+	defaultBulkConcurrency = 4
+)
+
+// ErrBulkTargets is returned by RunBulkTransition for requests it cannot
+// split into chunks.
+var ErrBulkTargets = errors.New("bulk transitions need explicit nodes")
+
+// RetryPolicy controls how CreateTransitionWithRetry retries requests the
+// server rejected with 429 Too Many Requests or 503 Service Unavailable.
+type RetryPolicy struct {
+	// MaxAttempts bounds the attempts, including the first. Defaults to 5.
+	MaxAttempts int
+	// BaseDelay is the first backoff delay when the response has no
+	// Retry-After header; it doubles on every attempt. Defaults to 1s.
+	BaseDelay time.Duration
+	// MaxDelay caps each delay, including Retry-After. Defaults to 1m.
+	MaxDelay time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+	return p
+}
+
+// delay returns how long to wait before the attempt after attempt, preferring
+// the server's Retry-After.
+func (p RetryPolicy) delay(attempt int, header http.Header, now time.Time) time.Duration {
+	delay := p.BaseDelay << min(attempt-1, 16)
+	if retryAfter, ok := parseRetryAfter(header.Get("Retry-After"), now); ok {
+		delay = retryAfter
+	}
+	return min(max(delay, 0), p.MaxDelay)
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds >= 0
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// CreateTransitionWithRetry is CreateTransition retried on 429 and 503
+// responses, waiting as long as their Retry-After header asks.
+func (c *Client) CreateTransitionWithRetry(
+	ctx context.Context,
+	req types.CreateTransitionRequest,
+	policy RetryPolicy,
+) (*httputil.Resource[types.Transition], error) {
+	policy = policy.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doRaw(ctx, http.MethodPost, transitionPathPrefix, req, "application/json")
+		if err != nil {
+			return nil, fmt.Errorf("creating transition: %w", err)
+		}
+
+		if resp.StatusCode < http.StatusBadRequest {
+			var result httputil.Resource[types.Transition]
+			err := json.NewDecoder(resp.Body).Decode(&result)
+			_ = resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("creating transition: decoding response: %w", err)
+			}
+			return &result, nil
+		}
+
+		apiErr := readAPIError(resp)
+		_ = resp.Body.Close()
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		if !retryable || attempt >= policy.MaxAttempts {
+			return nil, fmt.Errorf("creating transition: %w", apiErr)
+		}
+
+		timer := time.NewTimer(policy.delay(attempt, resp.Header, time.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("creating transition: %w (last error: %v)", ctx.Err(), apiErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// SplitNodes splits nodes into consecutive chunks of at most size nodes.
+func SplitNodes(nodes []string, size int) [][]string {
+	if size <= 0 {
+		size = defaultBulkChunkSize
+	}
+	chunks := make([][]string, 0, (len(nodes)+size-1)/size)
+	for start := 0; start < len(nodes); start += size {
+		end := min(start+size, len(nodes))
+		chunks = append(chunks, nodes[start:end])
+	}
+	return chunks
+}
+
+// BulkOptions configures RunBulkTransition.
+type BulkOptions struct {
+	// ChunkSize is the number of nodes per transition. Set it to the
+	// server's BulkMaxNodes; it defaults to that setting's default, 20.
+	ChunkSize int
+	// Concurrency bounds the transitions in flight. Defaults to 4.
+	Concurrency int
+	// Retry applies to creating each transition.
+	Retry RetryPolicy
+	// PollInterval is how often running transitions are polled.
+	PollInterval time.Duration
+	// OnResult, when set, is called as each transition finishes. Calls are
+	// serialized but arrive in completion order, not chunk order.
+	OnResult func(BulkResult)
+}
+
+// BulkResult is the outcome of one chunk of a bulk transition.
+type BulkResult struct {
+	// Chunk is the index of the chunk in the split target list.
+	Chunk        int
+	Nodes        []string
+	TransitionID string
+	// State is the final transition state; empty when Err is set.
+	State string
+	// FailedNodes lists the chunk's nodes whose task did not succeed, or
+	// every node of the chunk when Err is set.
+	FailedNodes []string
+	// Err is set when the transition could not be created or awaited.
+	Err error
+}
+
+// BulkSummary aggregates the chunks of a bulk transition.
+type BulkSummary struct {
+	Operation  string
+	TotalNodes int
+	// State is completed when every chunk completed, failed when no node
+	// succeeded and partial otherwise.
+	State          string
+	SucceededNodes int
+	// FailedNodes lists every node that did not succeed, sorted.
+	FailedNodes []string
+	// Results holds one entry per chunk, in chunk order.
+	Results []BulkResult
+}
+
+// RunBulkTransition runs req across any number of nodes as several
+// transitions of at most opts.ChunkSize nodes each, waits for all of them
+// and aggregates the outcome. Nodes may use hostlist ranges; ExcludeNodes
+// is applied before splitting. Groups, selectors and group exclusions are
+// resolved by the server per request and cannot be split, so they are
+// rejected with ErrBulkTargets.
+//
+// The returned error reports invalid requests only; failed chunks are
+// reported in the summary.
+func (c *Client) RunBulkTransition(
+	ctx context.Context,
+	req types.CreateTransitionRequest,
+	opts BulkOptions,
+) (*BulkSummary, error) {
+	nodes, err := bulkTargetNodes(req)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	chunks := SplitNodes(nodes, opts.ChunkSize)
+	results := make([]BulkResult, len(chunks))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range min(concurrency, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := c.runBulkChunk(ctx, req, index, chunks[index], opts)
+				mu.Lock()
+				results[index] = result
+				if opts.OnResult != nil {
+					opts.OnResult(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for index := range chunks {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return summarizeBulk(req.Operation, len(nodes), results), nil
+}
+
+// bulkTargetNodes expands, deduplicates and filters the nodes of req.
+func bulkTargetNodes(req types.CreateTransitionRequest) ([]string, error) {
+	if len(req.Groups) > 0 || len(req.ExcludeGroups) > 0 || req.Selector != nil {
+		return nil, fmt.Errorf("%w: groups and selectors are not supported", ErrBulkTargets)
+	}
+
+	expand := func(entries []string) ([]string, error) {
+		expanded := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			hosts, err := hostlist.Expand(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrBulkTargets, err)
+			}
+			expanded = append(expanded, hosts...)
+		}
+		return expanded, nil
+	}
+	excluded, err := expand(req.ExcludeNodes)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := expand(req.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(candidates)+len(excluded))
+	for _, node := range excluded {
+		seen[node] = struct{}{}
+	}
+	nodes := make([]string, 0, len(candidates))
+	for _, node := range candidates {
+		if _, dup := seen[node]; dup {
+			continue
+		}
+		seen[node] = struct{}{}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: no target nodes", ErrBulkTargets)
+	}
+	return nodes, nil
+}
+
+func (c *Client) runBulkChunk(
+	ctx context.Context,
+	req types.CreateTransitionRequest,
+	index int,
+	nodes []string,
+	opts BulkOptions,
+) BulkResult {
+	result := BulkResult{Chunk: index, Nodes: nodes}
+
+	chunkReq := req
+	chunkReq.Nodes = nodes
+	chunkReq.ExcludeNodes = nil
+	if req.RequestID != "" {
+		chunkReq.RequestID = fmt.Sprintf("%s-%d", req.RequestID, index)
+	}
+
+	created, err := c.CreateTransitionWithRetry(ctx, chunkReq, opts.Retry)
+	if err != nil {
+		result.Err = err
+		result.FailedNodes = nodes
+		return result
+	}
+	result.TransitionID = created.Metadata.ID
+
+	done, err := c.WaitTransition(ctx, created.Metadata.ID, WaitTransitionOptions{Interval: opts.PollInterval})
+	if err != nil {
+		result.Err = err
+		result.FailedNodes = nodes
+		return result
+	}
+	tasks := done.Spec.Tasks
+	if page := done.Spec.TaskPage; page != nil && page.Total > len(tasks) {
+		// Chunks larger than one task page need the full task list.
+		exported, err := c.ExportTransitionTasks(ctx, created.Metadata.ID)
+		if err != nil {
+			result.Err = err
+			result.FailedNodes = nodes
+			return result
+		}
+		tasks = make([]types.TransitionTask, 0, len(exported.Items))
+		for _, item := range exported.Items {
+			tasks = append(tasks, item.Spec)
+		}
+	}
+	result.State = done.Spec.State
+	result.FailedNodes = failedChunkNodes(nodes, tasks)
+	return result
+}
+
+// failedChunkNodes returns the nodes without a succeeded task. Dry-run
+// tasks stay planned and count as succeeded.
+func failedChunkNodes(nodes []string, tasks []types.TransitionTask) []string {
+	succeeded := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		if task.State == types.TaskStateSucceeded || task.State == types.TaskStatePlanned {
+			succeeded[task.NodeID] = struct{}{}
+		}
+	}
+	failed := make([]string, 0)
+	for _, node := range nodes {
+		if _, ok := succeeded[node]; !ok {
+			failed = append(failed, node)
+		}
+	}
+	return failed
+}
+
+func summarizeBulk(operation string, total int, results []BulkResult) *BulkSummary {
+	summary := &BulkSummary{
+		Operation:   operation,
+		TotalNodes:  total,
+		FailedNodes: make([]string, 0),
+		Results:     results,
+	}
+	for _, result := range results {
+		summary.FailedNodes = append(summary.FailedNodes, result.FailedNodes...)
+	}
+	sort.Strings(summary.FailedNodes)
+	summary.SucceededNodes = total - len(summary.FailedNodes)
+
+	switch {
+	case len(summary.FailedNodes) == 0:
+		summary.State = types.TransitionStateCompleted
+	case summary.SucceededNodes == 0:
+		summary.State = types.TransitionStateFailed
+	default:
+		summary.State = types.TransitionStatePartial
+	}
+	return summary
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	baseclient "git.cscs.ch/openchami/chamicore-lib/httputil/client"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	header := func(value string) http.Header {
+		h := http.Header{}
+		h.Set("Retry-After", value)
+		return h
+	}
+
+	assert.Equal(t, time.Second, policy.delay(1, http.Header{}, now))
+	assert.Equal(t, 4*time.Second, policy.delay(3, http.Header{}, now))
+	assert.Equal(t, 10*time.Second, policy.delay(8, http.Header{}, now), "backoff is capped")
+	assert.Equal(t, 3*time.Second, policy.delay(5, header("3"), now), "Retry-After wins over backoff")
+	assert.Equal(t, 7*time.Second, policy.delay(1, header(now.Add(7*time.Second).Format(http.TimeFormat)), now))
+	assert.Equal(t, 10*time.Second, policy.delay(1, header("3600"), now), "Retry-After is capped")
+	assert.Equal(t, 2*time.Second, policy.delay(2, header("soon"), now), "unparseable Retry-After is ignored")
+}
+
+func TestCreateTransitionWithRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries 429 and 503", func(t *testing.T) {
+		t.Parallel()
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			switch atomic.AddInt32(&attempts, 1) {
+			case 1:
+				w.Header().Set("Retry-After", "0")
+				problemJSON(w, http.StatusTooManyRequests, "rate limited")
+			case 2:
+				problemJSON(w, http.StatusServiceUnavailable, "queue full")
+			default:
+				respondJSON(w, http.StatusAccepted, transitionResource("t-1", types.TransitionStatePending))
+			}
+		}))
+		defer ts.Close()
+
+		c := newTestClient(t, Config{BaseURL: ts.URL, Token: "token"})
+		resp, err := c.CreateTransitionWithRetry(context.Background(), types.CreateTransitionRequest{
+			Operation: "On",
+			Nodes:     []string{"node-1"},
+		}, RetryPolicy{BaseDelay: time.Millisecond})
+		require.NoError(t, err)
+		assert.Equal(t, "t-1", resp.Metadata.ID)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		t.Parallel()
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.Header().Set("Retry-After", "0")
+			problemJSON(w, http.StatusTooManyRequests, "rate limited")
+		}))
+		defer ts.Close()
+
+		c := newTestClient(t, Config{BaseURL: ts.URL})
+		_, err := c.CreateTransitionWithRetry(context.Background(), types.CreateTransitionRequest{Operation: "On"}, RetryPolicy{MaxAttempts: 2})
+		var apiErr *baseclient.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			problemJSON(w, http.StatusBadRequest, "invalid operation")
+		}))
+		defer ts.Close()
+
+		c := newTestClient(t, Config{BaseURL: ts.URL})
+		_, err := c.CreateTransitionWithRetry(context.Background(), types.CreateTransitionRequest{Operation: "Bad"}, RetryPolicy{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid operation")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+}
+
+func TestSplitNodes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, SplitNodes([]string{"a", "b", "c", "d", "e"}, 2))
+	assert.Empty(t, SplitNodes(nil, 2))
+}
+
+// bulkTestServer creates one transition per request and fails the tasks of
+// the nodes in failing.
+type bulkTestServer struct {
+	mu          sync.Mutex
+	created     map[string][]string
+	requestIDs  []string
+	inFlight    int32
+	maxInFlight int32
+	failing     map[string]bool
+	rejectChunk string
+}
+
+func (s *bulkTestServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var req types.CreateTransitionRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.Nodes[0] == s.rejectChunk {
+				problemJSON(w, http.StatusBadRequest, "no mapping")
+				return
+			}
+
+			inFlight := atomic.AddInt32(&s.inFlight, 1)
+			for {
+				seen := atomic.LoadInt32(&s.maxInFlight)
+				if inFlight <= seen || atomic.CompareAndSwapInt32(&s.maxInFlight, seen, inFlight) {
+					break
+				}
+			}
+
+			s.mu.Lock()
+			id := fmt.Sprintf("t-%d", len(s.created))
+			s.created[id] = req.Nodes
+			s.requestIDs = append(s.requestIDs, req.RequestID)
+			s.mu.Unlock()
+			respondJSON(w, http.StatusAccepted, transitionResource(id, types.TransitionStatePending))
+			return
+		}
+
+		// Every transition completes on its first poll, which frees its slot.
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&s.inFlight, -1)
+
+		id := r.URL.Path[len(transitionPathPrefix)+1:]
+		s.mu.Lock()
+		nodes := s.created[id]
+		s.mu.Unlock()
+
+		state := types.TransitionStateCompleted
+		tasks := make([]types.TransitionTask, 0, len(nodes))
+		for _, node := range nodes {
+			taskState := types.TaskStateSucceeded
+			if s.failing[node] {
+				taskState = types.TaskStateFailed
+				state = types.TransitionStatePartial
+			}
+			tasks = append(tasks, types.TransitionTask{NodeID: node, State: taskState})
+		}
+		resource := transitionResource(id, state)
+		resource.Spec.Tasks = tasks
+		respondJSON(w, http.StatusOK, resource)
+	}
+}
+
+func TestRunBulkTransition(t *testing.T) {
+	t.Parallel()
+
+	srv := &bulkTestServer{
+		created:     make(map[string][]string),
+		failing:     map[string]bool{"nid003": true},
+		rejectChunk: "nid008",
+	}
+	ts := httptest.NewServer(srv.handler(t))
+	defer ts.Close()
+
+	var streamed []BulkResult
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	summary, err := c.RunBulkTransition(context.Background(), types.CreateTransitionRequest{
+		Operation:    "On",
+		RequestID:    "maint",
+		Nodes:        []string{"nid[001-008]", "nid002"},
+		ExcludeNodes: []string{"nid005"},
+	}, BulkOptions{
+		ChunkSize:    2,
+		Concurrency:  2,
+		PollInterval: time.Millisecond,
+		OnResult:     func(result BulkResult) { streamed = append(streamed, result) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, summary.TotalNodes)
+	assert.Equal(t, types.TransitionStatePartial, summary.State)
+	assert.Equal(t, []string{"nid003", "nid008"}, summary.FailedNodes)
+	assert.Equal(t, 5, summary.SucceededNodes)
+	assert.Len(t, streamed, 4)
+	assert.LessOrEqual(t, atomic.LoadInt32(&srv.maxInFlight), int32(2))
+
+	require.Len(t, summary.Results, 4)
+	assert.Equal(t, []string{"nid001", "nid002"}, summary.Results[0].Nodes)
+	assert.Equal(t, []string{"nid003", "nid004"}, summary.Results[1].Nodes)
+	assert.Equal(t, []string{"nid003"}, summary.Results[1].FailedNodes)
+	assert.Equal(t, []string{"nid006", "nid007"}, summary.Results[2].Nodes)
+	require.Error(t, summary.Results[3].Err)
+	assert.Contains(t, summary.Results[3].Err.Error(), "no mapping")
+	assert.ElementsMatch(t, []string{"maint-0", "maint-1", "maint-2"}, srv.requestIDs)
+}
+
+func TestRunBulkTransition_RejectsUnsplittableTargets(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, Config{BaseURL: "http://example.invalid"})
+	for _, req := range []types.CreateTransitionRequest{
+		{Operation: "On", Groups: []string{"rack1"}},
+		{Operation: "On", Nodes: []string{"nid001"}, Selector: &types.NodeSelector{Role: "Compute"}},
+		{Operation: "On", Nodes: []string{"nid[1-"}},
+		{Operation: "On", Nodes: []string{"nid001"}, ExcludeNodes: []string{"nid001"}},
+	} {
+		_, err := c.RunBulkTransition(context.Background(), req, BulkOptions{})
+		require.ErrorIs(t, err, ErrBulkTargets)
+	}
+}
+
+func TestRunBulkTransition_AllSucceeded(t *testing.T) {
+	t.Parallel()
+
+	srv := &bulkTestServer{created: make(map[string][]string)}
+	ts := httptest.NewServer(srv.handler(t))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	summary, err := c.RunBulkTransition(context.Background(), types.CreateTransitionRequest{
+		Operation: "ForceOff",
+		Nodes:     []string{"x1000c0s[0-4]b0n0"},
+	}, BulkOptions{ChunkSize: 3, PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, types.TransitionStateCompleted, summary.State)
+	assert.Equal(t, 5, summary.SucceededNodes)
+	assert.Empty(t, summary.FailedNodes)
+	require.Len(t, summary.Results, 2)
+	assert.NotEmpty(t, summary.Results[1].TransitionID)
+}
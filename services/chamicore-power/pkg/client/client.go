@@ -2,8 +2,12 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -18,12 +22,20 @@ const (
 	defaultTimeout          = 30 * time.Second
 	defaultMaxRetries       = 3
 	defaultWaitPollInterval = 2 * time.Second
+	maxProblemBytes         = 64 << 10
 	transitionPathPrefix    = "/power/v1/transitions"
 	powerStatusPath         = "/power/v1/power-status"
 	actionOnPath            = "/power/v1/actions/on"
 	actionOffPath           = "/power/v1/actions/off"
 	actionRebootPath        = "/power/v1/actions/reboot"
 	actionResetPath         = "/power/v1/actions/reset"
+	actionBootOverridePath  = "/power/v1/actions/boot-override"
+	powerCapPath            = "/power/v1/powercap"
+	telemetryPath           = "/power/v1/telemetry"
+	templatePathPrefix      = "/power/v1/templates"
+	bootOverridePath        = "/power/v1/boot-override"
+	insertMediaPath         = "/power/v1/virtual-media/insert"
+	ejectMediaPath          = "/power/v1/virtual-media/eject"
 	adminMappingSyncPath    = "/power/v1/admin/mappings/sync"
 )
 
@@ -54,12 +66,38 @@ type ListTransitionsOptions struct {
 	Offset int
 }
 
+// TransitionTaskPageOptions selects the task page of GET /power/v1/transitions/{id}.
+type TransitionTaskPageOptions struct {
+	Limit  int
+	Offset int
+}
+
 // PowerStatusOptions configures GET /power/v1/power-status query parameters.
 type PowerStatusOptions struct {
+	// Nodes may use hostlist ranges such as x1000c[0-3]s0b0n[0-1].
+	Nodes    []string
+	Groups   []string
+	Selector types.NodeSelector
+}
+
+// PowerCapOptions configures GET /power/v1/powercap query parameters.
+type PowerCapOptions struct {
 	Nodes  []string
 	Groups []string
 }
 
+// TelemetryOptions configures GET /power/v1/telemetry query parameters.
+type TelemetryOptions struct {
+	Nodes  []string
+	Groups []string
+	// Since is the start of the window; defaults to one hour before Until.
+	Since time.Time
+	// Until is the end of the window; defaults to now.
+	Until time.Time
+	// Step is the width of each point; defaults to the sampling resolution.
+	Step time.Duration
+}
+
 // WaitTransitionOptions configures polling behavior in WaitTransition.
 type WaitTransitionOptions struct {
 	Interval time.Duration
@@ -142,6 +180,38 @@ func (c *Client) GetTransition(ctx context.Context, id string) (*httputil.Resour
 	return &result, nil
 }
 
+// GetTransitionTaskPage returns a transition with one page of its tasks. Use
+// it to walk the tasks of transitions larger than one page; Spec.TaskPage
+// reports the total.
+func (c *Client) GetTransitionTaskPage(
+	ctx context.Context,
+	id string,
+	opts TransitionTaskPageOptions,
+) (*httputil.Resource[types.Transition], error) {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return nil, fmt.Errorf("transition id is required")
+	}
+
+	params := url.Values{}
+	if opts.Limit > 0 {
+		params.Set("taskLimit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		params.Set("taskOffset", strconv.Itoa(opts.Offset))
+	}
+	path := fmt.Sprintf("%s/%s", transitionPathPrefix, url.PathEscape(transitionID))
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+
+	var result httputil.Resource[types.Transition]
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("getting transition %q tasks: %w", transitionID, err)
+	}
+	return &result, nil
+}
+
 // AbortTransition requests cancellation for an in-progress transition.
 //
 // The endpoint returns a transition body on 202 Accepted. The base client
@@ -165,6 +235,148 @@ func (c *Client) AbortTransition(ctx context.Context, id string) (*httputil.Reso
 	return result, nil
 }
 
+// AbortTransitionTask cancels the pending or running task of one node and
+// returns the updated transition.
+func (c *Client) AbortTransitionTask(
+	ctx context.Context,
+	id string,
+	nodeID string,
+) (*httputil.Resource[types.Transition], error) {
+	transitionID := strings.TrimSpace(id)
+	node := strings.TrimSpace(nodeID)
+	if transitionID == "" || node == "" {
+		return nil, fmt.Errorf("transition id and node id are required")
+	}
+
+	path := fmt.Sprintf("%s/%s/tasks/%s", transitionPathPrefix, url.PathEscape(transitionID), url.PathEscape(node))
+	if err := c.client.Delete(ctx, path); err != nil {
+		return nil, fmt.Errorf("aborting task for node %q in transition %q: %w", node, transitionID, err)
+	}
+
+	result, err := c.GetTransition(ctx, transitionID)
+	if err != nil {
+		return nil, fmt.Errorf("loading transition %q after task abort: %w", transitionID, err)
+	}
+	return result, nil
+}
+
+// RetryTransition re-runs the failed tasks of a finished transition as a new
+// transition whose ParentID links back to it.
+func (c *Client) RetryTransition(
+	ctx context.Context,
+	id string,
+	req types.TransitionRetryRequest,
+) (*httputil.Resource[types.Transition], error) {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return nil, fmt.Errorf("transition id is required")
+	}
+
+	var result httputil.Resource[types.Transition]
+	path := fmt.Sprintf("%s/%s/retry", transitionPathPrefix, url.PathEscape(transitionID))
+	if err := c.client.Post(ctx, path, req, &result); err != nil {
+		return nil, fmt.Errorf("retrying transition %q: %w", transitionID, err)
+	}
+	return &result, nil
+}
+
+// GetTransitionReport returns success rates, latency percentiles and failure
+// clusters computed from all tasks of a transition.
+func (c *Client) GetTransitionReport(ctx context.Context, id string) (*httputil.Resource[types.TransitionReport], error) {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return nil, fmt.Errorf("transition id is required")
+	}
+
+	var result httputil.Resource[types.TransitionReport]
+	path := fmt.Sprintf("%s/%s/report", transitionPathPrefix, url.PathEscape(transitionID))
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("getting transition %q report: %w", transitionID, err)
+	}
+	return &result, nil
+}
+
+// ExportTransitionTasks returns every task of a transition in one list.
+func (c *Client) ExportTransitionTasks(
+	ctx context.Context,
+	id string,
+) (*httputil.ResourceList[types.TransitionTask], error) {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return nil, fmt.Errorf("transition id is required")
+	}
+
+	var result httputil.ResourceList[types.TransitionTask]
+	path := fmt.Sprintf("%s/%s/export?format=json", transitionPathPrefix, url.PathEscape(transitionID))
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("exporting transition %q tasks: %w", transitionID, err)
+	}
+	return &result, nil
+}
+
+// ExportTransitionTasksCSV streams every task of a transition to w as CSV
+// with a header row.
+func (c *Client) ExportTransitionTasksCSV(ctx context.Context, id string, w io.Writer) error {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return fmt.Errorf("transition id is required")
+	}
+
+	path := fmt.Sprintf("%s/%s/export?format=csv", transitionPathPrefix, url.PathEscape(transitionID))
+	resp, err := c.doRaw(ctx, http.MethodGet, path, nil, "text/csv")
+	if err != nil {
+		return fmt.Errorf("exporting transition %q tasks: %w", transitionID, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("exporting transition %q tasks: %w", transitionID, readAPIError(resp))
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("exporting transition %q tasks: %w", transitionID, err)
+	}
+	return nil
+}
+
+// ApproveTransition approves a pending-approval transition and enqueues it.
+// The approver must differ from the principal that requested it.
+func (c *Client) ApproveTransition(
+	ctx context.Context,
+	id string,
+	req types.ApprovalDecisionRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.decideApproval(ctx, id, "approve", "approving", req)
+}
+
+// RejectTransition rejects a pending-approval transition.
+func (c *Client) RejectTransition(
+	ctx context.Context,
+	id string,
+	req types.ApprovalDecisionRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.decideApproval(ctx, id, "reject", "rejecting", req)
+}
+
+func (c *Client) decideApproval(
+	ctx context.Context,
+	id string,
+	action string,
+	verb string,
+	req types.ApprovalDecisionRequest,
+) (*httputil.Resource[types.Transition], error) {
+	transitionID := strings.TrimSpace(id)
+	if transitionID == "" {
+		return nil, fmt.Errorf("transition id is required")
+	}
+
+	var result httputil.Resource[types.Transition]
+	path := fmt.Sprintf("%s/%s/%s", transitionPathPrefix, url.PathEscape(transitionID), action)
+	if err := c.client.Post(ctx, path, req, &result); err != nil {
+		return nil, fmt.Errorf("%s transition %q: %w", verb, transitionID, err)
+	}
+	return &result, nil
+}
+
 // WaitTransition polls transition status until it reaches a terminal state.
 func (c *Client) WaitTransition(
 	ctx context.Context,
@@ -240,6 +452,91 @@ func (c *Client) ActionReset(
 	return c.startTransitionAction(ctx, actionResetPath, req, "reset")
 }
 
+// GetPowerCap returns per-node power limits and draw for the requested targets.
+func (c *Client) GetPowerCap(ctx context.Context, opts PowerCapOptions) (*httputil.Resource[types.PowerCap], error) {
+	var result httputil.Resource[types.PowerCap]
+	params := url.Values{}
+	appendQueryValues(params, "nodes", opts.Nodes)
+	appendQueryValues(params, "groups", opts.Groups)
+
+	path := powerCapPath
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("getting power caps: %w", err)
+	}
+	return &result, nil
+}
+
+// GetTelemetry returns sampled power draw and inlet temperature for the
+// requested targets, with per-group aggregates for requested groups.
+func (c *Client) GetTelemetry(ctx context.Context, opts TelemetryOptions) (*httputil.Resource[types.Telemetry], error) {
+	var result httputil.Resource[types.Telemetry]
+	params := url.Values{}
+	appendQueryValues(params, "nodes", opts.Nodes)
+	appendQueryValues(params, "groups", opts.Groups)
+	if !opts.Since.IsZero() {
+		params.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("until", opts.Until.UTC().Format(time.RFC3339))
+	}
+	if opts.Step > 0 {
+		params.Set("step", opts.Step.String())
+	}
+
+	path := telemetryPath
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("getting telemetry: %w", err)
+	}
+	return &result, nil
+}
+
+// ApplyPowerCap starts a "PowerCap" transition for requested targets.
+func (c *Client) ApplyPowerCap(
+	ctx context.Context,
+	req types.PowerCapRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.startTransitionAction(ctx, powerCapPath, req, "power cap")
+}
+
+// SetBootOverride starts a "SetBootOverride" transition for requested targets.
+func (c *Client) SetBootOverride(
+	ctx context.Context,
+	req types.BootOverrideRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.startTransitionAction(ctx, bootOverridePath, req, "boot override")
+}
+
+// InsertMedia starts an "InsertMedia" transition for requested targets.
+func (c *Client) InsertMedia(
+	ctx context.Context,
+	req types.InsertMediaRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.startTransitionAction(ctx, insertMediaPath, req, "insert media")
+}
+
+// EjectMedia starts an "EjectMedia" transition for requested targets.
+func (c *Client) EjectMedia(
+	ctx context.Context,
+	req types.EjectMediaRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.startTransitionAction(ctx, ejectMediaPath, req, "eject media")
+}
+
+// ActionBootOverride starts a "BootOverrideReset" transition, which sets a boot
+// override (optionally inserting media first) and then resets the targets.
+func (c *Client) ActionBootOverride(
+	ctx context.Context,
+	req types.BootOverrideActionRequest,
+) (*httputil.Resource[types.Transition], error) {
+	return c.startTransitionAction(ctx, actionBootOverridePath, req, "boot override reset")
+}
+
 // TriggerMappingSync requests an immediate sync of local power topology mappings.
 func (c *Client) TriggerMappingSync(ctx context.Context) (*httputil.Resource[types.MappingSyncTrigger], error) {
 	var result httputil.Resource[types.MappingSyncTrigger]
@@ -249,13 +546,112 @@ func (c *Client) TriggerMappingSync(ctx context.Context) (*httputil.Resource[typ
 	return &result, nil
 }
 
+// ListTemplates returns the latest version of every transition template.
+func (c *Client) ListTemplates(ctx context.Context) (*httputil.ResourceList[types.Template], error) {
+	var result httputil.ResourceList[types.Template]
+	if err := c.client.Get(ctx, templatePathPrefix, &result); err != nil {
+		return nil, fmt.Errorf("listing templates: %w", err)
+	}
+	return &result, nil
+}
+
+// ListTemplateVersions returns every stored version of one template, newest first.
+func (c *Client) ListTemplateVersions(ctx context.Context, name string) (*httputil.ResourceList[types.Template], error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result httputil.ResourceList[types.Template]
+	if err := c.client.Get(ctx, path+"/versions", &result); err != nil {
+		return nil, fmt.Errorf("listing versions of template %q: %w", name, err)
+	}
+	return &result, nil
+}
+
+// GetTemplate returns one template version; version zero returns the latest.
+func (c *Client) GetTemplate(ctx context.Context, name string, version int) (*httputil.Resource[types.Template], error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 {
+		path += "?version=" + strconv.Itoa(version)
+	}
+
+	var result httputil.Resource[types.Template]
+	if err := c.client.Get(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("getting template %q: %w", name, err)
+	}
+	return &result, nil
+}
+
+// CreateTemplate stores version 1 of a new template.
+func (c *Client) CreateTemplate(ctx context.Context, req types.TemplateRequest) (*httputil.Resource[types.Template], error) {
+	var result httputil.Resource[types.Template]
+	if err := c.client.Post(ctx, templatePathPrefix, req, &result); err != nil {
+		return nil, fmt.Errorf("creating template: %w", err)
+	}
+	return &result, nil
+}
+
+// UpdateTemplate stores req as the next version of an existing template.
+func (c *Client) UpdateTemplate(
+	ctx context.Context,
+	name string,
+	req types.TemplateRequest,
+) (*httputil.Resource[types.Template], error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result httputil.Resource[types.Template]
+	if err := c.client.Put(ctx, path, req, &result); err != nil {
+		return nil, fmt.Errorf("updating template %q: %w", name, err)
+	}
+	return &result, nil
+}
+
+// DeleteTemplate removes every version of a template.
+func (c *Client) DeleteTemplate(ctx context.Context, name string) error {
+	path, err := templatePath(name)
+	if err != nil {
+		return err
+	}
+	if err := c.client.Delete(ctx, path); err != nil {
+		return fmt.Errorf("deleting template %q: %w", name, err)
+	}
+	return nil
+}
+
+// RunTemplate starts a transition from a template.
+func (c *Client) RunTemplate(
+	ctx context.Context,
+	name string,
+	req types.RunTemplateRequest,
+) (*httputil.Resource[types.Transition], error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result httputil.Resource[types.Transition]
+	if err := c.client.Post(ctx, path+"/run", req, &result); err != nil {
+		return nil, fmt.Errorf("running template %q: %w", name, err)
+	}
+	return &result, nil
+}
+
 // IsTransitionTerminalState reports whether transition state is final.
 func IsTransitionTerminalState(state string) bool {
 	switch strings.ToLower(strings.TrimSpace(state)) {
 	case types.TransitionStateCompleted,
 		types.TransitionStateFailed,
 		types.TransitionStatePartial,
-		types.TransitionStateCanceled:
+		types.TransitionStateCanceled,
+		types.TransitionStateRejected,
+		types.TransitionStateExpired:
 		return true
 	default:
 		return false
@@ -275,6 +671,58 @@ func (c *Client) startTransitionAction(
 	return &result, nil
 }
 
+// doRaw sends one request without the base client, for responses it cannot
+// decode or whose headers the caller needs. The caller closes the body.
+func (c *Client) doRaw(ctx context.Context, method, path string, body any, accept string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", accept)
+	if requestID, ok := ctx.Value(baseclient.RequestIDKey).(string); ok && requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	token := c.cfg.Token
+	if token == "" && c.cfg.TokenRefresh != nil {
+		token, err = c.cfg.TokenRefresh(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resolving token: %w", err)
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return (&http.Client{Timeout: c.cfg.Timeout}).Do(req)
+}
+
+// readAPIError builds the base client's error from a failed response, so
+// callers can use errors.As the same way for every method.
+func readAPIError(resp *http.Response) *baseclient.APIError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxProblemBytes))
+	var problem httputil.ProblemDetail
+	if err := json.Unmarshal(data, &problem); err != nil || problem.Detail == "" {
+		problem = httputil.ProblemDetail{
+			Title:  http.StatusText(resp.StatusCode),
+			Status: resp.StatusCode,
+			Detail: strings.TrimSpace(string(data)),
+		}
+	}
+	return &baseclient.APIError{StatusCode: resp.StatusCode, Problem: problem}
+}
+
 func buildListTransitionsPath(opts ListTransitionsOptions) string {
 	params := url.Values{}
 	if opts.Limit > 0 {
@@ -290,10 +738,23 @@ func buildListTransitionsPath(opts ListTransitionsOptions) string {
 	return transitionPathPrefix
 }
 
+func templatePath(name string) (string, error) {
+	templateName := strings.TrimSpace(name)
+	if templateName == "" {
+		return "", fmt.Errorf("template name is required")
+	}
+	return fmt.Sprintf("%s/%s", templatePathPrefix, url.PathEscape(templateName)), nil
+}
+
 func buildPowerStatusPath(opts PowerStatusOptions) string {
 	params := url.Values{}
 	appendQueryValues(params, "nodes", opts.Nodes)
 	appendQueryValues(params, "groups", opts.Groups)
+	appendQueryValues(params, "role", []string{opts.Selector.Role})
+	appendQueryValues(params, "subRole", []string{opts.Selector.SubRole})
+	appendQueryValues(params, "arch", []string{opts.Selector.Arch})
+	appendQueryValues(params, "state", []string{opts.Selector.State})
+	appendQueryValues(params, "cabinet", []string{opts.Selector.Cabinet})
 
 	if encoded := params.Encode(); encoded != "" {
 		return powerStatusPath + "?" + encoded
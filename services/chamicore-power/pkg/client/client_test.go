@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -191,6 +192,73 @@ func TestGetTransition(t *testing.T) {
 	})
 }
 
+func TestGetTransitionReport(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/power/v1/transitions/t-1/report", r.URL.Path)
+		respondJSON(w, http.StatusOK, httputil.Resource[types.TransitionReport]{
+			Kind: "TransitionReport",
+			Spec: types.TransitionReport{
+				TransitionID: "t-1",
+				SuccessRate:  0.5,
+				FailuresByError: []types.ReportErrorGroup{
+					{Error: "connection refused", Count: 1, Nodes: []string{"x0"}},
+				},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	resp, err := c.GetTransitionReport(context.Background(), "t-1")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, resp.Spec.SuccessRate, 1e-9)
+	require.Len(t, resp.Spec.FailuresByError, 1)
+	assert.Equal(t, []string{"x0"}, resp.Spec.FailuresByError[0].Nodes)
+}
+
+func TestExportTransitionTasks(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/power/v1/transitions/t-1/export" {
+			problemJSON(w, http.StatusNotFound, "transition not found")
+			return
+		}
+		switch r.URL.Query().Get("format") {
+		case "json":
+			respondJSON(w, http.StatusOK, httputil.ResourceList[types.TransitionTask]{
+				Metadata: httputil.ListMetadata{Total: 1},
+				Items: []httputil.Resource[types.TransitionTask]{
+					{Spec: types.TransitionTask{NodeID: "x0", State: types.TaskStateFailed}},
+				},
+			})
+		case "csv":
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			_, _ = w.Write([]byte("nodeID,state\nx0,failed\n"))
+		}
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL, Token: "secret"})
+	list, err := c.ExportTransitionTasks(context.Background(), "t-1")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "x0", list.Items[0].Spec.NodeID)
+
+	var out strings.Builder
+	require.NoError(t, c.ExportTransitionTasksCSV(context.Background(), "t-1", &out))
+	assert.Equal(t, "nodeID,state\nx0,failed\n", out.String())
+
+	err = c.ExportTransitionTasksCSV(context.Background(), "t-404", &out)
+	var apiErr *baseclient.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "transition not found", apiErr.Problem.Detail)
+}
+
 func TestAbortTransition(t *testing.T) {
 	t.Parallel()
 
@@ -354,6 +422,9 @@ func TestGetPowerStatus(t *testing.T) {
 		assert.Equal(t, powerStatusPath, r.URL.Path)
 		assert.Equal(t, []string{"node-1", "node-2"}, r.URL.Query()["nodes"])
 		assert.Equal(t, []string{"compute"}, r.URL.Query()["groups"])
+		assert.Equal(t, "Compute", r.URL.Query().Get("role"))
+		assert.Equal(t, "x1000", r.URL.Query().Get("cabinet"))
+		assert.NotContains(t, r.URL.Query(), "arch")
 		respondJSON(w, http.StatusOK, httputil.Resource[types.PowerStatus]{
 			Kind:       "PowerStatus",
 			APIVersion: "power/v1",
@@ -374,8 +445,9 @@ func TestGetPowerStatus(t *testing.T) {
 
 	c := newTestClient(t, Config{BaseURL: ts.URL})
 	resp, err := c.GetPowerStatus(context.Background(), PowerStatusOptions{
-		Nodes:  []string{"node-1", " ", "node-2"},
-		Groups: []string{"compute"},
+		Nodes:    []string{"node-1", " ", "node-2"},
+		Groups:   []string{"compute"},
+		Selector: types.NodeSelector{Role: "Compute", Cabinet: "x1000"},
 	})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -423,6 +495,146 @@ func TestGetPowerStatus_EmptyQueryAndError(t *testing.T) {
 	})
 }
 
+func TestGetPowerCap(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, powerCapPath, r.URL.Path)
+		assert.Equal(t, []string{"node-1"}, r.URL.Query()["nodes"])
+		assert.Equal(t, []string{"compute"}, r.URL.Query()["groups"])
+		limit := 500
+		respondJSON(w, http.StatusOK, httputil.Resource[types.PowerCap]{
+			Kind:       "PowerCap",
+			APIVersion: "power/v1",
+			Metadata:   httputil.Metadata{ID: "powercap"},
+			Spec: types.PowerCap{
+				Total: 1,
+				Nodes: []types.PowerCapNode{{NodeID: "node-1", LimitWatts: &limit, Source: "Power"}},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	resp, err := c.GetPowerCap(context.Background(), PowerCapOptions{
+		Nodes:  []string{"node-1"},
+		Groups: []string{"compute"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Spec.Nodes, 1)
+	require.NotNil(t, resp.Spec.Nodes[0].LimitWatts)
+	assert.Equal(t, 500, *resp.Spec.Nodes[0].LimitWatts)
+}
+
+func TestGetTelemetry(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, telemetryPath, r.URL.Path)
+		assert.Equal(t, []string{"compute"}, r.URL.Query()["groups"])
+		assert.Equal(t, "2026-03-01T12:00:00Z", r.URL.Query().Get("since"))
+		assert.Empty(t, r.URL.Query().Get("until"))
+		assert.Equal(t, "5m0s", r.URL.Query().Get("step"))
+		watts := 720.5
+		respondJSON(w, http.StatusOK, httputil.Resource[types.Telemetry]{
+			Kind:       "Telemetry",
+			APIVersion: "power/v1",
+			Metadata:   httputil.Metadata{ID: "telemetry"},
+			Spec: types.Telemetry{
+				Since:       since,
+				StepSeconds: 300,
+				Groups: []types.TelemetryGroup{{
+					Group:     "compute",
+					NodeCount: 2,
+					Points:    []types.TelemetryGroupPoint{{Timestamp: since, ReportingNodes: 2, PowerWatts: &watts}},
+				}},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	resp, err := c.GetTelemetry(context.Background(), TelemetryOptions{
+		Groups: []string{"compute"},
+		Since:  since,
+		Step:   5 * time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Spec.Groups, 1)
+	require.Len(t, resp.Spec.Groups[0].Points, 1)
+	assert.InDelta(t, 720.5, *resp.Spec.Groups[0].Points[0].PowerWatts, 0.001)
+}
+
+func TestTemplateEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == templatePathPrefix:
+			var req types.TemplateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "drain-rack", req.Name)
+			assert.Equal(t, 300, req.Escalation.AfterSeconds)
+			respondJSON(w, http.StatusCreated, httputil.Resource[types.Template]{
+				Kind: "TransitionTemplate",
+				Spec: types.Template{Name: req.Name, Version: 1, Operation: req.Operation, BatchSize: req.BatchSize},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == templatePathPrefix+"/drain-rack":
+			assert.Equal(t, "2", r.URL.Query().Get("version"))
+			respondJSON(w, http.StatusOK, httputil.Resource[types.Template]{
+				Kind: "TransitionTemplate",
+				Spec: types.Template{Name: "drain-rack", Version: 2},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == templatePathPrefix+"/drain-rack/run":
+			var req types.RunTemplateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.Confirm)
+			assert.Equal(t, []string{"node-1"}, req.Nodes)
+			respondJSON(w, http.StatusAccepted, httputil.Resource[types.Transition]{
+				Kind:     "Transition",
+				Metadata: httputil.Metadata{ID: "tr-1"},
+				Spec:     types.Transition{TemplateName: "drain-rack", TemplateVersion: 2},
+			})
+		case r.Method == http.MethodDelete && r.URL.Path == templatePathPrefix+"/drain-rack":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	ctx := context.Background()
+
+	created, err := c.CreateTemplate(ctx, types.TemplateRequest{
+		Name:       "drain-rack",
+		Operation:  "GracefulShutdown",
+		BatchSize:  32,
+		Escalation: &types.Escalation{AfterSeconds: 300},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.Spec.Version)
+	assert.Equal(t, 32, created.Spec.BatchSize)
+
+	fetched, err := c.GetTemplate(ctx, "drain-rack", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetched.Spec.Version)
+
+	run, err := c.RunTemplate(ctx, "drain-rack", types.RunTemplateRequest{Nodes: []string{"node-1"}, Confirm: true})
+	require.NoError(t, err)
+	assert.Equal(t, "drain-rack", run.Spec.TemplateName)
+	assert.Equal(t, 2, run.Spec.TemplateVersion)
+
+	require.NoError(t, c.DeleteTemplate(ctx, "drain-rack"))
+
+	_, err = c.GetTemplate(ctx, " ", 0)
+	require.Error(t, err)
+}
+
 func TestActionEndpoints(t *testing.T) {
 	t.Parallel()
 
@@ -462,6 +674,49 @@ func TestActionEndpoints(t *testing.T) {
 				})
 			},
 		},
+		{
+			name: "power cap",
+			path: powerCapPath,
+			call: func(ctx context.Context, c *Client) (*httputil.Resource[types.Transition], error) {
+				limit := 400
+				return c.ApplyPowerCap(ctx, types.PowerCapRequest{Nodes: []string{"node-1"}, LimitWatts: &limit})
+			},
+		},
+		{
+			name: "boot override",
+			path: bootOverridePath,
+			call: func(ctx context.Context, c *Client) (*httputil.Resource[types.Transition], error) {
+				return c.SetBootOverride(ctx, types.BootOverrideRequest{Nodes: []string{"node-1"}, Target: "Pxe"})
+			},
+		},
+		{
+			name: "insert media",
+			path: insertMediaPath,
+			call: func(ctx context.Context, c *Client) (*httputil.Resource[types.Transition], error) {
+				return c.InsertMedia(ctx, types.InsertMediaRequest{
+					Nodes: []string{"node-1"},
+					Image: "http://images.example/rescue.iso",
+				})
+			},
+		},
+		{
+			name: "eject media",
+			path: ejectMediaPath,
+			call: func(ctx context.Context, c *Client) (*httputil.Resource[types.Transition], error) {
+				return c.EjectMedia(ctx, types.EjectMediaRequest{Nodes: []string{"node-1"}})
+			},
+		},
+		{
+			name: "boot override reset",
+			path: actionBootOverridePath,
+			call: func(ctx context.Context, c *Client) (*httputil.Resource[types.Transition], error) {
+				return c.ActionBootOverride(ctx, types.BootOverrideActionRequest{
+					Nodes:  []string{"node-1"},
+					Target: "Cd",
+					Image:  "http://images.example/rescue.iso",
+				})
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -638,6 +893,138 @@ func TestRetryAndErrors(t *testing.T) {
 	})
 }
 
+func TestApprovalEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		var req types.ApprovalDecisionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch r.URL.Path {
+		case transitionPathPrefix + "/tr-1/approve":
+			assert.Equal(t, "maintenance window", req.Reason)
+			respondJSON(w, http.StatusOK, httputil.Resource[types.Transition]{
+				Kind:     "Transition",
+				Metadata: httputil.Metadata{ID: "tr-1"},
+				Spec: types.Transition{
+					State: types.TransitionStatePending,
+					Approvals: []types.Approval{
+						{Action: "requested", Principal: "alice"},
+						{Action: "approved", Principal: "bob", Reason: req.Reason},
+					},
+				},
+			})
+		case transitionPathPrefix + "/tr-2/reject":
+			respondJSON(w, http.StatusOK, httputil.Resource[types.Transition]{
+				Kind:     "Transition",
+				Metadata: httputil.Metadata{ID: "tr-2"},
+				Spec:     types.Transition{State: types.TransitionStateRejected},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	ctx := context.Background()
+
+	approved, err := c.ApproveTransition(ctx, "tr-1", types.ApprovalDecisionRequest{Reason: "maintenance window"})
+	require.NoError(t, err)
+	assert.Equal(t, types.TransitionStatePending, approved.Spec.State)
+	require.Len(t, approved.Spec.Approvals, 2)
+	assert.Equal(t, "bob", approved.Spec.Approvals[1].Principal)
+
+	rejected, err := c.RejectTransition(ctx, "tr-2", types.ApprovalDecisionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, types.TransitionStateRejected, rejected.Spec.State)
+
+	_, err = c.ApproveTransition(ctx, " ", types.ApprovalDecisionRequest{})
+	require.Error(t, err)
+}
+
+func TestTaskAbortAndRetryEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete && r.URL.Path == transitionPathPrefix+"/tr-1/tasks/x1000c0s0b0n0":
+			respondJSON(w, http.StatusAccepted, httputil.Resource[types.Transition]{Metadata: httputil.Metadata{ID: "tr-1"}})
+		case r.Method == http.MethodGet && r.URL.Path == transitionPathPrefix+"/tr-1":
+			respondJSON(w, http.StatusOK, httputil.Resource[types.Transition]{
+				Metadata: httputil.Metadata{ID: "tr-1"},
+				Spec: types.Transition{
+					State: types.TransitionStateRunning,
+					Tasks: []types.TransitionTask{{NodeID: "x1000c0s0b0n0", State: "canceled"}},
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == transitionPathPrefix+"/tr-1/retry":
+			var req types.TransitionRetryRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.IncludeCanceled)
+			respondJSON(w, http.StatusAccepted, httputil.Resource[types.Transition]{
+				Metadata: httputil.Metadata{ID: "tr-2"},
+				Spec:     types.Transition{State: types.TransitionStatePending, ParentID: "tr-1"},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	ctx := context.Background()
+
+	aborted, err := c.AbortTransitionTask(ctx, "tr-1", "x1000c0s0b0n0")
+	require.NoError(t, err)
+	require.Len(t, aborted.Spec.Tasks, 1)
+	assert.Equal(t, "canceled", aborted.Spec.Tasks[0].State)
+
+	retry, err := c.RetryTransition(ctx, "tr-1", types.TransitionRetryRequest{IncludeCanceled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "tr-2", retry.Metadata.ID)
+	assert.Equal(t, "tr-1", retry.Spec.ParentID)
+
+	_, err = c.AbortTransitionTask(ctx, "tr-1", " ")
+	require.Error(t, err)
+	_, err = c.RetryTransition(ctx, " ", types.TransitionRetryRequest{})
+	require.Error(t, err)
+}
+
+func TestGetTransitionTaskPage(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, transitionPathPrefix+"/tr-1", r.URL.Path)
+		assert.Equal(t, "50", r.URL.Query().Get("taskLimit"))
+		assert.Equal(t, "100", r.URL.Query().Get("taskOffset"))
+		respondJSON(w, http.StatusOK, httputil.Resource[types.Transition]{
+			Metadata: httputil.Metadata{ID: "tr-1"},
+			Spec: types.Transition{
+				State:      types.TransitionStateRunning,
+				ChunkSize:  20,
+				TaskCounts: map[string]int{"pending": 150, "succeeded": 50},
+				TaskPage:   &types.TaskPage{Total: 200, Limit: 50, Offset: 100},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := newTestClient(t, Config{BaseURL: ts.URL})
+	got, err := c.GetTransitionTaskPage(context.Background(), "tr-1", TransitionTaskPageOptions{Limit: 50, Offset: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 20, got.Spec.ChunkSize)
+	assert.Equal(t, 150, got.Spec.TaskCounts["pending"])
+	require.NotNil(t, got.Spec.TaskPage)
+	assert.Equal(t, 200, got.Spec.TaskPage.Total)
+
+	_, err = c.GetTransitionTaskPage(context.Background(), " ", TransitionTaskPageOptions{})
+	require.Error(t, err)
+}
+
 func TestIsTransitionTerminalState(t *testing.T) {
 	t.Parallel()
 
@@ -649,6 +1036,9 @@ func TestIsTransitionTerminalState(t *testing.T) {
 		{state: "failed", want: true},
 		{state: "partial", want: true},
 		{state: "canceled", want: true},
+		{state: "rejected", want: true},
+		{state: "expired", want: true},
+		{state: "pending-approval", want: false},
 		{state: "RUNNING", want: false},
 		{state: " pending ", want: false},
 		{state: "", want: false},
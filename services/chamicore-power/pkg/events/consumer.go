@@ -0,0 +1,265 @@
+// Package events consumes the transition events chamicore-power publishes
+// through its outbox, decoding them into the payload types of pkg/types.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	libevents "git.cscs.ch/openchami/chamicore-lib/events"
+	eventsnats "git.cscs.ch/openchami/chamicore-lib/events/nats"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+const (
+	// TransitionEventsSubject matches every transition event.
+	TransitionEventsSubject = "chamicore.power.transitions.>"
+	// DefaultStream is the JetStream stream the power outbox publishes to.
+	DefaultStream = "CHAMICORE_POWER"
+
+	defaultDedupeSize = 10000
+)
+
+var (
+	// ErrUnsupportedSchemaVersion indicates an event payload newer than this
+	// package understands.
+	ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+	// ErrMalformedEvent indicates an event whose data could not be decoded.
+	ErrMalformedEvent = errors.New("malformed event")
+)
+
+// Subscriber is the subset of the shared events subscriber the consumer uses.
+type Subscriber interface {
+	Subscribe(ctx context.Context, subject string, handler func(libevents.Event) error) error
+}
+
+// Handlers receive decoded events. Nil handlers skip their event type.
+// Returning an error leaves the event unacknowledged so it is redelivered.
+type Handlers struct {
+	Lifecycle  func(ctx context.Context, evt libevents.Event, data types.TransitionLifecycleEvent) error
+	TaskResult func(ctx context.Context, evt libevents.Event, data types.TransitionTaskResultEvent) error
+	Approval   func(ctx context.Context, evt libevents.Event, data types.TransitionApprovalEvent) error
+}
+
+// Consumer dispatches power events to typed handlers, dropping redeliveries
+// of events it already handled.
+type Consumer struct {
+	handlers      Handlers
+	dedupe        *dedupeWindow
+	onInvalidData func(evt libevents.Event, err error)
+}
+
+// Option configures a Consumer.
+type Option func(*Consumer)
+
+// WithDedupeSize sets how many recent event IDs are remembered to drop
+// redeliveries. Zero disables deduplication.
+func WithDedupeSize(size int) Option {
+	return func(c *Consumer) {
+		c.dedupe = newDedupeWindow(size)
+	}
+}
+
+// WithInvalidEventHandler is called for events that cannot be decoded or
+// carry an unsupported schema version. Such events are acknowledged and
+// dropped, since redelivering them cannot succeed.
+func WithInvalidEventHandler(fn func(evt libevents.Event, err error)) Option {
+	return func(c *Consumer) {
+		c.onInvalidData = fn
+	}
+}
+
+// NewConsumer constructs a consumer for the given handlers.
+func NewConsumer(handlers Handlers, opts ...Option) *Consumer {
+	c := &Consumer{
+		handlers: handlers,
+		dedupe:   newDedupeWindow(defaultDedupeSize),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Subscribe registers the consumer for all transition events.
+func (c *Consumer) Subscribe(ctx context.Context, sub Subscriber) error {
+	if sub == nil {
+		return fmt.Errorf("event subscriber is nil")
+	}
+	if err := sub.Subscribe(ctx, TransitionEventsSubject, func(evt libevents.Event) error {
+		return c.Handle(ctx, evt)
+	}); err != nil {
+		return fmt.Errorf("subscribing to %s: %w", TransitionEventsSubject, err)
+	}
+	return nil
+}
+
+// Handle decodes one event and calls its handler. Events of unknown types,
+// events without a handler and already handled events are ignored.
+func (c *Consumer) Handle(ctx context.Context, evt libevents.Event) error {
+	eventID := strings.TrimSpace(evt.ID)
+	if eventID != "" && c.dedupe.seen(eventID) {
+		return nil
+	}
+
+	err := c.dispatch(ctx, evt)
+	if errors.Is(err, ErrMalformedEvent) || errors.Is(err, ErrUnsupportedSchemaVersion) {
+		if c.onInvalidData != nil {
+			c.onInvalidData(evt, err)
+		}
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if eventID != "" {
+		c.dedupe.add(eventID)
+	}
+	return nil
+}
+
+func (c *Consumer) dispatch(ctx context.Context, evt libevents.Event) error {
+	switch strings.TrimSpace(evt.Type) {
+	case types.EventTypeTransitionLifecycle:
+		if c.handlers.Lifecycle == nil {
+			return nil
+		}
+		var data types.TransitionLifecycleEvent
+		if err := decodeEventData(evt, &data, &data.SchemaVersion); err != nil {
+			return err
+		}
+		return c.handlers.Lifecycle(ctx, evt, data)
+	case types.EventTypeTransitionTaskResult:
+		if c.handlers.TaskResult == nil {
+			return nil
+		}
+		var data types.TransitionTaskResultEvent
+		if err := decodeEventData(evt, &data, &data.SchemaVersion); err != nil {
+			return err
+		}
+		return c.handlers.TaskResult(ctx, evt, data)
+	case types.EventTypeTransitionApproval:
+		if c.handlers.Approval == nil {
+			return nil
+		}
+		var data types.TransitionApprovalEvent
+		if err := decodeEventData(evt, &data, &data.SchemaVersion); err != nil {
+			return err
+		}
+		return c.handlers.Approval(ctx, evt, data)
+	default:
+		return nil
+	}
+}
+
+// decodeEventData unmarshals event data and checks the decoded schema
+// version. Payloads from before versioning decode as version 1.
+func decodeEventData(evt libevents.Event, out any, version *int) error {
+	if err := json.Unmarshal(evt.Data, out); err != nil {
+		return fmt.Errorf("%w: event %s: %v", ErrMalformedEvent, evt.ID, err)
+	}
+	if *version == 0 {
+		*version = 1
+	}
+	if *version > types.TransitionEventSchemaVersion {
+		return fmt.Errorf(
+			"%w: event %s has version %d, max %d",
+			ErrUnsupportedSchemaVersion,
+			evt.ID,
+			*version,
+			types.TransitionEventSchemaVersion,
+		)
+	}
+	return nil
+}
+
+// NATSConfig configures a JetStream subscriber for the power stream.
+type NATSConfig struct {
+	URL string
+	// Name identifies the connection; it defaults to "chamicore-power-consumer".
+	Name string
+	// Stream defaults to DefaultStream.
+	Stream string
+}
+
+// NewNATSSubscriber connects to the JetStream stream the power outbox
+// publishes to. Callers close the subscriber when done.
+func NewNATSSubscriber(cfg NATSConfig) (*eventsnats.Subscriber, error) {
+	url := strings.TrimSpace(cfg.URL)
+	if url == "" {
+		return nil, fmt.Errorf("NATS URL is required")
+	}
+	name := strings.TrimSpace(cfg.Name)
+	if name == "" {
+		name = "chamicore-power-consumer"
+	}
+	stream := strings.TrimSpace(cfg.Stream)
+	if stream == "" {
+		stream = DefaultStream
+	}
+
+	sub, err := eventsnats.NewSubscriber(eventsnats.Config{
+		URL:  url,
+		Name: name,
+		Stream: eventsnats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{"chamicore.power.>"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating NATS subscriber: %w", err)
+	}
+	return sub, nil
+}
+
+// dedupeWindow remembers the most recent event IDs in insertion order.
+type dedupeWindow struct {
+	mu    sync.Mutex
+	size  int
+	ids   map[string]struct{}
+	order []string
+	next  int
+}
+
+func newDedupeWindow(size int) *dedupeWindow {
+	if size < 0 {
+		size = 0
+	}
+	return &dedupeWindow{
+		size: size,
+		ids:  make(map[string]struct{}, size),
+	}
+}
+
+func (w *dedupeWindow) seen(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.ids[id]
+	return ok
+}
+
+func (w *dedupeWindow) add(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size == 0 {
+		return
+	}
+	if _, ok := w.ids[id]; ok {
+		return
+	}
+	if len(w.order) < w.size {
+		w.order = append(w.order, id)
+	} else {
+		delete(w.ids, w.order[w.next])
+		w.order[w.next] = id
+		w.next = (w.next + 1) % w.size
+	}
+	w.ids[id] = struct{}{}
+}
@@ -0,0 +1,159 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libevents "git.cscs.ch/openchami/chamicore-lib/events"
+	"git.cscs.ch/openchami/chamicore-power/pkg/types"
+)
+
+type fakeSubscriber struct {
+	subject string
+	handler func(libevents.Event) error
+	err     error
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, subject string, handler func(libevents.Event) error) error {
+	f.subject = subject
+	f.handler = handler
+	return f.err
+}
+
+func newTestEvent(t *testing.T, id, eventType string, data any) libevents.Event {
+	t.Helper()
+
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	return libevents.Event{
+		ID:              id,
+		Source:          types.EventSource,
+		Type:            eventType,
+		DataContentType: libevents.JSONDataContentType,
+		Data:            raw,
+	}
+}
+
+func TestConsumer_DispatchesTypedEvents(t *testing.T) {
+	var (
+		lifecycle types.TransitionLifecycleEvent
+		task      types.TransitionTaskResultEvent
+		approval  types.TransitionApprovalEvent
+	)
+	consumer := NewConsumer(Handlers{
+		Lifecycle: func(ctx context.Context, evt libevents.Event, data types.TransitionLifecycleEvent) error {
+			lifecycle = data
+			return nil
+		},
+		TaskResult: func(ctx context.Context, evt libevents.Event, data types.TransitionTaskResultEvent) error {
+			task = data
+			return nil
+		},
+		Approval: func(ctx context.Context, evt libevents.Event, data types.TransitionApprovalEvent) error {
+			approval = data
+			return nil
+		},
+	})
+	sub := &fakeSubscriber{}
+	require.NoError(t, consumer.Subscribe(context.Background(), sub))
+	assert.Equal(t, TransitionEventsSubject, sub.subject)
+
+	require.NoError(t, sub.handler(newTestEvent(t, "evt-1", types.EventTypeTransitionLifecycle, types.TransitionLifecycleEvent{
+		SchemaVersion: types.TransitionEventSchemaVersion,
+		TransitionID:  "tr-1",
+		Snapshot:      types.TransitionEventSnapshot{ID: "tr-1", State: types.TransitionStateCompleted},
+	})))
+	require.NoError(t, sub.handler(newTestEvent(t, "evt-2", types.EventTypeTransitionTaskResult, types.TransitionTaskResultEvent{
+		TransitionID: "tr-1",
+		NodeID:       "node-1",
+		TaskID:       "task-1",
+		Snapshot:     types.TransitionTaskEventSnapshot{State: types.TaskStateSucceeded, FinalPowerState: "On"},
+	})))
+	require.NoError(t, sub.handler(newTestEvent(t, "evt-3", types.EventTypeTransitionApproval, types.TransitionApprovalEvent{
+		TransitionID: "tr-1",
+		Action:       "approved",
+		Principal:    "bob",
+	})))
+
+	assert.Equal(t, "tr-1", lifecycle.TransitionID)
+	assert.Equal(t, types.TransitionStateCompleted, lifecycle.Snapshot.State)
+	assert.Equal(t, "node-1", task.NodeID)
+	assert.Equal(t, "On", task.Snapshot.FinalPowerState)
+	assert.Equal(t, 1, task.SchemaVersion, "unversioned payloads decode as version 1")
+	assert.Equal(t, "bob", approval.Principal)
+}
+
+func TestConsumer_DedupesByEventID(t *testing.T) {
+	calls := 0
+	fail := true
+	consumer := NewConsumer(Handlers{
+		Lifecycle: func(ctx context.Context, evt libevents.Event, data types.TransitionLifecycleEvent) error {
+			calls++
+			if fail {
+				return errors.New("downstream unavailable")
+			}
+			return nil
+		},
+	}, WithDedupeSize(2))
+
+	evt := newTestEvent(t, "evt-1", types.EventTypeTransitionLifecycle, types.TransitionLifecycleEvent{TransitionID: "tr-1"})
+	require.Error(t, consumer.Handle(context.Background(), evt))
+
+	// A failed event is retried on redelivery, a handled one is not.
+	fail = false
+	require.NoError(t, consumer.Handle(context.Background(), evt))
+	require.NoError(t, consumer.Handle(context.Background(), evt))
+	assert.Equal(t, 2, calls)
+
+	// The window forgets the oldest IDs once full.
+	for _, id := range []string{"evt-2", "evt-3"} {
+		require.NoError(t, consumer.Handle(context.Background(), newTestEvent(t, id, types.EventTypeTransitionLifecycle, types.TransitionLifecycleEvent{})))
+	}
+	require.NoError(t, consumer.Handle(context.Background(), evt))
+	assert.Equal(t, 5, calls)
+}
+
+func TestConsumer_DropsInvalidEvents(t *testing.T) {
+	var invalid []error
+	calls := 0
+	consumer := NewConsumer(Handlers{
+		TaskResult: func(ctx context.Context, evt libevents.Event, data types.TransitionTaskResultEvent) error {
+			calls++
+			return nil
+		},
+	}, WithInvalidEventHandler(func(evt libevents.Event, err error) {
+		invalid = append(invalid, err)
+	}))
+
+	future := newTestEvent(t, "evt-1", types.EventTypeTransitionTaskResult, types.TransitionTaskResultEvent{
+		SchemaVersion: types.TransitionEventSchemaVersion + 1,
+	})
+	require.NoError(t, consumer.Handle(context.Background(), future))
+
+	malformed := libevents.Event{ID: "evt-2", Type: types.EventTypeTransitionTaskResult, Data: json.RawMessage(`{"nodeId":`)}
+	require.NoError(t, consumer.Handle(context.Background(), malformed))
+
+	unknown := newTestEvent(t, "evt-3", "chamicore.power.transitions.unknown", map[string]string{})
+	require.NoError(t, consumer.Handle(context.Background(), unknown))
+
+	assert.Zero(t, calls)
+	require.Len(t, invalid, 2)
+	assert.ErrorIs(t, invalid[0], ErrUnsupportedSchemaVersion)
+	assert.ErrorIs(t, invalid[1], ErrMalformedEvent)
+}
+
+func TestConsumer_SubscribeErrors(t *testing.T) {
+	consumer := NewConsumer(Handlers{})
+	require.Error(t, consumer.Subscribe(context.Background(), nil))
+	require.Error(t, consumer.Subscribe(context.Background(), &fakeSubscriber{err: errors.New("boom")}))
+}
+
+func TestNewNATSSubscriber_RequiresURL(t *testing.T) {
+	_, err := NewNATSSubscriber(NATSConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,175 @@
+// Package hostlist expands Slurm hostlist expressions such as
+// "nid[001-004,010],login01" into individual host names.
+package hostlist
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxHosts caps how many names a single expression may expand to.
+const MaxHosts = 65536
+
+// ErrTooManyHosts is returned when an expression expands to more names than
+// allowed.
+var ErrTooManyHosts = errors.New("hostlist expands to too many hosts")
+
+// Expand returns the host names described by expr in order, dropping
+// duplicates. Bracketed ranges keep the zero padding of their lower bound, and
+// several bracket groups in one name expand to their cartesian product.
+func Expand(expr string) ([]string, error) {
+	return ExpandLimit(expr, MaxHosts)
+}
+
+// ExpandLimit is Expand with a caller-chosen cap on the number of names. It
+// fails with ErrTooManyHosts as soon as the cap is exceeded, before building
+// the remaining names.
+func ExpandLimit(expr string, limit int) ([]string, error) {
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(terms))
+	seen := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		names, err := expandTerm(term, limit-len(hosts))
+		if errors.Is(err, ErrTooManyHosts) {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyHosts, limit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			hosts = append(hosts, name)
+		}
+	}
+	return hosts, nil
+}
+
+// splitTerms splits expr on commas and whitespace outside of brackets.
+func splitTerms(expr string) ([]string, error) {
+	terms := make([]string, 0, 4)
+	depth := 0
+	start := 0
+	flush := func(end int) {
+		if term := strings.TrimSpace(expr[start:end]); term != "" {
+			terms = append(terms, term)
+		}
+		start = end + 1
+	}
+
+	for i, r := range expr {
+		switch r {
+		case '[':
+			if depth > 0 {
+				return nil, fmt.Errorf("hostlist %q: nested brackets", expr)
+			}
+			depth++
+		case ']':
+			if depth == 0 {
+				return nil, fmt.Errorf("hostlist %q: unbalanced ']'", expr)
+			}
+			depth--
+		case ',', ' ', '\t', '\n':
+			if depth == 0 {
+				flush(i)
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("hostlist %q: unbalanced '['", expr)
+	}
+	flush(len(expr))
+	return terms, nil
+}
+
+// expandTerm expands a single comma-free term such as "rack[1-2]-nid[01-04]".
+func expandTerm(term string, limit int) ([]string, error) {
+	open := strings.IndexByte(term, '[')
+	if open < 0 {
+		if limit < 1 {
+			return nil, ErrTooManyHosts
+		}
+		return []string{term}, nil
+	}
+	closing := strings.IndexByte(term[open:], ']') + open
+
+	suffixes, err := expandTerm(term[closing+1:], limit)
+	if err != nil {
+		return nil, err
+	}
+	values, err := expandRanges(term[open+1:closing], limit/len(suffixes))
+	if errors.Is(err, ErrTooManyHosts) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hostlist term %q: %w", term, err)
+	}
+
+	prefix := term[:open]
+	names := make([]string, 0, len(values)*len(suffixes))
+	for _, value := range values {
+		for _, suffix := range suffixes {
+			names = append(names, prefix+value+suffix)
+		}
+	}
+	return names, nil
+}
+
+// expandRanges expands the inside of a bracket, e.g. "001-004,010", into at
+// most limit values.
+func expandRanges(spec string, limit int) ([]string, error) {
+	values := make([]string, 0, 8)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty range")
+		}
+
+		low, high, isRange := strings.Cut(part, "-")
+		if !isRange {
+			high = low
+		}
+		first, err := parseBound(low)
+		if err != nil {
+			return nil, err
+		}
+		last, err := parseBound(high)
+		if err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, fmt.Errorf("range %q is descending", part)
+		}
+		if last-first >= MaxHosts {
+			return nil, fmt.Errorf("range %q expands to more than %d hosts", part, MaxHosts)
+		}
+		if len(values)+last-first+1 > limit {
+			return nil, ErrTooManyHosts
+		}
+
+		width := len(low)
+		for n := first; n <= last; n++ {
+			values = append(values, fmt.Sprintf("%0*d", width, n))
+		}
+	}
+	return values, nil
+}
+
+func parseBound(raw string) (int, error) {
+	if raw == "" || strings.TrimLeft(raw, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid range bound %q", raw)
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid range bound %q", raw)
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package hostlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "single host", expr: "login01", want: []string{"login01"}},
+		{name: "padded range", expr: "nid[008-011]", want: []string{"nid008", "nid009", "nid010", "nid011"}},
+		{name: "unpadded range", expr: "n[9-10]", want: []string{"n9", "n10"}},
+		{name: "range list", expr: "nid[001-002,010]", want: []string{"nid001", "nid002", "nid010"}},
+		{name: "mixed terms", expr: "nid[1-2],login01 gpu3", want: []string{"nid1", "nid2", "login01", "gpu3"}},
+		{name: "suffix", expr: "x1000c0s[0-1]b0n0", want: []string{"x1000c0s0b0n0", "x1000c0s1b0n0"}},
+		{name: "cartesian", expr: "r[1-2]n[1-2]", want: []string{"r1n1", "r1n2", "r2n1", "r2n2"}},
+		{name: "duplicates", expr: "nid[1-2],nid2", want: []string{"nid1", "nid2"}},
+		{name: "empty", expr: " ", want: []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Expand(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExpand_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"nid[1-2",
+		"nid1-2]",
+		"nid[[1-2]]",
+		"nid[]",
+		"nid[a-b]",
+		"nid[5-1]",
+		"nid[1-,3]",
+		"nid[0-99999]",
+		"r[0-999]n[0-999]",
+		"n[0-65534,0-65534]",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Expand(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExpandLimit(t *testing.T) {
+	hosts, err := ExpandLimit("nid[1-2],nid[2-3]", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nid1", "nid2", "nid3"}, hosts)
+
+	for _, expr := range []string{
+		"nid[1-5]",
+		"nid[1-3,4-5]",
+		"r[1-2]n[1-3]",
+		"nid[1-4],login1",
+		"n[" + strings.Repeat("0-65534,", 1000) + "0]",
+	} {
+		t.Run(expr[:min(len(expr), 20)], func(t *testing.T) {
+			_, err := ExpandLimit(expr, 4)
+			require.ErrorIs(t, err, ErrTooManyHosts)
+			assert.Contains(t, err.Error(), "more than 4")
+		})
+	}
+}
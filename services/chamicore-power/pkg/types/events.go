@@ -0,0 +1,104 @@
+package types
+
+import "time"
+
+const (
+	// EventSource is the CloudEvents source of every power event.
+	EventSource = "chamicore-power"
+	// EventTypeTransitionLifecycle is emitted whenever a transition changes state.
+	EventTypeTransitionLifecycle = "chamicore.power.transitions.lifecycle"
+	// EventTypeTransitionTaskResult is emitted whenever a node task changes state.
+	EventTypeTransitionTaskResult = "chamicore.power.transitions.task-result"
+	// EventTypeTransitionApproval is emitted for each approval decision.
+	EventTypeTransitionApproval = "chamicore.power.transitions.approval"
+
+	// TransitionEventSchemaVersion is the version of the transition event
+	// payloads below. Fields are only ever added within a version; payloads
+	// published before versioning carry no version and match version 1.
+	TransitionEventSchemaVersion = 1
+)
+
+// TransitionLifecycleEvent is the data of a transition lifecycle event.
+type TransitionLifecycleEvent struct {
+	SchemaVersion int                     `json:"schemaVersion,omitempty"`
+	TransitionID  string                  `json:"transitionId"`
+	Snapshot      TransitionEventSnapshot `json:"snapshot"`
+}
+
+// TransitionTaskResultEvent is the data of a transition task-result event.
+type TransitionTaskResultEvent struct {
+	SchemaVersion int                         `json:"schemaVersion,omitempty"`
+	TransitionID  string                      `json:"transitionId"`
+	NodeID        string                      `json:"nodeId"`
+	TaskID        string                      `json:"taskId"`
+	Snapshot      TransitionTaskEventSnapshot `json:"snapshot"`
+}
+
+// TransitionApprovalEvent is the data of a transition approval event.
+type TransitionApprovalEvent struct {
+	SchemaVersion int                     `json:"schemaVersion,omitempty"`
+	TransitionID  string                  `json:"transitionId"`
+	Action        string                  `json:"action"`
+	Principal     string                  `json:"principal,omitempty"`
+	Reason        string                  `json:"reason,omitempty"`
+	DecidedAt     time.Time               `json:"decidedAt"`
+	Snapshot      TransitionEventSnapshot `json:"snapshot"`
+}
+
+// TransitionEventSnapshot is the state of a transition when an event was
+// emitted.
+type TransitionEventSnapshot struct {
+	ID              string `json:"id"`
+	RequestID       string `json:"requestId,omitempty"`
+	Operation       string `json:"operation"`
+	State           string `json:"state"`
+	RequestedBy     string `json:"requestedBy,omitempty"`
+	DryRun          bool   `json:"dryRun"`
+	TargetCount     int    `json:"targetCount"`
+	SuccessCount    int    `json:"successCount"`
+	FailureCount    int    `json:"failureCount"`
+	TemplateName    string `json:"templateName,omitempty"`
+	TemplateVersion int    `json:"templateVersion,omitempty"`
+	// ApprovalReason and ApprovalExpiresAt are set on transitions held for approval.
+	ApprovalReason    string     `json:"approvalReason,omitempty"`
+	ApprovalExpiresAt *time.Time `json:"approvalExpiresAt,omitempty"`
+	ParentID          string     `json:"parentId,omitempty"`
+	QueuedAt          time.Time  `json:"queuedAt"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// TransitionTaskEventSnapshot is the state of a node task when an event was
+// emitted.
+type TransitionTaskEventSnapshot struct {
+	ID                 string      `json:"id"`
+	TransitionID       string      `json:"transitionId"`
+	NodeID             string      `json:"nodeId"`
+	BMCID              string      `json:"bmcId,omitempty"`
+	BMCEndpoint        string      `json:"bmcEndpoint,omitempty"`
+	CredentialID       string      `json:"credentialId,omitempty"`
+	Operation          string      `json:"operation"`
+	State              string      `json:"state"`
+	DryRun             bool        `json:"dryRun"`
+	AttemptCount       int         `json:"attemptCount"`
+	FinalPowerState    string      `json:"finalPowerState,omitempty"`
+	ErrorDetail        string      `json:"errorDetail,omitempty"`
+	QueuedAt           time.Time   `json:"queuedAt"`
+	StartedAt          *time.Time  `json:"startedAt,omitempty"`
+	CompletedAt        *time.Time  `json:"completedAt,omitempty"`
+	CreatedAt          time.Time   `json:"createdAt"`
+	UpdatedAt          time.Time   `json:"updatedAt"`
+	InsecureSkipVerify bool        `json:"insecureSkipVerify,omitempty"`
+	BootTarget         string      `json:"bootTarget,omitempty"`
+	BootWaitReady      bool        `json:"bootWaitReady,omitempty"`
+	BootStage          string      `json:"bootStage,omitempty"`
+	BootStages         []BootStage `json:"bootStages,omitempty"`
+	PowerCapWatts      *int        `json:"powerCapWatts,omitempty"`
+	BootOverrideTarget string      `json:"bootOverrideTarget,omitempty"`
+	BootOverrideMode   string      `json:"bootOverrideMode,omitempty"`
+	MediaImage         string      `json:"mediaImage,omitempty"`
+	ResetOperation     string      `json:"resetOperation,omitempty"`
+	Escalated          bool        `json:"escalated,omitempty"`
+}
@@ -18,6 +18,12 @@ const (
 	TransitionStateCanceled = "canceled"
 	// TransitionStatePlanned indicates a dry-run transition plan.
 	TransitionStatePlanned = "planned"
+	// TransitionStatePendingApproval indicates a transition awaiting a second principal.
+	TransitionStatePendingApproval = "pending-approval"
+	// TransitionStateRejected indicates an approver rejected the transition.
+	TransitionStateRejected = "rejected"
+	// TransitionStateExpired indicates nobody approved the transition in time.
+	TransitionStateExpired = "expired"
 )
 
 const (
@@ -35,30 +41,77 @@ const (
 	TaskStatePlanned = "planned"
 )
 
+// BootWait requests boot tracking after a successful On or restart.
+type BootWait struct {
+	// Target is the Redfish BootProgress stage to wait for.
+	Target string `json:"target,omitempty"`
+	// WaitForReady also waits for the node's SMD state to become Ready.
+	WaitForReady bool `json:"waitForReady,omitempty"`
+}
+
 // CreateTransitionRequest is the body for POST /power/v1/transitions.
+// Nodes may use hostlist ranges such as x1000c[0-3]s0b0n[0-1].
 type CreateTransitionRequest struct {
-	Operation string   `json:"operation"`
-	RequestID string   `json:"requestID,omitempty"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	Operation string        `json:"operation"`
+	RequestID string        `json:"requestID,omitempty"`
+	Nodes     []string      `json:"nodes,omitempty"`
+	Groups    []string      `json:"groups,omitempty"`
+	Selector  *NodeSelector `json:"selector,omitempty"`
+	// GroupOperation combines Groups as a "union" (default) or an
+	// "intersection" of their members.
+	GroupOperation string `json:"groupOperation,omitempty"`
+	// ExcludeNodes and ExcludeGroups are removed from the resolved targets.
+	ExcludeNodes  []string  `json:"excludeNodes,omitempty"`
+	ExcludeGroups []string  `json:"excludeGroups,omitempty"`
+	DryRun        bool      `json:"dryRun,omitempty"`
+	BootWait      *BootWait `json:"bootWait,omitempty"`
+	// BatchSize caps how many nodes of the transition are worked on at once.
+	BatchSize  int         `json:"batchSize,omitempty"`
+	Escalation *Escalation `json:"escalation,omitempty"`
+}
+
+// NodeSelector adds the SMD nodes matching every set attribute to the
+// targets of a request.
+type NodeSelector struct {
+	Role    string `json:"role,omitempty"`
+	SubRole string `json:"subRole,omitempty"`
+	Arch    string `json:"arch,omitempty"`
+	State   string `json:"state,omitempty"`
+	// Cabinet is a cabinet xname such as x1000.
+	Cabinet string `json:"cabinet,omitempty"`
+}
+
+// TransitionExclusions records the nodes and groups excluded from a
+// transition and the resolved targets they removed.
+type TransitionExclusions struct {
+	Nodes    []string `json:"nodes,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Excluded []string `json:"excluded,omitempty"`
+}
+
+// Escalation forces a GracefulShutdown or GracefulRestart (ForceOff or
+// ForceRestart) on nodes that have not complied after AfterSeconds.
+type Escalation struct {
+	AfterSeconds int `json:"afterSeconds"`
 }
 
 // ActionRequest is the body for POST action convenience endpoints.
 type ActionRequest struct {
-	RequestID string   `json:"requestID,omitempty"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	RequestID string    `json:"requestID,omitempty"`
+	Nodes     []string  `json:"nodes,omitempty"`
+	Groups    []string  `json:"groups,omitempty"`
+	DryRun    bool      `json:"dryRun,omitempty"`
+	BootWait  *BootWait `json:"bootWait,omitempty"`
 }
 
 // ResetActionRequest is the body for POST /power/v1/actions/reset.
 type ResetActionRequest struct {
-	Operation string   `json:"operation"`
-	RequestID string   `json:"requestID,omitempty"`
-	Nodes     []string `json:"nodes,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	DryRun    bool     `json:"dryRun,omitempty"`
+	Operation string    `json:"operation"`
+	RequestID string    `json:"requestID,omitempty"`
+	Nodes     []string  `json:"nodes,omitempty"`
+	Groups    []string  `json:"groups,omitempty"`
+	DryRun    bool      `json:"dryRun,omitempty"`
+	BootWait  *BootWait `json:"bootWait,omitempty"`
 }
 
 // Transition is the public transition resource payload.
@@ -75,22 +128,153 @@ type Transition struct {
 	SuccessCount int              `json:"successCount"`
 	FailureCount int              `json:"failureCount"`
 	DryRun       bool             `json:"dryRun"`
+	BatchSize    int              `json:"batchSize,omitempty"`
+	// ChunkSize is set on transitions larger than the server bulk limit,
+	// which run in chunks of that many tasks.
+	ChunkSize int `json:"chunkSize,omitempty"`
+	// ResolvedNodes lists every target node of a dry run.
+	ResolvedNodes []string `json:"resolvedNodes,omitempty"`
+	// Exclusions records the exclusions requested at creation.
+	Exclusions *TransitionExclusions `json:"exclusions,omitempty"`
+	// TaskCounts aggregates all tasks by state; Tasks holds only the page
+	// described by TaskPage.
+	TaskCounts map[string]int `json:"taskCounts,omitempty"`
+	TaskPage   *TaskPage      `json:"taskPage,omitempty"`
+	// EscalateAfterSeconds is set when graceful resets escalate to forced ones.
+	EscalateAfterSeconds int `json:"escalateAfterSeconds,omitempty"`
+	// TemplateName and TemplateVersion identify the template the transition
+	// was started from.
+	TemplateName    string `json:"templateName,omitempty"`
+	TemplateVersion int    `json:"templateVersion,omitempty"`
+	// ApprovalReason and ApprovalExpiresAt are set when the approval policy
+	// held the transition; Approvals is its decision trail.
+	ApprovalReason    string     `json:"approvalReason,omitempty"`
+	ApprovalExpiresAt *time.Time `json:"approvalExpiresAt,omitempty"`
+	Approvals         []Approval `json:"approvals,omitempty"`
+	// ParentID is set on retries and names the transition they retry.
+	ParentID string `json:"parentID,omitempty"`
+}
+
+// TaskPage describes the page of tasks returned with a transition.
+type TaskPage struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Approval is one entry of a transition approval trail.
+type Approval struct {
+	// Action is requested, approved, rejected, expired or canceled.
+	Action    string    `json:"action"`
+	Principal string    `json:"principal,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	DecidedAt time.Time `json:"decidedAt"`
+}
+
+// ApprovalDecisionRequest is the optional body of approve and reject calls.
+type ApprovalDecisionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// TransitionRetryRequest is the optional body of a transition retry. The
+// retry re-runs the failed tasks of the transition, and canceled ones with
+// IncludeCanceled, as a new child transition.
+type TransitionRetryRequest struct {
+	RequestID       string `json:"requestID,omitempty"`
+	IncludeCanceled bool   `json:"includeCanceled,omitempty"`
+	DryRun          bool   `json:"dryRun,omitempty"`
 }
 
 // TransitionTask is the public per-node task payload.
 type TransitionTask struct {
-	NodeID          string     `json:"nodeID"`
-	BMCID           string     `json:"bmcID,omitempty"`
-	Endpoint        string     `json:"endpoint,omitempty"`
-	Operation       string     `json:"operation"`
-	State           string     `json:"state"`
-	FinalPowerState string     `json:"finalPowerState,omitempty"`
-	ErrorDetail     string     `json:"errorDetail,omitempty"`
-	QueuedAt        time.Time  `json:"queuedAt"`
-	StartedAt       *time.Time `json:"startedAt,omitempty"`
-	CompletedAt     *time.Time `json:"completedAt,omitempty"`
-	AttemptCount    int        `json:"attemptCount"`
-	DryRun          bool       `json:"dryRun"`
+	NodeID          string      `json:"nodeID"`
+	BMCID           string      `json:"bmcID,omitempty"`
+	Endpoint        string      `json:"endpoint,omitempty"`
+	Operation       string      `json:"operation"`
+	State           string      `json:"state"`
+	FinalPowerState string      `json:"finalPowerState,omitempty"`
+	ErrorDetail     string      `json:"errorDetail,omitempty"`
+	QueuedAt        time.Time   `json:"queuedAt"`
+	StartedAt       *time.Time  `json:"startedAt,omitempty"`
+	CompletedAt     *time.Time  `json:"completedAt,omitempty"`
+	AttemptCount    int         `json:"attemptCount"`
+	DryRun          bool        `json:"dryRun"`
+	BootTarget      string      `json:"bootTarget,omitempty"`
+	BootWaitReady   bool        `json:"bootWaitReady,omitempty"`
+	BootStage       string      `json:"bootStage,omitempty"`
+	BootStages      []BootStage `json:"bootStages,omitempty"`
+	PowerCapWatts   *int        `json:"powerCapWatts,omitempty"`
+	// BootOverrideTarget and BootOverrideMode record a requested boot override.
+	BootOverrideTarget string `json:"bootOverrideTarget,omitempty"`
+	BootOverrideMode   string `json:"bootOverrideMode,omitempty"`
+	// MediaImage records the virtual-media image inserted by the task.
+	MediaImage string `json:"mediaImage,omitempty"`
+	// ResetOperation records the reset issued by a BootOverrideReset task.
+	ResetOperation string `json:"resetOperation,omitempty"`
+	// Escalated reports that a graceful reset timed out and was forced.
+	Escalated bool `json:"escalated,omitempty"`
+}
+
+// TransitionReport is the payload returned by
+// GET /power/v1/transitions/{id}/report.
+type TransitionReport struct {
+	TransitionID string `json:"transitionID"`
+	Operation    string `json:"operation"`
+	State        string `json:"state"`
+	DryRun       bool   `json:"dryRun"`
+	TotalTasks   int    `json:"totalTasks"`
+	// SuccessRate is succeeded tasks over finished (succeeded, failed or
+	// canceled) tasks, between 0 and 1.
+	SuccessRate float64        `json:"successRate"`
+	TaskCounts  map[string]int `json:"taskCounts"`
+	// Latency covers tasks that have both started and completed.
+	Latency         ReportLatency      `json:"latency"`
+	BMCFailures     []ReportBMCFailure `json:"bmcFailures"`
+	FailuresByError []ReportErrorGroup `json:"failuresByError"`
+	FailedNodes     []ReportFailedNode `json:"failedNodes"`
+	QueuedAt        time.Time          `json:"queuedAt"`
+	StartedAt       *time.Time         `json:"startedAt,omitempty"`
+	CompletedAt     *time.Time         `json:"completedAt,omitempty"`
+}
+
+// ReportLatency summarizes task durations with nearest-rank percentiles.
+type ReportLatency struct {
+	Count      int     `json:"count"`
+	MinSeconds float64 `json:"minSeconds"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+// ReportBMCFailure counts the failed tasks behind one BMC, most failures
+// first.
+type ReportBMCFailure struct {
+	BMCID  string   `json:"bmcID"`
+	Failed int      `json:"failed"`
+	Total  int      `json:"total"`
+	Nodes  []string `json:"nodes"`
+}
+
+// ReportErrorGroup lists the nodes that failed with the same error.
+type ReportErrorGroup struct {
+	Error string   `json:"error"`
+	Count int      `json:"count"`
+	Nodes []string `json:"nodes"`
+}
+
+// ReportFailedNode is one failed or canceled task.
+type ReportFailedNode struct {
+	NodeID string `json:"nodeID"`
+	BMCID  string `json:"bmcID,omitempty"`
+	State  string `json:"state"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BootStage records when a task first observed one boot stage.
+type BootStage struct {
+	Stage      string    `json:"stage"`
+	ObservedAt time.Time `json:"observedAt"`
 }
 
 // PowerStatus is the payload returned by GET /power/v1/power-status.
@@ -112,7 +296,181 @@ type PowerNodeStatus struct {
 	LastCompletedAt *time.Time `json:"lastCompletedAt,omitempty"`
 }
 
+// PowerCapRequest is the body for POST /power/v1/powercap.
+type PowerCapRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	// LimitWatts is the node power limit to apply; zero removes the limit.
+	LimitWatts *int `json:"limitWatts"`
+	DryRun     bool `json:"dryRun,omitempty"`
+}
+
+// PowerCap is the payload returned by GET /power/v1/powercap.
+type PowerCap struct {
+	Nodes []PowerCapNode `json:"nodes"`
+	Total int            `json:"total"`
+}
+
+// PowerCapNode contains the power limit and draw of one node.
+type PowerCapNode struct {
+	NodeID        string   `json:"nodeID"`
+	BMCID         string   `json:"bmcID,omitempty"`
+	LimitWatts    *int     `json:"limitWatts,omitempty"`
+	ConsumedWatts *float64 `json:"consumedWatts,omitempty"`
+	MinLimitWatts *int     `json:"minLimitWatts,omitempty"`
+	MaxLimitWatts *int     `json:"maxLimitWatts,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	ErrorDetail   string   `json:"errorDetail,omitempty"`
+}
+
+// Telemetry is the payload returned by GET /power/v1/telemetry.
+type Telemetry struct {
+	Since       time.Time             `json:"since"`
+	Until       time.Time             `json:"until"`
+	StepSeconds int64                 `json:"stepSeconds"`
+	Nodes       []TelemetryNodeSeries `json:"nodes"`
+	Groups      []TelemetryGroup      `json:"groups,omitempty"`
+}
+
+// TelemetryNodeSeries contains the telemetry points of one node.
+type TelemetryNodeSeries struct {
+	NodeID string               `json:"nodeID"`
+	Points []TelemetryNodePoint `json:"points"`
+}
+
+// TelemetryNodePoint aggregates one node's samples over one step.
+type TelemetryNodePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Samples is the number of power readings in the step.
+	Samples             int      `json:"samples"`
+	PowerWatts          *float64 `json:"powerWatts,omitempty"`
+	MinPowerWatts       *float64 `json:"minPowerWatts,omitempty"`
+	MaxPowerWatts       *float64 `json:"maxPowerWatts,omitempty"`
+	InletTempCelsius    *float64 `json:"inletTempCelsius,omitempty"`
+	MaxInletTempCelsius *float64 `json:"maxInletTempCelsius,omitempty"`
+}
+
+// TelemetryGroup contains the aggregated telemetry of one requested group.
+type TelemetryGroup struct {
+	Group     string                `json:"group"`
+	NodeCount int                   `json:"nodeCount"`
+	Points    []TelemetryGroupPoint `json:"points"`
+}
+
+// TelemetryGroupPoint aggregates a group's reporting members over one step.
+type TelemetryGroupPoint struct {
+	Timestamp      time.Time `json:"timestamp"`
+	ReportingNodes int       `json:"reportingNodes"`
+	// PowerWatts is the sum of the reporting members' average draw.
+	PowerWatts *float64 `json:"powerWatts,omitempty"`
+	// InletTempCelsius is the mean of the reporting members' average inlet temperature.
+	InletTempCelsius    *float64 `json:"inletTempCelsius,omitempty"`
+	MaxInletTempCelsius *float64 `json:"maxInletTempCelsius,omitempty"`
+}
+
+// BootOverrideRequest is the body for POST /power/v1/boot-override.
+type BootOverrideRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	// Target is one of Pxe, Hdd, Cd or BiosSetup.
+	Target string `json:"target"`
+	// Mode is Once (the default) or Continuous.
+	Mode   string `json:"mode,omitempty"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// InsertMediaRequest is the body for POST /power/v1/virtual-media/insert.
+type InsertMediaRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	// Image is the URL the BMC fetches the image from.
+	Image  string `json:"image"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// EjectMediaRequest is the body for POST /power/v1/virtual-media/eject.
+type EjectMediaRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	DryRun    bool     `json:"dryRun,omitempty"`
+}
+
+// BootOverrideActionRequest is the body for POST /power/v1/actions/boot-override.
+type BootOverrideActionRequest struct {
+	RequestID string   `json:"requestID,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	Target    string   `json:"target"`
+	Mode      string   `json:"mode,omitempty"`
+	// Image optionally inserts virtual media before the override is set.
+	Image string `json:"image,omitempty"`
+	// ResetOperation is On, GracefulRestart or ForceRestart (the default).
+	ResetOperation string    `json:"resetOperation,omitempty"`
+	DryRun         bool      `json:"dryRun,omitempty"`
+	BootWait       *BootWait `json:"bootWait,omitempty"`
+}
+
 // MappingSyncTrigger is the payload for POST /power/v1/admin/mappings/sync.
 type MappingSyncTrigger struct {
 	Status string `json:"status"`
 }
+
+// TemplateRequest is the body for POST /power/v1/templates and
+// PUT /power/v1/templates/{name}.
+type TemplateRequest struct {
+	// Name is required on create and must match the path on update.
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Operation   string         `json:"operation"`
+	Nodes       []string       `json:"nodes,omitempty"`
+	Groups      []string       `json:"groups,omitempty"`
+	DryRun      bool           `json:"dryRun,omitempty"`
+	BootWait    *BootWait      `json:"bootWait,omitempty"`
+	BatchSize   int            `json:"batchSize,omitempty"`
+	Escalation  *Escalation    `json:"escalation,omitempty"`
+	Guard       *TemplateGuard `json:"guard,omitempty"`
+}
+
+// TemplateGuard restricts how a template may be run.
+type TemplateGuard struct {
+	// MaxNodes rejects runs that resolve to more nodes; zero means no limit.
+	MaxNodes int `json:"maxNodes,omitempty"`
+	// RequireConfirmation rejects runs that do not set Confirm.
+	RequireConfirmation bool `json:"requireConfirmation,omitempty"`
+}
+
+// Template is one stored version of a transition template.
+type Template struct {
+	Name        string         `json:"name"`
+	Version     int            `json:"version"`
+	Description string         `json:"description,omitempty"`
+	Operation   string         `json:"operation"`
+	Nodes       []string       `json:"nodes,omitempty"`
+	Groups      []string       `json:"groups,omitempty"`
+	DryRun      bool           `json:"dryRun,omitempty"`
+	BootWait    *BootWait      `json:"bootWait,omitempty"`
+	BatchSize   int            `json:"batchSize,omitempty"`
+	Escalation  *Escalation    `json:"escalation,omitempty"`
+	Guard       *TemplateGuard `json:"guard,omitempty"`
+	CreatedBy   string         `json:"createdBy,omitempty"`
+}
+
+// RunTemplateRequest is the body for POST /power/v1/templates/{name}/run.
+// Set fields override the template; Nodes or Groups replace its targets.
+type RunTemplateRequest struct {
+	RequestID string `json:"requestID,omitempty"`
+	// Version selects a template version; zero runs the latest.
+	Version    int         `json:"version,omitempty"`
+	Nodes      []string    `json:"nodes,omitempty"`
+	Groups     []string    `json:"groups,omitempty"`
+	DryRun     *bool       `json:"dryRun,omitempty"`
+	BootWait   *BootWait   `json:"bootWait,omitempty"`
+	BatchSize  *int        `json:"batchSize,omitempty"`
+	Escalation *Escalation `json:"escalation,omitempty"`
+	// Confirm acknowledges templates whose guard requires confirmation.
+	Confirm bool `json:"confirm,omitempty"`
+}